@@ -29,7 +29,7 @@ type Product struct {
 	Price       float64   `json:"price"`
 	Category    string    `json:"category"`
 	Description string    `json:"description"`
-	CreatedBy   int64     `json:"created_by"`
+	CreatedBy   string    `json:"created_by"`
 	Created     time.Time `json:"created"`
 	Updated     time.Time `json:"updated"`
 }
@@ -38,11 +38,19 @@ func main() {
 	// Cria o servidor (carrega automaticamente configurações do .env se disponível)
 	server := odata.NewServer()
 
-	// Registra as entidades
-	if err := server.RegisterEntity("Users", User{}); err != nil {
+	// Registra as entidades. Timestamps e criador são preenchidos pelo servidor a partir do
+	// contexto da requisição, sobrescrevendo os valores enviados pelo cliente
+	if err := server.RegisterEntity("Users", User{},
+		odata.WithAutoValue("created", odata.FromNow(), odata.AutoValueOnInsert()),
+		odata.WithAutoValue("updated", odata.FromNow()),
+	); err != nil {
 		log.Fatal(err)
 	}
-	if err := server.RegisterEntity("Products", Product{}); err != nil {
+	if err := server.RegisterEntity("Products", Product{},
+		odata.WithAutoValue("created_by", odata.FromUser("username"), odata.AutoValueOnInsert()),
+		odata.WithAutoValue("created", odata.FromNow(), odata.AutoValueOnInsert()),
+		odata.WithAutoValue("updated", odata.FromNow()),
+	); err != nil {
 		log.Fatal(err)
 	}
 
@@ -79,9 +87,7 @@ func setupUserEvents(server *odata.Server) {
 			return nil
 		}
 
-		// Adiciona timestamp de criação
-		insertArgs.Data["created"] = time.Now()
-		insertArgs.Data["updated"] = time.Now()
+		// Usuários novos começam ativos (created/updated são valores automáticos)
 		insertArgs.Data["is_active"] = true
 
 		return nil
@@ -140,9 +146,6 @@ func setupUserEvents(server *odata.Server) {
 			}
 		}
 
-		// Atualiza timestamp
-		modifyArgs.Data["updated"] = time.Now()
-
 		return nil
 	})
 
@@ -161,20 +164,20 @@ func setupUserEvents(server *odata.Server) {
 		return nil
 	})
 
-	// Evento OnEntityGet - Filtro após recuperação
+	// Mascara o email nas respostas para quem não é admin (a***@example.com)
+	if err := server.SetMaskingPolicy("Users", "email", odata.MaskingPolicy{
+		Mask:          odata.MaskEmail,
+		UnmaskedRoles: []string{"admin"},
+	}); err != nil {
+		log.Fatal(err)
+	}
+
+	// Evento OnEntityGet - Log após recuperação
 	server.OnEntityGet("Users", func(args odata.EventArgs) error {
 		getArgs := args.(*odata.EntityGetArgs)
 
 		log.Printf("👀 [Users] Recuperando usuário: %+v", getArgs.Keys)
 
-		// Aqui você poderia filtrar dados sensíveis baseado nas permissões
-		if entity, ok := getArgs.GetEntity().(map[string]interface{}); ok {
-			// Remove dados sensíveis se não for admin
-			if !isCurrentUserAdmin(getArgs.GetContext()) {
-				delete(entity, "email")
-			}
-		}
-
 		return nil
 	})
 }
@@ -193,15 +196,6 @@ func setupProductEvents(server *odata.Server) {
 			return nil
 		}
 
-		// Adiciona usuário criador
-		if userID := getCurrentUserID(insertArgs.GetContext()); userID != "" {
-			insertArgs.Data["created_by"] = userID
-		}
-
-		// Adiciona timestamps
-		insertArgs.Data["created"] = time.Now()
-		insertArgs.Data["updated"] = time.Now()
-
 		return nil
 	})
 
@@ -308,11 +302,6 @@ func hasUserProducts(keys map[string]interface{}) bool {
 	return false
 }
 
-func getCurrentUserID(ctx *odata.EventContext) string {
-	// Retorna o ID do usuário atual
-	return ctx.UserID
-}
-
 // Exemplo de uso com Fiber customizado
 func createFiberApp() *fiber.App {
 	app := fiber.New()
@@ -0,0 +1,245 @@
+package odata
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// ANOTAÇÕES DE INSTÂNCIA CUSTOMIZADAS (@namespace.termo)
+// =======================================================================================
+
+// responseAnnotationsKey guarda nos Locals as anotações do envelope da resposta
+const responseAnnotationsKey = "odata_response_annotations"
+
+// PreferIncludeAnnotations é a preferência que filtra as anotações customizadas da resposta
+// (ex: Prefer: odata.include-annotations="myapp.*,-myapp.debug")
+const PreferIncludeAnnotations = "odata.include-annotations"
+
+// annotationNameRegex valida o nome de uma anotação de instância: @Namespace.Termo, com
+// qualificador opcional (@Namespace.Termo#Qualificador)
+var annotationNameRegex = regexp.MustCompile(`^@[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)+(#[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// validateAnnotationName verifica o formato do nome e reserva o namespace odata às anotações
+// de controle geradas pelo servidor
+func validateAnnotationName(name string) error {
+	if !annotationNameRegex.MatchString(name) {
+		return fmt.Errorf("nome de anotação inválido: '%s' (formato: @namespace.termo)", name)
+	}
+	if strings.HasPrefix(strings.ToLower(name), "@odata.") {
+		return fmt.Errorf("anotação '%s' usa o namespace reservado odata", name)
+	}
+	return nil
+}
+
+// AddResponseAnnotation adiciona uma anotação ao envelope da resposta (antes do "value" nas
+// coleções, junto das propriedades na entidade única). Pode ser chamada por handlers,
+// middlewares e eventos (EventContext.FiberContext) antes do envio da resposta
+func AddResponseAnnotation(c fiber.Ctx, name string, value interface{}) error {
+	if err := validateAnnotationName(name); err != nil {
+		return err
+	}
+	annotations, _ := c.Locals(responseAnnotationsKey).([]OrderedProperty)
+	c.Locals(responseAnnotationsKey, setOrderedProperty(annotations, name, value))
+	return nil
+}
+
+// SetEntityAnnotation adiciona uma anotação a uma entidade da resposta (OrderedEntity,
+// OrderedEntityResponse ou map), como nos resultados recebidos pelos eventos
+func SetEntityAnnotation(entity interface{}, name string, value interface{}) error {
+	if err := validateAnnotationName(name); err != nil {
+		return err
+	}
+	switch e := entity.(type) {
+	case *OrderedEntity:
+		e.SetAnnotation(name, value)
+	case *OrderedEntityResponse:
+		e.SetAnnotation(name, value)
+	case map[string]interface{}:
+		e[name] = value
+	default:
+		return fmt.Errorf("tipo de entidade não suportado para anotações: %T", entity)
+	}
+	return nil
+}
+
+// responseAnnotations retorna as anotações do envelope adicionadas na requisição
+func responseAnnotations(c fiber.Ctx) []OrderedProperty {
+	annotations, _ := c.Locals(responseAnnotationsKey).([]OrderedProperty)
+	return annotations
+}
+
+// isCustomAnnotation indica se a chave é uma anotação de instância fora do namespace odata
+func isCustomAnnotation(name string) bool {
+	return strings.HasPrefix(name, "@") && !strings.HasPrefix(name, "@odata.")
+}
+
+// annotationRule é um item do odata.include-annotations: termo exato, namespace.* ou *,
+// precedido de "-" para exclusão
+type annotationRule struct {
+	pattern string
+	exclude bool
+}
+
+// annotationFilter decide quais anotações customizadas são enviadas. Sem a preferência, todas
+// são enviadas, exceto com odata.metadata=none
+type annotationFilter struct {
+	rules      []annotationRule
+	preference bool
+	level      MetadataLevel
+}
+
+// newAnnotationFilter lê o odata.include-annotations do header Prefer
+func newAnnotationFilter(prefer string, level MetadataLevel) *annotationFilter {
+	filter := &annotationFilter{level: level}
+	value, ok := preferenceValue(prefer, PreferIncludeAnnotations)
+	if !ok {
+		return filter
+	}
+	filter.preference = true
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		rule := annotationRule{pattern: strings.TrimPrefix(pattern, "-"), exclude: strings.HasPrefix(pattern, "-")}
+		if rule.pattern != "" {
+			filter.rules = append(filter.rules, rule)
+		}
+	}
+	return filter
+}
+
+// includes verifica se a anotação (com "@" e qualificador opcional) deve ser enviada. Vale a
+// regra mais específica (termo > namespace.* mais longo > *); no empate, a exclusão
+func (f *annotationFilter) includes(name string) bool {
+	if name == PatchOperationsAnnotation {
+		// Relatório pedido explicitamente pelo Prefer godata.patch-report
+		return true
+	}
+	if !f.preference {
+		return f.level != MetadataNone
+	}
+
+	term, _, _ := strings.Cut(strings.TrimPrefix(name, "@"), "#")
+	included, best := false, -1
+	for _, rule := range f.rules {
+		specificity := -1
+		switch {
+		case rule.pattern == "*":
+			specificity = 0
+		case strings.HasSuffix(rule.pattern, ".*"):
+			if strings.HasPrefix(term, strings.TrimSuffix(rule.pattern, "*")) {
+				specificity = len(rule.pattern)
+			}
+		case rule.pattern == term:
+			specificity = len(term) + 1
+		}
+		if specificity < 0 {
+			continue
+		}
+		if specificity > best || specificity == best && rule.exclude {
+			included, best = !rule.exclude, specificity
+		}
+	}
+	return included
+}
+
+// filter remove da lista as anotações customizadas não incluídas
+func (f *annotationFilter) filter(annotations []OrderedProperty) []OrderedProperty {
+	filtered := annotations[:0]
+	for _, annotation := range annotations {
+		if !isCustomAnnotation(annotation.Name) || f.includes(annotation.Name) {
+			filtered = append(filtered, annotation)
+		}
+	}
+	return filtered
+}
+
+// preferenceValue retorna o valor de uma preferência do header Prefer (sem as aspas)
+func preferenceValue(prefer, name string) (string, bool) {
+	for _, preference := range splitPreferences(prefer) {
+		token, value, _ := strings.Cut(strings.TrimSpace(preference), "=")
+		if strings.EqualFold(strings.TrimSpace(token), name) {
+			return strings.Trim(strings.TrimSpace(value), `"`), true
+		}
+	}
+	return "", false
+}
+
+// splitPreferences separa as preferências do header Prefer, respeitando vírgulas dentro de
+// valores entre aspas (odata.include-annotations="a.*,b.*")
+func splitPreferences(prefer string) []string {
+	var preferences []string
+	quoted, start := false, 0
+	for i, r := range prefer {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			preferences = append(preferences, prefer[start:i])
+			start = i + 1
+		}
+	}
+	return append(preferences, prefer[start:])
+}
+
+// MarshalJSON serializa a resposta com as anotações do envelope entre o @odata.count e o
+// @odata.nextLink. Sem anotações, a saída é a dos tags da struct
+func (r *ODataResponse) MarshalJSON() ([]byte, error) {
+	type plainResponse ODataResponse
+	if len(r.Annotations) == 0 {
+		return json.Marshal((*plainResponse)(r))
+	}
+	var count interface{}
+	if r.Count != nil {
+		count = *r.Count
+	}
+	return marshalResponseEnvelope(r.Context, count, r.Annotations, r.NextLink, r.Value, r.Error)
+}
+
+// marshalResponseEnvelope serializa o envelope de uma coleção na ordem do formato JSON do
+// OData: contexto, count, anotações, nextLink e value
+func marshalResponseEnvelope(context string, count interface{}, annotations []OrderedProperty, nextLink string, value interface{}, odataErr *ODataError) ([]byte, error) {
+	var pairs []string
+	add := func(name string, value interface{}) error {
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, fmt.Sprintf(`%s:%s`, mustJSONString(name), string(valueJSON)))
+		return nil
+	}
+
+	if context != "" {
+		pairs = append(pairs, `"@odata.context":`+mustJSONString(context))
+	}
+	if count != nil {
+		if err := add("@odata.count", count); err != nil {
+			return nil, err
+		}
+	}
+	for _, annotation := range annotations {
+		if err := add(annotation.Name, annotation.Value); err != nil {
+			return nil, err
+		}
+	}
+	if nextLink != "" {
+		pairs = append(pairs, `"@odata.nextLink":`+mustJSONString(nextLink))
+	}
+	if err := add("value", value); err != nil {
+		return nil, err
+	}
+	if odataErr != nil {
+		if err := add("error", odataErr); err != nil {
+			return nil, err
+		}
+	}
+	return []byte("{" + strings.Join(pairs, ",") + "}"), nil
+}
+
+// mustJSONString serializa uma string como JSON
+func mustJSONString(value string) string {
+	return string(appendJSONString(nil, value))
+}
@@ -0,0 +1,130 @@
+package odata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAnnotationName(t *testing.T) {
+	assert.NoError(t, validateAnnotationName("@myapp.permissions"))
+	assert.NoError(t, validateAnnotationName("@Core.Description#Curta"))
+	assert.Error(t, validateAnnotationName("myapp.permissions"), "sem @")
+	assert.Error(t, validateAnnotationName("@permissions"), "sem namespace")
+	assert.Error(t, validateAnnotationName("@odata.etag"), "namespace reservado")
+	assert.Error(t, validateAnnotationName("@OData.Count"), "namespace reservado")
+}
+
+func TestAnnotationFilter_Includes(t *testing.T) {
+	filter := newAnnotationFilter("", MetadataMinimal)
+	assert.True(t, filter.includes("@myapp.permissions"), "sem preferência")
+	assert.False(t, newAnnotationFilter("", MetadataNone).includes("@myapp.permissions"))
+
+	filter = newAnnotationFilter(`return=representation, odata.include-annotations="myapp.*,-myapp.debug,core.Description"`, MetadataMinimal)
+	assert.True(t, filter.includes("@myapp.permissions"))
+	assert.True(t, filter.includes("@myapp.permissions#Admin"))
+	assert.False(t, filter.includes("@myapp.debug"), "termo exato vence o namespace")
+	assert.True(t, filter.includes("@core.Description"))
+	assert.False(t, filter.includes("@other.flag"))
+
+	filter = newAnnotationFilter(`odata.include-annotations="*,-myapp.*"`, MetadataNone)
+	assert.True(t, filter.includes("@other.flag"), "a preferência vale também com none")
+	assert.False(t, filter.includes("@myapp.permissions"))
+	assert.True(t, filter.includes(PatchOperationsAnnotation))
+
+	filter = newAnnotationFilter(`odata.include-annotations="myapp.*,-myapp.*"`, MetadataMinimal)
+	assert.False(t, filter.includes("@myapp.permissions"), "exclusão vence no empate")
+}
+
+func TestServer_CustomAnnotations(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.OnEntityList("Products", func(args EventArgs) error {
+		for _, result := range args.(*EntityListArgs).Results {
+			if err := SetEntityAnnotation(result, "@myapp.permissions", []string{"read"}); err != nil {
+				return err
+			}
+		}
+		return AddResponseAnnotation(args.GetContext().FiberContext, "@myapp.generatedBy", "catalogo")
+	})
+	server.router.Use(func(c fiber.Ctx) error {
+		if err := AddResponseAnnotation(c, "@myapp.debug", true); err != nil {
+			return err
+		}
+		return c.Next()
+	})
+	server.setupEntityRoutes("Products")
+
+	get := func(t *testing.T, path string, headers map[string]string) map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result
+	}
+	first := func(body map[string]interface{}) map[string]interface{} {
+		return body["value"].([]interface{})[0].(map[string]interface{})
+	}
+
+	t.Run("Anotações no envelope e nas entidades", func(t *testing.T) {
+		body := get(t, "/odata/Products?$top=1", nil)
+		assert.Equal(t, "catalogo", body["@myapp.generatedBy"])
+		assert.Equal(t, true, body["@myapp.debug"])
+		assert.Equal(t, []interface{}{"read"}, first(body)["@myapp.permissions"])
+	})
+
+	t.Run("Removidas com odata.metadata=none", func(t *testing.T) {
+		body := get(t, "/odata/Products?$top=1", map[string]string{"Accept": "application/json;odata.metadata=none"})
+		assert.NotContains(t, body, "@myapp.generatedBy")
+		assert.NotContains(t, first(body), "@myapp.permissions")
+	})
+
+	t.Run("Filtradas pelo odata.include-annotations", func(t *testing.T) {
+		body := get(t, "/odata/Products?$top=1", map[string]string{"Prefer": `odata.include-annotations="myapp.*,-myapp.debug"`})
+		assert.Equal(t, "catalogo", body["@myapp.generatedBy"])
+		assert.NotContains(t, body, "@myapp.debug")
+		assert.Contains(t, first(body), "@myapp.permissions")
+	})
+
+	t.Run("Serializador rápido", func(t *testing.T) {
+		server.config.FastJSONEncoding = true
+		defer func() { server.config.FastJSONEncoding = false }()
+
+		body := get(t, "/odata/Products?$top=1", nil)
+		assert.Equal(t, "catalogo", body["@myapp.generatedBy"])
+		assert.Equal(t, []interface{}{"read"}, first(body)["@myapp.permissions"])
+	})
+
+	t.Run("Anotação do envelope na entidade única", func(t *testing.T) {
+		body := get(t, "/odata/Products(2)", nil)
+		assert.Equal(t, true, body["@myapp.debug"])
+		assert.Equal(t, "Teclado", body["name"])
+	})
+}
+
+func TestODataResponse_MarshalJSONAnnotations(t *testing.T) {
+	count := int64(2)
+	response := &ODataResponse{Context: "$metadata#Products", Count: &count, NextLink: "next", Value: []interface{}{}}
+	response.Annotations = []OrderedProperty{{Name: "@myapp.total", Value: 10}}
+
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.Equal(t, `{"@odata.context":"$metadata#Products","@odata.count":2,"@myapp.total":10,"@odata.nextLink":"next","value":[]}`, string(data))
+
+	response.Annotations = nil
+	data, err = json.Marshal(response)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "@myapp")
+}
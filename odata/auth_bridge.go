@@ -1,6 +1,8 @@
 package odata
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v3"
 )
 
@@ -10,11 +12,44 @@ import (
 
 // EntityConfig configuração de uma entidade
 type EntityConfig struct {
-	Name        string
-	Entity      interface{}
-	Middlewares []fiber.Handler // Middlewares aplicados às rotas da entidade
-	ReadOnly    bool
-	Permissions []string // GET, POST, PUT, DELETE, PATCH - se vazio, permite todos
+	Name         string
+	Entity       interface{}
+	Middlewares  []fiber.Handler // Middlewares aplicados às rotas da entidade
+	ReadOnly     bool
+	Permissions  []string      // GET, POST, PUT, DELETE, PATCH - se vazio, permite todos
+	QueryTimeout time.Duration // Sobrescreve o QueryTimeout do servidor para a entidade
+	Search       *SearchConfig // Propriedades pesquisáveis quando a struct não usa a tag searchable
+
+	CaseInsensitive *bool        // Sobrescreve o CaseInsensitive do servidor para a entidade (nil = padrão)
+	NamingPolicy    NamingPolicy // Sobrescreve a NamingPolicy do servidor para a entidade
+
+	DefaultFilter string        // Filtro combinado com o $filter de todas as consultas da coleção
+	QueryPresets  []QueryPreset // Consultas nomeadas expostas como funções (/Entidade/Default.Nome())
+
+	RoutePrefix   string // Sobrescreve o RoutePrefix do servidor para a entidade (ex: /api/v2)
+	EntitySetName string // Nome do entity set na URL (padrão: nome de registro)
+
+	QueryInterceptors []QueryInterceptor // Interceptors de consulta da entidade (após os globais)
+
+	SoftDelete string            // Propriedade de data da exclusão lógica (WithSoftDelete)
+	RecycleBin *RecycleBinConfig // Lixeira das linhas excluídas logicamente (WithRecycleBin)
+
+	History bool // Mantém as versões anteriores na tabela de histórico (WithHistory)
+
+	Pagination *PaginationConfig // Paginação dirigida pelo servidor (WithPagination)
+
+	Authorizers []EntityAuthorizer // Callbacks de autorização da entidade (WithAuthorizer)
+
+	ExposedProperties []string // Únicas propriedades expostas nas consultas (WithExposedProperties)
+	RequireSelect     bool     // Exige $select nas consultas da entidade (WithRequireSelect)
+
+	AutoValues []AutoValue // Propriedades preenchidas pelo servidor nas gravações (WithAutoValue)
+
+	Sync   *SyncConfig   // Sincronização em lote via POST /Entidade/$sync (WithSync)
+	Import *ImportConfig // Importação de CSV/XLSX via POST /Entidade/$import (WithImport)
+	Export *ExportConfig // Exportação assíncrona via POST /Entidade/$export (WithExport)
+
+	Provider string // Provider nomeado da entidade, registrado com AddProvider (WithProvider)
 }
 
 // EntityOption função que modifica a configuração de uma entidade
@@ -43,6 +78,14 @@ func WithPermissions(permissions ...string) EntityOption {
 	}
 }
 
+// WithQueryTimeout define um timeout de query específico para a entidade, sobrescrevendo
+// o QueryTimeout do servidor (ex: relatórios mais lentos)
+func WithQueryTimeout(timeout time.Duration) EntityOption {
+	return func(config *EntityConfig) {
+		config.QueryTimeout = timeout
+	}
+}
+
 // GetCurrentUser obtém o usuário atual do contexto
 func GetCurrentUser(c fiber.Ctx) *UserIdentity {
 	if user := c.Locals(UserContextKey); user != nil {
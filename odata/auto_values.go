@@ -0,0 +1,169 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// VALORES AUTOMÁTICOS (PREENCHIDOS PELO SERVIDOR A PARTIR DO CONTEXTO DA REQUISIÇÃO)
+// =======================================================================================
+
+// AutoValueSource obtém o valor de uma propriedade a partir do contexto da requisição.
+// ok = false quando o contexto não tem o valor (ex: requisição anônima)
+type AutoValueSource func(ctx context.Context) (value any, ok bool)
+
+// AutoValueOption configura um valor automático
+type AutoValueOption func(*AutoValue)
+
+// AutoValue é uma propriedade preenchida pelo servidor nas gravações (WithAutoValue)
+type AutoValue struct {
+	Property string
+	Source   AutoValueSource
+	OnInsert bool // Preenchida no INSERT
+	OnUpdate bool // Preenchida no UPDATE
+}
+
+// WithAutoValue preenche a propriedade com o valor da fonte no INSERT e no UPDATE (inclusive
+// em $batch e PATCH hierárquico), depois dos eventos e sobrescrevendo o valor enviado pelo
+// cliente. Nas operações não configuradas, e quando a fonte não tem valor, a propriedade é
+// removida do payload. Ex: WithAutoValue("created_by", FromUser("username"), AutoValueOnInsert())
+func WithAutoValue(property string, source AutoValueSource, opts ...AutoValueOption) EntityOption {
+	return func(config *EntityConfig) {
+		autoValue := AutoValue{Property: property, Source: source}
+		for _, opt := range opts {
+			opt(&autoValue)
+		}
+		if !autoValue.OnInsert && !autoValue.OnUpdate {
+			autoValue.OnInsert, autoValue.OnUpdate = true, true
+		}
+		config.AutoValues = append(config.AutoValues, autoValue)
+	}
+}
+
+// AutoValueOnInsert preenche a propriedade no INSERT (ex: created_by)
+func AutoValueOnInsert() AutoValueOption {
+	return func(autoValue *AutoValue) {
+		autoValue.OnInsert = true
+	}
+}
+
+// AutoValueOnUpdate preenche a propriedade no UPDATE (ex: updated_by)
+func AutoValueOnUpdate() AutoValueOption {
+	return func(autoValue *AutoValue) {
+		autoValue.OnUpdate = true
+	}
+}
+
+// FromUser obtém um campo do usuário autenticado: "username", "roles", "scopes", "admin" ou
+// uma claim customizada
+func FromUser(field string) AutoValueSource {
+	return func(ctx context.Context) (any, bool) {
+		user := contextUser(ctx)
+		if user == nil {
+			return nil, false
+		}
+		switch strings.ToLower(field) {
+		case "username":
+			return user.Username, user.Username != ""
+		case "roles":
+			return strings.Join(user.Roles, ","), true
+		case "scopes":
+			return strings.Join(user.Scopes, ","), true
+		case "admin":
+			return user.Admin, true
+		}
+		return user.GetCustomClaim(field)
+	}
+}
+
+// FromTenant obtém o tenant da requisição (multi-tenant)
+func FromTenant() AutoValueSource {
+	return func(ctx context.Context) (any, bool) {
+		tenant := contextTenant(ctx)
+		return tenant, tenant != ""
+	}
+}
+
+// FromNow obtém o instante da gravação
+func FromNow() AutoValueSource {
+	return func(ctx context.Context) (any, bool) {
+		return time.Now(), true
+	}
+}
+
+// validateAutoValues valida os valores automáticos no registro da entidade
+func validateAutoValues(metadata EntityMetadata) error {
+	for _, autoValue := range metadata.AutoValues {
+		if autoValue.Source == nil {
+			return fmt.Errorf("valor automático '%s' sem fonte", autoValue.Property)
+		}
+		prop := findPropertyByName(metadata, autoValue.Property)
+		if prop == nil {
+			return fmt.Errorf("propriedade '%s' do valor automático não encontrada", autoValue.Property)
+		}
+		if prop.IsKey {
+			return fmt.Errorf("propriedade '%s' do valor automático não pode ser chave", autoValue.Property)
+		}
+	}
+	return nil
+}
+
+// applyAutoValues preenche os valores automáticos da entidade no payload da gravação
+// (operation: QueryOperationInsert, QueryOperationUpdate ou queryOperationUpsert, que aplica
+// os dois tipos)
+func applyAutoValues(ctx context.Context, metadata EntityMetadata, data map[string]any, operation string) {
+	for _, autoValue := range metadata.AutoValues {
+		name := findPropertyByName(metadata, autoValue.Property).Name
+		for key := range data {
+			if strings.EqualFold(key, name) {
+				delete(data, key)
+			}
+		}
+
+		applies := autoValue.OnInsert && operation == QueryOperationInsert ||
+			autoValue.OnUpdate && operation == QueryOperationUpdate ||
+			operation == queryOperationUpsert
+		if !applies {
+			continue
+		}
+		if value, ok := autoValue.Source(ctx); ok {
+			data[name] = value
+		}
+	}
+}
+
+// batchIdentityKey guarda o usuário e o tenant da requisição $batch no contexto das
+// operações, executadas sem o contexto Fiber
+type batchIdentityKey struct{}
+
+// batchIdentity usuário e tenant da requisição $batch
+type batchIdentity struct {
+	user   *UserIdentity
+	tenant string
+}
+
+// withBatchIdentity associa o usuário e o tenant da requisição $batch ao contexto
+func withBatchIdentity(ctx context.Context, user *UserIdentity, tenant string) context.Context {
+	return context.WithValue(ctx, batchIdentityKey{}, batchIdentity{user: user, tenant: tenant})
+}
+
+// contextTenant retorna o tenant da requisição associada ao contexto ("" se não houver)
+func contextTenant(ctx context.Context) string {
+	if fc, ok := ctx.Value(FiberContextKey).(fiber.Ctx); ok && fc != nil {
+		if tenant, ok := fc.Locals(TenantContextKey).(string); ok {
+			return tenant
+		}
+	}
+	if tenant, ok := ctx.Value(TenantContextKey).(string); ok {
+		return tenant
+	}
+	if identity, ok := ctx.Value(batchIdentityKey{}).(batchIdentity); ok {
+		return identity.tenant
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_AutoValues(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	config := &EntityConfig{}
+	WithAutoValue("status", FromUser("username"), AutoValueOnInsert())(config)
+	WithAutoValue("customer_id", FromUser("customer"), AutoValueOnUpdate())(config)
+	orders := server.entities["Orders"].GetMetadata()
+	orders.AutoValues = config.AutoValues
+	require.NoError(t, validateAutoValues(orders))
+	service := NewBaseEntityService(server.provider, orders, server)
+	server.entities["Orders"] = service
+
+	row := func(id int) (status, customer sql.NullString) {
+		t.Helper()
+		err := server.provider.GetConnection().QueryRow("SELECT status, customer_id FROM orders WHERE id = ?", id).Scan(&status, &customer)
+		require.NoError(t, err)
+		return status, customer
+	}
+
+	user := &UserIdentity{Username: "ana", Custom: map[string]interface{}{"customer": int64(2)}}
+	ctx := withBatchIdentity(context.Background(), user, "")
+
+	t.Run("INSERT sobrescreve o valor do cliente", func(t *testing.T) {
+		_, err := service.Create(ctx, map[string]interface{}{"id": int64(10), "status": "forjado", "customer_id": int64(1)})
+		require.NoError(t, err)
+		status, customer := row(10)
+		assert.Equal(t, "ana", status.String)
+		assert.False(t, customer.Valid, "customer_id é preenchido apenas no UPDATE")
+	})
+
+	t.Run("UPDATE preserva a propriedade do INSERT", func(t *testing.T) {
+		_, err := service.Update(ctx, map[string]any{"id": int64(10)}, map[string]interface{}{"status": "alterado"})
+		require.NoError(t, err)
+		status, customer := row(10)
+		assert.Equal(t, "ana", status.String)
+		assert.Equal(t, "2", customer.String)
+	})
+
+	t.Run("Requisição anônima", func(t *testing.T) {
+		_, err := service.Create(context.Background(), map[string]interface{}{"id": int64(11), "status": "forjado"})
+		require.NoError(t, err)
+		status, _ := row(11)
+		assert.False(t, status.Valid)
+	})
+
+	t.Run("Operações do $batch", func(t *testing.T) {
+		processor := NewBatchProcessor(server)
+		processor.user = &UserIdentity{Username: "bruno"}
+		batchCtx, cancel := processor.batchContext(context.Background())
+		defer cancel()
+
+		resp, err := processor.executeOperation(batchCtx, &BatchHTTPOperation{
+			Method: "POST", URL: "/odata/Orders", ContentID: "1",
+			Body: []byte(`{"id": 12, "status": "forjado"}`),
+		}, map[string]interface{}{})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(resp.Body))
+		status, _ := row(12)
+		assert.Equal(t, "bruno", status.String)
+	})
+}
+
+func TestAutoValueSources(t *testing.T) {
+	ctx := withBatchIdentity(context.Background(), &UserIdentity{Username: "ana", Admin: true}, "acme")
+
+	value, ok := FromTenant()(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", value)
+
+	value, ok = FromUser("admin")(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, true, value)
+
+	_, ok = FromUser("department")(ctx)
+	assert.False(t, ok)
+
+	_, ok = FromTenant()(context.Background())
+	assert.False(t, ok)
+
+	metadata := EntityMetadata{Properties: []PropertyMetadata{{Name: "id", IsKey: true}, {Name: "created_by"}}}
+	metadata.AutoValues = []AutoValue{{Property: "updated_by", Source: FromNow()}}
+	assert.EqualError(t, validateAutoValues(metadata), "propriedade 'updated_by' do valor automático não encontrada")
+	metadata.AutoValues = []AutoValue{{Property: "id", Source: FromNow()}}
+	assert.Error(t, validateAutoValues(metadata))
+}
@@ -23,10 +23,7 @@ func (s *Server) NewRouterBasicAuth(validator func(string, string) bool, config
 				return validator(username, password)
 			},
 			Unauthorized: func(c fiber.Ctx) error {
-				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-					"error":   "Unauthorized",
-					"message": "Credenciais inválidas",
-				})
+				return s.writeODataError(c, fiber.StatusUnauthorized, NewODataError("Unauthorized", "Credenciais inválidas"), nil)
 			},
 		}
 	} else if len(config) > 0 && config[0] != nil {
@@ -41,10 +38,7 @@ func (s *Server) NewRouterBasicAuth(validator func(string, string) bool, config
 			Users: users,
 			Realm: realm,
 			Unauthorized: func(c fiber.Ctx) error {
-				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-					"error":   "Unauthorized",
-					"message": "Credenciais inválidas",
-				})
+				return s.writeODataError(c, fiber.StatusUnauthorized, NewODataError("Unauthorized", "Credenciais inválidas"), nil)
 			},
 		}
 	} else {
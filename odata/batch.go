@@ -5,12 +5,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"mime"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,14 +21,17 @@ import (
 
 // BatchRequest representa uma requisição batch OData
 type BatchRequest struct {
-	Parts []*BatchPart
+	Parts           []*BatchPart
+	IsJSON          bool // Requisição no formato JSON (OData 4.01) em vez de multipart/mixed
+	ContinueOnError bool // Prefer: odata.continue-on-error
 }
 
 // BatchPart representa uma parte individual do batch (request ou changeset)
 type BatchPart struct {
-	IsChangeset bool
-	Request     *BatchHTTPOperation
-	Changeset   []*BatchHTTPOperation
+	IsChangeset    bool
+	Request        *BatchHTTPOperation
+	Changeset      []*BatchHTTPOperation
+	AtomicityGroup string // Nome do grupo atômico (batch JSON)
 }
 
 // BatchHTTPOperation representa uma operação HTTP individual no batch
@@ -35,7 +40,8 @@ type BatchHTTPOperation struct {
 	URL       string
 	Headers   map[string]string
 	Body      []byte
-	ContentID string // Para referências dentro do batch
+	ContentID string   // Para referências dentro do batch
+	DependsOn []string // Ids/grupos que devem executar antes (batch JSON)
 }
 
 // BatchResponse representa a resposta de um batch
@@ -61,17 +67,76 @@ type BatchOperationResponse struct {
 // BatchProcessor processa requisições batch
 type BatchProcessor struct {
 	server *Server
+	config *BatchConfig
+	user   *UserIdentity // Usuário da requisição $batch (máscaras de propriedades)
+	tenant string        // Tenant da requisição $batch (valores automáticos)
+
+	maintenanceAllowed bool // IP ou usuário da requisição $batch liberados no modo de manutenção
 }
 
 // NewBatchProcessor cria um novo processador de batch
 func NewBatchProcessor(server *Server) *BatchProcessor {
+	config := DefaultBatchConfig()
+	if server != nil && server.config != nil && server.config.BatchConfig != nil {
+		config = server.config.BatchConfig
+	}
+
 	return &BatchProcessor{
 		server: server,
+		config: config,
 	}
 }
 
-// ParseBatchRequest faz o parsing de uma requisição batch multipart/mixed
+// ParseBatchRequest faz o parsing de uma requisição batch (multipart/mixed ou JSON),
+// valida os limites configurados e lê a preferência odata.continue-on-error
 func (bp *BatchProcessor) ParseBatchRequest(c fiber.Ctx) (*BatchRequest, error) {
+	batchReq, err := bp.parseBatchBody(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bp.validateLimits(batchReq); err != nil {
+		return nil, err
+	}
+
+	batchReq.ContinueOnError = preferContinueOnError(c.Get("Prefer"))
+	return batchReq, nil
+}
+
+// validateLimits verifica MaxOperations e MaxChangesets (valores <= 0 desabilitam o limite)
+func (bp *BatchProcessor) validateLimits(batchReq *BatchRequest) error {
+	operations, changesets := 0, 0
+	for _, part := range batchReq.Parts {
+		operations += len(part.operations())
+		if part.IsChangeset {
+			changesets++
+		}
+	}
+
+	if bp.config.MaxOperations > 0 && operations > bp.config.MaxOperations {
+		return fmt.Errorf("batch contains %d operations, maximum allowed is %d", operations, bp.config.MaxOperations)
+	}
+	if bp.config.MaxChangesets > 0 && changesets > bp.config.MaxChangesets {
+		return fmt.Errorf("batch contains %d changesets, maximum allowed is %d", changesets, bp.config.MaxChangesets)
+	}
+	return nil
+}
+
+// preferContinueOnError verifica se o header Prefer solicita odata.continue-on-error
+func preferContinueOnError(prefer string) bool {
+	for _, preference := range strings.Split(prefer, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(preference), "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "odata.continue-on-error" && name != "continue-on-error" {
+			continue
+		}
+		return !strings.EqualFold(strings.TrimSpace(value), "false")
+	}
+	return false
+}
+
+// parseBatchBody faz o parsing do corpo conforme o Content-Type
+func (bp *BatchProcessor) parseBatchBody(c fiber.Ctx) (*BatchRequest, error) {
 	contentType := c.Get("Content-Type")
 	if contentType == "" {
 		return nil, fmt.Errorf("Content-Type header is required for batch requests")
@@ -82,8 +147,12 @@ func (bp *BatchProcessor) ParseBatchRequest(c fiber.Ctx) (*BatchRequest, error)
 		return nil, fmt.Errorf("invalid Content-Type: %w", err)
 	}
 
+	if mediaType == "application/json" {
+		return bp.parseJSONBatch(c.Body())
+	}
+
 	if mediaType != "multipart/mixed" {
-		return nil, fmt.Errorf("Content-Type must be multipart/mixed, got: %s", mediaType)
+		return nil, fmt.Errorf("Content-Type must be multipart/mixed or application/json, got: %s", mediaType)
 	}
 
 	boundary := params["boundary"]
@@ -232,95 +301,222 @@ func (bp *BatchProcessor) parseOperation(body []byte) (*BatchHTTPOperation, erro
 		operation.Body = []byte(strings.Join(bodyLines, "\r\n"))
 	}
 
+	// POST com X-HTTP-Method é executado como o método tunelado
+	if err := bp.tunnelBatchOperation(operation); err != nil {
+		return nil, err
+	}
+
 	return operation, nil
 }
 
 // ExecuteBatch executa um batch request
+// Sem odata.continue-on-error, o processamento para na primeira requisição (fora de
+// changesets) que falhar
 func (bp *BatchProcessor) ExecuteBatch(ctx context.Context, batchReq *BatchRequest) (*BatchResponse, error) {
 	batchResp := &BatchResponse{
 		Parts: make([]*BatchResponsePart, 0),
 	}
 
+	ctx, cancel := bp.batchContext(ctx)
+	defer cancel()
+
+	err := bp.executeBatchParts(ctx, batchReq, func(part *BatchResponsePart) error {
+		batchResp.Parts = append(batchResp.Parts, part)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchResp, nil
+}
+
+// batchContext aplica o timeout configurado (BatchConfig.Timeout) ao contexto do batch e
+// associa a ele o usuário e o tenant da requisição (valores automáticos)
+func (bp *BatchProcessor) batchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = withBatchIdentity(ctx, bp.user, bp.tenant)
+	if bp.config.Timeout > 0 {
+		return context.WithTimeout(ctx, bp.config.Timeout)
+	}
+	return ctx, func() {}
+}
+
+// executeBatchParts executa as partes do batch em ordem, entregando a resposta de cada uma
+// a emit assim que fica pronta. Um erro de emit (ex: cliente desconectado) interrompe o batch
+func (bp *BatchProcessor) executeBatchParts(ctx context.Context, batchReq *BatchRequest, emit func(*BatchResponsePart) error) error {
 	// Mapa para armazenar referências de Content-ID
 	contentIDMap := make(map[string]interface{})
 
+	// Ids (e atomicityGroups) que falharam, para responder 424 aos dependentes
+	failed := make(map[string]bool)
+
 	for _, part := range batchReq.Parts {
+		if ctx.Err() != nil {
+			message := fmt.Sprintf("Batch timeout of %s exceeded", bp.config.Timeout)
+			if err := emit(errorResponsePart(part, http.StatusGatewayTimeout, "Timeout", message)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if dep := part.failedDependency(failed); dep != "" {
+			bp.markFailed(part, failed)
+			if err := emit(dependencyFailedPart(part, dep)); err != nil {
+				return err
+			}
+			if !part.IsChangeset && !batchReq.ContinueOnError {
+				break
+			}
+			continue
+		}
+
 		if part.IsChangeset {
 			// Executar changeset (transacional)
 			changesetResp, err := bp.executeChangeset(ctx, part.Changeset, contentIDMap)
+			responsePart := &BatchResponsePart{
+				IsChangeset: true,
+				Changeset:   changesetResp,
+			}
 			if err != nil {
-				// Se changeset falhar, retornar erro para todas as operações
-				failedResp := make([]*BatchOperationResponse, len(part.Changeset))
-				for i := range failedResp {
-					failedResp[i] = &BatchOperationResponse{
-						StatusCode: http.StatusInternalServerError,
-						Headers:    map[string]string{"Content-Type": "application/json"},
-						Body:       []byte(fmt.Sprintf(`{"error": {"message": "Changeset failed: %s"}}`, err.Error())),
-					}
-				}
-				batchResp.Parts = append(batchResp.Parts, &BatchResponsePart{
-					IsChangeset: true,
-					Changeset:   failedResp,
-				})
-			} else {
-				batchResp.Parts = append(batchResp.Parts, &BatchResponsePart{
-					IsChangeset: true,
-					Changeset:   changesetResp,
-				})
+				bp.markFailed(part, failed)
+				responsePart = failedChangesetPart(part, err)
+			}
+			if err := emit(responsePart); err != nil {
+				return err
 			}
 		} else {
 			// Executar requisição simples
 			resp, err := bp.executeOperation(ctx, part.Request, contentIDMap)
 			if err != nil {
-				resp = &BatchOperationResponse{
-					StatusCode: http.StatusInternalServerError,
-					Headers:    map[string]string{"Content-Type": "application/json"},
-					Body:       []byte(fmt.Sprintf(`{"error": {"message": "%s"}}`, err.Error())),
+				resp = batchErrorResponse(http.StatusInternalServerError, "InternalError", err.Error(), part.Request.ContentID)
+			}
+			if err := emit(&BatchResponsePart{IsChangeset: false, Response: resp}); err != nil {
+				return err
+			}
+			if resp.StatusCode >= 400 {
+				bp.markFailed(part, failed)
+				if !batchReq.ContinueOnError {
+					break
 				}
 			}
-			batchResp.Parts = append(batchResp.Parts, &BatchResponsePart{
-				IsChangeset: false,
-				Response:    resp,
-			})
 		}
 	}
 
-	return batchResp, nil
+	return nil
+}
+
+// batchOperationError indica que uma operação do changeset retornou status de erro
+type batchOperationError struct {
+	Index    int
+	Response *BatchOperationResponse
+}
+
+func (e *batchOperationError) Error() string {
+	return fmt.Sprintf("operation %d returned error status %d (rolled back)", e.Index, e.Response.StatusCode)
+}
+
+// failedDependency retorna a primeira dependência (dependsOn) que falhou, se houver
+func (part *BatchPart) failedDependency(failed map[string]bool) string {
+	for _, op := range part.operations() {
+		for _, dep := range op.DependsOn {
+			if failed[dep] {
+				return dep
+			}
+		}
+	}
+	return ""
+}
+
+// markFailed registra os ids e o atomicityGroup de uma parte que falhou
+func (bp *BatchProcessor) markFailed(part *BatchPart, failed map[string]bool) {
+	if part.AtomicityGroup != "" {
+		failed[part.AtomicityGroup] = true
+	}
+	for _, op := range part.operations() {
+		if op.ContentID != "" {
+			failed[op.ContentID] = true
+		}
+	}
+}
+
+// dependencyFailedPart cria respostas 424 (Failed Dependency) para uma parte não executada
+func dependencyFailedPart(part *BatchPart, dependency string) *BatchResponsePart {
+	message := fmt.Sprintf("Dependency '%s' failed", dependency)
+	return errorResponsePart(part, http.StatusFailedDependency, "FailedDependency", message)
+}
+
+// errorResponsePart cria a mesma resposta de erro para todas as operações de uma parte
+func errorResponsePart(part *BatchPart, statusCode int, code, message string) *BatchResponsePart {
+	if !part.IsChangeset {
+		return &BatchResponsePart{
+			Response: batchErrorResponse(statusCode, code, message, part.Request.ContentID),
+		}
+	}
+
+	responses := make([]*BatchOperationResponse, len(part.Changeset))
+	for i, op := range part.Changeset {
+		responses[i] = batchErrorResponse(statusCode, code, message, op.ContentID)
+	}
+	return &BatchResponsePart{IsChangeset: true, Changeset: responses}
 }
 
 // executeChangeset executa um changeset (transacional)
 func (bp *BatchProcessor) executeChangeset(ctx context.Context, operations []*BatchHTTPOperation, contentIDMap map[string]interface{}) ([]*BatchOperationResponse, error) {
 	responses := make([]*BatchOperationResponse, len(operations))
 
-	// Obter database provider padrão
-	provider := bp.server.provider
+	// Ordem de execução pelas dependências (dependsOn e referências de Content-ID); as
+	// respostas mantêm a ordem do documento
+	order, err := orderChangesetOperations(operations)
+	if err != nil {
+		return nil, err
+	}
+
+	// Provider das entidades do changeset (uma transação não atravessa bancos)
+	provider, err := bp.changesetProvider(operations)
+	if err != nil {
+		return nil, err
+	}
 	if provider == nil {
 		return nil, fmt.Errorf("no database provider configured")
 	}
 
 	// Iniciar transação
-	tx, err := provider.BeginTx(ctx, nil)
+	tx, err := beginSessionTx(ctx, provider, bp.changesetTxOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+
+	// Eventos "after" das operações só são disparados após o commit
+	var eventTx *EventTransaction
+	if bp.server.eventManager != nil {
+		eventTx = bp.server.eventManager.BeginTransaction(bp.newEventContext(ctx, ""))
+		ctx = WithEventTransaction(ctx, eventTx)
+	}
+
+	var txErr error
 	defer func() {
 		// Rollback automático se não houver commit explícito
 		if tx != nil {
 			tx.Rollback()
+			if eventTx != nil {
+				eventTx.Rollback(txErr)
+			}
 		}
 	}()
 
 	// Executar operações dentro da transação
-	for i, op := range operations {
-		resp, err := bp.executeOperationInTx(ctx, tx, op, contentIDMap)
+	for _, i := range order {
+		op := operations[i]
+		resp, err := bp.executeChangesetOperation(ctx, provider, tx.Tx, i, op, contentIDMap)
 		if err != nil {
 			// Se uma operação falha, rollback automático via defer
-			return nil, fmt.Errorf("operation %d failed (rolled back): %w", i, err)
+			txErr = fmt.Errorf("operation %d failed (rolled back): %w", i, err)
+			return nil, txErr
 		}
 
 		// Se status code indica erro, rollback
 		if resp.StatusCode >= 400 {
-			return nil, fmt.Errorf("operation %d returned error status %d (rolled back)", i, resp.StatusCode)
+			txErr = &batchOperationError{Index: i, Response: resp}
+			return nil, txErr
 		}
 
 		responses[i] = resp
@@ -333,32 +529,191 @@ func (bp *BatchProcessor) executeChangeset(ctx context.Context, operations []*Ba
 
 	// Se chegou aqui, todas as operações tiveram sucesso - commit
 	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		txErr = fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, txErr
 	}
 
 	// Marcar tx como nil para evitar rollback no defer
 	tx = nil
 
+	if eventTx != nil {
+		eventTx.Commit()
+	}
+
 	return responses, nil
 }
 
-// executeOperation executa uma operação individual
+// executeOperation executa uma operação individual (fora de changeset)
 func (bp *BatchProcessor) executeOperation(ctx context.Context, op *BatchHTTPOperation, contentIDMap map[string]interface{}) (*BatchOperationResponse, error) {
+	if op.Method != "GET" {
+		// Operações de escrita fora de changeset são executadas em sua própria transação
+		responses, err := bp.executeChangeset(ctx, []*BatchHTTPOperation{op}, contentIDMap)
+		if err != nil {
+			// Preserva a resposta de erro da própria operação (ex: 404, 400)
+			var opErr *batchOperationError
+			if errors.As(err, &opErr) {
+				return opErr.Response, nil
+			}
+			return nil, err
+		}
+		return responses[0], nil
+	}
+
 	// Resolver referências de Content-ID no URL
-	_ = bp.resolveContentID(op.URL, contentIDMap)
+	url := bp.resolveContentID(op.URL, contentIDMap)
+	path, rawQuery, _ := strings.Cut(url, "?")
+	path, presetName, isPreset := cutQueryPresetPath(path)
+
+	entityName, entityID, err := bp.parseOperationURL(path)
+	if err != nil {
+		return batchErrorResponse(http.StatusBadRequest, "BadRequest", fmt.Sprintf("Invalid URL: %s", err.Error()), op.ContentID), nil
+	}
+
+	isCount := strings.HasSuffix(entityName, "/$count")
+	entityName = strings.TrimSuffix(entityName, "/$count")
+
+	service := bp.server.GetEntityService(entityName)
+	if service == nil {
+		return batchErrorResponse(http.StatusNotFound, "NotFound", fmt.Sprintf("Entity not found: %s", entityName), op.ContentID), nil
+	}
+	metadata := service.GetMetadata()
+
+	var presetFilter string
+	if isPreset {
+		preset, found := findQueryPreset(metadata, presetName)
+		if !found {
+			return batchErrorResponse(http.StatusNotFound, "NotFound", fmt.Sprintf("Function not found: Default.%s", presetName), op.ContentID), nil
+		}
+		presetFilter = preset.Filter
+	}
+
+	isCollection := entityID == ""
+	var keys map[string]interface{}
+	if !isCollection && !isCount {
+		keys, err = bp.server.extractKeys(path, metadata)
+		if err != nil {
+			return batchErrorResponse(http.StatusBadRequest, "InvalidKey", err.Error(), op.ContentID), nil
+		}
+	}
+	if resp := bp.authorizeOperation(ctx, entityName, op, keys); resp != nil {
+		return resp, nil
+	}
+
+	options, err := bp.server.parseQueryString(rawQuery)
+	if err == nil && isCount {
+		err = bp.server.checkHiddenProperties(&options, metadata)
+	} else if err == nil {
+		err = bp.server.applySelectPolicy(&options, metadata)
+	}
+	if err == nil && entityID == "" {
+		err = bp.server.applyDefaultFilters(&options, metadata, presetFilter)
+	}
+	if err != nil {
+		return batchErrorResponse(http.StatusBadRequest, "InvalidQuery", err.Error(), op.ContentID), nil
+	}
+
+	if isCount {
+		count, err := bp.server.getEntityCount(ctx, service, options, entityName)
+		if err != nil {
+			return batchErrorResponse(entityErrorStatus(err, http.StatusInternalServerError), "CountError", err.Error(), op.ContentID), nil
+		}
+		return &BatchOperationResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Content-Type": "text/plain"},
+			Body:       []byte(fmt.Sprintf("%d", count)),
+			ContentID:  op.ContentID,
+		}, nil
+	}
+
+	if !isCollection {
+		options, err = bp.server.applyKeyFilter(ctx, service, keys, options)
+		if err != nil {
+			return batchErrorResponse(http.StatusBadRequest, "InvalidKey", err.Error(), op.ContentID), nil
+		}
+	}
+
+	response, err := bp.server.handleEntityQueryWithEvents(ctx, service, options, entityName, isCollection)
+	if err != nil {
+		return batchErrorResponse(entityErrorStatus(err, http.StatusInternalServerError), "QueryError", err.Error(), op.ContentID), nil
+	}
+
+	if !isCollection {
+		if results, ok := response.Value.([]interface{}); !ok || len(results) == 0 {
+			return batchErrorResponse(http.StatusNotFound, "EntityNotFound", "Entity not found", op.ContentID), nil
+		}
+	}
+
+	var payload interface{} = bp.server.buildODataResponse(response, isCollection, metadata)
+	bp.server.applyMaskingPolicies(bp.user, service, payload)
+	contentType := "application/json"
+	if bp.server.config.IEEE754Compatible || hasIEEE754Compatible(headerValue(op.Headers, fiber.HeaderAccept)) {
+		payload, contentType = ieee754Value(payload), ieee754ContentType
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return batchErrorResponse(http.StatusInternalServerError, "InternalError", fmt.Sprintf("Failed to serialize response: %s", err.Error()), op.ContentID), nil
+	}
 
-	// TODO: Implementar execução real da operação
-	// Por enquanto, retornar sucesso
-	resp := &BatchOperationResponse{
+	return &BatchOperationResponse{
 		StatusCode: http.StatusOK,
 		Headers: map[string]string{
-			"Content-Type": "application/json",
+			"Content-Type":  contentType,
+			"OData-Version": "4.0",
 		},
-		Body:      []byte(`{"@odata.context": "$metadata", "value": "Success"}`),
+		Body:      body,
 		ContentID: op.ContentID,
+	}, nil
+}
+
+// batchErrorResponse cria uma resposta de erro no formato OData para uma operação do batch
+func batchErrorResponse(statusCode int, code, message, contentID string) *BatchOperationResponse {
+	body, _ := json.Marshal(ODataErrorResponse{Error: NewODataError(code, message)})
+	return &BatchOperationResponse{
+		StatusCode: statusCode,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       body,
+		ContentID:  contentID,
 	}
+}
 
-	return resp, nil
+// preparePayloadValues converte os valores do payload para os tipos das propriedades (como
+// no Create/Update do serviço). Retorna a resposta 400 da operação se algum valor for inválido
+func (bp *BatchProcessor) preparePayloadValues(service EntityService, metadata EntityMetadata, data map[string]interface{}, contentID string) *BatchOperationResponse {
+	base, ok := service.(*BaseEntityService)
+	if !ok {
+		return nil
+	}
+	err := base.preparePayloadValues(metadata, data)
+	if err == nil {
+		return nil
+	}
+	var odataErr *ODataError
+	if !errors.As(err, &odataErr) {
+		return batchErrorResponse(http.StatusBadRequest, "BadRequest", err.Error(), contentID)
+	}
+	body, _ := json.Marshal(ODataErrorResponse{Error: odataErr})
+	return &BatchOperationResponse{
+		StatusCode: odataErr.Status,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       body,
+		ContentID:  contentID,
+	}
+}
+
+// batchExecErrorResponse cria a resposta de erro de um comando SQL do batch. Violações de
+// constraint viram 409/400 com a propriedade como target, sem expor a mensagem do banco
+func batchExecErrorResponse(err error, metadata EntityMetadata, action, contentID string) *BatchOperationResponse {
+	if violation, ok := constraintViolation(TranslateDatabaseError(err, metadata)); ok {
+		body, _ := json.Marshal(ODataErrorResponse{Error: violation.ODataError()})
+		return &BatchOperationResponse{
+			StatusCode: violation.StatusCode(),
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       body,
+			ContentID:  contentID,
+		}
+	}
+	return batchErrorResponse(http.StatusInternalServerError, "InternalError", fmt.Sprintf("Failed to %s: %s", action, err.Error()), contentID)
 }
 
 // resolveContentID resolve referências de Content-ID em URLs (ex: $1, $2)
@@ -391,22 +746,30 @@ func (bp *BatchProcessor) resolveContentID(url string, contentIDMap map[string]i
 
 // extractIDFromResponse extrai o ID de uma resposta JSON
 func extractIDFromResponse(body []byte) string {
+	id := extractIDValueFromResponse(body)
+	if id == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", id)
+}
+
+// extractIDValueFromResponse extrai o ID de uma resposta JSON preservando o tipo: números
+// como json.Number (sem perda de precisão em int64) e textos como string
+func extractIDValueFromResponse(body []byte) interface{} {
 	// Parse JSON response
 	var data map[string]interface{}
-	if err := json.Unmarshal(body, &data); err != nil {
-		return ""
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return nil
 	}
 
 	// Tentar encontrar ID em várias formas
 	// Ordem de preferência: id, ID, Id, @odata.id
-	if id, ok := data["id"]; ok {
-		return fmt.Sprintf("%v", id)
-	}
-	if id, ok := data["ID"]; ok {
-		return fmt.Sprintf("%v", id)
-	}
-	if id, ok := data["Id"]; ok {
-		return fmt.Sprintf("%v", id)
+	for _, key := range []string{"id", "ID", "Id"} {
+		if id, ok := data[key]; ok {
+			return id
+		}
 	}
 	if odataID, ok := data["@odata.id"]; ok {
 		// @odata.id pode ser uma URL completa como "/Products(123)"
@@ -416,40 +779,137 @@ func extractIDFromResponse(body []byte) string {
 			start := strings.Index(idStr, "(")
 			end := strings.Index(idStr, ")")
 			if start < end {
-				return strings.Trim(idStr[start+1:end], "'\"")
+				key := idStr[start+1 : end]
+				if unquoted := strings.Trim(key, "'\""); unquoted != key {
+					return unquoted
+				}
+				if _, err := strconv.ParseFloat(key, 64); err == nil {
+					return json.Number(key)
+				}
+				return key
 			}
 		}
 		return idStr
 	}
 
-	return ""
+	return nil
+}
+
+// resolveOperationBody resolve referências de Content-ID nos valores do corpo JSON da
+// operação (ex: {"order_id": "$1"} ou {"order_id": "${1}"}), substituindo-as pelo ID da
+// entidade criada pela operação referenciada, com o tipo original (número ou texto). Apenas
+// valores que são inteiramente uma referência a um Content-ID conhecido são substituídos.
+// Retorna a própria operação quando não há o que resolver
+func (bp *BatchProcessor) resolveOperationBody(op *BatchHTTPOperation, contentIDMap map[string]interface{}) *BatchHTTPOperation {
+	if len(contentIDMap) == 0 || !bytes.Contains(op.Body, []byte("\"$")) {
+		return op
+	}
+
+	var body interface{}
+	decoder := json.NewDecoder(bytes.NewReader(op.Body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		// Corpo inválido: a própria operação reporta o erro
+		return op
+	}
+
+	ids := make(map[string]interface{}, len(contentIDMap))
+	for contentID, response := range contentIDMap {
+		if batchResp, ok := response.(*BatchOperationResponse); ok {
+			if id := extractIDValueFromResponse(batchResp.Body); id != nil {
+				ids[contentID] = id
+			}
+		}
+	}
+
+	resolved, changed := resolveContentIDValues(body, ids)
+	if !changed {
+		return op
+	}
+	newBody, err := json.Marshal(resolved)
+	if err != nil {
+		return op
+	}
+
+	resolvedOp := *op
+	resolvedOp.Body = newBody
+	return &resolvedOp
+}
+
+// resolveContentIDValues substitui recursivamente (objetos e arrays) os valores string que
+// referenciam um Content-ID ($1, ${1}) pelo ID correspondente
+func resolveContentIDValues(value interface{}, ids map[string]interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		if ref, ok := contentIDReference(v); ok {
+			if id, found := ids[ref]; found {
+				return id, true
+			}
+		}
+	case map[string]interface{}:
+		changed := false
+		for key, item := range v {
+			if resolved, ok := resolveContentIDValues(item, ids); ok {
+				v[key] = resolved
+				changed = true
+			}
+		}
+		return v, changed
+	case []interface{}:
+		changed := false
+		for i, item := range v {
+			if resolved, ok := resolveContentIDValues(item, ids); ok {
+				v[i] = resolved
+				changed = true
+			}
+		}
+		return v, changed
+	}
+	return value, false
+}
+
+// contentIDReference extrai o Content-ID de uma referência "$<id>" ou "${<id>}"
+func contentIDReference(value string) (string, bool) {
+	if strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") {
+		ref := value[2 : len(value)-1]
+		return ref, ref != ""
+	}
+	if strings.HasPrefix(value, "$") && !strings.ContainsAny(value[1:], "${}/() ") {
+		ref := value[1:]
+		return ref, ref != ""
+	}
+	return "", false
 }
 
 // executeOperationInTx executa uma operação dentro de uma transação
 func (bp *BatchProcessor) executeOperationInTx(ctx context.Context, tx *sql.Tx, op *BatchHTTPOperation, contentIDMap map[string]interface{}) (*BatchOperationResponse, error) {
-	// Resolver referências de Content-ID
+	// Resolver referências de Content-ID na URL e no corpo (ex: {"order_id": "$1"})
 	url := bp.resolveContentID(op.URL, contentIDMap)
+	op = bp.resolveOperationBody(op, contentIDMap)
 
 	// Parse URL e extrair entidade/ID
 	entityName, entityID, err := bp.parseOperationURL(url)
 	if err != nil {
-		return &BatchOperationResponse{
-			StatusCode: http.StatusBadRequest,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(fmt.Sprintf(`{"error":{"code":"BadRequest","message":"Invalid URL: %s"}}`, err.Error())),
-			ContentID:  op.ContentID,
-		}, nil
+		return batchErrorResponse(http.StatusBadRequest, "BadRequest", fmt.Sprintf("Invalid URL: %s", err.Error()), op.ContentID), nil
 	}
 
 	// Obter entity service
 	service := bp.server.GetEntityService(entityName)
 	if service == nil {
-		return &BatchOperationResponse{
-			StatusCode: http.StatusNotFound,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(fmt.Sprintf(`{"error":{"code":"NotFound","message":"Entity not found: %s"}}`, entityName)),
-			ContentID:  op.ContentID,
-		}, nil
+		return batchErrorResponse(http.StatusNotFound, "NotFound", fmt.Sprintf("Entity not found: %s", entityName), op.ContentID), nil
+	}
+
+	// Autorização da entidade (os middlewares das rotas não participam do $batch). As chaves
+	// só são extraídas quando há callbacks para recebê-las
+	var keys map[string]interface{}
+	if entityID != "" && bp.server.hasEntityAuthorizers(entityName) {
+		path, _, _ := strings.Cut(url, "?")
+		if keys, err = bp.server.extractKeys(path, service.GetMetadata()); err != nil {
+			return batchErrorResponse(http.StatusBadRequest, "InvalidKey", err.Error(), op.ContentID), nil
+		}
+	}
+	if resp := bp.authorizeOperation(ctx, entityName, op, keys); resp != nil {
+		return resp, nil
 	}
 
 	// Executar operação baseado no método HTTP
@@ -461,12 +921,40 @@ func (bp *BatchProcessor) executeOperationInTx(ctx context.Context, tx *sql.Tx,
 	case "DELETE":
 		return bp.executeDelete(ctx, tx, service, entityID, op)
 	default:
-		return &BatchOperationResponse{
-			StatusCode: http.StatusMethodNotAllowed,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(fmt.Sprintf(`{"error":{"code":"MethodNotAllowed","message":"Unsupported method in changeset: %s"}}`, op.Method)),
-			ContentID:  op.ContentID,
-		}, nil
+		return batchErrorResponse(http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("Unsupported method in changeset: %s", op.Method), op.ContentID), nil
+	}
+}
+
+// authorizeOperation verifica o modo de manutenção, autoriza a operação do batch com o
+// usuário da requisição $batch e retorna a resposta de erro quando ela é recusada
+func (bp *BatchProcessor) authorizeOperation(ctx context.Context, entityName string, op *BatchHTTPOperation, keys map[string]interface{}) *BatchOperationResponse {
+	var err error
+	if maintenanceErr := bp.server.checkMaintenanceOperation(op.Method); maintenanceErr != nil && !bp.maintenanceAllowed {
+		err = maintenanceErr
+	} else {
+		err = bp.server.authorizeEntity(ctx, &AuthorizationRequest{
+			EntityName: entityName,
+			Operation:  op.Method,
+			User:       bp.user,
+			Keys:       keys,
+			Batch:      true,
+		})
+	}
+	if err == nil {
+		return nil
+	}
+	var odataErr *ODataError
+	errors.As(err, &odataErr)
+	body, _ := json.Marshal(ODataErrorResponse{Error: odataErr})
+	headers := map[string]string{"Content-Type": "application/json"}
+	for header, value := range bp.server.maintenanceHeaders(err) {
+		headers[header] = value
+	}
+	return &BatchOperationResponse{
+		StatusCode: odataErr.Status,
+		Headers:    headers,
+		Body:       body,
+		ContentID:  op.ContentID,
 	}
 }
 
@@ -491,31 +979,67 @@ func (bp *BatchProcessor) parseOperationURL(url string) (string, string, error)
 	return entityName, entityID, nil
 }
 
+// changesetProvider retorna o provider das entidades do changeset. Operações sobre entidades
+// de providers diferentes (WithProvider) são recusadas, pois não cabem em uma transação
+func (bp *BatchProcessor) changesetProvider(operations []*BatchHTTPOperation) (DatabaseProvider, error) {
+	var provider DatabaseProvider
+	for i, op := range operations {
+		// Referências de Content-ID apontam para entidades do próprio changeset
+		if strings.HasPrefix(strings.TrimPrefix(op.URL, "/"), "$") {
+			continue
+		}
+		entityName, _, _ := bp.parseOperationURL(op.URL)
+		entityName, _, _ = strings.Cut(entityName, "?")
+		service := bp.server.GetEntityService(entityName)
+		if service == nil {
+			continue
+		}
+		opProvider := bp.server.serviceProvider(service)
+		if provider != nil && opProvider != provider {
+			return nil, &batchOperationError{Index: i, Response: batchErrorResponse(http.StatusBadRequest, "BadRequest",
+				fmt.Sprintf("Changeset operations must target entities of the same database provider: %s", entityName), op.ContentID)}
+		}
+		provider = opProvider
+	}
+	if provider == nil {
+		provider = bp.server.provider
+	}
+	return provider, nil
+}
+
 // executeCreate executa um CREATE dentro da transação
 func (bp *BatchProcessor) executeCreate(ctx context.Context, tx *sql.Tx, service EntityService, op *BatchHTTPOperation) (*BatchOperationResponse, error) {
+	// Obter metadata
+	metadata := service.GetMetadata()
+
 	// Parse JSON body
-	var entity map[string]interface{}
-	if err := json.Unmarshal(op.Body, &entity); err != nil {
-		return &BatchOperationResponse{
-			StatusCode: http.StatusBadRequest,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(fmt.Sprintf(`{"error":{"code":"BadRequest","message":"Invalid JSON: %s"}}`, err.Error())),
-			ContentID:  op.ContentID,
-		}, nil
+	entity, err := bp.server.decodeEntityJSON(op.Body, metadata)
+	if err != nil {
+		return batchErrorResponse(http.StatusBadRequest, "BadRequest", fmt.Sprintf("Invalid JSON: %s", err.Error()), op.ContentID), nil
 	}
 
-	// Obter metadata
-	metadata := service.GetMetadata()
+	applyAutoValues(ctx, metadata, entity, QueryOperationInsert)
+
+	// Gera as chaves com idGenerator do servidor ausentes no payload
+	generatedKey, err := bp.server.generateKeyValues(metadata, entity)
+	if err != nil {
+		return batchErrorResponse(http.StatusInternalServerError, "InternalError", err.Error(), op.ContentID), nil
+	}
+
+	if response := bp.preparePayloadValues(service, metadata, entity, op.ContentID); response != nil {
+		return response, nil
+	}
+
+	// Criptografa as propriedades Encrypted
+	stored, err := bp.server.encryptPropertyValues(ctx, metadata, entity)
+	if err != nil {
+		return batchErrorResponse(http.StatusInternalServerError, "InternalError", err.Error(), op.ContentID), nil
+	}
 
 	// Build INSERT query
-	query, args, err := bp.server.provider.BuildInsertQuery(metadata, entity)
+	query, args, err := bp.server.serviceProvider(service).BuildInsertQuery(metadata, stored)
 	if err != nil {
-		return &BatchOperationResponse{
-			StatusCode: http.StatusInternalServerError,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(fmt.Sprintf(`{"error":{"code":"InternalError","message":"Failed to build query: %s"}}`, err.Error())),
-			ContentID:  op.ContentID,
-		}, nil
+		return batchErrorResponse(http.StatusInternalServerError, "InternalError", fmt.Sprintf("Failed to build query: %s", err.Error()), op.ContentID), nil
 	}
 
 	// Log da query SQL se DB_LOG_SQL estiver habilitado
@@ -532,25 +1056,22 @@ func (bp *BatchProcessor) executeCreate(ctx context.Context, tx *sql.Tx, service
 		if bp.server.GetConfig() != nil && bp.server.GetConfig().DBLogSQL {
 			log.Printf("❌ [SQL] ERRO: %v", err)
 		}
-		return &BatchOperationResponse{
-			StatusCode: http.StatusInternalServerError,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(fmt.Sprintf(`{"error":{"code":"InternalError","message":"Failed to insert: %s"}}`, err.Error())),
-			ContentID:  op.ContentID,
-		}, nil
-	}
-
-	// Obter ID gerado
-	lastID, err := result.LastInsertId()
-	if err != nil {
-		// Alguns bancos não suportam LastInsertId, usar ID do entity se disponível
-		if id, ok := entity["id"]; ok {
-			entity["ID"] = id
-		} else if id, ok := entity["ID"]; ok {
-			entity["ID"] = id
+		return batchExecErrorResponse(err, metadata, "insert", op.ContentID), nil
+	}
+
+	// Obter ID gerado pelo banco (a chave gerada no servidor já está na entidade)
+	var lastID int64
+	if !generatedKey {
+		if lastID, err = result.LastInsertId(); err != nil {
+			// Alguns bancos não suportam LastInsertId, usar ID do entity se disponível
+			if id, ok := entity["id"]; ok {
+				entity["ID"] = id
+			} else if id, ok := entity["ID"]; ok {
+				entity["ID"] = id
+			}
+		} else {
+			entity["ID"] = lastID
 		}
-	} else {
-		entity["ID"] = lastID
 	}
 
 	// Serializar resposta
@@ -559,11 +1080,15 @@ func (bp *BatchProcessor) executeCreate(ctx context.Context, tx *sql.Tx, service
 		respBody = []byte(fmt.Sprintf(`{"ID":%d}`, lastID))
 	}
 
+	bp.emitEvent(ctx, NewEntityInsertedArgs(bp.newEventContext(ctx, metadata.Name), entity))
+
+	location := fmt.Sprintf("/%s(%v)", metadata.Name, entity["ID"])
 	return &BatchOperationResponse{
 		StatusCode: http.StatusCreated,
 		Headers: map[string]string{
-			"Content-Type": "application/json",
-			"Location":     fmt.Sprintf("/%s(%v)", metadata.Name, entity["ID"]),
+			"Content-Type":   "application/json",
+			"Location":       location,
+			"OData-EntityId": location,
 		},
 		Body:      respBody,
 		ContentID: op.ContentID,
@@ -572,20 +1097,15 @@ func (bp *BatchProcessor) executeCreate(ctx context.Context, tx *sql.Tx, service
 
 // executeUpdate executa um UPDATE dentro da transação
 func (bp *BatchProcessor) executeUpdate(ctx context.Context, tx *sql.Tx, service EntityService, entityID string, op *BatchHTTPOperation) (*BatchOperationResponse, error) {
-	// Parse JSON body
-	var updates map[string]interface{}
-	if err := json.Unmarshal(op.Body, &updates); err != nil {
-		return &BatchOperationResponse{
-			StatusCode: http.StatusBadRequest,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(fmt.Sprintf(`{"error":{"code":"BadRequest","message":"Invalid JSON: %s"}}`, err.Error())),
-			ContentID:  op.ContentID,
-		}, nil
-	}
-
 	// Obter metadata
 	metadata := service.GetMetadata()
 
+	// Parse JSON body
+	updates, err := bp.server.decodeEntityJSON(op.Body, metadata)
+	if err != nil {
+		return batchErrorResponse(http.StatusBadRequest, "BadRequest", fmt.Sprintf("Invalid JSON: %s", err.Error()), op.ContentID), nil
+	}
+
 	// Determinar chave primária
 	var keyColumn string
 	var keyProperty string
@@ -611,15 +1131,22 @@ func (bp *BatchProcessor) executeUpdate(ctx context.Context, tx *sql.Tx, service
 		keyProperty: entityID,
 	}
 
+	applyAutoValues(ctx, metadata, updates, QueryOperationUpdate)
+
+	if response := bp.preparePayloadValues(service, metadata, updates, op.ContentID); response != nil {
+		return response, nil
+	}
+
+	// Criptografa as propriedades Encrypted
+	updates, err = bp.server.encryptPropertyValues(ctx, metadata, updates)
+	if err != nil {
+		return batchErrorResponse(http.StatusInternalServerError, "InternalError", err.Error(), op.ContentID), nil
+	}
+
 	// Build UPDATE query
-	query, args, err := bp.server.provider.BuildUpdateQuery(metadata, updates, keyValues)
+	query, args, err := bp.server.serviceProvider(service).BuildUpdateQuery(metadata, updates, keyValues)
 	if err != nil {
-		return &BatchOperationResponse{
-			StatusCode: http.StatusInternalServerError,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(fmt.Sprintf(`{"error":{"code":"InternalError","message":"Failed to build query: %s"}}`, err.Error())),
-			ContentID:  op.ContentID,
-		}, nil
+		return batchErrorResponse(http.StatusInternalServerError, "InternalError", fmt.Sprintf("Failed to build query: %s", err.Error()), op.ContentID), nil
 	}
 
 	// Log da query SQL se DB_LOG_SQL estiver habilitado
@@ -630,34 +1157,30 @@ func (bp *BatchProcessor) executeUpdate(ctx context.Context, tx *sql.Tx, service
 		}
 	}
 
+	if err := recordHistory(ctx, bp.server.serviceProvider(service), metadata, keyValues, txHistoryExec(tx)); err != nil {
+		return batchExecErrorResponse(err, metadata, "update", op.ContentID), nil
+	}
+
 	// Execute UPDATE dentro da transação
 	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		if bp.server.GetConfig() != nil && bp.server.GetConfig().DBLogSQL {
 			log.Printf("❌ [SQL] ERRO: %v", err)
 		}
-		return &BatchOperationResponse{
-			StatusCode: http.StatusInternalServerError,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(fmt.Sprintf(`{"error":{"code":"InternalError","message":"Failed to update: %s"}}`, err.Error())),
-			ContentID:  op.ContentID,
-		}, nil
+		return batchExecErrorResponse(err, metadata, "update", op.ContentID), nil
 	}
 
 	// Verificar se alguma linha foi afetada
 	rowsAffected, err := result.RowsAffected()
 	if err == nil && rowsAffected == 0 {
-		return &BatchOperationResponse{
-			StatusCode: http.StatusNotFound,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(`{"error":{"code":"NotFound","message":"Entity not found"}}`),
-			ContentID:  op.ContentID,
-		}, nil
+		return batchErrorResponse(http.StatusNotFound, "NotFound", "Entity not found", op.ContentID), nil
 	}
 
 	// Adicionar ID ao resultado
 	updates[keyProperty] = entityID
 
+	bp.emitEvent(ctx, NewEntityModifiedArgs(bp.newEventContext(ctx, metadata.Name), keyValues, updates, nil))
+
 	// Serializar resposta
 	respBody, err := json.Marshal(updates)
 	if err != nil {
@@ -705,14 +1228,9 @@ func (bp *BatchProcessor) executeDelete(ctx context.Context, tx *sql.Tx, service
 	}
 
 	// Build DELETE query
-	query, args, err := bp.server.provider.BuildDeleteQuery(metadata, keyValues)
+	query, args, err := buildDeleteQuery(ctx, bp.server.serviceProvider(service), metadata, keyValues)
 	if err != nil {
-		return &BatchOperationResponse{
-			StatusCode: http.StatusInternalServerError,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(fmt.Sprintf(`{"error":{"code":"InternalError","message":"Failed to build query: %s"}}`, err.Error())),
-			ContentID:  op.ContentID,
-		}, nil
+		return batchErrorResponse(http.StatusInternalServerError, "InternalError", fmt.Sprintf("Failed to build query: %s", err.Error()), op.ContentID), nil
 	}
 
 	// Log da query SQL se DB_LOG_SQL estiver habilitado
@@ -723,31 +1241,27 @@ func (bp *BatchProcessor) executeDelete(ctx context.Context, tx *sql.Tx, service
 		}
 	}
 
+	if err := recordHistory(ctx, bp.server.serviceProvider(service), metadata, keyValues, txHistoryExec(tx)); err != nil {
+		return batchExecErrorResponse(err, metadata, "delete", op.ContentID), nil
+	}
+
 	// Execute DELETE dentro da transação
 	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		if bp.server.GetConfig() != nil && bp.server.GetConfig().DBLogSQL {
 			log.Printf("❌ [SQL] ERRO: %v", err)
 		}
-		return &BatchOperationResponse{
-			StatusCode: http.StatusInternalServerError,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(fmt.Sprintf(`{"error":{"code":"InternalError","message":"Failed to delete: %s"}}`, err.Error())),
-			ContentID:  op.ContentID,
-		}, nil
+		return batchExecErrorResponse(err, metadata, "delete", op.ContentID), nil
 	}
 
 	// Verificar se alguma linha foi afetada
 	rowsAffected, err := result.RowsAffected()
 	if err == nil && rowsAffected == 0 {
-		return &BatchOperationResponse{
-			StatusCode: http.StatusNotFound,
-			Headers:    map[string]string{"Content-Type": "application/json"},
-			Body:       []byte(`{"error":{"code":"NotFound","message":"Entity not found"}}`),
-			ContentID:  op.ContentID,
-		}, nil
+		return batchErrorResponse(http.StatusNotFound, "NotFound", "Entity not found", op.ContentID), nil
 	}
 
+	bp.emitEvent(ctx, NewEntityDeletedArgs(bp.newEventContext(ctx, metadata.Name), keyValues, nil))
+
 	// DELETE bem sucedido retorna 204 No Content
 	return &BatchOperationResponse{
 		StatusCode: http.StatusNoContent,
@@ -757,9 +1271,37 @@ func (bp *BatchProcessor) executeDelete(ctx context.Context, tx *sql.Tx, service
 	}, nil
 }
 
+// newEventContext cria um contexto de evento para operações do batch (sem contexto Fiber)
+func (bp *BatchProcessor) newEventContext(ctx context.Context, entityName string) *EventContext {
+	return &EventContext{
+		Context:          ctx,
+		EntityName:       entityName,
+		Timestamp:        time.Now().Unix(),
+		Extra:            make(map[string]interface{}),
+		DatabaseProvider: bp.server.serviceProvider(bp.server.GetEntityService(entityName)),
+	}
+}
+
+// emitEvent dispara um evento respeitando o escopo transacional do contexto, se houver
+func (bp *BatchProcessor) emitEvent(ctx context.Context, args EventArgs) {
+	if bp.server.eventManager == nil {
+		return
+	}
+
+	var err error
+	if eventTx := EventTransactionFromContext(ctx); eventTx != nil {
+		err = eventTx.Emit(args)
+	} else {
+		err = bp.server.eventManager.Emit(args)
+	}
+	if err != nil {
+		bp.server.logger.Printf("Erro ao disparar evento %s (batch): %v", args.GetEventType(), err)
+	}
+}
+
 // WriteBatchResponse escreve a resposta batch no formato multipart/mixed
 func (bp *BatchProcessor) WriteBatchResponse(c fiber.Ctx, batchResp *BatchResponse) error {
-	boundary := fmt.Sprintf("batchresponse_%d", time.Now().UnixNano())
+	boundary := newBatchBoundary()
 
 	c.Set("Content-Type", fmt.Sprintf("multipart/mixed; boundary=%s", boundary))
 
@@ -768,40 +1310,48 @@ func (bp *BatchProcessor) WriteBatchResponse(c fiber.Ctx, batchResp *BatchRespon
 	writer.SetBoundary(boundary)
 
 	for partIndex, part := range batchResp.Parts {
-		if part.IsChangeset {
-			// Escrever changeset
-			changesetBoundary := fmt.Sprintf("changeset_%d_%d", time.Now().UnixNano(), partIndex)
+		if err := bp.writeBatchPart(writer, partIndex, part); err != nil {
+			return err
+		}
+	}
 
-			partWriter, err := writer.CreatePart(map[string][]string{
-				"Content-Type": {fmt.Sprintf("multipart/mixed; boundary=%s", changesetBoundary)},
-			})
-			if err != nil {
-				return err
-			}
+	writer.Close()
 
-			changesetBuf := &bytes.Buffer{}
-			changesetWriter := multipart.NewWriter(changesetBuf)
-			changesetWriter.SetBoundary(changesetBoundary)
+	return c.Send(buf.Bytes())
+}
 
-			for _, opResp := range part.Changeset {
-				if err := bp.writeOperationResponse(changesetWriter, opResp); err != nil {
-					return err
-				}
-			}
+// newBatchBoundary gera o boundary da resposta multipart/mixed
+func newBatchBoundary() string {
+	return fmt.Sprintf("batchresponse_%d", time.Now().UnixNano())
+}
 
-			changesetWriter.Close()
-			partWriter.Write(changesetBuf.Bytes())
-		} else {
-			// Escrever resposta simples
-			if err := bp.writeOperationResponse(writer, part.Response); err != nil {
-				return err
-			}
-		}
+// writeBatchPart escreve uma parte da resposta (requisição simples ou changeset)
+func (bp *BatchProcessor) writeBatchPart(writer *multipart.Writer, partIndex int, part *BatchResponsePart) error {
+	if !part.IsChangeset {
+		// Escrever resposta simples
+		return bp.writeOperationResponse(writer, part.Response)
 	}
 
-	writer.Close()
+	// Escrever changeset
+	changesetBoundary := fmt.Sprintf("changeset_%d_%d", time.Now().UnixNano(), partIndex)
 
-	return c.Send(buf.Bytes())
+	partWriter, err := writer.CreatePart(map[string][]string{
+		"Content-Type": {fmt.Sprintf("multipart/mixed; boundary=%s", changesetBoundary)},
+	})
+	if err != nil {
+		return err
+	}
+
+	changesetWriter := multipart.NewWriter(partWriter)
+	changesetWriter.SetBoundary(changesetBoundary)
+
+	for _, opResp := range part.Changeset {
+		if err := bp.writeOperationResponse(changesetWriter, opResp); err != nil {
+			return err
+		}
+	}
+
+	return changesetWriter.Close()
 }
 
 // writeOperationResponse escreve uma resposta de operação no multipart writer
@@ -842,39 +1392,56 @@ func (s *Server) HandleBatch(c fiber.Ctx) error {
 	ctx := c.Context()
 
 	processor := NewBatchProcessor(s)
+	processor.user = GetCurrentUser(c)
+	if tenant, ok := c.Locals(TenantContextKey).(string); ok {
+		processor.tenant = tenant
+	}
+
+	// Modo de manutenção: com MaintenanceAll o $batch inteiro é recusado; com MaintenanceWrites
+	// apenas as operações de escrita
+	if err := s.checkMaintenance(c, fiber.MethodGet); err != nil {
+		for header, value := range s.maintenanceHeaders(err) {
+			c.Set(header, value)
+		}
+		return s.writeODataError(c, err.Status, err, nil)
+	}
+	processor.maintenanceAllowed = maintenanceAllowed(s.GetMaintenance(), c)
 
 	// Parse batch request
 	batchReq, err := processor.ParseBatchRequest(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": fiber.Map{
-				"code":    "BadRequest",
-				"message": fmt.Sprintf("Invalid batch request: %v", err),
-			},
-		})
+		return s.writeODataError(c, fiber.StatusBadRequest, NewODataError("BadRequest", fmt.Sprintf("Invalid batch request: %v", err)), err)
+	}
+
+	if batchReq.ContinueOnError {
+		c.Set("Preference-Applied", "odata.continue-on-error")
+	}
+
+	// multipart/mixed: as partes são enviadas ao cliente à medida que são executadas
+	if !batchReq.IsJSON {
+		return processor.StreamBatchResponse(c, batchReq)
 	}
 
 	// Execute batch
 	batchResp, err := processor.ExecuteBatch(ctx, batchReq)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": fiber.Map{
-				"code":    "InternalServerError",
-				"message": fmt.Sprintf("Error executing batch: %v", err),
-			},
-		})
+		return s.writeODataError(c, fiber.StatusInternalServerError, NewODataError("InternalServerError", fmt.Sprintf("Error executing batch: %v", err)), err)
 	}
 
 	// Write batch response
-	return processor.WriteBatchResponse(c, batchResp)
+	return processor.WriteJSONBatchResponse(c, batchReq, batchResp)
 }
 
 // BatchConfig configurações para batch requests
 type BatchConfig struct {
-	MaxOperations      int           // Máximo de operações por batch
-	MaxChangesets      int           // Máximo de changesets por batch
-	Timeout            time.Duration // Timeout para execução do batch
-	EnableTransactions bool          // Habilitar transações para changesets
+	MaxOperations      int                // Máximo de operações por batch
+	MaxChangesets      int                // Máximo de changesets por batch
+	Timeout            time.Duration      // Timeout para execução do batch
+	EnableTransactions bool               // Habilitar transações para changesets
+	IsolationLevel     sql.IsolationLevel // Nível de isolamento das transações (LevelDefault usa o padrão do banco)
+	UseSavepoints      bool               // Cria um savepoint antes de cada operação do changeset
+	OperationRetries   int                // Tentativas adicionais de operações com falha do servidor (requer UseSavepoints)
+	FlushInterval      time.Duration      // Intervalo de envio das partes da resposta multipart (0 = a cada parte)
 }
 
 // DefaultBatchConfig retorna configuração padrão
@@ -1,6 +1,8 @@
 package odata
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -306,3 +308,63 @@ func Test_ContentID_EdgeCases(t *testing.T) {
 		assert.Equal(t, "/Products$", resolved)
 	})
 }
+
+func Test_resolveOperationBody(t *testing.T) {
+	processor := &BatchProcessor{server: &Server{}}
+	contentIDMap := map[string]interface{}{
+		"1":     &BatchOperationResponse{Body: []byte(`{"ID":9007199254740993}`)},
+		"order": &BatchOperationResponse{Body: []byte(`{"@odata.id":"/Orders('A-1')"}`)},
+	}
+
+	t.Run("Referências em valores, objetos e arrays", func(t *testing.T) {
+		op := &BatchHTTPOperation{Method: "POST", URL: "/OrderItems", Body: []byte(
+			`{"order_id":"$1","ref":"${order}","price":"$10","note":"pedido $1","tags":["$order"],"nested":{"parent":"$1"}}`)}
+		resolved := processor.resolveOperationBody(op, contentIDMap)
+
+		assert.NotSame(t, op, resolved)
+		assert.JSONEq(t,
+			`{"order_id":9007199254740993,"ref":"A-1","price":"$10","note":"pedido $1","tags":["A-1"],"nested":{"parent":9007199254740993}}`,
+			string(resolved.Body))
+		assert.Contains(t, string(op.Body), `"$1"`, "a operação original não é alterada")
+	})
+
+	t.Run("Sem referências", func(t *testing.T) {
+		op := &BatchHTTPOperation{Method: "POST", URL: "/OrderItems", Body: []byte(`{"order_id":1}`)}
+		assert.Same(t, op, processor.resolveOperationBody(op, contentIDMap))
+	})
+
+	t.Run("Corpo inválido", func(t *testing.T) {
+		op := &BatchHTTPOperation{Method: "POST", URL: "/OrderItems", Body: []byte(`{"order_id":"$1"`)}
+		assert.Same(t, op, processor.resolveOperationBody(op, contentIDMap))
+	})
+}
+
+func TestBatchProcessor_Changeset_ContentIDInBody(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	processor := NewBatchProcessor(server)
+
+	responses, err := processor.executeChangeset(context.Background(), []*BatchHTTPOperation{
+		{Method: "POST", URL: "/odata/Orders", ContentID: "1", Body: []byte(`{"customer_id":2,"status":"new"}`)},
+		{Method: "POST", URL: "/odata/OrderItems", ContentID: "2", Body: []byte(`{"order_id":"$1","product":"Cabo"}`)},
+		{Method: "POST", URL: "/odata/OrderItems", ContentID: "3", Body: []byte(`{"order_id":"${1}","product":"Hub"}`)},
+	}, map[string]interface{}{})
+	require.NoError(t, err)
+	require.Len(t, responses, 3)
+
+	orderID := extractIDFromResponse(responses[0].Body)
+	require.NotEmpty(t, orderID)
+
+	rows, err := server.provider.GetConnection().Query("SELECT order_id, product FROM order_items WHERE product IN ('Cabo', 'Hub') ORDER BY id")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var items []string
+	for rows.Next() {
+		var itemOrderID int64
+		var product string
+		require.NoError(t, rows.Scan(&itemOrderID, &product))
+		items = append(items, fmt.Sprintf("%d:%s", itemOrderID, product))
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{orderID + ":Cabo", orderID + ":Hub"}, items)
+}
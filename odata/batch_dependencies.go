@@ -0,0 +1,159 @@
+package odata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// =======================================================================================
+// ORDEM DE EXECUÇÃO DO CHANGESET (GRAFO DE CONTENT-ID)
+// =======================================================================================
+
+// urlContentIDReference encontra referências $<id> e ${<id>} em URLs
+var urlContentIDReference = regexp.MustCompile(`\$\{([^}]+)\}|\$([A-Za-z0-9_.\-]+)`)
+
+// orderChangesetOperations retorna os índices das operações do changeset em ordem de
+// dependência: uma operação executa depois das que ela referencia por dependsOn (batch JSON)
+// ou por Content-ID na URL ou no corpo ($1, ${1}). Entre operações independentes, a ordem do
+// documento é mantida. Referências circulares retornam erro com as operações envolvidas
+func orderChangesetOperations(operations []*BatchHTTPOperation) ([]int, error) {
+	index := make(map[string]int, len(operations))
+	for i, op := range operations {
+		if op.ContentID != "" {
+			index[op.ContentID] = i
+		}
+	}
+
+	dependencies := make([]map[int]bool, len(operations))
+	for i, op := range operations {
+		dependencies[i] = make(map[int]bool)
+		for _, ref := range operationReferences(op) {
+			// Referências a operações fora do changeset são resolvidas pela ordem das partes
+			if target, ok := index[ref]; ok && target != i {
+				dependencies[i][target] = true
+			}
+		}
+	}
+
+	order := make([]int, 0, len(operations))
+	done := make([]bool, len(operations))
+	for len(order) < len(operations) {
+		progressed := false
+		for i := range operations {
+			if done[i] {
+				continue
+			}
+			ready := true
+			for dep := range dependencies[i] {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[i] = true
+				order = append(order, i)
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			return nil, circularChangesetError(operations, dependencies, done)
+		}
+	}
+	return order, nil
+}
+
+// operationReferences retorna os Content-IDs referenciados pela operação (dependsOn, URL e
+// valores do corpo JSON)
+func operationReferences(op *BatchHTTPOperation) []string {
+	refs := append([]string(nil), op.DependsOn...)
+
+	for _, match := range urlContentIDReference.FindAllStringSubmatch(op.URL, -1) {
+		if match[1] != "" {
+			refs = append(refs, match[1])
+		} else {
+			refs = append(refs, match[2])
+		}
+	}
+
+	if bytes.Contains(op.Body, []byte(`"$`)) {
+		var body interface{}
+		if err := json.Unmarshal(op.Body, &body); err == nil {
+			refs = collectContentIDReferences(body, refs)
+		}
+	}
+	return refs
+}
+
+// collectContentIDReferences acumula as referências ($1, ${1}) nos valores string do JSON
+func collectContentIDReferences(value interface{}, refs []string) []string {
+	switch v := value.(type) {
+	case string:
+		if ref, ok := contentIDReference(v); ok {
+			refs = append(refs, ref)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			refs = collectContentIDReferences(item, refs)
+		}
+	case []interface{}:
+		for _, item := range v {
+			refs = collectContentIDReferences(item, refs)
+		}
+	}
+	return refs
+}
+
+// circularChangesetError cria o erro 400 com as operações que formam o ciclo (as que apenas
+// dependem dele são descartadas da mensagem)
+func circularChangesetError(operations []*BatchHTTPOperation, dependencies []map[int]bool, done []bool) error {
+	pending := make(map[int]bool)
+	for i := range operations {
+		if !done[i] {
+			pending[i] = true
+		}
+	}
+	for removed := true; removed; {
+		removed = false
+		for i := range pending {
+			dependedOn := false
+			for j := range pending {
+				if dependencies[j][i] {
+					dependedOn = true
+					break
+				}
+			}
+			if !dependedOn {
+				delete(pending, i)
+				removed = true
+			}
+		}
+	}
+
+	first := -1
+	var ids []string
+	for i, op := range operations {
+		if !pending[i] {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		id := op.ContentID
+		if id == "" {
+			id = fmt.Sprintf("#%d", i)
+		}
+		ids = append(ids, id)
+	}
+
+	message := fmt.Sprintf("Circular Content-ID references in changeset: %s", strings.Join(ids, ", "))
+	return &batchOperationError{
+		Index:    first,
+		Response: batchErrorResponse(http.StatusBadRequest, "BadRequest", message, operations[first].ContentID),
+	}
+}
@@ -0,0 +1,103 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderChangesetOperations(t *testing.T) {
+	t.Run("Sem dependências mantém a ordem do documento", func(t *testing.T) {
+		order, err := orderChangesetOperations([]*BatchHTTPOperation{
+			{Method: "POST", URL: "/Orders", ContentID: "1"},
+			{Method: "POST", URL: "/Orders", ContentID: "2"},
+			{Method: "DELETE", URL: "/Orders(5)", ContentID: "3"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2}, order)
+	})
+
+	t.Run("Referências no corpo, na URL e dependsOn", func(t *testing.T) {
+		order, err := orderChangesetOperations([]*BatchHTTPOperation{
+			{Method: "POST", URL: "/OrderItems", ContentID: "item", Body: []byte(`{"order_id":"$order","product":"Cabo"}`)},
+			{Method: "PATCH", URL: "/Orders(${order})", ContentID: "status", Body: []byte(`{"status":"paid"}`)},
+			{Method: "POST", URL: "/Orders", ContentID: "order", Body: []byte(`{"customer_id":"$customer"}`)},
+			{Method: "POST", URL: "/Audit", ContentID: "audit", DependsOn: []string{"status"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 0, 1, 3}, order, "$customer está fora do changeset e não influencia a ordem")
+	})
+
+	t.Run("Ciclo retorna erro com as operações envolvidas", func(t *testing.T) {
+		_, err := orderChangesetOperations([]*BatchHTTPOperation{
+			{Method: "POST", URL: "/Orders", ContentID: "a", Body: []byte(`{"item_id":"$b"}`)},
+			{Method: "POST", URL: "/OrderItems", ContentID: "b", DependsOn: []string{"a"}},
+			{Method: "POST", URL: "/Audit", ContentID: "c", Body: []byte(`{"order_id":"$a"}`)},
+			{Method: "POST", URL: "/Orders", ContentID: "d"},
+		})
+		require.Error(t, err)
+
+		var opErr *batchOperationError
+		require.True(t, errors.As(err, &opErr))
+		assert.Equal(t, 0, opErr.Index)
+		assert.Equal(t, http.StatusBadRequest, opErr.Response.StatusCode)
+		assert.Contains(t, string(opErr.Response.Body), "Circular Content-ID references in changeset: a, b")
+	})
+
+	t.Run("Auto-referência é ignorada", func(t *testing.T) {
+		order, err := orderChangesetOperations([]*BatchHTTPOperation{
+			{Method: "POST", URL: "/Notes", ContentID: "1", Body: []byte(`{"text":"$1"}`)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{0}, order)
+	})
+}
+
+func TestBatchProcessor_Changeset_DependencyOrder(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	processor := NewBatchProcessor(server)
+
+	t.Run("Itens antes do pedido no documento", func(t *testing.T) {
+		responses, err := processor.executeChangeset(context.Background(), []*BatchHTTPOperation{
+			{Method: "POST", URL: "/odata/OrderItems", ContentID: "2", Body: []byte(`{"order_id":"$1","product":"Cabo"}`)},
+			{Method: "POST", URL: "/odata/Orders", ContentID: "1", Body: []byte(`{"customer_id":2,"status":"new"}`)},
+		}, map[string]interface{}{})
+		require.NoError(t, err)
+		require.Len(t, responses, 2)
+
+		// Respostas na ordem do documento
+		assert.Equal(t, "2", responses[0].ContentID)
+		assert.Equal(t, "1", responses[1].ContentID)
+
+		orderID := extractIDFromResponse(responses[1].Body)
+		var itemOrderID string
+		require.NoError(t, server.provider.GetConnection().
+			QueryRow("SELECT CAST(order_id AS TEXT) FROM order_items WHERE product = 'Cabo'").Scan(&itemOrderID))
+		assert.Equal(t, orderID, itemOrderID)
+	})
+
+	t.Run("Ciclo não executa nenhuma operação", func(t *testing.T) {
+		batchReq := &BatchRequest{Parts: []*BatchPart{{IsChangeset: true, Changeset: []*BatchHTTPOperation{
+			{Method: "POST", URL: "/odata/Orders", ContentID: "1", Body: []byte(`{"customer_id":"$2","status":"new"}`)},
+			{Method: "POST", URL: "/odata/Customers", ContentID: "2", Body: []byte(`{"name":"$1"}`)},
+		}}}}
+
+		var parts []*BatchResponsePart
+		require.NoError(t, processor.executeBatchParts(context.Background(), batchReq, func(part *BatchResponsePart) error {
+			parts = append(parts, part)
+			return nil
+		}))
+		require.Len(t, parts, 1)
+		require.NotNil(t, parts[0].Response)
+		assert.Equal(t, http.StatusBadRequest, parts[0].Response.StatusCode)
+		assert.Equal(t, "1", parts[0].Response.ContentID)
+
+		var customers int
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT COUNT(*) FROM customers").Scan(&customers))
+		assert.Equal(t, 2, customers)
+	})
+}
@@ -0,0 +1,108 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func newBatchGetTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE products (id INTEGER PRIMARY KEY, name TEXT, price REAL)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO products (id, name, price) VALUES (1, 'Mouse', 10), (2, 'Teclado', 50), (3, 'Monitor', 900)")
+	require.NoError(t, err)
+
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+	provider := NewMySQLProvider(db)
+	server := &Server{
+		provider:     provider,
+		entities:     make(map[string]EntityService),
+		parser:       NewODataParser(),
+		urlParser:    NewURLParser(),
+		logger:       logger,
+		config:       DefaultServerConfig(),
+		entityAuth:   make(map[string]EntityAuthConfig),
+		eventManager: NewEntityEventManager(logger),
+	}
+	metadata := EntityMetadata{
+		Name:      "Products",
+		TableName: "products",
+		Keys:      []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "name", ColumnName: "name", Type: "string"},
+			{Name: "price", ColumnName: "price", Type: "float64"},
+		},
+	}
+	server.entities["Products"] = NewBaseEntityService(provider, metadata, server)
+	return server
+}
+
+func TestBatchProcessor_executeOperation_GET(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	processor := NewBatchProcessor(server)
+	ctx := context.Background()
+
+	t.Run("Coleção com query options", func(t *testing.T) {
+		resp, err := processor.executeOperation(ctx, &BatchHTTPOperation{
+			Method: "GET",
+			URL:    "/odata/Products?$filter=price gt 20&$orderby=price desc",
+		}, map[string]interface{}{})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(resp.Body))
+
+		var body struct {
+			Value []map[string]interface{} `json:"value"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body, &body))
+		require.Len(t, body.Value, 2)
+		assert.Equal(t, "Monitor", body.Value[0]["name"])
+	})
+
+	t.Run("Entidade por chave", func(t *testing.T) {
+		resp, err := processor.executeOperation(ctx, &BatchHTTPOperation{Method: "GET", URL: "/odata/Products(2)"}, map[string]interface{}{})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(resp.Body))
+		assert.Contains(t, string(resp.Body), "Teclado")
+	})
+
+	t.Run("Entidade inexistente", func(t *testing.T) {
+		resp, err := processor.executeOperation(ctx, &BatchHTTPOperation{Method: "GET", URL: "/odata/Products(99)"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		resp, err := processor.executeOperation(ctx, &BatchHTTPOperation{Method: "GET", URL: "/odata/Products/$count"}, map[string]interface{}{})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(resp.Body))
+		assert.Equal(t, "3", string(resp.Body))
+	})
+
+	t.Run("Entity set desconhecido", func(t *testing.T) {
+		resp, err := processor.executeOperation(ctx, &BatchHTTPOperation{Method: "GET", URL: "/odata/Missing"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Query inválida", func(t *testing.T) {
+		resp, err := processor.executeOperation(ctx, &BatchHTTPOperation{Method: "GET", URL: "/odata/Products?$top=abc"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,260 @@
+package odata
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// JSON BATCH FORMAT (OData 4.01)
+// =======================================================================================
+
+// jsonBatchRequest representa o corpo de um batch no formato JSON
+type jsonBatchRequest struct {
+	Requests []jsonBatchOperation `json:"requests"`
+}
+
+// jsonBatchOperation representa uma requisição individual do batch JSON
+type jsonBatchOperation struct {
+	ID             string            `json:"id"`
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           json.RawMessage   `json:"body,omitempty"`
+	AtomicityGroup string            `json:"atomicityGroup,omitempty"`
+	DependsOn      []string          `json:"dependsOn,omitempty"`
+}
+
+// jsonBatchResponse representa o corpo da resposta de um batch JSON
+type jsonBatchResponse struct {
+	Responses []jsonBatchOperationResponse `json:"responses"`
+}
+
+// jsonBatchOperationResponse representa a resposta de uma requisição do batch JSON
+type jsonBatchOperationResponse struct {
+	ID             string            `json:"id"`
+	AtomicityGroup string            `json:"atomicityGroup,omitempty"`
+	Status         int               `json:"status"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           interface{}       `json:"body,omitempty"`
+}
+
+// parseJSONBatch faz o parsing de um batch application/json, agrupando requisições
+// por atomicityGroup (changesets) e ordenando-as conforme dependsOn
+func (bp *BatchProcessor) parseJSONBatch(body []byte) (*BatchRequest, error) {
+	var payload jsonBatchRequest
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid JSON batch body: %w", err)
+	}
+	if len(payload.Requests) == 0 {
+		return nil, fmt.Errorf("JSON batch must contain at least one request")
+	}
+
+	batchReq := &BatchRequest{
+		Parts:  make([]*BatchPart, 0),
+		IsJSON: true,
+	}
+
+	ids := make(map[string]bool)
+	groups := make(map[string]*BatchPart)
+	for i, item := range payload.Requests {
+		if item.ID == "" {
+			return nil, fmt.Errorf("request %d: id is required", i)
+		}
+		if ids[item.ID] {
+			return nil, fmt.Errorf("request %d: duplicate id '%s'", i, item.ID)
+		}
+		ids[item.ID] = true
+
+		if item.Method == "" || item.URL == "" {
+			return nil, fmt.Errorf("request '%s': method and url are required", item.ID)
+		}
+
+		operation := &BatchHTTPOperation{
+			Method:    strings.ToUpper(item.Method),
+			URL:       item.URL,
+			Headers:   item.Headers,
+			Body:      jsonBatchBody(item.Body, item.Headers),
+			ContentID: item.ID,
+			DependsOn: item.DependsOn,
+		}
+		if operation.Headers == nil {
+			operation.Headers = make(map[string]string)
+		}
+		if err := bp.tunnelBatchOperation(operation); err != nil {
+			return nil, fmt.Errorf("request '%s': %w", item.ID, err)
+		}
+
+		if item.AtomicityGroup == "" {
+			batchReq.Parts = append(batchReq.Parts, &BatchPart{Request: operation})
+			continue
+		}
+
+		// Requisições do mesmo atomicityGroup formam um changeset
+		group, exists := groups[item.AtomicityGroup]
+		if !exists {
+			if ids[item.AtomicityGroup] {
+				return nil, fmt.Errorf("atomicityGroup '%s' conflicts with a request id", item.AtomicityGroup)
+			}
+			group = &BatchPart{IsChangeset: true, AtomicityGroup: item.AtomicityGroup}
+			groups[item.AtomicityGroup] = group
+			batchReq.Parts = append(batchReq.Parts, group)
+		}
+		group.Changeset = append(group.Changeset, operation)
+	}
+
+	ordered, err := orderBatchParts(batchReq.Parts)
+	if err != nil {
+		return nil, err
+	}
+	batchReq.Parts = ordered
+
+	return batchReq, nil
+}
+
+// jsonBatchBody converte o corpo de uma requisição JSON para bytes
+// Corpos string com Content-Type não-JSON são enviados como texto
+func jsonBatchBody(raw json.RawMessage, headers map[string]string) []byte {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	if raw[0] == '"' && !isJSONContentType(headerValue(headers, "Content-Type")) {
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			return []byte(text)
+		}
+	}
+	return []byte(raw)
+}
+
+// orderBatchParts ordena as partes de forma que dependências executem antes dos
+// dependentes, preservando a ordem original sempre que possível
+func orderBatchParts(parts []*BatchPart) ([]*BatchPart, error) {
+	// Mapeia ids de requisição e nomes de atomicityGroup para a parte que os contém
+	owner := make(map[string]int)
+	for i, part := range parts {
+		if part.IsChangeset {
+			owner[part.AtomicityGroup] = i
+			for _, op := range part.Changeset {
+				owner[op.ContentID] = i
+			}
+		} else {
+			owner[part.Request.ContentID] = i
+		}
+	}
+
+	dependencies := make([]map[int]bool, len(parts))
+	for i, part := range parts {
+		dependencies[i] = make(map[int]bool)
+		for _, op := range part.operations() {
+			for _, dep := range op.DependsOn {
+				target, exists := owner[dep]
+				if !exists {
+					return nil, fmt.Errorf("request '%s' depends on unknown id '%s'", op.ContentID, dep)
+				}
+				if target != i {
+					dependencies[i][target] = true
+				}
+			}
+		}
+	}
+
+	ordered := make([]*BatchPart, 0, len(parts))
+	done := make([]bool, len(parts))
+	for len(ordered) < len(parts) {
+		progressed := false
+		for i, part := range parts {
+			if done[i] {
+				continue
+			}
+			ready := true
+			for dep := range dependencies[i] {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[i] = true
+				ordered = append(ordered, part)
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			return nil, fmt.Errorf("circular dependsOn references in batch")
+		}
+	}
+
+	return ordered, nil
+}
+
+// operations retorna as operações de uma parte (requisição simples ou changeset)
+func (part *BatchPart) operations() []*BatchHTTPOperation {
+	if part.IsChangeset {
+		return part.Changeset
+	}
+	return []*BatchHTTPOperation{part.Request}
+}
+
+// WriteJSONBatchResponse escreve a resposta batch no formato application/json
+func (bp *BatchProcessor) WriteJSONBatchResponse(c fiber.Ctx, batchReq *BatchRequest, batchResp *BatchResponse) error {
+	payload := jsonBatchResponse{Responses: make([]jsonBatchOperationResponse, 0)}
+
+	for i, part := range batchResp.Parts {
+		group := ""
+		if i < len(batchReq.Parts) {
+			group = batchReq.Parts[i].AtomicityGroup
+		}
+
+		responses := part.Changeset
+		if !part.IsChangeset {
+			responses = []*BatchOperationResponse{part.Response}
+		}
+		for _, resp := range responses {
+			payload.Responses = append(payload.Responses, jsonBatchOperationResponse{
+				ID:             resp.ContentID,
+				AtomicityGroup: group,
+				Status:         resp.StatusCode,
+				Headers:        resp.Headers,
+				Body:           jsonBatchResponseBody(resp),
+			})
+		}
+	}
+
+	c.Set("Content-Type", "application/json")
+	c.Set("OData-Version", "4.01")
+	return c.Status(fiber.StatusOK).JSON(payload)
+}
+
+// jsonBatchResponseBody converte o corpo de uma resposta em valor JSON (ou texto)
+func jsonBatchResponseBody(resp *BatchOperationResponse) interface{} {
+	if len(resp.Body) == 0 {
+		return nil
+	}
+	if isJSONContentType(headerValue(resp.Headers, "Content-Type")) && json.Valid(resp.Body) {
+		return json.RawMessage(resp.Body)
+	}
+	return string(resp.Body)
+}
+
+// isJSONContentType verifica se o Content-Type é JSON
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// headerValue obtém um header ignorando maiúsculas/minúsculas
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package odata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchProcessor_parseJSONBatch(t *testing.T) {
+	processor := NewBatchProcessor(&Server{})
+
+	t.Run("Agrupa atomicityGroup e ordena por dependsOn", func(t *testing.T) {
+		batchReq, err := processor.parseJSONBatch([]byte(`{"requests":[
+			{"id":"3","method":"get","url":"/odata/Products(10)","dependsOn":["g1"]},
+			{"id":"1","method":"post","url":"/odata/Products","atomicityGroup":"g1","body":{"name":"A"}},
+			{"id":"2","method":"post","url":"/odata/Products","atomicityGroup":"g1","body":{"name":"B"}}
+		]}`))
+		require.NoError(t, err)
+		assert.True(t, batchReq.IsJSON)
+		require.Len(t, batchReq.Parts, 2)
+
+		assert.True(t, batchReq.Parts[0].IsChangeset)
+		assert.Equal(t, "g1", batchReq.Parts[0].AtomicityGroup)
+		require.Len(t, batchReq.Parts[0].Changeset, 2)
+		assert.Equal(t, "POST", batchReq.Parts[0].Changeset[0].Method)
+		assert.JSONEq(t, `{"name":"A"}`, string(batchReq.Parts[0].Changeset[0].Body))
+
+		assert.Equal(t, "3", batchReq.Parts[1].Request.ContentID)
+	})
+
+	t.Run("Erros de validação", func(t *testing.T) {
+		cases := map[string]string{
+			"sem requests":        `{"requests":[]}`,
+			"id duplicado":        `{"requests":[{"id":"1","method":"GET","url":"/a"},{"id":"1","method":"GET","url":"/b"}]}`,
+			"dependência inexist": `{"requests":[{"id":"1","method":"GET","url":"/a","dependsOn":["x"]}]}`,
+			"dependência circular": `{"requests":[{"id":"1","method":"GET","url":"/a","dependsOn":["2"]},
+				{"id":"2","method":"GET","url":"/b","dependsOn":["1"]}]}`,
+		}
+		for name, body := range cases {
+			_, err := processor.parseJSONBatch([]byte(body))
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestHandleBatch_JSONFormat(t *testing.T) {
+	server := newBatchGetTestServer(t)
+
+	app := fiber.New()
+	app.Post("/odata/$batch", server.HandleBatch)
+
+	body := `{"requests":[
+		{"id":"read","method":"GET","url":"/odata/Products?$filter=price gt 100"},
+		{"id":"check","method":"GET","url":"/odata/Products(10)","dependsOn":["g1"]},
+		{"id":"create","method":"POST","url":"/odata/Products","atomicityGroup":"g1","headers":{"Content-Type":"application/json"},"body":{"id":10,"name":"Webcam","price":150}},
+		{"id":"bad","method":"DELETE","url":"/odata/Missing(1)"},
+		{"id":"after-bad","method":"GET","url":"/odata/Products","dependsOn":["bad"]}
+	]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/odata/$batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "odata.continue-on-error")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "application/json")
+
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var payload struct {
+		Responses []struct {
+			ID             string          `json:"id"`
+			AtomicityGroup string          `json:"atomicityGroup"`
+			Status         int             `json:"status"`
+			Body           json.RawMessage `json:"body"`
+		} `json:"responses"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &payload), string(raw))
+
+	statuses := make(map[string]int)
+	var order []string
+	for _, r := range payload.Responses {
+		statuses[r.ID] = r.Status
+		order = append(order, r.ID)
+	}
+
+	assert.Equal(t, []string{"read", "create", "check", "bad", "after-bad"}, order)
+	assert.Equal(t, http.StatusOK, statuses["read"])
+	assert.Equal(t, http.StatusCreated, statuses["create"])
+	assert.Equal(t, http.StatusOK, statuses["check"], "GET dependente deve ver o registro criado pelo grupo")
+	assert.Equal(t, http.StatusNotFound, statuses["bad"])
+	assert.Equal(t, http.StatusFailedDependency, statuses["after-bad"])
+
+	assert.Equal(t, "g1", payload.Responses[1].AtomicityGroup)
+	assert.Contains(t, string(payload.Responses[0].Body), "Monitor")
+}
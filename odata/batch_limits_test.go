@@ -0,0 +1,145 @@
+package odata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBatchProcessor_Config(t *testing.T) {
+	assert.Equal(t, DefaultBatchConfig(), NewBatchProcessor(&Server{}).config)
+
+	server := newBatchGetTestServer(t)
+	server.SetBatchConfig(&BatchConfig{MaxOperations: 5, Timeout: time.Second})
+	assert.Equal(t, 5, NewBatchProcessor(server).config.MaxOperations)
+}
+
+func TestBatchProcessor_validateLimits(t *testing.T) {
+	processor := NewBatchProcessor(&Server{})
+	processor.config = &BatchConfig{MaxOperations: 3, MaxChangesets: 1}
+
+	get := &BatchPart{Request: &BatchHTTPOperation{Method: "GET"}}
+	changeset := &BatchPart{IsChangeset: true, Changeset: []*BatchHTTPOperation{{Method: "POST"}, {Method: "POST"}}}
+
+	assert.NoError(t, processor.validateLimits(&BatchRequest{Parts: []*BatchPart{get, changeset}}))
+
+	err := processor.validateLimits(&BatchRequest{Parts: []*BatchPart{get, get, changeset}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "4 operations")
+
+	processor.config.MaxOperations = 0
+	err = processor.validateLimits(&BatchRequest{Parts: []*BatchPart{changeset, changeset}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 changesets")
+}
+
+func TestPreferContinueOnError(t *testing.T) {
+	assert.True(t, preferContinueOnError("odata.continue-on-error"))
+	assert.True(t, preferContinueOnError("return=minimal, odata.continue-on-error=true"))
+	assert.True(t, preferContinueOnError("Continue-On-Error"))
+	assert.False(t, preferContinueOnError("odata.continue-on-error=false"))
+	assert.False(t, preferContinueOnError("return=representation"))
+	assert.False(t, preferContinueOnError(""))
+}
+
+func TestBatchProcessor_ExecuteBatch_ContinueOnError(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	processor := NewBatchProcessor(server)
+
+	parts := func() []*BatchPart {
+		return []*BatchPart{
+			{Request: &BatchHTTPOperation{Method: "GET", URL: "/odata/Products(1)"}},
+			{Request: &BatchHTTPOperation{Method: "GET", URL: "/odata/Products(99)"}},
+			{Request: &BatchHTTPOperation{Method: "GET", URL: "/odata/Products(2)"}},
+		}
+	}
+
+	t.Run("Para na primeira falha por padrão", func(t *testing.T) {
+		resp, err := processor.ExecuteBatch(context.Background(), &BatchRequest{Parts: parts()})
+		require.NoError(t, err)
+		require.Len(t, resp.Parts, 2)
+		assert.Equal(t, http.StatusOK, resp.Parts[0].Response.StatusCode)
+		assert.Equal(t, http.StatusNotFound, resp.Parts[1].Response.StatusCode)
+	})
+
+	t.Run("Continua com odata.continue-on-error", func(t *testing.T) {
+		resp, err := processor.ExecuteBatch(context.Background(), &BatchRequest{Parts: parts(), ContinueOnError: true})
+		require.NoError(t, err)
+		require.Len(t, resp.Parts, 3)
+		assert.Equal(t, http.StatusNotFound, resp.Parts[1].Response.StatusCode)
+		assert.Equal(t, http.StatusOK, resp.Parts[2].Response.StatusCode)
+	})
+}
+
+func TestBatchProcessor_ExecuteBatch_Timeout(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	processor := NewBatchProcessor(server)
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	resp, err := processor.ExecuteBatch(ctx, &BatchRequest{Parts: []*BatchPart{
+		{Request: &BatchHTTPOperation{Method: "GET", URL: "/odata/Products(1)"}},
+		{IsChangeset: true, Changeset: []*BatchHTTPOperation{{Method: "DELETE", URL: "/odata/Products(1)"}}},
+	}})
+	require.NoError(t, err)
+	require.Len(t, resp.Parts, 2)
+	assert.Equal(t, http.StatusGatewayTimeout, resp.Parts[0].Response.StatusCode)
+	assert.Equal(t, http.StatusGatewayTimeout, resp.Parts[1].Changeset[0].StatusCode)
+}
+
+func TestHandleBatch_Limits(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.SetBatchConfig(&BatchConfig{MaxOperations: 1, Timeout: time.Second})
+
+	app := fiber.New()
+	app.Post("/odata/$batch", server.HandleBatch)
+
+	body := `{"requests":[
+		{"id":"1","method":"GET","url":"/odata/Products(1)"},
+		{"id":"2","method":"GET","url":"/odata/Products(2)"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/odata/$batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "maximum allowed is 1")
+}
+
+func TestHandleBatch_PreferenceApplied(t *testing.T) {
+	server := newBatchGetTestServer(t)
+
+	app := fiber.New()
+	app.Post("/odata/$batch", server.HandleBatch)
+
+	body := `{"requests":[
+		{"id":"1","method":"GET","url":"/odata/Products(99)"},
+		{"id":"2","method":"GET","url":"/odata/Products(2)"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/odata/$batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "odata.continue-on-error")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "odata.continue-on-error", resp.Header.Get("Preference-Applied"))
+
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `"id":"2"`)
+}
@@ -0,0 +1,124 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// =======================================================================================
+// ISOLAMENTO E SAVEPOINTS DE CHANGESETS
+// =======================================================================================
+
+// changesetTxOptions retorna as opções da transação do changeset (nil usa o padrão do banco)
+func (bp *BatchProcessor) changesetTxOptions() *sql.TxOptions {
+	if bp.config.IsolationLevel == sql.LevelDefault {
+		return nil
+	}
+	return &sql.TxOptions{Isolation: bp.config.IsolationLevel}
+}
+
+// changesetSavepoint representa um savepoint criado antes de uma operação do changeset
+type changesetSavepoint struct {
+	tx      *sql.Tx
+	name    string
+	release bool // Oracle não suporta RELEASE SAVEPOINT
+}
+
+// createSavepoint cria o savepoint da operação de índice informado
+func (bp *BatchProcessor) createSavepoint(ctx context.Context, provider DatabaseProvider, tx *sql.Tx, index int) (*changesetSavepoint, error) {
+	return newSavepoint(ctx, provider, tx, fmt.Sprintf("batch_op_%d", index))
+}
+
+// newSavepoint cria um savepoint na transação com a sintaxe do dialeto do provider
+func newSavepoint(ctx context.Context, provider DatabaseProvider, tx *sql.Tx, name string) (*changesetSavepoint, error) {
+	dialect := "default"
+	if provider != nil {
+		dialect = GetDialect(provider.GetDriverName()).GetName()
+	}
+
+	sp := &changesetSavepoint{
+		tx:      tx,
+		name:    name,
+		release: dialect != "oracle",
+	}
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+sp.name); err != nil {
+		return nil, fmt.Errorf("failed to create savepoint %s: %w", sp.name, err)
+	}
+	return sp, nil
+}
+
+// rollback desfaz apenas as alterações feitas após o savepoint
+func (sp *changesetSavepoint) rollback(ctx context.Context) error {
+	if _, err := sp.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+sp.name); err != nil {
+		return fmt.Errorf("failed to rollback to savepoint %s: %w", sp.name, err)
+	}
+	return nil
+}
+
+// releaseSavepoint libera o savepoint após o sucesso da operação
+func (sp *changesetSavepoint) releaseSavepoint(ctx context.Context) error {
+	if !sp.release {
+		return nil
+	}
+	if _, err := sp.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+sp.name); err != nil {
+		return fmt.Errorf("failed to release savepoint %s: %w", sp.name, err)
+	}
+	return nil
+}
+
+// executeChangesetOperation executa uma operação do changeset. Com UseSavepoints, a
+// operação é isolada por um savepoint e falhas do servidor (erro ou status 5xx) são
+// repetidas até OperationRetries vezes sem descartar as operações anteriores
+func (bp *BatchProcessor) executeChangesetOperation(ctx context.Context, provider DatabaseProvider, tx *sql.Tx, index int, op *BatchHTTPOperation, contentIDMap map[string]interface{}) (*BatchOperationResponse, error) {
+	if !bp.config.UseSavepoints {
+		return bp.executeOperationInTx(ctx, tx, op, contentIDMap)
+	}
+
+	sp, err := bp.createSavepoint(ctx, provider, tx, index)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := bp.executeOperationInTx(ctx, tx, op, contentIDMap)
+		if err == nil && resp.StatusCode < 400 {
+			return resp, sp.releaseSavepoint(ctx)
+		}
+
+		if rbErr := sp.rollback(ctx); rbErr != nil {
+			if err == nil {
+				return nil, rbErr
+			}
+			return nil, errors.Join(err, rbErr)
+		}
+
+		retryable := err != nil || resp.StatusCode >= http.StatusInternalServerError
+		if !retryable || attempt >= bp.config.OperationRetries || ctx.Err() != nil {
+			return resp, err
+		}
+
+		if bp.server.logger != nil {
+			bp.server.logger.Printf("Repetindo operação %d do changeset (tentativa %d/%d)", index, attempt+2, bp.config.OperationRetries+1)
+		}
+	}
+}
+
+// failedChangesetPart cria a resposta de um changeset que falhou. Conforme o OData, o
+// changeset é substituído por uma única resposta com o erro da operação que falhou
+func failedChangesetPart(part *BatchPart, err error) *BatchResponsePart {
+	var opErr *batchOperationError
+	if errors.As(err, &opErr) {
+		resp := *opErr.Response
+		if resp.ContentID == "" && opErr.Index < len(part.Changeset) {
+			resp.ContentID = part.Changeset[opErr.Index].ContentID
+		}
+		return &BatchResponsePart{Response: &resp}
+	}
+
+	return &BatchResponsePart{
+		Response: batchErrorResponse(http.StatusInternalServerError, "InternalError", fmt.Sprintf("Changeset failed: %s", err.Error()), ""),
+	}
+}
@@ -0,0 +1,115 @@
+package odata
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func countProducts(t *testing.T, server *Server) int {
+	t.Helper()
+
+	var count int
+	require.NoError(t, server.provider.GetConnection().QueryRow("SELECT COUNT(*) FROM products").Scan(&count))
+	return count
+}
+
+func TestBatchProcessor_changesetTxOptions(t *testing.T) {
+	processor := NewBatchProcessor(&Server{})
+	assert.Nil(t, processor.changesetTxOptions())
+
+	processor.config = &BatchConfig{IsolationLevel: sql.LevelSerializable}
+	opts := processor.changesetTxOptions()
+	require.NotNil(t, opts)
+	assert.Equal(t, sql.LevelSerializable, opts.Isolation)
+}
+
+func TestBatchProcessor_ExecuteBatch_ChangesetFailure(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	processor := NewBatchProcessor(server)
+
+	resp, err := processor.ExecuteBatch(context.Background(), &BatchRequest{Parts: []*BatchPart{
+		{IsChangeset: true, Changeset: []*BatchHTTPOperation{
+			{Method: "POST", URL: "/odata/Products", Body: []byte(`{"id":10,"name":"Webcam","price":150}`), ContentID: "1"},
+			{Method: "DELETE", URL: "/odata/Products(99)", ContentID: "2"},
+		}},
+	}})
+	require.NoError(t, err)
+	require.Len(t, resp.Parts, 1)
+
+	// O changeset é substituído pela resposta da operação que falhou
+	part := resp.Parts[0]
+	assert.False(t, part.IsChangeset)
+	require.NotNil(t, part.Response)
+	assert.Equal(t, http.StatusNotFound, part.Response.StatusCode)
+	assert.Equal(t, "2", part.Response.ContentID)
+	assert.Equal(t, 3, countProducts(t, server))
+}
+
+func TestBatchProcessor_Savepoints(t *testing.T) {
+	t.Run("Libera savepoints e confirma o changeset", func(t *testing.T) {
+		server := newBatchGetTestServer(t)
+		server.SetBatchConfig(&BatchConfig{UseSavepoints: true})
+		processor := NewBatchProcessor(server)
+
+		resp, err := processor.ExecuteBatch(context.Background(), &BatchRequest{Parts: []*BatchPart{
+			{IsChangeset: true, Changeset: []*BatchHTTPOperation{
+				{Method: "POST", URL: "/odata/Products", Body: []byte(`{"id":10,"name":"Webcam","price":150}`), ContentID: "1"},
+				{Method: "POST", URL: "/odata/Products", Body: []byte(`{"id":11,"name":"Headset","price":200}`), ContentID: "2"},
+			}},
+		}})
+		require.NoError(t, err)
+		require.True(t, resp.Parts[0].IsChangeset)
+		assert.Equal(t, http.StatusCreated, resp.Parts[0].Changeset[1].StatusCode)
+		assert.Equal(t, 5, countProducts(t, server))
+	})
+
+	t.Run("Repete falhas do servidor e reporta a operação", func(t *testing.T) {
+		server := newBatchGetTestServer(t)
+		var logs bytes.Buffer
+		server.logger = log.New(&logs, "", 0)
+		server.SetBatchConfig(&BatchConfig{UseSavepoints: true, OperationRetries: 2})
+		processor := NewBatchProcessor(server)
+
+		db := server.provider.GetConnection()
+		_, err := db.Exec("CREATE TRIGGER products_fail BEFORE INSERT ON products WHEN NEW.name = 'Falha' BEGIN SELECT RAISE(ABORT, 'falha simulada'); END")
+		require.NoError(t, err)
+
+		resp, err := processor.ExecuteBatch(context.Background(), &BatchRequest{Parts: []*BatchPart{
+			{IsChangeset: true, Changeset: []*BatchHTTPOperation{
+				{Method: "POST", URL: "/odata/Products", Body: []byte(`{"id":10,"name":"Webcam","price":150}`), ContentID: "1"},
+				{Method: "POST", URL: "/odata/Products", Body: []byte(`{"id":11,"name":"Falha","price":1}`), ContentID: "2"},
+			}},
+		}})
+		require.NoError(t, err)
+		require.Len(t, resp.Parts, 1)
+		assert.Equal(t, http.StatusInternalServerError, resp.Parts[0].Response.StatusCode)
+		assert.Equal(t, "2", resp.Parts[0].Response.ContentID)
+		assert.Equal(t, 2, strings.Count(logs.String(), "Repetindo operação 1"))
+		assert.Equal(t, 3, countProducts(t, server))
+	})
+
+	t.Run("Erros do cliente não são repetidos", func(t *testing.T) {
+		server := newBatchGetTestServer(t)
+		var logs bytes.Buffer
+		server.logger = log.New(&logs, "", 0)
+		server.SetBatchConfig(&BatchConfig{UseSavepoints: true, OperationRetries: 2})
+		processor := NewBatchProcessor(server)
+
+		resp, err := processor.ExecuteBatch(context.Background(), &BatchRequest{Parts: []*BatchPart{
+			{IsChangeset: true, Changeset: []*BatchHTTPOperation{
+				{Method: "DELETE", URL: "/odata/Products(99)", ContentID: "1"},
+			}},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.Parts[0].Response.StatusCode)
+		assert.Empty(t, logs.String())
+	})
+}
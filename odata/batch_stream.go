@@ -0,0 +1,149 @@
+package odata
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// RESPOSTA MULTIPART DO BATCH EM STREAMING
+// =======================================================================================
+
+// StreamBatchResponse executa o batch e envia a resposta multipart/mixed em streaming: cada
+// parte é escrita assim que sua operação termina, sem montar o corpo inteiro em memória. As
+// partes são enviadas ao cliente a cada BatchConfig.FlushInterval (0 = a cada parte). Se o
+// cliente desconectar, as operações restantes não são executadas
+func (bp *BatchProcessor) StreamBatchResponse(c fiber.Ctx, batchReq *BatchRequest) error {
+	ctx := c.Context()
+	boundary := newBatchBoundary()
+
+	c.Set("Content-Type", fmt.Sprintf("multipart/mixed; boundary=%s", boundary))
+	c.Status(fiber.StatusOK)
+
+	// O stream é escrito depois do retorno do handler: não usar c dentro da função
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := bp.batchContext(ctx)
+		defer cancel()
+
+		stream := bp.newBatchStreamWriter(ctx, w, boundary)
+		err := bp.executeBatchParts(ctx, batchReq, stream.writePart)
+		if closeErr := stream.close(); err == nil {
+			err = closeErr
+		}
+		if err != nil && bp.server != nil && bp.server.logger != nil {
+			bp.server.logger.Printf("❌ Erro ao enviar resposta do batch: %v", err)
+		}
+	})
+}
+
+// batchStreamWriter escreve as partes da resposta multipart/mixed no stream da resposta
+type batchStreamWriter struct {
+	mu        sync.Mutex
+	processor *BatchProcessor
+	out       *bufio.Writer
+	writer    *multipart.Writer
+	interval  time.Duration
+	parts     int
+	pending   bool  // Partes escritas no buffer e ainda não enviadas
+	err       error // Primeiro erro de escrita (ex: cliente desconectado)
+	done      chan struct{}
+}
+
+// newBatchStreamWriter cria o writer e, com FlushInterval, inicia o envio periódico
+func (bp *BatchProcessor) newBatchStreamWriter(ctx context.Context, out *bufio.Writer, boundary string) *batchStreamWriter {
+	writer := multipart.NewWriter(out)
+	writer.SetBoundary(boundary)
+
+	stream := &batchStreamWriter{
+		processor: bp,
+		out:       out,
+		writer:    writer,
+		interval:  bp.config.FlushInterval,
+		done:      make(chan struct{}),
+	}
+	if stream.interval > 0 {
+		go stream.flushLoop(ctx)
+	}
+	return stream
+}
+
+// flushLoop envia as partes pendentes a cada intervalo. Ao fim do contexto (timeout do
+// batch) envia imediatamente as partes já executadas, antes das respostas 504 das demais
+func (sw *batchStreamWriter) flushLoop(ctx context.Context) {
+	ticker := time.NewTicker(sw.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sw.done:
+			return
+		case <-ctx.Done():
+			sw.flush()
+			return
+		case <-ticker.C:
+			sw.flush()
+		}
+	}
+}
+
+// writePart escreve a resposta de uma parte do batch; sem FlushInterval, envia em seguida
+func (sw *batchStreamWriter) writePart(part *BatchResponsePart) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if sw.err != nil {
+		return sw.err
+	}
+	if err := sw.processor.writeBatchPart(sw.writer, sw.parts, part); err != nil {
+		sw.err = err
+		return err
+	}
+	sw.parts++
+	sw.pending = true
+
+	if sw.interval <= 0 {
+		sw.flushLocked()
+	}
+	return sw.err
+}
+
+// flush envia as partes pendentes ao cliente
+func (sw *batchStreamWriter) flush() {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	sw.flushLocked()
+}
+
+func (sw *batchStreamWriter) flushLocked() {
+	if !sw.pending || sw.err != nil {
+		return
+	}
+	if err := sw.out.Flush(); err != nil {
+		sw.err = fmt.Errorf("failed to flush batch response: %w", err)
+	}
+	sw.pending = false
+}
+
+// close encerra o envio periódico, escreve o boundary final e envia o restante
+func (sw *batchStreamWriter) close() error {
+	close(sw.done)
+
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if sw.err != nil {
+		return sw.err
+	}
+	if err := sw.writer.Close(); err != nil {
+		return err
+	}
+	sw.pending = true
+	sw.flushLocked()
+	return sw.err
+}
@@ -0,0 +1,162 @@
+package odata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleBatch_StreamMultipart(t *testing.T) {
+	for _, interval := range []time.Duration{0, 10 * time.Millisecond} {
+		t.Run(interval.String(), func(t *testing.T) {
+			server := newBatchGetTestServer(t)
+			server.SetBatchConfig(&BatchConfig{Timeout: time.Second, EnableTransactions: true, FlushInterval: interval})
+
+			app := fiber.New()
+			app.Post("/odata/$batch", server.HandleBatch)
+
+			body := strings.Join([]string{
+				"--batch_1",
+				"Content-Type: application/http",
+				"",
+				"GET /odata/Products(1) HTTP/1.1",
+				"",
+				"",
+				"--batch_1",
+				"Content-Type: multipart/mixed; boundary=changeset_1",
+				"",
+				"--changeset_1",
+				"Content-Type: application/http",
+				"",
+				"PATCH /odata/Products(2) HTTP/1.1",
+				"Content-Type: application/json",
+				"",
+				`{"name":"Teclado BR"}`,
+				"--changeset_1--",
+				"",
+				"--batch_1",
+				"Content-Type: application/http",
+				"",
+				"GET /odata/Products(2) HTTP/1.1",
+				"",
+				"",
+				"--batch_1--",
+				"",
+			}, "\r\n")
+			req := httptest.NewRequest(http.MethodPost, "/odata/$batch", strings.NewReader(body))
+			req.Header.Set("Content-Type", "multipart/mixed; boundary=batch_1")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+			require.NoError(t, err)
+			assert.Equal(t, "multipart/mixed", mediaType)
+
+			var parts []string
+			reader := multipart.NewReader(resp.Body, params["boundary"])
+			for {
+				part, err := reader.NextPart()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				raw, err := io.ReadAll(part)
+				require.NoError(t, err)
+				parts = append(parts, part.Header.Get("Content-Type")+"\n"+string(raw))
+			}
+
+			require.Len(t, parts, 3)
+			assert.Contains(t, parts[0], "HTTP/1.1 200 OK")
+			assert.Contains(t, parts[0], `"Mouse"`)
+			assert.True(t, strings.HasPrefix(parts[1], "multipart/mixed; boundary=changeset_"))
+			assert.Contains(t, parts[1], "HTTP/1.1 200 OK")
+			assert.Contains(t, parts[2], `"Teclado BR"`)
+		})
+	}
+}
+
+// recordingWriter registra o que chegou ao "cliente" (após o flush do bufio)
+type recordingWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+	err error
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return 0, w.err
+	}
+	return w.buf.Write(p)
+}
+
+func (w *recordingWriter) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Len()
+}
+
+func TestBatchStreamWriter_Flush(t *testing.T) {
+	part := &BatchResponsePart{Response: &BatchOperationResponse{StatusCode: http.StatusOK, Body: []byte(`{}`)}}
+
+	t.Run("Sem intervalo envia cada parte", func(t *testing.T) {
+		processor := NewBatchProcessor(nil)
+		out := &recordingWriter{}
+		stream := processor.newBatchStreamWriter(context.Background(), bufio.NewWriter(out), "b")
+
+		require.NoError(t, stream.writePart(part))
+		assert.Contains(t, out.buf.String(), "HTTP/1.1 200 OK")
+		require.NoError(t, stream.close())
+		assert.True(t, strings.HasSuffix(out.buf.String(), "--b--\r\n"))
+	})
+
+	t.Run("Intervalo acumula até o fim do contexto", func(t *testing.T) {
+		processor := NewBatchProcessor(nil)
+		processor.config = &BatchConfig{FlushInterval: time.Hour}
+		out := &recordingWriter{}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream := processor.newBatchStreamWriter(ctx, bufio.NewWriter(out), "b")
+
+		require.NoError(t, stream.writePart(part))
+		assert.Zero(t, out.Len())
+
+		// Deadline do batch: as partes executadas são enviadas imediatamente
+		cancel()
+		assert.Eventually(t, func() bool { return out.Len() > 0 }, time.Second, 5*time.Millisecond)
+		require.NoError(t, stream.close())
+	})
+
+	t.Run("Cliente desconectado interrompe o batch", func(t *testing.T) {
+		server := newBatchGetTestServer(t)
+		processor := NewBatchProcessor(server)
+		out := &recordingWriter{err: errors.New("broken pipe")}
+		stream := processor.newBatchStreamWriter(context.Background(), bufio.NewWriter(out), "b")
+
+		batchReq := &BatchRequest{ContinueOnError: true, Parts: []*BatchPart{
+			{Request: &BatchHTTPOperation{Method: "GET", URL: "/odata/Products(1)"}},
+			{Request: &BatchHTTPOperation{Method: "GET", URL: "/odata/Products(2)"}},
+		}}
+		err := processor.executeBatchParts(context.Background(), batchReq, stream.writePart)
+		assert.ErrorContains(t, err, "broken pipe")
+		assert.Equal(t, 1, stream.parts)
+		assert.Error(t, stream.close())
+	})
+}
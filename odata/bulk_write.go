@@ -0,0 +1,117 @@
+package odata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// ESCRITA EM LOTE NAS COLEÇÕES (207 MULTI-STATUS)
+// =======================================================================================
+
+// DefaultMaxBulkItems é o máximo padrão de itens de um POST em lote na coleção
+const DefaultMaxBulkItems = 1000
+
+// BulkItemResult é o resultado de um item do POST em lote: o índice no array enviado, o
+// status HTTP e a entidade criada ou o erro OData
+type BulkItemResult struct {
+	Index    int         `json:"index"`
+	Status   int         `json:"status"`
+	Location string      `json:"location,omitempty"`
+	Entity   interface{} `json:"entity,omitempty"`
+	Error    *ODataError `json:"error,omitempty"`
+}
+
+// BulkWriteResponse é o corpo da resposta 207 Multi-Status do POST em lote. Os clientes
+// reenviam apenas os itens com falha
+type BulkWriteResponse struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Value     []BulkItemResult `json:"value"`
+}
+
+// isJSONArrayBody verifica se o corpo da requisição é um array JSON
+func isJSONArrayBody(body []byte) bool {
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// maxBulkItems retorna o limite de itens do POST em lote (0 usa DefaultMaxBulkItems e
+// valores negativos desabilitam o limite)
+func (s *Server) maxBulkItems() int {
+	if s.config == nil || s.config.MaxBulkItems == 0 {
+		return DefaultMaxBulkItems
+	}
+	return s.config.MaxBulkItems
+}
+
+// handleBulkCreate cria cada item do array de forma independente (sem transação comum): uma
+// falha não desfaz os itens já criados. A resposta 207 traz o resultado de cada item
+func (s *Server) handleBulkCreate(c fiber.Ctx, service EntityService) error {
+	var items []json.RawMessage
+	if err := s.decodeJSONBody(c.Body(), &items); err != nil {
+		s.writeError(c, fiber.StatusBadRequest, "InvalidRequest", "Invalid JSON")
+		return nil
+	}
+	if len(items) == 0 {
+		s.writeError(c, fiber.StatusBadRequest, "InvalidRequest", "Bulk request must contain at least one entity")
+		return nil
+	}
+	if limit := s.maxBulkItems(); limit > 0 && len(items) > limit {
+		s.writeError(c, fiber.StatusRequestEntityTooLarge, "TooManyItems",
+			fmt.Sprintf("Bulk request contains %d entities, maximum allowed is %d", len(items), limit))
+		return nil
+	}
+
+	entityName := s.extractEntityName(c.Path())
+	if err := s.authorizeRequest(c, entityName, nil); err != nil {
+		s.writeEntityError(c, fiber.StatusForbidden, "Forbidden", err)
+		return nil
+	}
+
+	user := GetCurrentUser(c)
+	ieee754 := s.isIEEE754Compatible(c)
+	response := &BulkWriteResponse{Value: make([]BulkItemResult, 0, len(items))}
+	for i, item := range items {
+		result := BulkItemResult{Index: i}
+
+		entity, err := s.decodeEntityJSON(item, service.GetMetadata())
+		if err == nil && entity == nil {
+			err = fmt.Errorf("item is not a JSON object")
+		}
+		if err != nil {
+			result.Status = fiber.StatusBadRequest
+			result.Error = NewODataError("InvalidRequest", fmt.Sprintf("Invalid JSON: %v", err))
+			response.Failed++
+			response.Value = append(response.Value, result)
+			continue
+		}
+
+		createdEntity, status, code, err := s.createEntity(c, service, entityName, entity)
+		if err != nil {
+			result.Status, result.Error, _ = entityODataError(status, code, err)
+			response.Failed++
+			response.Value = append(response.Value, result)
+			continue
+		}
+
+		result.Status = status
+		result.Location = s.buildEntityURL(c, service, createdEntity)
+		s.applyMaskingPolicies(user, service, createdEntity)
+		if ieee754 {
+			createdEntity = ieee754Value(createdEntity)
+		}
+		result.Entity = createdEntity
+		response.Succeeded++
+		response.Value = append(response.Value, result)
+	}
+
+	c.Status(fiber.StatusMultiStatus)
+	if ieee754 {
+		return c.JSON(response, ieee754ContentType)
+	}
+	return c.JSON(response)
+}
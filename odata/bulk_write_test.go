@@ -0,0 +1,115 @@
+package odata
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_BulkCreate(t *testing.T) {
+	newServer := func(t *testing.T) *Server {
+		server := newBatchGetTestServer(t)
+		server.router = fiber.New()
+		server.setupEntityRoutes("Products")
+		return server
+	}
+
+	post := func(t *testing.T, server *Server, body string) (int, BulkWriteResponse) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/odata/Products", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var decoded BulkWriteResponse
+		json.NewDecoder(resp.Body).Decode(&decoded)
+		return resp.StatusCode, decoded
+	}
+
+	t.Run("reports per-item results and keeps the successful ones", func(t *testing.T) {
+		server := newServer(t)
+		server.OnEntityInserting("Products", func(args EventArgs) error {
+			inserting := args.(*EntityInsertingArgs)
+			if inserting.Data["name"] == "Bloqueado" {
+				inserting.Cancel("produto bloqueado")
+				return errors.New("canceled")
+			}
+			return nil
+		})
+
+		status, body := post(t, server, `[
+			{"id": 10, "name": "Cabo", "price": 12.5},
+			{"id": 11, "name": "Fonte", "price": "caro"},
+			{"id": 1, "name": "Duplicado", "price": 1},
+			{"id": 12, "name": "Bloqueado", "price": 3},
+			42,
+			{"id": 13, "name": "Hub", "price": 80}
+		]`)
+		require.Equal(t, http.StatusMultiStatus, status)
+		assert.Equal(t, 2, body.Succeeded)
+		assert.Equal(t, 4, body.Failed)
+		require.Len(t, body.Value, 6)
+
+		for i, item := range body.Value {
+			assert.Equal(t, i, item.Index)
+		}
+
+		assert.Equal(t, http.StatusCreated, body.Value[0].Status)
+		assert.Nil(t, body.Value[0].Error)
+		assert.Equal(t, "Cabo", body.Value[0].Entity.(map[string]any)["name"])
+
+		assert.Equal(t, http.StatusBadRequest, body.Value[1].Status)
+		assert.Equal(t, "price", body.Value[1].Error.Target)
+		assert.Nil(t, body.Value[1].Entity)
+
+		assert.GreaterOrEqual(t, body.Value[2].Status, 400)
+		require.NotNil(t, body.Value[2].Error)
+
+		assert.Equal(t, http.StatusBadRequest, body.Value[3].Status)
+		assert.Equal(t, "ValidationError", body.Value[3].Error.Code)
+		assert.Equal(t, "produto bloqueado", body.Value[3].Error.Message)
+
+		assert.Equal(t, http.StatusBadRequest, body.Value[4].Status)
+		assert.Equal(t, "InvalidRequest", body.Value[4].Error.Code)
+
+		assert.Equal(t, http.StatusCreated, body.Value[5].Status)
+
+		var count int
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT COUNT(*) FROM products WHERE id IN (10, 13)").Scan(&count))
+		assert.Equal(t, 2, count)
+		var name string
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT name FROM products WHERE id = 1").Scan(&name))
+		assert.Equal(t, "Mouse", name)
+	})
+
+	t.Run("rejects empty and oversized requests", func(t *testing.T) {
+		server := newServer(t)
+		status, _ := post(t, server, `[]`)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		server.SetMaxBulkItems(2)
+		status, _ = post(t, server, `[{"id": 20}, {"id": 21}, {"id": 22}]`)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+
+		var count int
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT COUNT(*) FROM products").Scan(&count))
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("single entity keeps 201", func(t *testing.T) {
+		server := newServer(t)
+		req := httptest.NewRequest(http.MethodPost, "/odata/Products", strings.NewReader(`{"id": 30, "name": "Cabo", "price": 5}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+}
@@ -0,0 +1,90 @@
+package odata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// =======================================================================================
+// COMPARAÇÕES DE STRINGS SEM DIFERENCIAR MAIÚSCULAS/MINÚSCULAS
+// =======================================================================================
+
+// caseInsensitiveOperators são os operadores e funções afetados pelo modo case-insensitive
+var caseInsensitiveOperators = map[string]bool{
+	"eq":         true,
+	"ne":         true,
+	"in":         true,
+	"contains":   true,
+	"startswith": true,
+	"endswith":   true,
+}
+
+// WithCaseInsensitive define se as comparações de strings do $filter da entidade ignoram
+// maiúsculas/minúsculas, sobrescrevendo o CaseInsensitive do servidor
+func WithCaseInsensitive(enabled bool) EntityOption {
+	return func(config *EntityConfig) {
+		config.CaseInsensitive = &enabled
+	}
+}
+
+// queryMetadata retorna os metadados usados na construção das queries, com o modo
+// case-insensitive resolvido a partir do servidor quando a entidade não o define
+func (s *BaseEntityService) queryMetadata() EntityMetadata {
+	metadata := s.metadata
+	if metadata.CaseInsensitive == nil && s.server != nil && s.server.config != nil && s.server.config.CaseInsensitive {
+		enabled := true
+		metadata.CaseInsensitive = &enabled
+	}
+	return metadata
+}
+
+// isCaseInsensitiveComparison verifica se a comparação deve ignorar maiúsculas/minúsculas:
+// o modo deve estar ativo e os operandos devem ser propriedades string ou literais string,
+// com pelo menos uma propriedade (expressões e outros tipos mantêm a comparação original)
+func (qb *QueryBuilder) isCaseInsensitiveComparison(operator string, operands []*ParseNode, metadata EntityMetadata) bool {
+	if metadata.CaseInsensitive == nil || !*metadata.CaseInsensitive || !caseInsensitiveOperators[strings.ToLower(operator)] {
+		return false
+	}
+
+	hasProperty := false
+	for _, operand := range operands {
+		if operand == nil || operand.Token == nil {
+			return false
+		}
+		switch operand.Token.Type {
+		case int(FilterTokenString):
+		case int(FilterTokenProperty):
+			prop := findPropertyByName(metadata, operand.Token.Value)
+			// Valores criptografados só podem ser comparados exatamente
+			if prop == nil || !strings.EqualFold(prop.Type, "string") || prop.IsEncrypted {
+				return false
+			}
+			hasProperty = true
+		default:
+			return false
+		}
+	}
+	return hasProperty
+}
+
+// lowerFunctionOperands indica se as funções de string precisam de LOWER() no modo
+// case-insensitive; no PostgreSQL contains/startswith/endswith já usam ILIKE
+func (qb *QueryBuilder) lowerFunctionOperands() bool {
+	return qb.dialect.GetName() != "postgresql"
+}
+
+// lowerExpression envolve a expressão SQL em LOWER()
+func lowerExpression(expr string) string {
+	return fmt.Sprintf("LOWER(%s)", expr)
+}
+
+// findPropertyByName busca uma propriedade (não navegação) pelo nome, sem diferenciar
+// maiúsculas/minúsculas
+func findPropertyByName(metadata EntityMetadata, name string) *PropertyMetadata {
+	for i, prop := range metadata.Properties {
+		if !prop.IsNavigation && strings.EqualFold(prop.Name, name) {
+			return &metadata.Properties[i]
+		}
+	}
+	return nil
+}
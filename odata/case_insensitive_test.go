@@ -0,0 +1,118 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBuilder_CaseInsensitive(t *testing.T) {
+	ctx := context.Background()
+	enabled := true
+	metadata := EntityMetadata{
+		Name:            "Products",
+		CaseInsensitive: &enabled,
+		Properties: []PropertyMetadata{
+			{Name: "Name", ColumnName: "name", Type: "string"},
+			{Name: "Price", ColumnName: "price", Type: "float64"},
+		},
+	}
+
+	build := func(t *testing.T, dialect, filter string, metadata EntityMetadata) (string, []interface{}) {
+		t.Helper()
+		parsed, err := ParseFilterString(ctx, filter)
+		require.NoError(t, err)
+		namedArgs := NewNamedArgs(dialect)
+		where, err := NewQueryBuilder(dialect).BuildWhereClauseNamed(ctx, parsed.Tree, metadata, namedArgs)
+		require.NoError(t, err)
+		return where, namedArgs.GetNamedArgs()
+	}
+
+	t.Run("eq, ne e in comparam em minúsculas", func(t *testing.T) {
+		sql, _ := build(t, "mysql", "Name eq 'Mouse'", metadata)
+		assert.Equal(t, "(LOWER(name) = LOWER(:param1))", sql)
+
+		sql, _ = build(t, "mysql", "Name ne 'Mouse'", metadata)
+		assert.Equal(t, "(LOWER(name) != LOWER(:param1))", sql)
+
+		sql, _ = build(t, "mysql", "Name in ('Mouse', 'Monitor')", metadata)
+		assert.Contains(t, sql, "LOWER(name) IN (LOWER(:param1), LOWER(:param2))")
+	})
+
+	t.Run("contains aplica LOWER e o padrão do LIKE", func(t *testing.T) {
+		where, args := build(t, "mysql", "contains(Name, 'Mou')", metadata)
+		assert.Equal(t, "(LOWER(name) LIKE LOWER(:param1))", where)
+		require.Len(t, args, 1)
+		assert.Equal(t, "%Mou%", args[0].(sql.NamedArg).Value)
+	})
+
+	t.Run("PostgreSQL usa ILIKE nas funções de string", func(t *testing.T) {
+		sql, _ := build(t, "postgresql", "startswith(Name, 'Mou')", metadata)
+		assert.Equal(t, "(name ILIKE @param1)", sql)
+
+		sql, _ = build(t, "postgresql", "Name eq 'Mouse'", metadata)
+		assert.Equal(t, "(LOWER(name) = LOWER(@param1))", sql)
+	})
+
+	t.Run("Propriedades não string e modo desabilitado não são alterados", func(t *testing.T) {
+		sql, _ := build(t, "mysql", "price eq 10", metadata)
+		assert.Equal(t, "(price = :param1)", sql)
+
+		disabled := metadata
+		disabled.CaseInsensitive = nil
+		sql, _ = build(t, "mysql", "Name eq 'Mouse'", disabled)
+		assert.Equal(t, "(name = :param1)", sql)
+	})
+}
+
+func TestServer_CaseInsensitiveFilter(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	filter := "$filter=" + url.QueryEscape("name eq 'MOUSE'")
+
+	t.Run("Sensível a maiúsculas/minúsculas por padrão", func(t *testing.T) {
+		status, body := getComputeTestProducts(t, server, filter)
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Empty(t, body["value"])
+	})
+
+	t.Run("Configuração global", func(t *testing.T) {
+		server.SetCaseInsensitive(true)
+		defer server.SetCaseInsensitive(false)
+
+		status, body := getComputeTestProducts(t, server, filter+"&$count=true")
+		require.Equal(t, http.StatusOK, status, body)
+		require.Len(t, body["value"], 1)
+		assert.Equal(t, "Mouse", body["value"].([]any)[0].(map[string]any)["name"])
+		assert.EqualValues(t, 1, body["@odata.count"])
+	})
+
+	t.Run("Entidade sobrescreve a configuração global", func(t *testing.T) {
+		server.SetCaseInsensitive(true)
+		defer server.SetCaseInsensitive(false)
+
+		metadata := server.entities["Products"].GetMetadata()
+		disabled := false
+		metadata.CaseInsensitive = &disabled
+		server.entities["Products"] = NewBaseEntityService(server.provider, metadata, server)
+
+		status, body := getComputeTestProducts(t, server, filter)
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Empty(t, body["value"])
+	})
+}
+
+func TestWithCaseInsensitive(t *testing.T) {
+	config := &EntityConfig{}
+	WithCaseInsensitive(true)(config)
+	require.NotNil(t, config.CaseInsensitive)
+	assert.True(t, *config.CaseInsensitive)
+}
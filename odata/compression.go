@@ -0,0 +1,157 @@
+package odata
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
+)
+
+// =======================================================================================
+// COMPRESSÃO DE RESPOSTAS (GZIP/BROTLI)
+// =======================================================================================
+
+// CompressionConfig configura a compressão das respostas (habilitada com EnableCompression)
+type CompressionConfig struct {
+	Brotli               bool     // Oferece brotli (br), preferido ao gzip quando o cliente aceita ambos
+	MinSize              int      // Tamanho mínimo do corpo (bytes) para comprimir
+	ExcludedContentTypes []string // Content-Types já comprimidos, não recomprimidos ("image/" cobre o tipo inteiro)
+}
+
+// DefaultCompressionConfig retorna configuração padrão de compressão
+func DefaultCompressionConfig() *CompressionConfig {
+	return &CompressionConfig{
+		Brotli:  true,
+		MinSize: 1024,
+		ExcludedContentTypes: []string{
+			"image/",
+			"video/",
+			"audio/",
+			"font/woff",
+			"font/woff2",
+			"application/zip",
+			"application/gzip",
+			"application/x-gzip",
+			"application/x-brotli",
+			"application/x-7z-compressed",
+			"application/x-rar-compressed",
+			"application/pdf",
+		},
+	}
+}
+
+// compressionConfig retorna a configuração de compressão em uso
+func (s *Server) compressionConfig() *CompressionConfig {
+	if s.config == nil || s.config.CompressionConfig == nil {
+		return DefaultCompressionConfig()
+	}
+	return s.config.CompressionConfig
+}
+
+// CompressionMiddleware comprime a resposta com gzip ou brotli, conforme o Accept-Encoding.
+// Não comprime corpos menores que MinSize, respostas em streaming (mídia, $batch multipart,
+// relatórios), Content-Types excluídos nem respostas que já têm Content-Encoding. A
+// configuração é lida a cada requisição, permitindo alterá-la após a criação do servidor
+func (s *Server) CompressionMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if s.config == nil || !s.config.EnableCompression {
+			return nil
+		}
+
+		config := s.compressionConfig()
+		resp := c.Response()
+		status := resp.StatusCode()
+		if status < fiber.StatusOK || status == fiber.StatusNoContent || status == fiber.StatusNotModified ||
+			resp.IsBodyStream() || len(resp.Header.ContentEncoding()) > 0 ||
+			len(resp.Body()) < config.MinSize || compressionExcluded(config, string(resp.Header.ContentType())) {
+			return nil
+		}
+
+		// A representação varia com o Accept-Encoding mesmo quando não é comprimida
+		c.Vary(fiber.HeaderAcceptEncoding)
+
+		body := resp.Body()
+		var compressed []byte
+		encoding := negotiateEncoding(c.Get(fiber.HeaderAcceptEncoding), config.Brotli)
+		switch encoding {
+		case "br":
+			compressed = fasthttp.AppendBrotliBytesLevel(nil, body, fasthttp.CompressBrotliDefaultCompression)
+		case "gzip":
+			compressed = fasthttp.AppendGzipBytesLevel(nil, body, fasthttp.CompressDefaultCompression)
+		default:
+			return nil
+		}
+		if len(compressed) >= len(body) {
+			return nil
+		}
+
+		resp.SetBodyRaw(compressed)
+		resp.Header.SetContentEncoding(encoding)
+		return nil
+	}
+}
+
+// compressionExcluded verifica se o Content-Type está na lista de exclusão
+func compressionExcluded(config *CompressionConfig, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	for _, excluded := range config.ExcludedContentTypes {
+		excluded = strings.ToLower(excluded)
+		if mediaType == excluded || (strings.HasSuffix(excluded, "/") && strings.HasPrefix(mediaType, excluded)) {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateEncoding escolhe a codificação pelo Accept-Encoding (valores q): brotli (se
+// habilitado) ou gzip. Em caso de empate, prefere brotli. Retorna "" se nenhuma é aceita
+func negotiateEncoding(acceptEncoding string, brotli bool) string {
+	qualities := make(map[string]float64)
+	for _, item := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && strings.EqualFold(strings.TrimSpace(key), "q") {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		qualities[name] = q
+	}
+
+	quality := func(encoding string) float64 {
+		if q, ok := qualities[encoding]; ok {
+			return q
+		}
+		if q, ok := qualities["*"]; ok {
+			return q
+		}
+		return 0
+	}
+
+	best, bestQ := "", 0.0
+	candidates := []string{"gzip"}
+	if brotli {
+		candidates = []string{"br", "gzip"}
+	}
+	for _, encoding := range candidates {
+		if q := quality(encoding); q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,122 @@
+package odata
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		accept   string
+		brotli   bool
+		expected string
+	}{
+		{"gzip, deflate, br", true, "br"},
+		{"gzip, deflate, br", false, "gzip"},
+		{"gzip;q=1.0, br;q=0.5", true, "gzip"},
+		{"br;q=0, gzip", true, "gzip"},
+		{"*", true, "br"},
+		{"*;q=0.5, gzip;q=0", true, "br"},
+		{"deflate", true, ""},
+		{"", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert.Equal(t, tt.expected, negotiateEncoding(tt.accept, tt.brotli))
+		})
+	}
+}
+
+func TestServer_CompressionMiddleware(t *testing.T) {
+	server := &Server{config: DefaultServerConfig()}
+	server.SetCompressionConfig(&CompressionConfig{Brotli: true, MinSize: 100, ExcludedContentTypes: []string{"image/", "application/zip"}})
+
+	large := strings.Repeat(`{"name":"Mouse","price":10}`, 100)
+	app := fiber.New()
+	app.Use(server.CompressionMiddleware())
+	app.Get("/large", func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.SendString(large)
+	})
+	app.Get("/small", func(c fiber.Ctx) error { return c.SendString("ok") })
+	app.Get("/image", func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "image/png")
+		return c.SendString(large)
+	})
+	app.Get("/stream", func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "application/octet-stream")
+		return c.SendStream(bytes.NewReader([]byte(large)))
+	})
+
+	send := func(t *testing.T, path, acceptEncoding string) (*http.Response, []byte) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set(fiber.HeaderAcceptEncoding, acceptEncoding)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	t.Run("Brotli", func(t *testing.T) {
+		resp, body := send(t, "/large", "gzip, br")
+		require.Equal(t, "br", resp.Header.Get(fiber.HeaderContentEncoding))
+		assert.Equal(t, fiber.HeaderAcceptEncoding, resp.Header.Get(fiber.HeaderVary))
+		assert.Less(t, len(body), len(large))
+		plain, err := fasthttp.AppendUnbrotliBytes(nil, body)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(plain))
+	})
+
+	t.Run("Gzip", func(t *testing.T) {
+		resp, body := send(t, "/large", "gzip")
+		require.Equal(t, "gzip", resp.Header.Get(fiber.HeaderContentEncoding))
+		plain, err := fasthttp.AppendGunzipBytes(nil, body)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(plain))
+	})
+
+	t.Run("Sem Accept-Encoding", func(t *testing.T) {
+		resp, body := send(t, "/large", "")
+		assert.Empty(t, resp.Header.Get(fiber.HeaderContentEncoding))
+		assert.Equal(t, fiber.HeaderAcceptEncoding, resp.Header.Get(fiber.HeaderVary))
+		assert.Equal(t, large, string(body))
+	})
+
+	for _, path := range []string{"/small", "/image", "/stream"} {
+		t.Run("Não comprime "+path, func(t *testing.T) {
+			resp, _ := send(t, path, "gzip, br")
+			assert.Empty(t, resp.Header.Get(fiber.HeaderContentEncoding))
+		})
+	}
+
+	t.Run("Brotli desabilitado", func(t *testing.T) {
+		server.config.CompressionConfig.Brotli = false
+		defer func() { server.config.CompressionConfig.Brotli = true }()
+
+		resp, _ := send(t, "/large", "br, gzip")
+		assert.Equal(t, "gzip", resp.Header.Get(fiber.HeaderContentEncoding))
+	})
+
+	t.Run("Compressão desabilitada", func(t *testing.T) {
+		server.config.EnableCompression = false
+		defer func() { server.config.EnableCompression = true }()
+
+		resp, body := send(t, "/large", "gzip, br")
+		assert.Empty(t, resp.Header.Get(fiber.HeaderContentEncoding))
+		assert.Equal(t, large, string(body))
+	})
+}
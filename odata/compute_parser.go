@@ -401,3 +401,61 @@ func (p *ComputeParser) inferComputeFieldType(node *ParseNode) string {
 		return "string"
 	}
 }
+
+// FindAlias retorna a expressão de compute com o alias informado (case-insensitive)
+func (c *ComputeOption) FindAlias(alias string) *ComputeExpression {
+	if c == nil {
+		return nil
+	}
+	for i := range c.Expressions {
+		if strings.EqualFold(c.Expressions[i].Alias, alias) {
+			return &c.Expressions[i]
+		}
+	}
+	return nil
+}
+
+// inlineComputeAliases retorna uma cópia da árvore substituindo referências a aliases de
+// $compute pela expressão correspondente, permitindo usá-los no WHERE (onde aliases do
+// SELECT não são visíveis). Propriedades da entidade têm precedência sobre os aliases
+func inlineComputeAliases(node *ParseNode, computeOption *ComputeOption, metadata EntityMetadata) *ParseNode {
+	if node == nil {
+		return nil
+	}
+
+	if node.Token != nil && node.Token.Type == int(FilterTokenProperty) && !hasProperty(metadata, node.Token.Value) {
+		if expr := computeOption.FindAlias(node.Token.Value); expr != nil && expr.ParseTree != nil {
+			return cloneParseTree(expr.ParseTree, node.Parent)
+		}
+	}
+
+	clone := &ParseNode{Token: node.Token, Parent: node.Parent}
+	for _, child := range node.Children {
+		inlined := inlineComputeAliases(child, computeOption, metadata)
+		inlined.Parent = clone
+		clone.Children = append(clone.Children, inlined)
+	}
+	return clone
+}
+
+// cloneParseTree copia uma árvore de parse (a árvore do compute é reutilizada no SELECT)
+func cloneParseTree(node *ParseNode, parent *ParseNode) *ParseNode {
+	if node == nil {
+		return nil
+	}
+	clone := &ParseNode{Token: node.Token, Parent: parent}
+	for _, child := range node.Children {
+		clone.Children = append(clone.Children, cloneParseTree(child, clone))
+	}
+	return clone
+}
+
+// hasProperty verifica se a entidade possui a propriedade (case-insensitive)
+func hasProperty(metadata EntityMetadata, name string) bool {
+	for _, prop := range metadata.Properties {
+		if strings.EqualFold(prop.Name, name) {
+			return true
+		}
+	}
+	return false
+}
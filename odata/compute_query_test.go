@@ -0,0 +1,94 @@
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getComputeTestProducts(t *testing.T, server *Server, query string) (int, map[string]any) {
+	t.Helper()
+
+	resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Products?"+query, nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func TestServer_ComputeInFilterAndOrderBy(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	compute := "$compute=" + url.QueryEscape("price mul 2 as Total")
+
+	t.Run("Calcula o alias no SELECT", func(t *testing.T) {
+		var sqls []string
+		server.OnQueryBuilt("Products", func(args EventArgs) error {
+			sqls = append(sqls, args.(*QueryBuiltArgs).SQL)
+			return nil
+		})
+
+		status, body := getComputeTestProducts(t, server, compute)
+		require.Equal(t, http.StatusOK, status, body)
+		require.NotEmpty(t, sqls)
+		assert.Contains(t, sqls[0], "AS `Total`")
+
+		values := body["value"].([]any)
+		require.Len(t, values, 3)
+		assert.EqualValues(t, 20, values[0].(map[string]any)["Total"])
+	})
+
+	t.Run("Alias no $filter e no $count", func(t *testing.T) {
+		status, body := getComputeTestProducts(t, server, compute+"&$count=true&$filter="+url.QueryEscape("Total gt 50"))
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Len(t, body["value"], 2)
+		assert.EqualValues(t, 2, body["@odata.count"])
+	})
+
+	t.Run("Alias no $orderby", func(t *testing.T) {
+		status, body := getComputeTestProducts(t, server, compute+"&$orderby="+url.QueryEscape("Total desc"))
+		require.Equal(t, http.StatusOK, status, body)
+
+		values := body["value"].([]any)
+		require.Len(t, values, 3)
+		assert.Equal(t, "Monitor", values[0].(map[string]any)["name"])
+		assert.Equal(t, "Mouse", values[2].(map[string]any)["name"])
+	})
+
+	t.Run("Expressão inválida retorna 400", func(t *testing.T) {
+		status, body := getComputeTestProducts(t, server, "$compute="+url.QueryEscape("price mul 2"))
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "InvalidCompute", body["error"].(map[string]any)["code"])
+	})
+}
+
+func TestInlineComputeAliases(t *testing.T) {
+	computeOption, err := NewComputeParser().ParseCompute(context.Background(), "price mul 2 as Total")
+	require.NoError(t, err)
+
+	filter, err := ParseFilterString(context.Background(), "Total gt 50 and price lt 100")
+	require.NoError(t, err)
+
+	metadata := EntityMetadata{Name: "Products", Properties: []PropertyMetadata{{Name: "price", Type: "float64"}}}
+	inlined := inlineComputeAliases(filter.Tree, computeOption, metadata)
+
+	comparison := inlined.Children[0]
+	assert.Equal(t, "mul", comparison.Children[0].Token.Value)
+	assert.Same(t, comparison, comparison.Children[0].Parent)
+	assert.Equal(t, "Total", filter.Tree.Children[0].Children[0].Token.Value, "a árvore original não é alterada")
+
+	sql, _, err := NewQueryBuilder("default").BuildWhereClause(context.Background(), inlined, metadata)
+	require.NoError(t, err)
+	assert.NotContains(t, sql, "Total")
+}
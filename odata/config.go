@@ -3,6 +3,7 @@ package odata
 import (
 	"bufio"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -68,37 +69,93 @@ func ClearProviderCache() {
 // EnvConfig representa as configurações carregadas do arquivo .env
 type EnvConfig struct {
 	// Configurações do banco de dados
-	DBDriver           string
-	DBHost             string
-	DBPort             string
-	DBName             string
-	DBUser             string
-	DBPassword         string
-	DBSchema           string
-	DBConnectionString string
-	DBMaxOpenConns     int
-	DBMaxIdleConns     int
-	DBConnMaxLifetime  time.Duration
-	DBConnMaxIdleTime  time.Duration
-	DBLogSQL           bool // Habilita/desabilita logs de queries SQL
-	LogPayloads        bool // Habilita/desabilita logs de payloads de request/response
+	DBDriver             string
+	DBHost               string
+	DBPort               string
+	DBName               string
+	DBUser               string
+	DBPassword           string
+	DBSchema             string
+	DBConnectionString   string
+	DBMaxOpenConns       int
+	DBMaxIdleConns       int
+	DBConnMaxLifetime    time.Duration
+	DBConnMaxIdleTime    time.Duration
+	DBLogSQL             bool          // Habilita/desabilita logs de queries SQL
+	DBQueryTimeout       time.Duration // Timeout das queries por requisição (0 = sem timeout)
+	DBCaseInsensitive    bool          // Comparações de strings no $filter sem diferenciar maiúsculas/minúsculas
+	DBDecimalAsString    bool          // Serializa valores Edm.Decimal como strings JSON
+	DBTimeZone           string        // Fuso horário IANA dos literais de data/hora sem offset (padrão: UTC)
+	DBSlowQueryEnabled   bool          // Loga queries que excedem DBSlowQueryThreshold
+	DBSlowQueryThreshold time.Duration // Duração a partir da qual a query é considerada lenta
+	DBSlowQueryExplain   bool          // Captura o EXPLAIN das queries lentas (PostgreSQL/MySQL)
+	DBAutoMigrate        bool          // Cria/altera tabelas conforme as entidades registradas
+	DBAutoMigrateDry     bool          // Apenas loga o DDL da auto-migração
+	LogPayloads          bool          // Habilita/desabilita logs de payloads de request/response
 
 	// Configurações do servidor OData
-	ServerHost              string
-	ServerPort              int
-	ServerRoutePrefix       string
-	ServerEnableCORS        bool
-	ServerAllowedOrigins    []string
-	ServerAllowedMethods    []string
-	ServerAllowedHeaders    []string
-	ServerExposedHeaders    []string
-	ServerAllowCredentials  bool
-	ServerEnableLogging     bool
-	ServerLogLevel          string
-	ServerLogFile           string
-	ServerEnableCompression bool
-	ServerMaxRequestSize    int64
-	ServerShutdownTimeout   time.Duration
+	ServerHost                  string
+	ServerPort                  int
+	ServerRoutePrefix           string
+	ServerExternalBaseURL       string // URL pública do serviço (links absolutos atrás de proxy)
+	ServerEnableCORS            bool
+	ServerAllowedOrigins        []string
+	ServerAllowedMethods        []string
+	ServerAllowedHeaders        []string
+	ServerExposedHeaders        []string
+	ServerAllowCredentials      bool
+	ServerEnableLogging         bool
+	ServerLogLevel              string
+	ServerLogFile               string
+	ServerLogMaxSize            int64         // Tamanho máximo do arquivo de log antes de rotacionar (bytes)
+	ServerLogMaxAge             time.Duration // Idade máxima do arquivo de log antes de rotacionar
+	ServerLogMaxBackups         int           // Arquivos de log rotacionados mantidos
+	ServerLogCompress           bool          // Comprime os arquivos de log rotacionados
+	ServerEnableCompression     bool
+	ServerCompressionBrotli     bool
+	ServerCompressionMinSize    int
+	ServerMaxRequestSize        int64
+	ServerDisableMethodOverride bool
+	ServerMaxExpandFanOut       int   // Máximo de entidades por coleção expandida (0 = sem limite)
+	ServerMaxExpandParallelism  int   // Máximo de navegações de um $expand buscadas em paralelo
+	ServerMaxRowsPerRequest     int   // Máximo de registros por resposta de coleção (0 = sem limite)
+	ServerMaxBulkItems          int   // Máximo de entidades de um POST em lote na coleção
+	ServerFederatedExpandBatch  int   // Chaves por consulta do $expand de outro provider
+	ServerMaxFederatedKeys      int   // Máximo de chaves distintas do $expand de outro provider
+	ServerSnowflakeNodeID       int64 // Nó do gerador de IDs snowflake (0 a 1023)
+	ServerShutdownTimeout       time.Duration
+	ServerReusePort             bool
+	ServerEnableHandoff         bool
+	ServerDebugErrors           bool
+	ServerIEEE754Compatible     bool // Serializa Edm.Int64 e Edm.Decimal como strings JSON
+	ServerFastJSONEncoding      bool // Serializa as respostas de entidades com encoders pré-compilados
+	ServerNamingPolicy          string
+	ServerJSONNumberMode        string
+	ServerRecoverEnabled        bool
+	ServerRecoverStackTrace     bool
+
+	// Configurações de auditoria HTTP
+	HTTPAuditEnabled      bool
+	HTTPAuditRequestBody  bool
+	HTTPAuditResponseBody bool
+	HTTPAuditMaxBodySize  int
+	HTTPAuditRedactFields []string
+	HTTPAuditSkipPaths    []string
+
+	// Configurações de diagnóstico
+	DiagnosticsEnabled    bool
+	DiagnosticsPprof      bool
+	DiagnosticsQueryDebug bool
+	DiagnosticsAdminRole  string
+
+	// Configurações do modo de manutenção
+	MaintenanceEnabled      bool
+	MaintenanceMode         string // writes ou all
+	MaintenanceMessage      string
+	MaintenanceAllowedRoles []string
+	MaintenanceAllowedIPs   []string // IPs ou redes CIDR
+	MaintenanceSignal       bool     // SIGUSR1 alterna o modo de manutenção (Unix)
+	MaintenanceRetryAfter   time.Duration
 
 	// Configurações TLS
 	ServerTLSCertFile string
@@ -113,10 +170,27 @@ type EnvConfig struct {
 	JWTEnabled     bool
 	JWTRequireAuth bool
 
+	// Rotação de chaves JWT
+	JWTKeyID                string // kid da chave atual (JWT_SECRET_KEY)
+	JWTPreviousSecretKey    string // Chave anterior, aceita na validação durante a rotação
+	JWTPreviousKeyID        string
+	JWTPreviousKeyExpiresAt string // Fim da aceitação da chave anterior (RFC3339)
+	JWTPrivateKeyFile       string // Chave privada RSA/ECDSA em PEM (RS256/ES256)
+
+	// Configurações de criptografia de propriedades
+	EncryptionKey   string // Chave AES em base64 (16, 24 ou 32 bytes)
+	EncryptionKeyID string
+
 	// Configurações do serviço
-	ServiceName        string
-	ServiceDisplayName string
-	ServiceDescription string
+	ServiceName             string
+	ServiceDisplayName      string
+	ServiceDescription      string
+	ServiceInstance         string
+	ServiceStartType        string
+	ServiceRestartOnFailure bool
+	ServiceRestartDelay     time.Duration
+	ServiceUser             string
+	ServicePassword         string
 
 	// Configurações de Rate Limit
 	RateLimitEnabled           bool
@@ -128,6 +202,25 @@ type EnvConfig struct {
 	// Configurações de PATCH OData 4.01
 	PatchRemovedFormat string // Formato aceito para @odata.removed: "both", "empty", "with_reason" (default: "both")
 
+	// Configurações de Batch
+	BatchMaxOperations int
+	BatchMaxChangesets int
+	BatchTimeout       time.Duration
+	BatchFlushInterval time.Duration
+
+	// Configurações de armazenamento de arquivos (exportações, relatórios, mídias)
+	StorageDriver          string // local, s3, gcs ou azure (vazio: sem armazenamento configurado)
+	StorageBucket          string // Bucket (S3/GCS) ou container (Azure)
+	StoragePrefix          string // Prefixo das chaves
+	StorageEndpoint        string // Endpoint alternativo (ex: MinIO, Azurite)
+	StorageLocalDir        string // Diretório do driver local
+	StorageRegion          string // Região do S3 (padrão: AWS_REGION)
+	StorageAccessKey       string // Access key id do S3 (padrão: AWS_ACCESS_KEY_ID)
+	StorageSecretKey       string // Secret access key do S3 (padrão: AWS_SECRET_ACCESS_KEY)
+	StorageCredentialsFile string // JSON da conta de serviço do GCS (padrão: GOOGLE_APPLICATION_CREDENTIALS)
+	StorageAccountName     string // Conta do Azure Storage (padrão: AZURE_STORAGE_ACCOUNT)
+	StorageAccountKey      string // Chave da conta do Azure Storage (padrão: AZURE_STORAGE_KEY)
+
 	// Mapa de todas as variáveis para acesso direto
 	Variables map[string]string
 }
@@ -146,6 +239,26 @@ func LoadEnvConfig() (*EnvConfig, error) {
 		return nil, fmt.Errorf("erro ao carregar arquivo .env: %w", err)
 	}
 
+	// Instância do serviço (definida pelo gerenciador de serviços ou no .env): as variáveis
+	// do .env.<instância>, se existir, sobrescrevem as do .env
+	instance := os.Getenv(ServiceInstanceEnv)
+	if instance == "" {
+		instance = variables[ServiceInstanceEnv]
+	}
+	if instance != "" {
+		instancePath := envPath + "." + instance
+		if _, err := os.Stat(instancePath); err == nil {
+			instanceVariables, err := loadEnvFile(instancePath)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao carregar arquivo %s: %w", filepath.Base(instancePath), err)
+			}
+			for key, value := range instanceVariables {
+				variables[key] = value
+			}
+		}
+		variables[ServiceInstanceEnv] = instance
+	}
+
 	// ✅ INJETAR TODAS as variáveis no ambiente global
 	// Isso permite que os.Getenv() funcione para todas as variáveis
 	// APENAS se a variável NÃO existir já no ambiente (não sobrescrever variáveis do sistema)
@@ -155,6 +268,9 @@ func LoadEnvConfig() (*EnvConfig, error) {
 		}
 	}
 
+	// Fontes externas (Vault, AWS, ambiente) têm precedência sobre o .env
+	applyConfigSources(variables)
+
 	// Cria a configuração com valores padrão
 	config := &EnvConfig{
 		Variables: variables,
@@ -254,25 +370,84 @@ func (c *EnvConfig) parseVariables() {
 	c.DBMaxIdleConns = c.getEnvInt("DB_MAX_IDLE_CONNS", DefaultMinConnections)
 	c.DBConnMaxLifetime = c.getEnvDuration("DB_CONN_MAX_LIFETIME", DefaultMaxIdleTime)
 	c.DBConnMaxIdleTime = c.getEnvDuration("DB_CONN_MAX_IDLE_TIME", DefaultMaxIdleTime)
+	c.DBQueryTimeout = c.getEnvDuration("DB_QUERY_TIMEOUT", 0)
+	c.DBCaseInsensitive = c.getEnvBool("DB_CASE_INSENSITIVE", false)
+	c.DBDecimalAsString = c.getEnvBool("DB_DECIMAL_AS_STRING", false)
+	c.DBTimeZone = c.getEnvString("DB_TIMEZONE", "UTC")
+	c.DBSlowQueryEnabled = c.getEnvBool("DB_SLOW_QUERY_ENABLED", false)
+	c.DBSlowQueryThreshold = c.getEnvDuration("DB_SLOW_QUERY_THRESHOLD", 1*time.Second)
+	c.DBSlowQueryExplain = c.getEnvBool("DB_SLOW_QUERY_EXPLAIN", false)
 	c.DBLogSQL = c.getEnvBool("DB_LOG_SQL", false)      // Padrão: desabilitado
 	c.LogPayloads = c.getEnvBool("LOG_PAYLOADS", false) // Padrão: desabilitado
+	c.DBAutoMigrate = c.getEnvBool("DB_AUTO_MIGRATE", false)
+	c.DBAutoMigrateDry = c.getEnvBool("DB_AUTO_MIGRATE_DRY_RUN", false)
 
 	// Configurações do servidor OData
 	c.ServerHost = c.getEnvString("SERVER_HOST", "localhost")
 	c.ServerPort = c.getEnvInt("SERVER_PORT", 8080)
 	c.ServerRoutePrefix = c.getEnvString("SERVER_ROUTE_PREFIX", "/odata")
+	c.ServerExternalBaseURL = c.getEnvString("SERVER_EXTERNAL_BASE_URL", "")
 	c.ServerEnableCORS = c.getEnvBool("SERVER_ENABLE_CORS", true)
 	c.ServerAllowedOrigins = c.getEnvStringSlice("SERVER_ALLOWED_ORIGINS", []string{"*"})
 	c.ServerAllowedMethods = c.getEnvStringSlice("SERVER_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
 	c.ServerAllowedHeaders = c.getEnvStringSlice("SERVER_ALLOWED_HEADERS", []string{"*"})
-	c.ServerExposedHeaders = c.getEnvStringSlice("SERVER_EXPOSED_HEADERS", []string{"OData-Version", "Content-Type"})
+	c.ServerExposedHeaders = c.getEnvStringSlice("SERVER_EXPOSED_HEADERS", []string{"OData-Version", "Content-Type", "Location", "OData-EntityId"})
 	c.ServerAllowCredentials = c.getEnvBool("SERVER_ALLOW_CREDENTIALS", false)
 	c.ServerEnableLogging = c.getEnvBool("SERVER_ENABLE_LOGGING", true)
 	c.ServerLogLevel = c.getEnvString("SERVER_LOG_LEVEL", "INFO")
 	c.ServerLogFile = c.getEnvString("SERVER_LOG_FILE", "")
+	logRotationDefaults := DefaultLogRotationConfig()
+	c.ServerLogMaxSize = c.getEnvInt64("SERVER_LOG_MAX_SIZE", logRotationDefaults.MaxSize)
+	c.ServerLogMaxAge = c.getEnvDuration("SERVER_LOG_MAX_AGE", logRotationDefaults.MaxAge)
+	c.ServerLogMaxBackups = c.getEnvInt("SERVER_LOG_MAX_BACKUPS", logRotationDefaults.MaxBackups)
+	c.ServerLogCompress = c.getEnvBool("SERVER_LOG_COMPRESS", logRotationDefaults.Compress)
 	c.ServerEnableCompression = c.getEnvBool("SERVER_ENABLE_COMPRESSION", false)
+	compressionDefaults := DefaultCompressionConfig()
+	c.ServerCompressionBrotli = c.getEnvBool("SERVER_COMPRESSION_BROTLI", compressionDefaults.Brotli)
+	c.ServerCompressionMinSize = c.getEnvInt("SERVER_COMPRESSION_MIN_SIZE", compressionDefaults.MinSize)
 	c.ServerMaxRequestSize = c.getEnvInt64("SERVER_MAX_REQUEST_SIZE", 10*1024*1024)
+	c.ServerDisableMethodOverride = c.getEnvBool("SERVER_DISABLE_METHOD_OVERRIDE", false)
+	c.ServerMaxExpandFanOut = c.getEnvInt("SERVER_MAX_EXPAND_FANOUT", 0)
+	c.ServerMaxExpandParallelism = c.getEnvInt("SERVER_MAX_EXPAND_PARALLELISM", DefaultMaxExpandParallelism)
+	c.ServerMaxRowsPerRequest = c.getEnvInt("SERVER_MAX_ROWS_PER_REQUEST", 0)
+	c.ServerMaxBulkItems = c.getEnvInt("SERVER_MAX_BULK_ITEMS", DefaultMaxBulkItems)
+	c.ServerFederatedExpandBatch = c.getEnvInt("SERVER_FEDERATED_EXPAND_BATCH_SIZE", DefaultFederatedExpandBatchSize)
+	c.ServerMaxFederatedKeys = c.getEnvInt("SERVER_MAX_FEDERATED_EXPAND_KEYS", DefaultMaxFederatedExpandKeys)
+	c.ServerSnowflakeNodeID = c.getEnvInt64("SERVER_SNOWFLAKE_NODE_ID", 0)
 	c.ServerShutdownTimeout = c.getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second)
+	c.ServerReusePort = c.getEnvBool("SERVER_REUSE_PORT", false)
+	c.ServerEnableHandoff = c.getEnvBool("SERVER_ENABLE_HANDOFF", false)
+	c.ServerDebugErrors = c.getEnvBool("SERVER_DEBUG_ERRORS", false)
+	c.ServerIEEE754Compatible = c.getEnvBool("SERVER_IEEE754_COMPATIBLE", false)
+	c.ServerFastJSONEncoding = c.getEnvBool("SERVER_FAST_JSON_ENCODING", false)
+	c.ServerNamingPolicy = c.getEnvString("SERVER_NAMING_POLICY", "json") // json, field, camelCase, PascalCase, snake_case
+	c.ServerJSONNumberMode = c.getEnvString("SERVER_JSON_NUMBER_MODE", "typed")
+	c.ServerRecoverEnabled = c.getEnvBool("SERVER_RECOVER_ENABLED", true)
+	c.ServerRecoverStackTrace = c.getEnvBool("SERVER_RECOVER_STACK_TRACE", true)
+
+	// Configurações de auditoria HTTP
+	c.HTTPAuditEnabled = c.getEnvBool("HTTP_AUDIT_ENABLED", false)
+	c.HTTPAuditRequestBody = c.getEnvBool("HTTP_AUDIT_REQUEST_BODY", false)
+	c.HTTPAuditResponseBody = c.getEnvBool("HTTP_AUDIT_RESPONSE_BODY", false)
+	c.HTTPAuditMaxBodySize = c.getEnvInt("HTTP_AUDIT_MAX_BODY_SIZE", 4096)
+	c.HTTPAuditRedactFields = c.getEnvStringSlice("HTTP_AUDIT_REDACT_FIELDS", DefaultHTTPAuditRedactFields)
+	c.HTTPAuditSkipPaths = c.getEnvStringSlice("HTTP_AUDIT_SKIP_PATHS", nil)
+
+	// Configurações de diagnóstico
+	c.DiagnosticsEnabled = c.getEnvBool("DIAGNOSTICS_ENABLED", false)
+	c.DiagnosticsPprof = c.getEnvBool("DIAGNOSTICS_PPROF", false)
+	c.DiagnosticsQueryDebug = c.getEnvBool("DIAGNOSTICS_QUERY_DEBUG", false)
+	c.DiagnosticsAdminRole = c.getEnvString("DIAGNOSTICS_ADMIN_ROLE", "admin")
+
+	// Configurações do modo de manutenção
+	maintenanceDefaults := DefaultMaintenanceConfig()
+	c.MaintenanceEnabled = c.getEnvBool("MAINTENANCE_ENABLED", false)
+	c.MaintenanceMode = c.getEnvString("MAINTENANCE_MODE", maintenanceDefaults.Mode)
+	c.MaintenanceMessage = c.getEnvString("MAINTENANCE_MESSAGE", maintenanceDefaults.Message)
+	c.MaintenanceAllowedRoles = c.getEnvStringSlice("MAINTENANCE_ALLOWED_ROLES", nil)
+	c.MaintenanceAllowedIPs = c.getEnvStringSlice("MAINTENANCE_ALLOWED_IPS", nil)
+	c.MaintenanceSignal = c.getEnvBool("MAINTENANCE_SIGNAL", false)
+	c.MaintenanceRetryAfter = c.getEnvDuration("MAINTENANCE_RETRY_AFTER", time.Minute)
 
 	// Configurações TLS
 	c.ServerTLSCertFile = c.getEnvString("SERVER_TLS_CERT_FILE", "")
@@ -286,11 +461,27 @@ func (c *EnvConfig) parseVariables() {
 	c.JWTAlgorithm = c.getEnvString("JWT_ALGORITHM", "HS256")
 	c.JWTEnabled = c.getEnvBool("JWT_ENABLED", false)
 	c.JWTRequireAuth = c.getEnvBool("JWT_REQUIRE_AUTH", false)
+	c.JWTKeyID = c.getEnvString("JWT_KEY_ID", "")
+	c.JWTPreviousSecretKey = c.getEnvString("JWT_PREVIOUS_SECRET_KEY", "")
+	c.JWTPreviousKeyID = c.getEnvString("JWT_PREVIOUS_KEY_ID", "")
+	c.JWTPreviousKeyExpiresAt = c.getEnvString("JWT_PREVIOUS_KEY_EXPIRES_AT", "")
+	c.JWTPrivateKeyFile = c.getEnvString("JWT_PRIVATE_KEY_FILE", "")
+
+	// Configurações de criptografia de propriedades
+	c.EncryptionKey = c.getEnvString("ENCRYPTION_KEY", "")
+	c.EncryptionKeyID = c.getEnvString("ENCRYPTION_KEY_ID", "default")
 
 	// Configurações do serviço
 	c.ServiceName = c.getEnvString("SERVICE_NAME", "godata-service")
 	c.ServiceDisplayName = c.getEnvString("SERVICE_DISPLAY_NAME", "GoData OData Service")
 	c.ServiceDescription = c.getEnvString("SERVICE_DESCRIPTION", "Serviço GoData OData v4 para APIs RESTful")
+	serviceDefaults := DefaultServiceConfig()
+	c.ServiceInstance = c.getEnvString(ServiceInstanceEnv, "")
+	c.ServiceStartType = c.getEnvString("SERVICE_START_TYPE", serviceDefaults.StartType)
+	c.ServiceRestartOnFailure = c.getEnvBool("SERVICE_RESTART_ON_FAILURE", serviceDefaults.RestartOnFailure)
+	c.ServiceRestartDelay = c.getEnvDuration("SERVICE_RESTART_DELAY", serviceDefaults.RestartDelay)
+	c.ServiceUser = c.getEnvString("SERVICE_USER", "")
+	c.ServicePassword = c.getEnvString("SERVICE_PASSWORD", "")
 
 	// Configurações de Rate Limit
 	c.RateLimitEnabled = c.getEnvBool("RATE_LIMIT_ENABLED", false)
@@ -301,6 +492,26 @@ func (c *EnvConfig) parseVariables() {
 
 	// Configurações de PATCH OData 4.01
 	c.PatchRemovedFormat = c.getEnvString("PATCH_REMOVED_FORMAT", "both") // both, empty, with_reason
+
+	// Configurações de Batch
+	batchDefaults := DefaultBatchConfig()
+	c.BatchMaxOperations = c.getEnvInt("BATCH_MAX_OPERATIONS", batchDefaults.MaxOperations)
+	c.BatchMaxChangesets = c.getEnvInt("BATCH_MAX_CHANGESETS", batchDefaults.MaxChangesets)
+	c.BatchTimeout = c.getEnvDuration("BATCH_TIMEOUT", batchDefaults.Timeout)
+	c.BatchFlushInterval = c.getEnvDuration("BATCH_FLUSH_INTERVAL", batchDefaults.FlushInterval)
+
+	// Configurações de armazenamento de arquivos
+	c.StorageDriver = c.getEnvString("STORAGE_DRIVER", "")
+	c.StorageBucket = c.getEnvString("STORAGE_BUCKET", "")
+	c.StoragePrefix = c.getEnvString("STORAGE_PREFIX", "")
+	c.StorageEndpoint = c.getEnvString("STORAGE_ENDPOINT", "")
+	c.StorageLocalDir = c.getEnvString("STORAGE_LOCAL_DIR", "storage")
+	c.StorageRegion = c.getEnvString("STORAGE_REGION", "")
+	c.StorageAccessKey = c.getEnvString("STORAGE_ACCESS_KEY", "")
+	c.StorageSecretKey = c.getEnvString("STORAGE_SECRET_KEY", "")
+	c.StorageCredentialsFile = c.getEnvString("STORAGE_CREDENTIALS_FILE", "")
+	c.StorageAccountName = c.getEnvString("STORAGE_ACCOUNT_NAME", "")
+	c.StorageAccountKey = c.getEnvString("STORAGE_ACCOUNT_KEY", "")
 }
 
 // getEnvString retorna uma string do ambiente ou valor padrão
@@ -389,38 +600,94 @@ func (c *EnvConfig) ToServerConfig() *ServerConfig {
 		Name:        c.ServiceName,
 		DisplayName: c.ServiceDisplayName,
 		Description: c.ServiceDescription,
+		ServiceConfig: &ServiceConfig{
+			Instance:         c.ServiceInstance,
+			StartType:        c.ServiceStartType,
+			RestartOnFailure: c.ServiceRestartOnFailure,
+			RestartDelay:     c.ServiceRestartDelay,
+			UserName:         c.ServiceUser,
+			Password:         c.ServicePassword,
+		},
 
 		// Configurações do servidor
-		Host:              c.ServerHost,
-		Port:              c.ServerPort,
-		RoutePrefix:       c.ServerRoutePrefix,
-		EnableCORS:        c.ServerEnableCORS,
-		AllowedOrigins:    c.ServerAllowedOrigins,
-		AllowedMethods:    c.ServerAllowedMethods,
-		AllowedHeaders:    c.ServerAllowedHeaders,
-		ExposedHeaders:    c.ServerExposedHeaders,
-		AllowCredentials:  c.ServerAllowCredentials,
-		EnableLogging:     c.ServerEnableLogging,
-		LogLevel:          c.ServerLogLevel,
-		LogFile:           c.ServerLogFile,
-		EnableCompression: c.ServerEnableCompression,
-		MaxRequestSize:    c.ServerMaxRequestSize,
-		ShutdownTimeout:   c.ServerShutdownTimeout,
-		CertFile:          c.ServerTLSCertFile,
-		CertKeyFile:       c.ServerTLSKeyFile,
-		EnableJWT:         c.JWTEnabled,
-		RequireAuth:       c.JWTRequireAuth,
-		DBLogSQL:          c.DBLogSQL, // Copia configuração de log SQL do .env
+		Host:                  c.ServerHost,
+		Port:                  c.ServerPort,
+		RoutePrefix:           c.ServerRoutePrefix,
+		ExternalBaseURL:       c.ServerExternalBaseURL,
+		EnableCORS:            c.ServerEnableCORS,
+		AllowedOrigins:        c.ServerAllowedOrigins,
+		AllowedMethods:        c.ServerAllowedMethods,
+		AllowedHeaders:        c.ServerAllowedHeaders,
+		ExposedHeaders:        c.ServerExposedHeaders,
+		AllowCredentials:      c.ServerAllowCredentials,
+		EnableLogging:         c.ServerEnableLogging,
+		LogLevel:              c.ServerLogLevel,
+		LogFile:               c.ServerLogFile,
+		EnableCompression:     c.ServerEnableCompression,
+		MaxRequestSize:        c.ServerMaxRequestSize,
+		DisableMethodOverride: c.ServerDisableMethodOverride,
+		MaxExpandFanOut:       c.ServerMaxExpandFanOut,
+		MaxExpandParallelism:  c.ServerMaxExpandParallelism,
+		SnowflakeNodeID:       c.ServerSnowflakeNodeID,
+		ShutdownTimeout:       c.ServerShutdownTimeout,
+		ReusePort:             c.ServerReusePort,
+		EnableHandoff:         c.ServerEnableHandoff,
+		DebugErrors:           c.ServerDebugErrors,
+		IEEE754Compatible:     c.ServerIEEE754Compatible,
+		FastJSONEncoding:      c.ServerFastJSONEncoding,
+		NamingPolicy:          parseNamingPolicy(c.ServerNamingPolicy),
+
+		FederatedExpandBatchSize: c.ServerFederatedExpandBatch,
+		MaxFederatedExpandKeys:   c.ServerMaxFederatedKeys,
+		MaxRowsPerRequest:        c.ServerMaxRowsPerRequest,
+		MaxBulkItems:             c.ServerMaxBulkItems,
+		JSONNumberMode:           parseJSONNumberMode(c.ServerJSONNumberMode),
+
+		RecoverConfig: &RecoverConfig{
+			Enabled:       c.ServerRecoverEnabled,
+			LogStackTrace: c.ServerRecoverStackTrace,
+		},
+		LogRotation: &LogRotationConfig{
+			MaxSize:    c.ServerLogMaxSize,
+			MaxAge:     c.ServerLogMaxAge,
+			MaxBackups: c.ServerLogMaxBackups,
+			Compress:   c.ServerLogCompress,
+		},
+		CompressionConfig: &CompressionConfig{
+			Brotli:               c.ServerCompressionBrotli,
+			MinSize:              c.ServerCompressionMinSize,
+			ExcludedContentTypes: DefaultCompressionConfig().ExcludedContentTypes,
+		},
+		CertFile:        c.ServerTLSCertFile,
+		CertKeyFile:     c.ServerTLSKeyFile,
+		EnableJWT:       c.JWTEnabled,
+		RequireAuth:     c.JWTRequireAuth,
+		DBLogSQL:        c.DBLogSQL, // Copia configuração de log SQL do .env
+		QueryTimeout:    c.DBQueryTimeout,
+		CaseInsensitive: c.DBCaseInsensitive,
+		DecimalAsString: c.DBDecimalAsString,
+		TimeZone:        loadTimeZone(c.DBTimeZone),
+		SlowQueryConfig: &SlowQueryConfig{
+			Enabled:        c.DBSlowQueryEnabled,
+			Threshold:      c.DBSlowQueryThreshold,
+			CaptureExplain: c.DBSlowQueryExplain,
+			ExplainTimeout: 5 * time.Second,
+		},
+		AutoMigrate:           c.DBAutoMigrate,
+		AutoMigrateDryRun:     c.DBAutoMigrateDry,
+		EncryptionKeyProvider: loadEncryptionKeyProvider(c.EncryptionKeyID, c.EncryptionKey),
+		Storage:               loadStorage(c),
 	}
 
 	// Configura JWT se habilitado
-	if c.JWTEnabled && c.JWTSecretKey != "" {
-		config.JWTConfig = &JWTConfig{
-			SecretKey: c.JWTSecretKey,
-			Issuer:    c.JWTIssuer,
-			ExpiresIn: c.JWTExpiresIn,
-			RefreshIn: c.JWTRefreshIn,
-			Algorithm: c.JWTAlgorithm,
+	if c.JWTEnabled && (c.JWTSecretKey != "" || c.JWTPrivateKeyFile != "") {
+		jwtConfig, err := jwtConfigFromEnv(c)
+		if err != nil {
+			// Sem a chave configurada o JWT não é habilitado (nenhum token é emitido ou aceito)
+			log.Printf("❌ JWT desabilitado: %v", err)
+			config.EnableJWT = false
+		} else {
+			config.JWTConfig = jwtConfig
 		}
 	}
 
@@ -439,6 +706,45 @@ func (c *EnvConfig) ToServerConfig() *ServerConfig {
 	// Configurações de PATCH OData 4.01
 	config.PatchRemovedFormat = c.PatchRemovedFormat
 
+	// Configurações de auditoria HTTP
+	config.HTTPAuditConfig = &HTTPAuditConfig{
+		Enabled:             c.HTTPAuditEnabled,
+		CaptureRequestBody:  c.HTTPAuditRequestBody,
+		CaptureResponseBody: c.HTTPAuditResponseBody,
+		MaxBodySize:         c.HTTPAuditMaxBodySize,
+		RedactFields:        c.HTTPAuditRedactFields,
+		SkipPaths:           c.HTTPAuditSkipPaths,
+	}
+
+	// Configurações de diagnóstico
+	config.DiagnosticsConfig = &DiagnosticsConfig{
+		Enabled:     c.DiagnosticsEnabled,
+		EnablePprof: c.DiagnosticsPprof,
+		QueryDebug:  c.DiagnosticsQueryDebug,
+		AdminPrefix: "/admin",
+		AdminRole:   c.DiagnosticsAdminRole,
+	}
+
+	// Configurações do modo de manutenção
+	config.Maintenance = &MaintenanceConfig{
+		Enabled:      c.MaintenanceEnabled,
+		Mode:         c.MaintenanceMode,
+		Message:      c.MaintenanceMessage,
+		AllowedRoles: c.MaintenanceAllowedRoles,
+		AllowedIPs:   c.MaintenanceAllowedIPs,
+		Signal:       c.MaintenanceSignal,
+	}
+	config.MaintenanceRetryAfter = c.MaintenanceRetryAfter
+
+	// Configurações de Batch
+	config.BatchConfig = &BatchConfig{
+		MaxOperations:      c.BatchMaxOperations,
+		MaxChangesets:      c.BatchMaxChangesets,
+		Timeout:            c.BatchTimeout,
+		EnableTransactions: true,
+		FlushInterval:      c.BatchFlushInterval,
+	}
+
 	return config
 }
 
@@ -450,6 +756,7 @@ func LoadEnvOrDefault() (*EnvConfig, error) {
 		config = &EnvConfig{
 			Variables: make(map[string]string),
 		}
+		applyConfigSources(config.Variables)
 		config.parseVariables()
 	}
 	return config, nil
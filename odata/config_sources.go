@@ -0,0 +1,279 @@
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =======================================================================================
+// FONTES DE CONFIGURAÇÃO E SEGREDOS (ENV, VAULT, AWS)
+// =======================================================================================
+
+// ConfigSource fornece variáveis de configuração (ex: DB_PASSWORD, JWT_SECRET_KEY) de uma
+// fonte externa ao .env. As chaves retornadas usam os mesmos nomes das variáveis do .env
+type ConfigSource interface {
+	// Name identifica a fonte nos logs e erros
+	Name() string
+	// Load retorna as variáveis da fonte
+	Load(ctx context.Context) (map[string]string, error)
+}
+
+var (
+	configSourcesMu     sync.RWMutex
+	configSources       []ConfigSource
+	configSourceValues  map[string]string
+	configSourceTimeout = 30 * time.Second
+)
+
+// UseConfigSources registra fontes consultadas por LoadEnvConfig e LoadEnvOrDefault depois
+// do .env. As fontes são carregadas imediatamente, na ordem informada (as últimas têm
+// precedência, inclusive sobre o .env), e os valores ficam em cache até ReloadConfigSources.
+// Deve ser chamada antes de criar o servidor e os providers
+func UseConfigSources(ctx context.Context, sources ...ConfigSource) error {
+	values, err := loadConfigSources(ctx, sources)
+	if err != nil {
+		return err
+	}
+
+	configSourcesMu.Lock()
+	defer configSourcesMu.Unlock()
+	configSources = sources
+	configSourceValues = values
+	return nil
+}
+
+// ReloadConfigSources recarrega as fontes registradas (ex: após a rotação de um segredo).
+// Em caso de erro os valores anteriores são mantidos. Configurações já aplicadas ao servidor
+// e aos providers não são alteradas
+func ReloadConfigSources(ctx context.Context) error {
+	configSourcesMu.RLock()
+	sources := configSources
+	configSourcesMu.RUnlock()
+
+	values, err := loadConfigSources(ctx, sources)
+	if err != nil {
+		return err
+	}
+
+	configSourcesMu.Lock()
+	defer configSourcesMu.Unlock()
+	configSourceValues = values
+	return nil
+}
+
+// loadConfigSources carrega e combina as variáveis das fontes
+func loadConfigSources(ctx context.Context, sources []ConfigSource) (map[string]string, error) {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, configSourceTimeout)
+		defer cancel()
+	}
+
+	values := make(map[string]string)
+	for _, source := range sources {
+		loaded, err := source.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config source %s: %w", source.Name(), err)
+		}
+		for key, value := range loaded {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// applyConfigSources sobrescreve as variáveis com os valores das fontes registradas
+func applyConfigSources(variables map[string]string) {
+	configSourcesMu.RLock()
+	defer configSourcesMu.RUnlock()
+	for key, value := range configSourceValues {
+		variables[key] = value
+	}
+}
+
+// =======================================================================================
+// VARIÁVEIS DE AMBIENTE
+// =======================================================================================
+
+// EnvConfigSource lê as variáveis do ambiente do processo (ex: injetadas pelo orquestrador)
+type EnvConfigSource struct {
+	// Prefixo das variáveis, removido das chaves (ex: "MYAPP_" lê MYAPP_DB_PASSWORD como
+	// DB_PASSWORD). Vazio lê todas as variáveis
+	Prefix string
+	// Restringe as chaves carregadas (após remover o prefixo). Vazio carrega todas
+	Keys []string
+}
+
+// NewEnvConfigSource cria uma fonte de variáveis de ambiente com o prefixo informado
+func NewEnvConfigSource(prefix string, keys ...string) *EnvConfigSource {
+	return &EnvConfigSource{Prefix: prefix, Keys: keys}
+}
+
+// Name implementa ConfigSource
+func (s *EnvConfigSource) Name() string {
+	return "env"
+}
+
+// Load implementa ConfigSource
+func (s *EnvConfigSource) Load(ctx context.Context) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(key, s.Prefix) {
+			continue
+		}
+		key = strings.TrimPrefix(key, s.Prefix)
+		if key == "" || (len(s.Keys) > 0 && !containsString(s.Keys, key)) {
+			continue
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// containsString verifica se o slice contém o valor
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// =======================================================================================
+// HASHICORP VAULT
+// =======================================================================================
+
+// VaultConfigSource lê um segredo do engine KV do HashiCorp Vault. Cada campo do segredo
+// vira uma variável (ex: campo DB_PASSWORD)
+type VaultConfigSource struct {
+	Address    string       // URL do Vault (padrão: VAULT_ADDR)
+	Token      string       // Token de acesso (padrão: VAULT_TOKEN)
+	Namespace  string       // Namespace (Vault Enterprise; padrão: VAULT_NAMESPACE)
+	Mount      string       // Mount do engine KV (padrão: "secret")
+	Path       string       // Caminho do segredo no mount (ex: "myapp/prod")
+	KVVersion  int          // Versão do engine KV: 1 ou 2 (padrão: 2)
+	HTTPClient *http.Client // Cliente HTTP (padrão: timeout de 10s)
+}
+
+// NewVaultConfigSource cria uma fonte do Vault (KV v2) para o segredo em mount/path, usando
+// VAULT_ADDR e VAULT_TOKEN do ambiente
+func NewVaultConfigSource(mount, path string) *VaultConfigSource {
+	return &VaultConfigSource{Mount: mount, Path: path}
+}
+
+// Name implementa ConfigSource
+func (s *VaultConfigSource) Name() string {
+	return "vault:" + s.mount() + "/" + strings.Trim(s.Path, "/")
+}
+
+// Load implementa ConfigSource
+func (s *VaultConfigSource) Load(ctx context.Context) (map[string]string, error) {
+	address := firstNonEmpty(s.Address, os.Getenv("VAULT_ADDR"))
+	token := firstNonEmpty(s.Token, os.Getenv("VAULT_TOKEN"))
+	if address == "" || token == "" {
+		return nil, fmt.Errorf("vault address and token are required")
+	}
+
+	url := strings.TrimRight(address, "/") + "/v1/" + s.mount() + "/"
+	if s.KVVersion != 1 {
+		url += "data/"
+	}
+	url += strings.Trim(s.Path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := firstNonEmpty(s.Namespace, os.Getenv("VAULT_NAMESPACE")); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	body, err := doConfigSourceRequest(s.HTTPClient, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("invalid vault response: %w", err)
+	}
+	data := response.Data
+	if s.KVVersion != 1 {
+		// KV v2 retorna o segredo em data.data, junto com data.metadata
+		data = nil
+		if err := json.Unmarshal(response.Data["data"], &data); err != nil {
+			return nil, fmt.Errorf("invalid vault response: %w", err)
+		}
+	}
+	return configValuesFromJSON(data), nil
+}
+
+// mount retorna o mount do engine KV
+func (s *VaultConfigSource) mount() string {
+	if s.Mount == "" {
+		return "secret"
+	}
+	return strings.Trim(s.Mount, "/")
+}
+
+// =======================================================================================
+// HELPERS
+// =======================================================================================
+
+// doConfigSourceRequest executa a requisição e retorna o corpo, tratando status de erro
+func doConfigSourceRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s returned status %d: %s", req.Method, req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// configValuesFromJSON converte os campos de um objeto JSON em variáveis (strings são usadas
+// sem aspas; os demais tipos mantêm a representação JSON)
+func configValuesFromJSON(data map[string]json.RawMessage) map[string]string {
+	values := make(map[string]string, len(data))
+	for key, raw := range data {
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			values[key] = text
+		} else {
+			values[key] = string(raw)
+		}
+	}
+	return values
+}
+
+// firstNonEmpty retorna o primeiro valor não vazio
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,244 @@
+package odata
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// =======================================================================================
+// AWS SYSTEMS MANAGER PARAMETER STORE E SECRETS MANAGER
+// =======================================================================================
+
+// AWSCredentials identifica a conta e a região das fontes AWS. Campos vazios são lidos de
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN e AWS_REGION (ou
+// AWS_DEFAULT_REGION). Credenciais do perfil (~/.aws) e do metadata da instância não são lidas
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+}
+
+// resolve completa as credenciais com as variáveis de ambiente
+func (c AWSCredentials) resolve() (AWSCredentials, error) {
+	c.AccessKeyID = firstNonEmpty(c.AccessKeyID, os.Getenv("AWS_ACCESS_KEY_ID"))
+	c.SecretAccessKey = firstNonEmpty(c.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY"))
+	c.SessionToken = firstNonEmpty(c.SessionToken, os.Getenv("AWS_SESSION_TOKEN"))
+	c.Region = firstNonEmpty(c.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"))
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return c, fmt.Errorf("aws access key id and secret access key are required")
+	}
+	if c.Region == "" {
+		return c, fmt.Errorf("aws region is required")
+	}
+	return c, nil
+}
+
+// AWSParameterStoreSource lê os parâmetros sob um caminho do Parameter Store (SSM), com
+// descriptografia de SecureString. O nome relativo ao caminho vira a chave
+// (ex: /myapp/prod/DB_PASSWORD com Path "/myapp/prod" vira DB_PASSWORD; níveis extras são
+// unidos com "_")
+type AWSParameterStoreSource struct {
+	AWSCredentials
+	Path       string       // Caminho hierárquico dos parâmetros (ex: "/myapp/prod")
+	Endpoint   string       // Endpoint da API (padrão: https://ssm.{região}.amazonaws.com)
+	HTTPClient *http.Client // Cliente HTTP (padrão: timeout de 10s)
+}
+
+// NewAWSParameterStoreSource cria uma fonte do Parameter Store para o caminho informado
+func NewAWSParameterStoreSource(path string) *AWSParameterStoreSource {
+	return &AWSParameterStoreSource{Path: path}
+}
+
+// Name implementa ConfigSource
+func (s *AWSParameterStoreSource) Name() string {
+	return "aws-ssm:" + s.Path
+}
+
+// Load implementa ConfigSource
+func (s *AWSParameterStoreSource) Load(ctx context.Context) (map[string]string, error) {
+	credentials, err := s.resolve()
+	if err != nil {
+		return nil, err
+	}
+	prefix := "/" + strings.Trim(s.Path, "/")
+
+	values := make(map[string]string)
+	request := map[string]interface{}{
+		"Path":           prefix,
+		"Recursive":      true,
+		"WithDecryption": true,
+	}
+	for {
+		var response struct {
+			Parameters []struct {
+				Name  string `json:"Name"`
+				Value string `json:"Value"`
+			} `json:"Parameters"`
+			NextToken string `json:"NextToken"`
+		}
+		err := callAWSJSON(ctx, s.HTTPClient, credentials, "ssm", s.Endpoint, "AmazonSSM.GetParametersByPath", request, &response)
+		if err != nil {
+			return nil, err
+		}
+		for _, parameter := range response.Parameters {
+			key := strings.Trim(strings.TrimPrefix(parameter.Name, prefix), "/")
+			if key != "" {
+				values[strings.ReplaceAll(key, "/", "_")] = parameter.Value
+			}
+		}
+		if response.NextToken == "" {
+			return values, nil
+		}
+		request["NextToken"] = response.NextToken
+	}
+}
+
+// AWSSecretsManagerSource lê um segredo do Secrets Manager cujo valor é um objeto JSON
+// (formato chave/valor do console). Cada campo vira uma variável
+type AWSSecretsManagerSource struct {
+	AWSCredentials
+	SecretID     string       // Nome ou ARN do segredo
+	VersionStage string       // Estágio da versão (padrão: AWSCURRENT)
+	Endpoint     string       // Endpoint da API (padrão: https://secretsmanager.{região}.amazonaws.com)
+	HTTPClient   *http.Client // Cliente HTTP (padrão: timeout de 10s)
+}
+
+// NewAWSSecretsManagerSource cria uma fonte do Secrets Manager para o segredo informado
+func NewAWSSecretsManagerSource(secretID string) *AWSSecretsManagerSource {
+	return &AWSSecretsManagerSource{SecretID: secretID}
+}
+
+// Name implementa ConfigSource
+func (s *AWSSecretsManagerSource) Name() string {
+	return "aws-secretsmanager:" + s.SecretID
+}
+
+// Load implementa ConfigSource
+func (s *AWSSecretsManagerSource) Load(ctx context.Context) (map[string]string, error) {
+	credentials, err := s.resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	request := map[string]interface{}{"SecretId": s.SecretID}
+	if s.VersionStage != "" {
+		request["VersionStage"] = s.VersionStage
+	}
+	var response struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := callAWSJSON(ctx, s.HTTPClient, credentials, "secretsmanager", s.Endpoint, "secretsmanager.GetSecretValue", request, &response); err != nil {
+		return nil, err
+	}
+
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(response.SecretString), &data); err != nil {
+		return nil, fmt.Errorf("secret '%s' is not a JSON object: %w", s.SecretID, err)
+	}
+	return configValuesFromJSON(data), nil
+}
+
+// callAWSJSON chama uma API JSON da AWS (protocolo awsJson1.1) assinando a requisição com SigV4
+func callAWSJSON(ctx context.Context, client *http.Client, credentials AWSCredentials, service, endpoint, target string, request, response interface{}) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", service, credentials.Region)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", target)
+	signAWSRequestV4(req, payload, credentials, service, time.Now().UTC())
+
+	body, err := doConfigSourceRequest(client, req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("invalid %s response: %w", service, err)
+	}
+	return nil
+}
+
+// signAWSRequestV4 assina a requisição com AWS Signature Version 4
+func signAWSRequestV4(req *http.Request, payload []byte, credentials AWSCredentials, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	headers := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         req.URL.Host,
+		"x-amz-date":   amzDate,
+		"x-amz-target": req.Header.Get("X-Amz-Target"),
+	}
+	if credentials.SessionToken != "" {
+		headers["x-amz-security-token"] = credentials.SessionToken
+	}
+	// Ordem alfabética exigida pela especificação
+	names := []string{"content-type", "host", "x-amz-date"}
+	if credentials.SessionToken != "" {
+		names = append(names, "x-amz-security-token")
+	}
+	names = append(names, "x-amz-target")
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(payload)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + credentials.Region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, credentials.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 calcula o HMAC-SHA256 de data com a chave informada
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,153 @@
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticConfigSource struct {
+	values map[string]string
+	err    error
+}
+
+func (s *staticConfigSource) Name() string { return "static" }
+
+func (s *staticConfigSource) Load(ctx context.Context) (map[string]string, error) {
+	return s.values, s.err
+}
+
+func TestUseConfigSources(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, UseConfigSources(context.Background())) })
+
+	first := &staticConfigSource{values: map[string]string{"DB_PASSWORD": "from-first", "JWT_SECRET_KEY": "jwt-key"}}
+	second := &staticConfigSource{values: map[string]string{"DB_PASSWORD": "from-vault"}}
+	require.NoError(t, UseConfigSources(context.Background(), first, second))
+
+	config, err := LoadEnvOrDefault()
+	require.NoError(t, err)
+	assert.Equal(t, "from-vault", config.DBPassword, "a última fonte tem precedência")
+	assert.Equal(t, "jwt-key", config.JWTSecretKey)
+
+	t.Run("Reload mantém os valores anteriores em caso de erro", func(t *testing.T) {
+		second.values = map[string]string{"DB_PASSWORD": "rotated"}
+		require.NoError(t, ReloadConfigSources(context.Background()))
+		config, _ := LoadEnvOrDefault()
+		assert.Equal(t, "rotated", config.DBPassword)
+
+		second.err = errors.New("vault sealed")
+		err := ReloadConfigSources(context.Background())
+		assert.ErrorContains(t, err, "vault sealed")
+		config, _ = LoadEnvOrDefault()
+		assert.Equal(t, "rotated", config.DBPassword)
+	})
+}
+
+func TestEnvConfigSource(t *testing.T) {
+	t.Setenv("MYAPP_DB_PASSWORD", "s3cret")
+	t.Setenv("MYAPP_JWT_SECRET_KEY", "jwt")
+
+	values, err := NewEnvConfigSource("MYAPP_").Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", values["DB_PASSWORD"])
+	assert.Equal(t, "jwt", values["JWT_SECRET_KEY"])
+
+	values, err = NewEnvConfigSource("MYAPP_", "DB_PASSWORD").Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"DB_PASSWORD": "s3cret"}, values)
+}
+
+func TestVaultConfigSource(t *testing.T) {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root-token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/data/myapp/prod":
+			w.Write([]byte(`{"data":{"data":{"DB_PASSWORD":"s3cret","DB_MAX_OPEN_CONNS":20},"metadata":{"version":3}}}`))
+		case "/v1/legacy/myapp":
+			w.Write([]byte(`{"data":{"JWT_SECRET_KEY":"jwt"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer vault.Close()
+
+	source := &VaultConfigSource{Address: vault.URL, Token: "root-token", Mount: "kv", Path: "/myapp/prod/"}
+	values, err := source.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"DB_PASSWORD": "s3cret", "DB_MAX_OPEN_CONNS": "20"}, values)
+
+	source = &VaultConfigSource{Address: vault.URL, Token: "root-token", Mount: "legacy", Path: "myapp", KVVersion: 1}
+	values, err = source.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "jwt", values["JWT_SECRET_KEY"])
+
+	source.Token = "wrong"
+	_, err = source.Load(context.Background())
+	assert.ErrorContains(t, err, "403")
+}
+
+func TestAWSConfigSources(t *testing.T) {
+	var targets []string
+	aws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			!strings.Contains(auth, "/sa-east-1/") ||
+			!strings.Contains(auth, "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		target := r.Header.Get("X-Amz-Target")
+		targets = append(targets, target)
+
+		body, _ := io.ReadAll(r.Body)
+		var request map[string]interface{}
+		json.Unmarshal(body, &request)
+
+		switch target {
+		case "AmazonSSM.GetParametersByPath":
+			assert.Equal(t, "/myapp/prod", request["Path"])
+			assert.Equal(t, true, request["WithDecryption"])
+			if request["NextToken"] == nil {
+				w.Write([]byte(`{"Parameters":[{"Name":"/myapp/prod/DB_PASSWORD","Value":"s3cret"}],"NextToken":"page2"}`))
+			} else {
+				w.Write([]byte(`{"Parameters":[{"Name":"/myapp/prod/jwt/SECRET","Value":"jwt"}]}`))
+			}
+		case "secretsmanager.GetSecretValue":
+			assert.Equal(t, "myapp/prod", request["SecretId"])
+			w.Write([]byte(`{"SecretString":"{\"DB_PASSWORD\":\"from-secrets-manager\"}"}`))
+		}
+	}))
+	defer aws.Close()
+
+	credentials := AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session", Region: "sa-east-1"}
+
+	ssm := &AWSParameterStoreSource{AWSCredentials: credentials, Path: "myapp/prod", Endpoint: aws.URL}
+	values, err := ssm.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"DB_PASSWORD": "s3cret", "jwt_SECRET": "jwt"}, values)
+
+	secrets := &AWSSecretsManagerSource{AWSCredentials: credentials, SecretID: "myapp/prod", Endpoint: aws.URL}
+	values, err = secrets.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "from-secrets-manager", values["DB_PASSWORD"])
+	assert.Equal(t, []string{"AmazonSSM.GetParametersByPath", "AmazonSSM.GetParametersByPath", "secretsmanager.GetSecretValue"}, targets)
+
+	t.Run("Credenciais ausentes", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+		_, err := NewAWSSecretsManagerSource("myapp/prod").Load(context.Background())
+		assert.ErrorContains(t, err, "access key")
+	})
+}
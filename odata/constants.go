@@ -30,8 +30,12 @@ const (
 
 // Query Defaults
 const (
-	DefaultPageSize = 50               // Default page size if $top not specified
-	DefaultTimeout  = 30 * time.Second // Default query timeout
+	DefaultPageSize             = 50               // Default page size if $top not specified
+	DefaultTimeout              = 30 * time.Second // Default query timeout
+	DefaultMaxExpandParallelism = 4                // Max navigations of a $expand fetched concurrently
+
+	DefaultFederatedExpandBatchSize = 500   // Keys per query of a $expand from another provider
+	DefaultMaxFederatedExpandKeys   = 10000 // Max distinct keys of a $expand from another provider
 )
 
 // Security
@@ -16,15 +16,27 @@ func getServerFromContext(c fiber.Ctx) *Server {
 	return nil
 }
 
+// objectManagerContextKey é a chave do ObjectManager da requisição no contexto
+const objectManagerContextKey = "odata_object_manager"
+
 // GetObjectManager retorna o ObjectManager do fiber context
 // Útil para endpoints customizados (JWT, handlers manuais)
+// O manager é único por requisição (mesmo cache e unidade de trabalho entre chamadas); use
+// CreateObjectManager para um manager independente
 func GetObjectManager(c fiber.Ctx) *ObjectManager {
 	server := getServerFromContext(c)
 	if server == nil {
 		return nil
 	}
 	provider := server.getCurrentProvider(c)
-	return NewObjectManager(provider, c.Context())
+
+	// Se o tenant mudou na requisição, o manager anterior aponta para outro banco
+	if manager, ok := c.Locals(objectManagerContextKey).(*ObjectManager); ok && manager.provider == provider {
+		return manager
+	}
+	manager := NewObjectManager(provider, c.Context())
+	c.Locals(objectManagerContextKey, manager)
+	return manager
 }
 
 // GetConnection retorna a conexão SQL do fiber context
@@ -0,0 +1,69 @@
+package odata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_CountEndpoint(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	metadata := server.entities["Products"].GetMetadata()
+	metadata.Properties[1].IsSearchable = true
+	metadata.Search = &SearchConfig{Strategy: SearchStrategyLike}
+	server.entities["Products"] = NewBaseEntityService(server.provider, metadata, server)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	count := func(t *testing.T, path string) (int, string) {
+		t.Helper()
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if resp.StatusCode == http.StatusOK {
+			assert.Equal(t, "text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"Sem filtro", "/odata/Products/$count", "3"},
+		{"Com $filter", "/odata/Products/$count?$filter=price%20gt%2020", "2"},
+		{"Com $search", "/odata/Products/$count?$search=Mo", "2"},
+		{"Com $filter e $search", "/odata/Products/$count?$filter=price%20lt%20100&$search=Mo", "1"},
+		{"Ignora $top e $skip", "/odata/Products/$count?$top=1&$skip=1", "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := count(t, tt.path)
+			require.Equal(t, http.StatusOK, status, body)
+			assert.Equal(t, tt.want, body)
+		})
+	}
+
+	t.Run("$count=true com $search", func(t *testing.T) {
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Products?$search=Mo&$count=true&$top=1", nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, float64(2), body["@odata.count"])
+	})
+
+	t.Run("$filter inválido", func(t *testing.T) {
+		status, _ := count(t, "/odata/Products/$count?$filter=price%20gt")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
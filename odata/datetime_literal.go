@@ -0,0 +1,188 @@
+package odata
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// =======================================================================================
+// LITERAIS DE DATA/HORA E FUSO HORÁRIO
+// =======================================================================================
+
+// Formatos ISO 8601 aceitos em literais Edm.DateTimeOffset. Sem offset, o valor é
+// interpretado no fuso horário do servidor
+var dateTimeLiteralLayouts = []string{
+	time.RFC3339Nano,                // 2024-01-01T00:00:00.123Z, 2024-01-01T00:00:00-03:00
+	"2006-01-02T15:04Z07:00",        // 2024-01-01T00:00Z
+	"2006-01-02T15:04:05.999999999", // 2024-01-01T00:00:00 (fuso do servidor)
+	"2006-01-02T15:04",              // 2024-01-01T00:00 (fuso do servidor)
+	"2006-01-02 15:04:05.999999999", // formato SQL (payloads e valores vindos do banco)
+	"2006-01-02",                    // apenas a data (payloads): meia-noite no fuso do servidor
+}
+
+// Formatos aceitos em literais Edm.TimeOfDay
+var timeLiteralLayouts = []string{"15:04:05.999999999", "15:04"}
+
+const (
+	dateLiteralLayout = "2006-01-02"
+	timeLiteralLayout = "15:04:05"
+)
+
+// loadTimeZone carrega o fuso horário pelo nome IANA (ex: America/Sao_Paulo), usando UTC
+// quando vazio ou inválido
+func loadTimeZone(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("⚠️ Fuso horário inválido '%s', usando UTC: %v", name, err)
+		return time.UTC
+	}
+	return location
+}
+
+// timeZone retorna o fuso horário do servidor (UTC por padrão)
+func (s *Server) timeZone() *time.Location {
+	if s == nil || s.config == nil || s.config.TimeZone == nil {
+		return time.UTC
+	}
+	return s.config.TimeZone
+}
+
+// parseDateTimeLiteral converte um literal ISO 8601 para time.Time em UTC. Valores sem
+// offset são interpretados no fuso horário informado
+func parseDateTimeLiteral(value string, location *time.Location) (time.Time, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	for _, layout := range dateTimeLiteralLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, location); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid datetime literal: %s", value)
+}
+
+// parseTimeLiteral valida um literal Edm.TimeOfDay e o normaliza para HH:MM:SS
+func parseTimeLiteral(value string) (string, error) {
+	for _, layout := range timeLiteralLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			if parsed.Nanosecond() > 0 {
+				return parsed.Format("15:04:05.999999999"), nil
+			}
+			return parsed.Format(timeLiteralLayout), nil
+		}
+	}
+	return "", fmt.Errorf("invalid time literal: %s", value)
+}
+
+// resolveDateTimeLiterals valida os literais de data/hora do filtro e converte os
+// Edm.DateTimeOffset para UTC usando o fuso horário do servidor, guardando o valor no
+// SemanticReference do token
+func resolveDateTimeLiterals(node *ParseNode, location *time.Location) error {
+	var err error
+	walkParseTree(node, func(n *ParseNode) {
+		if err != nil || n.Token == nil {
+			return
+		}
+		switch n.Token.Type {
+		case int(FilterTokenDateTime):
+			var parsed time.Time
+			if parsed, err = parseDateTimeLiteral(n.Token.Value, location); err == nil {
+				n.Token.SemanticReference = parsed
+			}
+		case int(FilterTokenDate):
+			_, err = time.Parse(dateLiteralLayout, n.Token.Value)
+			if err != nil {
+				err = fmt.Errorf("invalid date literal: %s", n.Token.Value)
+			}
+		case int(FilterTokenTime):
+			_, err = parseTimeLiteral(n.Token.Value)
+		}
+	})
+	return err
+}
+
+// buildDateTimeLiteral retorna o valor de um literal de data/hora no formato do banco
+// (FormatDateTime do dialeto) e o template do placeholder (conversão explícita no Oracle)
+func (qb *QueryBuilder) buildDateTimeLiteral(node *ParseNode) (string, interface{}, error) {
+	oracle := qb.dialect.GetName() == "oracle"
+
+	switch node.Token.Type {
+	case int(FilterTokenDateTime):
+		parsed, ok := node.Token.SemanticReference.(time.Time)
+		if !ok {
+			var err error
+			if parsed, err = parseDateTimeLiteral(node.Token.Value, time.UTC); err != nil {
+				return "", nil, err
+			}
+		}
+		if oracle {
+			return "TO_TIMESTAMP(%s, 'YYYY-MM-DD HH24:MI:SS')", qb.dialect.FormatDateTime(parsed), nil
+		}
+		return "%s", qb.dialect.FormatDateTime(parsed), nil
+
+	case int(FilterTokenDate):
+		if _, err := time.Parse(dateLiteralLayout, node.Token.Value); err != nil {
+			return "", nil, fmt.Errorf("invalid date literal: %s", node.Token.Value)
+		}
+		if oracle {
+			return "TO_DATE(%s, 'YYYY-MM-DD')", node.Token.Value, nil
+		}
+		return "%s", node.Token.Value, nil
+
+	default:
+		value, err := parseTimeLiteral(node.Token.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		return "%s", value, nil
+	}
+}
+
+// buildDateTimeLiteralNamed adiciona o literal de data/hora aos argumentos nomeados
+func (qb *QueryBuilder) buildDateTimeLiteralNamed(node *ParseNode, namedArgs *NamedArgs) (string, error) {
+	template, value, err := qb.buildDateTimeLiteral(node)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(template, namedArgs.AddArg(value)), nil
+}
+
+// normalizeDateTimeValues converte os valores das propriedades time.Time do payload para
+// UTC antes da gravação. Strings sem offset são interpretadas no fuso do servidor; formatos
+// não reconhecidos seguem para a validação do provider
+func (s *BaseEntityService) normalizeDateTimeValues(metadata EntityMetadata, data map[string]any) {
+	location := time.UTC
+	if s.server != nil {
+		location = s.server.timeZone()
+	}
+
+	for _, prop := range metadata.Properties {
+		if prop.Type != "time.Time" {
+			continue
+		}
+		switch value := data[prop.Name].(type) {
+		case time.Time:
+			data[prop.Name] = value.UTC()
+		case *time.Time:
+			if value != nil {
+				data[prop.Name] = value.UTC()
+			}
+		case string:
+			if parsed, err := parseDateTimeLiteral(value, location); err == nil {
+				data[prop.Name] = parsed
+			}
+		}
+	}
+}
+
+// prepareDateTimeFilter resolve os literais de data/hora do filtro no fuso do servidor
+func (s *Server) prepareDateTimeFilter(options *QueryOptions) error {
+	if options.Filter == nil || options.Filter.Tree == nil {
+		return nil
+	}
+	return resolveDateTimeLiterals(options.Filter.Tree, s.timeZone())
+}
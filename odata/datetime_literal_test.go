@@ -0,0 +1,152 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDateTimeLiteral(t *testing.T) {
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+
+	tests := []struct {
+		value    string
+		location *time.Location
+		expected time.Time
+	}{
+		{"2024-01-01T00:00:00Z", saoPaulo, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-01T00:00:00-03:00", time.UTC, time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)},
+		{"2024-01-01T10:30:00.125Z", nil, time.Date(2024, 1, 1, 10, 30, 0, 125000000, time.UTC)},
+		{"2024-01-01T00:00:00", saoPaulo, time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)},
+		{"2024-01-01T00:00", nil, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			parsed, err := parseDateTimeLiteral(tt.value, tt.location)
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(parsed), "esperado %s, obtido %s", tt.expected, parsed)
+			assert.Equal(t, time.UTC, parsed.Location())
+		})
+	}
+
+	_, err := parseDateTimeLiteral("2024-13-01T00:00:00Z", nil)
+	assert.Error(t, err)
+
+	assert.Equal(t, time.UTC, loadTimeZone(""))
+	assert.Equal(t, time.UTC, loadTimeZone("Invalid/Zone"))
+}
+
+func TestQueryBuilder_DateTimeLiterals(t *testing.T) {
+	ctx := context.Background()
+	metadata := EntityMetadata{
+		Name: "Orders",
+		Properties: []PropertyMetadata{
+			{Name: "CreatedAt", ColumnName: "created_at", Type: "time.Time"},
+			{Name: "OpensAt", ColumnName: "opens_at", Type: "string"},
+		},
+	}
+
+	build := func(t *testing.T, dialect, filter string) (string, []interface{}) {
+		t.Helper()
+		parsed, err := ParseFilterString(ctx, filter)
+		require.NoError(t, err)
+		require.NoError(t, resolveDateTimeLiterals(parsed.Tree, time.FixedZone("BRT", -3*60*60)))
+		namedArgs := NewNamedArgs(dialect)
+		where, err := NewQueryBuilder(dialect).BuildWhereClauseNamed(ctx, parsed.Tree, metadata, namedArgs)
+		require.NoError(t, err)
+		var values []interface{}
+		for _, arg := range namedArgs.GetNamedArgs() {
+			values = append(values, arg.(sql.NamedArg).Value)
+		}
+		return where, values
+	}
+
+	t.Run("datetimeoffset convertido para UTC no formato do banco", func(t *testing.T) {
+		where, values := build(t, "mysql", "CreatedAt ge 2024-01-01T00:00:00-03:00")
+		assert.Equal(t, "(created_at >= :param1)", where)
+		assert.Equal(t, []interface{}{"2024-01-01 03:00:00"}, values)
+
+		_, values = build(t, "mysql", "CreatedAt lt 2024-01-01T00:00:00")
+		assert.Equal(t, []interface{}{"2024-01-01 03:00:00"}, values, "sem offset usa o fuso do servidor")
+	})
+
+	t.Run("date e time", func(t *testing.T) {
+		_, values := build(t, "mysql", "CreatedAt ge 2024-01-01 and OpensAt lt 08:30")
+		assert.Equal(t, []interface{}{"2024-01-01", "08:30:00"}, values)
+	})
+
+	t.Run("Oracle converte explicitamente", func(t *testing.T) {
+		where, _ := build(t, "oracle", "CreatedAt ge 2024-01-01T00:00:00Z and CreatedAt lt 2024-02-01")
+		assert.Contains(t, where, "TO_TIMESTAMP(:param1, 'YYYY-MM-DD HH24:MI:SS')")
+		assert.Contains(t, where, "TO_DATE(:param2, 'YYYY-MM-DD')")
+	})
+
+	t.Run("Literais inválidos", func(t *testing.T) {
+		parsed, err := ParseFilterString(ctx, "CreatedAt ge 2024-02-30")
+		require.NoError(t, err)
+		assert.Error(t, resolveDateTimeLiterals(parsed.Tree, time.UTC))
+	})
+}
+
+func TestServer_DateTimeFilter(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	conn := server.provider.GetConnection()
+	for _, stmt := range []string{
+		"ALTER TABLE products ADD COLUMN created_at TEXT",
+		"UPDATE products SET created_at = CASE id WHEN 1 THEN '2024-01-01 02:00:00' WHEN 2 THEN '2024-01-01 04:00:00' ELSE '2024-03-01 12:00:00' END",
+	} {
+		_, err := conn.Exec(stmt)
+		require.NoError(t, err)
+	}
+	metadata := server.entities["Products"].GetMetadata()
+	metadata.Properties = append(metadata.Properties, PropertyMetadata{Name: "created_at", ColumnName: "created_at", Type: "time.Time"})
+	server.entities["Products"] = NewBaseEntityService(server.provider, metadata, server)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	t.Run("Filtra por datetimeoffset e serializa em UTC", func(t *testing.T) {
+		status, body := getComputeTestProducts(t, server, "$filter="+url.QueryEscape("created_at ge 2024-01-01T00:00:00-03:00")+"&$orderby=id")
+		require.Equal(t, http.StatusOK, status, body)
+		values := body["value"].([]any)
+		require.Len(t, values, 2)
+		assert.Equal(t, "2024-01-01T04:00:00Z", values[0].(map[string]any)["created_at"])
+	})
+
+	t.Run("Literais sem offset usam o fuso do servidor", func(t *testing.T) {
+		server.SetTimeZone(time.FixedZone("BRT", -3*60*60))
+		defer server.SetTimeZone(nil)
+
+		status, body := getComputeTestProducts(t, server, "$filter="+url.QueryEscape("created_at lt 2024-01-01T00:00:00"))
+		require.Equal(t, http.StatusOK, status, body)
+		require.Len(t, body["value"], 1)
+		assert.Equal(t, "Mouse", body["value"].([]any)[0].(map[string]any)["name"])
+	})
+
+	t.Run("Literal inválido retorna erro", func(t *testing.T) {
+		status, body := getComputeTestProducts(t, server, "$filter="+url.QueryEscape("created_at ge 2024-02-30"))
+		assert.Equal(t, http.StatusBadRequest, status, body)
+	})
+}
+
+func TestBaseEntityService_NormalizeDateTimeValues(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.SetTimeZone(time.FixedZone("BRT", -3*60*60))
+	service := server.entities["Products"].(*BaseEntityService)
+	metadata := EntityMetadata{Properties: []PropertyMetadata{{Name: "CreatedAt", Type: "time.Time"}}}
+
+	data := map[string]any{"CreatedAt": "2024-01-01T00:00:00"}
+	service.normalizeDateTimeValues(metadata, data)
+	assert.Equal(t, time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC), data["CreatedAt"])
+
+	data = map[string]any{"CreatedAt": time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600))}
+	service.normalizeDateTimeValues(metadata, data)
+	assert.Equal(t, time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC), data["CreatedAt"])
+}
@@ -0,0 +1,380 @@
+package odata
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v3"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// =======================================================================================
+// MAPEAMENTO DE ERROS DE CONSTRAINT DO BANCO DE DADOS
+// =======================================================================================
+
+// ConstraintKind identifica o tipo de constraint violada
+type ConstraintKind string
+
+const (
+	ConstraintUnique       ConstraintKind = "unique"
+	ConstraintForeignKey   ConstraintKind = "foreign_key"
+	ConstraintNotNull      ConstraintKind = "not_null"
+	ConstraintValueTooLong ConstraintKind = "value_too_long"
+)
+
+// ConstraintViolationError representa uma violação de constraint reportada pelo banco,
+// já associada à propriedade da entidade quando é possível identificá-la
+type ConstraintViolationError struct {
+	Kind       ConstraintKind
+	Entity     string
+	Property   string // Propriedade da entidade correspondente à coluna (vazio se desconhecida)
+	Column     string // Coluna reportada pelo banco
+	Constraint string // Nome da constraint reportado pelo banco
+	Referenced bool   // Chave estrangeira: a entidade é referenciada por outros registros
+	Err        error  // Erro original do driver
+}
+
+// Error implementa a interface error mantendo o erro original para logs
+func (e *ConstraintViolationError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Message(), e.Err)
+}
+
+// Unwrap retorna o erro original do driver
+func (e *ConstraintViolationError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode retorna o status HTTP da violação: 409 para unique/chave estrangeira e
+// 400 para valores nulos ou longos demais
+func (e *ConstraintViolationError) StatusCode() int {
+	switch e.Kind {
+	case ConstraintUnique, ConstraintForeignKey:
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Code retorna o código de erro OData da violação
+func (e *ConstraintViolationError) Code() string {
+	switch e.Kind {
+	case ConstraintUnique:
+		return "UniqueViolation"
+	case ConstraintForeignKey:
+		return "ForeignKeyViolation"
+	case ConstraintNotNull:
+		return "NotNullViolation"
+	default:
+		return "ValueTooLong"
+	}
+}
+
+// Message retorna a mensagem exposta ao cliente, sem o texto original do banco
+func (e *ConstraintViolationError) Message() string {
+	switch e.Kind {
+	case ConstraintUnique:
+		if e.Property != "" {
+			return fmt.Sprintf("Value of property '%s' already exists", e.Property)
+		}
+		return "Entity violates a unique constraint"
+	case ConstraintForeignKey:
+		if e.Referenced {
+			return "Entity is referenced by other entities"
+		}
+		if e.Property != "" {
+			return fmt.Sprintf("Value of property '%s' does not reference an existing entity", e.Property)
+		}
+		return "Entity violates a foreign key constraint"
+	case ConstraintNotNull:
+		if e.Property != "" {
+			return fmt.Sprintf("Property '%s' cannot be null", e.Property)
+		}
+		return "A required property is null"
+	default:
+		if e.Property != "" {
+			return fmt.Sprintf("Value of property '%s' is too long", e.Property)
+		}
+		return "A property value is too long"
+	}
+}
+
+// ODataError converte a violação no erro OData, com target apontando para a propriedade
+func (e *ConstraintViolationError) ODataError() *ODataError {
+	odataErr := NewODataError(e.Code(), e.Message()).WithStatus(e.StatusCode())
+	if e.Property != "" {
+		odataErr.WithTarget(e.Property)
+	}
+	return odataErr
+}
+
+var (
+	// PostgreSQL: Key (email)=(x) already exists / is not present in table ...
+	pgKeyDetailPattern = regexp.MustCompile(`Key \(([^)]+)\)=`)
+
+	// MySQL: Duplicate entry 'x' for key 'products.name'
+	mysqlDuplicateKeyPattern = regexp.MustCompile(`for key '([^']+)'`)
+	// MySQL: ... CONSTRAINT `fk` FOREIGN KEY (`customer_id`) REFERENCES ...
+	mysqlForeignKeyPattern = regexp.MustCompile("CONSTRAINT `([^`]+)` FOREIGN KEY \\(`([^`]+)`")
+	// MySQL: Column 'name' cannot be null / Data too long for column 'name' / Field 'name' doesn't have a default value
+	mysqlColumnPattern = regexp.MustCompile(`(?:[Cc]olumn|Field) '([^']+)'`)
+
+	// Oracle: ORA-00001: unique constraint (SCHEMA.UK_NAME) violated
+	oracleErrorPattern = regexp.MustCompile(`ORA-(\d{5})`)
+	// Oracle: ("SCHEMA"."TABLE"."COLUMN") / column "SCHEMA"."TABLE"."COLUMN"
+	oracleColumnPattern     = regexp.MustCompile(`"[^"]+"\."[^"]+"\."([^"]+)"`)
+	oracleConstraintPattern = regexp.MustCompile(`constraint \(([^)]+)\)`)
+
+	// SQLite: UNIQUE constraint failed: products.name
+	sqliteConstraintPattern = regexp.MustCompile(`(UNIQUE|NOT NULL|FOREIGN KEY) constraint failed(?::\s*([\w.]+))?`)
+)
+
+// TranslateDatabaseError identifica violações de constraint (unique, chave estrangeira,
+// not null e valor longo demais) no erro do driver e as converte em
+// *ConstraintViolationError. Outros erros são retornados sem alteração
+func TranslateDatabaseError(err error, metadata EntityMetadata) error {
+	if err == nil {
+		return nil
+	}
+
+	var violation *ConstraintViolationError
+	if errors.As(err, &violation) {
+		return err
+	}
+
+	violation = detectConstraintViolation(err)
+	if violation == nil {
+		return err
+	}
+
+	violation.Entity = metadata.Name
+	violation.Err = err
+	if violation.Column != "" {
+		violation.Property = propertyForColumn(metadata, violation.Column)
+	} else if violation.Constraint != "" {
+		violation.Property = propertyForConstraint(metadata, violation.Constraint)
+	}
+	return violation
+}
+
+// detectConstraintViolation reconhece os erros de constraint de cada banco suportado
+func detectConstraintViolation(err error) *ConstraintViolationError {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return postgresConstraintViolation(pgErr)
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlConstraintViolation(mysqlErr.Number, mysqlErr.Message)
+	}
+
+	message := err.Error()
+	if match := oracleErrorPattern.FindStringSubmatch(message); match != nil {
+		return oracleConstraintViolation(match[1], message)
+	}
+	return sqliteConstraintViolation(message)
+}
+
+// postgresConstraintViolation mapeia os SQLSTATE 23505, 23503, 23502 e 22001
+func postgresConstraintViolation(pgErr *pgconn.PgError) *ConstraintViolationError {
+	violation := &ConstraintViolationError{Column: pgErr.ColumnName, Constraint: pgErr.ConstraintName}
+	keyColumn := ""
+	if match := pgKeyDetailPattern.FindStringSubmatch(pgErr.Detail); match != nil {
+		keyColumn = match[1]
+	}
+
+	switch pgErr.Code {
+	case "23505":
+		violation.Kind = ConstraintUnique
+		violation.Column = keyColumn
+	case "23503":
+		violation.Kind = ConstraintForeignKey
+		violation.Referenced = strings.Contains(pgErr.Detail, "still referenced")
+		if !violation.Referenced {
+			violation.Column = keyColumn
+		}
+	case "23502":
+		violation.Kind = ConstraintNotNull
+	case "22001":
+		violation.Kind = ConstraintValueTooLong
+	default:
+		return nil
+	}
+	return violation
+}
+
+// mysqlConstraintViolation mapeia os erros 1062, 1451, 1452, 1048, 1364 e 1406
+func mysqlConstraintViolation(number uint16, message string) *ConstraintViolationError {
+	violation := &ConstraintViolationError{}
+
+	switch number {
+	case 1062:
+		violation.Kind = ConstraintUnique
+		if match := mysqlDuplicateKeyPattern.FindStringSubmatch(message); match != nil {
+			violation.Constraint = match[1]
+		}
+	case 1451, 1452:
+		violation.Kind = ConstraintForeignKey
+		violation.Referenced = number == 1451
+		if match := mysqlForeignKeyPattern.FindStringSubmatch(message); match != nil {
+			violation.Constraint = match[1]
+			if !violation.Referenced {
+				violation.Column = match[2]
+			}
+		}
+	case 1048, 1364:
+		violation.Kind = ConstraintNotNull
+	case 1406:
+		violation.Kind = ConstraintValueTooLong
+	default:
+		return nil
+	}
+
+	if violation.Kind == ConstraintNotNull || violation.Kind == ConstraintValueTooLong {
+		if match := mysqlColumnPattern.FindStringSubmatch(message); match != nil {
+			violation.Column = match[1]
+		}
+	}
+	return violation
+}
+
+// oracleConstraintViolation mapeia ORA-00001, ORA-02291, ORA-02292, ORA-01400 e ORA-12899
+func oracleConstraintViolation(code, message string) *ConstraintViolationError {
+	violation := &ConstraintViolationError{}
+	if match := oracleConstraintPattern.FindStringSubmatch(message); match != nil {
+		violation.Constraint = match[1]
+	}
+
+	switch code {
+	case "00001":
+		violation.Kind = ConstraintUnique
+	case "02291", "02292":
+		violation.Kind = ConstraintForeignKey
+		violation.Referenced = code == "02292"
+	case "01400":
+		violation.Kind = ConstraintNotNull
+	case "12899":
+		violation.Kind = ConstraintValueTooLong
+	default:
+		return nil
+	}
+
+	if violation.Kind == ConstraintNotNull || violation.Kind == ConstraintValueTooLong {
+		if match := oracleColumnPattern.FindStringSubmatch(message); match != nil {
+			violation.Column = match[1]
+		}
+	}
+	return violation
+}
+
+// sqliteConstraintViolation mapeia as mensagens de constraint do SQLite
+func sqliteConstraintViolation(message string) *ConstraintViolationError {
+	match := sqliteConstraintPattern.FindStringSubmatch(message)
+	if match == nil {
+		return nil
+	}
+
+	violation := &ConstraintViolationError{Column: match[2]}
+	switch match[1] {
+	case "UNIQUE":
+		violation.Kind = ConstraintUnique
+	case "NOT NULL":
+		violation.Kind = ConstraintNotNull
+	default:
+		violation.Kind = ConstraintForeignKey
+	}
+	return violation
+}
+
+// propertyForColumn retorna a propriedade mapeada para a coluna. Colunas compostas
+// (a, b) e qualificadas (tabela.coluna) usam a primeira coluna reconhecida
+func propertyForColumn(metadata EntityMetadata, column string) string {
+	for _, name := range strings.Split(column, ",") {
+		name = strings.Trim(strings.TrimSpace(name), "\"`")
+		if idx := strings.LastIndex(name, "."); idx >= 0 {
+			name = name[idx+1:]
+		}
+
+		for _, prop := range metadata.Properties {
+			if prop.IsNavigation {
+				continue
+			}
+			if strings.EqualFold(prop.ColumnName, name) || strings.EqualFold(prop.Name, name) {
+				return prop.Name
+			}
+		}
+	}
+	return ""
+}
+
+// propertyForConstraint tenta identificar a propriedade pelo nome da constraint
+// (por exemplo products.name, UK_PRODUCTS_NAME ou products_name_key), preferindo a
+// coluna de nome mais longo quando várias coincidem
+func propertyForConstraint(metadata EntityMetadata, constraint string) string {
+	tokens := strings.FieldsFunc(strings.ToLower(constraint), func(r rune) bool {
+		return r == '.' || r == '_' || r == '"' || r == '`'
+	})
+	joined := "_" + strings.Join(tokens, "_") + "_"
+
+	best, bestLen := "", 0
+	for _, prop := range metadata.Properties {
+		if prop.IsNavigation {
+			continue
+		}
+		column := prop.ColumnName
+		if column == "" {
+			column = prop.Name
+		}
+		column = strings.ToLower(column)
+		if len(column) > bestLen && strings.Contains(joined, "_"+column+"_") {
+			best, bestLen = prop.Name, len(column)
+		}
+	}
+	return best
+}
+
+// constraintViolation extrai a violação de constraint de um erro, se houver
+func constraintViolation(err error) (*ConstraintViolationError, bool) {
+	var violation *ConstraintViolationError
+	if errors.As(err, &violation) {
+		return violation, true
+	}
+	return nil, false
+}
+
+// writeEntityError escreve o erro de uma operação na entidade: violações de constraint
+// viram 409/400 com a propriedade como target, *ODataError com status (ex: timeout de
+// query) mantém o próprio status; demais erros usam o status informado
+func (s *Server) writeEntityError(c fiber.Ctx, status int, code string, err error) {
+	status, odataErr, cause := entityODataError(status, code, err)
+	s.writeODataError(c, status, odataErr, cause)
+}
+
+// entityODataError converte o erro de uma operação na entidade no ODataError e no status
+// HTTP da resposta, com as mesmas regras de writeEntityError
+func entityODataError(status int, code string, err error) (int, *ODataError, error) {
+	if violation, ok := constraintViolation(err); ok {
+		return violation.StatusCode(), violation.ODataError(), err
+	}
+	var odataErr *ODataError
+	if errors.As(err, &odataErr) && odataErr.Status != 0 {
+		return odataErr.Status, odataErr, err
+	}
+	return status, NewODataError(code, err.Error()), nil
+}
+
+// entityErrorStatus retorna o status HTTP de um erro de operação na entidade
+func entityErrorStatus(err error, fallback int) int {
+	if violation, ok := constraintViolation(err); ok {
+		return violation.StatusCode()
+	}
+	var odataErr *ODataError
+	if errors.As(err, &odataErr) && odataErr.Status != 0 {
+		return odataErr.Status
+	}
+	return fallback
+}
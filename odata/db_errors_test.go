@@ -0,0 +1,158 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v3"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var constraintTestMetadata = EntityMetadata{
+	Name:      "Customers",
+	TableName: "customers",
+	Keys:      []string{"id"},
+	Properties: []PropertyMetadata{
+		{Name: "ID", ColumnName: "id", Type: "int64", IsKey: true},
+		{Name: "Email", ColumnName: "email", Type: "string"},
+		{Name: "Name", ColumnName: "name", Type: "string", MaxLength: 10},
+		{Name: "CompanyID", ColumnName: "company_id", Type: "int64"},
+	},
+}
+
+func TestTranslateDatabaseError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		kind       ConstraintKind
+		property   string
+		status     int
+		referenced bool
+	}{
+		{"PostgreSQL unique", &pgconn.PgError{Code: "23505", ConstraintName: "customers_email_key", Detail: "Key (email)=(a@b.com) already exists."}, ConstraintUnique, "Email", http.StatusConflict, false},
+		{"PostgreSQL FK filho", &pgconn.PgError{Code: "23503", Detail: `Key (company_id)=(9) is not present in table "companies".`}, ConstraintForeignKey, "CompanyID", http.StatusConflict, false},
+		{"PostgreSQL FK referenciada", &pgconn.PgError{Code: "23503", Detail: `Key (id)=(1) is still referenced from table "orders".`}, ConstraintForeignKey, "", http.StatusConflict, true},
+		{"PostgreSQL not null", &pgconn.PgError{Code: "23502", ColumnName: "name"}, ConstraintNotNull, "Name", http.StatusBadRequest, false},
+		{"PostgreSQL valor longo", &pgconn.PgError{Code: "22001", Message: "value too long for type character varying(10)"}, ConstraintValueTooLong, "", http.StatusBadRequest, false},
+		{"MySQL unique", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.com' for key 'customers.email'"}, ConstraintUnique, "Email", http.StatusConflict, false},
+		{"MySQL FK filho", &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`db`.`customers`, CONSTRAINT `fk_customers_company` FOREIGN KEY (`company_id`) REFERENCES `companies` (`id`))"}, ConstraintForeignKey, "CompanyID", http.StatusConflict, false},
+		{"MySQL FK referenciada", &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails (`db`.`orders`, CONSTRAINT `fk_orders_customer` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`))"}, ConstraintForeignKey, "", http.StatusConflict, true},
+		{"MySQL not null", &mysql.MySQLError{Number: 1048, Message: "Column 'name' cannot be null"}, ConstraintNotNull, "Name", http.StatusBadRequest, false},
+		{"MySQL valor longo", &mysql.MySQLError{Number: 1406, Message: "Data too long for column 'name' at row 1"}, ConstraintValueTooLong, "Name", http.StatusBadRequest, false},
+		{"Oracle unique", errors.New("ORA-00001: unique constraint (APP.UK_CUSTOMERS_EMAIL) violated"), ConstraintUnique, "Email", http.StatusConflict, false},
+		{"Oracle FK referenciada", errors.New("ORA-02292: integrity constraint (APP.FK_ORDERS_CUSTOMER) violated - child record found"), ConstraintForeignKey, "", http.StatusConflict, true},
+		{"Oracle not null", errors.New(`ORA-01400: cannot insert NULL into ("APP"."CUSTOMERS"."NAME")`), ConstraintNotNull, "Name", http.StatusBadRequest, false},
+		{"Oracle valor longo", errors.New(`ORA-12899: value too large for column "APP"."CUSTOMERS"."NAME" (actual: 20, maximum: 10)`), ConstraintValueTooLong, "Name", http.StatusBadRequest, false},
+		{"SQLite unique", errors.New("constraint failed: UNIQUE constraint failed: customers.email (2067)"), ConstraintUnique, "Email", http.StatusConflict, false},
+		{"SQLite not null", errors.New("constraint failed: NOT NULL constraint failed: customers.name (1299)"), ConstraintNotNull, "Name", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("failed to execute insert: %w", TranslateDatabaseError(tt.err, constraintTestMetadata))
+
+			violation, ok := constraintViolation(err)
+			require.True(t, ok)
+			assert.Equal(t, tt.kind, violation.Kind)
+			assert.Equal(t, tt.property, violation.Property)
+			assert.Equal(t, tt.status, violation.StatusCode())
+			assert.Equal(t, tt.referenced, violation.Referenced)
+			assert.True(t, errors.Is(err, tt.err))
+
+			odataErr := violation.ODataError()
+			assert.Equal(t, tt.property, odataErr.Target)
+			assert.NotContains(t, odataErr.Message, "constraint failed")
+			assert.NotContains(t, odataErr.Message, "ORA-")
+		})
+	}
+
+	t.Run("Outros erros não são alterados", func(t *testing.T) {
+		original := errors.New("connection refused")
+		assert.Same(t, original, TranslateDatabaseError(original, constraintTestMetadata))
+		assert.Nil(t, TranslateDatabaseError(nil, constraintTestMetadata))
+	})
+}
+
+func TestHandleCreateEntity_ConstraintViolation(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE customers (id INTEGER PRIMARY KEY, email TEXT UNIQUE, name TEXT NOT NULL, company_id INTEGER)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO customers (id, email, name) VALUES (1, 'ana@example.com', 'Ana')")
+	require.NoError(t, err)
+
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+	provider := NewMySQLProvider(db)
+	server := &Server{
+		provider:     provider,
+		entities:     make(map[string]EntityService),
+		logger:       logger,
+		config:       DefaultServerConfig(),
+		eventManager: NewEntityEventManager(logger),
+	}
+	service := NewBaseEntityService(provider, constraintTestMetadata, server)
+	server.entities["Customers"] = service
+
+	app := fiber.New()
+	app.Post("/odata/Customers", func(c fiber.Ctx) error {
+		return server.handleCreateEntity(c, service)
+	})
+
+	send := func(body string) (*http.Response, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/odata/Customers", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp, decodeODataError(t, resp)
+	}
+
+	t.Run("Unique retorna 409 com a propriedade", func(t *testing.T) {
+		resp, odataErr := send(`{"id":2,"email":"ana@example.com","name":"Outra"}`)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, "UniqueViolation", odataErr["code"])
+		assert.Equal(t, "Email", odataErr["target"])
+		assert.NotContains(t, odataErr["message"], "UNIQUE constraint failed")
+	})
+
+	t.Run("Not null retorna 400 com a propriedade", func(t *testing.T) {
+		resp, odataErr := send(`{"id":3,"email":"bia@example.com","name":null}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "NotNullViolation", odataErr["code"])
+		assert.Equal(t, "Name", odataErr["target"])
+	})
+
+	t.Run("Batch usa o mesmo mapeamento", func(t *testing.T) {
+		processor := NewBatchProcessor(server)
+		tx, err := db.BeginTx(context.Background(), nil)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		resp, err := processor.executeCreate(context.Background(), tx, service, &BatchHTTPOperation{
+			Method: "POST",
+			URL:    "/odata/Customers",
+			Body:   []byte(`{"id":4,"email":"ana@example.com","name":"Ana"}`),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		var payload ODataErrorResponse
+		require.NoError(t, json.Unmarshal(resp.Body, &payload))
+		assert.Equal(t, "Email", payload.Error.Target)
+	})
+}
@@ -0,0 +1,221 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// =======================================================================================
+// CONFIGURAÇÃO DA SESSÃO DO BANCO POR REQUISIÇÃO
+// =======================================================================================
+
+// dbSessionResetTimeout limita o Reset da sessão antes da conexão voltar ao pool
+const dbSessionResetTimeout = 5 * time.Second
+
+// SessionExecutor executa os comandos de preparação da sessão (*sql.Conn da requisição)
+type SessionExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// SessionInitializer executa comandos na conexão da requisição antes das suas queries (ex:
+// SELECT set_config('app.tenant_id', $1, false) no PostgreSQL ou DBMS_SESSION.SET_CONTEXT
+// no Oracle). O contexto é o da requisição: GetUserFromContext e GetTenantFromContext
+// retornam o usuário e o tenant
+type SessionInitializer func(ctx context.Context, session SessionExecutor) error
+
+// SessionConfig configura a sessão do banco usada pelas requisições, permitindo segurança
+// por linha aplicada pelo próprio banco (RLS do PostgreSQL, VPD do Oracle) com o pool de
+// conexões
+type SessionConfig struct {
+	// Initialize prepara a sessão na primeira query da requisição. Um erro aborta a query
+	Initialize SessionInitializer
+
+	// Reset desfaz o estado da sessão ao final da requisição, antes da conexão voltar ao
+	// pool (ex: RESET app.tenant_id, DBMS_SESSION.CLEAR_CONTEXT). Se falhar, a conexão é
+	// descartada. Sem Reset, a conexão é descartada ao final de cada requisição
+	Reset SessionInitializer
+}
+
+// SetSessionConfig define a preparação da sessão do banco das requisições. As queries de
+// cada requisição passam a usar uma única conexão do pool, preparada por Initialize
+func (p *BaseProvider) SetSessionConfig(config *SessionConfig) {
+	p.session = config
+}
+
+// GetSessionConfig retorna a preparação da sessão do banco (nil se não configurada)
+func (p *BaseProvider) GetSessionConfig() *SessionConfig {
+	return p.session
+}
+
+// sessionConfigProvider é implementado pelos providers com SessionConfig (BaseProvider)
+type sessionConfigProvider interface {
+	GetSessionConfig() *SessionConfig
+}
+
+// sqlQuerier é a interface comum de *sql.DB e *sql.Conn usada na execução das queries
+type sqlQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// dbSession guarda as conexões dedicadas de uma requisição (uma por pool), obtidas e
+// preparadas na primeira query
+type dbSession struct {
+	mu    sync.Mutex
+	conns map[*sql.DB]*sessionConn
+}
+
+// sessionConn é uma conexão dedicada e a configuração que a preparou
+type sessionConn struct {
+	conn   *sql.Conn
+	config *SessionConfig
+}
+
+// dbSessionKey é a chave da dbSession no contexto da requisição
+type dbSessionKey struct{}
+
+// GetUserFromContext retorna o usuário da requisição associada ao contexto (nil se não houver)
+func GetUserFromContext(ctx context.Context) *UserIdentity {
+	return contextUser(ctx)
+}
+
+// GetTenantFromContext retorna o tenant da requisição associada ao contexto ("" se não houver)
+func GetTenantFromContext(ctx context.Context) string {
+	return contextTenant(ctx)
+}
+
+// withDBSession associa ao contexto uma sessão do banco, liberada pela função retornada
+func withDBSession(ctx context.Context) (context.Context, func()) {
+	session := &dbSession{}
+	return context.WithValue(ctx, dbSessionKey{}, session), session.release
+}
+
+// sessionQuerier retorna onde executar as queries do provider: a transação do contexto
+// (WithTransaction), a conexão dedicada da requisição, quando o provider tem SessionConfig,
+// ou o pool
+func sessionQuerier(ctx context.Context, provider DatabaseProvider, db *sql.DB) (sqlQuerier, error) {
+	if current := contextTransaction(ctx); current != nil {
+		return current.querier(db)
+	}
+	config := providerSessionConfig(provider)
+	session, ok := ctx.Value(dbSessionKey{}).(*dbSession)
+	if config == nil || !ok {
+		return db, nil
+	}
+	return session.conn(ctx, db, config)
+}
+
+// beginSessionTx inicia uma transação na conexão dedicada da requisição ou no provider.
+// Dentro de WithTransaction, abre um savepoint na transação do contexto
+func beginSessionTx(ctx context.Context, provider DatabaseProvider, opts *sql.TxOptions) (*scopedTx, error) {
+	if current := contextTransaction(ctx); current != nil {
+		return current.savepoint(ctx, provider)
+	}
+
+	var tx *sql.Tx
+	var err error
+	if providerSessionConfig(provider) == nil {
+		tx, err = provider.BeginTx(ctx, opts)
+	} else {
+		db := provider.GetConnection()
+		if db == nil {
+			return nil, fmt.Errorf("database connection is nil")
+		}
+		var querier sqlQuerier
+		if querier, err = sessionQuerier(ctx, provider, db); err != nil {
+			return nil, err
+		}
+		tx, err = querier.BeginTx(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &scopedTx{Tx: tx, ctx: ctx}, nil
+}
+
+// hasDBSession indica se as queries do provider nesta requisição compartilham uma conexão
+// dedicada ou a transação do contexto (e, portanto, não podem rodar em paralelo)
+func hasDBSession(ctx context.Context, provider DatabaseProvider) bool {
+	if contextTransaction(ctx) != nil {
+		return true
+	}
+	_, ok := ctx.Value(dbSessionKey{}).(*dbSession)
+	return ok && providerSessionConfig(provider) != nil
+}
+
+// providerSessionConfig retorna a SessionConfig do provider (nil sem Initialize)
+func providerSessionConfig(provider DatabaseProvider) *SessionConfig {
+	if provider == nil {
+		return nil
+	}
+	sessionProvider, ok := provider.(sessionConfigProvider)
+	if !ok {
+		return nil
+	}
+	config := sessionProvider.GetSessionConfig()
+	if config == nil || config.Initialize == nil {
+		return nil
+	}
+	return config
+}
+
+// conn retorna a conexão dedicada do pool, obtendo-a e executando Initialize na primeira vez
+func (s *dbSession) conn(ctx context.Context, db *sql.DB, config *SessionConfig) (*sql.Conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.conns[db]; ok {
+		return existing.conn, nil
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Initialize(ctx, conn); err != nil {
+		discardConn(conn)
+		return nil, fmt.Errorf("failed to initialize database session: %w", err)
+	}
+
+	if s.conns == nil {
+		s.conns = make(map[*sql.DB]*sessionConn)
+	}
+	s.conns[db] = &sessionConn{conn: conn, config: config}
+	return conn, nil
+}
+
+// release executa o Reset das conexões da requisição e as devolve ao pool
+func (s *dbSession) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, session := range s.conns {
+		if session.config.Reset == nil {
+			discardConn(session.conn)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), dbSessionResetTimeout)
+		err := session.config.Reset(ctx, session.conn)
+		cancel()
+		if err != nil {
+			log.Printf("⚠️ Falha ao restaurar a sessão do banco, conexão descartada: %v", err)
+			discardConn(session.conn)
+			continue
+		}
+		session.conn.Close()
+	}
+	s.conns = nil
+}
+
+// discardConn fecha a conexão sem devolvê-la ao pool (o estado da sessão é desconhecido)
+func discardConn(conn *sql.Conn) {
+	conn.Raw(func(any) error { return driver.ErrBadConn })
+	conn.Close()
+}
@@ -0,0 +1,108 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_DBSession(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	db := server.provider.GetConnection()
+	_, err := db.Exec("INSERT INTO orders (id, customer_id, status) VALUES (3, 2, 'open')")
+	require.NoError(t, err)
+
+	// A view temporária (por conexão) simula a segurança por linha aplicada pelo banco
+	var initialized, reset atomic.Int32
+	server.provider.(*MySQLProvider).SetSessionConfig(&SessionConfig{
+		Initialize: func(ctx context.Context, session SessionExecutor) error {
+			initialized.Add(1)
+			customer, err := strconv.Atoi(GetTenantFromContext(ctx))
+			if err != nil {
+				return fmt.Errorf("invalid tenant")
+			}
+			_, err = session.ExecContext(ctx, fmt.Sprintf("CREATE TEMP VIEW orders AS SELECT * FROM main.orders WHERE customer_id = %d", customer))
+			return err
+		},
+		Reset: func(ctx context.Context, session SessionExecutor) error {
+			reset.Add(1)
+			_, err := session.ExecContext(ctx, "DROP VIEW IF EXISTS temp.orders")
+			return err
+		},
+	})
+	server.router = fiber.New()
+	server.router.Use(func(c fiber.Ctx) error {
+		c.Locals(TenantContextKey, c.Get("X-Customer"))
+		return c.Next()
+	})
+	server.setupEntityRoutes("Orders")
+
+	get := func(customer, target string) (int, map[string]any) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("X-Customer", customer)
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body map[string]any
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	status, body := get("2", "/odata/Orders?$count=true&$expand=Items")
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, float64(1), body["@odata.count"])
+	require.Len(t, body["value"], 1)
+	assert.Equal(t, float64(3), body["value"].([]any)[0].(map[string]any)["id"])
+
+	status, body = get("1", "/odata/Orders?$expand=Items,Customer&$orderby=id")
+	require.Equal(t, http.StatusOK, status, body)
+	require.Len(t, body["value"], 2, "as navegações rodam em série na conexão da sessão")
+	assert.Len(t, body["value"].([]any)[0].(map[string]any)["Items"], 2)
+
+	assert.Equal(t, int32(2), initialized.Load(), "uma preparação por requisição")
+	assert.Equal(t, int32(2), reset.Load())
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM orders").Scan(&count))
+	assert.Equal(t, 3, count, "o Reset remove o estado antes da conexão voltar ao pool")
+
+	status, _ = get("", "/odata/Orders")
+	assert.Equal(t, http.StatusInternalServerError, status)
+}
+
+func TestDBSession_InitializeError(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "session.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	provider := NewMySQLProvider(db)
+	provider.SetSessionConfig(&SessionConfig{
+		Initialize: func(ctx context.Context, session SessionExecutor) error {
+			return errors.New("context denied")
+		},
+	})
+
+	ctx, release := withDBSession(context.Background())
+	_, err = sessionQuerier(ctx, provider, db)
+	assert.EqualError(t, err, "failed to initialize database session: context denied")
+	release()
+
+	// Fora de uma requisição as queries usam o pool
+	querier, err := sessionQuerier(context.Background(), provider, db)
+	require.NoError(t, err)
+	assert.Same(t, db, querier)
+	assert.NoError(t, db.Ping())
+}
@@ -0,0 +1,293 @@
+package odata
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// =======================================================================================
+// EDM.DECIMAL
+// =======================================================================================
+
+// decimalPattern reconhece números decimais, com sinal, fração e expoente opcionais
+var decimalPattern = regexp.MustCompile(`^([+-])?(\d*)(?:\.(\d*))?(?:[eE]([+-]?\d+))?$`)
+
+// maxDecimalExponent limita o expoente aceito para evitar representações gigantes
+const maxDecimalExponent = 1000
+
+// Decimal representa um valor Edm.Decimal exato, sem as perdas do float64. O valor é
+// mantido na forma textual canônica (ex: "1234.50") e pode ser null
+type Decimal struct {
+	Val   string
+	Valid bool
+}
+
+// NewDecimal cria um Decimal válido a partir da representação textual
+func NewDecimal(value string) (Decimal, error) {
+	normalized, err := normalizeDecimal(value)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{Val: normalized, Valid: true}, nil
+}
+
+// NewDecimalFromFloat cria um Decimal a partir de um float64, usando a menor representação
+// que identifica o valor (0.1 vira "0.1")
+func NewDecimalFromFloat(value float64) Decimal {
+	return Decimal{Val: strconv.FormatFloat(value, 'f', -1, 64), Valid: true}
+}
+
+// NullDecimal cria um Decimal null
+func NullDecimal() Decimal {
+	return Decimal{Valid: false}
+}
+
+// String retorna a representação textual exata
+func (d Decimal) String() string {
+	if !d.Valid {
+		return ""
+	}
+	return d.Val
+}
+
+// Float64 converte o valor para float64 (com possível perda de precisão)
+func (d Decimal) Float64() float64 {
+	value, _ := strconv.ParseFloat(d.Val, 64)
+	return value
+}
+
+// Round arredonda o valor para a escala informada (metade para longe do zero). Valores
+// com menos casas decimais são mantidos como estão
+func (d Decimal) Round(scale int) Decimal {
+	if !d.Valid || scale < 0 {
+		return d
+	}
+	integer, fraction, _ := strings.Cut(strings.TrimPrefix(d.Val, "-"), ".")
+	if len(fraction) <= scale {
+		return d
+	}
+
+	unscaled, _ := new(big.Int).SetString(integer+fraction[:scale], 10)
+	if fraction[scale] >= '5' {
+		unscaled.Add(unscaled, big.NewInt(1))
+	}
+
+	digits := unscaled.String()
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	value := digits
+	if scale > 0 {
+		value = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+	if strings.HasPrefix(d.Val, "-") && strings.Trim(digits, "0") != "" {
+		value = "-" + value
+	}
+	return Decimal{Val: value, Valid: true}
+}
+
+// Scan implementa sql.Scanner
+func (d *Decimal) Scan(value interface{}) error {
+	if value == nil {
+		d.Val, d.Valid = "", false
+		return nil
+	}
+	parsed, err := toDecimal(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value implementa driver.Valuer. O valor é enviado como string para o banco converter
+// sem passar por ponto flutuante
+func (d Decimal) Value() (driver.Value, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+	return d.Val, nil
+}
+
+// MarshalJSON implementa json.Marshaler, serializando o número com os dígitos exatos
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(d.Val), nil
+}
+
+// UnmarshalJSON implementa json.Unmarshaler, aceitando números e strings ("12.50")
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if strings.EqualFold(string(data), "null") {
+		d.Val, d.Valid = "", false
+		return nil
+	}
+	raw := string(data)
+	if strings.HasPrefix(raw, `"`) {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+	}
+	parsed, err := NewDecimal(raw)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// normalizeDecimal converte a representação textual (inclusive com expoente) para a forma
+// canônica sem expoente, preservando as casas decimais informadas
+func normalizeDecimal(value string) (string, error) {
+	match := decimalPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || match[2]+match[3] == "" {
+		return "", fmt.Errorf("invalid decimal value: %s", value)
+	}
+
+	digits := match[2] + match[3]
+	point := len(match[2])
+	if match[4] != "" {
+		exponent, err := strconv.Atoi(match[4])
+		if err != nil || exponent > maxDecimalExponent || exponent < -maxDecimalExponent {
+			return "", fmt.Errorf("invalid decimal value: %s", value)
+		}
+		point += exponent
+	}
+
+	if point <= 0 {
+		digits = strings.Repeat("0", 1-point) + digits
+		point = 1
+	} else if point > len(digits) {
+		digits += strings.Repeat("0", point-len(digits))
+	}
+
+	integer := strings.TrimLeft(digits[:point], "0")
+	if integer == "" {
+		integer = "0"
+	}
+	result := integer
+	if fraction := digits[point:]; fraction != "" {
+		result += "." + fraction
+	}
+
+	if match[1] == "-" && strings.Trim(result, "0.") != "" {
+		result = "-" + result
+	}
+	return result, nil
+}
+
+// toDecimal converte os valores vindos do banco, do payload ou do $filter para Decimal
+func toDecimal(value interface{}) (Decimal, error) {
+	switch v := value.(type) {
+	case Decimal:
+		return v, nil
+	case *Decimal:
+		if v == nil {
+			return NullDecimal(), nil
+		}
+		return *v, nil
+	case string:
+		return NewDecimal(v)
+	case []byte:
+		return NewDecimal(string(v))
+	case json.Number:
+		return NewDecimal(v.String())
+	case float64:
+		return NewDecimalFromFloat(v), nil
+	case float32:
+		return NewDecimal(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case int:
+		return NewDecimal(strconv.Itoa(v))
+	case int32:
+		return NewDecimal(strconv.FormatInt(int64(v), 10))
+	case int64:
+		return NewDecimal(strconv.FormatInt(v, 10))
+	default:
+		return Decimal{}, fmt.Errorf("cannot convert %T to decimal", value)
+	}
+}
+
+// isDecimalProperty verifica se a propriedade é Edm.Decimal: tipo Decimal ou float com
+// precision definida na tag
+func isDecimalProperty(prop PropertyMetadata) bool {
+	switch prop.Type {
+	case "decimal":
+		return true
+	case "float32", "float64":
+		return prop.Precision > 0
+	}
+	return false
+}
+
+// convertToDecimal converte o valor lido do banco para Decimal, arredondado para a escala
+// da propriedade. Com DecimalAsString o valor é serializado como string JSON
+func (s *BaseEntityService) convertToDecimal(value any, prop PropertyMetadata) (any, error) {
+	decimal, err := toDecimal(value)
+	if err != nil {
+		return nil, err
+	}
+	if prop.Precision > 0 {
+		decimal = decimal.Round(prop.Scale)
+	}
+	if s.server != nil && s.server.config != nil && s.server.config.DecimalAsString {
+		return decimal.String(), nil
+	}
+	return decimal, nil
+}
+
+// normalizeDecimalValues converte os valores das propriedades Edm.Decimal do payload para
+// Decimal (aceitando números e strings), arredondados para a escala da propriedade. Valores
+// não reconhecidos seguem para a validação do provider
+func (s *BaseEntityService) normalizeDecimalValues(metadata EntityMetadata, data map[string]any) {
+	for _, prop := range metadata.Properties {
+		if !isDecimalProperty(prop) {
+			continue
+		}
+		value, exists := data[prop.Name]
+		if !exists || value == nil {
+			continue
+		}
+		decimal, err := toDecimal(value)
+		if err != nil {
+			continue
+		}
+		if prop.Precision > 0 {
+			decimal = decimal.Round(prop.Scale)
+		}
+		data[prop.Name] = decimal
+	}
+}
+
+// decimalFilterArgument converte o literal numérico do $filter comparado a uma propriedade
+// Edm.Decimal para Decimal, evitando a conversão para float64
+func decimalFilterArgument(literal *ParseNode, property *ParseNode, metadata EntityMetadata) (Decimal, bool) {
+	if literal == nil || literal.Token == nil || literal.Token.Type != int(FilterTokenNumber) ||
+		property == nil || property.Token == nil || property.Token.Type != int(FilterTokenProperty) {
+		return Decimal{}, false
+	}
+	prop := findPropertyByName(metadata, property.Token.Value)
+	if prop == nil || !isDecimalProperty(*prop) {
+		return Decimal{}, false
+	}
+	decimal, err := NewDecimal(strings.TrimRight(literal.Token.Value, "mMdDfF"))
+	if err != nil {
+		return Decimal{}, false
+	}
+	return decimal, true
+}
+
+// buildOperandNamed constrói o operando de uma comparação; literais numéricos comparados a
+// propriedades Edm.Decimal são enviados como Decimal exato
+func (qb *QueryBuilder) buildOperandNamed(ctx context.Context, operand, other *ParseNode, metadata EntityMetadata, namedArgs *NamedArgs) (string, error) {
+	if decimal, ok := decimalFilterArgument(operand, other, metadata); ok {
+		return namedArgs.AddArg(decimal), nil
+	}
+	return qb.buildNodeExpressionNamed(ctx, operand, metadata, namedArgs)
+}
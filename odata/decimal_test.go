@@ -0,0 +1,229 @@
+package odata
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDecimal(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"12.50", "12.50"},
+		{"+007", "7"},
+		{".5", "0.5"},
+		{"-0.00", "0.00"},
+		{"1.5e2", "150"},
+		{"1.25E-3", "0.00125"},
+		{"12345678901234567890.123456789", "12345678901234567890.123456789"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			decimal, err := NewDecimal(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, decimal.String())
+		})
+	}
+
+	for _, invalid := range []string{"", "abc", "1.2.3", "1e5000"} {
+		_, err := NewDecimal(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestDecimal_Round(t *testing.T) {
+	tests := []struct {
+		value    string
+		scale    int
+		expected string
+	}{
+		{"10.125", 2, "10.13"},
+		{"10.124", 2, "10.12"},
+		{"9.995", 2, "10.00"},
+		{"-2.5", 0, "-3"},
+		{"0.005", 2, "0.01"},
+		{"-0.001", 2, "0.00"},
+		{"1.5", 2, "1.5"},
+	}
+
+	for _, tt := range tests {
+		decimal, err := NewDecimal(tt.value)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, decimal.Round(tt.scale).String(), "%s com escala %d", tt.value, tt.scale)
+	}
+}
+
+func TestDecimal_JSONAndSQL(t *testing.T) {
+	decimal, err := NewDecimal("12345678901234567.89")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(map[string]any{"value": decimal, "empty": NullDecimal()})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value":12345678901234567.89,"empty":null}`, string(data))
+	assert.Contains(t, string(data), "12345678901234567.89", "dígitos serializados sem perda")
+
+	var fromNumber, fromString Decimal
+	require.NoError(t, json.Unmarshal([]byte(`0.1`), &fromNumber))
+	require.NoError(t, json.Unmarshal([]byte(`"0.10"`), &fromString))
+	assert.Equal(t, "0.1", fromNumber.String())
+	assert.Equal(t, "0.10", fromString.String())
+
+	value, err := decimal.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "12345678901234567.89", value)
+
+	var scanned Decimal
+	require.NoError(t, scanned.Scan([]byte("99.90")))
+	assert.Equal(t, "99.90", scanned.String())
+	require.NoError(t, scanned.Scan(nil))
+	assert.False(t, scanned.Valid)
+}
+
+func TestEntityMapper_DecimalProperty(t *testing.T) {
+	type Invoice struct {
+		ID     int64   `json:"id" primaryKey:"idGenerator:auto"`
+		Total  Decimal `json:"total" prop:"precision:18; scale:2"`
+		Weight float64 `json:"weight"`
+	}
+
+	metadata, err := NewEntityMapper().MapEntity(Invoice{})
+	require.NoError(t, err)
+
+	for _, prop := range metadata.Properties {
+		switch prop.Name {
+		case "total":
+			assert.Equal(t, "decimal", prop.Type)
+			assert.True(t, isDecimalProperty(prop))
+		case "weight":
+			assert.False(t, isDecimalProperty(prop), "float sem precision continua Edm.Double")
+		}
+	}
+}
+
+func newDecimalTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := newBatchGetTestServer(t)
+	db := server.provider.GetConnection()
+	_, err := db.Exec("CREATE TABLE ledger (id INTEGER PRIMARY KEY, amount TEXT)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO ledger (id, amount) VALUES (1, '12345678901234567.89')")
+	require.NoError(t, err)
+
+	products := server.entities["Products"].GetMetadata()
+	products.Properties[2].Precision = 10
+	products.Properties[2].Scale = 2
+	server.entities["Products"] = NewBaseEntityService(server.provider, products, server)
+
+	server.entities["Ledger"] = NewBaseEntityService(server.provider, EntityMetadata{
+		Name:      "Ledger",
+		TableName: "ledger",
+		Keys:      []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "amount", ColumnName: "amount", Type: "decimal", Precision: 20, Scale: 2},
+		},
+	}, server)
+
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+	server.setupEntityRoutes("Ledger")
+	return server
+}
+
+func TestServer_DecimalProperties(t *testing.T) {
+	server := newDecimalTestServer(t)
+
+	getBody := func(t *testing.T, path string) string {
+		t.Helper()
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	t.Run("Serializa os dígitos exatos", func(t *testing.T) {
+		body := getBody(t, "/odata/Ledger")
+		assert.Contains(t, body, `"amount":12345678901234567.89`)
+	})
+
+	t.Run("DecimalAsString serializa como string", func(t *testing.T) {
+		server.SetDecimalAsString(true)
+		defer server.SetDecimalAsString(false)
+
+		body := getBody(t, "/odata/Ledger")
+		assert.Contains(t, body, `"amount":"12345678901234567.89"`)
+	})
+
+	t.Run("Literal do filtro enviado como Decimal", func(t *testing.T) {
+		var args []any
+		server.OnQueryBuilt("Products", func(event EventArgs) error {
+			args = event.(*QueryBuiltArgs).Args
+			return nil
+		})
+
+		status, body := getComputeTestProducts(t, server, "$filter="+url.QueryEscape("price gt 49.995"))
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{"Teclado", "Monitor"}, decimalTestNames(body))
+
+		require.NotEmpty(t, args)
+		bound := args[0]
+		if named, ok := bound.(sql.NamedArg); ok {
+			bound = named.Value
+		}
+		assert.Equal(t, Decimal{Val: "49.995", Valid: true}, bound)
+	})
+
+	t.Run("Metadados Edm.Decimal com precision e scale", func(t *testing.T) {
+		metadata := server.buildMetadataJSON()
+		for _, entity := range metadata.Entities {
+			for _, prop := range entity.Properties {
+				if entity.Name == "Ledger" && prop.Name == "amount" || entity.Name == "Products" && prop.Name == "price" {
+					assert.Equal(t, "Edm.Decimal", prop.Type)
+					assert.Greater(t, prop.Precision, 0)
+					assert.Equal(t, 2, prop.Scale)
+				}
+			}
+		}
+	})
+
+	t.Run("Payload arredondado para a escala", func(t *testing.T) {
+		payload := strings.NewReader(`{"id": 2, "amount": "100.005"}`)
+		req := httptest.NewRequest(http.MethodPost, "/odata/Ledger", payload)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var stored string
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT amount FROM ledger WHERE id = 2").Scan(&stored))
+		assert.Equal(t, "100.01", stored)
+	})
+}
+
+func decimalTestNames(body map[string]any) []string {
+	var names []string
+	values, _ := body["value"].([]any)
+	for _, value := range values {
+		if entity, ok := value.(map[string]any); ok {
+			names = append(names, entity["name"].(string))
+		}
+	}
+	return names
+}
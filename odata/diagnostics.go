@@ -0,0 +1,182 @@
+package odata
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	fiberpprof "github.com/gofiber/fiber/v3/middleware/pprof"
+)
+
+// =======================================================================================
+// DIAGNÓSTICO EM TEMPO DE EXECUÇÃO (PPROF, GOROUTINES E ESTATÍSTICAS)
+// =======================================================================================
+
+// DiagnosticsConfig configura os endpoints de diagnóstico, protegidos por role de administrador
+type DiagnosticsConfig struct {
+	Enabled     bool          // Registra /admin/stats, /admin/goroutines, /admin/entities e /admin/maintenance
+	EnablePprof bool          // Registra também /debug/pprof
+	QueryDebug  bool          // Registra {RoutePrefix}/$debug/{EntitySet} (SQL gerado, sem executar)
+	AdminPrefix string        // Prefixo das rotas administrativas (padrão: /admin)
+	AdminRole   string        // Role exigida, além de usuários Admin (padrão: admin)
+	Auth        fiber.Handler // Autenticação das rotas (padrão: JWT do servidor, se configurado)
+}
+
+// DefaultDiagnosticsConfig retorna configuração padrão de diagnóstico (desabilitado)
+func DefaultDiagnosticsConfig() *DiagnosticsConfig {
+	return &DiagnosticsConfig{
+		Enabled:     false,
+		EnablePprof: false,
+		AdminPrefix: "/admin",
+		AdminRole:   "admin",
+	}
+}
+
+// setupDiagnosticsRoutes registra as rotas de diagnóstico (uma única vez)
+func (s *Server) setupDiagnosticsRoutes() {
+	config := s.config.DiagnosticsConfig
+	if config == nil || !config.Enabled || s.diagnosticsRoutes {
+		return
+	}
+	s.diagnosticsRoutes = true
+
+	prefix := config.AdminPrefix
+	if prefix == "" {
+		prefix = "/admin"
+	}
+
+	// Autenticação e autorização executam antes dos handlers de diagnóstico
+	auth := s.diagnosticsAuth(config)
+	requireAdmin := s.RequireAdmin(config.AdminRole)
+	s.router.Get(prefix+"/stats", auth, requireAdmin, s.handleAdminStats)
+	s.router.Get(prefix+"/goroutines", auth, requireAdmin, s.handleAdminGoroutines)
+	s.router.Get(prefix+"/slow-queries", auth, requireAdmin, s.handleAdminSlowQueries)
+	s.router.Get(prefix+"/entities", auth, requireAdmin, s.handleAdminEntities)
+	s.router.Patch(prefix+"/entities/:name", auth, requireAdmin, s.handleAdminEntityState)
+	s.router.Get(prefix+"/maintenance", auth, requireAdmin, s.handleAdminMaintenance)
+	s.router.Patch(prefix+"/maintenance", auth, requireAdmin, s.handleAdminSetMaintenance)
+
+	if config.EnablePprof {
+		s.router.Use("/debug/pprof", auth, requireAdmin, fiberpprof.New())
+	}
+
+	if config.QueryDebug {
+		s.router.Get(s.config.RoutePrefix+"/$debug/*", auth, requireAdmin, s.handleQueryDebug)
+	}
+}
+
+// diagnosticsAuth retorna o middleware de autenticação das rotas de diagnóstico. Sem
+// autenticação configurada nenhum usuário é identificado e o acesso é negado
+func (s *Server) diagnosticsAuth(config *DiagnosticsConfig) fiber.Handler {
+	if config.Auth != nil {
+		return config.Auth
+	}
+	if s.config.JWTConfig != nil {
+		return s.NewRouterJWTAuth()
+	}
+	return func(c fiber.Ctx) error {
+		return c.Next()
+	}
+}
+
+// RequireAdmin exige usuário autenticado com flag Admin ou com a role informada
+func (s *Server) RequireAdmin(role string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		user := GetCurrentUser(c)
+		if user == nil {
+			return s.writeODataError(c, http.StatusUnauthorized,
+				NewODataError("Unauthorized", "Authentication required"), nil)
+		}
+		if !user.Admin && (role == "" || !user.HasRole(role)) {
+			return s.writeODataError(c, http.StatusForbidden,
+				NewODataError("Forbidden", "Administrator role required"), nil)
+		}
+		return c.Next()
+	}
+}
+
+// handleAdminStats retorna estatísticas do pool de conexões, caches, eventos e runtime
+func (s *Server) handleAdminStats(c fiber.Ctx) error {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := map[string]interface{}{
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		"runtime": map[string]interface{}{
+			"go_version":     runtime.Version(),
+			"goroutines":     runtime.NumGoroutine(),
+			"heap_alloc":     mem.HeapAlloc,
+			"heap_objects":   mem.HeapObjects,
+			"total_alloc":    mem.TotalAlloc,
+			"gc_cycles":      mem.NumGC,
+			"gc_pause_total": time.Duration(mem.PauseTotalNs).String(),
+		},
+		"entities": len(s.GetEntities()),
+		"panics":   s.GetPanicCount(),
+	}
+
+	if s.provider != nil {
+		if db := s.provider.GetConnection(); db != nil {
+			dbStats := db.Stats()
+			stats["pool"] = map[string]interface{}{
+				"driver":               s.provider.GetDriverName(),
+				"max_open":             dbStats.MaxOpenConnections,
+				"open":                 dbStats.OpenConnections,
+				"in_use":               dbStats.InUse,
+				"idle":                 dbStats.Idle,
+				"wait_count":           dbStats.WaitCount,
+				"wait_duration":        dbStats.WaitDuration.String(),
+				"max_idle_closed":      dbStats.MaxIdleClosed,
+				"max_idle_time_closed": dbStats.MaxIdleTimeClosed,
+				"max_lifetime_closed":  dbStats.MaxLifetimeClosed,
+			}
+		}
+	}
+	if s.multiTenantPool != nil {
+		stats["tenants"] = s.multiTenantPool.GetAllStats()
+	}
+
+	if s.urlParser != nil {
+		normalize, validate, simple := s.urlParser.GetCacheStats()
+		stats["cache"] = map[string]interface{}{
+			"url_normalize_entries": normalize,
+			"url_validate_entries":  validate,
+			"url_simple_entries":    simple,
+		}
+	}
+
+	if s.eventManager != nil {
+		depth, capacity := s.eventManager.AsyncQueueStats()
+		stats["events"] = map[string]interface{}{
+			"async_queue_depth":    depth,
+			"async_queue_capacity": capacity,
+			"handler_panics":       s.eventManager.PanicCount(),
+		}
+	}
+
+	return c.JSON(stats)
+}
+
+// handleAdminGoroutines retorna o dump das goroutines em texto
+func (s *Server) handleAdminGoroutines(c fiber.Ctx) error {
+	c.Set("Content-Type", "text/plain; charset=utf-8")
+	return pprof.Lookup("goroutine").WriteTo(c.Response().BodyWriter(), 2)
+}
+
+// handleAdminSlowQueries retorna as queries lentas do store em memória, se configurado
+func (s *Server) handleAdminSlowQueries(c fiber.Ctx) error {
+	config := s.config.SlowQueryConfig
+	if config == nil || config.Store == nil {
+		return s.writeODataError(c, http.StatusNotFound,
+			NewODataError("NotFound", "Slow query store is not configured"), nil)
+	}
+
+	lister, ok := config.Store.(interface{ Entries() []SlowQueryEntry })
+	if !ok {
+		return s.writeODataError(c, http.StatusNotImplemented,
+			NewODataError("NotImplemented", "Slow query store does not support listing"), nil)
+	}
+	return c.JSON(fiber.Map{"value": lister.Entries()})
+}
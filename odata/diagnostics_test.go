@@ -0,0 +1,166 @@
+package odata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Diagnostics(t *testing.T) {
+	logger := log.New(&bytes.Buffer{}, "", 0)
+	server := &Server{
+		router:       fiber.New(),
+		entities:     make(map[string]EntityService),
+		urlParser:    NewURLParser(),
+		config:       DefaultServerConfig(),
+		logger:       logger,
+		eventManager: NewEntityEventManager(logger),
+	}
+
+	// Autenticação de teste: o usuário vem do header X-Test-User
+	auth := func(c fiber.Ctx) error {
+		switch c.Get("X-Test-User") {
+		case "admin":
+			c.Locals(UserContextKey, &UserIdentity{Username: "root", Roles: []string{"admin"}})
+		case "user":
+			c.Locals(UserContextKey, &UserIdentity{Username: "ana", Roles: []string{"user"}})
+		}
+		return c.Next()
+	}
+
+	server.SetDiagnostics(&DiagnosticsConfig{Enabled: true, EnablePprof: true, AdminRole: "admin", Auth: auth})
+	// Registro repetido não duplica as rotas
+	server.SetDiagnostics(server.config.DiagnosticsConfig)
+
+	get := func(path, user string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if user != "" {
+			req.Header.Set("X-Test-User", user)
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Sem usuário retorna 401", func(t *testing.T) {
+		resp := get("/admin/stats", "")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, "Unauthorized", decodeODataError(t, resp)["code"])
+	})
+
+	t.Run("Usuário sem role de administrador retorna 403", func(t *testing.T) {
+		for _, path := range []string{"/admin/stats", "/admin/goroutines", "/debug/pprof/"} {
+			resp := get(path, "user")
+			resp.Body.Close()
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode, path)
+		}
+	})
+
+	t.Run("Administrador acessa as estatísticas", func(t *testing.T) {
+		resp := get("/admin/stats", "admin")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var stats map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+		assert.Contains(t, stats, "runtime")
+		assert.Contains(t, stats, "cache")
+		events := stats["events"].(map[string]interface{})
+		assert.Equal(t, float64(0), events["async_queue_depth"])
+	})
+
+	t.Run("Administrador acessa goroutines e pprof", func(t *testing.T) {
+		resp := get("/admin/goroutines", "admin")
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(body), "goroutine")
+
+		resp = get("/debug/pprof/", "admin")
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+	t.Run("Queries lentas do store em memória", func(t *testing.T) {
+		resp := get("/admin/slow-queries", "admin")
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		store := NewMemorySlowQueryStore(10)
+		require.NoError(t, store.Save(SlowQueryEntry{EntityName: "Products", SQL: "SELECT 1"}))
+		server.SetSlowQueryConfig(&SlowQueryConfig{Enabled: true, Store: store})
+
+		resp = get("/admin/slow-queries", "admin")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body struct {
+			Value []SlowQueryEntry `json:"value"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.Len(t, body.Value, 1)
+		assert.Equal(t, "SELECT 1", body.Value[0].SQL)
+	})
+}
+
+func TestServer_AdminStatsWithConcurrentRegistration(t *testing.T) {
+	logger := log.New(&bytes.Buffer{}, "", 0)
+	server := &Server{
+		router:   fiber.New(),
+		entities: make(map[string]EntityService),
+		config:   DefaultServerConfig(),
+		logger:   logger,
+	}
+	server.SetDiagnostics(&DiagnosticsConfig{Enabled: true, AdminRole: "admin", Auth: func(c fiber.Ctx) error {
+		c.Locals(UserContextKey, &UserIdentity{Username: "root", Roles: []string{"admin"}})
+		return c.Next()
+	}})
+
+	// Registro em tempo de execução (rotas dinâmicas) concorrente com as estatísticas
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			server.mu.Lock()
+			server.entities[fmt.Sprintf("Plugin%d", i)] = NewBaseEntityService(nil, EntityMetadata{Name: "Plugin"}, server)
+			server.mu.Unlock()
+		}()
+		go func() {
+			defer wg.Done()
+			resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+
+	resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var stats map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+	assert.Equal(t, float64(8), stats["entities"])
+}
+
+func TestServer_DiagnosticsDisabledByDefault(t *testing.T) {
+	server := &Server{router: fiber.New(), config: DefaultServerConfig()}
+	server.setupDiagnosticsRoutes()
+
+	resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -57,9 +57,10 @@ func (d *DefaultDialect) SetupNodeMap() NodeMap {
 	nodeMap["hour"] = "HOUR(%s)"
 	nodeMap["minute"] = "MINUTE(%s)"
 	nodeMap["second"] = "SECOND(%s)"
-	nodeMap["now"] = "NOW()"
+	nodeMap["now"] = "CURRENT_TIMESTAMP"
 	nodeMap["date"] = "DATE(%s)"
 	nodeMap["time"] = "TIME(%s)"
+	nodeMap["totaloffsetminutes"] = "(CASE WHEN %s IS NULL THEN NULL ELSE 0 END)"
 
 	// Funções matemáticas
 	nodeMap["round"] = "ROUND(%s)"
@@ -58,9 +58,10 @@ func (d *MySQLDialect) SetupNodeMap() NodeMap {
 	nodeMap["hour"] = "HOUR(%s)"
 	nodeMap["minute"] = "MINUTE(%s)"
 	nodeMap["second"] = "SECOND(%s)"
-	nodeMap["now"] = "NOW()"
+	nodeMap["now"] = "UTC_TIMESTAMP()" // valores gravados em UTC
 	nodeMap["date"] = "DATE(%s)"
 	nodeMap["time"] = "TIME(%s)"
+	nodeMap["totaloffsetminutes"] = "(CASE WHEN %s IS NULL THEN NULL ELSE 0 END)" // DATETIME sem offset (UTC)
 
 	// Funções matemáticas
 	nodeMap["round"] = "ROUND(%s)"
@@ -58,9 +58,10 @@ func (d *OracleDialect) SetupNodeMap() NodeMap {
 	nodeMap["hour"] = "HOUR(%s)"
 	nodeMap["minute"] = "MINUTE(%s)"
 	nodeMap["second"] = "SECOND(%s)"
-	nodeMap["now"] = "SYSDATE" // Oracle usa SYSDATE
-	nodeMap["date"] = "DATE(%s)"
-	nodeMap["time"] = "TIME(%s)"
+	nodeMap["now"] = "SYS_EXTRACT_UTC(SYSTIMESTAMP)" // valores gravados em UTC
+	nodeMap["date"] = "TRUNC(%s)"
+	nodeMap["time"] = "TO_CHAR(%s, 'HH24:MI:SS')"
+	nodeMap["totaloffsetminutes"] = "(EXTRACT(TIMEZONE_HOUR FROM %[1]s) * 60 + EXTRACT(TIMEZONE_MINUTE FROM %[1]s))"
 
 	// Funções matemáticas
 	nodeMap["round"] = "ROUND(%s)"
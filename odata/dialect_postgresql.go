@@ -58,9 +58,10 @@ func (d *PostgreSQLDialect) SetupNodeMap() NodeMap {
 	nodeMap["hour"] = "HOUR(%s)"
 	nodeMap["minute"] = "MINUTE(%s)"
 	nodeMap["second"] = "SECOND(%s)"
-	nodeMap["now"] = "NOW()"
-	nodeMap["date"] = "DATE(%s)"
-	nodeMap["time"] = "TIME(%s)"
+	nodeMap["now"] = "(NOW() AT TIME ZONE 'UTC')" // valores gravados em UTC
+	nodeMap["date"] = "CAST(%s AS DATE)"
+	nodeMap["time"] = "CAST(%s AS TIME)"
+	nodeMap["totaloffsetminutes"] = "(EXTRACT(TIMEZONE FROM %s) / 60)"
 
 	// Funções matemáticas
 	nodeMap["round"] = "ROUND(%s)"
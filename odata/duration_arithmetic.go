@@ -0,0 +1,165 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// =======================================================================================
+// LITERAIS DE DURAÇÃO E ARITMÉTICA DE DATAS
+// =======================================================================================
+
+// durationLiteralPattern reconhece o valor de um literal Edm.Duration (ex: P1DT12H30M5S)
+var durationLiteralPattern = regexp.MustCompile(`^(-)?P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// parseDurationLiteral converte um literal duration'P7D' (ou apenas P7D) para o total de
+// segundos. Retorna int64 quando não há fração de segundo
+func parseDurationLiteral(value string) (interface{}, error) {
+	raw := value
+	if len(raw) > len("duration''") && strings.EqualFold(raw[:len("duration'")], "duration'") {
+		raw = strings.TrimSuffix(raw[len("duration'"):], "'")
+	}
+
+	match := durationLiteralPattern.FindStringSubmatch(raw)
+	if match == nil || raw == "P" || raw == "-P" || strings.HasSuffix(raw, "T") {
+		return nil, fmt.Errorf("invalid duration literal: %s", value)
+	}
+
+	var whole int64
+	for i, unit := range []int64{86400, 3600, 60} {
+		if match[i+2] == "" {
+			continue
+		}
+		amount, err := strconv.ParseInt(match[i+2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration literal: %s", value)
+		}
+		whole += amount * unit
+	}
+
+	sign := int64(1)
+	if match[1] == "-" {
+		sign = -1
+	}
+
+	seconds := match[5]
+	if strings.Contains(seconds, ".") {
+		fraction, err := strconv.ParseFloat(seconds, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration literal: %s", value)
+		}
+		return float64(sign) * (float64(whole) + fraction), nil
+	}
+	if seconds != "" {
+		amount, err := strconv.ParseInt(seconds, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration literal: %s", value)
+		}
+		whole += amount
+	}
+	return sign * whole, nil
+}
+
+// isDurationArithmetic verifica se o nó é uma soma/subtração entre data e duração
+// (ex: now() sub duration'P7D')
+func isDurationArithmetic(node *ParseNode) bool {
+	operator := strings.ToLower(node.Token.Value)
+	if node.Token.Type != int(FilterTokenArithmetic) || (operator != "add" && operator != "sub") || len(node.Children) != 2 {
+		return false
+	}
+	for _, child := range node.Children {
+		if child != nil && child.Token != nil && child.Token.Type == int(FilterTokenDuration) {
+			return true
+		}
+	}
+	return false
+}
+
+// durationArithmeticTemplate retorna o template do dialeto para somar/subtrair segundos de
+// uma data. O primeiro %s é a data e o segundo o total de segundos
+func (qb *QueryBuilder) durationArithmeticTemplate(operator string) string {
+	subtract := strings.EqualFold(operator, "sub")
+
+	switch qb.dialect.GetName() {
+	case "mysql":
+		if subtract {
+			return "DATE_SUB(%s, INTERVAL %s SECOND)"
+		}
+		return "DATE_ADD(%s, INTERVAL %s SECOND)"
+	case "postgresql":
+		if subtract {
+			return "(%s - (%s * INTERVAL '1 second'))"
+		}
+		return "(%s + (%s * INTERVAL '1 second'))"
+	case "oracle":
+		if subtract {
+			return "(%s - NUMTODSINTERVAL(%s, 'SECOND'))"
+		}
+		return "(%s + NUMTODSINTERVAL(%s, 'SECOND'))"
+	default:
+		if subtract {
+			return "(%s - (%s * INTERVAL '1' SECOND))"
+		}
+		return "(%s + (%s * INTERVAL '1' SECOND))"
+	}
+}
+
+// durationArithmeticOperands separa o operando de data e o literal de duração. Na subtração
+// a duração deve estar à direita (data sub duração)
+func durationArithmeticOperands(node *ParseNode) (*ParseNode, *ParseNode, error) {
+	left, right := node.Children[0], node.Children[1]
+	if right.Token.Type == int(FilterTokenDuration) {
+		if left.Token.Type == int(FilterTokenDuration) {
+			return nil, nil, fmt.Errorf("arithmetic between two durations is not supported")
+		}
+		return left, right, nil
+	}
+	if strings.EqualFold(node.Token.Value, "sub") {
+		return nil, nil, fmt.Errorf("cannot subtract a date from a duration")
+	}
+	return right, left, nil
+}
+
+// buildDurationArithmetic constrói a soma/subtração de uma duração a uma data
+func (qb *QueryBuilder) buildDurationArithmetic(ctx context.Context, node *ParseNode, metadata EntityMetadata) (string, []interface{}, error) {
+	dateNode, durationNode, err := durationArithmeticOperands(node)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dateExpr, args, err := qb.buildNodeExpression(ctx, dateNode, metadata)
+	if err != nil {
+		return "", nil, err
+	}
+
+	seconds, err := parseDurationLiteral(durationNode.Token.Value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fmt.Sprintf(qb.durationArithmeticTemplate(node.Token.Value), dateExpr, "?"), append(args, seconds), nil
+}
+
+// buildDurationArithmeticNamed constrói a soma/subtração de uma duração a uma data usando
+// argumentos nomeados
+func (qb *QueryBuilder) buildDurationArithmeticNamed(ctx context.Context, node *ParseNode, metadata EntityMetadata, namedArgs *NamedArgs) (string, error) {
+	dateNode, durationNode, err := durationArithmeticOperands(node)
+	if err != nil {
+		return "", err
+	}
+
+	dateExpr, err := qb.buildNodeExpressionNamed(ctx, dateNode, metadata, namedArgs)
+	if err != nil {
+		return "", err
+	}
+
+	seconds, err := parseDurationLiteral(durationNode.Token.Value)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(qb.durationArithmeticTemplate(node.Token.Value), dateExpr, namedArgs.AddArg(seconds)), nil
+}
@@ -0,0 +1,118 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDurationLiteral(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected interface{}
+	}{
+		{"duration'P7D'", int64(7 * 86400)},
+		{"duration'PT12H30M'", int64(12*3600 + 30*60)},
+		{"duration'P1DT2H3M4S'", int64(86400 + 2*3600 + 3*60 + 4)},
+		{"duration'-PT90S'", int64(-90)},
+		{"Duration'PT1.5S'", 1.5},
+		{"P2D", int64(2 * 86400)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			seconds, err := parseDurationLiteral(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, seconds)
+		})
+	}
+
+	for _, invalid := range []string{"duration'P'", "duration'PT'", "duration'7D'"} {
+		_, err := parseDurationLiteral(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestTokenizer_DurationAndPProperties(t *testing.T) {
+	ctx := context.Background()
+
+	parsed, err := ParseFilterString(ctx, "Price eq 10")
+	require.NoError(t, err, "propriedades iniciadas por P não devem ser lidas como duração")
+	assert.Equal(t, "Price", parsed.Tree.Children[0].Token.Value)
+
+	parsed, err = ParseFilterString(ctx, "CreatedAt ge now() sub duration'P7D'")
+	require.NoError(t, err)
+	arithmetic := parsed.Tree.Children[1]
+	assert.Equal(t, "sub", arithmetic.Token.Value)
+	assert.Equal(t, int(FilterTokenDuration), arithmetic.Children[1].Token.Type)
+}
+
+func TestQueryBuilder_DurationArithmetic(t *testing.T) {
+	ctx := context.Background()
+	metadata := EntityMetadata{
+		Name: "Orders",
+		Properties: []PropertyMetadata{
+			{Name: "CreatedAt", ColumnName: "created_at", Type: "time.Time"},
+		},
+	}
+
+	build := func(t *testing.T, dialect, filter string) (string, []interface{}) {
+		t.Helper()
+		parsed, err := ParseFilterString(ctx, filter)
+		require.NoError(t, err)
+		namedArgs := NewNamedArgs(dialect)
+		where, err := NewQueryBuilder(dialect).BuildWhereClauseNamed(ctx, parsed.Tree, metadata, namedArgs)
+		require.NoError(t, err)
+		var values []interface{}
+		for _, arg := range namedArgs.GetNamedArgs() {
+			values = append(values, arg.(sql.NamedArg).Value)
+		}
+		for i := 1; i <= len(namedArgs.pgxArgs); i++ {
+			values = append(values, namedArgs.pgxArgs[fmt.Sprintf("param%d", i)])
+		}
+		return where, values
+	}
+
+	tests := []struct {
+		dialect  string
+		expected string
+	}{
+		{"mysql", "(created_at >= DATE_SUB(UTC_TIMESTAMP(), INTERVAL :param1 SECOND))"},
+		{"postgresql", "(created_at >= ((NOW() AT TIME ZONE 'UTC') - (@param1 * INTERVAL '1 second')))"},
+		{"oracle", "(created_at >= (SYS_EXTRACT_UTC(SYSTIMESTAMP) - NUMTODSINTERVAL(:param1, 'SECOND')))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			where, values := build(t, tt.dialect, "CreatedAt ge now() sub duration'P7D'")
+			assert.Equal(t, tt.expected, where)
+			assert.Equal(t, []interface{}{int64(7 * 86400)}, values)
+		})
+	}
+
+	t.Run("duração à esquerda na soma", func(t *testing.T) {
+		where, values := build(t, "mysql", "CreatedAt lt duration'PT1H' add now()")
+		assert.Equal(t, "(created_at < DATE_ADD(UTC_TIMESTAMP(), INTERVAL :param1 SECOND))", where)
+		assert.Equal(t, []interface{}{int64(3600)}, values)
+	})
+
+	t.Run("date, time e totaloffsetminutes", func(t *testing.T) {
+		where, _ := build(t, "postgresql", "date(CreatedAt) eq 2024-01-01 and totaloffsetminutes(CreatedAt) eq 0")
+		assert.Contains(t, where, "CAST(created_at AS DATE)")
+		assert.Contains(t, where, "(EXTRACT(TIMEZONE FROM created_at) / 60)")
+
+		where, _ = build(t, "oracle", "time(CreatedAt) ge 08:00")
+		assert.Contains(t, where, "TO_CHAR(created_at, 'HH24:MI:SS')")
+	})
+
+	t.Run("duração menos data é inválido", func(t *testing.T) {
+		parsed, err := ParseFilterString(ctx, "CreatedAt ge duration'P1D' sub now()")
+		require.NoError(t, err)
+		_, err = NewQueryBuilder("mysql").BuildWhereClauseNamed(ctx, parsed.Tree, metadata, NewNamedArgs("mysql"))
+		assert.Error(t, err)
+	})
+}
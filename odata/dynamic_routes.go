@@ -0,0 +1,66 @@
+package odata
+
+import (
+	"slices"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
+)
+
+// =======================================================================================
+// REGISTRO DE ENTIDADES COM O SERVIDOR EM EXECUÇÃO
+// =======================================================================================
+
+// dynamicRouter atende as rotas das entidades registradas após o Start. A árvore de rotas do
+// Fiber não pode ser alterada durante o Listen: cada registro monta um novo roteador com as
+// rotas de todas as entidades dinâmicas e o publica atomicamente
+type dynamicRouter struct {
+	handler fasthttp.RequestHandler
+}
+
+// installDynamicRoutes adiciona ao fim da pilha do roteador o despacho para as rotas
+// dinâmicas: apenas requisições sem rota registrada antes do Start chegam até ele.
+// Chamado com s.mu bloqueado
+func (s *Server) installDynamicRoutes() {
+	if s.dynamicInstalled {
+		return
+	}
+	s.dynamicInstalled = true
+	s.router.Use(s.handleDynamicRoutes)
+}
+
+// handleDynamicRoutes encaminha a requisição ao roteador das entidades registradas após o
+// Start. Os Locals definidos pelos middlewares globais são preservados
+func (s *Server) handleDynamicRoutes(c fiber.Ctx) error {
+	router := s.dynamicRoutes.Load()
+	if router == nil {
+		return c.Next()
+	}
+	router.handler(c.RequestCtx())
+	return nil
+}
+
+// addDynamicEntityRoutes publica as rotas da entidade registrada com o servidor em execução
+func (s *Server) addDynamicEntityRoutes(entityName string) {
+	s.dynamicMu.Lock()
+	defer s.dynamicMu.Unlock()
+
+	s.mu.Lock()
+	if !slices.Contains(s.dynamicEntities, entityName) {
+		s.dynamicEntities = append(s.dynamicEntities, entityName)
+	}
+	entities := slices.Clone(s.dynamicEntities)
+	prefixes := slices.Clone(s.dynamicPrefixes)
+	s.mu.Unlock()
+
+	app := fiber.New(s.router.Config())
+	for _, prefix := range prefixes {
+		s.addPrefixRoutes(app, prefix)
+	}
+	for _, name := range entities {
+		s.addEntityRoutes(app, name)
+	}
+	s.dynamicRoutes.Store(&dynamicRouter{handler: app.Handler()})
+
+	s.logger.Printf("🔌 Rotas da entidade '%s' publicadas com o servidor em execução", entityName)
+}
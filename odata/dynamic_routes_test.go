@@ -0,0 +1,71 @@
+package odata
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTestRouter simula o Start: instala o despacho das rotas dinâmicas e marca o servidor
+// como em execução, sem abrir o listener
+func startTestRouter(server *Server) {
+	server.mu.Lock()
+	server.installDynamicRoutes()
+	server.running = true
+	server.mu.Unlock()
+}
+
+func TestServer_RegisterEntityAfterStart(t *testing.T) {
+	server, _ := newNamedProviderTestServer(t)
+	server.router = fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
+	server.setupEntityRoutes("Orders")
+	startTestRouter(server)
+
+	status := func(t *testing.T, method, target string) int {
+		t.Helper()
+		resp, err := server.router.Test(httptest.NewRequest(method, target, nil))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Sem entidades dinâmicas, a requisição segue para o 404 do roteador
+	assert.Equal(t, http.StatusNotFound, status(t, http.MethodGet, "/crm/Visitors"))
+
+	require.NoError(t, server.RegisterEntity("Visits", AnalyticsPageView{}, WithProvider("analytics"), WithEntitySetName("Visitors"), WithRoutePrefix("/crm")))
+	require.NoError(t, server.RegisterEntity("Clicks", AnalyticsPageView{}, WithProvider("analytics"), WithReadOnly(true)))
+
+	assert.Equal(t, http.StatusOK, status(t, http.MethodGet, "/odata/Orders"), "rotas do Start")
+	assert.Equal(t, http.StatusOK, status(t, http.MethodGet, "/crm/Visitors"))
+	assert.Equal(t, http.StatusOK, status(t, http.MethodGet, "/crm/$metadata"))
+	assert.Equal(t, http.StatusOK, status(t, http.MethodGet, "/odata/Clicks(1)"))
+	assert.Equal(t, http.StatusOK, status(t, http.MethodGet, "/odata/Clicks/$count"))
+	assert.Equal(t, http.StatusMethodNotAllowed, status(t, http.MethodDelete, "/odata/Clicks(1)"), "opções da entidade aplicadas")
+	assert.Equal(t, http.StatusNotFound, status(t, http.MethodGet, "/odata/Unknown"))
+
+	t.Run("registro concorrente com requisições", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, server.RegisterEntity(fmt.Sprintf("Plugin%d", i), AnalyticsPageView{}, WithProvider("analytics")))
+			}()
+			go func() {
+				defer wg.Done()
+				assert.Equal(t, http.StatusOK, status(t, http.MethodGet, "/odata/Orders?$expand=Customer"))
+			}()
+		}
+		wg.Wait()
+
+		for i := range 8 {
+			assert.Equal(t, http.StatusOK, status(t, http.MethodGet, fmt.Sprintf("/odata/Plugin%d", i)))
+		}
+	})
+}
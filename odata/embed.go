@@ -0,0 +1,90 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
+)
+
+// =======================================================================================
+// SERVIDOR EMBUTIDO EM OUTRA APLICAÇÃO (Mount / Handler)
+// =======================================================================================
+
+// Mount publica as rotas OData em uma aplicação Fiber existente, sob o prefixo informado
+// (ex: "/api" publica /api/odata/Entidade). A aplicação hospedeira controla o Listen e o
+// ciclo de vida: Start não deve ser chamado, e Shutdown apenas libera os recursos do
+// servidor (jobs, exportações, eventos assíncronos e providers)
+func (s *Server) Mount(app *fiber.App, prefix string) error {
+	if app == nil {
+		return fmt.Errorf("aplicação Fiber nil")
+	}
+	prefix = "/" + strings.Trim(prefix, "/")
+	if prefix == "/" {
+		prefix = ""
+	}
+
+	handler, err := s.embed(prefix)
+	if err != nil {
+		return err
+	}
+
+	mounted := func(c fiber.Ctx) error {
+		// As rotas do servidor não têm o prefixo: ele é removido do path e volta nas URLs
+		// geradas (requestBaseURL)
+		if prefix != "" {
+			path := strings.TrimPrefix(c.Path(), prefix)
+			if path == "" {
+				path = "/"
+			}
+			c.Path(path)
+		}
+		handler(c.RequestCtx())
+		return nil
+	}
+	if prefix == "" {
+		app.Use(mounted)
+	} else {
+		app.Use(prefix, mounted)
+	}
+
+	s.logger.Printf("🔗 Servidor OData montado em %s%s", prefix, s.config.RoutePrefix)
+	return nil
+}
+
+// Handler retorna o handler fasthttp das rotas OData, para servidores fasthttp próprios ou
+// adaptadores (net/http). Como em Mount, a aplicação hospedeira controla o ciclo de vida
+func (s *Server) Handler() (fasthttp.RequestHandler, error) {
+	return s.embed("")
+}
+
+// embed prepara o servidor para atender por outra aplicação, como o Start faria antes do
+// Listen: migrations dos módulos, auto-migração, rotas dinâmicas e jobs agendados
+func (s *Server) embed(prefix string) (fasthttp.RequestHandler, error) {
+	ctx := context.Background()
+	if err := s.runModuleMigrations(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.runAutoMigrate(ctx); err != nil {
+		return nil, fmt.Errorf("erro na auto-migração de schema: %w", err)
+	}
+
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("servidor já está rodando")
+	}
+	s.installDynamicRoutes()
+	s.running = true
+	s.embedded = true
+	s.mountPrefix = prefix
+	jobs := s.jobs
+	s.mu.Unlock()
+
+	if jobs != nil {
+		jobs.Start()
+	}
+	return s.router.Handler(), nil
+}
@@ -0,0 +1,58 @@
+package odata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Mount(t *testing.T) {
+	server, _ := newNamedProviderTestServer(t)
+	server.setupEntityRoutes("Orders")
+
+	host := fiber.New()
+	host.Get("/status", func(c fiber.Ctx) error { return c.SendString("ok") })
+	require.NoError(t, server.Mount(host, "/api/"))
+	assert.True(t, server.IsRunning())
+	assert.EqualError(t, server.Mount(fiber.New(), "/other"), "servidor já está rodando")
+
+	get := func(t *testing.T, target string) (int, map[string]any) {
+		t.Helper()
+		resp, err := host.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body map[string]any
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	status, body := get(t, "/api/odata/Orders?$orderby=id")
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body["value"], 2)
+	assert.Equal(t, "http://example.com/api/odata/$metadata#Orders", body["@odata.context"])
+
+	status, _ = get(t, "/api/odata/PageViews(1)")
+	assert.Equal(t, http.StatusOK, status)
+
+	resp, err := host.Test(httptest.NewRequest(http.MethodGet, "/status", nil))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "rotas da aplicação hospedeira")
+
+	t.Run("entidade registrada após a montagem", func(t *testing.T) {
+		require.NoError(t, server.RegisterEntity("Clicks", AnalyticsPageView{}, WithProvider("analytics")))
+		status, body := get(t, "/api/odata/Clicks")
+		require.Equal(t, http.StatusOK, status)
+		assert.Len(t, body["value"], 2)
+	})
+
+	t.Run("shutdown libera os recursos", func(t *testing.T) {
+		require.NoError(t, server.Shutdown())
+		assert.False(t, server.IsRunning())
+	})
+}
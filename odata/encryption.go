@@ -0,0 +1,392 @@
+package odata
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// =======================================================================================
+// CRIPTOGRAFIA DE PROPRIEDADES EM REPOUSO (prop:"[Encrypted]")
+// =======================================================================================
+
+// Prefixos dos valores criptografados gravados no banco: enc:<modo>:<keyID>:<base64(nonce|cifra)>
+const (
+	encryptedValuePrefix        = "enc:"
+	encryptionModeRandom        = "r"
+	encryptionModeDeterministic = "d"
+)
+
+// KeyProvider fornece as chaves AES usadas na criptografia das propriedades. Pode ser
+// implementado sobre um KMS (AWS KMS, Vault, etc.). O identificador da chave é gravado junto
+// do valor, o que permite a rotação: novos valores usam a chave atual e os antigos continuam
+// sendo lidos pela chave com que foram gravados
+type KeyProvider interface {
+	// CurrentKey retorna o identificador e a chave (16, 24 ou 32 bytes) usados nas gravações
+	CurrentKey(ctx context.Context) (keyID string, key []byte, err error)
+	// Key retorna a chave pelo identificador gravado no valor criptografado
+	Key(ctx context.Context, keyID string) ([]byte, error)
+}
+
+// StaticKeyProvider é um KeyProvider com chaves em memória (ex: lidas de variável de ambiente)
+type StaticKeyProvider struct {
+	mu        sync.RWMutex
+	currentID string
+	keys      map[string][]byte
+}
+
+// NewStaticKeyProvider cria um KeyProvider com a chave atual informada
+func NewStaticKeyProvider(keyID string, key []byte) (*StaticKeyProvider, error) {
+	provider := &StaticKeyProvider{keys: make(map[string][]byte)}
+	if err := provider.AddKey(keyID, key); err != nil {
+		return nil, err
+	}
+	provider.currentID = keyID
+	return provider, nil
+}
+
+// AddKey registra uma chave adicional (ex: chave anterior à rotação), usada apenas na leitura
+func (p *StaticKeyProvider) AddKey(keyID string, key []byte) error {
+	if keyID == "" || strings.Contains(keyID, ":") {
+		return fmt.Errorf("invalid encryption key id '%s'", keyID)
+	}
+	if err := validateEncryptionKey(key); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys[keyID] = append([]byte(nil), key...)
+	return nil
+}
+
+// CurrentKey implementa KeyProvider
+func (p *StaticKeyProvider) CurrentKey(ctx context.Context) (string, []byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.currentID, p.keys[p.currentID], nil
+}
+
+// Key implementa KeyProvider
+func (p *StaticKeyProvider) Key(ctx context.Context, keyID string) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("encryption key '%s' not found", keyID)
+	}
+	return key, nil
+}
+
+// validateEncryptionKey verifica se a chave tem tamanho válido para AES
+func validateEncryptionKey(key []byte) error {
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	default:
+		return fmt.Errorf("invalid encryption key size %d: must be 16, 24 or 32 bytes", len(key))
+	}
+}
+
+// loadEncryptionKeyProvider cria o KeyProvider da configuração por ambiente (chave em base64).
+// Sem chave, ou com chave inválida, retorna nil
+func loadEncryptionKeyProvider(keyID, encodedKey string) KeyProvider {
+	if encodedKey == "" {
+		return nil
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err == nil {
+		var provider *StaticKeyProvider
+		if provider, err = NewStaticKeyProvider(keyID, key); err == nil {
+			return provider
+		}
+	}
+	log.Printf("⚠️ Chave de criptografia inválida, propriedades Encrypted indisponíveis: %v", err)
+	return nil
+}
+
+// encryptValue criptografa o texto com AES-GCM. No modo determinístico o nonce é derivado do
+// próprio texto (HMAC-SHA256), de forma que o mesmo texto gera sempre o mesmo valor cifrado
+func encryptValue(keyID string, key []byte, plaintext string, deterministic bool) (string, error) {
+	gcm, err := newEncryptionGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	mode := encryptionModeRandom
+	if deterministic {
+		mode = encryptionModeDeterministic
+		mac := hmac.New(sha256.New, deriveNonceKey(key))
+		mac.Write([]byte(plaintext))
+		copy(nonce, mac.Sum(nil))
+	} else if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedValuePrefix + mode + ":" + keyID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptValue descriptografa um valor gravado por encryptValue
+func decryptValue(ctx context.Context, provider KeyProvider, value string) (string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(value, encryptedValuePrefix), ":", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+
+	key, err := provider.Key(ctx, parts[1])
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+
+	gcm, err := newEncryptionGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// newEncryptionGCM cria o AEAD AES-GCM para a chave
+func newEncryptionGCM(key []byte) (cipher.AEAD, error) {
+	if err := validateEncryptionKey(key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// deriveNonceKey deriva da chave de criptografia a chave do HMAC dos nonces determinísticos
+func deriveNonceKey(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("go-data:deterministic-nonce"))
+	return mac.Sum(nil)
+}
+
+// isEncryptedValue verifica se o valor lido do banco está criptografado. Valores em texto
+// puro (gravados antes da propriedade ser marcada como Encrypted) são retornados como estão
+func isEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, encryptedValuePrefix+encryptionModeRandom+":") ||
+		strings.HasPrefix(value, encryptedValuePrefix+encryptionModeDeterministic+":")
+}
+
+// encryptedColumnLength calcula o tamanho da coluna para um texto criptografado de até
+// plainLength caracteres: UTF-8 (até 4 bytes por caractere), nonce e tag do GCM em base64,
+// mais o prefixo com o identificador da chave
+func encryptedColumnLength(plainLength int) int {
+	sealed := 12 + plainLength*4 + 16
+	return (sealed+2)/3*4 + 64
+}
+
+// encryptedProperties retorna as propriedades criptografadas da entidade
+func encryptedProperties(metadata EntityMetadata) []PropertyMetadata {
+	var props []PropertyMetadata
+	for _, prop := range metadata.Properties {
+		if prop.IsEncrypted && !prop.IsNavigation {
+			props = append(props, prop)
+		}
+	}
+	return props
+}
+
+// encryptionKeyProvider retorna o KeyProvider configurado no servidor
+func (s *Server) encryptionKeyProvider() KeyProvider {
+	if s == nil || s.config == nil {
+		return nil
+	}
+	return s.config.EncryptionKeyProvider
+}
+
+// encryptPropertyValues retorna uma cópia do payload com as propriedades Encrypted
+// criptografadas. O payload original não é alterado, pois pode ser devolvido ao cliente
+func (s *Server) encryptPropertyValues(ctx context.Context, metadata EntityMetadata, data map[string]any) (map[string]any, error) {
+	props := encryptedProperties(metadata)
+	if len(props) == 0 {
+		return data, nil
+	}
+
+	encrypted := make(map[string]any, len(data))
+	for key, value := range data {
+		encrypted[key] = value
+	}
+
+	for _, prop := range props {
+		value, exists := encrypted[prop.Name]
+		if !exists || value == nil {
+			continue
+		}
+
+		provider := s.encryptionKeyProvider()
+		if provider == nil {
+			return nil, fmt.Errorf("property '%s' is encrypted but no encryption key provider is configured", prop.Name)
+		}
+		keyID, key, err := provider.CurrentKey(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get encryption key: %w", err)
+		}
+
+		ciphertext, err := encryptValue(keyID, key, fmt.Sprint(value), prop.DeterministicEncryption)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt property '%s': %w", prop.Name, err)
+		}
+		encrypted[prop.Name] = ciphertext
+	}
+
+	return encrypted, nil
+}
+
+// decryptPropertyValues descriptografa as propriedades Encrypted das entidades lidas do banco
+func (s *Server) decryptPropertyValues(ctx context.Context, metadata EntityMetadata, results []any) error {
+	props := encryptedProperties(metadata)
+	if len(props) == 0 {
+		return nil
+	}
+
+	for _, result := range results {
+		entity, ok := result.(*OrderedEntity)
+		if !ok {
+			continue
+		}
+
+		for _, prop := range props {
+			value, exists := entity.Get(prop.Name)
+			if !exists {
+				continue
+			}
+			text, ok := value.(string)
+			if !ok || !isEncryptedValue(text) {
+				continue
+			}
+
+			provider := s.encryptionKeyProvider()
+			if provider == nil {
+				return fmt.Errorf("property '%s' is encrypted but no encryption key provider is configured", prop.Name)
+			}
+			plaintext, err := decryptValue(ctx, provider, text)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt property '%s': %w", prop.Name, err)
+			}
+			entity.Set(prop.Name, plaintext)
+		}
+	}
+
+	return nil
+}
+
+// encryptFilterLiterals criptografa os literais comparados a propriedades Encrypted no
+// $filter. Só propriedades com criptografia determinística podem ser filtradas, e apenas com
+// eq, ne e in contra literais string
+func (s *Server) encryptFilterLiterals(ctx context.Context, node *ParseNode, metadata EntityMetadata) error {
+	if node == nil || node.Token == nil {
+		return nil
+	}
+
+	var prop *PropertyMetadata
+	for _, child := range node.Children {
+		if child != nil && child.Token != nil && child.Token.Type == int(FilterTokenProperty) {
+			if candidate := findPropertyByName(metadata, child.Token.Value); candidate != nil && candidate.IsEncrypted {
+				prop = candidate
+				break
+			}
+		}
+	}
+
+	if prop == nil {
+		for _, child := range node.Children {
+			if err := s.encryptFilterLiterals(ctx, child, metadata); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	operator := strings.ToLower(node.Token.Value)
+	if node.Token.Type != int(FilterTokenComparison) || (operator != "eq" && operator != "ne" && operator != "in") {
+		return fmt.Errorf("property '%s' is encrypted and can only be filtered with eq, ne or in", prop.Name)
+	}
+	if !prop.DeterministicEncryption {
+		return fmt.Errorf("property '%s' is encrypted and cannot be used in $filter", prop.Name)
+	}
+
+	provider := s.encryptionKeyProvider()
+	if provider == nil {
+		return fmt.Errorf("property '%s' is encrypted but no encryption key provider is configured", prop.Name)
+	}
+	keyID, key, err := provider.CurrentKey(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get encryption key: %w", err)
+	}
+
+	var encryptLiteral func(n *ParseNode) error
+	encryptLiteral = func(n *ParseNode) error {
+		if n == nil || n.Token == nil {
+			return nil
+		}
+		switch n.Token.Type {
+		case int(FilterTokenProperty):
+			if !strings.EqualFold(n.Token.Value, prop.Name) {
+				return fmt.Errorf("property '%s' is encrypted and can only be compared to literals", prop.Name)
+			}
+			return nil
+		case int(FilterTokenNull):
+			return nil
+		case int(FilterTokenString):
+			literal := strings.Trim(n.Token.Value, "'")
+			if isEncryptedValue(literal) {
+				return nil
+			}
+			ciphertext, err := encryptValue(keyID, key, strings.ReplaceAll(literal, "''", "'"), true)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt filter value: %w", err)
+			}
+			n.Token.Value = "'" + ciphertext + "'"
+			return nil
+		default:
+			return fmt.Errorf("property '%s' is encrypted and can only be compared to string literals", prop.Name)
+		}
+	}
+
+	for _, child := range node.Children {
+		if err := encryptLiteral(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepareEncryptedFilter valida e criptografa o $filter que referencia propriedades Encrypted
+func (s *BaseEntityService) prepareEncryptedFilter(ctx context.Context, filter *GoDataFilterQuery) error {
+	if filter == nil || filter.Tree == nil || len(encryptedProperties(s.metadata)) == 0 {
+		return nil
+	}
+	if err := s.server.encryptFilterLiterals(ctx, filter.Tree, s.metadata); err != nil {
+		return NewODataError("InvalidFilter", err.Error()).
+			WithStatus(http.StatusBadRequest).
+			WithTarget("$filter")
+	}
+	return nil
+}
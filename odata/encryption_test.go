@@ -0,0 +1,223 @@
+package odata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptValue(t *testing.T) {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{1}, 32)
+	provider, err := NewStaticKeyProvider("k1", key)
+	require.NoError(t, err)
+
+	t.Run("Aleatória gera valores diferentes", func(t *testing.T) {
+		first, err := encryptValue("k1", key, "segredo", false)
+		require.NoError(t, err)
+		second, err := encryptValue("k1", key, "segredo", false)
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+		assert.True(t, strings.HasPrefix(first, "enc:r:k1:"))
+
+		plaintext, err := decryptValue(ctx, provider, first)
+		require.NoError(t, err)
+		assert.Equal(t, "segredo", plaintext)
+	})
+
+	t.Run("Determinística gera o mesmo valor", func(t *testing.T) {
+		first, err := encryptValue("k1", key, "segredo", true)
+		require.NoError(t, err)
+		second, err := encryptValue("k1", key, "segredo", true)
+		require.NoError(t, err)
+		other, err := encryptValue("k1", key, "outro", true)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+		assert.NotEqual(t, first, other)
+
+		plaintext, err := decryptValue(ctx, provider, first)
+		require.NoError(t, err)
+		assert.Equal(t, "segredo", plaintext)
+	})
+
+	t.Run("Rotação mantém a leitura com a chave anterior", func(t *testing.T) {
+		old, err := encryptValue("k1", key, "antigo", false)
+		require.NoError(t, err)
+
+		rotated, err := NewStaticKeyProvider("k2", bytes.Repeat([]byte{2}, 32))
+		require.NoError(t, err)
+		require.NoError(t, rotated.AddKey("k1", key))
+
+		plaintext, err := decryptValue(ctx, rotated, old)
+		require.NoError(t, err)
+		assert.Equal(t, "antigo", plaintext)
+
+		_, err = decryptValue(ctx, provider, strings.Replace(old, "k1", "k9", 1))
+		assert.Error(t, err, "chave desconhecida")
+	})
+
+	t.Run("Valida as chaves", func(t *testing.T) {
+		_, err := NewStaticKeyProvider("k1", []byte("curta"))
+		assert.Error(t, err)
+		_, err = NewStaticKeyProvider("a:b", key)
+		assert.Error(t, err)
+	})
+}
+
+func TestEntityMapper_EncryptedProperty(t *testing.T) {
+	type Customer struct {
+		ID    int64  `json:"id" primaryKey:"idGenerator:none"`
+		Email string `json:"email" prop:"[Encrypted]; encryption:deterministic; length:100"`
+		Notes string `json:"notes" prop:"[Encrypted]"`
+	}
+
+	metadata, err := NewEntityMapper().MapEntity(Customer{})
+	require.NoError(t, err)
+
+	email := findPropertyByName(metadata, "email")
+	notes := findPropertyByName(metadata, "notes")
+	require.NotNil(t, email)
+	require.NotNil(t, notes)
+	assert.True(t, email.IsEncrypted)
+	assert.True(t, email.DeterministicEncryption)
+	assert.Equal(t, 100, email.MaxLength)
+	assert.True(t, notes.IsEncrypted)
+	assert.False(t, notes.DeterministicEncryption)
+}
+
+func newEncryptionTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := newBatchGetTestServer(t)
+	_, err := server.provider.GetConnection().Exec("CREATE TABLE customers (id INTEGER PRIMARY KEY, email TEXT, notes TEXT)")
+	require.NoError(t, err)
+
+	provider, err := NewStaticKeyProvider("k1", bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	server.SetEncryptionKeyProvider(provider)
+
+	server.entities["Customers"] = NewBaseEntityService(server.provider, EntityMetadata{
+		Name:      "Customers",
+		TableName: "customers",
+		Keys:      []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "email", ColumnName: "email", Type: "string", IsEncrypted: true, DeterministicEncryption: true},
+			{Name: "notes", ColumnName: "notes", Type: "string", IsNullable: true, IsEncrypted: true},
+		},
+	}, server)
+
+	server.router = fiber.New()
+	server.setupEntityRoutes("Customers")
+	return server
+}
+
+func TestServer_EncryptedProperties(t *testing.T) {
+	server := newEncryptionTestServer(t)
+
+	getCustomers := func(t *testing.T, query string) (int, map[string]any) {
+		t.Helper()
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Customers?"+query, nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body
+	}
+
+	for _, payload := range []string{
+		`{"id": 1, "email": "ana@example.com", "notes": "cliente VIP"}`,
+		`{"id": 2, "email": "bruno@example.com", "notes": null}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/odata/Customers", strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	t.Run("Grava o valor criptografado", func(t *testing.T) {
+		var email, notes string
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT email, notes FROM customers WHERE id = 1").Scan(&email, &notes))
+		assert.True(t, strings.HasPrefix(email, "enc:d:k1:"))
+		assert.True(t, strings.HasPrefix(notes, "enc:r:k1:"))
+		assert.NotContains(t, email, "ana@example.com")
+	})
+
+	t.Run("Descriptografa na leitura", func(t *testing.T) {
+		status, body := getCustomers(t, "$orderby=id")
+		require.Equal(t, http.StatusOK, status)
+
+		values := body["value"].([]any)
+		require.Len(t, values, 2)
+		assert.Equal(t, "ana@example.com", values[0].(map[string]any)["email"])
+		assert.Equal(t, "cliente VIP", values[0].(map[string]any)["notes"])
+		assert.Nil(t, values[1].(map[string]any)["notes"])
+	})
+
+	t.Run("Filtro eq e in com criptografia determinística", func(t *testing.T) {
+		status, body := getCustomers(t, "$count=true&$filter="+url.QueryEscape("email eq 'bruno@example.com'"))
+		require.Equal(t, http.StatusOK, status)
+		values := body["value"].([]any)
+		require.Len(t, values, 1)
+		assert.Equal(t, float64(2), values[0].(map[string]any)["id"])
+		assert.Equal(t, float64(1), body["@odata.count"])
+
+		status, body = getCustomers(t, "$filter="+url.QueryEscape("email in ('ana@example.com', 'x@example.com')"))
+		require.Equal(t, http.StatusOK, status)
+		assert.Len(t, body["value"].([]any), 1)
+	})
+
+	t.Run("Rejeita filtros não suportados", func(t *testing.T) {
+		for _, filter := range []string{
+			"contains(email, 'ana')",
+			"email gt 'a'",
+			"notes eq 'cliente VIP'",
+		} {
+			status, _ := getCustomers(t, "$filter="+url.QueryEscape(filter))
+			assert.Equal(t, http.StatusBadRequest, status, filter)
+		}
+	})
+
+	t.Run("Sem provedor de chaves falha a gravação", func(t *testing.T) {
+		provider := server.encryptionKeyProvider()
+		server.SetEncryptionKeyProvider(nil)
+		defer server.SetEncryptionKeyProvider(provider)
+
+		_, err := server.entities["Customers"].Create(context.Background(), map[string]any{"id": int64(3), "email": "c@example.com"})
+		assert.Error(t, err)
+	})
+}
+
+func TestServer_EncryptedNavigationFilter(t *testing.T) {
+	server := newNavigationTestServer(t)
+	updateNavigationCategories(server, func(metadata *EntityMetadata) {
+		for i := range metadata.Properties {
+			if metadata.Properties[i].Name == "name" {
+				metadata.Properties[i].IsEncrypted = true
+				metadata.Properties[i].DeterministicEncryption = true
+			}
+		}
+	})
+
+	for option, value := range map[string]string{
+		"$filter":  "Category/name eq 'Displays'",
+		"$orderby": "Category/name desc",
+	} {
+		status, body := getComputeTestProducts(t, server, option+"="+url.QueryEscape(value))
+		if assert.Equal(t, http.StatusBadRequest, status, option) {
+			assert.Equal(t, option, body["error"].(map[string]any)["target"], option)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// AUTORIZAÇÃO POR ENTIDADE
+// =======================================================================================
+
+// EntityAuthorizer autoriza uma operação na entidade. É chamado nos handlers da entidade, em
+// cada operação de $batch e em cada entidade relacionada alterada por um PATCH hierárquico,
+// caminhos que não passam pelos middlewares da entidade. Retorne nil para permitir; um erro
+// recusa a operação com 403 (use *ODataError com Status para outro status HTTP)
+type EntityAuthorizer func(ctx context.Context, request *AuthorizationRequest) error
+
+// AuthorizationRequest descreve a operação a autorizar
+type AuthorizationRequest struct {
+	EntityName     string                 // Nome de registro da entidade
+	Operation      string                 // Método HTTP equivalente: GET, POST, PUT, PATCH ou DELETE
+	User           *UserIdentity          // Usuário autenticado (nil se anônimo)
+	Keys           map[string]interface{} // Chaves da entidade (nil em coleções e inserções)
+	NavigationPath string                 // Caminho da entidade no PATCH hierárquico (vazio na raiz)
+	Batch          bool                   // true quando a operação faz parte de um $batch
+}
+
+// WithAuthorizer adiciona callbacks de autorização à entidade, executados na ordem de registro
+func WithAuthorizer(authorizers ...EntityAuthorizer) EntityOption {
+	return func(config *EntityConfig) {
+		config.Authorizers = append(config.Authorizers, authorizers...)
+	}
+}
+
+// authorizeEntity recusa entidades desativadas em tempo de execução, aplica o ReadOnly e as Permissions da entidade e executa os callbacks de
+// autorização. O erro retornado é sempre um *ODataError com o status HTTP definido
+func (s *Server) authorizeEntity(ctx context.Context, request *AuthorizationRequest) error {
+	if err := s.checkEntityAvailability(request.EntityName, request.Operation); err != nil {
+		return err
+	}
+	if authConfig, ok := s.GetEntityAuth(request.EntityName); ok {
+		if authConfig.ReadOnly && request.Operation != fiber.MethodGet {
+			return NewODataError("Forbidden", "Entidade "+request.EntityName+" é apenas leitura").WithStatus(http.StatusForbidden)
+		}
+		if !operationPermitted(authConfig.Permissions, request.Operation) {
+			return NewODataError("Forbidden", fmt.Sprintf("Operação %s não permitida na entidade %s", request.Operation, request.EntityName)).WithStatus(http.StatusForbidden)
+		}
+	}
+
+	for _, authorize := range s.entityAuthorizersFor(request.EntityName) {
+		if err := authorize(ctx, request); err != nil {
+			return authorizationError(err)
+		}
+	}
+	return nil
+}
+
+// entityAuthorizersFor retorna os callbacks de autorização da entidade
+func (s *Server) entityAuthorizersFor(entityName string) []EntityAuthorizer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.entityAuthorizers[entityName]
+}
+
+// hasEntityAuthorizers verifica se a entidade possui callbacks de autorização
+func (s *Server) hasEntityAuthorizers(entityName string) bool {
+	return len(s.entityAuthorizersFor(entityName)) > 0
+}
+
+// authorizeRequest verifica o modo de manutenção e autoriza a requisição HTTP direta à entidade
+func (s *Server) authorizeRequest(c fiber.Ctx, entityName string, keys map[string]interface{}) error {
+	operation := c.Method()
+	if operation == fiber.MethodHead {
+		operation = fiber.MethodGet
+	}
+	var err error
+	if maintenanceErr := s.checkMaintenance(c, operation); maintenanceErr != nil {
+		err = maintenanceErr
+	} else {
+		err = s.authorizeEntity(context.WithValue(c.Context(), FiberContextKey, c), &AuthorizationRequest{
+			EntityName: entityName,
+			Operation:  operation,
+			User:       GetCurrentUser(c),
+			Keys:       keys,
+		})
+	}
+	for header, value := range s.maintenanceHeaders(err) {
+		c.Set(header, value)
+	}
+	return err
+}
+
+// operationPermitted verifica se a operação consta nas Permissions (vazio permite todas)
+func operationPermitted(permissions []string, operation string) bool {
+	if len(permissions) == 0 {
+		return true
+	}
+	for _, permission := range permissions {
+		if permission == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizationError converte a recusa do callback em *ODataError (403 se não houver status)
+func authorizationError(err error) error {
+	var odataErr *ODataError
+	if errors.As(err, &odataErr) && odataErr.Status != 0 {
+		return odataErr
+	}
+	return NewODataError("Forbidden", err.Error()).WithStatus(http.StatusForbidden)
+}
+
+// patchOperationMethod retorna o método HTTP equivalente a uma operação do PATCH hierárquico
+func patchOperationMethod(operationType string) string {
+	switch operationType {
+	case "INSERT":
+		return fiber.MethodPost
+	case "DELETE":
+		return fiber.MethodDelete
+	default:
+		return fiber.MethodPatch
+	}
+}
+
+// registeredEntityName retorna o nome de registro do serviço (o tipo relacionado da navegação
+// pode diferir dele, ex: OrderItem para OrderItems)
+func (s *Server) registeredEntityName(service EntityService, fallback string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for name, registered := range s.entities {
+		if registered == service {
+			return name
+		}
+	}
+	return fallback
+}
+
+// contextUser retorna o usuário da requisição HTTP associada ao contexto, se houver
+func contextUser(ctx context.Context) *UserIdentity {
+	if fc, ok := ctx.Value(FiberContextKey).(fiber.Ctx); ok && fc != nil {
+		return GetCurrentUser(fc)
+	}
+	if identity, ok := ctx.Value(batchIdentityKey{}).(batchIdentity); ok {
+		return identity.user
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuthorizationTestServer registra um authorizer em OrderItems que recusa escritas de
+// usuários sem a role "estoque" e registra as requisições recebidas
+func newAuthorizationTestServer(t *testing.T) (*Server, *[]AuthorizationRequest) {
+	t.Helper()
+	server := newPatchDeltaTestServer(t)
+
+	var requests []AuthorizationRequest
+	server.entityAuthorizers = map[string][]EntityAuthorizer{
+		"OrderItems": {func(ctx context.Context, request *AuthorizationRequest) error {
+			requests = append(requests, *request)
+			if request.Operation != http.MethodGet && (request.User == nil || !request.User.HasRole("estoque")) {
+				return fmt.Errorf("%s em OrderItems requer a role estoque", request.Operation)
+			}
+			return nil
+		}},
+	}
+	return server, &requests
+}
+
+func TestServer_AuthorizeEntity(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	server.entityAuth["Customers"] = EntityAuthConfig{ReadOnly: true}
+	server.entityAuth["Orders"] = EntityAuthConfig{Permissions: []string{"GET", "PATCH"}}
+	server.entityAuthorizers = map[string][]EntityAuthorizer{
+		"Orders": {func(ctx context.Context, request *AuthorizationRequest) error {
+			if request.Keys["id"] == int64(2) {
+				return NewODataError("NotFound", "Pedido não encontrado").WithStatus(http.StatusNotFound)
+			}
+			return nil
+		}},
+	}
+	ctx := context.Background()
+
+	var odataErr *ODataError
+	err := server.authorizeEntity(ctx, &AuthorizationRequest{EntityName: "Customers", Operation: "DELETE"})
+	require.True(t, errors.As(err, &odataErr))
+	assert.Equal(t, http.StatusForbidden, odataErr.Status)
+	assert.NoError(t, server.authorizeEntity(ctx, &AuthorizationRequest{EntityName: "Customers", Operation: "GET"}))
+
+	err = server.authorizeEntity(ctx, &AuthorizationRequest{EntityName: "Orders", Operation: "POST"})
+	require.True(t, errors.As(err, &odataErr))
+	assert.Equal(t, "Operação POST não permitida na entidade Orders", odataErr.Message)
+
+	assert.NoError(t, server.authorizeEntity(ctx, &AuthorizationRequest{EntityName: "Orders", Operation: "PATCH", Keys: map[string]interface{}{"id": int64(1)}}))
+	err = server.authorizeEntity(ctx, &AuthorizationRequest{EntityName: "Orders", Operation: "PATCH", Keys: map[string]interface{}{"id": int64(2)}})
+	require.True(t, errors.As(err, &odataErr))
+	assert.Equal(t, http.StatusNotFound, odataErr.Status, "status do *ODataError é mantido")
+}
+
+func TestServer_Authorizer_DirectHandlers(t *testing.T) {
+	server, requests := newAuthorizationTestServer(t)
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		if roles := c.Get("X-Roles"); roles != "" {
+			c.Locals(UserContextKey, &UserIdentity{Username: "ana", Roles: []string{roles}})
+		}
+		return c.Next()
+	})
+	app.Get("/odata/OrderItems(*)", server.handleEntityById)
+	app.Delete("/odata/OrderItems(*)", server.handleEntityById)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/odata/OrderItems(1)", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, int64(1), orderItemOwner(t, server, 1))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/odata/OrderItems(1)", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req := httptest.NewRequest(http.MethodDelete, "/odata/OrderItems(1)", nil)
+	req.Header.Set("X-Roles", "estoque")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	require.Len(t, *requests, 3)
+	assert.Equal(t, AuthorizationRequest{
+		EntityName: "OrderItems",
+		Operation:  "DELETE",
+		Keys:       map[string]interface{}{"id": int64(1)},
+	}, (*requests)[0])
+	assert.Equal(t, "ana", (*requests)[2].User.Username)
+}
+
+func TestBatchProcessor_Authorizer(t *testing.T) {
+	server, requests := newAuthorizationTestServer(t)
+	processor := NewBatchProcessor(server)
+
+	t.Run("Operação recusada desfaz o changeset", func(t *testing.T) {
+		_, err := processor.executeChangeset(context.Background(), []*BatchHTTPOperation{
+			{Method: "PATCH", URL: "/odata/Orders(1)", ContentID: "1", Body: []byte(`{"status":"closed"}`)},
+			{Method: "DELETE", URL: "/odata/OrderItems(1)", ContentID: "2"},
+		}, map[string]interface{}{})
+
+		var opErr *batchOperationError
+		require.True(t, errors.As(err, &opErr))
+		assert.Equal(t, 1, opErr.Index)
+		assert.Equal(t, http.StatusForbidden, opErr.Response.StatusCode)
+		assert.Contains(t, string(opErr.Response.Body), "DELETE em OrderItems requer a role estoque")
+
+		var status string
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT status FROM orders WHERE id = 1").Scan(&status))
+		assert.Equal(t, "open", status)
+
+		last := (*requests)[len(*requests)-1]
+		assert.True(t, last.Batch)
+		assert.Equal(t, map[string]interface{}{"id": int64(1)}, last.Keys)
+	})
+
+	t.Run("Usuário da requisição $batch", func(t *testing.T) {
+		processor.user = &UserIdentity{Username: "bruno", Roles: []string{"estoque"}}
+		defer func() { processor.user = nil }()
+
+		responses, err := processor.executeChangeset(context.Background(), []*BatchHTTPOperation{
+			{Method: "DELETE", URL: "/odata/OrderItems(1)", ContentID: "1"},
+		}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, responses[0].StatusCode)
+	})
+
+	t.Run("Leitura fora de changeset", func(t *testing.T) {
+		server.entityAuth["OrderItems"] = EntityAuthConfig{Permissions: []string{"POST"}}
+		defer delete(server.entityAuth, "OrderItems")
+
+		resp, err := processor.executeOperation(context.Background(), &BatchHTTPOperation{Method: "GET", URL: "/odata/OrderItems", ContentID: "1"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestBaseEntityService_PatchDelta_Authorizer(t *testing.T) {
+	server, requests := newAuthorizationTestServer(t)
+	service := server.entities["Orders"].(*BaseEntityService)
+
+	_, report, err := service.PatchWithReport(context.Background(), map[string]any{"id": int64(1)}, map[string]interface{}{
+		"status": "closed",
+		"Items@delta": []interface{}{
+			map[string]interface{}{"@id": "OrderItems(1)", "@removed": map[string]interface{}{"reason": "deleted"}},
+		},
+	})
+	require.Error(t, err)
+	assert.True(t, report.RolledBack)
+
+	var odataErr *ODataError
+	require.True(t, errors.As(err, &odataErr))
+	assert.Equal(t, http.StatusForbidden, odataErr.Status)
+	assert.Equal(t, int64(1), orderItemOwner(t, server, 1))
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "DELETE", (*requests)[0].Operation)
+	assert.Equal(t, "Items", (*requests)[0].NavigationPath)
+}
+
+func TestServer_Authorizer_NavigationPaths(t *testing.T) {
+	server := newNavigationTestServer(t)
+	var requests []AuthorizationRequest
+	server.entityAuthorizers = map[string][]EntityAuthorizer{
+		"Categories": {func(ctx context.Context, request *AuthorizationRequest) error {
+			requests = append(requests, *request)
+			return errors.New("categorias restritas")
+		}},
+	}
+
+	for _, query := range []string{
+		"$filter=" + url.QueryEscape("Category/name eq 'Displays'"),
+		"$filter=" + url.QueryEscape("Category/name eq 'Displays'") + "&$count=true",
+		"$orderby=" + url.QueryEscape("Category/name desc"),
+	} {
+		status, _ := getComputeTestProducts(t, server, query)
+		assert.Equal(t, http.StatusForbidden, status, query)
+	}
+	require.NotEmpty(t, requests)
+	assert.Equal(t, "Categories", requests[0].EntityName)
+	assert.Equal(t, http.MethodGet, requests[0].Operation)
+}
@@ -0,0 +1,170 @@
+package odata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// ALIASES DE ENTITY SETS E PREFIXOS DE ROTA VERSIONADOS
+// =======================================================================================
+
+// entityRoute é o caminho público de uma entidade: prefixo de rota e nome do entity set
+type entityRoute struct {
+	prefix  string
+	setName string
+}
+
+// path retorna o caminho base da coleção (ex: /api/v2/Products)
+func (r entityRoute) path() string {
+	return r.prefix + "/" + r.setName
+}
+
+// WithRoutePrefix publica a entidade sob outro prefixo de rota (ex: "/api/v2"), sobrescrevendo
+// o RoutePrefix do servidor. O prefixo ganha $metadata e service document próprios
+func WithRoutePrefix(prefix string) EntityOption {
+	return func(config *EntityConfig) {
+		config.RoutePrefix = prefix
+	}
+}
+
+// WithEntitySetName define o nome do entity set na URL, quando diferente do nome de registro.
+// Permite registrar a mesma entidade (ou versões dela) mais de uma vez com o mesmo nome público
+// em prefixos diferentes. Ex:
+//
+//	server.RegisterEntity("Products", ProductV1{}, odata.WithRoutePrefix("/api/v1"))
+//	server.RegisterEntity("ProductsV2", ProductV2{}, odata.WithRoutePrefix("/api/v2"), odata.WithEntitySetName("Products"))
+func WithEntitySetName(name string) EntityOption {
+	return func(config *EntityConfig) {
+		config.EntitySetName = name
+	}
+}
+
+// normalizeRoutePrefix garante a barra inicial e remove a barra final do prefixo
+func normalizeRoutePrefix(prefix string) string {
+	prefix = strings.TrimRight(strings.TrimSpace(prefix), "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return prefix
+}
+
+// routePrefix retorna o RoutePrefix do servidor
+func (s *Server) routePrefix() string {
+	if s.config == nil {
+		return ""
+	}
+	return s.config.RoutePrefix
+}
+
+// entityRoute retorna o caminho público da entidade registrada (padrão: RoutePrefix do
+// servidor e o nome de registro)
+func (s *Server) entityRoute(name string) entityRoute {
+	s.mu.RLock()
+	route, ok := s.entityRoutes[name]
+	s.mu.RUnlock()
+	if ok {
+		return route
+	}
+	return entityRoute{prefix: s.routePrefix(), setName: name}
+}
+
+// newEntityRoute resolve o caminho público da entidade a partir das options e verifica se ele
+// já é usado por outra entidade
+func (s *Server) newEntityRoute(name string, config *EntityConfig) (entityRoute, error) {
+	route := entityRoute{prefix: s.routePrefix(), setName: name}
+	if config.RoutePrefix != "" {
+		route.prefix = normalizeRoutePrefix(config.RoutePrefix)
+	}
+	if config.EntitySetName != "" {
+		if strings.ContainsAny(config.EntitySetName, "/()$") {
+			return route, fmt.Errorf("nome de entity set inválido: '%s'", config.EntitySetName)
+		}
+		route.setName = config.EntitySetName
+	}
+
+	for existing := range s.GetEntities() {
+		if existing != name && s.entityRoute(existing).path() == route.path() {
+			return route, fmt.Errorf("rota %s já é usada pela entidade %s", route.path(), existing)
+		}
+	}
+	return route, nil
+}
+
+// setEntityRoute armazena o caminho público da entidade e registra $metadata e service
+// document na primeira vez que um prefixo diferente do RoutePrefix do servidor é usado
+func (s *Server) setEntityRoute(name string, route entityRoute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if route.prefix == s.routePrefix() && route.setName == name {
+		delete(s.entityRoutes, name)
+		return
+	}
+	if s.entityRoutes == nil {
+		s.entityRoutes = make(map[string]entityRoute)
+	}
+	s.entityRoutes[name] = route
+
+	if route.prefix == s.routePrefix() || s.routePrefixes[route.prefix] {
+		return
+	}
+	if s.routePrefixes == nil {
+		s.routePrefixes = make(map[string]bool)
+	}
+	s.routePrefixes[route.prefix] = true
+
+	if s.running {
+		s.dynamicPrefixes = append(s.dynamicPrefixes, route.prefix)
+		return
+	}
+	s.addPrefixRoutes(s.router, route.prefix)
+}
+
+// addPrefixRoutes registra o $metadata e o service document de um prefixo de rota
+func (s *Server) addPrefixRoutes(router fiber.Router, prefix string) {
+	router.Get(prefix+"/$metadata", func(c fiber.Ctx) error {
+		return c.JSON(s.buildPrefixMetadataJSON(prefix))
+	})
+	router.Get(prefix+"/", func(c fiber.Ctx) error {
+		return c.JSON(map[string]interface{}{
+			"@odata.context": s.metadataURL(c, prefix),
+			"value":          s.buildPrefixEntitySets(prefix),
+		})
+	})
+}
+
+// entityNameFromRoute retorna o nome de registro da entidade publicada com prefixo ou nome de
+// entity set próprios a partir do caminho da requisição
+func (s *Server) entityNameFromRoute(path string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.entityRoutes) == 0 {
+		return "", false
+	}
+
+	if idx := strings.Index(path, "("); idx != -1 {
+		path = path[:idx]
+	}
+	path = strings.TrimSuffix(path, "/$count")
+	for name, route := range s.entityRoutes {
+		if route.path() == path {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// entitySetNames retorna as entidades publicadas no prefixo, por nome de entity set
+// (valor: nome de registro)
+func (s *Server) entitySetNames(prefix string) map[string]string {
+	names := make(map[string]string)
+	for name := range s.GetEntities() {
+		if route := s.entityRoute(name); route.prefix == prefix {
+			names[route.setName] = name
+		}
+	}
+	return names
+}
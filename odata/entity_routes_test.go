@@ -0,0 +1,134 @@
+package odata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type RouteProductV1 struct {
+	TableName string `table:"products"`
+	ID        int64  `json:"id" column:"id" primaryKey:"idGenerator:none"`
+	Name      string `json:"name" column:"name"`
+}
+
+type RouteProductV2 struct {
+	TableName string  `table:"products"`
+	ID        int64   `json:"id" column:"id" primaryKey:"idGenerator:none"`
+	Name      string  `json:"name" column:"name"`
+	Price     float64 `json:"price" column:"price"`
+}
+
+func TestServer_VersionedEntityRoutes(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupBaseRoutes()
+
+	require.NoError(t, server.RegisterEntity("ProductsV1", RouteProductV1{},
+		WithRoutePrefix("/api/v1"), WithEntitySetName("Products")))
+	require.NoError(t, server.RegisterEntity("ProductsV2", RouteProductV2{},
+		WithRoutePrefix("api/v2/"), WithEntitySetName("Products"),
+		WithQueryPreset("Expensive", "price gt 100")))
+	require.NoError(t, server.RegisterEntity("Produtos", RouteProductV2{}))
+
+	request := func(t *testing.T, method, path, body string) (int, string) {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		payload, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(payload)
+	}
+
+	t.Run("Colunas expostas por versão", func(t *testing.T) {
+		status, body := request(t, http.MethodGet, "/api/v1/Products(2)", "")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Contains(t, body, `"name":"Teclado"`)
+		assert.NotContains(t, body, "price")
+
+		status, body = request(t, http.MethodGet, "/api/v2/Products(2)", "")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Contains(t, body, `"price":50`)
+	})
+
+	t.Run("Coleção, $count e presets no prefixo", func(t *testing.T) {
+		status, body := request(t, http.MethodGet, "/api/v2/Products?$filter=price%20gt%2020&$orderby=id", "")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Contains(t, body, `"name":"Teclado"`)
+		assert.NotContains(t, body, `"name":"Mouse"`)
+
+		_, body = request(t, http.MethodGet, "/api/v1/Products/$count", "")
+		assert.Equal(t, "3", body)
+
+		_, body = request(t, http.MethodGet, "/api/v2/Products/Default.Expensive()/$count", "")
+		assert.Equal(t, "1", body)
+
+		status, _ = request(t, http.MethodGet, "/api/v1/Products/Default.Expensive()", "")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Escrita pela versão", func(t *testing.T) {
+		status, body := request(t, http.MethodPatch, "/api/v2/Products(1)", `{"price": 12}`)
+		require.Contains(t, []int{http.StatusOK, http.StatusNoContent}, status, body)
+
+		var price float64
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT price FROM products WHERE id = 1").Scan(&price))
+		assert.Equal(t, float64(12), price)
+	})
+
+	t.Run("Alias no prefixo padrão", func(t *testing.T) {
+		status, body := request(t, http.MethodGet, "/odata/Produtos(3)", "")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Contains(t, body, `"name":"Monitor"`)
+	})
+
+	t.Run("$metadata e service document por prefixo", func(t *testing.T) {
+		entityNames := func(t *testing.T, path string) []string {
+			t.Helper()
+			status, body := request(t, http.MethodGet, path, "")
+			require.Equal(t, http.StatusOK, status, body)
+			var metadata MetadataResponse
+			require.NoError(t, json.Unmarshal([]byte(body), &metadata))
+			var names []string
+			for _, entity := range metadata.Entities {
+				names = append(names, entity.Name)
+				if path == "/api/v1/$metadata" {
+					assert.Len(t, entity.Properties, 2, "v1 não expõe price")
+				}
+			}
+			return names
+		}
+
+		assert.Equal(t, []string{"Products"}, entityNames(t, "/api/v1/$metadata"))
+		assert.Equal(t, []string{"Products"}, entityNames(t, "/api/v2/$metadata"))
+		assert.ElementsMatch(t, []string{"Products", "Produtos"}, entityNames(t, "/odata/$metadata"))
+
+		status, body := request(t, http.MethodGet, "/api/v2/", "")
+		require.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, `"name":"Products"`)
+		assert.NotContains(t, body, "Produtos")
+	})
+
+	t.Run("Rota já usada", func(t *testing.T) {
+		err := server.RegisterEntity("Outro", RouteProductV1{}, WithRoutePrefix("/api/v2"), WithEntitySetName("Products"))
+		assert.ErrorContains(t, err, "ProductsV2")
+
+		err = server.RegisterEntity("Outro", RouteProductV1{}, WithEntitySetName("Produtos"))
+		assert.ErrorContains(t, err, "Produtos")
+
+		err = server.RegisterEntity("Outro", RouteProductV1{}, WithEntitySetName("Products(1)"))
+		assert.Error(t, err)
+	})
+}
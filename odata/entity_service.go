@@ -3,10 +3,13 @@ package odata
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 )
 
 // BaseEntityService implementa o serviço base para entidades
@@ -16,6 +19,10 @@ type BaseEntityService struct {
 	server        *Server
 	computeParser *ComputeParser
 	searchParser  *SearchParser
+
+	// Planos de scan por conjunto de colunas do resultado (scan_plan.go)
+	scanPlansMu sync.RWMutex
+	scanPlans   map[string]*scanPlan
 }
 
 // NewBaseEntityService cria uma nova instância do serviço base
@@ -47,7 +54,7 @@ func (s *BaseEntityService) Query(ctx context.Context, options QueryOptions) (*O
 	// 1. $filter – aplica filtros sobre a entidade atual
 	// 2. $orderby – ordena os resultados filtrados
 	// 3. $skip/$top – aplica paginação
-	// 4. $compute – calcula colunas derivadas
+	// 4. $compute – calcula colunas derivadas (no SELECT; aliases podem ser usados em $filter/$orderby)
 	// 5. $select – reduz os campos retornados
 	// 6. $expand – processa entidades relacionadas (recursivamente)
 
@@ -56,19 +63,104 @@ func (s *BaseEntityService) Query(ctx context.Context, options QueryOptions) (*O
 	var args []any
 	var err error
 
+	// Exclusão lógica: apenas linhas do escopo (ativas ou da lixeira)
+	if err := s.applySoftDeleteFilter(ctx, &options); err != nil {
+		return nil, err
+	}
+
+	// $search é integrado ao WHERE: analisa e valida a expressão antes de construir a query
+	if options.Search != nil {
+		options.Search, err = s.prepareSearchOption(ctx, options.Search)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Navegações N:1 no $orderby (ex: Category/Name) são resolvidas em JOINs, que leem a
+	// entidade relacionada com as proteções de um GET
+	if s.server != nil {
+		options.navigationResolver = s.localEntityMetadata
+	}
+	if err := s.checkNavigationReads(ctx, navigationPathsInOrderBy(options.OrderBy), "$orderby"); err != nil {
+		return nil, err
+	}
+
+	// $compute é calculado no SELECT: seus aliases são inlinados no $filter (inclusive no $count)
+	if options.Compute != nil {
+		options.Compute, err = s.prepareComputeOption(ctx, options.Compute)
+		if err != nil {
+			return nil, err
+		}
+		if options.Filter != nil && options.Filter.Tree != nil {
+			options.Filter = &GoDataFilterQuery{
+				Tree:     inlineComputeAliases(options.Filter.Tree, options.Compute, s.metadata),
+				RawValue: options.Filter.RawValue,
+			}
+		}
+	}
+
+	// Referências $root/EntitySet(chave)/Propriedade no $filter e no $orderby viram subqueries escalares
+	if options.Filter, err = s.resolveRootFilter(ctx, options.Filter); err != nil {
+		return nil, err
+	}
+	if options.rootReferences, err = s.resolveRootOrderBy(ctx, options.OrderBy); err != nil {
+		return nil, err
+	}
+
+	// Navegações N:1 no $filter (ex: Category/Name eq 'X') viram subqueries EXISTS, que
+	// também leem a entidade relacionada com as proteções de um GET
+	if options.Filter != nil && options.Filter.Tree != nil && hasNavigationPath(options.Filter.Tree) {
+		if err := s.checkNavigationReads(ctx, navigationPathsInTree(options.Filter.Tree), "$filter"); err != nil {
+			return nil, err
+		}
+		tree, err := resolveNavigationFilters(options.Filter.Tree, s.queryMetadata(), options.navigationResolver)
+		if err != nil {
+			return nil, NewODataError("InvalidFilter", err.Error()).
+				WithStatus(http.StatusBadRequest).
+				WithTarget("$filter")
+		}
+		options.Filter = &GoDataFilterQuery{Tree: tree, RawValue: options.Filter.RawValue}
+	}
+
+	// Literais comparados a propriedades Encrypted são criptografados (modo determinístico)
+	if err := s.prepareEncryptedFilter(ctx, options.Filter); err != nil {
+		return nil, err
+	}
+
+	// Propriedades virtuais são calculadas em Go: com elas no $select, a query busca todas
+	// as colunas (possíveis dependências do cálculo) e o $select é aplicado no resultado
+	virtuals := s.virtualProperties()
+	selectOption := options.Select
+	if selectsVirtualProperty(options.Select, virtuals) {
+		options.Select = nil
+	}
+
 	// Aplica $filter, $orderby, $skip/$top primeiro na query SQL
 	if optimizedProvider, ok := s.provider.(interface {
 		BuildSelectQueryOptimized(ctx context.Context, metadata EntityMetadata, options QueryOptions) (string, []any, error)
 	}); ok {
-		query, args, err = optimizedProvider.BuildSelectQueryOptimized(ctx, s.metadata, options)
+		query, args, err = optimizedProvider.BuildSelectQueryOptimized(ctx, s.queryMetadata(), options)
 	} else {
-		query, args, err = s.provider.BuildSelectQuery(s.metadata, options)
+		query, args, err = buildSelectQuery(ctx, s.provider, s.queryMetadata(), options)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to build select query: %w", err)
 	}
 
+	// Dry-run ($debug): registra o SQL (e o do $count) sem executá-lo
+	if dryRun := queryDryRunFrom(ctx); dryRun != nil {
+		if err := s.recordDryRun(ctx, dryRun, QueryOperationSelect, query, args); err != nil {
+			return nil, err
+		}
+		if IsCountRequested(options.Count) {
+			if _, err := s.GetCount(ctx, options); err != nil {
+				return nil, err
+			}
+		}
+		return &ODataResponse{Context: fmt.Sprintf("$metadata#%s", s.metadata.Name), Value: []interface{}{}}, nil
+	}
+
 	// Verifica cancelamento do contexto antes da execução
 	select {
 	case <-ctx.Done():
@@ -76,7 +168,7 @@ func (s *BaseEntityService) Query(ctx context.Context, options QueryOptions) (*O
 	default:
 	}
 
-	rows, err := s.executeQuery(ctx, query, args)
+	rows, trace, err := s.executeQuery(ctx, query, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -130,35 +222,22 @@ func (s *BaseEntityService) Query(ctx context.Context, options QueryOptions) (*O
 
 	// Scana os resultados (aplicando paginação do SQL)
 	results, err := s.scanRows(rows, expandOptions)
+	trace.finish(int64(len(results)), err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan rows: %w", err)
 	}
-
-	// 4. Processa $compute DEPOIS da execução SQL básica
-	if options.Compute != nil {
-		err := s.processComputeOption(ctx, options.Compute)
-		if err != nil {
-			return nil, fmt.Errorf("failed to process compute option: %w", err)
-		}
-
-		// Aplica campos computados aos resultados
-		results, err = s.applyComputeToResults(ctx, results, options.Compute)
-		if err != nil {
-			return nil, fmt.Errorf("failed to apply compute to results: %w", err)
-		}
-	}
-
-	// 5. Processa $search se presente (integrado com $filter)
-	if options.Search != nil {
-		err := s.processSearchOption(ctx, options.Search)
-		if err != nil {
-			return nil, fmt.Errorf("failed to process search option: %w", err)
-		}
+	if err := s.server.decryptPropertyValues(ctx, s.metadata, results); err != nil {
+		return nil, err
 	}
 
-	// 6. Processa navegações expandidas seguindo a ordem recursivamente
+	// 5. Processa navegações expandidas seguindo a ordem recursivamente
 	if len(expandOptions) > 0 {
-		expandedResults, err := s.processExpandedNavigationWithOrder(results, expandOptions)
+		expandedResults, err := s.processExpandedNavigationWithOrder(ctx, results, expandOptions)
+		var odataErr *ODataError
+		if errors.As(err, &odataErr) && odataErr.Status != 0 {
+			// Limites da requisição (ex: $expand federado) são erros do cliente
+			return nil, err
+		}
 		if err != nil {
 			// Log do erro mas tenta continuar com navigation links
 			log.Printf("Warning: Failed to process expanded navigation: %v. Continuing with navigation links.", err)
@@ -167,9 +246,12 @@ func (s *BaseEntityService) Query(ctx context.Context, options QueryOptions) (*O
 		}
 	}
 
-	// 7. Aplica $select final se necessário (pode ser otimizado no SQL)
-	if options.Select != nil {
-		results, err = s.applySelectToResults(results, options.Select)
+	// Calcula as propriedades virtuais (após o scan)
+	applyVirtualProperties(results, virtuals, selectOption)
+
+	// 6. Aplica $select final se necessário (pode ser otimizado no SQL)
+	if selectOption != nil {
+		results, err = s.applySelectToResults(results, selectOption)
 		if err != nil {
 			return nil, fmt.Errorf("failed to apply select to results: %w", err)
 		}
@@ -193,6 +275,15 @@ func (s *BaseEntityService) Query(ctx context.Context, options QueryOptions) (*O
 	return response, nil
 }
 
+// buildSelectQuery monta o SELECT dos providers sem o builder otimizado, com o contexto da
+// requisição quando o provider implementa ContextQueryBuilder
+func buildSelectQuery(ctx context.Context, provider DatabaseProvider, metadata EntityMetadata, options QueryOptions) (string, []any, error) {
+	if builder, ok := provider.(ContextQueryBuilder); ok {
+		return builder.BuildSelectQueryContext(ctx, metadata, options)
+	}
+	return provider.BuildSelectQuery(metadata, options)
+}
+
 // Get recupera uma entidade específica pelas chaves
 func (s *BaseEntityService) Get(ctx context.Context, keys map[string]any) (any, error) {
 	log.Printf("🔍 BaseEntityService.Get - Starting with keys: %+v", keys)
@@ -211,10 +302,22 @@ func (s *BaseEntityService) Get(ctx context.Context, keys map[string]any) (any,
 	options := QueryOptions{
 		Filter: filterQuery,
 	}
+	if err := s.applySoftDeleteFilter(ctx, &options); err != nil {
+		return nil, err
+	}
 
 	log.Printf("🔍 BaseEntityService.Get - Options: %+v", options)
 
-	query, args, err := s.provider.BuildSelectQuery(s.metadata, options)
+	var query string
+	var args []any
+	if optimizedProvider, ok := s.provider.(interface {
+		BuildSelectQueryOptimized(ctx context.Context, metadata EntityMetadata, options QueryOptions) (string, []any, error)
+	}); ok && options.softDeleteApplied {
+		// O filtro de exclusão lógica é combinado na árvore, montada apenas pelo builder otimizado
+		query, args, err = optimizedProvider.BuildSelectQueryOptimized(ctx, s.metadata, options)
+	} else {
+		query, args, err = buildSelectQuery(ctx, s.provider, s.metadata, options)
+	}
 	if err != nil {
 		log.Printf("❌ BaseEntityService.Get - Failed to build select query: %v", err)
 		return nil, fmt.Errorf("failed to build select query: %w", err)
@@ -229,7 +332,7 @@ func (s *BaseEntityService) Get(ctx context.Context, keys map[string]any) (any,
 	}
 
 	// Executa a query
-	rows, err := s.executeQuery(ctx, query, args)
+	rows, trace, err := s.executeQuery(ctx, query, args)
 	if err != nil {
 		log.Printf("❌ BaseEntityService.Get - Failed to execute query: %v", err)
 		return nil, fmt.Errorf("failed to execute query: %w", err)
@@ -240,10 +343,14 @@ func (s *BaseEntityService) Get(ctx context.Context, keys map[string]any) (any,
 
 	// Converte os resultados (sem expand para Get)
 	results, err := s.scanRows(rows, []ExpandOption{})
+	trace.finish(int64(len(results)), err)
 	if err != nil {
 		log.Printf("❌ BaseEntityService.Get - Failed to scan rows: %v", err)
 		return nil, fmt.Errorf("failed to scan rows: %w", err)
 	}
+	if err := s.server.decryptPropertyValues(ctx, s.metadata, results); err != nil {
+		return nil, err
+	}
 
 	if len(results) == 0 {
 		log.Printf("❌ BaseEntityService.Get - Entity not found")
@@ -517,17 +624,23 @@ func (s *BaseEntityService) getRelatedEntityService(relatedName string) EntitySe
 	if s.server == nil {
 		return nil
 	}
-	if svc := s.server.GetEntityService(relatedName); svc != nil {
+	return s.server.relatedEntityService(relatedName)
+}
+
+// relatedEntityService busca o serviço da entidade relacionada, aceitando o nome no singular
+// ou no plural
+func (s *Server) relatedEntityService(relatedName string) EntityService {
+	if svc := s.GetEntityService(relatedName); svc != nil {
 		return svc
 	}
 	if strings.HasSuffix(relatedName, "s") && len(relatedName) > 1 {
 		base := relatedName[:len(relatedName)-1]
-		if svc := s.server.GetEntityService(base); svc != nil {
+		if svc := s.GetEntityService(base); svc != nil {
 			return svc
 		}
 	}
 	base := relatedName + "s"
-	return s.server.GetEntityService(base)
+	return s.GetEntityService(base)
 }
 
 func findPropertyByColumnName(metadata EntityMetadata, columnName string) *PropertyMetadata {
@@ -615,54 +728,36 @@ func (s *BaseEntityService) Create(ctx context.Context, entity any) (any, error)
 	if err := s.processAssociationCascadeSaveUpdate(ctx, data); err != nil {
 		return nil, err
 	}
+	applyAutoValues(ctx, s.metadata, data, QueryOperationInsert)
+	// Gera as chaves com idGenerator do servidor (snowflake, ulid, ksuid...) ausentes no payload
+	generatedKey, err := s.server.generateKeyValues(s.metadata, data)
+	if err != nil {
+		return nil, err
+	}
+	s.stripVirtualProperties(data)
+	if err := s.preparePayloadValues(s.metadata, data); err != nil {
+		return nil, err
+	}
+	// Criptografa as propriedades Encrypted em uma cópia (o payload pode ser retornado)
+	stored, err := s.server.encryptPropertyValues(ctx, s.metadata, data)
+	if err != nil {
+		return nil, err
+	}
 	// Constrói a query SQL
-	query, args, err := s.provider.BuildInsertQuery(s.metadata, data)
+	query, args, err := s.provider.BuildInsertQuery(s.metadata, stored)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build insert query: %w", err)
 	}
 
-	// Verifica se a query contém RETURNING (PostgreSQL, Oracle, etc.)
-	hasReturning := strings.Contains(strings.ToUpper(query), "RETURNING")
-
-	if hasReturning {
-		// Para queries com RETURNING, usa Query para obter os valores retornados
-		// (usamos Query em vez de QueryRow para poder obter os nomes das colunas dinamicamente)
-		conn := s.provider.GetConnection()
-		if conn == nil {
-			return nil, fmt.Errorf("database connection is nil")
-		}
-
-		// Log da query SQL se DB_LOG_SQL estiver habilitado
-		if s.shouldLogSQL() {
-			log.Printf("🔍 [SQL] EXEC (RETURNING): %s", query)
-			if len(args) > 0 {
-				log.Printf("🔍 [SQL] ARGS: %v", args)
-			}
-		}
-
-		// Executa a query e obtém o resultado
-		rows, err := conn.QueryContext(ctx, query, args...)
-		if err != nil {
-			if s.shouldLogSQL() {
-				log.Printf("❌ [SQL] ERRO na query: %v", err)
-			}
-			return nil, fmt.Errorf("failed to execute insert with returning: %w", err)
-		}
-		defer rows.Close()
-
-		// Usa scanRows para processar o resultado (já sabe lidar com mapeamento dinâmico de colunas)
-		results, err := s.scanRows(rows, []ExpandOption{})
-		if err != nil {
-			if s.shouldLogSQL() {
-				log.Printf("❌ [SQL] ERRO no scan: %v", err)
-			}
-			return nil, fmt.Errorf("failed to scan returned values: %w", err)
-		}
-
+	// PostgreSQL (RETURNING) e Oracle (RETURNING ... INTO) retornam a linha inserida
+	results, handled, err := s.executeWriteReturning(ctx, QueryOperationInsert, query, args)
+	if err != nil {
+		return nil, err
+	}
+	if handled {
 		if len(results) == 0 {
 			return nil, fmt.Errorf("no rows returned from insert")
 		}
-
 		// Retorna a primeira (e única) row retornada
 		return results[0], nil
 	}
@@ -670,7 +765,7 @@ func (s *BaseEntityService) Create(ctx context.Context, entity any) (any, error)
 	// Para bancos que não usam RETURNING (MySQL, SQLite), usa a abordagem tradicional
 	result, err := s.executeExec(ctx, query, args)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute insert: %w", err)
+		return nil, fmt.Errorf("failed to execute insert: %w", TranslateDatabaseError(err, s.metadata))
 	}
 
 	// Verifica se a inserção foi bem-sucedida
@@ -683,6 +778,11 @@ func (s *BaseEntityService) Create(ctx context.Context, entity any) (any, error)
 		return nil, fmt.Errorf("no rows inserted")
 	}
 
+	// Chave gerada no servidor: busca o registro inserido por ela
+	if generatedKey {
+		return s.Get(ctx, extractKeysFromEntity(data, s.metadata))
+	}
+
 	// Se há chaves auto-incrementais, busca o registro inserido
 	if s.hasAutoIncrementKey() {
 		lastID, err := result.LastInsertId()
@@ -717,16 +817,45 @@ func (s *BaseEntityService) Update(ctx context.Context, keys map[string]any, ent
 		delete(data, key)
 	}
 
+	applyAutoValues(ctx, s.metadata, data, QueryOperationUpdate)
+	s.stripVirtualProperties(data)
+	if err := s.preparePayloadValues(s.metadata, data); err != nil {
+		return nil, err
+	}
+	stored, err := s.server.encryptPropertyValues(ctx, s.metadata, data)
+	if err != nil {
+		return nil, err
+	}
+
 	// Constrói a query SQL
-	query, args, err := s.provider.BuildUpdateQuery(s.metadata, data, keys)
+	query, args, err := s.provider.BuildUpdateQuery(s.metadata, stored, keys)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build update query: %w", err)
 	}
 
+	if err := recordHistory(ctx, s.provider, s.metadata, keys, s.executeExec); err != nil {
+		return nil, err
+	}
+
+	// PostgreSQL e Oracle retornam a linha atualizada na própria query. Com exclusão lógica a
+	// linha é relida pelo Get, que recusa registros fora do escopo (ex: na lixeira)
+	if s.metadata.SoftDeleteProperty == "" {
+		results, handled, err := s.executeWriteReturning(ctx, QueryOperationUpdate, query, args)
+		if err != nil {
+			return nil, err
+		}
+		if handled {
+			if len(results) == 0 {
+				return nil, fmt.Errorf("no rows updated")
+			}
+			return results[0], nil
+		}
+	}
+
 	// Executa a query
 	result, err := s.executeExec(ctx, query, args)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute update: %w", err)
+		return nil, fmt.Errorf("failed to execute update: %w", TranslateDatabaseError(err, s.metadata))
 	}
 
 	// Verifica se a atualização foi bem-sucedida
@@ -745,16 +874,27 @@ func (s *BaseEntityService) Update(ctx context.Context, keys map[string]any, ent
 
 // Delete remove uma entidade
 func (s *BaseEntityService) Delete(ctx context.Context, keys map[string]any) error {
+	// Com exclusão lógica, a entidade precisa existir no escopo (ativa ou na lixeira)
+	if s.metadata.SoftDeleteProperty != "" {
+		if _, err := s.Get(ctx, keys); err != nil {
+			return err
+		}
+	}
+
 	// Constrói a query SQL
-	query, args, err := s.provider.BuildDeleteQuery(s.metadata, keys)
+	query, args, err := buildDeleteQuery(ctx, s.provider, s.metadata, keys)
 	if err != nil {
 		return fmt.Errorf("failed to build delete query: %w", err)
 	}
 
+	if err := recordHistory(ctx, s.provider, s.metadata, keys, s.executeExec); err != nil {
+		return err
+	}
+
 	// Executa a query
 	result, err := s.executeExec(ctx, query, args)
 	if err != nil {
-		return fmt.Errorf("failed to execute delete: %w", err)
+		return fmt.Errorf("failed to execute delete: %w", TranslateDatabaseError(err, s.metadata))
 	}
 
 	// Verifica se a exclusão foi bem-sucedida
@@ -773,10 +913,20 @@ func (s *BaseEntityService) Delete(ctx context.Context, keys map[string]any) err
 // Patch processa um PATCH com suporte a hierarquias aninhadas (INSERT/UPDATE/DELETE)
 // Se não houver hierarquia, delega para Update existente para manter compatibilidade
 func (s *BaseEntityService) Patch(ctx context.Context, keys map[string]any, entity any) (any, error) {
+	result, _, err := s.PatchWithReport(ctx, keys, entity)
+	return result, err
+}
+
+// PatchWithReport executa o PATCH como Patch e retorna também o relatório de cada
+// operação executada (entidade, tipo, chaves e status). Em caso de falha, o relatório
+// indica a operação que falhou e as que não foram executadas
+func (s *BaseEntityService) PatchWithReport(ctx context.Context, keys map[string]any, entity any) (any, *PatchReport, error) {
+	report := &PatchReport{Operations: make([]PatchOperationResult, 0)}
+
 	// Converte a entidade para map
 	data, err := s.entityToMap(entity)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert entity to map: %w", err)
+		return nil, report, fmt.Errorf("failed to convert entity to map: %w", err)
 	}
 
 	// Obtém configuração do formato de @odata.removed
@@ -791,7 +941,16 @@ func (s *BaseEntityService) Patch(ctx context.Context, keys map[string]any, enti
 	// Verifica se precisa processamento avançado (hierárquico)
 	if !hasHierarchicalStructure(data, s.metadata) {
 		// Não há hierarquia, usa fluxo simples (compatibilidade com código existente)
-		return s.Update(ctx, keys, entity)
+		result, err := s.Update(ctx, keys, entity)
+		report.add(PatchOperation{Type: "UPDATE", Keys: keys, EntityName: s.metadata.Name}, keys, err)
+		return result, report, err
+	}
+
+	// A entidade raiz é identificada pelas chaves da URL
+	for key, value := range keys {
+		if _, exists := data[key]; !exists {
+			data[key] = value
+		}
 	}
 
 	// Processa hierarquia recursivamente
@@ -800,54 +959,47 @@ func (s *BaseEntityService) Patch(ctx context.Context, keys map[string]any, enti
 	// Processa a entidade raiz primeiro
 	rootOpType, err := identifyOperation(data, s.metadata, removedFormat)
 	if err != nil {
-		return nil, fmt.Errorf("failed to identify root operation: %w", err)
+		return nil, report, err
 	}
 
 	// Se a raiz é DELETE, processa apenas a raiz
 	if rootOpType == "DELETE" {
-		rootKeys := extractKeysFromEntity(data, s.metadata)
-		cleanEntity := cleanRemovedAnnotation(data)
 		operations = append(operations, PatchOperation{
 			Type:           "DELETE",
-			Entity:         cleanEntity,
-			Keys:           rootKeys,
+			Keys:           keys,
 			NavigationPath: "",
 			EntityName:     s.metadata.Name,
 		})
 	} else {
-		// Processa propriedades de navegação recursivamente
+		// Processa propriedades de navegação recursivamente (referências N:1 preenchem as
+		// chaves estrangeiras da raiz)
 		if err := processPatchRecursive(ctx, s.server, data, s.metadata, "", removedFormat, &operations); err != nil {
-			log.Printf("Warning: Failed to process navigation properties: %v", err)
+			return nil, report, err
 		}
 
-		// Adiciona operação da raiz (UPDATE ou INSERT)
-		rootKeys := extractKeysFromEntity(data, s.metadata)
-		cleanEntity := cleanRemovedAnnotation(data)
-		// Remove propriedades de navegação do objeto raiz (já foram processadas)
-		for _, prop := range s.metadata.Properties {
-			if prop.IsNavigation {
-				delete(cleanEntity, prop.Name)
-			}
+		// Adiciona operação da raiz (UPDATE ou INSERT), sem anotações e navegações (já processadas).
+		// Um UPDATE sem propriedades além das chaves é omitido (payload só com navegações)
+		rootEntity := cleanDeltaPayload(data, s.metadata)
+		if rootOpType != "UPDATE" || hasNonKeyValues(rootEntity, s.metadata) {
+			operations = append(operations, PatchOperation{
+				Type:           rootOpType,
+				Entity:         rootEntity,
+				Keys:           extractKeysFromEntity(data, s.metadata),
+				NavigationPath: "",
+				EntityName:     s.metadata.Name,
+			})
 		}
-
-		operations = append(operations, PatchOperation{
-			Type:           rootOpType,
-			Entity:         cleanEntity,
-			Keys:           rootKeys,
-			NavigationPath: "",
-			EntityName:     s.metadata.Name,
-		})
 	}
 
 	// Inicia transação única
 	conn := s.provider.GetConnection()
 	if conn == nil {
-		return nil, fmt.Errorf("database connection is nil")
+		return nil, report, fmt.Errorf("database connection is nil")
 	}
 
-	tx, err := s.provider.BeginTx(ctx, nil)
+	tx, err := beginSessionTx(ctx, s.provider, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, report, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
 	defer func() {
@@ -870,56 +1022,72 @@ func (s *BaseEntityService) Patch(ctx context.Context, keys map[string]any, enti
 		}
 	}
 
-	// Executa DELETEs primeiro
-	for _, op := range deletes {
-		if err := executePatchOperation(ctx, tx, s.server, op); err != nil {
-			return nil, fmt.Errorf("failed to execute DELETE operation for %s: %w", op.EntityName, err)
-		}
-	}
-
-	// Executa UPDATEs
-	for _, op := range updates {
-		if err := executePatchOperation(ctx, tx, s.server, op); err != nil {
-			return nil, fmt.Errorf("failed to execute UPDATE operation for %s: %w", op.EntityName, err)
-		}
-	}
-
-	// Executa INSERTs por último
-	for _, op := range inserts {
-		if err := executePatchOperation(ctx, tx, s.server, op); err != nil {
-			return nil, fmt.Errorf("failed to execute INSERT operation for %s: %w", op.EntityName, err)
+	// Executa DELETEs primeiro, depois UPDATEs e INSERTs por último
+	ordered := append(append(deletes, updates...), inserts...)
+	for i, op := range ordered {
+		affectedKeys, err := executePatchOperation(ctx, tx.Tx, s.server, op)
+		report.add(op, affectedKeys, err)
+		if err != nil {
+			report.skip(ordered[i+1:])
+			report.RolledBack = true
+			return nil, report, fmt.Errorf("failed to execute %s operation for %s: %w", op.Type, op.EntityName, err)
 		}
 	}
 
 	// Commit da transação
 	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		report.RolledBack = true
+		return nil, report, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	tx = nil // Marca como nil para evitar rollback no defer
 
+	// Raiz removida (@removed): não há entidade a retornar
+	if rootOpType == "DELETE" {
+		return nil, report, nil
+	}
+
 	// Busca a entidade atualizada
-	return s.Get(ctx, keys)
+	result, err := s.Get(ctx, keys)
+	return result, report, err
 }
 
 // executePatchOperation executa uma operação PATCH individual dentro de uma transação
-func executePatchOperation(ctx context.Context, tx *sql.Tx, server *Server, op PatchOperation) error {
+// e retorna as chaves da entidade afetada
+func executePatchOperation(ctx context.Context, tx *sql.Tx, server *Server, op PatchOperation) (map[string]interface{}, error) {
 	// Obtém o serviço da entidade
-	service := server.GetEntityService(op.EntityName)
+	service := server.relatedEntityService(op.EntityName)
 	if service == nil {
-		return fmt.Errorf("entity service not found: %s", op.EntityName)
+		return op.Keys, fmt.Errorf("entity service not found: %s", op.EntityName)
+	}
+
+	// Entidades relacionadas são autorizadas individualmente (a raiz já foi autorizada pelo handler)
+	if op.NavigationPath != "" {
+		if err := server.authorizeEntity(ctx, &AuthorizationRequest{
+			EntityName:     server.registeredEntityName(service, op.EntityName),
+			Operation:      patchOperationMethod(op.Type),
+			User:           contextUser(ctx),
+			Keys:           op.Keys,
+			NavigationPath: op.NavigationPath,
+		}); err != nil {
+			return op.Keys, err
+		}
 	}
 
 	// Executa operação baseada no tipo
 	switch op.Type {
 	case "DELETE":
-		return executeDeleteInTx(ctx, tx, service, op.Keys)
+		return op.Keys, executeDeleteInTx(ctx, tx, service, op.Keys)
 	case "UPDATE":
-		return executeUpdateInTx(ctx, tx, service, op.Keys, op.Entity)
+		return op.Keys, executeUpdateInTx(ctx, tx, service, op.Keys, op.Entity)
 	case "INSERT":
-		return executeInsertInTx(ctx, tx, service, op.Entity)
+		keys, err := executeInsertInTx(ctx, tx, service, op.Entity)
+		if err != nil {
+			return op.Keys, err
+		}
+		return keys, nil
 	default:
-		return fmt.Errorf("unknown operation type: %s", op.Type)
+		return op.Keys, fmt.Errorf("unknown operation type: %s", op.Type)
 	}
 }
 
@@ -931,7 +1099,7 @@ func executeDeleteInTx(ctx context.Context, tx *sql.Tx, service EntityService, k
 	}
 
 	metadata := baseService.GetMetadata()
-	query, args, err := baseService.provider.BuildDeleteQuery(metadata, keys)
+	query, args, err := buildDeleteQuery(ctx, baseService.provider, metadata, keys)
 	if err != nil {
 		return fmt.Errorf("failed to build delete query: %w", err)
 	}
@@ -944,12 +1112,16 @@ func executeDeleteInTx(ctx context.Context, tx *sql.Tx, service EntityService, k
 		}
 	}
 
+	if err := recordHistory(ctx, baseService.provider, metadata, keys, txHistoryExec(tx)); err != nil {
+		return err
+	}
+
 	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		if baseService.shouldLogSQL() {
 			log.Printf("❌ [SQL] ERRO: %v", err)
 		}
-		return fmt.Errorf("failed to execute delete: %w", err)
+		return fmt.Errorf("failed to execute delete: %w", TranslateDatabaseError(err, metadata))
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -981,6 +1153,15 @@ func executeUpdateInTx(ctx context.Context, tx *sql.Tx, service EntityService, k
 	for key := range keys {
 		delete(data, key)
 	}
+	applyAutoValues(ctx, metadata, data, QueryOperationUpdate)
+	baseService.stripVirtualProperties(data)
+	if err := baseService.preparePayloadValues(metadata, data); err != nil {
+		return err
+	}
+	data, err := baseService.server.encryptPropertyValues(ctx, metadata, data)
+	if err != nil {
+		return err
+	}
 
 	query, args, err := baseService.provider.BuildUpdateQuery(metadata, data, keys)
 	if err != nil {
@@ -995,12 +1176,16 @@ func executeUpdateInTx(ctx context.Context, tx *sql.Tx, service EntityService, k
 		}
 	}
 
+	if err := recordHistory(ctx, baseService.provider, metadata, keys, txHistoryExec(tx)); err != nil {
+		return err
+	}
+
 	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		if baseService.shouldLogSQL() {
 			log.Printf("❌ [SQL] ERRO: %v", err)
 		}
-		return fmt.Errorf("failed to execute update: %w", err)
+		return fmt.Errorf("failed to execute update: %w", TranslateDatabaseError(err, metadata))
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -1015,17 +1200,30 @@ func executeUpdateInTx(ctx context.Context, tx *sql.Tx, service EntityService, k
 	return nil
 }
 
-// executeInsertInTx executa INSERT dentro de uma transação
-func executeInsertInTx(ctx context.Context, tx *sql.Tx, service EntityService, entity map[string]interface{}) error {
+// executeInsertInTx executa INSERT dentro de uma transação e retorna as chaves do registro
+// inserido (incluindo a chave auto-incremental, quando disponível)
+func executeInsertInTx(ctx context.Context, tx *sql.Tx, service EntityService, entity map[string]interface{}) (map[string]interface{}, error) {
 	baseService, ok := service.(*BaseEntityService)
 	if !ok {
-		return fmt.Errorf("service is not BaseEntityService")
+		return nil, fmt.Errorf("service is not BaseEntityService")
 	}
 
 	metadata := baseService.GetMetadata()
-	query, args, err := baseService.provider.BuildInsertQuery(metadata, entity)
+	applyAutoValues(ctx, metadata, entity, QueryOperationInsert)
+	if _, err := baseService.server.generateKeyValues(metadata, entity); err != nil {
+		return nil, err
+	}
+	baseService.stripVirtualProperties(entity)
+	if err := baseService.preparePayloadValues(metadata, entity); err != nil {
+		return nil, err
+	}
+	stored, err := baseService.server.encryptPropertyValues(ctx, metadata, entity)
 	if err != nil {
-		return fmt.Errorf("failed to build insert query: %w", err)
+		return nil, err
+	}
+	query, args, err := baseService.provider.BuildInsertQuery(metadata, stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build insert query: %w", err)
 	}
 
 	// Log da query SQL se DB_LOG_SQL estiver habilitado
@@ -1041,19 +1239,26 @@ func executeInsertInTx(ctx context.Context, tx *sql.Tx, service EntityService, e
 		if baseService.shouldLogSQL() {
 			log.Printf("❌ [SQL] ERRO: %v", err)
 		}
-		return fmt.Errorf("failed to execute insert: %w", err)
+		return nil, fmt.Errorf("failed to execute insert: %w", TranslateDatabaseError(err, metadata))
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("no rows inserted")
+		return nil, fmt.Errorf("no rows inserted")
 	}
 
-	return nil
+	keys := extractKeysFromEntity(entity, metadata)
+	if len(metadata.Keys) == 1 && keys[metadata.Keys[0]] == nil {
+		if lastID, err := result.LastInsertId(); err == nil && lastID > 0 {
+			keys[metadata.Keys[0]] = lastID
+		}
+	}
+
+	return keys, nil
 }
 
 // buildKeyFilter constrói um filtro baseado nas chaves (método legado, considerar usar BuildTypedKeyFilter)
@@ -1085,20 +1290,15 @@ func (s *BaseEntityService) buildKeyFilter(keys map[string]any) string {
 	return result
 }
 
-// hasAutoIncrementKey verifica se há chaves auto-incrementais
+// hasAutoIncrementKey verifica se há chaves auto-incrementais (inteiras e não geradas no servidor)
 func (s *BaseEntityService) hasAutoIncrementKey() bool {
-	for _, prop := range s.metadata.Properties {
-		if prop.IsKey && (prop.Type == "int" || prop.Type == "int32" || prop.Type == "int64") {
-			return true
-		}
-	}
-	return false
+	return s.getAutoIncrementKey() != nil
 }
 
 // getAutoIncrementKey retorna a primeira chave auto-incremental
 func (s *BaseEntityService) getAutoIncrementKey() *PropertyMetadata {
 	for _, prop := range s.metadata.Properties {
-		if prop.IsKey && (prop.Type == "int" || prop.Type == "int32" || prop.Type == "int64") {
+		if prop.IsKey && (prop.Type == "int" || prop.Type == "int32" || prop.Type == "int64") && !s.server.isServerGeneratedKey(prop) {
 			return &prop
 		}
 	}
@@ -1137,12 +1337,52 @@ func (s *BaseEntityService) processSearchOption(ctx context.Context, searchOptio
 	// Verifica se há propriedades pesquisáveis
 	searchableProps := s.searchParser.GetSearchableProperties(s.metadata)
 	if len(searchableProps) == 0 {
-		return fmt.Errorf("no searchable properties found in entity %s", s.metadata.Name)
+		return errNoSearchableProperties(s.metadata.Name)
 	}
 
 	return nil
 }
 
+// prepareSearchOption analisa o texto do $search (o parser de URL guarda apenas o texto)
+// e valida a expressão contra as propriedades pesquisáveis da entidade
+func (s *BaseEntityService) prepareSearchOption(ctx context.Context, searchOption *SearchOption) (*SearchOption, error) {
+	if searchOption.Expression == nil && strings.TrimSpace(searchOption.RawQuery) != "" {
+		parsed, err := s.ParseSearchQuery(ctx, searchOption.RawQuery)
+		if err != nil {
+			return nil, NewODataError("InvalidSearch", err.Error()).
+				WithStatus(http.StatusBadRequest).
+				WithTarget("$search")
+		}
+		searchOption = parsed
+	}
+
+	if err := s.processSearchOption(ctx, searchOption); err != nil {
+		return nil, fmt.Errorf("failed to process search option: %w", err)
+	}
+	return searchOption, nil
+}
+
+// prepareComputeOption analisa o texto do $compute (o parser de URL guarda apenas o texto)
+// e valida as expressões contra as propriedades da entidade
+func (s *BaseEntityService) prepareComputeOption(ctx context.Context, computeOption *ComputeOption) (*ComputeOption, error) {
+	if len(computeOption.Expressions) == 1 && computeOption.Expressions[0].ParseTree == nil {
+		parsed, err := s.ParseComputeQuery(ctx, computeOption.Expressions[0].Expression)
+		if err != nil {
+			return nil, NewODataError("InvalidCompute", err.Error()).
+				WithStatus(http.StatusBadRequest).
+				WithTarget("$compute")
+		}
+		computeOption = parsed
+	}
+
+	if err := s.processComputeOption(ctx, computeOption); err != nil {
+		return nil, NewODataError("InvalidCompute", err.Error()).
+			WithStatus(http.StatusBadRequest).
+			WithTarget("$compute")
+	}
+	return computeOption, nil
+}
+
 // ParseComputeQuery analisa uma string $compute e retorna ComputeOption
 func (s *BaseEntityService) ParseComputeQuery(ctx context.Context, computeStr string) (*ComputeOption, error) {
 	if computeStr == "" {
@@ -0,0 +1,156 @@
+package odata
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// ATIVAÇÃO DE ENTIDADES EM TEMPO DE EXECUÇÃO (MANUTENÇÃO)
+// =======================================================================================
+
+// Códigos de erro das entidades desativadas ou somente leitura em tempo de execução
+const (
+	EntityDisabledCode = "EntityDisabled"
+	EntityReadOnlyCode = "EntityReadOnly"
+)
+
+// EntityState estado de uma entidade em tempo de execução
+type EntityState struct {
+	Name     string `json:"name"`
+	Enabled  bool   `json:"enabled"`  // false responde 503 a todas as operações
+	ReadOnly bool   `json:"readOnly"` // true responde 405 às operações de escrita
+}
+
+// entityToggle estado alterado de uma entidade (entidades ausentes estão ativas)
+type entityToggle struct {
+	disabled bool
+	readOnly bool
+}
+
+// SetEntityEnabled ativa ou desativa a entidade sem reiniciar o servidor. Desativada, todas as
+// operações (inclusive em $batch e PATCH hierárquico) respondem 503 com Retry-After
+func (s *Server) SetEntityEnabled(name string, enabled bool) *Server {
+	s.updateEntityToggle(name, func(toggle *entityToggle) { toggle.disabled = !enabled })
+	return s
+}
+
+// SetEntityReadOnly coloca a entidade em modo somente leitura sem reiniciar o servidor. As
+// operações de escrita respondem 405 com Allow e Retry-After
+func (s *Server) SetEntityReadOnly(name string, readOnly bool) *Server {
+	s.updateEntityToggle(name, func(toggle *entityToggle) { toggle.readOnly = readOnly })
+	return s
+}
+
+// GetEntityState retorna o estado da entidade em tempo de execução
+func (s *Server) GetEntityState(name string) EntityState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	toggle := s.entityToggles[name]
+	return EntityState{Name: name, Enabled: !toggle.disabled, ReadOnly: toggle.readOnly}
+}
+
+// updateEntityToggle altera o estado da entidade, removendo-o quando volta ao padrão
+func (s *Server) updateEntityToggle(name string, update func(*entityToggle)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	toggle := s.entityToggles[name]
+	update(&toggle)
+	if toggle == (entityToggle{}) {
+		delete(s.entityToggles, name)
+		return
+	}
+	if s.entityToggles == nil {
+		s.entityToggles = make(map[string]entityToggle)
+	}
+	s.entityToggles[name] = toggle
+}
+
+// checkEntityAvailability recusa a operação se a entidade estiver desativada (503) ou, para
+// escritas, em modo somente leitura (405)
+func (s *Server) checkEntityAvailability(entityName, operation string) error {
+	state := s.GetEntityState(entityName)
+	if !state.Enabled {
+		return NewODataError(EntityDisabledCode, "Entidade "+entityName+" temporariamente indisponível").WithStatus(http.StatusServiceUnavailable)
+	}
+	if state.ReadOnly && operation != fiber.MethodGet {
+		return NewODataError(EntityReadOnlyCode, "Entidade "+entityName+" temporariamente somente leitura").WithStatus(http.StatusMethodNotAllowed)
+	}
+	return nil
+}
+
+// maintenanceHeaders retorna os cabeçalhos Retry-After (e Allow no 405) quando o erro foi
+// causado pelo estado da entidade em tempo de execução ou pelo modo de manutenção
+func (s *Server) maintenanceHeaders(err error) map[string]string {
+	var odataErr *ODataError
+	if !errors.As(err, &odataErr) || (odataErr.Code != EntityDisabledCode && odataErr.Code != EntityReadOnlyCode && odataErr.Code != MaintenanceCode) {
+		return nil
+	}
+	headers := make(map[string]string)
+	if retryAfter := s.config.MaintenanceRetryAfter; retryAfter > 0 {
+		headers[fiber.HeaderRetryAfter] = strconv.Itoa(int((retryAfter + time.Second - 1) / time.Second))
+	}
+	if odataErr.Code == EntityReadOnlyCode {
+		headers[fiber.HeaderAllow] = strings.Join([]string{fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions}, ", ")
+	}
+	return headers
+}
+
+// =======================================================================================
+// ENDPOINT ADMINISTRATIVO
+// =======================================================================================
+
+// entityStatePatch alterações do estado da entidade pelo endpoint administrativo
+type entityStatePatch struct {
+	Enabled  *bool `json:"enabled"`
+	ReadOnly *bool `json:"readOnly"`
+}
+
+// handleAdminEntities lista o estado de todas as entidades registradas
+func (s *Server) handleAdminEntities(c fiber.Ctx) error {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.entities))
+	for name := range s.entities {
+		names = append(names, name)
+	}
+	s.mu.RUnlock()
+	sort.Strings(names)
+
+	states := make([]EntityState, 0, len(names))
+	for _, name := range names {
+		states = append(states, s.GetEntityState(name))
+	}
+	return c.JSON(fiber.Map{"value": states})
+}
+
+// handleAdminEntityState altera o estado de uma entidade ({"enabled": false, "readOnly": true})
+func (s *Server) handleAdminEntityState(c fiber.Ctx) error {
+	// O parâmetro referencia o buffer da requisição: a cópia é usada como chave do estado
+	name := strings.Clone(c.Params("name"))
+	if s.GetEntityService(name) == nil {
+		return s.writeODataError(c, http.StatusNotFound,
+			NewODataError("NotFound", "Entity '"+name+"' not found"), nil)
+	}
+
+	var patch entityStatePatch
+	if err := c.Bind().Body(&patch); err != nil {
+		return s.writeODataError(c, http.StatusBadRequest,
+			NewODataError("BadRequest", "Invalid JSON"), err)
+	}
+	if patch.Enabled != nil {
+		s.SetEntityEnabled(name, *patch.Enabled)
+	}
+	if patch.ReadOnly != nil {
+		s.SetEntityReadOnly(name, *patch.ReadOnly)
+	}
+
+	state := s.GetEntityState(name)
+	s.logger.Printf("🔧 Entidade %s: enabled=%t readOnly=%t", name, state.Enabled, state.ReadOnly)
+	return c.JSON(state)
+}
@@ -0,0 +1,102 @@
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_EntityToggles(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	app := fiber.New()
+	app.Get("/odata/OrderItems(*)", server.handleEntityById)
+	app.Delete("/odata/OrderItems(*)", server.handleEntityById)
+
+	t.Run("Entidade desativada responde 503", func(t *testing.T) {
+		server.SetEntityEnabled("OrderItems", false)
+		defer server.SetEntityEnabled("OrderItems", true)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/odata/OrderItems(1)", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "60", resp.Header.Get(fiber.HeaderRetryAfter))
+		assert.Equal(t, EntityState{Name: "OrderItems", Enabled: false}, server.GetEntityState("OrderItems"))
+	})
+
+	t.Run("Somente leitura responde 405 às escritas", func(t *testing.T) {
+		server.SetEntityReadOnly("OrderItems", true).SetMaintenanceRetryAfter(90 * time.Second)
+		defer server.SetEntityReadOnly("OrderItems", false)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/odata/OrderItems(1)", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+		assert.Equal(t, "90", resp.Header.Get(fiber.HeaderRetryAfter))
+		assert.Equal(t, "GET, HEAD, OPTIONS", resp.Header.Get(fiber.HeaderAllow))
+		assert.Equal(t, int64(1), orderItemOwner(t, server, 1))
+
+		resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/odata/OrderItems(1)", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Batch e PATCH hierárquico", func(t *testing.T) {
+		server.SetEntityEnabled("OrderItems", false).SetMaintenanceRetryAfter(time.Minute)
+		defer server.SetEntityEnabled("OrderItems", true)
+
+		processor := NewBatchProcessor(server)
+		resp, err := processor.executeOperation(context.Background(), &BatchHTTPOperation{Method: "GET", URL: "/odata/OrderItems", ContentID: "1"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "60", resp.Headers[fiber.HeaderRetryAfter])
+
+		_, err = server.entities["Orders"].(*BaseEntityService).Patch(context.Background(), map[string]any{"id": int64(1)}, map[string]interface{}{
+			"Items@delta": []interface{}{map[string]interface{}{"product": "Cabo"}},
+		})
+		assert.ErrorContains(t, err, "Entidade OrderItems temporariamente indisponível")
+	})
+}
+
+func TestServer_AdminEntities(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	server.SetDiagnostics(&DiagnosticsConfig{
+		Enabled:     true,
+		AdminPrefix: "/admin",
+		Auth: func(c fiber.Ctx) error {
+			c.Locals(UserContextKey, &UserIdentity{Username: "root", Admin: true})
+			return c.Next()
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPatch, "/admin/entities/Customers", strings.NewReader(`{"readOnly":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := server.router.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, server.GetEntityState("Customers").ReadOnly)
+
+	req = httptest.NewRequest(http.MethodPatch, "/admin/entities/Unknown", strings.NewReader(`{"enabled":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = server.router.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = server.router.Test(httptest.NewRequest(http.MethodGet, "/admin/entities", nil))
+	require.NoError(t, err)
+	var body struct {
+		Value []EntityState `json:"value"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, []EntityState{
+		{Name: "Customers", Enabled: true, ReadOnly: true},
+		{Name: "OrderItems", Enabled: true},
+		{Name: "Orders", Enabled: true},
+	}, body.Value)
+}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
@@ -65,6 +69,8 @@ type EventArgs interface {
 	Cancel(reason string)
 	IsCanceled() bool
 	GetCancelReason() string
+	StopPropagation()
+	IsPropagationStopped() bool
 	Manager() *ObjectManager
 	GetManager() *ObjectManager
 }
@@ -78,6 +84,7 @@ type BaseEventArgs struct {
 	canCancel    bool
 	canceled     bool
 	cancelReason string
+	stopped      bool
 }
 
 func (e *BaseEventArgs) GetContext() *EventContext    { return e.Context }
@@ -88,6 +95,13 @@ func (e *BaseEventArgs) SetEntity(entity interface{}) { e.Entity = entity }
 func (e *BaseEventArgs) CanCancel() bool              { return e.canCancel }
 func (e *BaseEventArgs) IsCanceled() bool             { return e.canceled }
 func (e *BaseEventArgs) GetCancelReason() string      { return e.cancelReason }
+func (e *BaseEventArgs) IsPropagationStopped() bool   { return e.stopped }
+
+// StopPropagation impede que os handlers seguintes (de menor prioridade) sejam executados,
+// sem cancelar a operação
+func (e *BaseEventArgs) StopPropagation() {
+	e.stopped = true
+}
 
 func (e *BaseEventArgs) Cancel(reason string) {
 	if e.canCancel {
@@ -210,12 +224,59 @@ func (f EventHandlerFunc) Handle(args EventArgs) error {
 	return f(args)
 }
 
+// EventHandlerConfig configuração de registro de um handler
+type EventHandlerConfig struct {
+	Name     string // Nome exibido em ListEventHandlers (padrão: nome da função)
+	Priority int    // Handlers com maior prioridade executam primeiro (padrão: 0)
+	Async    bool   // Handler executado pelo pool assíncrono
+}
+
+// EventHandlerOption função que modifica a configuração de registro de um handler
+type EventHandlerOption func(*EventHandlerConfig)
+
+// WithPriority define a prioridade do handler (maior prioridade executa primeiro)
+func WithPriority(priority int) EventHandlerOption {
+	return func(config *EventHandlerConfig) {
+		config.Priority = priority
+	}
+}
+
+// WithHandlerName define um nome para identificar o handler
+func WithHandlerName(name string) EventHandlerOption {
+	return func(config *EventHandlerConfig) {
+		config.Name = name
+	}
+}
+
+// EventHandlerInfo descreve um handler registrado
+type EventHandlerInfo struct {
+	EventType  EventType `json:"eventType"`
+	EntityName string    `json:"entityName,omitempty"` // Vazio para handlers globais
+	Name       string    `json:"name"`
+	Priority   int       `json:"priority"`
+	Global     bool      `json:"global"`
+	Async      bool      `json:"async"`
+}
+
+// registeredHandler representa um handler registrado com suas opções
+type registeredHandler struct {
+	handler EventHandler
+	config  EventHandlerConfig
+	scope   string
+}
+
 // EntityEventManager gerencia todos os eventos de entidade
 type EntityEventManager struct {
 	mu       sync.RWMutex
-	handlers map[EventType]map[string][]EventHandler // EventType -> EntityName -> []Handler
-	global   map[EventType][]EventHandler            // Handlers globais por tipo
+	handlers map[EventType]map[string][]registeredHandler // EventType -> EntityName -> []Handler
+	global   map[EventType][]registeredHandler            // Handlers globais por tipo
 	logger   *log.Logger
+
+	asyncMu     sync.Mutex
+	async       *AsyncEventDispatcher // Pool de workers dos handlers assíncronos (sob demanda)
+	asyncConfig *AsyncEventConfig
+
+	panics atomic.Int64 // Panics recuperados em handlers síncronos
 }
 
 // NewEntityEventManager cria um novo gerenciador de eventos
@@ -225,79 +286,98 @@ func NewEntityEventManager(logger *log.Logger) *EntityEventManager {
 	}
 
 	return &EntityEventManager{
-		handlers: make(map[EventType]map[string][]EventHandler),
-		global:   make(map[EventType][]EventHandler),
+		handlers: make(map[EventType]map[string][]registeredHandler),
+		global:   make(map[EventType][]registeredHandler),
 		logger:   logger,
 	}
 }
 
+// newRegisteredHandler aplica as opções de registro a um handler
+func newRegisteredHandler(handler EventHandler, scope string, opts []EventHandlerOption) registeredHandler {
+	config := EventHandlerConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.Name == "" {
+		config.Name = handlerName(handler)
+	}
+	return registeredHandler{handler: handler, config: config, scope: scope}
+}
+
+// handlerName retorna o nome da função do handler para inspeção
+func handlerName(handler EventHandler) string {
+	if f, ok := handler.(EventHandlerFunc); ok {
+		if fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+	return fmt.Sprintf("%T", handler)
+}
+
 // Subscribe registra um handler para um evento específico de uma entidade
-func (em *EntityEventManager) Subscribe(eventType EventType, entityName string, handler EventHandler) {
+func (em *EntityEventManager) Subscribe(eventType EventType, entityName string, handler EventHandler, opts ...EventHandlerOption) {
 	em.mu.Lock()
 	defer em.mu.Unlock()
 
 	if em.handlers[eventType] == nil {
-		em.handlers[eventType] = make(map[string][]EventHandler)
+		em.handlers[eventType] = make(map[string][]registeredHandler)
 	}
 
-	em.handlers[eventType][entityName] = append(em.handlers[eventType][entityName], handler)
+	em.handlers[eventType][entityName] = append(em.handlers[eventType][entityName], newRegisteredHandler(handler, entityName, opts))
 	em.logger.Printf("Handler registrado para evento %s da entidade %s", eventType, entityName)
 }
 
 // SubscribeGlobal registra um handler global para um tipo de evento
-func (em *EntityEventManager) SubscribeGlobal(eventType EventType, handler EventHandler) {
+func (em *EntityEventManager) SubscribeGlobal(eventType EventType, handler EventHandler, opts ...EventHandlerOption) {
 	em.mu.Lock()
 	defer em.mu.Unlock()
 
-	em.global[eventType] = append(em.global[eventType], handler)
+	em.global[eventType] = append(em.global[eventType], newRegisteredHandler(handler, "global", opts))
 	em.logger.Printf("Handler global registrado para evento %s", eventType)
 }
 
 // SubscribeFunc registra uma função como handler
-func (em *EntityEventManager) SubscribeFunc(eventType EventType, entityName string, handler func(args EventArgs) error) {
-	em.Subscribe(eventType, entityName, EventHandlerFunc(handler))
+func (em *EntityEventManager) SubscribeFunc(eventType EventType, entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	em.Subscribe(eventType, entityName, EventHandlerFunc(handler), opts...)
 }
 
 // SubscribeGlobalFunc registra uma função como handler global
-func (em *EntityEventManager) SubscribeGlobalFunc(eventType EventType, handler func(args EventArgs) error) {
-	em.SubscribeGlobal(eventType, EventHandlerFunc(handler))
+func (em *EntityEventManager) SubscribeGlobalFunc(eventType EventType, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	em.SubscribeGlobal(eventType, EventHandlerFunc(handler), opts...)
 }
 
-// Emit dispara um evento
-func (em *EntityEventManager) Emit(args EventArgs) error {
+// orderedHandlers retorna os handlers aplicáveis na ordem de execução:
+// maior prioridade primeiro; em caso de empate, globais antes dos específicos e
+// depois por ordem de registro
+func (em *EntityEventManager) orderedHandlers(eventType EventType, entityName string) []registeredHandler {
 	em.mu.RLock()
 	defer em.mu.RUnlock()
 
-	eventType := args.GetEventType()
-	entityName := args.GetEntityName()
+	handlers := make([]registeredHandler, 0, len(em.global[eventType])+len(em.handlers[eventType][entityName]))
+	handlers = append(handlers, em.global[eventType]...)
+	handlers = append(handlers, em.handlers[eventType][entityName]...)
 
-	// Executa handlers globais primeiro
-	if globalHandlers, exists := em.global[eventType]; exists {
-		for _, handler := range globalHandlers {
-			if err := em.executeHandler(handler, args, "global"); err != nil {
-				return err
-			}
+	sort.SliceStable(handlers, func(i, j int) bool {
+		return handlers[i].config.Priority > handlers[j].config.Priority
+	})
+	return handlers
+}
 
-			// Verifica se o evento foi cancelado
-			if args.IsCanceled() {
-				return fmt.Errorf("evento cancelado: %s", args.GetCancelReason())
-			}
+// Emit dispara um evento
+func (em *EntityEventManager) Emit(args EventArgs) error {
+	for _, registered := range em.orderedHandlers(args.GetEventType(), args.GetEntityName()) {
+		if err := em.executeHandler(registered.handler, args, registered.scope); err != nil {
+			return err
 		}
-	}
 
-	// Executa handlers específicos da entidade
-	if entityHandlers, exists := em.handlers[eventType]; exists {
-		if handlers, exists := entityHandlers[entityName]; exists {
-			for _, handler := range handlers {
-				if err := em.executeHandler(handler, args, entityName); err != nil {
-					return err
-				}
-
-				// Verifica se o evento foi cancelado
-				if args.IsCanceled() {
-					return fmt.Errorf("evento cancelado: %s", args.GetCancelReason())
-				}
-			}
+		// Verifica se o evento foi cancelado
+		if args.IsCanceled() {
+			return fmt.Errorf("evento cancelado: %s", args.GetCancelReason())
+		}
+
+		// Handler interrompeu a propagação para os demais
+		if args.IsPropagationStopped() {
+			break
 		}
 	}
 
@@ -305,10 +385,17 @@ func (em *EntityEventManager) Emit(args EventArgs) error {
 }
 
 // executeHandler executa um handler com tratamento de erro
-func (em *EntityEventManager) executeHandler(handler EventHandler, args EventArgs, scope string) error {
+func (em *EntityEventManager) executeHandler(handler EventHandler, args EventArgs, scope string) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			em.logger.Printf("PANIC no handler %s do evento %s: %v", scope, args.GetEventType(), r)
+			em.panics.Add(1)
+			requestID := ""
+			if ctx := args.GetContext(); ctx != nil {
+				requestID = ctx.RequestID
+			}
+			stack := debug.Stack()
+			em.logger.Printf("PANIC no handler %s do evento %s [request_id=%s]: %v\n%s", scope, args.GetEventType(), requestID, r, stack)
+			err = fmt.Errorf("erro no handler %s: %w", scope, &PanicError{Value: r, Stack: stack})
 		}
 	}()
 
@@ -320,6 +407,11 @@ func (em *EntityEventManager) executeHandler(handler EventHandler, args EventArg
 	return nil
 }
 
+// PanicCount retorna a quantidade de panics recuperados nos handlers de evento
+func (em *EntityEventManager) PanicCount() int64 {
+	return em.panics.Load()
+}
+
 // GetHandlerCount retorna o número de handlers registrados
 func (em *EntityEventManager) GetHandlerCount(eventType EventType, entityName string) int {
 	em.mu.RLock()
@@ -376,8 +468,8 @@ func (em *EntityEventManager) Clear() {
 	em.mu.Lock()
 	defer em.mu.Unlock()
 
-	em.handlers = make(map[EventType]map[string][]EventHandler)
-	em.global = make(map[EventType][]EventHandler)
+	em.handlers = make(map[EventType]map[string][]registeredHandler)
+	em.global = make(map[EventType][]registeredHandler)
 	em.logger.Printf("Todos os handlers foram removidos")
 }
 
@@ -392,6 +484,46 @@ func (em *EntityEventManager) ClearEntity(entityName string) {
 	em.logger.Printf("Handlers da entidade %s foram removidos", entityName)
 }
 
+// ListHandlers lista os handlers aplicáveis a uma entidade (globais e específicos),
+// agrupados por tipo de evento e na ordem em que serão executados
+func (em *EntityEventManager) ListHandlers(entityName string) []EventHandlerInfo {
+	em.mu.RLock()
+	eventTypes := make([]string, 0)
+	seen := make(map[EventType]bool)
+	for eventType := range em.global {
+		seen[eventType] = true
+	}
+	for eventType, entityHandlers := range em.handlers {
+		if len(entityHandlers[entityName]) > 0 {
+			seen[eventType] = true
+		}
+	}
+	em.mu.RUnlock()
+
+	for eventType := range seen {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+	sort.Strings(eventTypes)
+
+	infos := make([]EventHandlerInfo, 0)
+	for _, eventType := range eventTypes {
+		for _, registered := range em.orderedHandlers(EventType(eventType), entityName) {
+			info := EventHandlerInfo{
+				EventType: EventType(eventType),
+				Name:      registered.config.Name,
+				Priority:  registered.config.Priority,
+				Global:    registered.scope == "global",
+				Async:     registered.config.Async,
+			}
+			if !info.Global {
+				info.EntityName = entityName
+			}
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
 // Funções auxiliares para criar argumentos de evento
 
 // NewEntityGetArgs cria argumentos para evento EntityGet
@@ -0,0 +1,305 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// =======================================================================================
+// HANDLERS DE EVENTO ASSÍNCRONOS
+// =======================================================================================
+
+// AsyncEventConfig configura o pool de workers dos handlers assíncronos
+type AsyncEventConfig struct {
+	Workers      int                             // Número de workers concorrentes
+	QueueSize    int                             // Capacidade da fila de eventos pendentes
+	MaxRetries   int                             // Tentativas adicionais em caso de erro
+	RetryBackoff time.Duration                   // Espera base entre tentativas (multiplicada pela tentativa)
+	DeadLetter   func(args EventArgs, err error) // Chamado quando o evento esgota as tentativas ou é rejeitado
+}
+
+// DefaultAsyncEventConfig retorna configuração padrão para handlers assíncronos
+func DefaultAsyncEventConfig() *AsyncEventConfig {
+	return &AsyncEventConfig{
+		Workers:      4,
+		QueueSize:    1000,
+		MaxRetries:   3,
+		RetryBackoff: 100 * time.Millisecond,
+	}
+}
+
+// asyncEventJob representa a execução pendente de um handler assíncrono
+type asyncEventJob struct {
+	handler EventHandler
+	args    EventArgs
+	scope   string
+}
+
+// AsyncEventDispatcher executa handlers de evento em um pool limitado de workers
+type AsyncEventDispatcher struct {
+	config *AsyncEventConfig
+	logger *log.Logger
+	queue  chan asyncEventJob
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncEventDispatcher cria o dispatcher e inicia os workers
+func NewAsyncEventDispatcher(config *AsyncEventConfig, logger *log.Logger) *AsyncEventDispatcher {
+	if config == nil {
+		config = DefaultAsyncEventConfig()
+	}
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	if config.QueueSize < 0 {
+		config.QueueSize = 0
+	}
+	if logger == nil {
+		logger = log.New(log.Writer(), "[AsyncEvents] ", log.LstdFlags|log.Lshortfile)
+	}
+
+	d := &AsyncEventDispatcher{
+		config: config,
+		logger: logger,
+		queue:  make(chan asyncEventJob, config.QueueSize),
+	}
+
+	for i := 0; i < config.Workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+
+	return d
+}
+
+// Enqueue agenda a execução de um handler sem bloquear a requisição
+func (d *AsyncEventDispatcher) Enqueue(handler EventHandler, args EventArgs, scope string) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	job := asyncEventJob{handler: handler, args: detachEventArgs(args), scope: scope}
+
+	if d.closed {
+		err := fmt.Errorf("dispatcher assíncrono encerrado")
+		d.deadLetter(job, err)
+		return err
+	}
+
+	select {
+	case d.queue <- job:
+		return nil
+	default:
+		err := fmt.Errorf("fila de eventos assíncronos cheia (%d)", d.config.QueueSize)
+		d.deadLetter(job, err)
+		return err
+	}
+}
+
+// QueueStats retorna a quantidade de eventos pendentes e a capacidade da fila
+func (d *AsyncEventDispatcher) QueueStats() (depth, capacity int) {
+	return len(d.queue), cap(d.queue)
+}
+
+// Drain encerra o recebimento de novos eventos e aguarda a fila esvaziar
+func (d *AsyncEventDispatcher) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timeout aguardando handlers assíncronos: %w", ctx.Err())
+	}
+}
+
+// worker consome a fila até o encerramento do dispatcher
+func (d *AsyncEventDispatcher) worker() {
+	defer d.wg.Done()
+
+	for job := range d.queue {
+		d.process(job)
+	}
+}
+
+// process executa o handler aplicando a política de retry
+func (d *AsyncEventDispatcher) process(job asyncEventJob) {
+	var err error
+	for attempt := 0; attempt <= d.config.MaxRetries; attempt++ {
+		if attempt > 0 && d.config.RetryBackoff > 0 {
+			time.Sleep(d.config.RetryBackoff * time.Duration(attempt))
+		}
+
+		if err = d.invoke(job); err == nil {
+			return
+		}
+
+		d.logger.Printf("Erro no handler assíncrono %s do evento %s (tentativa %d/%d): %v",
+			job.scope, job.args.GetEventType(), attempt+1, d.config.MaxRetries+1, err)
+	}
+
+	d.deadLetter(job, err)
+}
+
+// invoke executa o handler convertendo panics em erro
+func (d *AsyncEventDispatcher) invoke(job asyncEventJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return job.handler.Handle(job.args)
+}
+
+// deadLetter entrega o evento ao callback de dead-letter ou apenas loga
+func (d *AsyncEventDispatcher) deadLetter(job asyncEventJob, err error) {
+	if d.config.DeadLetter == nil {
+		d.logger.Printf("Evento %s descartado (handler %s): %v", job.args.GetEventType(), job.scope, err)
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			d.logger.Printf("PANIC no dead-letter do evento %s: %v", job.args.GetEventType(), r)
+		}
+	}()
+	d.config.DeadLetter(job.args, err)
+}
+
+// detachEventArgs copia os argumentos desvinculando-os da requisição HTTP, que é
+// reciclada pelo Fiber após o envio da resposta
+func detachEventArgs(args EventArgs) EventArgs {
+	value := reflect.ValueOf(args)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return args
+	}
+
+	clone := reflect.New(value.Elem().Type())
+	clone.Elem().Set(value.Elem())
+
+	field := clone.Elem().FieldByName("BaseEventArgs")
+	if !field.IsValid() || field.Kind() != reflect.Ptr || field.IsNil() || !field.CanSet() {
+		return args
+	}
+
+	base := *field.Interface().(*BaseEventArgs)
+	base.Context = detachEventContext(base.Context)
+	field.Set(reflect.ValueOf(&base))
+
+	detached, ok := clone.Interface().(EventArgs)
+	if !ok {
+		return args
+	}
+	return detached
+}
+
+// detachEventContext copia o contexto do evento sem referências à requisição
+func detachEventContext(ctx *EventContext) *EventContext {
+	if ctx == nil {
+		return nil
+	}
+
+	detached := *ctx
+	detached.Context = context.Background()
+	detached.FiberContext = nil
+	detached.Extra = make(map[string]interface{}, len(ctx.Extra))
+	for k, v := range ctx.Extra {
+		detached.Extra[k] = v
+	}
+	return &detached
+}
+
+// SetAsyncConfig define a configuração do pool assíncrono (antes do primeiro handler assíncrono)
+func (em *EntityEventManager) SetAsyncConfig(config *AsyncEventConfig) {
+	em.asyncMu.Lock()
+	defer em.asyncMu.Unlock()
+
+	if em.async != nil {
+		em.logger.Printf("Pool assíncrono já iniciado; nova configuração ignorada")
+		return
+	}
+	em.asyncConfig = config
+}
+
+// asyncDispatcher retorna o dispatcher assíncrono, criando-o sob demanda
+func (em *EntityEventManager) asyncDispatcher() *AsyncEventDispatcher {
+	em.asyncMu.Lock()
+	defer em.asyncMu.Unlock()
+
+	if em.async == nil {
+		em.async = NewAsyncEventDispatcher(em.asyncConfig, em.logger)
+	}
+	return em.async
+}
+
+// asyncHandler cria um handler síncrono que apenas agenda a execução do handler real
+func (em *EntityEventManager) asyncHandler(handler EventHandler, scope string) EventHandler {
+	dispatcher := em.asyncDispatcher()
+	return EventHandlerFunc(func(args EventArgs) error {
+		// Erros são tratados pela política de retry/dead-letter e não afetam a requisição
+		_ = dispatcher.Enqueue(handler, args, scope)
+		return nil
+	})
+}
+
+// asyncHandlerOption marca o registro como assíncrono preservando o nome do handler original
+func asyncHandlerOption(handler EventHandler) EventHandlerOption {
+	return func(config *EventHandlerConfig) {
+		config.Async = true
+		config.Name = handlerName(handler)
+	}
+}
+
+// SubscribeAsync registra um handler executado de forma assíncrona para uma entidade
+func (em *EntityEventManager) SubscribeAsync(eventType EventType, entityName string, handler EventHandler, opts ...EventHandlerOption) {
+	opts = append([]EventHandlerOption{asyncHandlerOption(handler)}, opts...)
+	em.Subscribe(eventType, entityName, em.asyncHandler(handler, entityName), opts...)
+}
+
+// SubscribeGlobalAsync registra um handler global executado de forma assíncrona
+func (em *EntityEventManager) SubscribeGlobalAsync(eventType EventType, handler EventHandler, opts ...EventHandlerOption) {
+	opts = append([]EventHandlerOption{asyncHandlerOption(handler)}, opts...)
+	em.SubscribeGlobal(eventType, em.asyncHandler(handler, "global"), opts...)
+}
+
+// AsyncQueueStats retorna a profundidade e a capacidade da fila de eventos assíncronos
+// (zero enquanto nenhum handler assíncrono foi registrado)
+func (em *EntityEventManager) AsyncQueueStats() (depth, capacity int) {
+	em.asyncMu.Lock()
+	dispatcher := em.async
+	em.asyncMu.Unlock()
+
+	if dispatcher == nil {
+		return 0, 0
+	}
+	return dispatcher.QueueStats()
+}
+
+// DrainAsync aguarda a conclusão dos handlers assíncronos pendentes
+func (em *EntityEventManager) DrainAsync(ctx context.Context) error {
+	em.asyncMu.Lock()
+	dispatcher := em.async
+	em.asyncMu.Unlock()
+
+	if dispatcher == nil {
+		return nil
+	}
+	return dispatcher.Drain(ctx)
+}
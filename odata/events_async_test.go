@@ -0,0 +1,88 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsyncEvents_HandlerRunsOffRequestPath(t *testing.T) {
+	em := NewEntityEventManager(log.New(os.Stdout, "[TEST] ", log.LstdFlags))
+
+	release := make(chan struct{})
+	var calls int32
+	em.SubscribeAsync(EventEntityInserted, "Products", EventHandlerFunc(func(args EventArgs) error {
+		<-release
+		assert.Nil(t, args.GetContext().FiberContext)
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}))
+
+	ctx := &EventContext{Context: context.Background(), EntityName: "Products"}
+	start := time.Now()
+	require.NoError(t, em.Emit(NewEntityInsertedArgs(ctx, map[string]interface{}{"id": 1})))
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "Emit não deve aguardar o handler assíncrono")
+
+	close(release)
+	require.NoError(t, em.DrainAsync(context.Background()))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestAsyncEvents_RetryAndDeadLetter(t *testing.T) {
+	em := NewEntityEventManager(log.New(os.Stdout, "[TEST] ", log.LstdFlags))
+
+	var mu sync.Mutex
+	var deadLetters []error
+	em.SetAsyncConfig(&AsyncEventConfig{
+		Workers:    1,
+		QueueSize:  10,
+		MaxRetries: 2,
+		DeadLetter: func(args EventArgs, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			deadLetters = append(deadLetters, err)
+		},
+	})
+
+	var attempts int32
+	em.SubscribeGlobalAsync(EventEntityDeleted, EventHandlerFunc(func(args EventArgs) error {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return errors.New("temporário")
+		}
+		return nil
+	}))
+	em.SubscribeAsync(EventEntityDeleted, "Products", EventHandlerFunc(func(args EventArgs) error {
+		panic("falha permanente")
+	}))
+
+	ctx := &EventContext{Context: context.Background(), EntityName: "Products"}
+	require.NoError(t, em.Emit(NewEntityDeletedArgs(ctx, nil, nil)))
+	require.NoError(t, em.DrainAsync(context.Background()))
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts), "handler deve ser repetido até obter sucesso")
+	require.Len(t, deadLetters, 1)
+	assert.Contains(t, deadLetters[0].Error(), "falha permanente")
+
+	// Após o drain novos eventos vão direto para o dead-letter
+	require.NoError(t, em.Emit(NewEntityDeletedArgs(ctx, nil, nil)))
+	assert.Len(t, deadLetters, 3)
+}
+
+func TestDetachEventArgs_PreservesConcreteType(t *testing.T) {
+	ctx := &EventContext{Context: context.Background(), EntityName: "Products", Extra: map[string]interface{}{"k": "v"}}
+	original := NewEntityInsertedArgs(ctx, "entity")
+
+	detached, ok := detachEventArgs(original).(*EntityInsertedArgs)
+	require.True(t, ok)
+	assert.Equal(t, "entity", detached.CreatedEntity)
+	assert.NotSame(t, original.Context, detached.Context)
+	assert.Equal(t, "v", detached.Context.Extra["k"])
+}
@@ -0,0 +1,81 @@
+package odata
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPriorityTestManager() *EntityEventManager {
+	return NewEntityEventManager(log.New(os.Stdout, "[TEST] ", log.LstdFlags))
+}
+
+func TestEventManager_PriorityOrder(t *testing.T) {
+	em := newPriorityTestManager()
+
+	var order []string
+	record := func(name string) func(args EventArgs) error {
+		return func(args EventArgs) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	em.SubscribeFunc(EventEntityInserting, "Users", record("entity-default"))
+	em.SubscribeGlobalFunc(EventEntityInserting, record("global-default"))
+	em.SubscribeFunc(EventEntityInserting, "Users", record("entity-high"), WithPriority(100))
+	em.SubscribeGlobalFunc(EventEntityInserting, record("global-low"), WithPriority(-10))
+	em.SubscribeGlobalFunc(EventEntityInserting, record("global-mid"), WithPriority(50))
+
+	ctx := &EventContext{Context: context.Background(), EntityName: "Users"}
+	require.NoError(t, em.Emit(NewEntityInsertingArgs(ctx, map[string]interface{}{})))
+
+	assert.Equal(t, []string{"entity-high", "global-mid", "global-default", "entity-default", "global-low"}, order)
+}
+
+func TestEventManager_StopPropagation(t *testing.T) {
+	em := newPriorityTestManager()
+
+	var executed []string
+	em.SubscribeGlobalFunc(EventEntityModifying, func(args EventArgs) error {
+		executed = append(executed, "first")
+		args.StopPropagation()
+		return nil
+	}, WithPriority(10))
+	em.SubscribeFunc(EventEntityModifying, "Users", func(args EventArgs) error {
+		executed = append(executed, "second")
+		return nil
+	})
+
+	ctx := &EventContext{Context: context.Background(), EntityName: "Users"}
+	args := NewEntityModifyingArgs(ctx, nil, map[string]interface{}{}, nil)
+	require.NoError(t, em.Emit(args), "interromper a propagação não cancela a operação")
+
+	assert.Equal(t, []string{"first"}, executed)
+	assert.True(t, args.IsPropagationStopped())
+	assert.False(t, args.IsCanceled())
+}
+
+func TestEventManager_ListHandlers(t *testing.T) {
+	em := newPriorityTestManager()
+
+	em.SubscribeFunc(EventEntityInserted, "Users", func(args EventArgs) error { return nil }, WithHandlerName("audit"))
+	em.SubscribeGlobalFunc(EventEntityInserted, func(args EventArgs) error { return nil }, WithHandlerName("metrics"), WithPriority(5))
+	em.SubscribeFunc(EventEntityDeleting, "Orders", func(args EventArgs) error { return nil })
+	em.SubscribeAsync(EventEntityDeleted, "Users", EventHandlerFunc(func(args EventArgs) error { return nil }))
+	defer em.DrainAsync(context.Background())
+
+	infos := em.ListHandlers("Users")
+	require.Len(t, infos, 3)
+
+	assert.Equal(t, EventEntityDeleted, infos[0].EventType)
+	assert.True(t, infos[0].Async)
+	assert.Contains(t, infos[0].Name, "TestEventManager_ListHandlers")
+
+	assert.Equal(t, EventHandlerInfo{EventType: EventEntityInserted, Name: "metrics", Priority: 5, Global: true}, infos[1])
+	assert.Equal(t, EventHandlerInfo{EventType: EventEntityInserted, EntityName: "Users", Name: "audit"}, infos[2])
+}
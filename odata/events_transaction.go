@@ -0,0 +1,162 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// =======================================================================================
+// EVENTOS COM ESCOPO TRANSACIONAL
+// =======================================================================================
+
+const (
+	// Eventos de transação (changesets)
+	EventTransactionCommitted  EventType = "TransactionCommitted"
+	EventTransactionRolledBack EventType = "TransactionRolledBack"
+)
+
+// TransactionEventArgs argumentos para eventos TransactionCommitted/TransactionRolledBack
+type TransactionEventArgs struct {
+	*BaseEventArgs
+	TransactionID string
+	Events        []EventArgs // Eventos "after" disparados (commit) ou descartados (rollback)
+	Error         error       // Motivo do rollback, quando disponível
+}
+
+// EventTransaction acumula os eventos "after" de operações executadas dentro de uma
+// transação e só os dispara após o commit, descartando-os em caso de rollback
+type EventTransaction struct {
+	ID       string
+	context  *EventContext
+	manager  *EntityEventManager
+	mu       sync.Mutex
+	pending  []EventArgs
+	finished bool
+}
+
+type eventTransactionKey struct{}
+
+// BeginTransaction inicia um escopo transacional de eventos
+func (em *EntityEventManager) BeginTransaction(ctx *EventContext) *EventTransaction {
+	if ctx == nil {
+		ctx = &EventContext{Context: context.Background(), Extra: make(map[string]interface{})}
+	}
+	ctx.Timestamp = time.Now().Unix()
+
+	return &EventTransaction{
+		ID:      fmt.Sprintf("tx_%d", time.Now().UnixNano()),
+		context: ctx,
+		manager: em,
+	}
+}
+
+// WithEventTransaction anexa um escopo transacional de eventos ao contexto
+func WithEventTransaction(ctx context.Context, tx *EventTransaction) context.Context {
+	return context.WithValue(ctx, eventTransactionKey{}, tx)
+}
+
+// EventTransactionFromContext retorna o escopo transacional de eventos do contexto, se houver
+func EventTransactionFromContext(ctx context.Context) *EventTransaction {
+	if ctx == nil {
+		return nil
+	}
+	tx, _ := ctx.Value(eventTransactionKey{}).(*EventTransaction)
+	return tx
+}
+
+// isDeferredEvent indica se o evento deve aguardar o commit da transação
+func isDeferredEvent(eventType EventType) bool {
+	switch eventType {
+	case EventEntityInserted, EventEntityModified, EventEntityDeleted:
+		return true
+	}
+	return false
+}
+
+// Emit dispara eventos "before" imediatamente e enfileira eventos "after" até o commit
+func (t *EventTransaction) Emit(args EventArgs) error {
+	if !isDeferredEvent(args.GetEventType()) {
+		return t.manager.Emit(args)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.finished {
+		return fmt.Errorf("transação de eventos %s já finalizada", t.ID)
+	}
+	t.pending = append(t.pending, args)
+	return nil
+}
+
+// Pending retorna a quantidade de eventos aguardando o commit
+func (t *EventTransaction) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+// Commit dispara os eventos pendentes e, em seguida, o evento TransactionCommitted.
+// Os dados já foram persistidos, portanto erros dos handlers são apenas logados
+func (t *EventTransaction) Commit() {
+	events := t.finish()
+	if events == nil {
+		return
+	}
+
+	for _, args := range events {
+		if err := t.manager.Emit(args); err != nil {
+			t.manager.logger.Printf("Erro ao disparar evento %s após commit da transação %s: %v", args.GetEventType(), t.ID, err)
+		}
+	}
+
+	if err := t.manager.Emit(t.newTransactionArgs(EventTransactionCommitted, events, nil)); err != nil {
+		t.manager.logger.Printf("Erro no evento %s da transação %s: %v", EventTransactionCommitted, t.ID, err)
+	}
+}
+
+// Rollback descarta os eventos pendentes e dispara o evento TransactionRolledBack
+func (t *EventTransaction) Rollback(cause error) {
+	events := t.finish()
+	if events == nil {
+		return
+	}
+
+	if err := t.manager.Emit(t.newTransactionArgs(EventTransactionRolledBack, events, cause)); err != nil {
+		t.manager.logger.Printf("Erro no evento %s da transação %s: %v", EventTransactionRolledBack, t.ID, err)
+	}
+}
+
+// finish marca a transação como finalizada e retorna os eventos pendentes (nil se já finalizada)
+func (t *EventTransaction) finish() []EventArgs {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.finished {
+		return nil
+	}
+	t.finished = true
+
+	events := t.pending
+	if events == nil {
+		events = make([]EventArgs, 0)
+	}
+	t.pending = nil
+	return events
+}
+
+// newTransactionArgs cria os argumentos de um evento de transação
+func (t *EventTransaction) newTransactionArgs(eventType EventType, events []EventArgs, cause error) *TransactionEventArgs {
+	return &TransactionEventArgs{
+		BaseEventArgs: &BaseEventArgs{
+			Context:   t.context,
+			EventType: eventType,
+			canCancel: false,
+		},
+		TransactionID: t.ID,
+		Events:        events,
+		Error:         cause,
+	}
+}
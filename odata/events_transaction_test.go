@@ -0,0 +1,135 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestEventTransaction_DefersAfterEventsUntilCommit(t *testing.T) {
+	em := NewEntityEventManager(log.New(os.Stdout, "[TEST] ", log.LstdFlags))
+
+	var inserted, inserting, committed int
+	em.SubscribeFunc(EventEntityInserted, "Products", func(args EventArgs) error { inserted++; return nil })
+	em.SubscribeFunc(EventEntityInserting, "Products", func(args EventArgs) error { inserting++; return nil })
+	em.SubscribeGlobalFunc(EventTransactionCommitted, func(args EventArgs) error {
+		committed++
+		txArgs, ok := args.(*TransactionEventArgs)
+		require.True(t, ok)
+		assert.Len(t, txArgs.Events, 1)
+		return nil
+	})
+
+	ctx := &EventContext{Context: context.Background(), EntityName: "Products"}
+	tx := em.BeginTransaction(ctx)
+
+	require.NoError(t, tx.Emit(NewEntityInsertingArgs(ctx, map[string]interface{}{})))
+	require.NoError(t, tx.Emit(NewEntityInsertedArgs(ctx, map[string]interface{}{})))
+
+	assert.Equal(t, 1, inserting, "eventos before devem ser disparados imediatamente")
+	assert.Equal(t, 0, inserted, "eventos after devem aguardar o commit")
+	assert.Equal(t, 1, tx.Pending())
+
+	tx.Commit()
+	assert.Equal(t, 1, inserted)
+	assert.Equal(t, 1, committed)
+
+	// Commit/Rollback subsequentes são ignorados
+	tx.Commit()
+	tx.Rollback(nil)
+	assert.Equal(t, 1, committed)
+	assert.Error(t, tx.Emit(NewEntityInsertedArgs(ctx, nil)))
+}
+
+func TestEventTransaction_RollbackDiscardsEvents(t *testing.T) {
+	em := NewEntityEventManager(log.New(os.Stdout, "[TEST] ", log.LstdFlags))
+
+	var inserted int
+	var rollbackErr error
+	em.SubscribeFunc(EventEntityInserted, "Products", func(args EventArgs) error { inserted++; return nil })
+	em.SubscribeGlobalFunc(EventTransactionRolledBack, func(args EventArgs) error {
+		rollbackErr = args.(*TransactionEventArgs).Error
+		return nil
+	})
+
+	ctx := &EventContext{Context: context.Background(), EntityName: "Products"}
+	tx := em.BeginTransaction(ctx)
+	require.NoError(t, tx.Emit(NewEntityInsertedArgs(ctx, nil)))
+
+	tx.Rollback(errors.New("falha"))
+	assert.Equal(t, 0, inserted)
+	assert.EqualError(t, rollbackErr, "falha")
+}
+
+func TestBatchChangeset_EventsFollowTransactionOutcome(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE products (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)")
+	require.NoError(t, err)
+
+	provider := &BatchMockDatabaseProvider{
+		beginTxFunc: func(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+			return db.BeginTx(ctx, opts)
+		},
+	}
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+	server := &Server{
+		provider:     provider,
+		entities:     make(map[string]EntityService),
+		logger:       logger,
+		config:       DefaultServerConfig(),
+		mu:           sync.RWMutex{},
+		entityAuth:   make(map[string]EntityAuthConfig),
+		eventManager: NewEntityEventManager(logger),
+	}
+	metadata := EntityMetadata{
+		Name:      "Products",
+		TableName: "products",
+		Properties: []PropertyMetadata{
+			{Name: "ID", ColumnName: "id", IsKey: true, Type: "int"},
+			{Name: "Name", ColumnName: "name", Type: "string"},
+		},
+	}
+	server.entities["Products"] = NewBaseEntityService(provider, metadata, server)
+
+	var inserted, committed, rolledBack int
+	server.OnEntityInserted("Products", func(args EventArgs) error { inserted++; return nil })
+	server.OnTransactionCommitted(func(args EventArgs) error { committed++; return nil })
+	server.OnTransactionRolledBack(func(args EventArgs) error { rolledBack++; return nil })
+
+	processor := NewBatchProcessor(server)
+
+	t.Run("Rollback não dispara EntityInserted", func(t *testing.T) {
+		ops := []*BatchHTTPOperation{
+			{Method: "POST", URL: "/odata/Products", Body: []byte(`{"Name":"A"}`)},
+			{Method: "POST", URL: "/odata/Missing", Body: []byte(`{"Name":"B"}`)},
+		}
+		_, err := processor.executeChangeset(context.Background(), ops, map[string]interface{}{})
+		assert.Error(t, err)
+		assert.Equal(t, 0, inserted)
+		assert.Equal(t, 1, rolledBack)
+		assert.Equal(t, 0, committed)
+	})
+
+	t.Run("Commit dispara eventos pendentes", func(t *testing.T) {
+		ops := []*BatchHTTPOperation{
+			{Method: "POST", URL: "/odata/Products", Body: []byte(`{"Name":"A"}`)},
+			{Method: "POST", URL: "/odata/Products", Body: []byte(`{"Name":"B"}`)},
+		}
+		_, err := processor.executeChangeset(context.Background(), ops, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, inserted)
+		assert.Equal(t, 1, committed)
+	})
+}
@@ -7,10 +7,22 @@ import (
 	"strings"
 )
 
+// expandBatch guarda as entidades relacionadas de uma navegação expandida, agrupadas pelo
+// valor da foreign key, até serem associadas às entidades principais
+type expandBatch struct {
+	navProperty     *PropertyMetadata
+	expandOption    ExpandOption
+	relatedMetadata EntityMetadata
+	grouped         map[interface{}][]any
+	fanOut          bool
+	fanOutLimit     int
+}
+
 // expandWithBatching usa batching (2 queries) para relacionamentos, evitando N+1
 // Estratégia: Faz uma query para buscar todas as entidades relacionadas de uma vez,
 // depois agrupa em memória
 func (s *BaseEntityService) expandWithBatching(
+	ctx context.Context,
 	entities []any,
 	navProperty *PropertyMetadata,
 	expandOption ExpandOption,
@@ -19,6 +31,28 @@ func (s *BaseEntityService) expandWithBatching(
 		return entities, nil
 	}
 
+	batch, err := s.fetchExpandBatch(ctx, entities, navProperty, expandOption)
+	if err != nil {
+		return nil, err
+	}
+	s.applyExpandBatch(ctx, entities, batch)
+	return entities, nil
+}
+
+// fetchExpandBatch executa a query em batch de uma navegação expandida sem alterar as
+// entidades principais (apenas lê as chaves), o que permite buscar navegações em paralelo
+func (s *BaseEntityService) fetchExpandBatch(
+	ctx context.Context,
+	entities []any,
+	navProperty *PropertyMetadata,
+	expandOption ExpandOption,
+) (*expandBatch, error) {
+	batch := &expandBatch{
+		navProperty:  navProperty,
+		expandOption: expandOption,
+		grouped:      make(map[interface{}][]any),
+	}
+
 	log.Printf("🔍 EXPAND: Using BATCHING for %s (evitando N+1)", navProperty.Name)
 
 	// 1. Coletar todos os IDs das entidades principais
@@ -41,20 +75,9 @@ func (s *BaseEntityService) expandWithBatching(
 	}
 
 	if len(parentIDs) == 0 {
-		// Nenhuma entidade tem chave para expansão
-		// Se é collection, define arrays vazios; se não, define nil
-		for _, entity := range entities {
-			orderedEntity, ok := entity.(*OrderedEntity)
-			if !ok {
-				continue
-			}
-			if navProperty.IsCollection {
-				orderedEntity.Set(navProperty.Name, []any{})
-			} else {
-				orderedEntity.Set(navProperty.Name, nil)
-			}
-		}
-		return entities, nil
+		// Nenhuma entidade tem chave para expansão: a associação define arrays vazios
+		// (collection) ou nil
+		return batch, nil
 	}
 
 	// 2. Obter metadados da entidade relacionada
@@ -62,6 +85,7 @@ func (s *BaseEntityService) expandWithBatching(
 	if err != nil {
 		return nil, fmt.Errorf("failed to get related entity metadata: %w", err)
 	}
+	batch.relatedMetadata = relatedMetadata
 
 	// 3. Construir filtro usando IN operador
 	filterParts := make([]string, len(parentIDs))
@@ -83,7 +107,7 @@ func (s *BaseEntityService) expandWithBatching(
 
 	// 4. Criar QueryOptions para a query em batch
 	queryOptions := QueryOptions{}
-	filterQuery, err := s.parseFilterWithTimeout(context.Background(), filterStr)
+	filterQuery, err := s.parseFilterWithTimeout(ctx, filterStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse batch filter: %w", err)
 	}
@@ -103,49 +127,49 @@ func (s *BaseEntityService) expandWithBatching(
 	}
 
 	// 5. Executar query única para todas as entidades relacionadas (BATCHING!)
-	relatedService := NewBaseEntityService(s.provider, relatedMetadata, s.server)
-	response, err := relatedService.Query(context.Background(), queryOptions)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query related entities in batch: %w", err)
-	}
-
-	relatedEntities, ok := response.Value.([]any)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type from batch query")
-	}
+	relatedProvider := s.relatedProvider(relatedMetadata)
+	relatedService := NewBaseEntityService(relatedProvider, relatedMetadata, s.server)
 
-	log.Printf("✅ EXPAND BATCH: Retrieved %d related entities in 1 query", len(relatedEntities))
+	// Com MaxExpandFanOut, cada coleção é lida até o limite (o restante vai no nextLink)
+	batch.fanOutLimit = s.expandFanOutLimit()
+	batch.fanOut = expandFanOutApplies(batch.fanOutLimit, navProperty, expandOption)
 
 	// 6. Agrupar entidades relacionadas por foreign key
-	grouped := make(map[interface{}][]any)
+	if s.isFederated(relatedProvider) {
+		// Entidade relacionada em outro banco: consultas em lotes de chaves, com limite
+		batch.grouped, err = s.queryFederatedExpand(ctx, relatedService, navProperty, parentIDs,
+			expandOption, batch.fanOutLimit, batch.fanOut)
+		if err != nil {
+			return nil, err
+		}
+	} else if batch.fanOut {
+		batch.grouped, err = s.queryExpandFanOut(ctx, relatedService, navProperty, parentIDs,
+			expandFanOutOrder(relatedMetadata, expandOption), batch.fanOutLimit)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		response, err := relatedService.Query(ctx, queryOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query related entities in batch: %w", err)
+		}
 
-	for _, relatedEntity := range relatedEntities {
-		orderedEntity, ok := relatedEntity.(*OrderedEntity)
+		relatedEntities, ok := response.Value.([]any)
 		if !ok {
-			continue
+			return nil, fmt.Errorf("unexpected response type from batch query")
 		}
 
-		// Buscar o valor da foreign key
-		fkValue, exists := orderedEntity.Get(navProperty.Relationship.ReferencedProperty)
-		if !exists {
-			// Tentar case-insensitive
-			for _, prop := range orderedEntity.Properties {
-				if strings.EqualFold(prop.Name, navProperty.Relationship.ReferencedProperty) {
-					fkValue = prop.Value
-					exists = true
-					break
-				}
-			}
-		}
+		log.Printf("✅ EXPAND BATCH: Retrieved %d related entities in 1 query", len(relatedEntities))
 
-		if exists {
-			// Converter para string para comparação consistente
-			fkKey := fmt.Sprintf("%v", fkValue)
-			grouped[fkKey] = append(grouped[fkKey], relatedEntity)
-		}
+		batch.grouped = groupRelatedEntities(relatedEntities, navProperty)
 	}
 
-	// 7. Associar entidades relacionadas às principais
+	return batch, nil
+}
+
+// applyExpandBatch associa as entidades relacionadas às principais (7º passo do batching)
+func (s *BaseEntityService) applyExpandBatch(ctx context.Context, entities []any, batch *expandBatch) {
+	navProperty := batch.navProperty
 	for _, entity := range entities {
 		orderedEntity, ok := entity.(*OrderedEntity)
 		if !ok {
@@ -166,9 +190,15 @@ func (s *BaseEntityService) expandWithBatching(
 		// Converter para string para comparação consistente
 		pkKey := fmt.Sprintf("%v", pkValue)
 
-		if related, found := grouped[pkKey]; found {
+		if related, found := batch.grouped[pkKey]; found {
 			if navProperty.IsCollection {
-				// 1:N - Retorna array
+				// 1:N - Retorna array (truncado no limite de fan-out, com o nextLink do restante)
+				if batch.fanOut && len(related) > batch.fanOutLimit {
+					orderedEntity.Set(navProperty.Name, related[:batch.fanOutLimit])
+					orderedEntity.Set(navProperty.Name+"@odata.nextLink",
+						s.expandNextLink(ctx, navProperty, batch.relatedMetadata, pkValue, batch.expandOption, batch.fanOutLimit))
+					continue
+				}
 				orderedEntity.Set(navProperty.Name, related)
 			} else {
 				// N:1 ou 1:1 - Retorna primeira entidade
@@ -189,6 +219,37 @@ func (s *BaseEntityService) expandWithBatching(
 	}
 
 	log.Printf("✅ EXPAND BATCH: Associated related entities to %d parent entities", len(entities))
+}
 
-	return entities, nil
+// groupRelatedEntities agrupa as entidades relacionadas pelo valor (texto) da foreign key
+func groupRelatedEntities(relatedEntities []any, navProperty *PropertyMetadata) map[interface{}][]any {
+	grouped := make(map[interface{}][]any)
+
+	for _, relatedEntity := range relatedEntities {
+		orderedEntity, ok := relatedEntity.(*OrderedEntity)
+		if !ok {
+			continue
+		}
+
+		if fkValue, exists := relatedForeignKey(orderedEntity, navProperty); exists {
+			// Converter para string para comparação consistente
+			fkKey := fmt.Sprintf("%v", fkValue)
+			grouped[fkKey] = append(grouped[fkKey], relatedEntity)
+		}
+	}
+	return grouped
+}
+
+// relatedForeignKey retorna o valor da foreign key de uma entidade relacionada
+func relatedForeignKey(orderedEntity *OrderedEntity, navProperty *PropertyMetadata) (interface{}, bool) {
+	fkValue, exists := orderedEntity.Get(navProperty.Relationship.ReferencedProperty)
+	if !exists {
+		// Tentar case-insensitive
+		for _, prop := range orderedEntity.Properties {
+			if strings.EqualFold(prop.Name, navProperty.Relationship.ReferencedProperty) {
+				return prop.Value, true
+			}
+		}
+	}
+	return fkValue, exists
 }
@@ -0,0 +1,158 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// LIMITE DE ENTIDADES POR COLEÇÃO EXPANDIDA (FAN-OUT)
+// =======================================================================================
+
+// expandFanOutLimit retorna o máximo de entidades por coleção expandida (0: sem limite)
+func (s *BaseEntityService) expandFanOutLimit() int {
+	if s.server == nil || s.server.config == nil {
+		return 0
+	}
+	return s.server.config.MaxExpandFanOut
+}
+
+// expandFanOutApplies verifica se o limite vale para a expansão: apenas coleções, sem $skip
+// e com $top ausente ou maior que o limite
+func expandFanOutApplies(limit int, navProperty *PropertyMetadata, expandOption ExpandOption) bool {
+	return limit > 0 && navProperty.IsCollection && expandOption.Skip == 0 &&
+		(expandOption.Top == 0 || expandOption.Top > limit)
+}
+
+// expandFanOutOrder retorna a ordenação das entidades de cada coleção: o $orderby do
+// expand ou, sem ele, as chaves da entidade relacionada (estável entre as páginas)
+func expandFanOutOrder(relatedMetadata EntityMetadata, expandOption ExpandOption) string {
+	if expandOption.OrderBy != "" {
+		return expandOption.OrderBy
+	}
+	var keys []string
+	for _, prop := range relatedMetadata.Properties {
+		if prop.IsKey {
+			keys = append(keys, prop.Name)
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// queryExpandFanOut busca as entidades relacionadas de cada pai sem carregar coleções
+// inteiras: ordenadas pela chave estrangeira, cada rodada lê até limit+1 linhas por pai
+// pendente. Os pais cujo grupo terminou antes da última linha lida (ou que já passaram do
+// limite) estão completos; os demais são consultados novamente na rodada seguinte
+func (s *BaseEntityService) queryExpandFanOut(ctx context.Context, relatedService EntityService, navProperty *PropertyMetadata, parentIDs []interface{}, order string, limit int) (map[interface{}][]any, error) {
+	foreignKey := navProperty.Relationship.ReferencedProperty
+	orderBy := foreignKey
+	if order != "" {
+		orderBy += "," + order
+	}
+
+	grouped := make(map[interface{}][]any)
+	pending := parentIDs
+	for len(pending) > 0 {
+		literals := make([]string, len(pending))
+		for i, id := range pending {
+			literals[i] = expandFilterLiteral(id)
+		}
+		filterQuery, err := s.parseFilterWithTimeout(ctx, fmt.Sprintf("%s in (%s)", foreignKey, strings.Join(literals, ",")))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse batch filter: %w", err)
+		}
+		capacity := len(pending) * (limit + 1)
+		top := GoDataTopQuery(capacity)
+
+		response, err := relatedService.Query(ctx, QueryOptions{Filter: filterQuery, OrderBy: orderBy, Top: &top})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query related entities in batch: %w", err)
+		}
+		rows, ok := response.Value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("unexpected response type from batch query")
+		}
+
+		round := groupRelatedEntities(rows, navProperty)
+		if len(rows) < capacity {
+			for key, related := range round {
+				grouped[key] = related
+			}
+			break
+		}
+
+		var last string
+		if entity, ok := rows[len(rows)-1].(*OrderedEntity); ok {
+			if value, exists := relatedForeignKey(entity, navProperty); exists {
+				last = fmt.Sprintf("%v", value)
+			}
+		}
+		var next []interface{}
+		for _, id := range pending {
+			key := fmt.Sprintf("%v", id)
+			related := round[key]
+			if len(related) > limit || (len(related) > 0 && key != last) {
+				grouped[key] = related
+				continue
+			}
+			next = append(next, id)
+		}
+		pending = next
+	}
+	return grouped, nil
+}
+
+// expandNextLink monta o @odata.nextLink de uma coleção expandida truncada: o entity set da
+// entidade relacionada filtrado pela chave estrangeira, a partir da primeira entidade omitida
+func (s *BaseEntityService) expandNextLink(ctx context.Context, navProperty *PropertyMetadata, relatedMetadata EntityMetadata, parentID interface{}, expandOption ExpandOption, limit int) string {
+	if s.server == nil {
+		return ""
+	}
+
+	var route entityRoute
+	if service := s.server.GetEntityService(navProperty.RelatedType); service != nil {
+		route = s.server.entityServiceRoute(service)
+	} else {
+		route = s.server.entityRoute(relatedMetadata.Name)
+	}
+	var base string
+	if c, ok := ctx.Value(FiberContextKey).(fiber.Ctx); ok {
+		base = s.server.requestBaseURL(c)
+	}
+
+	escape := func(value string) string {
+		return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+	}
+	params := []string{
+		"$filter=" + escape(fmt.Sprintf("%s eq %s", navProperty.Relationship.ReferencedProperty, expandFilterLiteral(parentID))),
+	}
+	if order := expandFanOutOrder(relatedMetadata, expandOption); order != "" {
+		params = append(params, "$orderby="+escape(order))
+	}
+	// Sem $top no expand, o link pagina a coleção no tamanho do limite
+	top := limit
+	if expandOption.Top > 0 {
+		top = expandOption.Top - limit
+	}
+	params = append(params, "$top="+strconv.Itoa(top), "$skip="+strconv.Itoa(limit))
+	return base + route.prefix + "/" + route.setName + "?" + strings.Join(params, "&")
+}
+
+// expandFilterLiteral formata o valor da chave estrangeira como literal do $filter
+func expandFilterLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case []byte:
+		return "'" + strings.ReplaceAll(string(v), "'", "''") + "'"
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,113 @@
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ExpandFanOut(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	server.setupEntityRoutes("OrderItems")
+	server.SetMaxExpandFanOut(2)
+
+	// Pedido 1 com 5 itens (1, 2, 4, 5, 6) e pedido 2 com 1 item (3)
+	_, err := server.provider.GetConnection().Exec("INSERT INTO order_items (id, order_id, product) VALUES (4, 1, 'Cabo'), (5, 1, 'Hub'), (6, 1, 'Webcam')")
+	require.NoError(t, err)
+
+	type page struct {
+		NextLink string `json:"@odata.nextLink"`
+		Value    []map[string]interface{}
+	}
+	get := func(t *testing.T, path string) page {
+		t.Helper()
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body page
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+	products := func(items interface{}) []string {
+		var names []string
+		for _, item := range items.([]interface{}) {
+			names = append(names, item.(map[string]interface{})["product"].(string))
+		}
+		return names
+	}
+
+	body := get(t, "/odata/Orders?$expand=Items&$orderby=id")
+	require.Len(t, body.Value, 2)
+
+	first := body.Value[0]
+	assert.Equal(t, []string{"Mouse", "Teclado"}, products(first["Items"]))
+	nextLink, _ := first["Items@odata.nextLink"].(string)
+	assert.Equal(t, "http://example.com/odata/OrderItems?$filter=order_id%20eq%201&$orderby=id&$top=2&$skip=2", nextLink)
+
+	second := body.Value[1]
+	assert.Equal(t, []string{"Monitor"}, products(second["Items"]))
+	assert.NotContains(t, second, "Items@odata.nextLink")
+
+	t.Run("nextLink retorna a próxima página", func(t *testing.T) {
+		rest := get(t, strings.TrimPrefix(nextLink, "http://example.com"))
+		var names []string
+		for _, item := range rest.Value {
+			names = append(names, item["product"].(string))
+		}
+		assert.Equal(t, []string{"Cabo", "Hub"}, names)
+	})
+
+	t.Run("$top do expand menor que o limite", func(t *testing.T) {
+		body := get(t, "/odata/Orders?$expand=Items($top=1)&$filter=id%20eq%201")
+		require.Len(t, body.Value, 1)
+		assert.Equal(t, []string{"Mouse"}, products(body.Value[0]["Items"]))
+		assert.NotContains(t, body.Value[0], "Items@odata.nextLink")
+	})
+
+	t.Run("$top do expand maior que o limite", func(t *testing.T) {
+		body := get(t, "/odata/Orders?$expand=Items($top=4;$orderby=product%20desc)&$filter=id%20eq%201")
+		require.Len(t, body.Value, 1)
+		assert.Equal(t, []string{"Webcam", "Teclado"}, products(body.Value[0]["Items"]))
+		assert.Equal(t, "http://example.com/odata/OrderItems?$filter=order_id%20eq%201&$orderby=product%20desc&$top=2&$skip=2",
+			body.Value[0]["Items@odata.nextLink"])
+	})
+
+	t.Run("Sem limite", func(t *testing.T) {
+		server.SetMaxExpandFanOut(0)
+		defer server.SetMaxExpandFanOut(2)
+
+		body := get(t, "/odata/Orders?$expand=Items&$filter=id%20eq%201")
+		require.Len(t, body.Value, 1)
+		assert.Len(t, body.Value[0]["Items"], 5)
+		assert.NotContains(t, body.Value[0], "Items@odata.nextLink")
+	})
+}
+
+func TestBaseEntityService_QueryExpandFanOut(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	_, err := server.provider.GetConnection().Exec("INSERT INTO order_items (id, order_id, product) VALUES (4, 1, 'Cabo'), (5, 1, 'Hub'), (6, 1, 'Webcam')")
+	require.NoError(t, err)
+
+	orders := server.entities["Orders"].(*BaseEntityService)
+	var items *PropertyMetadata
+	for i, prop := range orders.metadata.Properties {
+		if prop.Name == "Items" {
+			items = &orders.metadata.Properties[i]
+		}
+	}
+	require.NotNil(t, items)
+
+	// Limite 1: a primeira rodada (4 linhas) só traz itens do pedido 1; o pedido 2 é lido na
+	// rodada seguinte
+	grouped, err := orders.queryExpandFanOut(context.Background(), server.entities["OrderItems"], items, []interface{}{int64(1), int64(2)}, "id", 1)
+	require.NoError(t, err)
+	assert.Len(t, grouped["1"], 4)
+	assert.Len(t, grouped["2"], 1)
+}
@@ -0,0 +1,132 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// =======================================================================================
+// $EXPAND FEDERADO (ENTIDADE RELACIONADA EM OUTRO PROVIDER)
+// =======================================================================================
+
+// isFederated indica se o provider da entidade relacionada é outro banco (WithProvider), o
+// que impede JOIN/EXISTS e exige buscar as relacionadas pelas chaves das entidades principais
+func (s *BaseEntityService) isFederated(relatedProvider DatabaseProvider) bool {
+	return relatedProvider != nil && s.provider != nil && relatedProvider != s.provider
+}
+
+// federatedExpandLimits retorna as chaves por consulta ao outro provider e o máximo de
+// chaves distintas de uma navegação federada
+func (s *BaseEntityService) federatedExpandLimits() (batchSize, maxKeys int) {
+	batchSize, maxKeys = DefaultFederatedExpandBatchSize, DefaultMaxFederatedExpandKeys
+	if s.server == nil || s.server.config == nil {
+		return batchSize, maxKeys
+	}
+	if s.server.config.FederatedExpandBatchSize > 0 {
+		batchSize = s.server.config.FederatedExpandBatchSize
+	}
+	if s.server.config.MaxFederatedExpandKeys > 0 {
+		maxKeys = s.server.config.MaxFederatedExpandKeys
+	}
+	return batchSize, maxKeys
+}
+
+// localEntityMetadata resolve as navegações de $filter e $orderby, traduzidas em EXISTS e
+// JOIN no SQL: a entidade relacionada precisa estar no banco da entidade consultada
+func (s *BaseEntityService) localEntityMetadata(relatedType string) (EntityMetadata, error) {
+	metadata, err := s.getRelatedEntityMetadata(relatedType)
+	if err != nil {
+		return metadata, err
+	}
+	if s.isFederated(s.relatedProvider(metadata)) {
+		return EntityMetadata{}, NewODataError("CrossProviderNavigation",
+			fmt.Sprintf("entity %s belongs to another database provider: navigations across providers are only supported in $expand", metadata.Name)).
+			WithStatus(http.StatusBadRequest)
+	}
+	return metadata, nil
+}
+
+// federatedExpandLimitError é o erro de uma navegação federada com chaves acima do limite
+func federatedExpandLimitError(navigation string, keys, limit int) error {
+	return NewODataError("FederatedExpandLimitExceeded",
+		fmt.Sprintf("$expand=%s requires %d keys from another database provider, above the limit of %d: reduce $top or the expanded navigations", navigation, keys, limit)).
+		WithStatus(http.StatusBadRequest).
+		WithTarget("$expand")
+}
+
+// queryFederatedExpand busca as entidades relacionadas no outro provider em lotes de
+// chaves (uma consulta "fk in (...)" por lote) e as agrupa pela foreign key. $top e $skip
+// do expand são aplicados por entidade principal, em memória
+func (s *BaseEntityService) queryFederatedExpand(ctx context.Context, relatedService EntityService, navProperty *PropertyMetadata, parentIDs []interface{}, expandOption ExpandOption, fanOutLimit int, fanOut bool) (map[interface{}][]any, error) {
+	batchSize, maxKeys := s.federatedExpandLimits()
+	if len(parentIDs) > maxKeys {
+		return nil, federatedExpandLimitError(navProperty.Name, len(parentIDs), maxKeys)
+	}
+
+	log.Printf("🔍 EXPAND FEDERATED: %s from another provider (%d keys, %d per query)", navProperty.Name, len(parentIDs), batchSize)
+
+	grouped := make(map[interface{}][]any)
+	for start := 0; start < len(parentIDs); start += batchSize {
+		chunk := parentIDs[start:min(start+batchSize, len(parentIDs))]
+
+		var round map[interface{}][]any
+		var err error
+		if fanOut {
+			round, err = s.queryExpandFanOut(ctx, relatedService, navProperty, chunk,
+				expandFanOutOrder(relatedService.GetMetadata(), expandOption), fanOutLimit)
+		} else {
+			round, err = s.queryFederatedChunk(ctx, relatedService, navProperty, chunk, expandOption)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for key, related := range round {
+			grouped[key] = related
+		}
+	}
+
+	if !fanOut && (expandOption.Skip > 0 || expandOption.Top > 0) {
+		for key, related := range grouped {
+			grouped[key] = pageExpandGroup(related, expandOption.Skip, expandOption.Top)
+		}
+	}
+	return grouped, nil
+}
+
+// queryFederatedChunk consulta as entidades relacionadas de um lote de chaves
+func (s *BaseEntityService) queryFederatedChunk(ctx context.Context, relatedService EntityService, navProperty *PropertyMetadata, keys []interface{}, expandOption ExpandOption) (map[interface{}][]any, error) {
+	literals := make([]string, len(keys))
+	for i, key := range keys {
+		literals[i] = expandFilterLiteral(key)
+	}
+	filterQuery, err := s.parseFilterWithTimeout(ctx, fmt.Sprintf("%s in (%s)",
+		navProperty.Relationship.ReferencedProperty, strings.Join(literals, ",")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse batch filter: %w", err)
+	}
+
+	response, err := relatedService.Query(ctx, QueryOptions{Filter: filterQuery, OrderBy: expandOption.OrderBy})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query related entities in batch: %w", err)
+	}
+	rows, ok := response.Value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type from batch query")
+	}
+	return groupRelatedEntities(rows, navProperty), nil
+}
+
+// pageExpandGroup aplica $skip e $top às entidades relacionadas de uma entidade principal
+func pageExpandGroup(related []any, skip, top int) []any {
+	if skip >= len(related) {
+		return []any{}
+	}
+	related = related[skip:]
+	if top > 0 && top < len(related) {
+		related = related[:top]
+	}
+	return related
+}
@@ -0,0 +1,96 @@
+package odata
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFederatedExpandTestServer move Customers e OrderItems para um segundo banco, mantendo
+// Orders no provider padrão
+func newFederatedExpandTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := newPatchDeltaTestServer(t)
+	_, err := server.provider.GetConnection().Exec("DROP TABLE order_items")
+	require.NoError(t, err)
+
+	crm, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { crm.Close() })
+	crm.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		"CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE order_items (id INTEGER PRIMARY KEY AUTOINCREMENT, order_id INTEGER, product TEXT)",
+		"INSERT INTO customers (id, name) VALUES (1, 'Ana (crm)')",
+		"INSERT INTO order_items (id, order_id, product) VALUES (1, 1, 'Mouse'), (2, 1, 'Teclado'), (3, 2, 'Monitor')",
+	} {
+		_, err := crm.Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	provider := NewMySQLProvider(crm)
+	server.AddProvider("crm", provider)
+	for _, name := range []string{"Customers", "OrderItems"} {
+		server.entities[name] = NewBaseEntityService(provider, server.entities[name].GetMetadata(), server)
+	}
+
+	server.router = fiber.New()
+	server.setupEntityRoutes("Orders")
+	return server
+}
+
+func TestServer_FederatedExpand(t *testing.T) {
+	server := newFederatedExpandTestServer(t)
+
+	get := func(t *testing.T, target string) (int, map[string]any) {
+		t.Helper()
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body
+	}
+
+	t.Run("navigations from another provider", func(t *testing.T) {
+		server.SetFederatedExpandLimits(1, 100)
+
+		status, body := get(t, "/odata/Orders?$expand=Items($orderby=id%20desc;$top=1),Customer&$orderby=id")
+		require.Equal(t, http.StatusOK, status, body)
+		orders := body["value"].([]any)
+		require.Len(t, orders, 2)
+
+		first := orders[0].(map[string]any)
+		assert.Equal(t, "Ana (crm)", first["Customer"].(map[string]any)["name"])
+		require.Len(t, first["Items"], 1, "$top é aplicado por entidade principal")
+		assert.Equal(t, "Teclado", first["Items"].([]any)[0].(map[string]any)["product"])
+		assert.Equal(t, "Monitor", orders[1].(map[string]any)["Items"].([]any)[0].(map[string]any)["product"])
+	})
+
+	t.Run("keys above the limit", func(t *testing.T) {
+		server.SetFederatedExpandLimits(10, 1)
+
+		status, body := get(t, "/odata/Orders?$expand=Items")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "FederatedExpandLimitExceeded", body["error"].(map[string]any)["code"])
+	})
+
+	t.Run("filter on navigation from another provider", func(t *testing.T) {
+		server.SetFederatedExpandLimits(0, 0)
+
+		status, body := get(t, "/odata/Orders?$filter=Customer/name%20eq%20'Ana'")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, body["error"].(map[string]any)["message"], "another database provider")
+
+		status, body = get(t, "/odata/Orders?$orderby=Customer/name")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "CrossProviderNavigation", body["error"].(map[string]any)["code"])
+	})
+}
@@ -2,9 +2,12 @@ package odata
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // =======================================================================================
@@ -12,15 +15,24 @@ import (
 // =======================================================================================
 
 // processExpandedNavigationWithOrder processa navegações expandidas seguindo a ordem OData v4
-// Com otimização para evitar problema N+1 usando batching
-func (s *BaseEntityService) processExpandedNavigationWithOrder(results []any, expandOptions []ExpandOption) ([]any, error) {
+// Com otimização para evitar problema N+1 usando batching. As navegações consultam tabelas
+// independentes, então as queries em batch rodam em paralelo (até MaxExpandParallelism) e a
+// associação às entidades é feita depois, na ordem do $expand
+func (s *BaseEntityService) processExpandedNavigationWithOrder(ctx context.Context, results []any, expandOptions []ExpandOption) ([]any, error) {
 	if len(results) == 0 {
 		return results, nil
 	}
 
-	// Para cada opção de expand, processa em batch para todas as entidades
+	type expandBranch struct {
+		option      ExpandOption
+		navProperty *PropertyMetadata
+		batch       *expandBatch
+		err         error
+	}
+
+	// Encontrar a propriedade de navegação de cada opção de expand
+	var branches []*expandBranch
 	for _, expandOption := range expandOptions {
-		// Encontrar propriedade de navegação
 		var navProperty *PropertyMetadata
 		for _, prop := range s.metadata.Properties {
 			if strings.EqualFold(prop.Name, expandOption.Property) && prop.IsNavigation {
@@ -40,39 +52,65 @@ func (s *BaseEntityService) processExpandedNavigationWithOrder(results []any, ex
 			log.Printf("Warning: Navigation property %s not found. Available: %v", expandOption.Property, availableProps)
 			continue
 		}
+		branches = append(branches, &expandBranch{option: expandOption, navProperty: navProperty})
+	}
 
-		// Decidir estratégia baseada no tipo de relacionamento e configuração
-		var err error
-
-		if s.server != nil && s.server.config.DisableJoinForExpand {
-			// Usuário forçou batching para tudo
-			log.Printf("🔍 EXPAND: Forced batching for %s (DisableJoinForExpand=true)", navProperty.Name)
-			results, err = s.expandWithBatching(results, navProperty, expandOption)
-		} else {
+	// Buscar as navegações em paralelo; as entidades principais só são lidas nesta etapa
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(s.expandParallelism(ctx))
+	for _, branch := range branches {
+		group.Go(func() error {
+			if s.server != nil && s.server.config.DisableJoinForExpand {
+				// Usuário forçou batching para tudo
+				log.Printf("🔍 EXPAND: Forced batching for %s (DisableJoinForExpand=true)", branch.navProperty.Name)
+			}
 			// Usa batching (resolve N+1 problem)
 			// TODO: Implementar JOIN otimizado para N:1 no futuro
-			results, err = s.expandWithBatching(results, navProperty, expandOption)
-		}
-
-		if err != nil {
-			// Log detalhado do erro
-			errorMsg := fmt.Sprintf("%v", err)
+			branch.batch, branch.err = s.fetchExpandBatch(groupCtx, results, branch.navProperty, branch.option)
 
-			log.Printf("⚠️ Warning: Failed to expand %s: %v", expandOption.Property, err)
-
-			// Se erro crítico de estrutura, falha
-			if strings.Contains(errorMsg, "navigation property") && strings.Contains(errorMsg, "not found") {
-				return nil, fmt.Errorf("critical error expanding navigation property %s: %w", expandOption.Property, err)
+			// Se erro crítico de estrutura ou limite da requisição, falha (e cancela as demais navegações)
+			if branch.err != nil {
+				var odataErr *ODataError
+				if errors.As(branch.err, &odataErr) && odataErr.Status != 0 {
+					return branch.err
+				}
+				errorMsg := fmt.Sprintf("%v", branch.err)
+				if strings.Contains(errorMsg, "navigation property") && strings.Contains(errorMsg, "not found") {
+					return fmt.Errorf("critical error expanding navigation property %s: %w", branch.option.Property, branch.err)
+				}
 			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
 
+	// Associar os resultados na ordem do $expand
+	for _, branch := range branches {
+		if branch.err != nil {
 			// Para outros erros, continua (entidades já têm navigation links)
+			log.Printf("⚠️ Warning: Failed to expand %s: %v", branch.option.Property, branch.err)
 			continue
 		}
+		s.applyExpandBatch(ctx, results, branch.batch)
 	}
 
 	return results, nil
 }
 
+// expandParallelism retorna quantas navegações de um $expand são buscadas ao mesmo tempo.
+// Com a sessão do banco da requisição, as queries compartilham a conexão e rodam em série
+func (s *BaseEntityService) expandParallelism(ctx context.Context) int {
+	if hasDBSession(ctx, s.provider) {
+		return 1
+	}
+	if s.server == nil || s.server.config == nil || s.server.config.MaxExpandParallelism <= 0 {
+		return DefaultMaxExpandParallelism
+	}
+	return s.server.config.MaxExpandParallelism
+}
+
 // findRelatedEntitiesWithOrder encontra entidades relacionadas seguindo a ordem OData v4
 func (s *BaseEntityService) findRelatedEntitiesWithOrder(ctx context.Context, navProperty *PropertyMetadata, entity *OrderedEntity, expandOption ExpandOption) ([]any, error) {
 	if navProperty.Relationship == nil {
@@ -180,7 +218,7 @@ func (s *BaseEntityService) findRelatedEntitiesWithOrder(ctx context.Context, na
 	}
 
 	// Cria serviço para a entidade relacionada
-	relatedService := NewBaseEntityService(s.provider, relatedMetadata, s.server)
+	relatedService := NewBaseEntityService(s.relatedProvider(relatedMetadata), relatedMetadata, s.server)
 
 	// Executa a consulta seguindo a ordem OData v4
 	response, err := relatedService.Query(ctx, queryOptions)
@@ -241,7 +279,7 @@ func (s *BaseEntityService) getRelatedEntityMetadata(relatedType string) (Entity
 	}
 
 	// Busca no registry de entidades do servidor
-	for entityName, service := range s.server.entities {
+	for entityName, service := range s.server.GetEntities() {
 		metadata := service.GetMetadata()
 		// Verifica se o nome da entidade ou o tipo corresponde
 		if entityName == relatedType || metadata.Name == relatedType {
@@ -251,7 +289,7 @@ func (s *BaseEntityService) getRelatedEntityMetadata(relatedType string) (Entity
 
 	// Debug: mostra as entidades disponíveis
 	var availableEntities []string
-	for entityName, service := range s.server.entities {
+	for entityName, service := range s.server.GetEntities() {
 		metadata := service.GetMetadata()
 		availableEntities = append(availableEntities, fmt.Sprintf("%s (%s)", entityName, metadata.Name))
 	}
@@ -259,6 +297,19 @@ func (s *BaseEntityService) getRelatedEntityMetadata(relatedType string) (Entity
 	return EntityMetadata{}, fmt.Errorf("related entity metadata not found for type: %s. Available entities: %v", relatedType, availableEntities)
 }
 
+// relatedProvider retorna o provider da entidade relacionada, que pode estar registrada em
+// outro banco (WithProvider); sem registro, usa o provider da própria entidade
+func (s *BaseEntityService) relatedProvider(relatedMetadata EntityMetadata) DatabaseProvider {
+	if s.server != nil {
+		for _, service := range s.server.GetEntities() {
+			if base, ok := service.(*BaseEntityService); ok && base.metadata.Name == relatedMetadata.Name && base.provider != nil {
+				return base.provider
+			}
+		}
+	}
+	return s.provider
+}
+
 // convertExpandItemsToExpandOptions converte ExpandItems para ExpandOptions
 func (s *BaseEntityService) convertExpandItemsToExpandOptions(items []*ExpandItem) []ExpandOption {
 	var expandOptions []ExpandOption
@@ -0,0 +1,70 @@
+package odata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ExpandParallelism(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+
+	// Mede quantas queries das navegações expandidas estão em execução ao mesmo tempo
+	var inFlight, maxInFlight atomic.Int32
+	server.OnQueryBuiltGlobal(func(args EventArgs) error {
+		if args.GetEntityName() == "Orders" {
+			return nil
+		}
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if current <= peak || maxInFlight.CompareAndSwap(peak, current) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+
+	get := func(t *testing.T) []map[string]json.RawMessage {
+		t.Helper()
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Orders?$expand=Items,Customer&$orderby=id", nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Value []map[string]json.RawMessage
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body.Value
+	}
+
+	t.Run("Navegações buscadas em paralelo", func(t *testing.T) {
+		maxInFlight.Store(0)
+		orders := get(t)
+		require.Len(t, orders, 2)
+		assert.EqualValues(t, 2, maxInFlight.Load())
+
+		assert.JSONEq(t, `[{"id":1,"order_id":1,"product":"Mouse"},{"id":2,"order_id":1,"product":"Teclado"}]`, string(orders[0]["Items"]))
+		assert.JSONEq(t, `{"id":1,"name":"Ana"}`, string(orders[0]["Customer"]))
+		assert.JSONEq(t, `[{"id":3,"order_id":2,"product":"Monitor"}]`, string(orders[1]["Items"]))
+	})
+
+	t.Run("Paralelismo 1 busca em sequência", func(t *testing.T) {
+		server.SetMaxExpandParallelism(1)
+		defer server.SetMaxExpandParallelism(DefaultMaxExpandParallelism)
+
+		maxInFlight.Store(0)
+		orders := get(t)
+		require.Len(t, orders, 2)
+		assert.EqualValues(t, 1, maxInFlight.Load())
+		assert.JSONEq(t, `{"id":1,"name":"Ana"}`, string(orders[1]["Customer"]))
+	})
+}
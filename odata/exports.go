@@ -0,0 +1,748 @@
+package odata
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// EXPORTAÇÕES ASSÍNCRONAS ($export)
+// =======================================================================================
+
+// Padrões das exportações
+const (
+	DefaultExportPageSize    = 5000             // Registros lidos por consulta
+	DefaultExportConcurrency = 2                // Exportações executadas em paralelo
+	DefaultExportRetention   = 24 * time.Hour   // Tempo de vida do job e do arquivo após o término
+	DefaultExportURLTTL      = 15 * time.Minute // Validade da URL de download
+)
+
+// exportSegment é o segmento das exportações nas rotas (ex: POST /odata/Orders/$export)
+const exportSegment = "/$export"
+
+// ExportStatus é a situação de um job de exportação
+type ExportStatus string
+
+const (
+	ExportPending   ExportStatus = "pending"   // Aguardando vaga (ExportOptions.Concurrency)
+	ExportRunning   ExportStatus = "running"   // Lendo os registros e gerando o arquivo
+	ExportCompleted ExportStatus = "completed" // Arquivo disponível para download
+	ExportFailed    ExportStatus = "failed"    // Erro na consulta, na geração ou no armazenamento
+	ExportCanceled  ExportStatus = "canceled"  // Interrompido (DELETE do job ou shutdown do servidor)
+)
+
+// ExportConfig configura as exportações de uma entidade
+type ExportConfig struct {
+	Formats  []ReportFormat // Formatos aceitos (padrão: CSV e XLSX)
+	PageSize int            // Registros lidos por consulta (padrão: DefaultExportPageSize)
+	MaxRows  int            // Registros por exportação; acima disso o job falha (0: sem limite)
+}
+
+// ExportOptions configura o subsistema de exportações do servidor
+type ExportOptions struct {
+	Storage     Storage       // Destino dos arquivos (padrão: Storage do servidor ou diretório godata-exports no temp do sistema)
+	Concurrency int           // Exportações em paralelo (padrão: DefaultExportConcurrency)
+	Retention   time.Duration // Tempo de vida do job e do arquivo após o término (padrão: DefaultExportRetention)
+	URLTTL      time.Duration // Validade da URL de download (padrão: DefaultExportURLTTL)
+	SigningKey  []byte        // Chave HMAC das URLs servidas pelo servidor (padrão: aleatória; use a mesma em todas as instâncias)
+	TempDir     string        // Diretório do arquivo em geração (padrão: temp do sistema)
+}
+
+// ExportJob é a situação de uma exportação, devolvida na criação e na consulta do job
+type ExportJob struct {
+	ID                string       `json:"id"`
+	EntitySet         string       `json:"entitySet"`
+	Format            ReportFormat `json:"format"`
+	Status            ExportStatus `json:"status"`
+	Rows              int64        `json:"rows"`
+	Size              int64        `json:"size,omitempty"`
+	Error             string       `json:"error,omitempty"`
+	CreatedAt         time.Time    `json:"createdAt"`
+	CompletedAt       *time.Time   `json:"completedAt,omitempty"`
+	ExpiresAt         *time.Time   `json:"expiresAt,omitempty"`
+	DownloadURL       string       `json:"downloadUrl,omitempty"`
+	DownloadExpiresAt *time.Time   `json:"downloadExpiresAt,omitempty"`
+}
+
+// exportJob é o job com os dados internos de acesso e armazenamento
+type exportJob struct {
+	ExportJob
+	entityName string
+	owner      string // Usuário que criou o job ("" sem autenticação)
+	tenant     string
+	key        string // Chave do arquivo no Storage
+	cancel     context.CancelFunc
+}
+
+// exportTask é a consulta executada pelo job
+type exportTask struct {
+	service EntityService
+	config  ExportConfig
+	options QueryOptions
+	columns []ReportColumn
+	user    *UserIdentity
+}
+
+// exportManager executa os jobs de exportação do servidor
+type exportManager struct {
+	server  *Server
+	options ExportOptions
+
+	mu      sync.Mutex
+	jobs    map[string]*exportJob
+	slots   chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+}
+
+// WithExport expõe POST /Entidade/$export, que cria um job assíncrono de exportação com as
+// query options da URL ($filter, $select, $orderby, $top...) no formato informado em
+// ?format=. O arquivo é gerado em páginas e gravado no Storage das exportações; a consulta
+// do job devolve a URL de download assinada ao término
+func WithExport(config ExportConfig) EntityOption {
+	return func(entityConfig *EntityConfig) {
+		entityConfig.Export = &config
+	}
+}
+
+// SetExportOptions configura o armazenamento, a concorrência e a retenção das exportações.
+// Deve ser chamado antes do início do servidor
+func (s *Server) SetExportOptions(options ExportOptions) *Server {
+	manager := s.exportManager()
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	manager.options = options.withDefaults()
+	manager.slots = make(chan struct{}, manager.options.Concurrency)
+	return s
+}
+
+// withDefaults completa as opções com os valores padrão
+func (o ExportOptions) withDefaults() ExportOptions {
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultExportConcurrency
+	}
+	if o.Retention <= 0 {
+		o.Retention = DefaultExportRetention
+	}
+	if o.URLTTL <= 0 {
+		o.URLTTL = DefaultExportURLTTL
+	}
+	if len(o.SigningKey) == 0 {
+		o.SigningKey = make([]byte, 32)
+		rand.Read(o.SigningKey)
+	}
+	return o
+}
+
+// storage retorna o armazenamento das exportações: o das opções, o do servidor ou, sem
+// nenhum configurado, o diretório godata-exports no temp do sistema
+func (m *exportManager) storage() Storage {
+	if m.options.Storage != nil {
+		return m.options.Storage
+	}
+	if storage := m.server.config.Storage; storage != nil {
+		return storage
+	}
+	return NewFileSystemStorage(filepath.Join(os.TempDir(), "godata-exports"))
+}
+
+// exportManager retorna o gerenciador de exportações, criando-o com as opções padrão
+func (s *Server) exportManager() *exportManager {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.exportJobs == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		options := ExportOptions{}.withDefaults()
+		s.exportJobs = &exportManager{
+			server:  s,
+			options: options,
+			jobs:    make(map[string]*exportJob),
+			slots:   make(chan struct{}, options.Concurrency),
+			ctx:     ctx,
+			cancel:  cancel,
+		}
+	}
+	return s.exportJobs
+}
+
+// registerExport valida e guarda a configuração das exportações da entidade
+func (s *Server) registerExport(entityName string, config ExportConfig) error {
+	if config.PageSize < 0 || config.MaxRows < 0 {
+		return fmt.Errorf("PageSize e MaxRows do $export não podem ser negativos")
+	}
+	if config.PageSize == 0 {
+		config.PageSize = DefaultExportPageSize
+	}
+	if len(config.Formats) == 0 {
+		config.Formats = []ReportFormat{ReportCSV, ReportXLSX}
+	}
+	for _, format := range config.Formats {
+		if _, err := newReportWriter(format); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.exports == nil {
+		s.exports = make(map[string]ExportConfig)
+	}
+	s.exports[entityName] = config
+	return nil
+}
+
+// getExport retorna a configuração das exportações da entidade, se houver
+func (s *Server) getExport(entityName string) (ExportConfig, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	config, ok := s.exports[entityName]
+	return config, ok
+}
+
+// setupExportRoutes registra as rotas do $export. O download ($value) não passa pelos
+// middlewares da entidade: a URL assinada é a credencial, para uso direto no navegador
+func (s *Server) setupExportRoutes(router fiber.Router, entityName, prefix, setName string, middlewares []any) {
+	config, ok := s.getExport(entityName)
+	if !ok {
+		return
+	}
+
+	register := func(method, path string, handler fiber.Handler) {
+		chain := append(append([]any{}, middlewares...), handler)
+		router.Add([]string{method}, path, chain[0], chain[1:]...)
+	}
+	path := prefix + "/" + setName + exportSegment
+	register(fiber.MethodPost, path, s.handleExportCreate(entityName, setName, path, config))
+	register(fiber.MethodGet, path+"/:id", s.handleExportStatus(entityName, path))
+	register(fiber.MethodDelete, path+"/:id", s.handleExportDelete(entityName))
+	router.Get(path+"/:id/$value", s.handleExportDownload(entityName))
+}
+
+// handleExportCreate valida a consulta e cria o job, respondendo 202 com o Location do job
+func (s *Server) handleExportCreate(entityName, setName, path string, config ExportConfig) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		service := s.GetEntityService(entityName)
+		if service == nil {
+			s.writeError(c, fiber.StatusNotFound, "EntityNotFound", fmt.Sprintf("Entity '%s' not found", entityName))
+			return nil
+		}
+		metadata := service.GetMetadata()
+
+		// A exportação é uma leitura: autorizada como GET
+		var err error
+		if maintenanceErr := s.checkMaintenance(c, fiber.MethodGet); maintenanceErr != nil {
+			err = maintenanceErr
+		} else {
+			err = s.authorizeEntity(context.WithValue(c.Context(), FiberContextKey, c), &AuthorizationRequest{
+				EntityName: entityName,
+				Operation:  fiber.MethodGet,
+				User:       GetCurrentUser(c),
+			})
+		}
+		if err != nil {
+			s.writeEntityError(c, fiber.StatusForbidden, "Forbidden", err)
+			return nil
+		}
+
+		format, err := ParseReportFormat(c.Query("format", string(ReportCSV)))
+		if err == nil && !containsReportFormat(config.Formats, format) {
+			err = fmt.Errorf("format '%s' is not enabled for $export of entity '%s'", format, entityName)
+		}
+		if err != nil {
+			s.writeError(c, fiber.StatusBadRequest, "InvalidFormat", err.Error())
+			return nil
+		}
+
+		// format não é uma query option OData: é removido antes do parse
+		options, err := s.parseQueryString(withoutQueryParameter(string(c.Request().URI().QueryString()), "format"))
+		if err == nil {
+			err = s.applySelectPolicy(&options, metadata)
+		}
+		if err == nil {
+			err = s.applyDefaultFilters(&options, metadata, "")
+		}
+		if err != nil {
+			s.writeError(c, fiber.StatusBadRequest, "InvalidQuery", err.Error())
+			return nil
+		}
+		// A exportação é plana: sem $expand e sem $count
+		options.Expand, options.Count = nil, nil
+
+		task := exportTask{
+			service: service,
+			config:  config,
+			options: options,
+			columns: exportColumns(metadata, options.Select),
+			user:    GetCurrentUser(c),
+		}
+		job := s.exportManager().start(c, entityName, setName, format, task)
+
+		c.Set(fiber.HeaderLocation, s.requestBaseURL(c)+path+"/"+job.ID)
+		return c.Status(fiber.StatusAccepted).JSON(job)
+	}
+}
+
+// handleExportStatus devolve a situação do job e, se concluído, a URL de download assinada
+func (s *Server) handleExportStatus(entityName, path string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		manager := s.exportManager()
+		job, ok := manager.find(c, entityName, c.Params("id"))
+		if !ok {
+			s.writeError(c, fiber.StatusNotFound, "ExportNotFound", "Export job not found")
+			return nil
+		}
+
+		if job.Status == ExportCompleted {
+			ctx, cancel := s.requestContext(c, entityName)
+			defer cancel()
+			link, expiresAt, err := manager.downloadURL(ctx, s.requestBaseURL(c)+path, job)
+			if err != nil {
+				s.writeError(c, fiber.StatusInternalServerError, "ExportError", err.Error())
+				return nil
+			}
+			job.DownloadURL, job.DownloadExpiresAt = link, &expiresAt
+		}
+		return c.JSON(job.ExportJob)
+	}
+}
+
+// handleExportDelete interrompe o job em andamento ou remove o arquivo gerado
+func (s *Server) handleExportDelete(entityName string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		manager := s.exportManager()
+		if _, ok := manager.find(c, entityName, c.Params("id")); !ok {
+			s.writeError(c, fiber.StatusNotFound, "ExportNotFound", "Export job not found")
+			return nil
+		}
+
+		ctx, cancel := s.requestContext(c, entityName)
+		defer cancel()
+		if err := manager.remove(ctx, c.Params("id")); err != nil {
+			s.writeError(c, fiber.StatusInternalServerError, "ExportError", err.Error())
+			return nil
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// handleExportDownload envia o arquivo do job, validando a assinatura e a validade da URL
+func (s *Server) handleExportDownload(entityName string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		manager := s.exportManager()
+		id := c.Params("id")
+		expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+		if err != nil || time.Now().Unix() > expires || !manager.validSignature(id, expires, c.Query("signature")) {
+			s.writeError(c, fiber.StatusForbidden, "Forbidden", "Invalid or expired download link")
+			return nil
+		}
+
+		job, ok := manager.get(id)
+		if !ok || job.entityName != entityName || job.Status != ExportCompleted {
+			s.writeError(c, fiber.StatusNotFound, "ExportNotFound", "Export job not found")
+			return nil
+		}
+		reader, err := manager.storage().Open(c.Context(), job.key)
+		if err != nil {
+			s.writeError(c, fiber.StatusNotFound, "ExportNotFound", "Export file not found")
+			return nil
+		}
+
+		c.Set(fiber.HeaderContentType, job.Format.ContentType())
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", job.EntitySet+"."+string(job.Format)))
+		return c.SendStream(reader, int(job.Size))
+	}
+}
+
+// start registra o job e inicia a exportação em segundo plano, com o usuário e o tenant da
+// requisição (a consulta roda desvinculada dela)
+func (m *exportManager) start(c fiber.Ctx, entityName, setName string, format ReportFormat, task exportTask) ExportJob {
+	m.purge(c.Context())
+
+	id := make([]byte, 16)
+	rand.Read(id)
+	job := &exportJob{
+		ExportJob: ExportJob{
+			ID:        hex.EncodeToString(id),
+			EntitySet: setName,
+			Format:    format,
+			Status:    ExportPending,
+			CreatedAt: time.Now().UTC(),
+		},
+		entityName: entityName,
+		tenant:     GetCurrentTenant(c),
+	}
+	if task.user != nil {
+		job.owner = task.user.Username
+	}
+	job.key = job.tenant + "/" + job.EntitySet + "/" + job.ID + "." + string(format)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ctx := context.WithValue(m.ctx, TenantContextKey, job.tenant)
+	ctx = withBatchIdentity(ctx, task.user, job.tenant)
+	ctx, job.cancel = context.WithCancel(ctx)
+	m.jobs[job.ID] = job
+
+	m.running.Add(1)
+	go func() {
+		defer m.running.Done()
+		m.run(ctx, job, task)
+	}()
+	return job.ExportJob
+}
+
+// run aguarda uma vaga, gera o arquivo e o grava no armazenamento
+func (m *exportManager) run(ctx context.Context, job *exportJob, task exportTask) {
+	defer job.cancel()
+
+	select {
+	case m.slots <- struct{}{}:
+		defer func() { <-m.slots }()
+	case <-ctx.Done():
+		m.finish(job, 0, ctx.Err())
+		return
+	}
+	m.update(job, func(job *exportJob) { job.Status = ExportRunning })
+
+	size, err := m.export(ctx, job, task)
+	m.finish(job, size, err)
+}
+
+// export gera o arquivo em um temporário e o envia ao armazenamento, retornando o tamanho
+func (m *exportManager) export(ctx context.Context, job *exportJob, task exportTask) (int64, error) {
+	file, err := os.CreateTemp(m.options.TempDir, "odata-export-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	buffered := bufio.NewWriter(file)
+	if err := m.writeRows(ctx, buffered, job, task); err != nil {
+		return 0, err
+	}
+	if err := buffered.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write export file: %w", err)
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if err := m.storage().Save(ctx, job.key, file, size, job.Format.ContentType()); err != nil {
+		return 0, fmt.Errorf("failed to store export file: %w", err)
+	}
+	return size, nil
+}
+
+// writeRows percorre a consulta em páginas (keyset quando a ordenação permite, ou $skip) e
+// escreve os registros no formato do job
+func (m *exportManager) writeRows(ctx context.Context, w io.Writer, job *exportJob, task exportTask) error {
+	s := m.server
+	metadata := task.service.GetMetadata()
+	writer, err := newReportWriter(job.Format)
+	if err != nil {
+		return err
+	}
+
+	options := task.options
+	order, keyset := s.keysetOrder(metadata, &options)
+	if keyset {
+		options.OrderBy = keysetOrderBy(order)
+	} else if options.OrderBy == "" && len(metadata.Keys) > 0 {
+		// Sem ordenação estável, as páginas por $skip poderiam repetir ou perder registros
+		options.OrderBy = strings.Join(metadata.Keys, ",")
+	}
+	remaining := -1
+	if options.Top != nil {
+		remaining = int(*options.Top)
+	}
+	skip := 0
+	if options.Skip != nil {
+		skip = int(*options.Skip)
+	}
+
+	if err := writer.begin(w, job.EntitySet, task.columns); err != nil {
+		return err
+	}
+	values := make([]any, len(task.columns))
+	var written int64
+	token := ""
+	for remaining != 0 {
+		page := options
+		limit := task.config.PageSize
+		if remaining > 0 && remaining < limit {
+			limit = remaining
+		}
+		top := GoDataTopQuery(limit)
+		page.Top = &top
+		page.Skip = nil
+		if token != "" {
+			if err := s.applySkipToken(&page, order, token); err != nil {
+				return err
+			}
+		} else if skip > 0 {
+			pageSkip := GoDataSkipQuery(skip)
+			page.Skip = &pageSkip
+		}
+
+		response, err := s.handleEntityQueryWithEvents(ctx, task.service, page, job.entityName, true)
+		if err != nil {
+			return err
+		}
+		rows, _ := response.Value.([]interface{})
+		s.applyMaskingPolicies(task.user, task.service, rows)
+
+		for _, row := range rows {
+			if task.config.MaxRows > 0 && written >= int64(task.config.MaxRows) {
+				return fmt.Errorf("export exceeds the limit of %d rows", task.config.MaxRows)
+			}
+			get := entityValueGetter(row)
+			for i, column := range task.columns {
+				values[i] = nil
+				if get != nil {
+					values[i], _ = get(column.Field)
+				}
+			}
+			if err := writer.writeRow(values); err != nil {
+				return err
+			}
+			written++
+		}
+		m.update(job, func(job *exportJob) { job.Rows = written })
+
+		if len(rows) < limit {
+			break
+		}
+		if remaining > 0 {
+			remaining -= len(rows)
+		}
+		if keyset {
+			var ok bool
+			if token, ok = encodeSkipToken(order, rows[len(rows)-1]); !ok {
+				// Valor de ordenação ausente: continua por $skip na mesma ordenação
+				keyset, token = false, ""
+				skip = int(written)
+				if options.Skip != nil {
+					skip += int(*options.Skip)
+				}
+			}
+		} else {
+			skip += len(rows)
+		}
+	}
+	return writer.end()
+}
+
+// finish registra o resultado do job
+func (m *exportManager) finish(job *exportJob, size int64, err error) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(m.options.Retention)
+	var removed bool
+	m.update(job, func(job *exportJob) {
+		_, active := m.jobs[job.ID]
+		removed = !active
+		job.CompletedAt, job.ExpiresAt = &now, &expiresAt
+		switch {
+		case err == nil:
+			job.Status, job.Size = ExportCompleted, size
+		case errors.Is(err, context.Canceled):
+			job.Status, job.Error = ExportCanceled, "export canceled"
+		default:
+			job.Status, job.Error = ExportFailed, err.Error()
+		}
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		m.server.logger.Printf("❌ Erro na exportação %s de %s: %v", job.ID, job.EntitySet, err)
+	}
+	// Job removido (DELETE) durante a gravação do arquivo
+	if removed && err == nil {
+		if err := m.storage().Delete(context.Background(), job.key); err != nil {
+			m.server.logger.Printf("❌ Erro ao remover exportação %s: %v", job.ID, err)
+		}
+	}
+}
+
+// update altera o job com o mutex do gerenciador
+func (m *exportManager) update(job *exportJob, fn func(job *exportJob)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn(job)
+}
+
+// get retorna uma cópia do job
+func (m *exportManager) get(id string) (exportJob, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return exportJob{}, false
+	}
+	return *job, true
+}
+
+// find retorna o job da entidade visível ao usuário da requisição: o criador (ou admins) do
+// mesmo tenant
+func (m *exportManager) find(c fiber.Ctx, entityName, id string) (exportJob, bool) {
+	m.purge(c.Context())
+
+	job, ok := m.get(id)
+	if !ok || job.entityName != entityName || job.tenant != GetCurrentTenant(c) {
+		return exportJob{}, false
+	}
+	if job.owner != "" {
+		user := GetCurrentUser(c)
+		if user == nil || (user.Username != job.owner && !user.Admin) {
+			return exportJob{}, false
+		}
+	}
+	return job, true
+}
+
+// remove interrompe o job, se em andamento, e exclui o arquivo gerado
+func (m *exportManager) remove(ctx context.Context, id string) error {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	delete(m.jobs, id)
+	completed := ok && job.Status == ExportCompleted
+	m.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	// Jobs em andamento descartam o arquivo ao terminar (finish)
+	job.cancel()
+	if completed {
+		return m.storage().Delete(ctx, job.key)
+	}
+	return nil
+}
+
+// purge remove os jobs (e os arquivos) com a retenção vencida
+func (m *exportManager) purge(ctx context.Context) {
+	now := time.Now()
+	var expired []*exportJob
+	m.mu.Lock()
+	for id, job := range m.jobs {
+		if job.ExpiresAt != nil && now.After(*job.ExpiresAt) {
+			expired = append(expired, job)
+			delete(m.jobs, id)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, job := range expired {
+		if job.Status != ExportCompleted {
+			continue
+		}
+		if err := m.storage().Delete(ctx, job.key); err != nil {
+			m.server.logger.Printf("❌ Erro ao remover exportação %s: %v", job.ID, err)
+		}
+	}
+}
+
+// downloadURL gera a URL de download do job: a do armazenamento (StorageURLSigner) ou a do
+// próprio servidor, assinada com HMAC
+func (m *exportManager) downloadURL(ctx context.Context, base string, job exportJob) (string, time.Time, error) {
+	expiresAt := time.Now().Add(m.options.URLTTL).UTC().Truncate(time.Second)
+
+	if signer, ok := m.storage().(StorageURLSigner); ok {
+		link, err := signer.SignedURL(ctx, job.key, m.options.URLTTL)
+		return link, expiresAt, err
+	}
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", m.signature(job.ID, expiresAt.Unix()))
+	return base + "/" + job.ID + "/$value?" + query.Encode(), expiresAt, nil
+}
+
+// signature assina o job e a validade da URL de download
+func (m *exportManager) signature(id string, expires int64) string {
+	return hex.EncodeToString(hmacSHA256(m.options.SigningKey, id+"\n"+strconv.FormatInt(expires, 10)))
+}
+
+// validSignature verifica a assinatura da URL de download
+func (m *exportManager) validSignature(id string, expires int64, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	actual, _ := hex.DecodeString(m.signature(id, expires))
+	return hmac.Equal(expected, actual)
+}
+
+// stop cancela as exportações em andamento e aguarda o término até o fim de ctx
+func (m *exportManager) stop(ctx context.Context) error {
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("exports still running at shutdown: %w", ctx.Err())
+	}
+}
+
+// exportColumns retorna as colunas do arquivo: as propriedades da entidade no $select,
+// exceto navegações e propriedades ocultas
+func exportColumns(metadata EntityMetadata, selectQuery *GoDataSelectQuery) []ReportColumn {
+	var columns []ReportColumn
+	for _, prop := range metadata.Properties {
+		if prop.IsNavigation || isHiddenProperty(metadata, prop.Name) || !keysetSelected(selectQuery, prop.Name) {
+			continue
+		}
+		columns = append(columns, ReportColumn{Field: prop.Name, Title: prop.Name})
+	}
+	return columns
+}
+
+// withoutQueryParameter remove o parâmetro da query string, mantendo os demais sem
+// recodificá-los
+func withoutQueryParameter(query, name string) string {
+	parts := strings.Split(query, "&")
+	kept := parts[:0]
+	for _, part := range parts {
+		key, _, _ := strings.Cut(part, "=")
+		if part != "" && key != name {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "&")
+}
+
+// containsReportFormat verifica se o formato está na lista
+func containsReportFormat(formats []ReportFormat, format ReportFormat) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
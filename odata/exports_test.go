@@ -0,0 +1,100 @@
+package odata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Export(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	_, err := server.provider.GetConnection().Exec("INSERT INTO orders (id, customer_id, status) VALUES (3, 2, 'closed'), (4, 2, 'open')")
+	require.NoError(t, err)
+	require.NoError(t, server.registerExport("Orders", ExportConfig{PageSize: 1, MaxRows: 3}))
+	server.SetExportOptions(ExportOptions{Storage: NewFileSystemStorage(t.TempDir())})
+
+	app := fiber.New()
+	server.router = app
+	server.setupExportRoutes(server.router, "Orders", "/odata", "Orders", nil)
+
+	request := func(method, target string) (int, http.Header, string) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(method, target, nil))
+		require.NoError(t, err)
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, resp.Header, string(data)
+	}
+	wait := func(location string) ExportJob {
+		t.Helper()
+		var job ExportJob
+		require.Eventually(t, func() bool {
+			status, _, body := request(http.MethodGet, location)
+			require.Equal(t, http.StatusOK, status, body)
+			require.NoError(t, json.Unmarshal([]byte(body), &job))
+			return job.Status != ExportPending && job.Status != ExportRunning
+		}, 5*time.Second, 10*time.Millisecond)
+		return job
+	}
+
+	t.Run("Exporta em páginas e baixa pela URL assinada", func(t *testing.T) {
+		status, header, body := request(http.MethodPost, "/odata/Orders/$export?format=csv&$filter=id%20ne%202&$select=id,status&$orderby=id%20desc")
+		require.Equal(t, http.StatusAccepted, status, body)
+		location, err := url.Parse(header.Get(fiber.HeaderLocation))
+		require.NoError(t, err)
+
+		job := wait(location.Path)
+		require.Equal(t, ExportCompleted, job.Status, job.Error)
+		assert.Equal(t, int64(3), job.Rows)
+		require.NotEmpty(t, job.DownloadURL)
+
+		download, err := url.Parse(job.DownloadURL)
+		require.NoError(t, err)
+		status, header, body = request(http.MethodGet, download.RequestURI())
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Equal(t, "text/csv; charset=utf-8", header.Get(fiber.HeaderContentType))
+		assert.Equal(t, "id,status\n4,open\n3,closed\n1,open\n", strings.TrimPrefix(body, "\xef\xbb\xbf"))
+
+		query := download.Query()
+		query.Set("signature", strings.Repeat("0", 64))
+		status, _, _ = request(http.MethodGet, download.Path+"?"+query.Encode())
+		assert.Equal(t, http.StatusForbidden, status)
+
+		status, _, _ = request(http.MethodDelete, location.Path)
+		assert.Equal(t, http.StatusNoContent, status)
+		status, _, _ = request(http.MethodGet, download.RequestURI())
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Limite de registros", func(t *testing.T) {
+		status, header, body := request(http.MethodPost, "/odata/Orders/$export")
+		require.Equal(t, http.StatusAccepted, status, body)
+
+		job := wait(header.Get(fiber.HeaderLocation))
+		assert.Equal(t, ExportFailed, job.Status)
+		assert.Equal(t, "export exceeds the limit of 3 rows", job.Error)
+		assert.Empty(t, job.DownloadURL)
+
+		status, header, body = request(http.MethodPost, "/odata/Orders/$export?$top=2")
+		require.Equal(t, http.StatusAccepted, status, body)
+		job = wait(header.Get(fiber.HeaderLocation))
+		assert.Equal(t, ExportCompleted, job.Status)
+		assert.Equal(t, int64(2), job.Rows)
+	})
+
+	t.Run("Requisição inválida", func(t *testing.T) {
+		status, _, _ := request(http.MethodPost, "/odata/Orders/$export?format=pdf")
+		assert.Equal(t, http.StatusBadRequest, status)
+		status, _, _ = request(http.MethodGet, "/odata/Orders/$export/unknown")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
@@ -72,6 +72,11 @@ const (
 	AssocRight
 )
 
+// inListSize é o número de valores da lista de um operador in, registrado pelo
+// InfixToPostfix no SemanticReference do token para que a árvore saiba quantos
+// operandos consumir
+type inListSize int
+
 // ParseNode representa um nó na árvore de parse
 type ParseNode struct {
 	Token    *Token
@@ -90,6 +95,9 @@ func (p *ExpressionParser) ParseFilterExpression(ctx context.Context, filter str
 	if err != nil {
 		return nil, fmt.Errorf("tokenization error: %w", err)
 	}
+	if err := resolveItReferences(tokens); err != nil {
+		return nil, fmt.Errorf("tokenization error: %w", err)
+	}
 
 	if len(tokens) == 0 {
 		return nil, nil
@@ -115,7 +123,14 @@ func (p *ExpressionParser) InfixToPostfix(ctx context.Context, tokens []*Token)
 	var output []*Token
 	var operatorStack []*Token
 
+	// Listas de operadores in abertas: parêntese da lista -> número de valores
+	inLists := make(map[*Token]int)
+	var previous *Token
+
 	for _, token := range tokens {
+		prev := previous
+		previous = token
+
 		// Verifica cancelamento do contexto
 		select {
 		case <-ctx.Done():
@@ -126,7 +141,7 @@ func (p *ExpressionParser) InfixToPostfix(ctx context.Context, tokens []*Token)
 		switch token.Type {
 		case int(FilterTokenProperty), int(FilterTokenString), int(FilterTokenNumber), int(FilterTokenBoolean), int(FilterTokenNull),
 			int(FilterTokenDateTime), int(FilterTokenDate), int(FilterTokenTime), int(FilterTokenGuid), int(FilterTokenDuration),
-			int(FilterTokenGeographyPoint), int(FilterTokenGeometryPoint):
+			int(FilterTokenGeographyPoint), int(FilterTokenGeometryPoint), int(FilterTokenRootReference):
 			// Operandos vão direto para output
 			output = append(output, token)
 
@@ -135,6 +150,13 @@ func (p *ExpressionParser) InfixToPostfix(ctx context.Context, tokens []*Token)
 			operatorStack = append(operatorStack, token)
 
 		case int(FilterTokenComma):
+			// Vírgula no nível da lista de um in: mais um valor
+			if len(operatorStack) > 0 {
+				if count, ok := inLists[operatorStack[len(operatorStack)-1]]; ok {
+					inLists[operatorStack[len(operatorStack)-1]] = count + 1
+				}
+			}
+
 			// Vírgula: pop até encontrar parêntese aberto
 			// EXCEÇÃO: não fazer pop do operador "in" - ele deve permanecer na stack
 			for len(operatorStack) > 0 && operatorStack[len(operatorStack)-1].Type != int(FilterTokenOpenParen) {
@@ -148,7 +170,10 @@ func (p *ExpressionParser) InfixToPostfix(ctx context.Context, tokens []*Token)
 			}
 
 		case int(FilterTokenOpenParen):
-			// Parêntese aberto vai para stack
+			// Parêntese aberto vai para stack; após um in ele abre a lista de valores
+			if prev != nil && prev.Type == int(FilterTokenComparison) && strings.EqualFold(prev.Value, "in") {
+				inLists[token] = 1
+			}
 			operatorStack = append(operatorStack, token)
 
 		case int(FilterTokenCloseParen):
@@ -163,8 +188,29 @@ func (p *ExpressionParser) InfixToPostfix(ctx context.Context, tokens []*Token)
 			}
 
 			// Remove parêntese aberto
+			openParen := operatorStack[len(operatorStack)-1]
 			operatorStack = operatorStack[:len(operatorStack)-1]
 
+			// Fim da lista de um in: a operação está completa e vai para o output com o
+			// número de valores
+			if count, ok := inLists[openParen]; ok {
+				delete(inLists, openParen)
+				if prev != nil && prev.Type == int(FilterTokenOpenParen) {
+					return nil, fmt.Errorf("in operator requires at least one value")
+				}
+				if len(operatorStack) > 0 {
+					in := operatorStack[len(operatorStack)-1]
+					operatorStack = operatorStack[:len(operatorStack)-1]
+					output = append(output, &Token{
+						Type:              in.Type,
+						Value:             in.Value,
+						SemanticType:      in.SemanticType,
+						SemanticReference: inListSize(count),
+					})
+				}
+				continue
+			}
+
 			// Se há função no topo, move para output
 			if len(operatorStack) > 0 && operatorStack[len(operatorStack)-1].Type == int(FilterTokenFunction) {
 				output = append(output, operatorStack[len(operatorStack)-1])
@@ -250,7 +296,7 @@ func (p *ExpressionParser) PostfixToTree(ctx context.Context, postfix []*Token)
 		switch token.Type {
 		case int(FilterTokenProperty), int(FilterTokenString), int(FilterTokenNumber), int(FilterTokenBoolean), int(FilterTokenNull),
 			int(FilterTokenDateTime), int(FilterTokenDate), int(FilterTokenTime), int(FilterTokenGuid), int(FilterTokenDuration),
-			int(FilterTokenGeographyPoint), int(FilterTokenGeometryPoint):
+			int(FilterTokenGeographyPoint), int(FilterTokenGeometryPoint), int(FilterTokenRootReference):
 			// Operandos: nós folha
 			stack = append(stack, node)
 
@@ -283,6 +329,26 @@ func (p *ExpressionParser) PostfixToTree(ctx context.Context, postfix []*Token)
 					return nil, fmt.Errorf("insufficient operands for operator %s", token.Value)
 				}
 
+				// Com o tamanho da lista conhecido, consome apenas a propriedade e seus
+				// valores do topo da pilha (o in pode ser operando de and/or)
+				if size, ok := token.SemanticReference.(inListSize); ok {
+					count := int(size)
+					if len(stack) < count+1 {
+						return nil, fmt.Errorf("insufficient operands for operator %s", token.Value)
+					}
+					property := stack[len(stack)-count-1]
+					values := append([]*ParseNode(nil), stack[len(stack)-count:]...)
+					stack = stack[:len(stack)-count-1]
+
+					property.Parent = node
+					for _, value := range values {
+						value.Parent = node
+					}
+					node.Children = append([]*ParseNode{property}, values...)
+					stack = append(stack, node)
+					break
+				}
+
 				// A propriedade está no índice 0, os valores nos índices 1 até n
 				property := stack[0]
 				values := stack[1:] // Todos os valores
@@ -359,7 +425,7 @@ func (p *ExpressionParser) getFunctionArgCount(funcName string) int {
 		return 3 // pode ser 2 ou 3, mas assumimos 3 por padrão
 	case "concat":
 		return 2 // pode ser variável, mas assumimos 2 por padrão
-	case "length", "tolower", "toupper", "trim", "year", "month", "day", "hour", "minute", "second", "round", "floor", "ceiling":
+	case "length", "tolower", "toupper", "trim", "year", "month", "day", "hour", "minute", "second", "date", "time", "totaloffsetminutes", "round", "floor", "ceiling":
 		return 1
 	case "now":
 		return 0
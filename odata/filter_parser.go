@@ -28,11 +28,37 @@ func ParseFilterString(ctx context.Context, filter string) (*GoDataFilterQuery,
 		return nil, nil
 	}
 
+	tree, err := ParseExpressionString(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if tree == nil {
+		return nil, nil
+	}
+
+	// Valida se é uma expressão booleana
+	if tree.Token == nil || !GlobalFilterParser.isBooleanExpression(tree.Token) {
+		return nil, fmt.Errorf("filter expression must be a boolean expression")
+	}
+
+	return &GoDataFilterQuery{
+		Tree:     tree,
+		RawValue: filter,
+	}, nil
+}
+
+// ParseExpressionString converte uma expressão OData de qualquer tipo (aritmética, função,
+// propriedade ou booleana) em árvore de parse, usando a gramática do $filter.
+// Usada por opções que aceitam expressões não booleanas, como $orderby
+func ParseExpressionString(ctx context.Context, expression string) (*ParseNode, error) {
 	// Tokenize usando o tokenizer global
-	tokens, err := GlobalFilterTokenizer.Tokenize(ctx, filter)
+	tokens, err := GlobalFilterTokenizer.Tokenize(ctx, expression)
 	if err != nil {
 		return nil, fmt.Errorf("failed to tokenize filter: %w", err)
 	}
+	if err := resolveItReferences(tokens); err != nil {
+		return nil, fmt.Errorf("failed to tokenize filter: %w", err)
+	}
 
 	if len(tokens) == 0 {
 		return nil, nil
@@ -50,15 +76,7 @@ func ParseFilterString(ctx context.Context, filter string) (*GoDataFilterQuery,
 		return nil, fmt.Errorf("failed to build parse tree: %w", err)
 	}
 
-	// Valida se é uma expressão booleana
-	if tree == nil || tree.Token == nil || !GlobalFilterParser.isBooleanExpression(tree.Token) {
-		return nil, fmt.Errorf("filter expression must be a boolean expression")
-	}
-
-	return &GoDataFilterQuery{
-		Tree:     tree,
-		RawValue: filter,
-	}, nil
+	return tree, nil
 }
 
 // SemanticizeFilterQuery adiciona informações semânticas à query de filtro
@@ -275,7 +293,7 @@ func FormatFilterExpression(filter *GoDataFilterQuery) string {
 		switch node.Token.Type {
 		case int(FilterTokenProperty), int(FilterTokenString), int(FilterTokenNumber),
 			int(FilterTokenBoolean), int(FilterTokenDateTime), int(FilterTokenDate),
-			int(FilterTokenTime), int(FilterTokenGuid):
+			int(FilterTokenTime), int(FilterTokenGuid), int(FilterTokenDuration), int(FilterTokenRootReference):
 			return node.Token.Value
 
 		case int(FilterTokenLogical), int(FilterTokenComparison), int(FilterTokenArithmetic):
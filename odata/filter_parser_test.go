@@ -450,3 +450,49 @@ func TestValidateFilterExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFilterString_InOperator(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   string
+		root     string
+		inValues []int // número de valores de cada in, na ordem da árvore
+	}{
+		{"in isolado", "Status in ('A','B','C')", "in", []int{3}},
+		{"in após and", "Amount gt 5 and Status in ('A','B')", "and", []int{2}},
+		{"dois in com or", "Status in ('A') or Id in (1,2)", "or", []int{1, 2}},
+		{"in com funções na lista", "Name in (concat('a','b'),'c')", "in", []int{2}},
+		{"in entre parênteses", "(Status in ('A','B')) and Id eq 1", "and", []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseFilterString(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("Unexpected error for filter '%s': %v", tt.filter, err)
+			}
+			if parsed.Tree.Token.Value != tt.root {
+				t.Errorf("Expected root %s, got %s", tt.root, parsed.Tree.Token.Value)
+			}
+
+			var sizes []int
+			walkParseTree(parsed.Tree, func(node *ParseNode) {
+				if node.Token.Type == int(FilterTokenComparison) && node.Token.Value == "in" {
+					sizes = append(sizes, len(node.Children)-1)
+				}
+			})
+			if len(sizes) != len(tt.inValues) {
+				t.Fatalf("Expected %d in operators, got %d", len(tt.inValues), len(sizes))
+			}
+			for i := range sizes {
+				if sizes[i] != tt.inValues[i] {
+					t.Errorf("Expected %d values in operator %d, got %d", tt.inValues[i], i, sizes[i])
+				}
+			}
+		})
+	}
+
+	if _, err := ParseFilterString(context.Background(), "Status in ()"); err == nil {
+		t.Errorf("Expected error for empty in list")
+	}
+}
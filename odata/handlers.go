@@ -1,7 +1,7 @@
 package odata
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -19,13 +19,13 @@ func (s *Server) handleHealth(c fiber.Ctx) error {
 		"status":    "healthy",
 		"timestamp": time.Now().UTC().Format(time.RFC3339),
 		"version":   "1.0.0",
-		"entities":  len(s.entities),
+		"entities":  len(s.GetEntities()),
 	}
 
 	// Testa conexão com banco se possível
 	if s.provider != nil {
 		if db := s.provider.GetConnection(); db != nil {
-			if err := db.Ping(); err != nil {
+			if err := db.PingContext(c.Context()); err != nil {
 				health["database"] = "error"
 				health["database_error"] = err.Error()
 			} else {
@@ -34,6 +34,20 @@ func (s *Server) handleHealth(c fiber.Ctx) error {
 		}
 	}
 
+	// Providers nomeados (AddProvider)
+	if providers := s.GetNamedProviders(); len(providers) > 0 {
+		databases := make(map[string]string, len(providers))
+		for name, provider := range providers {
+			databases[name] = "healthy"
+			if db := provider.GetConnection(); db == nil {
+				databases[name] = "unavailable"
+			} else if err := db.PingContext(c.Context()); err != nil {
+				databases[name] = "error: " + err.Error()
+			}
+		}
+		health["databases"] = databases
+	}
+
 	return c.JSON(health)
 }
 
@@ -45,7 +59,7 @@ func (s *Server) handleServerInfo(c fiber.Ctx) error {
 		"odata_version": "4.0",
 		"description":   "Servidor OData v4 completo em Go",
 		"address":       s.GetAddress(),
-		"entities":      len(s.entities),
+		"entities":      len(s.GetEntities()),
 		"entity_list":   s.getEntityList(),
 		"endpoints": map[string]string{
 			"service_document": s.config.RoutePrefix + "/",
@@ -76,17 +90,12 @@ func (s *Server) handleServerInfo(c fiber.Ctx) error {
 // getEntityList retorna lista de entidades registradas
 func (s *Server) getEntityList() []string {
 	var entities []string
-	for name := range s.entities {
+	for name := range s.GetEntities() {
 		entities = append(entities, name)
 	}
 	return entities
 }
 
-// handleOptions lida com requisições OPTIONS para CORS
-func (s *Server) handleOptions(c fiber.Ctx) error {
-	return c.SendStatus(fiber.StatusNoContent)
-}
-
 // =======================================================================================
 // ENTITY COLLECTION HANDLERS
 // =======================================================================================
@@ -94,15 +103,19 @@ func (s *Server) handleOptions(c fiber.Ctx) error {
 // handleEntityCollection lida com operações na coleção de entidades (GET, POST)
 func (s *Server) handleEntityCollection(c fiber.Ctx) error {
 	entityName := s.extractEntityName(c.Path())
-	service, exists := s.entities[entityName]
-	if !exists {
+	service := s.GetEntityService(entityName)
+	if service == nil {
 		s.writeError(c, fiber.StatusNotFound, "EntityNotFound", fmt.Sprintf("Entity '%s' not found", entityName))
 		return nil
 	}
 
 	switch c.Method() {
-	case "GET":
-		return s.handleGetCollection(c, service)
+	case "GET", "HEAD":
+		if err := s.handleGetCollection(c, service); err != nil {
+			return err
+		}
+		setResponseETag(c)
+		return nil
 	case "POST":
 		return s.handleCreateEntity(c, service)
 	default:
@@ -113,15 +126,34 @@ func (s *Server) handleEntityCollection(c fiber.Ctx) error {
 
 // handleGetCollection lida com GET na coleção de entidades
 func (s *Server) handleGetCollection(c fiber.Ctx, service EntityService) error {
-	// Cria contexto com referência ao Fiber Context para multi-tenant
-	// Usa background context para garantir que não seja cancelado quando a requisição HTTP terminar
-	ctx := context.WithValue(context.Background(), FiberContextKey, c)
+	return s.writeCollection(c, service, s.extractEntityName(c.Path()), "")
+}
 
-	// Extrai o nome da entidade
-	entityName := s.extractEntityName(c.Path())
+// writeCollection consulta a coleção com o filtro padrão da entidade e o filtro do preset
+// (quando chamado por /Entidade/Default.Preset()) combinados ao $filter do cliente
+func (s *Server) writeCollection(c fiber.Ctx, service EntityService, entityName, presetFilter string) error {
+	// Cria contexto com referência ao Fiber Context para multi-tenant, com o QueryTimeout
+	// da entidade e cancelamento se o cliente desconectar
+	ctx, cancel := s.requestContext(c, entityName)
+	defer cancel()
+
+	if err := s.authorizeRequest(c, entityName, nil); err != nil {
+		s.writeEntityError(c, fiber.StatusForbidden, "Forbidden", err)
+		return nil
+	}
 
 	// Parse centralizado das opções de consulta
 	options, err := s.parseQueryOptions(c)
+	if err == nil {
+		err = s.applySelectPolicy(&options, service.GetMetadata())
+	}
+	if err == nil {
+		err = s.applyDefaultFilters(&options, service.GetMetadata(), presetFilter)
+	}
+	var page *pagination
+	if err == nil {
+		page, err = s.preparePagination(c, entityName, service.GetMetadata(), &options)
+	}
 	if err != nil {
 		s.writeError(c, fiber.StatusBadRequest, "InvalidQuery", err.Error())
 		return nil
@@ -130,20 +162,37 @@ func (s *Server) handleGetCollection(c fiber.Ctx, service EntityService) error {
 	// Executa consulta centralizada com eventos
 	response, err := s.handleEntityQueryWithEvents(ctx, service, options, entityName, true)
 	if err != nil {
-		s.writeError(c, fiber.StatusInternalServerError, "QueryError", err.Error())
+		s.writeEntityError(c, fiber.StatusInternalServerError, "QueryError", s.queryContextError(ctx, entityName, err))
 		return nil
 	}
+	if page != nil && response != nil {
+		results, _ := response.Value.([]interface{})
+		if page.capped {
+			results = page.truncate(results)
+			response.Value = results
+		}
+		response.NextLink = page.nextLink(s, c, results)
+		if page.truncated {
+			s.logger.Printf("⚠️ %s: resultado truncado em %d registros (MaxRowsPerRequest)", entityName, page.limit)
+			AddResponseAnnotation(c, TruncatedAnnotation, page.truncatedAnnotation())
+		}
+	}
 
 	// Constrói resposta OData centralizada
 	odataResponse := s.buildODataResponse(response, true, service.GetMetadata())
 
-	return c.JSON(odataResponse)
+	return s.writeEntityJSON(c, service, odataResponse)
 }
 
-// handleCreateEntity lida com POST para criar uma entidade
+// handleCreateEntity lida com POST para criar uma entidade. Um array JSON no corpo cria
+// cada item de forma independente, com resposta 207 Multi-Status (handleBulkCreate)
 func (s *Server) handleCreateEntity(c fiber.Ctx, service EntityService) error {
-	var entity map[string]interface{}
-	if err := c.Bind().Body(&entity); err != nil {
+	if isJSONArrayBody(c.Body()) {
+		return s.handleBulkCreate(c, service)
+	}
+
+	entity, err := s.decodeEntityJSON(c.Body(), service.GetMetadata())
+	if err != nil {
 		s.writeError(c, fiber.StatusBadRequest, "InvalidRequest", "Invalid JSON")
 		return nil
 	}
@@ -151,6 +200,29 @@ func (s *Server) handleCreateEntity(c fiber.Ctx, service EntityService) error {
 	// Extrai o nome da entidade
 	entityName := s.extractEntityName(c.Path())
 
+	if err := s.authorizeRequest(c, entityName, nil); err != nil {
+		s.writeEntityError(c, fiber.StatusForbidden, "Forbidden", err)
+		return nil
+	}
+
+	createdEntity, status, code, err := s.createEntity(c, service, entityName, entity)
+	if err != nil {
+		s.writeEntityError(c, status, code, err)
+		return nil
+	}
+
+	// Location e OData-EntityId apontam para a URL canônica da entidade criada
+	if entityURL := s.buildEntityURL(c, service, createdEntity); entityURL != "" {
+		c.Set(fiber.HeaderLocation, entityURL)
+		c.Set("OData-EntityId", entityURL)
+	}
+	c.Status(fiber.StatusCreated)
+	return s.writeEntityJSON(c, service, createdEntity)
+}
+
+// createEntity cria uma entidade disparando os eventos OnEntityInserting/OnEntityInserted.
+// Em caso de falha, retorna o status HTTP e o código de erro OData da resposta
+func (s *Server) createEntity(c fiber.Ctx, service EntityService, entityName string, entity map[string]any) (interface{}, int, string, error) {
 	// Cria o contexto do evento
 	eventCtx := createEventContext(c, entityName)
 
@@ -159,26 +231,28 @@ func (s *Server) handleCreateEntity(c fiber.Ctx, service EntityService) error {
 	if err := s.eventManager.Emit(insertingArgs); err != nil {
 		// Se o evento foi cancelado, retorna erro
 		if insertingArgs.IsCanceled() {
-			s.writeError(c, fiber.StatusBadRequest, "ValidationError", insertingArgs.GetCancelReason())
-			return nil
+			return nil, fiber.StatusBadRequest, "ValidationError", errors.New(insertingArgs.GetCancelReason())
 		}
 		s.logger.Printf("❌ Erro no evento OnEntityInserting: %v", err)
-		s.writeError(c, fiber.StatusInternalServerError, "EventError", err.Error())
-		return nil
+		return nil, fiber.StatusInternalServerError, "EventError", err
 	}
 
 	// Usa os dados modificados pelo evento (caso tenha sido alterado)
 	dataToInsert := insertingArgs.Data
 
 	// Executa a criação
-	createdEntity, err := service.Create(c.Context(), dataToInsert)
+	ctx, cancel := s.requestContext(c, entityName)
+	defer cancel()
+
+	createdEntity, err := service.Create(ctx, dataToInsert)
 	if err != nil {
+		err = s.queryContextError(ctx, entityName, err)
+
 		// Dispara evento de erro
-		errorArgs := NewEntityErrorArgs(eventCtx, err, "Create", fiber.StatusInternalServerError)
+		errorArgs := NewEntityErrorArgs(eventCtx, err, "Create", entityErrorStatus(err, fiber.StatusInternalServerError))
 		s.eventManager.Emit(errorArgs) // Não retorna erro, apenas loga
 
-		s.writeError(c, fiber.StatusInternalServerError, "CreateError", err.Error())
-		return nil
+		return nil, fiber.StatusInternalServerError, "CreateError", err
 	}
 
 	// Dispara evento OnEntityInserted (após inserção bem-sucedida)
@@ -187,10 +261,7 @@ func (s *Server) handleCreateEntity(c fiber.Ctx, service EntityService) error {
 		s.logger.Printf("❌ Erro no evento OnEntityInserted: %v", err)
 		// Não retorna erro aqui, pois a inserção já foi bem-sucedida
 	}
-
-	c.Set("Location", s.buildEntityURL(c, service, createdEntity))
-	c.Status(fiber.StatusCreated)
-	return c.JSON(createdEntity)
+	return createdEntity, fiber.StatusCreated, "", nil
 }
 
 // =======================================================================================
@@ -205,8 +276,8 @@ func (s *Server) handleEntityById(c fiber.Ctx) error {
 	entityName := s.extractEntityName(path)
 	s.logger.Printf("🔍 handleEntityById - EntityName: %s", entityName)
 
-	service, exists := s.entities[entityName]
-	if !exists {
+	service := s.GetEntityService(entityName)
+	if service == nil {
 		s.logger.Printf("❌ handleEntityById - Entity '%s' not found", entityName)
 		s.writeError(c, fiber.StatusNotFound, "EntityNotFound", fmt.Sprintf("Entity '%s' not found", entityName))
 		return nil
@@ -228,9 +299,18 @@ func (s *Server) handleEntityById(c fiber.Ctx) error {
 
 	s.logger.Printf("🔍 handleEntityById - Keys extraídas: %+v", keys)
 
+	if err := s.authorizeRequest(c, entityName, keys); err != nil {
+		s.writeEntityError(c, fiber.StatusForbidden, "Forbidden", err)
+		return nil
+	}
+
 	switch c.Method() {
-	case "GET":
-		return s.handleGetEntity(c, service, keys)
+	case "GET", "HEAD":
+		if err := s.handleGetEntity(c, service, keys); err != nil {
+			return err
+		}
+		setResponseETag(c)
+		return nil
 	case "PUT":
 		return s.handleUpdateEntity(c, service, keys)
 	case "PATCH":
@@ -252,15 +332,24 @@ func (s *Server) handleGetEntity(c fiber.Ctx, service EntityService, keys map[st
 		s.logger.Printf("🔍 handleGetEntity - Key '%s': value=%v, type=%T", k, v, v)
 	}
 
-	// Cria contexto com referência ao Fiber Context para multi-tenant
-	// Usa background context para garantir que não seja cancelado quando a requisição HTTP terminar
-	ctx := context.WithValue(context.Background(), FiberContextKey, c)
-
 	// Extrai o nome da entidade
 	entityName := s.extractEntityName(c.Path())
 
+	// Cria contexto com referência ao Fiber Context para multi-tenant, com o QueryTimeout
+	// da entidade e cancelamento se o cliente desconectar
+	ctx, cancel := s.requestContext(c, entityName)
+	defer cancel()
+
+	// Versão vigente em um instante (WithHistory)
+	if asOf := c.Query(queryOptionAsOf); asOf != "" {
+		return s.handleGetEntityAsOf(c, ctx, service, keys, asOf)
+	}
+
 	// Parse das opções de consulta da URL (caso existam)
 	options, err := s.parseQueryOptions(c)
+	if err == nil {
+		err = s.applySelectPolicy(&options, service.GetMetadata())
+	}
 	if err != nil {
 		s.writeError(c, fiber.StatusBadRequest, "InvalidQuery", err.Error())
 		return nil
@@ -302,7 +391,7 @@ func (s *Server) handleGetEntity(c fiber.Ctx, service EntityService, keys map[st
 	// Executa consulta centralizada com eventos
 	response, err := s.handleEntityQueryWithEvents(ctx, service, options, entityName, false)
 	if err != nil {
-		s.writeError(c, fiber.StatusInternalServerError, "QueryError", err.Error())
+		s.writeEntityError(c, fiber.StatusInternalServerError, "QueryError", s.queryContextError(ctx, entityName, err))
 		return nil
 	}
 
@@ -333,13 +422,13 @@ func (s *Server) handleGetEntity(c fiber.Ctx, service EntityService, keys map[st
 	// Constrói resposta OData centralizada
 	odataResponse := s.buildODataResponse(response, false, service.GetMetadata())
 
-	return c.JSON(odataResponse)
+	return s.writeEntityJSON(c, service, odataResponse)
 }
 
 // handleUpdateEntity lida com PUT/PATCH para atualizar uma entidade
 func (s *Server) handleUpdateEntity(c fiber.Ctx, service EntityService, keys map[string]interface{}) error {
-	var entity map[string]interface{}
-	if err := c.Bind().Body(&entity); err != nil {
+	entity, err := s.decodeEntityJSON(c.Body(), service.GetMetadata())
+	if err != nil {
 		s.writeError(c, fiber.StatusBadRequest, "InvalidRequest", "Invalid JSON")
 		return nil
 	}
@@ -350,10 +439,13 @@ func (s *Server) handleUpdateEntity(c fiber.Ctx, service EntityService, keys map
 	// Cria o contexto do evento
 	eventCtx := createEventContext(c, entityName)
 
+	ctx, cancel := s.requestContext(c, entityName)
+	defer cancel()
+
 	// Busca a entidade original antes da atualização (para OnEntityModifying e OnEntityModified)
 	var originalEntity interface{}
 	if service != nil {
-		originalEntity, _ = service.Get(c.Context(), keys)
+		originalEntity, _ = service.Get(ctx, keys)
 	}
 
 	// Dispara evento OnEntityModifying (antes da atualização)
@@ -373,20 +465,21 @@ func (s *Server) handleUpdateEntity(c fiber.Ctx, service EntityService, keys map
 	dataToUpdate := modifyingArgs.Data
 
 	var updatedEntity interface{}
-	var err error
+	var patchReport *PatchReport
 	operation := "Update"
 
 	// PUT: comportamento atual INALTERADO - chama Update diretamente
 	if c.Method() == "PUT" {
-		updatedEntity, err = service.Update(c.Context(), keys, dataToUpdate)
+		updatedEntity, err = service.Update(ctx, keys, dataToUpdate)
 		if err != nil {
+			err = s.queryContextError(ctx, entityName, err)
 			if strings.Contains(err.Error(), "not found") {
 				s.writeError(c, fiber.StatusNotFound, "EntityNotFound", err.Error())
 			} else {
 				// Dispara evento de erro
-				errorArgs := NewEntityErrorArgs(eventCtx, err, operation, fiber.StatusInternalServerError)
+				errorArgs := NewEntityErrorArgs(eventCtx, err, operation, entityErrorStatus(err, fiber.StatusInternalServerError))
 				s.eventManager.Emit(errorArgs)
-				s.writeError(c, fiber.StatusInternalServerError, "UpdateError", err.Error())
+				s.writeEntityError(c, fiber.StatusInternalServerError, "UpdateError", err)
 			}
 			return nil
 		}
@@ -394,29 +487,38 @@ func (s *Server) handleUpdateEntity(c fiber.Ctx, service EntityService, keys map
 		operation = "Patch"
 		// PATCH: tenta usar método Patch se disponível, fallback para Update
 		if baseService, ok := service.(*BaseEntityService); ok {
-			updatedEntity, err = baseService.Patch(c.Context(), keys, dataToUpdate)
+			updatedEntity, patchReport, err = baseService.PatchWithReport(ctx, keys, dataToUpdate)
+			if !hasPreference(c.Get("Prefer"), PreferPatchReport) {
+				patchReport = nil
+			}
 			if err != nil {
-				if strings.Contains(err.Error(), "not found") {
+				err = s.queryContextError(ctx, entityName, err)
+				if patchReport != nil {
+					errorArgs := NewEntityErrorArgs(eventCtx, err, operation, entityErrorStatus(err, fiber.StatusInternalServerError))
+					s.eventManager.Emit(errorArgs)
+					s.writePatchReportError(c, err, patchReport)
+				} else if strings.Contains(err.Error(), "not found") {
 					s.writeError(c, fiber.StatusNotFound, "EntityNotFound", err.Error())
 				} else {
 					// Dispara evento de erro
-					errorArgs := NewEntityErrorArgs(eventCtx, err, operation, fiber.StatusInternalServerError)
+					errorArgs := NewEntityErrorArgs(eventCtx, err, operation, entityErrorStatus(err, fiber.StatusInternalServerError))
 					s.eventManager.Emit(errorArgs)
-					s.writeError(c, fiber.StatusInternalServerError, "UpdateError", err.Error())
+					s.writeEntityError(c, fiber.StatusInternalServerError, "UpdateError", err)
 				}
 				return nil
 			}
 		} else {
 			// Fallback para Update se Patch não estiver disponível
-			updatedEntity, err = service.Update(c.Context(), keys, dataToUpdate)
+			updatedEntity, err = service.Update(ctx, keys, dataToUpdate)
 			if err != nil {
+				err = s.queryContextError(ctx, entityName, err)
 				if strings.Contains(err.Error(), "not found") {
 					s.writeError(c, fiber.StatusNotFound, "EntityNotFound", err.Error())
 				} else {
 					// Dispara evento de erro
-					errorArgs := NewEntityErrorArgs(eventCtx, err, operation, fiber.StatusInternalServerError)
+					errorArgs := NewEntityErrorArgs(eventCtx, err, operation, entityErrorStatus(err, fiber.StatusInternalServerError))
 					s.eventManager.Emit(errorArgs)
-					s.writeError(c, fiber.StatusInternalServerError, "UpdateError", err.Error())
+					s.writeEntityError(c, fiber.StatusInternalServerError, "UpdateError", err)
 				}
 				return nil
 			}
@@ -434,7 +536,54 @@ func (s *Server) handleUpdateEntity(c fiber.Ctx, service EntityService, keys map
 		// Não retorna erro aqui, pois a atualização já foi bem-sucedida
 	}
 
-	return c.JSON(updatedEntity)
+	if patchReport != nil {
+		c.Set("Preference-Applied", PreferPatchReport)
+		return s.writeEntityJSON(c, service, attachPatchReport(updatedEntity, patchReport))
+	}
+
+	// PATCH com @removed na raiz exclui a entidade
+	if updatedEntity == nil {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	return s.writeEntityJSON(c, service, updatedEntity)
+}
+
+// writePatchReportError escreve o erro de um PATCH hierárquico incluindo o relatório de
+// operações; o status e o target refletem a operação que falhou
+func (s *Server) writePatchReportError(c fiber.Ctx, err error, report *PatchReport) {
+	statusCode := fiber.StatusInternalServerError
+	odataErr := &ODataError{Code: "UpdateError", Message: err.Error()}
+	violation, isViolation := constraintViolation(err)
+	if isViolation {
+		odataErr = &ODataError{Code: violation.Code(), Message: violation.Message()}
+	}
+
+	// Payload rejeitado antes de executar as operações (ex: @removed inválido)
+	var payloadErr *ODataError
+	if errors.As(err, &payloadErr) && payloadErr.Status != 0 {
+		statusCode, odataErr = payloadErr.Status, payloadErr
+	}
+
+	if failed := report.FailedOperation(); failed != nil {
+		statusCode = failed.Status
+		odataErr.Target = failed.EntityName
+		if failed.NavigationPath != "" {
+			odataErr.Target = failed.NavigationPath
+		}
+		if isViolation && violation.Property != "" {
+			odataErr.Target += "/" + violation.Property
+		}
+		if statusCode == fiber.StatusNotFound {
+			odataErr.Code = "EntityNotFound"
+		}
+	}
+
+	c.Set("Preference-Applied", PreferPatchReport)
+	c.Status(statusCode).JSON(fiber.Map{
+		"error":                   odataErr,
+		PatchOperationsAnnotation: report.Operations,
+	})
 }
 
 // handleDeleteEntity lida com DELETE para remover uma entidade
@@ -445,10 +594,13 @@ func (s *Server) handleDeleteEntity(c fiber.Ctx, service EntityService, keys map
 	// Cria o contexto do evento
 	eventCtx := createEventContext(c, entityName)
 
+	ctx, cancel := s.requestContext(c, entityName)
+	defer cancel()
+
 	// Busca a entidade antes da exclusão (para OnEntityDeleting e OnEntityDeleted)
 	var entityToDelete interface{}
 	if service != nil {
-		entityToDelete, _ = service.Get(c.Context(), keys)
+		entityToDelete, _ = service.Get(ctx, keys)
 	}
 
 	// Dispara evento OnEntityDeleting (antes da exclusão)
@@ -465,15 +617,16 @@ func (s *Server) handleDeleteEntity(c fiber.Ctx, service EntityService, keys map
 	}
 
 	// Executa a exclusão
-	err := service.Delete(c.Context(), keys)
+	err := service.Delete(ctx, keys)
 	if err != nil {
+		err = s.queryContextError(ctx, entityName, err)
 		if strings.Contains(err.Error(), "not found") {
 			s.writeError(c, fiber.StatusNotFound, "EntityNotFound", err.Error())
 		} else {
 			// Dispara evento de erro
-			errorArgs := NewEntityErrorArgs(eventCtx, err, "Delete", fiber.StatusInternalServerError)
+			errorArgs := NewEntityErrorArgs(eventCtx, err, "Delete", entityErrorStatus(err, fiber.StatusInternalServerError))
 			s.eventManager.Emit(errorArgs)
-			s.writeError(c, fiber.StatusInternalServerError, "DeleteError", err.Error())
+			s.writeEntityError(c, fiber.StatusInternalServerError, "DeleteError", err)
 		}
 		return nil
 	}
@@ -501,7 +654,7 @@ func (s *Server) handleMetadata(c fiber.Ctx) error {
 // handleServiceDocument lida com GET do documento de serviço OData
 func (s *Server) handleServiceDocument(c fiber.Ctx) error {
 	serviceDoc := map[string]interface{}{
-		"@odata.context": "$metadata",
+		"@odata.context": s.metadataURL(c, s.routePrefix()),
 		"value":          s.buildEntitySets(),
 	}
 
@@ -515,28 +668,48 @@ func (s *Server) handleServiceDocument(c fiber.Ctx) error {
 // handleEntityCount lida com GET do count de uma coleção de entidades
 func (s *Server) handleEntityCount(c fiber.Ctx) error {
 	entityName := s.extractEntityName(c.Path())
-	service, exists := s.entities[entityName]
-	if !exists {
+	service := s.GetEntityService(entityName)
+	if service == nil {
 		s.writeError(c, fiber.StatusNotFound, "EntityNotFound", fmt.Sprintf("Entity '%s' not found", entityName))
 		return nil
 	}
 
+	return s.writeCollectionCount(c, service, entityName, "")
+}
+
+// writeCollectionCount retorna a contagem da coleção, com o filtro padrão da entidade e o
+// filtro do preset combinados ao $filter do cliente
+func (s *Server) writeCollectionCount(c fiber.Ctx, service EntityService, entityName, presetFilter string) error {
+	if err := s.authorizeRequest(c, entityName, nil); err != nil {
+		s.writeEntityError(c, fiber.StatusForbidden, "Forbidden", err)
+		return nil
+	}
+
 	// Parse centralizado das opções de consulta
 	options, err := s.parseQueryOptions(c)
+	if err == nil {
+		err = s.checkHiddenProperties(&options, service.GetMetadata())
+	}
+	if err == nil {
+		err = s.applyDefaultFilters(&options, service.GetMetadata(), presetFilter)
+	}
 	if err != nil {
 		s.writeError(c, fiber.StatusBadRequest, "InvalidQuery", err.Error())
 		return nil
 	}
 
+	ctx, cancel := s.requestContext(c, entityName)
+	defer cancel()
+
 	// Obtém a contagem usando o método centralizado
-	count, err := s.getEntityCount(c.Context(), service, options)
+	count, err := s.getEntityCount(ctx, service, options, entityName)
 	if err != nil {
-		s.writeError(c, fiber.StatusInternalServerError, "CountError", err.Error())
+		s.writeEntityError(c, fiber.StatusInternalServerError, "CountError", s.queryContextError(ctx, entityName, err))
 		return nil
 	}
 
 	// Retorna apenas o valor numérico para count
-	c.Set("Content-Type", "text/plain")
+	c.Set("Content-Type", "text/plain; charset=utf-8")
 	c.Status(fiber.StatusOK)
 	return c.SendString(fmt.Sprintf("%d", count))
 }
@@ -611,7 +784,7 @@ func (s *Server) handleTenantHealth(c fiber.Ctx) error {
 
 	// Testa a conexão
 	if db := provider.GetConnection(); db != nil {
-		if err := db.Ping(); err != nil {
+		if err := db.PingContext(c.Context()); err != nil {
 			health["status"] = "unhealthy"
 			health["error"] = err.Error()
 			return c.Status(fiber.StatusServiceUnavailable).JSON(health)
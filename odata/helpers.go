@@ -15,17 +15,34 @@ import (
 // =======================================================================================
 
 // getEntityCount obtém a contagem de entidades com base nas opções de consulta
-func (s *Server) getEntityCount(ctx context.Context, service EntityService, options QueryOptions) (int64, error) {
+func (s *Server) getEntityCount(ctx context.Context, service EntityService, options QueryOptions, entityName string) (int64, error) {
 	// Cria novas opções apenas com filtro para contagem
 	countOptions := QueryOptions{
 		Filter: options.Filter,
 		Search: options.Search,
 	}
 
-	// Executa a consulta para contagem
-	response, err := service.Query(ctx, countOptions)
+	// Executa a consulta para contagem (após os interceptors de consulta)
+	var response *ODataResponse
+	err := s.runQueryInterceptors(ctx, entityName, QueryOperationCount, true, &countOptions, func(options QueryOptions) error {
+		// Serviços com SELECT COUNT(*) não precisam ler as linhas
+		if counter, ok := service.(countEntityService); ok {
+			count, err := counter.GetCount(ctx, options)
+			if err != nil {
+				return fmt.Errorf("failed to execute count query: %w", err)
+			}
+			response = &ODataResponse{Count: &count}
+			return nil
+		}
+		var err error
+		response, err = service.Query(ctx, options)
+		if err != nil {
+			return fmt.Errorf("failed to execute count query: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to execute count query: %w", err)
+		return 0, err
 	}
 
 	// Extrai contagem da resposta
@@ -45,14 +62,21 @@ func (s *Server) getEntityCount(ctx context.Context, service EntityService, opti
 	return 0, nil
 }
 
+// countEntityService é implementado pelos serviços que contam as linhas no banco
+type countEntityService interface {
+	GetCount(ctx context.Context, options QueryOptions) (int64, error)
+}
+
 // parseQueryOptions analisa as opções de consulta OData da URL
 func (s *Server) parseQueryOptions(c fiber.Ctx) (QueryOptions, error) {
+	return s.parseQueryString(string(c.Request().URI().QueryString()))
+}
+
+// parseQueryString converte uma query string OData em QueryOptions validadas
+func (s *Server) parseQueryString(queryString string) (QueryOptions, error) {
 	var queryValues url.Values
 	var err error
 
-	// Extrai query string
-	queryString := string(c.Request().URI().QueryString())
-
 	// Parse rápido da query string
 	queryValuesURL, parseErr := s.urlParser.ParseQueryFast(queryString)
 	if parseErr != nil {
@@ -71,6 +95,11 @@ func (s *Server) parseQueryOptions(c fiber.Ctx) (QueryOptions, error) {
 		return QueryOptions{}, fmt.Errorf("failed to parse query options: %w", err)
 	}
 
+	// Literais de data/hora sem offset são interpretados no fuso do servidor
+	if err := s.prepareDateTimeFilter(&options); err != nil {
+		return QueryOptions{}, fmt.Errorf("invalid $filter: %w", err)
+	}
+
 	// Valida as opções
 	if err := s.parser.ValidateQueryOptions(options); err != nil {
 		return QueryOptions{}, fmt.Errorf("invalid query options: %w", err)
@@ -101,10 +130,44 @@ func (s *Server) executeEntityQuery(ctx context.Context, service EntityService,
 	return response, nil
 }
 
+// applyKeyFilter combina o filtro tipado das chaves com o $filter da query (se houver)
+func (s *Server) applyKeyFilter(ctx context.Context, service EntityService, keys map[string]interface{}, options QueryOptions) (QueryOptions, error) {
+	// Constrói filtro para as chaves específicas usando o método centralizado do BaseEntityService
+	baseService, ok := service.(*BaseEntityService)
+	if !ok {
+		// Tenta com MultiTenantEntityService
+		mtService, ok := service.(*MultiTenantEntityService)
+		if !ok {
+			return options, fmt.Errorf("service type not supported")
+		}
+		baseService = mtService.BaseEntityService
+	}
+
+	// Constrói filtro tipado para as chaves
+	keyFilter, err := baseService.BuildTypedKeyFilter(ctx, keys)
+	if err != nil {
+		return options, fmt.Errorf("invalid key: %w", err)
+	}
+
+	// Combina filtro de chaves com filtro da query (se houver)
+	if options.Filter != nil {
+		// Se já há um filtro na query, combina com AND (implementação básica - idealmente deveria combinar as árvores)
+		keyFilter.RawValue = fmt.Sprintf("(%s) and (%s)", keyFilter.RawValue, options.Filter.RawValue)
+	}
+
+	options.Filter = keyFilter
+	return options, nil
+}
+
 // handleEntityQueryWithEvents executa consulta e dispara eventos apropriados
 func (s *Server) handleEntityQueryWithEvents(ctx context.Context, service EntityService, options QueryOptions, entityName string, isCollection bool) (*ODataResponse, error) {
-	// Executa a consulta
-	response, err := s.executeEntityQuery(ctx, service, options, entityName)
+	// Executa a consulta (após os interceptors de consulta, que podem reescrever as options)
+	var response *ODataResponse
+	err := s.runQueryInterceptors(ctx, entityName, QueryOperationSelect, isCollection, &options, func(options QueryOptions) error {
+		var err error
+		response, err = s.executeEntityQuery(ctx, service, options, entityName)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +230,11 @@ func (s *Server) handleEntityQueryWithEvents(ctx context.Context, service Entity
 
 // extractEntityName extrai o nome da entidade da URL
 func (s *Server) extractEntityName(path string) string {
+	// Entidades publicadas com prefixo ou nome de entity set próprios (WithRoutePrefix/WithEntitySetName)
+	if name, ok := s.entityNameFromRoute(path); ok {
+		return name
+	}
+
 	// Remove o prefixo da rota
 	prefix := s.config.RoutePrefix
 	if strings.HasPrefix(path, prefix+"/") {
@@ -321,55 +389,34 @@ func (s *Server) parseKeyValue(value, dataType string) (interface{}, error) {
 // RESPONSE BUILDERS
 // =======================================================================================
 
-// buildEntityURL constrói a URL para uma entidade específica
+// buildEntityURL constrói a URL canônica e absoluta de uma entidade (Location do POST),
+// vazia quando os valores das chaves não estão presentes
 func (s *Server) buildEntityURL(c fiber.Ctx, service EntityService, entity interface{}) string {
-	metadata := service.GetMetadata()
-
-	// Encontra as chaves primárias
-	var keyValues []string
-	entityMap, ok := entity.(map[string]interface{})
-	if !ok {
-		return ""
-	}
-
-	for _, prop := range metadata.Properties {
-		if prop.IsKey {
-			if value, exists := entityMap[prop.Name]; exists {
-				keyValues = append(keyValues, fmt.Sprintf("%v", value))
-			}
+	var get func(name string) (interface{}, bool)
+	switch e := entity.(type) {
+	case map[string]interface{}:
+		get = mapGetter(e)
+	case *OrderedEntity:
+		if e == nil {
+			return ""
 		}
-	}
-
-	if len(keyValues) == 0 {
+		get = e.Get
+	default:
 		return ""
 	}
 
-	scheme := "http"
-	if c.Protocol() == "https" {
-		scheme = "https"
-	}
-
-	baseURL := fmt.Sprintf("%s://%s%s/%s", scheme, c.Hostname(), s.config.RoutePrefix, metadata.Name)
-
-	if len(keyValues) == 1 {
-		return fmt.Sprintf("%s(%s)", baseURL, keyValues[0])
-	}
-
-	// Para chaves compostas, usar formato chave=valor
-	var keyPairs []string
-	i := 0
-	for _, prop := range metadata.Properties {
-		if prop.IsKey && i < len(keyValues) {
-			keyPairs = append(keyPairs, fmt.Sprintf("%s=%s", prop.Name, keyValues[i]))
-			i++
-		}
-	}
-
-	return fmt.Sprintf("%s(%s)", baseURL, strings.Join(keyPairs, ","))
+	url, _ := s.newEntityLinks(c).entityID(service, get)
+	return url
 }
 
 // buildMetadataJSON constrói os metadados em formato JSON
 func (s *Server) buildMetadataJSON() MetadataResponse {
+	return s.buildPrefixMetadataJSON(s.routePrefix())
+}
+
+// buildPrefixMetadataJSON constrói os metadados das entidades publicadas no prefixo de rota,
+// usando os nomes dos entity sets
+func (s *Server) buildPrefixMetadataJSON(prefix string) MetadataResponse {
 	metadata := MetadataResponse{
 		Context: "$metadata",
 		Version: "4.0",
@@ -379,12 +426,16 @@ func (s *Server) buildMetadataJSON() MetadataResponse {
 	var entities []EntityTypeMetadata
 	var entitySets []EntitySetMetadata
 
-	for name, service := range s.entities {
-		entityMetadata := service.GetMetadata()
+	for setName, name := range s.entitySetNames(prefix) {
+		entityMetadata := s.GetEntityService(name).GetMetadata()
 
 		// Constrói as propriedades
 		var properties []PropertyTypeMetadata
 		for _, prop := range entityMetadata.Properties {
+			// Propriedades fora da lista de expostas (WithExposedProperties) não são publicadas
+			if isHiddenProperty(entityMetadata, prop.Name) {
+				continue
+			}
 			property := PropertyTypeMetadata{
 				Name:       prop.Name,
 				Type:       s.mapODataType(prop.Type),
@@ -393,13 +444,28 @@ func (s *Server) buildMetadataJSON() MetadataResponse {
 				HasDefault: prop.HasDefault,
 				MaxLength:  prop.MaxLength,
 			}
+			if isDecimalProperty(prop) {
+				property.Type = "Edm.Decimal"
+				property.Precision = prop.Precision
+				property.Scale = prop.Scale
+			}
 
 			properties = append(properties, property)
 		}
 
+		// Propriedades virtuais (calculadas em Go, somente leitura)
+		for _, prop := range s.getVirtualProperties(name) {
+			properties = append(properties, PropertyTypeMetadata{
+				Name:     prop.name,
+				Type:     s.mapODataType(prop.goType),
+				Nullable: prop.nullable,
+				Computed: true,
+			})
+		}
+
 		// Entidade
 		entity := EntityTypeMetadata{
-			Name:       name,
+			Name:       setName,
 			Namespace:  "Default",
 			Keys:       s.getEntityKeys(entityMetadata),
 			Properties: properties,
@@ -409,10 +475,10 @@ func (s *Server) buildMetadataJSON() MetadataResponse {
 
 		// Entity Set
 		entitySet := EntitySetMetadata{
-			Name:       name,
-			EntityType: "Default." + name,
+			Name:       setName,
+			EntityType: "Default." + setName,
 			Kind:       "EntitySet",
-			URL:        name,
+			URL:        setName,
 		}
 
 		entitySets = append(entitySets, entitySet)
@@ -420,6 +486,10 @@ func (s *Server) buildMetadataJSON() MetadataResponse {
 
 	metadata.Entities = entities
 	metadata.EntitySets = entitySets
+	metadata.Functions = s.buildQueryPresetFunctions(prefix)
+	if prefix == s.routePrefix() {
+		s.applyServiceOperations(&metadata)
+	}
 
 	return metadata
 }
@@ -444,6 +514,7 @@ func (s *Server) mapODataType(internalType string) string {
 		"int64":     "Edm.Int64",
 		"float32":   "Edm.Single",
 		"float64":   "Edm.Double",
+		"decimal":   "Edm.Decimal",
 		"bool":      "Edm.Boolean",
 		"time.Time": "Edm.DateTimeOffset",
 		"[]byte":    "Edm.Binary",
@@ -459,15 +530,23 @@ func (s *Server) mapODataType(internalType string) string {
 
 // buildEntitySets constrói a lista de entity sets
 func (s *Server) buildEntitySets() []map[string]interface{} {
+	return s.buildPrefixEntitySets(s.routePrefix())
+}
+
+// buildPrefixEntitySets constrói a lista de entity sets publicados no prefixo de rota
+func (s *Server) buildPrefixEntitySets(prefix string) []map[string]interface{} {
 	var entitySets []map[string]interface{}
 
-	for name := range s.entities {
+	for setName := range s.entitySetNames(prefix) {
 		entitySets = append(entitySets, map[string]interface{}{
-			"name": name,
+			"name": setName,
 			"kind": "EntitySet",
-			"url":  name,
+			"url":  setName,
 		})
 	}
+	if prefix == s.routePrefix() {
+		entitySets = append(entitySets, s.buildFunctionImportEntries()...)
+	}
 
 	return entitySets
 }
@@ -576,17 +655,7 @@ func (s *Server) buildODataResponse(response *ODataResponse, isCollection bool,
 
 // writeError escreve uma resposta de erro OData
 func (s *Server) writeError(c fiber.Ctx, statusCode int, code, message string) {
-	c.Set("Content-Type", "application/json")
-	c.Status(statusCode)
-
-	errorResponse := ODataResponse{
-		Error: &ODataError{
-			Code:    code,
-			Message: message,
-		},
-	}
-
-	c.JSON(errorResponse)
+	s.writeODataError(c, statusCode, NewODataError(code, message), nil)
 }
 
 // =======================================================================================
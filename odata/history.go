@@ -0,0 +1,296 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// HISTÓRICO DE VERSÕES (TABELAS TEMPORAIS)
+// =======================================================================================
+
+const (
+	historyTableSuffix     = "_history"   // Sufixo da tabela de histórico (ex: products_history)
+	historyValidFromColumn = "valid_from" // Início da validade da versão (NULL: desde a criação)
+	historyValidToColumn   = "valid_to"   // Fim da validade da versão (instante da alteração)
+	queryOptionAsOf        = "$asof"      // Consulta da versão vigente em um instante
+)
+
+// WithHistory mantém as versões anteriores da entidade na tabela de histórico
+// (<tabela>_history, com valid_from/valid_to), gravadas antes de cada UPDATE e DELETE, e
+// habilita a consulta por chave em um instante: /Products(1)?$asof=2024-01-01T00:00:00Z
+func WithHistory(enabled bool) EntityOption {
+	return func(config *EntityConfig) {
+		config.History = enabled
+	}
+}
+
+// historyExec executa um comando na conexão do serviço ou na transação em andamento
+type historyExec func(ctx context.Context, query string, args []any) (sql.Result, error)
+
+// txHistoryExec executa os comandos do histórico na transação
+func txHistoryExec(tx *sql.Tx) historyExec {
+	return func(ctx context.Context, query string, args []any) (sql.Result, error) {
+		return tx.ExecContext(ctx, query, args...)
+	}
+}
+
+// recordHistory copia a versão atual da linha para a tabela de histórico, encerrando sua
+// validade agora. Deve ser executado antes do UPDATE/DELETE (inclusive da exclusão lógica)
+func recordHistory(ctx context.Context, provider DatabaseProvider, metadata EntityMetadata, keys map[string]any, exec historyExec) error {
+	if !metadata.History {
+		return nil
+	}
+	query, args, err := buildHistoryInsertQuery(provider, metadata, keys, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to build history query: %w", err)
+	}
+	if _, err := exec(ctx, query, args); err != nil {
+		return fmt.Errorf("failed to record history: %w", TranslateDatabaseError(err, metadata))
+	}
+	return nil
+}
+
+// historyArgs acumula os argumentos com os placeholders posicionais do dialeto
+type historyArgs struct {
+	dialect string
+	args    []any
+}
+
+// add adiciona um argumento e retorna seu placeholder (?, $n ou :n)
+func (a *historyArgs) add(value any) string {
+	a.args = append(a.args, value)
+	switch a.dialect {
+	case "postgresql":
+		return fmt.Sprintf("$%d", len(a.args))
+	case "oracle":
+		return fmt.Sprintf(":%d", len(a.args))
+	}
+	return "?"
+}
+
+// historyColumns retorna as colunas da entidade copiadas para o histórico
+func historyColumns(metadata EntityMetadata) []string {
+	var columns []string
+	for _, prop := range metadata.Properties {
+		if !prop.IsNavigation {
+			columns = append(columns, columnNameOf(prop))
+		}
+	}
+	return columns
+}
+
+// historyKeyConditions gera as condições das chaves (alias.coluna = placeholder)
+func historyKeyConditions(provider DatabaseProvider, metadata EntityMetadata, keys map[string]any, alias string, args *historyArgs) ([]string, error) {
+	var conditions []string
+	for _, prop := range metadata.Properties {
+		if !prop.IsKey {
+			continue
+		}
+		value, ok := keys[prop.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing key value for %s", prop.Name)
+		}
+		// Chaves vindas da URL do $batch chegam como texto
+		if converter, ok := provider.(interface {
+			ConvertValue(value interface{}, targetType string) (interface{}, error)
+		}); ok {
+			converted, err := converter.ConvertValue(value, prop.Type)
+			if err != nil {
+				return nil, err
+			}
+			value = converted
+		}
+		conditions = append(conditions, fmt.Sprintf("%s.%s = %s", alias, columnNameOf(prop), args.add(value)))
+	}
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("no valid keys found for history")
+	}
+	return conditions, nil
+}
+
+// buildHistoryInsertQuery gera o INSERT ... SELECT da versão atual. O início da validade é o
+// fim da versão anterior da mesma chave (NULL na primeira alteração)
+func buildHistoryInsertQuery(provider DatabaseProvider, metadata EntityMetadata, keys map[string]any, validTo time.Time) (string, []any, error) {
+	tableName := tableNameOf(metadata)
+	historyTable := tableName + historyTableSuffix
+	args := &historyArgs{dialect: GetDialect(provider.GetDriverName()).GetName()}
+
+	columns := historyColumns(metadata)
+	selected := make([]string, len(columns))
+	for i, column := range columns {
+		selected[i] = "src." + column
+	}
+
+	var previous []string
+	for _, prop := range metadata.Properties {
+		if prop.IsKey {
+			column := columnNameOf(prop)
+			previous = append(previous, fmt.Sprintf("h.%s = src.%s", column, column))
+		}
+	}
+	validFrom := fmt.Sprintf("(SELECT MAX(h.%s) FROM %s h WHERE %s)",
+		historyValidToColumn, historyTable, strings.Join(previous, " AND "))
+	validToPlaceholder := args.add(validTo)
+
+	conditions, err := historyKeyConditions(provider, metadata, keys, "src", args)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s, %s, %s) SELECT %s, %s, %s FROM %s src WHERE %s",
+		historyTable, strings.Join(columns, ", "), historyValidFromColumn, historyValidToColumn,
+		strings.Join(selected, ", "), validFrom, validToPlaceholder,
+		tableName, strings.Join(conditions, " AND "))
+	return query, args.args, nil
+}
+
+// buildHistoryAsOfQuery gera o SELECT da versão do histórico vigente no instante informado
+func buildHistoryAsOfQuery(provider DatabaseProvider, metadata EntityMetadata, keys map[string]any, asOf time.Time) (string, []any, error) {
+	dialect := GetDialect(provider.GetDriverName())
+	args := &historyArgs{dialect: dialect.GetName()}
+
+	conditions, err := historyKeyConditions(provider, metadata, keys, "h", args)
+	if err != nil {
+		return "", nil, err
+	}
+	conditions = append(conditions,
+		fmt.Sprintf("(h.%s IS NULL OR h.%s <= %s)", historyValidFromColumn, historyValidFromColumn, args.add(asOf)),
+		fmt.Sprintf("h.%s > %s", historyValidToColumn, args.add(asOf)))
+
+	columns := historyColumns(metadata)
+	for i, column := range columns {
+		columns[i] = "h." + column
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s h WHERE %s ORDER BY h.%s %s",
+		strings.Join(columns, ", "), tableNameOf(metadata)+historyTableSuffix,
+		strings.Join(conditions, " AND "), historyValidToColumn, dialect.BuildLimitClause(1, 0))
+	return strings.TrimSpace(query), args.args, nil
+}
+
+// GetAsOf retorna a versão da entidade vigente no instante informado: a versão do histórico
+// encerrada depois dele ou, se não houver, a versão atual
+func (s *BaseEntityService) GetAsOf(ctx context.Context, keys map[string]any, asOf time.Time) (any, error) {
+	if !s.metadata.History {
+		return nil, fmt.Errorf("entity %s does not keep history", s.metadata.Name)
+	}
+
+	query, args, err := buildHistoryAsOfQuery(s.provider, s.metadata, keys, asOf.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build history query: %w", err)
+	}
+	rows, trace, err := s.executeQuery(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute history query: %w", err)
+	}
+	results, err := s.scanRows(rows, []ExpandOption{})
+	rows.Close()
+	trace.finish(int64(len(results)), err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan rows: %w", err)
+	}
+	if len(results) == 0 {
+		return s.Get(ctx, keys)
+	}
+
+	if err := s.server.decryptPropertyValues(ctx, s.metadata, results); err != nil {
+		return nil, err
+	}
+	// Uma versão excluída logicamente estava na lixeira naquele instante
+	if property := s.metadata.SoftDeleteProperty; property != "" {
+		if get := entityValueGetter(results[0]); get != nil {
+			if deletedAt, _ := get(property); deletedAt != nil {
+				return nil, fmt.Errorf("entity not found")
+			}
+		}
+	}
+	return results[0], nil
+}
+
+// GetAsOf retorna a versão da entidade no instante usando o provider do tenant
+func (s *MultiTenantEntityService) GetAsOf(ctx context.Context, keys map[string]any, asOf time.Time) (any, error) {
+	return s.withProviderContext(ctx, "GetAsOf", func() (any, error) {
+		return s.BaseEntityService.GetAsOf(ctx, keys, asOf)
+	})
+}
+
+// historyEntityService é implementado pelos serviços com histórico de versões
+type historyEntityService interface {
+	GetAsOf(ctx context.Context, keys map[string]any, asOf time.Time) (any, error)
+}
+
+// handleGetEntityAsOf lida com GET /Entidade(chave)?$asof=instante
+func (s *Server) handleGetEntityAsOf(c fiber.Ctx, ctx context.Context, service EntityService, keys map[string]interface{}, value string) error {
+	asOf, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		s.writeError(c, fiber.StatusBadRequest, "InvalidAsOf",
+			fmt.Sprintf("invalid $asof '%s': expected RFC 3339 date-time", value))
+		return nil
+	}
+	historyService, ok := service.(historyEntityService)
+	if !ok || !service.GetMetadata().History {
+		s.writeError(c, fiber.StatusBadRequest, "InvalidAsOf", "Entity does not keep history")
+		return nil
+	}
+
+	entity, err := historyService.GetAsOf(ctx, keys, asOf)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			s.writeError(c, fiber.StatusNotFound, "EntityNotFound", "Entity not found")
+			return nil
+		}
+		s.writeEntityError(c, fiber.StatusInternalServerError, "QueryError", err)
+		return nil
+	}
+
+	response := &ODataResponse{Value: []interface{}{entity}}
+	return s.writeEntityJSON(c, service, s.buildODataResponse(response, false, service.GetMetadata()))
+}
+
+// historyMetadata descreve a tabela de histórico: as colunas da entidade, sem chave primária
+// nem restrições, mais o período de validade de cada versão
+func historyMetadata(metadata EntityMetadata) EntityMetadata {
+	history := EntityMetadata{
+		Name:      metadata.Name + historyTableSuffix,
+		TableName: tableNameOf(metadata) + historyTableSuffix,
+		Schema:    metadata.Schema,
+	}
+	for _, prop := range metadata.Properties {
+		if prop.IsNavigation {
+			continue
+		}
+		if prop.IsKey {
+			history.historyIndex = append(history.historyIndex, columnNameOf(prop))
+		}
+		history.Properties = append(history.Properties, PropertyMetadata{
+			Name:        prop.Name,
+			ColumnName:  columnNameOf(prop),
+			Type:        prop.Type,
+			IsNullable:  true,
+			MaxLength:   prop.MaxLength,
+			Precision:   prop.Precision,
+			Scale:       prop.Scale,
+			IsEncrypted: prop.IsEncrypted,
+		})
+	}
+	for _, column := range []string{historyValidFromColumn, historyValidToColumn} {
+		history.Properties = append(history.Properties, PropertyMetadata{
+			Name: column, ColumnName: column, Type: "time.Time", IsNullable: column == historyValidFromColumn,
+		})
+	}
+	history.historyIndex = append(history.historyIndex, historyValidToColumn)
+	return history
+}
+
+// tableNameOf retorna o nome da tabela da entidade (sem schema)
+func tableNameOf(metadata EntityMetadata) string {
+	if metadata.TableName != "" {
+		return metadata.TableName
+	}
+	return metadata.Name
+}
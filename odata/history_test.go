@@ -0,0 +1,154 @@
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHistoryTestServer cria o servidor de teste com Products versionado (tabela criada pelo
+// AutoMigrate)
+func newHistoryTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := newBatchGetTestServer(t)
+	metadata := server.entities["Products"].GetMetadata()
+	metadata.History = true
+	server.entities["Products"] = NewBaseEntityService(server.provider, metadata, server)
+
+	_, err := server.AutoMigrate(context.Background(), DefaultAutoMigrateOptions())
+	require.NoError(t, err)
+
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+	return server
+}
+
+func TestServer_HistoryAsOf(t *testing.T) {
+	server := newHistoryTestServer(t)
+
+	send := func(t *testing.T, method, path, body string) (int, map[string]interface{}) {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var result map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result
+	}
+	instant := func() time.Time {
+		now := time.Now().UTC()
+		time.Sleep(5 * time.Millisecond)
+		return now
+	}
+
+	beforeUpdate := instant()
+	status, _ := send(t, http.MethodPatch, "/odata/Products(1)", `{"name":"Mouse Pro"}`)
+	require.Equal(t, http.StatusOK, status)
+	time.Sleep(5 * time.Millisecond)
+	beforeDelete := instant()
+	status, _ = send(t, http.MethodDelete, "/odata/Products(1)", "")
+	require.Equal(t, http.StatusNoContent, status)
+	time.Sleep(5 * time.Millisecond)
+	afterDelete := instant()
+
+	var versions int
+	require.NoError(t, server.provider.GetConnection().QueryRow("SELECT COUNT(*) FROM products_history WHERE id = 1").Scan(&versions))
+	assert.Equal(t, 2, versions)
+
+	asOf := func(at time.Time) string {
+		return "/odata/Products(1)?$asof=" + at.Format(time.RFC3339Nano)
+	}
+
+	t.Run("Versão anterior à alteração", func(t *testing.T) {
+		status, body := send(t, http.MethodGet, asOf(beforeUpdate), "")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Equal(t, "Mouse", body["name"])
+	})
+
+	t.Run("Versão anterior à exclusão", func(t *testing.T) {
+		status, body := send(t, http.MethodGet, asOf(beforeDelete), "")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Equal(t, "Mouse Pro", body["name"])
+	})
+
+	t.Run("Excluída no instante", func(t *testing.T) {
+		status, _ := send(t, http.MethodGet, asOf(afterDelete), "")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Sem alterações retorna a versão atual", func(t *testing.T) {
+		status, body := send(t, http.MethodGet, "/odata/Products(2)?$asof="+beforeUpdate.Format(time.RFC3339), "")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Equal(t, "Teclado", body["name"])
+	})
+
+	t.Run("Instante inválido", func(t *testing.T) {
+		status, _ := send(t, http.MethodGet, "/odata/Products(2)?$asof=ontem", "")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestServer_HistoryAsOfDisabled(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	req := httptest.NewRequest(http.MethodGet, "/odata/Products(1)?$asof=2024-01-01T00:00:00Z", nil)
+	resp, err := server.router.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestBuildHistoryQueries(t *testing.T) {
+	metadata := EntityMetadata{
+		Name:      "Products",
+		TableName: "products",
+		History:   true,
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "name", ColumnName: "name", Type: "string"},
+		},
+	}
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	postgres := &PostgreSQLProvider{BaseProvider: BaseProvider{driverName: "pgx"}}
+	oracle := &OracleProvider{BaseProvider: &BaseProvider{driverName: "oracle"}}
+
+	query, args, err := buildHistoryInsertQuery(postgres, metadata, map[string]any{"id": int64(1)}, at)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO products_history (id, name, valid_from, valid_to) SELECT src.id, src.name, "+
+		"(SELECT MAX(h.valid_to) FROM products_history h WHERE h.id = src.id), $1 FROM products src WHERE src.id = $2", query)
+	assert.Equal(t, []any{at, int64(1)}, args)
+
+	query, _, err = buildHistoryAsOfQuery(oracle, metadata, map[string]any{"id": int64(1)}, at)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT h.id, h.name FROM products_history h WHERE h.id = :1 AND "+
+		"(h.valid_from IS NULL OR h.valid_from <= :2) AND h.valid_to > :3 ORDER BY h.valid_to FETCH NEXT 1 ROWS ONLY", query)
+
+	_, _, err = buildHistoryAsOfQuery(postgres, metadata, map[string]any{}, at)
+	assert.Error(t, err, "sem chave")
+}
+
+func TestGenerateDDL_History(t *testing.T) {
+	server := newHistoryTestServer(t)
+
+	statements, err := server.GenerateDDL(nil)
+	require.NoError(t, err)
+	script := strings.Join(statements, ";\n")
+	assert.Contains(t, script, "CREATE TABLE products_history (id BIGINT, name VARCHAR(255), price DOUBLE, valid_from DATETIME, valid_to DATETIME NOT NULL)")
+	assert.Contains(t, script, "CREATE INDEX ix_products_history_valid_to ON products_history (id, valid_to)")
+}
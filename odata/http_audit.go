@@ -0,0 +1,287 @@
+package odata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// AUDITORIA HTTP (REQUEST/RESPONSE COM REDAÇÃO DE CAMPOS)
+// =======================================================================================
+
+// HTTPAuditConfig configura o registro das requisições HTTP
+type HTTPAuditConfig struct {
+	// Habilita/desabilita a auditoria HTTP
+	Enabled bool
+
+	// Captura o corpo da requisição e/ou da resposta
+	CaptureRequestBody  bool
+	CaptureResponseBody bool
+
+	// Tamanho máximo (bytes) de cada corpo registrado; o excedente é truncado (padrão: 4KB)
+	MaxBodySize int
+
+	// Campos JSON, parâmetros de query e de formulário cujo valor é substituído por
+	// RedactedValue. A comparação ignora maiúsculas/minúsculas (padrão: DefaultHTTPAuditRedactFields)
+	RedactFields []string
+
+	// Prefixos de caminho que não são auditados (ex: /health)
+	SkipPaths []string
+
+	// Destino das entradas (nil = logger do servidor, uma linha JSON por requisição)
+	Sink HTTPAuditSink
+}
+
+// RedactedValue substitui os valores dos campos redigidos
+const RedactedValue = "[REDACTED]"
+
+// DefaultHTTPAuditRedactFields são os campos redigidos quando RedactFields não é informado
+var DefaultHTTPAuditRedactFields = []string{
+	"password", "senha", "secret", "client_secret", "token", "access_token", "refresh_token", "authorization",
+}
+
+// DefaultHTTPAuditConfig retorna configuração padrão da auditoria HTTP (desabilitada)
+func DefaultHTTPAuditConfig() *HTTPAuditConfig {
+	return &HTTPAuditConfig{
+		Enabled:      false,
+		MaxBodySize:  4096,
+		RedactFields: DefaultHTTPAuditRedactFields,
+	}
+}
+
+// HTTPAuditEntry representa uma requisição auditada
+type HTTPAuditEntry struct {
+	Timestamp    time.Time     `json:"timestamp"`
+	RequestID    string        `json:"request_id,omitempty"`
+	Method       string        `json:"method"`
+	URL          string        `json:"url"`
+	Status       int           `json:"status"`
+	Duration     time.Duration `json:"-"`
+	DurationMs   int64         `json:"duration_ms"`
+	User         string        `json:"user,omitempty"`
+	Impersonator string        `json:"impersonated_by,omitempty"`
+	TenantID     string        `json:"tenant_id,omitempty"`
+	IP           string        `json:"ip"`
+	RequestBody  any           `json:"request_body,omitempty"`
+	ResponseBody any           `json:"response_body,omitempty"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// HTTPAuditSink recebe as entradas da auditoria HTTP (ex: fila, banco ou serviço externo)
+type HTTPAuditSink interface {
+	Write(entry HTTPAuditEntry) error
+}
+
+// HTTPAuditSinkFunc adapta uma função a HTTPAuditSink
+type HTTPAuditSinkFunc func(entry HTTPAuditEntry) error
+
+// Write implementa HTTPAuditSink
+func (f HTTPAuditSinkFunc) Write(entry HTTPAuditEntry) error {
+	return f(entry)
+}
+
+// httpAuditConfig retorna a configuração de auditoria HTTP em uso, ou nil se desabilitada
+func (s *Server) httpAuditConfig() *HTTPAuditConfig {
+	if s.config == nil || s.config.HTTPAuditConfig == nil || !s.config.HTTPAuditConfig.Enabled {
+		return nil
+	}
+	return s.config.HTTPAuditConfig
+}
+
+// HTTPAuditMiddleware registra método, URL, status, duração, usuário, tenant e, opcionalmente,
+// os corpos da requisição e da resposta. A configuração é lida a cada requisição, permitindo
+// habilitá-la após a criação do servidor
+func (s *Server) HTTPAuditMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		config := s.httpAuditConfig()
+		if config == nil || config.skipPath(c.Path()) {
+			return c.Next()
+		}
+
+		start := time.Now()
+		err := c.Next()
+		if err != nil {
+			// Gera a resposta de erro agora para registrar o status e o corpo reais
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		duration := time.Since(start)
+
+		redact := newHTTPAuditRedactor(config.RedactFields)
+		entry := HTTPAuditEntry{
+			Timestamp:  start,
+			RequestID:  c.Get("X-Request-ID", string(c.Response().Header.Peek("X-Request-ID"))),
+			Method:     c.Method(),
+			URL:        redact.url(c.OriginalURL()),
+			Status:     c.Response().StatusCode(),
+			Duration:   duration,
+			DurationMs: duration.Milliseconds(),
+			TenantID:   GetCurrentTenant(c),
+			IP:         c.IP(),
+		}
+		if user := GetCurrentUser(c); user != nil {
+			entry.User = user.Username
+		}
+		if admin := GetImpersonator(c); admin != nil {
+			entry.Impersonator = admin.Username
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		if config.CaptureRequestBody {
+			entry.RequestBody = redact.body(c.Body(), c.Get("Content-Type"), config.maxBodySize())
+		}
+		// Corpo em streaming (ex: $batch multipart) não é capturado: lê-lo consumiria o stream
+		if config.CaptureResponseBody && !c.Response().IsBodyStream() {
+			entry.ResponseBody = redact.body(c.Response().Body(), string(c.Response().Header.ContentType()), config.maxBodySize())
+		}
+
+		s.writeHTTPAudit(config, entry)
+		return nil
+	}
+}
+
+// writeHTTPAudit envia a entrada ao sink configurado ou ao logger do servidor
+func (s *Server) writeHTTPAudit(config *HTTPAuditConfig, entry HTTPAuditEntry) {
+	if config.Sink != nil {
+		if err := config.Sink.Write(entry); err != nil && s.logger != nil {
+			s.logger.Printf("⚠️  Erro ao gravar auditoria HTTP: %v", err)
+		}
+		return
+	}
+	if s.logger == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		s.logger.Printf("⚠️  Erro ao serializar auditoria HTTP: %v", err)
+		return
+	}
+	s.logger.Printf("[HTTP AUDIT] %s", data)
+}
+
+// skipPath verifica se o caminho não deve ser auditado
+func (c *HTTPAuditConfig) skipPath(path string) bool {
+	for _, prefix := range c.SkipPaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxBodySize retorna o limite de cada corpo registrado
+func (c *HTTPAuditConfig) maxBodySize() int {
+	if c.MaxBodySize <= 0 {
+		return 4096
+	}
+	return c.MaxBodySize
+}
+
+// httpAuditRedactor substitui os valores dos campos sensíveis
+type httpAuditRedactor map[string]struct{}
+
+// newHTTPAuditRedactor cria o redator a partir dos nomes de campos (nil = campos padrão)
+func newHTTPAuditRedactor(fields []string) httpAuditRedactor {
+	if fields == nil {
+		fields = DefaultHTTPAuditRedactFields
+	}
+	redactor := make(httpAuditRedactor, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			redactor[strings.ToLower(field)] = struct{}{}
+		}
+	}
+	return redactor
+}
+
+// redacts verifica se o campo deve ser redigido
+func (r httpAuditRedactor) redacts(field string) bool {
+	_, ok := r[strings.ToLower(field)]
+	return ok
+}
+
+// url redige os parâmetros sensíveis da query string
+func (r httpAuditRedactor) url(rawURL string) string {
+	path, rawQuery, found := strings.Cut(rawURL, "?")
+	if !found || rawQuery == "" {
+		return rawURL
+	}
+	return path + "?" + r.form(rawQuery)
+}
+
+// form redige os valores sensíveis de uma string application/x-www-form-urlencoded,
+// preservando a ordem e a codificação dos demais parâmetros
+func (r httpAuditRedactor) form(raw string) string {
+	params := strings.Split(raw, "&")
+	for i, param := range params {
+		name, _, _ := strings.Cut(param, "=")
+		if decoded, err := url.QueryUnescape(name); err == nil && r.redacts(decoded) {
+			params[i] = name + "=" + url.QueryEscape(RedactedValue)
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// body prepara o corpo para registro: JSON é redigido e mantido estruturado, formulários são
+// redigidos e os demais tipos são registrados como texto (ou descritos, se binários)
+func (r httpAuditRedactor) body(body []byte, contentType string, maxSize int) any {
+	if len(body) == 0 {
+		return nil
+	}
+	baseContentType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+
+	switch {
+	case strings.Contains(baseContentType, "json"):
+		var value any
+		if err := json.Unmarshal(body, &value); err == nil {
+			redacted, err := json.Marshal(r.value(value))
+			if err == nil {
+				if len(redacted) <= maxSize {
+					return json.RawMessage(redacted)
+				}
+				return truncateAuditBody(string(redacted), maxSize)
+			}
+		}
+	case baseContentType == "application/x-www-form-urlencoded":
+		return truncateAuditBody(r.form(string(body)), maxSize)
+	}
+
+	if !isPrintableUTF8(string(body)) {
+		return fmt.Sprintf("[binary data, %d bytes]", len(body))
+	}
+	return truncateAuditBody(string(body), maxSize)
+}
+
+// value redige recursivamente os campos sensíveis de um valor JSON decodificado
+func (r httpAuditRedactor) value(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if r.redacts(key) {
+				v[key] = RedactedValue
+			} else {
+				v[key] = r.value(item)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = r.value(item)
+		}
+	}
+	return value
+}
+
+// truncateAuditBody limita o corpo registrado a maxSize bytes
+func truncateAuditBody(body string, maxSize int) string {
+	if len(body) <= maxSize {
+		return body
+	}
+	return fmt.Sprintf("%s... [truncated, original size: %d bytes]", body[:maxSize], len(body))
+}
@@ -0,0 +1,126 @@
+package odata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_HTTPAuditMiddleware(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
+
+	var entries []HTTPAuditEntry
+	server.SetHTTPAudit(&HTTPAuditConfig{
+		Enabled:             true,
+		CaptureRequestBody:  true,
+		CaptureResponseBody: true,
+		MaxBodySize:         200,
+		RedactFields:        []string{"password", " access_token"},
+		SkipPaths:           []string{"/health"},
+		Sink: HTTPAuditSinkFunc(func(entry HTTPAuditEntry) error {
+			entries = append(entries, entry)
+			return nil
+		}),
+	})
+	server.router.Use(server.HTTPAuditMiddleware())
+	server.router.Use(func(c fiber.Ctx) error {
+		if user := c.Get("X-Test-User"); user != "" {
+			c.Locals(UserContextKey, &UserIdentity{Username: user})
+		}
+		return c.Next()
+	})
+	server.setupEntityRoutes("Products")
+	server.router.Post("/login", func(c fiber.Ctx) error {
+		return c.JSON(map[string]any{"access_token": "abc123", "user": map[string]any{"name": "ana"}})
+	})
+	server.router.Post("/form", func(c fiber.Ctx) error {
+		return c.SendString(strings.Repeat("x", 500))
+	})
+	server.router.Get("/health", func(c fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	do := func(t *testing.T, method, path, contentType, body string, headers ...string) HTTPAuditEntry {
+		t.Helper()
+		entries = nil
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		require.Len(t, entries, 1)
+		return entries[0]
+	}
+
+	t.Run("Consulta com usuário", func(t *testing.T) {
+		entry := do(t, http.MethodGet, "/odata/Products(2)?$select=name", "", "", "X-Test-User", "ana")
+		assert.Equal(t, http.MethodGet, entry.Method)
+		assert.Equal(t, "/odata/Products(2)?$select=name", entry.URL)
+		assert.Equal(t, http.StatusOK, entry.Status)
+		assert.Equal(t, "ana", entry.User)
+		assert.Nil(t, entry.RequestBody)
+
+		response, ok := entry.ResponseBody.(json.RawMessage)
+		require.True(t, ok, "corpo JSON é registrado estruturado")
+		assert.Contains(t, string(response), `"name":"Teclado"`)
+	})
+
+	t.Run("Campos redigidos na URL e nos corpos JSON", func(t *testing.T) {
+		entry := do(t, http.MethodPost, "/login?access_token=abc&mode=full", "application/json",
+			`{"username":"ana","Password":"s3cret","items":[{"password":"x"}]}`)
+		assert.Equal(t, "/login?access_token=%5BREDACTED%5D&mode=full", entry.URL)
+
+		assert.JSONEq(t, `{"username":"ana","Password":"[REDACTED]","items":[{"password":"[REDACTED]"}]}`,
+			string(entry.RequestBody.(json.RawMessage)))
+		assert.JSONEq(t, `{"access_token":"[REDACTED]","user":{"name":"ana"}}`,
+			string(entry.ResponseBody.(json.RawMessage)))
+
+		data, err := json.Marshal(entry)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "s3cret")
+		assert.NotContains(t, string(data), "abc123")
+	})
+
+	t.Run("Formulário e truncamento", func(t *testing.T) {
+		entry := do(t, http.MethodPost, "/form", "application/x-www-form-urlencoded", "user=ana&password=s3cret")
+		assert.Equal(t, "user=ana&password=%5BREDACTED%5D", entry.RequestBody)
+		assert.Contains(t, entry.ResponseBody, "[truncated, original size: 500 bytes]")
+	})
+
+	t.Run("Erros registram o status final", func(t *testing.T) {
+		entry := do(t, http.MethodGet, "/odata/Products(99)", "", "")
+		assert.Equal(t, http.StatusNotFound, entry.Status)
+
+		entry = do(t, http.MethodGet, "/odata/Unknown", "", "")
+		assert.Equal(t, http.StatusNotFound, entry.Status)
+		assert.NotEmpty(t, entry.Error)
+	})
+
+	t.Run("Caminhos ignorados e auditoria desabilitada", func(t *testing.T) {
+		entries = nil
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/health", nil))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Empty(t, entries)
+
+		server.config.HTTPAuditConfig.Enabled = false
+		resp, err = server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Products", nil))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Empty(t, entries)
+	})
+}
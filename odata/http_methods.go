@@ -0,0 +1,37 @@
+package odata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// HEAD E OPTIONS NAS ROTAS DAS ENTIDADES
+// =======================================================================================
+
+// setResponseETag define o ETag (fraco) da resposta de leitura a partir do corpo gerado. No
+// HEAD o corpo é descartado pelo Fiber, mantendo o ETag e o Content-Length do GET
+func setResponseETag(c fiber.Ctx) {
+	if c.Response().StatusCode() != fiber.StatusOK || c.Response().IsBodyStream() {
+		return
+	}
+	body := c.Response().Body()
+	if len(body) == 0 {
+		return
+	}
+	sum := sha256.Sum256(body)
+	c.Set(fiber.HeaderETag, `W/"`+hex.EncodeToString(sum[:16])+`"`)
+}
+
+// handleEntityOptions responde ao OPTIONS com os métodos aceitos pela rota (conforme as
+// permissões e o modo somente leitura da entidade)
+func (s *Server) handleEntityOptions(methods []string) fiber.Handler {
+	allow := strings.Join(append(methods, fiber.MethodOptions), ", ")
+	return func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderAllow, allow)
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
@@ -0,0 +1,116 @@
+package odata
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_HeadEntityRoutes(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	send := func(t *testing.T, method, path string) (*http.Response, []byte) {
+		t.Helper()
+		resp, err := server.router.Test(httptest.NewRequest(method, path, nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	for _, path := range []string{"/odata/Products", "/odata/Products(1)", "/odata/Products?$filter=price%20gt%2020"} {
+		t.Run(path, func(t *testing.T) {
+			get, getBody := send(t, http.MethodGet, path)
+			require.Equal(t, http.StatusOK, get.StatusCode)
+			assert.NotEmpty(t, get.Header.Get("ETag"))
+
+			head, headBody := send(t, http.MethodHead, path)
+			require.Equal(t, http.StatusOK, head.StatusCode)
+			assert.Empty(t, headBody)
+			assert.Equal(t, get.Header.Get("ETag"), head.Header.Get("ETag"))
+			assert.Equal(t, strconv.Itoa(len(getBody)), head.Header.Get("Content-Length"))
+		})
+	}
+
+	t.Run("ETag muda com o conteúdo", func(t *testing.T) {
+		before, _ := send(t, http.MethodHead, "/odata/Products(1)")
+		_, err := server.provider.GetConnection().Exec("UPDATE products SET price = 11 WHERE id = 1")
+		require.NoError(t, err)
+		after, _ := send(t, http.MethodHead, "/odata/Products(1)")
+		assert.NotEqual(t, before.Header.Get("ETag"), after.Header.Get("ETag"))
+	})
+
+	t.Run("Entidade inexistente", func(t *testing.T) {
+		resp, _ := send(t, http.MethodHead, "/odata/Products(99)")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("ETag"))
+	})
+}
+
+func TestServer_OptionsEntityRoutes(t *testing.T) {
+	allow := func(t *testing.T, server *Server, path string) string {
+		t.Helper()
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodOptions, path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		return resp.Header.Get("Allow")
+	}
+
+	tests := []struct {
+		name       string
+		auth       *EntityAuthConfig
+		collection string
+		entity     string
+	}{
+		{
+			name:       "Sem restrições",
+			collection: "GET, HEAD, POST, OPTIONS",
+			entity:     "GET, HEAD, PUT, PATCH, DELETE, OPTIONS",
+		},
+		{
+			name:       "Somente leitura",
+			auth:       &EntityAuthConfig{ReadOnly: true},
+			collection: "GET, HEAD, OPTIONS",
+			entity:     "GET, HEAD, OPTIONS",
+		},
+		{
+			name:       "Permissões",
+			auth:       &EntityAuthConfig{Permissions: []string{"GET", "PATCH"}},
+			collection: "GET, HEAD, OPTIONS",
+			entity:     "GET, HEAD, PATCH, OPTIONS",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newBatchGetTestServer(t)
+			if tt.auth != nil {
+				server.entityAuth["Products"] = *tt.auth
+			}
+			server.router = fiber.New()
+			server.setupEntityRoutes("Products")
+
+			assert.Equal(t, tt.collection, allow(t, server, "/odata/Products"))
+			assert.Equal(t, tt.entity, allow(t, server, "/odata/Products(1)"))
+		})
+	}
+
+	t.Run("HEAD em entidade somente leitura", func(t *testing.T) {
+		server := newBatchGetTestServer(t)
+		server.entityAuth["Products"] = EntityAuthConfig{ReadOnly: true}
+		server.router = fiber.New()
+		server.setupEntityRoutes("Products")
+
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodHead, "/odata/Products(1)", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
@@ -0,0 +1,354 @@
+package odata
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================================
+// GERADORES DE ID (primaryKey:"idGenerator:<nome>")
+// =================================================================================================
+
+// IDGenerator gera o valor da chave de uma nova entidade no servidor, antes do INSERT.
+// Implementações devem ser seguras para uso concorrente
+type IDGenerator interface {
+	NewID() (any, error)
+}
+
+// IDGeneratorFunc adapta uma função a IDGenerator
+type IDGeneratorFunc func() (any, error)
+
+// NewID chama a função
+func (f IDGeneratorFunc) NewID() (any, error) {
+	return f()
+}
+
+// idGeneratorRegistry guarda os geradores registrados com RegisterIDGenerator
+var idGeneratorRegistry = struct {
+	sync.RWMutex
+	generators map[string]IDGenerator
+}{generators: make(map[string]IDGenerator)}
+
+// RegisterIDGenerator registra um gerador de ID, usado pelas chaves com
+// primaryKey:"idGenerator:<nome>". O nome não diferencia maiúsculas/minúsculas e um gerador
+// registrado substitui o embutido de mesmo nome (snowflake, ulid, ksuid, guid, uuid36...)
+func RegisterIDGenerator(name string, generator IDGenerator) {
+	idGeneratorRegistry.Lock()
+	defer idGeneratorRegistry.Unlock()
+	if generator == nil {
+		delete(idGeneratorRegistry.generators, strings.ToLower(name))
+		return
+	}
+	idGeneratorRegistry.generators[strings.ToLower(name)] = generator
+}
+
+// LookupIDGenerator retorna o gerador registrado com RegisterIDGenerator
+func LookupIDGenerator(name string) (IDGenerator, bool) {
+	idGeneratorRegistry.RLock()
+	defer idGeneratorRegistry.RUnlock()
+	generator, ok := idGeneratorRegistry.generators[strings.ToLower(name)]
+	return generator, ok
+}
+
+// idGenerator resolve o gerador de uma chave: registrado, embutido ou nenhum (o banco gera
+// o valor: sequence, identity, auto)
+func (s *Server) idGenerator(name string) (IDGenerator, bool) {
+	if name == "" {
+		return nil, false
+	}
+	if generator, ok := LookupIDGenerator(name); ok {
+		return generator, true
+	}
+
+	switch IDGeneratorType(strings.ToLower(name)) {
+	case IDGeneratorSnowflake:
+		s.snowflakeOnce.Do(func() {
+			var nodeID int64
+			if s.config != nil {
+				nodeID = s.config.SnowflakeNodeID
+			}
+			s.snowflake, s.snowflakeErr = NewSnowflakeGenerator(nodeID)
+		})
+		if s.snowflakeErr != nil {
+			return IDGeneratorFunc(func() (any, error) { return nil, s.snowflakeErr }), true
+		}
+		return s.snowflake, true
+	case IDGeneratorUlid:
+		return defaultULIDGenerator, true
+	case IDGeneratorKsuid:
+		return IDGeneratorFunc(newKSUID), true
+	case IDGeneratorGuid, IDGeneratorUuid36:
+		return IDGeneratorFunc(func() (any, error) { return uuid.NewString(), nil }), true
+	case IDGeneratorUuid38:
+		return IDGeneratorFunc(func() (any, error) { return "{" + uuid.NewString() + "}", nil }), true
+	case IDGeneratorUuid32:
+		return IDGeneratorFunc(func() (any, error) { return strings.ReplaceAll(uuid.NewString(), "-", ""), nil }), true
+	case IDGeneratorType(strings.ToLower(string(IDGeneratorSmartGuid))):
+		// UUID v7: ordenado pelo instante de criação, melhor para índices que o v4
+		return IDGeneratorFunc(func() (any, error) {
+			id, err := uuid.NewV7()
+			if err != nil {
+				return nil, err
+			}
+			return id.String(), nil
+		}), true
+	}
+	return nil, false
+}
+
+// isServerGeneratedKey verifica se a chave é gerada no servidor (e não pelo banco)
+func (s *Server) isServerGeneratedKey(prop PropertyMetadata) bool {
+	if !prop.IsKey || s == nil {
+		return false
+	}
+	_, ok := s.idGenerator(prop.IDGenerator)
+	return ok
+}
+
+// generateKeyValues preenche as chaves com gerador do servidor ausentes (ou zeradas) no
+// payload. Retorna se algum valor foi gerado
+func (s *Server) generateKeyValues(metadata EntityMetadata, data map[string]any) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+
+	generated := false
+	for _, prop := range metadata.Properties {
+		if !prop.IsKey || prop.IDGenerator == "" || !isZeroKeyValue(data[prop.Name]) {
+			continue
+		}
+		generator, ok := s.idGenerator(prop.IDGenerator)
+		if !ok {
+			continue
+		}
+
+		id, err := generator.NewID()
+		if err != nil {
+			return false, fmt.Errorf("erro ao gerar %s para %s.%s: %w", prop.IDGenerator, metadata.Name, prop.Name, err)
+		}
+		value, err := convertGeneratedID(id, prop)
+		if err != nil {
+			return false, fmt.Errorf("gerador %s incompatível com %s.%s: %w", prop.IDGenerator, metadata.Name, prop.Name, err)
+		}
+		data[prop.Name] = value
+		generated = true
+	}
+	return generated, nil
+}
+
+// isZeroKeyValue verifica se o valor da chave está ausente (nil, "" ou 0)
+func isZeroKeyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case int32:
+		return v == 0
+	case int64:
+		return v == 0
+	case uint64:
+		return v == 0
+	case float64:
+		return v == 0
+	}
+	return false
+}
+
+// convertGeneratedID ajusta o ID gerado ao tipo da propriedade: IDs numéricos podem ser
+// gravados em propriedades texto; IDs texto exigem propriedade texto
+func convertGeneratedID(id any, prop PropertyMetadata) (any, error) {
+	switch prop.Type {
+	case "string":
+		return fmt.Sprint(id), nil
+	case "int", "int32", "int64", "uint", "uint32", "uint64":
+		switch v := id.(type) {
+		case int64, int, uint64:
+			return v, nil
+		case string:
+			return nil, fmt.Errorf("ID %q não é numérico (tipo %s)", v, prop.Type)
+		}
+	}
+	return id, nil
+}
+
+// =================================================================================================
+// SNOWFLAKE
+// =================================================================================================
+
+// SnowflakeEpoch é o instante zero dos IDs snowflake
+var SnowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+
+	// MaxSnowflakeNodeID é o maior ID de nó aceito (10 bits)
+	MaxSnowflakeNodeID = 1<<snowflakeNodeBits - 1
+
+	snowflakeMaxSequence = 1<<snowflakeSequenceBits - 1
+)
+
+// SnowflakeGenerator gera IDs int64 ordenáveis por tempo: 41 bits de milissegundos desde
+// SnowflakeEpoch, 10 bits do nó e 12 bits de sequência (4096 IDs por milissegundo por nó).
+// Cada instância do serviço precisa de um nó distinto para evitar colisões
+type SnowflakeGenerator struct {
+	mu        sync.Mutex
+	nodeID    int64
+	lastMilli int64
+	sequence  int64
+	now       func() time.Time
+}
+
+// NewSnowflakeGenerator cria um gerador snowflake para o nó informado (0 a MaxSnowflakeNodeID)
+func NewSnowflakeGenerator(nodeID int64) (*SnowflakeGenerator, error) {
+	if nodeID < 0 || nodeID > MaxSnowflakeNodeID {
+		return nil, fmt.Errorf("nó snowflake inválido: %d (0 a %d)", nodeID, MaxSnowflakeNodeID)
+	}
+	return &SnowflakeGenerator{nodeID: nodeID, now: time.Now}, nil
+}
+
+// NewID retorna o próximo ID (int64). Se o relógio retroceder (ou a sequência se esgotar),
+// continua a partir do último instante usado, mantendo os IDs crescentes
+func (g *SnowflakeGenerator) NewID() (any, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	milli := g.now().Sub(SnowflakeEpoch).Milliseconds()
+	if milli < g.lastMilli {
+		milli = g.lastMilli
+	}
+	if milli == g.lastMilli {
+		g.sequence = (g.sequence + 1) & snowflakeMaxSequence
+		if g.sequence == 0 {
+			// Sequência esgotada no milissegundo: avança para o próximo
+			milli++
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastMilli = milli
+
+	return milli<<(snowflakeNodeBits+snowflakeSequenceBits) | g.nodeID<<snowflakeSequenceBits | g.sequence, nil
+}
+
+// =================================================================================================
+// ULID
+// =================================================================================================
+
+// crockfordBase32 é o alfabeto do ULID (sem I, L, O e U)
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// defaultULIDGenerator é compartilhado para manter a monotonicidade no processo
+var defaultULIDGenerator = &ulidGenerator{now: time.Now}
+
+// ulidGenerator gera ULIDs (26 caracteres, 48 bits de milissegundos + 80 bits aleatórios).
+// No mesmo milissegundo, a parte aleatória é incrementada, mantendo a ordem de criação
+type ulidGenerator struct {
+	mu        sync.Mutex
+	lastMilli uint64
+	entropy   [10]byte
+	now       func() time.Time
+}
+
+// NewID retorna o próximo ULID (string)
+func (g *ulidGenerator) NewID() (any, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	milli := uint64(g.now().UnixMilli())
+	if milli <= g.lastMilli {
+		milli = g.lastMilli
+		if !incrementBytes(g.entropy[:]) {
+			return nil, fmt.Errorf("entropia do ULID esgotada no milissegundo")
+		}
+	} else {
+		if _, err := rand.Read(g.entropy[:]); err != nil {
+			return nil, err
+		}
+		g.lastMilli = milli
+	}
+
+	var id [16]byte
+	id[0] = byte(milli >> 40)
+	id[1] = byte(milli >> 32)
+	id[2] = byte(milli >> 24)
+	id[3] = byte(milli >> 16)
+	id[4] = byte(milli >> 8)
+	id[5] = byte(milli)
+	copy(id[6:], g.entropy[:])
+	return encodeULID(id), nil
+}
+
+// incrementBytes soma 1 ao número big-endian; retorna false em overflow
+func incrementBytes(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID codifica os 128 bits em 26 caracteres base32 Crockford
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordBase32[lo&0x1F]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// =================================================================================================
+// KSUID
+// =================================================================================================
+
+// ksuidEpoch é o instante zero dos KSUIDs (13/05/2014 16:53:20 UTC)
+const ksuidEpoch = 1400000000
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// newKSUID gera um KSUID (27 caracteres base62, 32 bits de segundos + 128 bits aleatórios),
+// ordenável por segundo de criação
+func newKSUID() (any, error) {
+	var id [20]byte
+	binary.BigEndian.PutUint32(id[:4], uint32(time.Now().Unix()-ksuidEpoch))
+	if _, err := rand.Read(id[4:]); err != nil {
+		return nil, err
+	}
+	return encodeKSUID(id), nil
+}
+
+// encodeKSUID codifica os 160 bits em 27 caracteres base62, com zeros à esquerda
+func encodeKSUID(id [20]byte) string {
+	encoded := new(big.Int).SetBytes(id[:]).Text(62)
+	// big.Int usa 0-9a-zA-Z; o KSUID usa 0-9A-Za-z
+	out := make([]byte, len(encoded))
+	for i := 0; i < len(encoded); i++ {
+		c := encoded[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			out[i] = base62Alphabet[10+c-'a']
+		case c >= 'A' && c <= 'Z':
+			out[i] = base62Alphabet[36+c-'A']
+		default:
+			out[i] = c
+		}
+	}
+	return strings.Repeat("0", 27-len(out)) + string(out)
+}
@@ -0,0 +1,187 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnowflakeGenerator(t *testing.T) {
+	_, err := NewSnowflakeGenerator(MaxSnowflakeNodeID + 1)
+	assert.ErrorContains(t, err, "nó snowflake inválido")
+
+	generator, err := NewSnowflakeGenerator(7)
+	require.NoError(t, err)
+	clock := SnowflakeEpoch.Add(time.Hour)
+	generator.now = func() time.Time { return clock }
+
+	var last int64
+	for i := 0; i < snowflakeMaxSequence+10; i++ {
+		id, err := generator.NewID()
+		require.NoError(t, err)
+		value := id.(int64)
+		require.Greater(t, value, last)
+		assert.Equal(t, int64(7), value>>snowflakeSequenceBits&MaxSnowflakeNodeID)
+		last = value
+	}
+
+	// Relógio retrocedendo: IDs continuam crescentes
+	clock = clock.Add(-time.Minute)
+	id, err := generator.NewID()
+	require.NoError(t, err)
+	assert.Greater(t, id.(int64), last)
+}
+
+func TestULIDGenerator(t *testing.T) {
+	assert.Equal(t, "00000000000000000000000000", encodeULID([16]byte{}))
+	var max [16]byte
+	for i := range max {
+		max[i] = 0xFF
+	}
+	assert.Equal(t, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", encodeULID(max))
+
+	clock := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	generator := &ulidGenerator{now: func() time.Time { return clock }}
+	var last string
+	for i := 0; i < 100; i++ {
+		id, err := generator.NewID()
+		require.NoError(t, err)
+		value := id.(string)
+		require.Len(t, value, 26)
+		require.Greater(t, value, last, "ULIDs do mesmo milissegundo devem ser crescentes")
+		last = value
+	}
+
+	clock = clock.Add(time.Millisecond)
+	id, err := generator.NewID()
+	require.NoError(t, err)
+	assert.Greater(t, id.(string), last)
+}
+
+func TestKSUIDGenerator(t *testing.T) {
+	assert.Equal(t, strings.Repeat("0", 27), encodeKSUID([20]byte{}))
+	var max [20]byte
+	for i := range max {
+		max[i] = 0xFF
+	}
+	assert.Equal(t, "aWgEPTl1tmebfsQzFP4bxwgy80V", encodeKSUID(max))
+
+	id, err := newKSUID()
+	require.NoError(t, err)
+	assert.Len(t, id.(string), 27)
+}
+
+func TestServer_IDGeneratorRegistry(t *testing.T) {
+	server := &Server{config: DefaultServerConfig()}
+
+	for _, name := range []string{"snowflake", "ULID", "ksuid", "guid", "uuid32", "uuid38", "smartGuid"} {
+		_, ok := server.idGenerator(name)
+		assert.True(t, ok, name)
+	}
+	for _, name := range []string{"", "sequence", "identity", "auto", "none"} {
+		_, ok := server.idGenerator(name)
+		assert.False(t, ok, name)
+	}
+
+	RegisterIDGenerator("Tenant-Code", IDGeneratorFunc(func() (any, error) { return "T-1", nil }))
+	defer RegisterIDGenerator("Tenant-Code", nil)
+
+	generator, ok := LookupIDGenerator("tenant-code")
+	require.True(t, ok)
+	id, err := generator.NewID()
+	require.NoError(t, err)
+	assert.Equal(t, "T-1", id)
+
+	metadata := EntityMetadata{Name: "Tenants", Properties: []PropertyMetadata{
+		{Name: "code", Type: "string", IsKey: true, IDGenerator: "tenant-code"},
+	}}
+	data := map[string]any{}
+	generated, err := server.generateKeyValues(metadata, data)
+	require.NoError(t, err)
+	assert.True(t, generated)
+	assert.Equal(t, "T-1", data["code"])
+
+	// Valor informado pelo cliente é mantido
+	data = map[string]any{"code": "T-9"}
+	generated, err = server.generateKeyValues(metadata, data)
+	require.NoError(t, err)
+	assert.False(t, generated)
+	assert.Equal(t, "T-9", data["code"])
+
+	// Gerador texto em chave numérica
+	metadata.Properties[0].Type = "int64"
+	metadata.Properties[0].IDGenerator = "ulid"
+	_, err = server.generateKeyValues(metadata, map[string]any{})
+	assert.ErrorContains(t, err, "não é numérico")
+
+	// Nó snowflake inválido
+	invalid := &Server{config: DefaultServerConfig()}
+	invalid.SetSnowflakeNodeID(2048)
+	metadata.Properties[0].IDGenerator = "snowflake"
+	_, err = invalid.generateKeyValues(metadata, map[string]any{})
+	assert.ErrorContains(t, err, "nó snowflake inválido")
+}
+
+func TestBaseEntityService_Create_GeneratedKeys(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE orders (id BIGINT PRIMARY KEY, customer TEXT)")
+	require.NoError(t, err)
+	_, err = db.Exec("CREATE TABLE events (id TEXT PRIMARY KEY, name TEXT)")
+	require.NoError(t, err)
+
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+	provider := NewMySQLProvider(db)
+	server := &Server{
+		provider:     provider,
+		entities:     make(map[string]EntityService),
+		logger:       logger,
+		config:       DefaultServerConfig(),
+		entityAuth:   make(map[string]EntityAuthConfig),
+		eventManager: NewEntityEventManager(logger),
+	}
+	server.SetSnowflakeNodeID(3)
+
+	orders := NewBaseEntityService(provider, EntityMetadata{
+		Name: "Orders", TableName: "orders", Keys: []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true, IDGenerator: "snowflake"},
+			{Name: "customer", ColumnName: "customer", Type: "string"},
+		},
+	}, server)
+	events := NewBaseEntityService(provider, EntityMetadata{
+		Name: "Events", TableName: "events", Keys: []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "string", IsKey: true, IDGenerator: "ulid"},
+			{Name: "name", ColumnName: "name", Type: "string"},
+		},
+	}, server)
+
+	ctx := context.Background()
+	first, err := orders.Create(ctx, map[string]any{"customer": "ACME"})
+	require.NoError(t, err)
+	second, err := orders.Create(ctx, map[string]any{"customer": "Globex"})
+	require.NoError(t, err)
+
+	firstID := toInt64(first.(*OrderedEntity).ToMap()["id"])
+	secondID := toInt64(second.(*OrderedEntity).ToMap()["id"])
+	assert.Greater(t, secondID, firstID)
+	assert.Equal(t, int64(3), firstID>>snowflakeSequenceBits&MaxSnowflakeNodeID)
+	assert.Equal(t, "Globex", second.(*OrderedEntity).ToMap()["customer"])
+
+	created, err := events.Create(ctx, map[string]any{"name": "login"})
+	require.NoError(t, err)
+	event := created.(*OrderedEntity).ToMap()
+	assert.Len(t, event["id"], 26)
+	assert.Equal(t, "login", event["name"])
+}
@@ -0,0 +1,167 @@
+package odata
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// IEEE754COMPATIBLE: EDM.INT64 E EDM.DECIMAL COMO STRINGS JSON
+// =======================================================================================
+
+// ieee754ContentType é o Content-Type das respostas com Edm.Int64/Edm.Decimal em strings
+const ieee754ContentType = "application/json;IEEE754Compatible=true"
+
+// hasIEEE754Compatible verifica se o media type (Accept, Content-Type ou $format) contém o
+// parâmetro IEEE754Compatible=true
+func hasIEEE754Compatible(mediaType string) bool {
+	for _, param := range strings.Split(mediaType, ";") {
+		name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if found && strings.EqualFold(strings.TrimSpace(name), "IEEE754Compatible") {
+			return strings.EqualFold(strings.TrimSpace(value), "true")
+		}
+	}
+	return false
+}
+
+// isIEEE754Compatible verifica se a resposta deve serializar Edm.Int64 e Edm.Decimal como
+// strings: habilitado no servidor ou pedido pelo cliente no Accept ou no $format
+func (s *Server) isIEEE754Compatible(c fiber.Ctx) bool {
+	if s.config != nil && s.config.IEEE754Compatible {
+		return true
+	}
+	return hasIEEE754Compatible(c.Get(fiber.HeaderAccept)) || hasIEEE754Compatible(c.Query("$format"))
+}
+
+// writeEntityJSON envia a resposta de entidades, aplicando as máscaras de propriedades
+// sensíveis e o nível de odata.metadata antes do serializador (SetResponseSerializer)
+func (s *Server) writeEntityJSON(c fiber.Ctx, service EntityService, body interface{}) error {
+	s.applyMaskingPolicies(GetCurrentUser(c), service, body)
+	level, explicit := s.metadataLevel(c)
+	s.applyMetadataLevel(c, level, service, body)
+	if explicit {
+		defer setMetadataContentType(c, level)
+	}
+
+	ctx := &SerializationContext{
+		Ctx:           c,
+		Service:       service,
+		MetadataLevel: level,
+		IEEE754:       s.isIEEE754Compatible(c),
+	}
+	return s.getResponseSerializer().Serialize(ctx, body)
+}
+
+// serializeJSON é o serializador padrão: o encoder pré-compilado (FastJSONEncoding) ou o
+// encoding/json, com Edm.Int64/Edm.Decimal em strings quando IEEE754Compatible
+func (s *Server) serializeJSON(ctx *SerializationContext, body interface{}) error {
+	c := ctx.Ctx
+	if s.config != nil && s.config.FastJSONEncoding && ctx.Service != nil {
+		if handled, err := s.writeFastJSON(c, ctx.Service.GetMetadata(), body, ctx.IEEE754); handled {
+			return err
+		}
+	}
+	if !ctx.IEEE754 {
+		return c.JSON(body)
+	}
+	return c.JSON(ieee754Value(body), ieee754ContentType)
+}
+
+// ieee754Response é a ODataResponse com o @odata.count serializado como string
+type ieee754Response struct {
+	Context  string      `json:"@odata.context,omitempty"`
+	Count    *string     `json:"@odata.count,omitempty"`
+	NextLink string      `json:"@odata.nextLink,omitempty"`
+	Value    interface{} `json:"value"`
+	Error    *ODataError `json:"error,omitempty"`
+
+	Annotations []OrderedProperty `json:"-"`
+}
+
+// MarshalJSON serializa a resposta com as anotações do envelope, como ODataResponse
+func (r *ieee754Response) MarshalJSON() ([]byte, error) {
+	type plainResponse ieee754Response
+	if len(r.Annotations) == 0 {
+		return json.Marshal((*plainResponse)(r))
+	}
+	var count interface{}
+	if r.Count != nil {
+		count = *r.Count
+	}
+	return marshalResponseEnvelope(r.Context, count, r.Annotations, r.NextLink, r.Value, r.Error)
+}
+
+// ieee754Value converte os valores int64 e Decimal da resposta (inclusive entidades
+// expandidas) para strings. Entidades são alteradas no lugar, pois pertencem à requisição
+func ieee754Value(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case Decimal:
+		if !v.Valid {
+			return nil
+		}
+		return v.String()
+	case *ODataResponse:
+		if v == nil {
+			return v
+		}
+		response := &ieee754Response{Context: v.Context, NextLink: v.NextLink, Value: ieee754Value(v.Value), Error: v.Error, Annotations: v.Annotations}
+		if v.Count != nil {
+			count := strconv.FormatInt(*v.Count, 10)
+			response.Count = &count
+		}
+		return response
+	case *OrderedEntity:
+		if v != nil {
+			for _, prop := range v.Properties {
+				v.Set(prop.Name, ieee754Value(prop.Value))
+			}
+		}
+		return v
+	case *OrderedEntityResponse:
+		if v != nil {
+			for i := range v.Fields {
+				v.Fields[i].Value = ieee754Value(v.Fields[i].Value)
+			}
+		}
+		return v
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = ieee754Value(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = ieee754Value(item)
+		}
+		return v
+	case []*OrderedEntity:
+		for _, item := range v {
+			ieee754Value(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// normalizeInt64Values converte strings numéricas das propriedades Edm.Int64 do payload
+// (enviadas por clientes IEEE754Compatible) para int64
+func (s *BaseEntityService) normalizeInt64Values(metadata EntityMetadata, data map[string]any) {
+	for _, prop := range metadata.Properties {
+		if prop.Type != "int64" {
+			continue
+		}
+		if value, ok := data[prop.Name].(string); ok {
+			if parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+				data[prop.Name] = parsed
+			}
+		}
+	}
+}
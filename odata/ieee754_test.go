@@ -0,0 +1,105 @@
+package odata
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasIEEE754Compatible(t *testing.T) {
+	assert.True(t, hasIEEE754Compatible("application/json;odata.metadata=minimal;IEEE754Compatible=true"))
+	assert.True(t, hasIEEE754Compatible("application/json; ieee754compatible=TRUE"))
+	assert.False(t, hasIEEE754Compatible("application/json;IEEE754Compatible=false"))
+	assert.False(t, hasIEEE754Compatible("application/json"))
+	assert.False(t, hasIEEE754Compatible(""))
+}
+
+func TestIEEE754Value(t *testing.T) {
+	count := int64(9007199254740993)
+	entity := NewOrderedEntity()
+	entity.Set("id", int64(9007199254740993))
+	entity.Set("quantity", int32(5))
+	entity.Set("total", Decimal{Val: "10.50", Valid: true})
+	entity.Set("Items", []interface{}{map[string]interface{}{"id": int64(7)}})
+
+	converted := ieee754Value(&ODataResponse{Count: &count, Value: []interface{}{entity}}).(*ieee754Response)
+
+	require.NotNil(t, converted.Count)
+	assert.Equal(t, "9007199254740993", *converted.Count)
+
+	id, _ := entity.Get("id")
+	quantity, _ := entity.Get("quantity")
+	total, _ := entity.Get("total")
+	items, _ := entity.Get("Items")
+	assert.Equal(t, "9007199254740993", id)
+	assert.Equal(t, int32(5), quantity, "Edm.Int32 continua número")
+	assert.Equal(t, "10.50", total)
+	assert.Equal(t, "7", items.([]interface{})[0].(map[string]interface{})["id"])
+}
+
+func TestServer_IEEE754Compatible(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	get := func(t *testing.T, path, accept string) (string, string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body), resp.Header.Get("Content-Type")
+	}
+
+	t.Run("Sem o parâmetro mantém números", func(t *testing.T) {
+		body, contentType := get(t, "/odata/Products?$filter="+url.QueryEscape("id eq 1"), "")
+		assert.Contains(t, body, `"id":1`)
+		assert.NotContains(t, contentType, "IEEE754Compatible")
+	})
+
+	t.Run("Accept com IEEE754Compatible=true", func(t *testing.T) {
+		body, contentType := get(t, "/odata/Products?$count=true&$filter="+url.QueryEscape("id eq 1"), "application/json;IEEE754Compatible=true")
+		assert.Contains(t, body, `"id":"1"`)
+		assert.Contains(t, body, `"@odata.count":"1"`)
+		assert.Contains(t, contentType, "IEEE754Compatible=true")
+	})
+
+	t.Run("$format com IEEE754Compatible=true", func(t *testing.T) {
+		body, _ := get(t, "/odata/Products(2)?$format="+url.QueryEscape("application/json;IEEE754Compatible=true"), "")
+		assert.Contains(t, body, `"id":"2"`)
+	})
+
+	t.Run("Habilitado no servidor", func(t *testing.T) {
+		server.SetIEEE754Compatible(true)
+		defer server.SetIEEE754Compatible(false)
+
+		body, _ := get(t, "/odata/Products?$filter="+url.QueryEscape("id eq 3"), "")
+		assert.Contains(t, body, `"id":"3"`)
+	})
+
+	t.Run("Aceita Edm.Int64 como string no payload", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/odata/Products", strings.NewReader(`{"id": "9007199254740993", "name": "Cabo", "price": 5}`))
+		req.Header.Set("Content-Type", "application/json;IEEE754Compatible=true")
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var id int64
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT id FROM products WHERE name = 'Cabo'").Scan(&id))
+		assert.Equal(t, int64(9007199254740993), id)
+	})
+}
@@ -0,0 +1,68 @@
+package odata
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// PERSONIFICAÇÃO (ON-BEHALF-OF)
+// =======================================================================================
+
+// ImpersonatorContextKey é a chave do usuário original (administrador) no contexto durante a
+// personificação
+const ImpersonatorContextKey = "impersonated_by"
+
+// Impersonate troca o usuário efetivo (e, opcionalmente, o tenant) pelo restante da requisição,
+// para que ferramentas de suporte reproduzam o comportamento visto por um usuário específico
+// (filtros por usuário, interceptors, permissões). Apenas administradores podem personificar;
+// cada troca é registrada no log e o administrador aparece em GetImpersonator e na auditoria HTTP
+func Impersonate(c fiber.Ctx, target *UserIdentity, tenantID ...string) error {
+	if target == nil || target.Username == "" {
+		return fmt.Errorf("impersonation target user is required")
+	}
+
+	// Em personificações encadeadas, o administrador original é mantido
+	admin := GetImpersonator(c)
+	if admin == nil {
+		admin = GetCurrentUser(c)
+	}
+	if admin == nil || !admin.Admin {
+		return fmt.Errorf("impersonation requires an authenticated admin user")
+	}
+
+	tenant := GetCurrentTenant(c)
+	server := getServerFromContext(c)
+	if len(tenantID) > 0 && tenantID[0] != "" {
+		tenant = tenantID[0]
+		// Sem o tenant no pool, as consultas cairiam silenciosamente no provider padrão
+		if server != nil && server.multiTenantPool != nil && !containsString(server.multiTenantPool.GetTenantList(), tenant) {
+			return fmt.Errorf("impersonation tenant '%s' not found", tenant)
+		}
+		c.Locals(TenantContextKey, tenant)
+	}
+
+	c.Locals(ImpersonatorContextKey, admin)
+	c.Locals(UserContextKey, target)
+
+	if server != nil {
+		server.logger.Printf("[IMPERSONATION] %s atuando como %s (tenant %s) em %s %s",
+			admin.Username, target.Username, tenant, c.Method(), c.Path())
+	}
+	return nil
+}
+
+// GetImpersonator retorna o administrador que está personificando o usuário atual (nil fora
+// de uma personificação)
+func GetImpersonator(c fiber.Ctx) *UserIdentity {
+	if user, ok := c.Locals(ImpersonatorContextKey).(*UserIdentity); ok {
+		return user
+	}
+	return nil
+}
+
+// IsImpersonating verifica se a requisição está sendo executada em nome de outro usuário
+func IsImpersonating(c fiber.Ctx) bool {
+	return GetImpersonator(c) != nil
+}
@@ -0,0 +1,95 @@
+package odata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonate(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
+
+	var entries []HTTPAuditEntry
+	server.SetHTTPAudit(&HTTPAuditConfig{
+		Enabled: true,
+		Sink: HTTPAuditSinkFunc(func(entry HTTPAuditEntry) error {
+			entries = append(entries, entry)
+			return nil
+		}),
+	})
+	server.router.Use(server.HTTPAuditMiddleware())
+	server.router.Use(func(c fiber.Ctx) error {
+		c.Locals("odata_server", server)
+		if user := c.Get("X-Test-User"); user != "" {
+			c.Locals(UserContextKey, &UserIdentity{Username: user, Admin: c.Get("X-Test-Admin") == "true"})
+		}
+		return c.Next()
+	})
+
+	server.router.Get("/support/:user", func(c fiber.Ctx) error {
+		if err := Impersonate(c, &UserIdentity{Username: c.Params("user"), Roles: []string{"sales"}}); err != nil {
+			return server.writeODataError(c, fiber.StatusForbidden, NewODataError("Forbidden", err.Error()), nil)
+		}
+		require.NotNil(t, GetObjectManager(c), "o ObjectManager segue disponível em nome do usuário")
+		return c.JSON(fiber.Map{
+			"user":          GetCurrentUser(c).Username,
+			"sales":         HasRole(c, "sales"),
+			"admin":         IsAdmin(c),
+			"impersonating": IsImpersonating(c),
+		})
+	})
+
+	do := func(headers ...string) *http.Response {
+		entries = nil
+		req := httptest.NewRequest(http.MethodGet, "/support/maria", nil)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Administrador assume o usuário", func(t *testing.T) {
+		resp := do("X-Test-User", "ana", "X-Test-Admin", "true")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "maria", body["user"])
+		assert.Equal(t, true, body["sales"])
+		assert.Equal(t, false, body["admin"], "as permissões passam a ser as do usuário personificado")
+		assert.Equal(t, true, body["impersonating"])
+
+		require.Len(t, entries, 1)
+		assert.Equal(t, "maria", entries[0].User)
+		assert.Equal(t, "ana", entries[0].Impersonator)
+	})
+
+	t.Run("Apenas administradores", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, do("X-Test-User", "joao").StatusCode)
+		assert.Equal(t, http.StatusForbidden, do().StatusCode)
+	})
+
+	t.Run("Tenant inexistente", func(t *testing.T) {
+		server.multiTenantPool = &MultiTenantProviderPool{providers: map[string]DatabaseProvider{}, config: &MultiTenantConfig{DefaultTenant: "default"}}
+		defer func() { server.multiTenantPool = nil }()
+
+		var err error
+		app := fiber.New()
+		app.Get("/", func(c fiber.Ctx) error {
+			c.Locals("odata_server", server)
+			c.Locals(UserContextKey, &UserIdentity{Username: "ana", Admin: true})
+			err = Impersonate(c, &UserIdentity{Username: "maria"}, "acme")
+			return nil
+		})
+		_, testErr := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+		require.NoError(t, testErr)
+		assert.ErrorContains(t, err, "tenant 'acme' not found")
+	})
+}
@@ -0,0 +1,562 @@
+package odata
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// IMPORTAÇÃO DE ARQUIVOS ($import): CSV E XLSX
+// =======================================================================================
+
+// Limites padrão da importação
+const (
+	DefaultImportBatchSize = 500   // Linhas por transação
+	DefaultImportMaxRows   = 10000 // Linhas de dados por arquivo
+)
+
+// importSegment é o segmento da importação nas rotas (ex: POST /odata/Products/$import)
+const importSegment = "/$import"
+
+// importPreviewRows é a quantidade de linhas convertidas devolvidas na pré-visualização
+const importPreviewRows = 10
+
+// importSavepoint é o savepoint que isola cada linha dentro da transação do lote
+const importSavepoint = "import_row"
+
+// ImportConfig configura a importação de arquivos de uma entidade
+type ImportConfig struct {
+	BatchSize int               // Linhas por transação (padrão: DefaultImportBatchSize)
+	MaxRows   int               // Linhas de dados por arquivo (padrão: DefaultImportMaxRows)
+	Mapping   map[string]string // Cabeçalho do arquivo -> propriedade ("" ignora a coluna)
+}
+
+// ImportColumnMapping associa uma coluna do arquivo a uma propriedade da entidade
+type ImportColumnMapping struct {
+	Column   string `json:"column"`
+	Property string `json:"property"`
+}
+
+// ImportRowError descreve a falha de uma linha do arquivo (o cabeçalho é a linha 1)
+type ImportRowError struct {
+	Row      int    `json:"row"`
+	Column   string `json:"column,omitempty"`
+	Property string `json:"property,omitempty"`
+	Message  string `json:"message"`
+}
+
+// ImportPreview é a resposta da pré-visualização (preview=true): o mapeamento das colunas,
+// as primeiras linhas convertidas e os erros de validação, sem gravar
+type ImportPreview struct {
+	Columns  []ImportColumnMapping    `json:"columns"`
+	Unmapped []string                 `json:"unmapped,omitempty"`
+	Rows     int                      `json:"rows"`
+	Sample   []map[string]interface{} `json:"sample"`
+	Errors   []ImportRowError         `json:"errors,omitempty"`
+}
+
+// ImportResult é a resposta da importação
+type ImportResult struct {
+	Rows     int              `json:"rows"`
+	Inserted int              `json:"inserted"`
+	Failed   int              `json:"failed"`
+	Errors   []ImportRowError `json:"errors,omitempty"`
+}
+
+// importRow é uma linha do arquivo convertida para a entidade
+type importRow struct {
+	line int
+	data map[string]interface{}
+}
+
+// WithImport expõe POST /Entidade/$import, que recebe um arquivo CSV ou XLSX (multipart, campo
+// "file"), associa as colunas às propriedades pelo cabeçalho, valida as linhas e insere as
+// válidas em lotes transacionais, devolvendo os erros de cada linha rejeitada
+func WithImport(config ImportConfig) EntityOption {
+	return func(entityConfig *EntityConfig) {
+		entityConfig.Import = &config
+	}
+}
+
+// registerImport valida e guarda a configuração da importação da entidade
+func (s *Server) registerImport(entityName string, metadata EntityMetadata, config ImportConfig) error {
+	if config.BatchSize < 0 || config.MaxRows < 0 {
+		return fmt.Errorf("BatchSize e MaxRows do $import não podem ser negativos")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = DefaultImportBatchSize
+	}
+	if config.MaxRows == 0 {
+		config.MaxRows = DefaultImportMaxRows
+	}
+	for column, name := range config.Mapping {
+		if name != "" && importProperty(metadata, name) == nil {
+			return fmt.Errorf("propriedade '%s' do mapeamento da coluna '%s' do $import não encontrada", name, column)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.imports == nil {
+		s.imports = make(map[string]ImportConfig)
+	}
+	s.imports[entityName] = config
+	return nil
+}
+
+// getImport retorna a configuração da importação da entidade, se houver
+func (s *Server) getImport(entityName string) (ImportConfig, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	config, ok := s.imports[entityName]
+	return config, ok
+}
+
+// setupImportRoute registra a rota do $import com os middlewares da entidade
+func (s *Server) setupImportRoute(router fiber.Router, entityName, prefix, setName string, middlewares []any) {
+	config, ok := s.getImport(entityName)
+	if !ok {
+		return
+	}
+	chain := append(append([]any{}, middlewares...), s.handleImport(entityName, config))
+	router.Add([]string{fiber.MethodPost}, prefix+"/"+setName+importSegment, chain[0], chain[1:]...)
+}
+
+// handleImport processa o arquivo enviado. Campos do formulário: file (obrigatório; .xlsx ou
+// CSV), mapping (JSON cabeçalho -> propriedade, sobre o mapeamento da configuração),
+// delimiter (CSV; detectado pelo cabeçalho quando ausente) e preview=true
+func (s *Server) handleImport(entityName string, config ImportConfig) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		service, ok := s.GetEntityService(entityName).(*BaseEntityService)
+		if !ok {
+			s.writeError(c, fiber.StatusNotImplemented, "NotImplemented", "Entity service does not support $import")
+			return nil
+		}
+		metadata := service.GetMetadata()
+
+		if err := s.authorizeRequest(c, entityName, nil); err != nil {
+			s.writeEntityError(c, fiber.StatusForbidden, "Forbidden", err)
+			return nil
+		}
+
+		file, err := c.FormFile("file")
+		if err != nil {
+			s.writeError(c, fiber.StatusBadRequest, "InvalidRequest", "Multipart field 'file' is required")
+			return nil
+		}
+		mapping := make(map[string]string, len(config.Mapping))
+		for column, name := range config.Mapping {
+			mapping[column] = name
+		}
+		if raw := c.FormValue("mapping"); raw != "" {
+			var requested map[string]string
+			if err := json.Unmarshal([]byte(raw), &requested); err != nil {
+				s.writeError(c, fiber.StatusBadRequest, "InvalidRequest", "Invalid JSON in field 'mapping'")
+				return nil
+			}
+			for column, name := range requested {
+				mapping[column] = name
+			}
+		}
+
+		records, err := readImportFile(file, c.FormValue("delimiter"))
+		if err != nil {
+			s.writeError(c, fiber.StatusBadRequest, "InvalidFile", err.Error())
+			return nil
+		}
+		if len(records) == 0 {
+			s.writeError(c, fiber.StatusBadRequest, "InvalidFile", "File has no header row")
+			return nil
+		}
+		total := countImportRecords(records[1:])
+		if total > config.MaxRows {
+			s.writeError(c, fiber.StatusRequestEntityTooLarge, "TooManyRows",
+				fmt.Sprintf("$import accepts at most %d rows", config.MaxRows))
+			return nil
+		}
+
+		header := records[0]
+		columns, unmapped, err := resolveImportMapping(metadata, header, mapping)
+		if err != nil {
+			s.writeError(c, fiber.StatusBadRequest, "InvalidMapping", err.Error())
+			return nil
+		}
+		rows, rowErrors := convertImportRows(metadata, header, columns, records[1:])
+
+		if preview, _ := strconv.ParseBool(c.FormValue("preview")); preview {
+			result := &ImportPreview{
+				Columns:  columns,
+				Unmapped: unmapped,
+				Rows:     total,
+				Sample:   []map[string]interface{}{},
+				Errors:   rowErrors,
+			}
+			for i := 0; i < len(rows) && i < importPreviewRows; i++ {
+				result.Sample = append(result.Sample, rows[i].data)
+			}
+			return c.JSON(result)
+		}
+
+		// OnEntityInserting em cada linha válida: cancelamentos viram erros da linha
+		eventCtx := createEventContext(c, entityName)
+		accepted := rows[:0]
+		for _, row := range rows {
+			insertingArgs := NewEntityInsertingArgs(eventCtx, row.data)
+			if err := s.eventManager.Emit(insertingArgs); err != nil {
+				message := err.Error()
+				if insertingArgs.IsCanceled() {
+					message = insertingArgs.GetCancelReason()
+				}
+				rowErrors = append(rowErrors, ImportRowError{Row: row.line, Message: message})
+				continue
+			}
+			row.data = insertingArgs.Data
+			accepted = append(accepted, row)
+		}
+
+		ctx, cancel := s.requestContext(c, entityName)
+		defer cancel()
+
+		inserted, insertErrors, err := service.importRows(ctx, accepted, config.BatchSize)
+		if err != nil {
+			s.writeEntityError(c, fiber.StatusInternalServerError, "ImportError", s.queryContextError(ctx, entityName, err))
+			return nil
+		}
+		for _, row := range inserted {
+			insertedArgs := NewEntityInsertedArgs(eventCtx, row.data)
+			if err := s.eventManager.Emit(insertedArgs); err != nil {
+				s.logger.Printf("❌ Erro no evento OnEntityInserted: %v", err)
+			}
+		}
+
+		rowErrors = append(rowErrors, insertErrors...)
+		sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Row < rowErrors[j].Row })
+		failed := make(map[int]bool, len(rowErrors))
+		for _, rowError := range rowErrors {
+			failed[rowError.Row] = true
+		}
+		return c.JSON(&ImportResult{
+			Rows:     total,
+			Inserted: len(inserted),
+			Failed:   len(failed),
+			Errors:   rowErrors,
+		})
+	}
+}
+
+// importRows insere as linhas em transações de até batchSize linhas. Cada linha é isolada por
+// um savepoint: a falha de uma linha é reportada sem descartar as demais do lote. Se o commit
+// do lote falhar, todas as suas linhas são reportadas
+func (s *BaseEntityService) importRows(ctx context.Context, rows []importRow, batchSize int) ([]importRow, []ImportRowError, error) {
+	var inserted []importRow
+	var rowErrors []ImportRowError
+
+	for start := 0; start < len(rows); start += batchSize {
+		batch := rows[start:min(start+batchSize, len(rows))]
+
+		tx, err := beginSessionTx(ctx, s.provider, nil)
+		if err != nil {
+			return inserted, rowErrors, fmt.Errorf("failed to begin transaction: %w", err)
+		}
+
+		var batchInserted []importRow
+		for _, row := range batch {
+			sp, err := newSavepoint(ctx, s.provider, tx.Tx, importSavepoint)
+			if err != nil {
+				tx.Rollback()
+				return inserted, rowErrors, err
+			}
+			if _, err := executeInsertInTx(ctx, tx.Tx, s, row.data); err != nil {
+				if rollbackErr := sp.rollback(ctx); rollbackErr != nil {
+					tx.Rollback()
+					return inserted, rowErrors, rollbackErr
+				}
+				rowErrors = append(rowErrors, ImportRowError{Row: row.line, Message: err.Error()})
+				continue
+			}
+			if err := sp.releaseSavepoint(ctx); err != nil {
+				tx.Rollback()
+				return inserted, rowErrors, err
+			}
+			batchInserted = append(batchInserted, row)
+		}
+
+		if err := tx.Commit(); err != nil {
+			for _, row := range batchInserted {
+				rowErrors = append(rowErrors, ImportRowError{
+					Row:     row.line,
+					Message: fmt.Sprintf("failed to commit transaction: %v", err),
+				})
+			}
+			continue
+		}
+		inserted = append(inserted, batchInserted...)
+	}
+	return inserted, rowErrors, nil
+}
+
+// readImportFile lê as linhas do arquivo enviado: XLSX pela extensão, CSV nos demais casos
+func readImportFile(header *multipart.FileHeader, delimiter string) ([][]string, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(header.Filename), ".xlsx") {
+		return readXLSXRows(file, header.Size)
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	return readCSVRows(content, delimiter)
+}
+
+// readCSVRows lê o CSV (UTF-8, com ou sem BOM), com o registro de cada linha na posição
+// correspondente do resultado. Sem delimitador informado, usa ';' quando o
+// cabeçalho tem mais ';' do que ',' (padrão do Excel em locales com vírgula decimal)
+func readCSVRows(content []byte, delimiter string) ([][]string, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(content) {
+		return nil, fmt.Errorf("invalid csv file: content is not UTF-8")
+	}
+
+	comma := ','
+	switch {
+	case delimiter == "\\t" || delimiter == "tab":
+		comma = '\t'
+	case delimiter != "":
+		r, size := utf8.DecodeRuneInString(delimiter)
+		if size != len(delimiter) {
+			return nil, fmt.Errorf("invalid csv delimiter %q", delimiter)
+		}
+		comma = r
+	default:
+		firstLine, _, _ := bytes.Cut(content, []byte("\n"))
+		if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+			comma = ';'
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv file: %w", err)
+		}
+		// Linhas vazias (ignoradas pelo leitor) são mantidas para preservar a numeração
+		line, _ := reader.FieldPos(0)
+		for len(records) < line-1 {
+			records = append(records, nil)
+		}
+		records = append(records, record)
+	}
+}
+
+// resolveImportMapping associa as colunas do cabeçalho às propriedades: pelo mapeamento
+// informado ou, na ausência dele, pelo nome da propriedade ou da coluna (case-insensitive)
+func resolveImportMapping(metadata EntityMetadata, header []string, mapping map[string]string) ([]ImportColumnMapping, []string, error) {
+	for column, name := range mapping {
+		if name != "" && importProperty(metadata, name) == nil {
+			return nil, nil, fmt.Errorf("property '%s' mapped from column '%s' not found", name, column)
+		}
+	}
+
+	var columns []ImportColumnMapping
+	var unmapped []string
+	targets := make(map[string]string, len(header))
+	for _, column := range header {
+		column = strings.TrimSpace(column)
+		name, explicit := mapping[column]
+		if !explicit {
+			name = ""
+			if prop := importProperty(metadata, column); prop != nil {
+				name = prop.Name
+			}
+		}
+		if name == "" {
+			unmapped = append(unmapped, column)
+			continue
+		}
+
+		prop := importProperty(metadata, name)
+		if previous, ok := targets[prop.Name]; ok {
+			return nil, nil, fmt.Errorf("columns '%s' and '%s' are both mapped to property '%s'", previous, column, prop.Name)
+		}
+		targets[prop.Name] = column
+		columns = append(columns, ImportColumnMapping{Column: column, Property: prop.Name})
+	}
+	if len(columns) == 0 {
+		return nil, nil, fmt.Errorf("no column of the file is mapped to a property of entity '%s'", metadata.Name)
+	}
+	return columns, unmapped, nil
+}
+
+// importProperty localiza a propriedade gravável pelo nome ou pela coluna (case-insensitive).
+// Navegações e propriedades fora da lista de expostas não são importadas
+func importProperty(metadata EntityMetadata, name string) *PropertyMetadata {
+	for i, prop := range metadata.Properties {
+		if prop.IsNavigation || isHiddenProperty(metadata, prop.Name) {
+			continue
+		}
+		if strings.EqualFold(prop.Name, name) || (prop.ColumnName != "" && strings.EqualFold(prop.ColumnName, name)) {
+			return &metadata.Properties[i]
+		}
+	}
+	return nil
+}
+
+// convertImportRows converte as linhas do arquivo nas entidades, validando os tipos, o tamanho
+// máximo e as propriedades obrigatórias. Linhas em branco são ignoradas
+func convertImportRows(metadata EntityMetadata, header []string, columns []ImportColumnMapping, records [][]string) ([]importRow, []ImportRowError) {
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.TrimSpace(column)] = i
+	}
+
+	var rows []importRow
+	var rowErrors []ImportRowError
+	for i, record := range records {
+		line := i + 2
+		if isBlankImportRecord(record) {
+			continue
+		}
+
+		data := make(map[string]interface{}, len(columns))
+		valid := true
+		for _, column := range columns {
+			prop := findPropertyByName(metadata, column.Property)
+			raw := ""
+			if position := index[column.Column]; position < len(record) {
+				raw = strings.TrimSpace(record[position])
+			}
+
+			value, err := convertImportValue(*prop, raw)
+			if err == nil && value == nil && hasPropFlag(prop.PropFlags, "Required") {
+				err = fmt.Errorf("value is required")
+			}
+			if err != nil {
+				rowErrors = append(rowErrors, ImportRowError{Row: line, Column: column.Column, Property: prop.Name, Message: err.Error()})
+				valid = false
+				continue
+			}
+			data[prop.Name] = value
+		}
+		if valid {
+			rows = append(rows, importRow{line: line, data: data})
+		}
+	}
+	return rows, rowErrors
+}
+
+// countImportRecords conta as linhas de dados que não estão em branco
+func countImportRecords(records [][]string) int {
+	count := 0
+	for _, record := range records {
+		if !isBlankImportRecord(record) {
+			count++
+		}
+	}
+	return count
+}
+
+// isBlankImportRecord verifica se todas as células da linha estão vazias
+func isBlankImportRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// excelEpoch é a data base dos números seriais de data do Excel
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// excelMaxSerial é o número serial seguinte a 31/12/9999, última data válida no Excel
+const excelMaxSerial = 2958466
+
+// importTimeLayouts são os formatos de data aceitos nas células de texto
+var importTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// convertImportValue converte o texto da célula para o tipo da propriedade (célula vazia = nil)
+func convertImportValue(prop PropertyMetadata, raw string) (interface{}, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if isDecimalProperty(prop) {
+		value, err := normalizeDecimal(raw)
+		if err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	switch prop.Type {
+	case "int64", "int32", "int":
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			// Células numéricas do XLSX podem chegar como "42.0"
+			number, floatErr := strconv.ParseFloat(raw, 64)
+			if floatErr != nil || number != math.Trunc(number) {
+				return nil, fmt.Errorf("invalid integer value: %s", raw)
+			}
+			value = int64(number)
+		}
+		return value, nil
+	case "float64", "double", "float32", "single":
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number value: %s", raw)
+		}
+		return value, nil
+	case "bool", "boolean":
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean value: %s", raw)
+		}
+		return value, nil
+	case "time.Time":
+		for _, layout := range importTimeLayouts {
+			if value, err := time.Parse(layout, raw); err == nil {
+				return value, nil
+			}
+		}
+		if serial, err := strconv.ParseFloat(raw, 64); err == nil && serial > 0 && serial < excelMaxSerial {
+			days := math.Floor(serial)
+			seconds := math.Round((serial - days) * 24 * 60 * 60)
+			return excelEpoch.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second), nil
+		}
+		return nil, fmt.Errorf("invalid date value: %s", raw)
+	case "string":
+		if prop.MaxLength > 0 && utf8.RuneCountInString(raw) > prop.MaxLength {
+			return nil, fmt.Errorf("value exceeds max length %d", prop.MaxLength)
+		}
+		return raw, nil
+	default:
+		return nil, fmt.Errorf("type %s is not supported by $import", prop.Type)
+	}
+}
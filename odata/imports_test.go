@@ -0,0 +1,139 @@
+package odata
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Import(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	orders := server.entities["Orders"].GetMetadata()
+	require.NoError(t, server.registerImport("Orders", orders, ImportConfig{BatchSize: 2, MaxRows: 5}))
+
+	app := fiber.New()
+	server.router = app
+	server.setupImportRoute(server.router, "Orders", "/odata", "Orders", nil)
+
+	upload := func(filename string, content []byte, fields map[string]string) (int, string) {
+		t.Helper()
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", filename)
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+		for name, value := range fields {
+			require.NoError(t, form.WriteField(name, value))
+		}
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/odata/Orders/$import", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+	count := func() int {
+		var n int
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT COUNT(*) FROM orders").Scan(&n))
+		return n
+	}
+
+	csvContent := []byte("\xef\xbb\xbfID;Customer_ID;Status;Obs\n10;1;new;x\n11;abc;new;\n\n12;2;open;\n")
+
+	t.Run("Pré-visualização do mapeamento", func(t *testing.T) {
+		status, body := upload("orders.csv", csvContent, map[string]string{"preview": "true"})
+		require.Equal(t, http.StatusOK, status, body)
+
+		var preview ImportPreview
+		require.NoError(t, json.Unmarshal([]byte(body), &preview))
+		assert.Equal(t, []ImportColumnMapping{
+			{Column: "ID", Property: "id"},
+			{Column: "Customer_ID", Property: "customer_id"},
+			{Column: "Status", Property: "status"},
+		}, preview.Columns)
+		assert.Equal(t, []string{"Obs"}, preview.Unmapped)
+		assert.Equal(t, 3, preview.Rows)
+		assert.Len(t, preview.Sample, 2)
+		assert.Equal(t, []ImportRowError{{Row: 3, Column: "Customer_ID", Property: "customer_id", Message: "invalid integer value: abc"}}, preview.Errors)
+		assert.Equal(t, 2, count(), "a pré-visualização não grava")
+	})
+
+	t.Run("Importa em lotes com erros por linha", func(t *testing.T) {
+		content := append(csvContent, []byte("1;1;duplicated;\n")...)
+		status, body := upload("orders.csv", content, nil)
+		require.Equal(t, http.StatusOK, status, body)
+
+		var result ImportResult
+		require.NoError(t, json.Unmarshal([]byte(body), &result))
+		assert.Equal(t, 4, result.Rows)
+		assert.Equal(t, 2, result.Inserted)
+		assert.Equal(t, 2, result.Failed)
+		require.Len(t, result.Errors, 2)
+		assert.Equal(t, 3, result.Errors[0].Row)
+		assert.Equal(t, 6, result.Errors[1].Row, "a chave duplicada é desfeita pelo savepoint sem afetar o lote")
+		assert.Equal(t, 4, count())
+	})
+
+	t.Run("XLSX com mapeamento explícito", func(t *testing.T) {
+		var file bytes.Buffer
+		writer := &xlsxReportWriter{}
+		require.NoError(t, writer.begin(&file, "Pedidos", []ReportColumn{{Title: "Código"}, {Title: "Situação"}}))
+		require.NoError(t, writer.writeRow([]any{20, "imported"}))
+		require.NoError(t, writer.writeRow([]any{21, "imported & checked"}))
+		require.NoError(t, writer.end())
+
+		status, body := upload("pedidos.xlsx", file.Bytes(), map[string]string{
+			"mapping": `{"Código": "id", "Situação": "status"}`,
+		})
+		require.Equal(t, http.StatusOK, status, body)
+
+		var result ImportResult
+		require.NoError(t, json.Unmarshal([]byte(body), &result))
+		assert.Equal(t, 2, result.Inserted)
+
+		var orderStatus string
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT status FROM orders WHERE id = 21").Scan(&orderStatus))
+		assert.Equal(t, "imported & checked", orderStatus)
+	})
+
+	t.Run("Arquivo inválido", func(t *testing.T) {
+		status, _ := upload("orders.csv", []byte("id\n1\n2\n3\n4\n5\n6\n"), nil)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+
+		status, _ = upload("orders.csv", []byte("id,status\n1,x\n"), map[string]string{"mapping": `{"status": "missing"}`})
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = upload("orders.xlsx", []byte("not a zip"), nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestConvertImportValue(t *testing.T) {
+	value, err := convertImportValue(PropertyMetadata{Type: "time.Time"}, "45292.5")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), value)
+
+	value, err = convertImportValue(PropertyMetadata{Type: "int64"}, "42.0")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), value)
+
+	value, err = convertImportValue(PropertyMetadata{Type: "decimal"}, "1.5e2")
+	require.NoError(t, err)
+	assert.Equal(t, "150", value)
+
+	_, err = convertImportValue(PropertyMetadata{Type: "string", MaxLength: 3}, "abcd")
+	assert.EqualError(t, err, "value exceeds max length 3")
+}
@@ -0,0 +1,203 @@
+package odata
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ==================================================
+// LEITURA DE XLSX (SpreadsheetML)
+// ==================================================
+
+// xlsxMaxPartSize limita o tamanho descompactado de cada parte lida do pacote (zip bomb)
+const xlsxMaxPartSize = 256 << 20
+
+// xlsxRichText é um texto do XLSX: simples (<t>) ou formatado em trechos (<r><t>)
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String retorna o texto completo
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+// xlsxWorksheet é a planilha lida de xl/worksheets/sheetN.xml
+type xlsxWorksheet struct {
+	Rows []struct {
+		Ref   int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string        `xml:"r,attr"`
+			Type   string        `xml:"t,attr"`
+			Value  string        `xml:"v"`
+			Inline *xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSXRows lê as linhas da primeira planilha do XLSX, com as células vazias preenchidas
+// com "" e cada linha na posição do seu número. Datas chegam como o número serial do Excel
+func readXLSXRows(r io.ReaderAt, size int64) ([][]string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx file: %w", err)
+	}
+	parts := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		parts[strings.TrimPrefix(file.Name, "/")] = file
+	}
+
+	sheetPath, err := xlsxFirstSheetPath(parts)
+	if err != nil {
+		return nil, err
+	}
+	var sharedStrings []string
+	if part, ok := parts["xl/sharedStrings.xml"]; ok {
+		var sst struct {
+			Items []xlsxRichText `xml:"si"`
+		}
+		if err := decodeXLSXPart(part, &sst); err != nil {
+			return nil, err
+		}
+		sharedStrings = make([]string, len(sst.Items))
+		for i, item := range sst.Items {
+			sharedStrings[i] = item.String()
+		}
+	}
+
+	part, ok := parts[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("invalid xlsx file: worksheet %s not found", sheetPath)
+	}
+	var sheet xlsxWorksheet
+	if err := decodeXLSXPart(part, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		var values []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				if column, err = xlsxColumnIndex(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(values) <= column {
+				values = append(values, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(sharedStrings) {
+					return nil, fmt.Errorf("invalid xlsx file: shared string %q in %s", cell.Value, cell.Ref)
+				}
+				values[column] = sharedStrings[index]
+			case "inlineStr":
+				if cell.Inline != nil {
+					values[column] = cell.Inline.String()
+				}
+			default:
+				values[column] = cell.Value
+			}
+		}
+		// Linhas omitidas do XML (vazias) são mantidas para preservar a numeração
+		for row.Ref > 0 && len(rows) < row.Ref-1 {
+			rows = append(rows, nil)
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// xlsxFirstSheetPath resolve o caminho da primeira planilha do workbook
+func xlsxFirstSheetPath(parts map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	workbookPart, ok := parts["xl/workbook.xml"]
+	relsPart, hasRels := parts["xl/_rels/workbook.xml.rels"]
+	if !ok || !hasRels {
+		return fallback, nil
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeXLSXPart(workbookPart, &workbook); err != nil {
+		return "", err
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeXLSXPart(relsPart, &rels); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("invalid xlsx file: workbook has no sheets")
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+// decodeXLSXPart decodifica uma parte XML do pacote
+func decodeXLSXPart(file *zip.File, v any) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("invalid xlsx file: %w", err)
+	}
+	defer reader.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(reader, xlsxMaxPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("invalid xlsx file: %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// xlsxColumnIndex converte a referência da célula no índice da coluna (A1 = 0, AA7 = 26),
+// inverso de xlsxColumnName
+func xlsxColumnIndex(ref string) (int, error) {
+	index := 0
+	letters := 0
+	for _, r := range strings.ToUpper(ref) {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A') + 1
+		letters++
+	}
+	if letters == 0 || letters > 3 {
+		return 0, fmt.Errorf("invalid xlsx file: cell reference %q", ref)
+	}
+	return index - 1, nil
+}
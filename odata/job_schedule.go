@@ -0,0 +1,200 @@
+package odata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =======================================================================================
+// EXPRESSÕES DE AGENDAMENTO (CRON)
+// =======================================================================================
+
+// JobSchedule calcula os próximos disparos de um job
+type JobSchedule interface {
+	Next(after time.Time) time.Time
+}
+
+// cronSchedule é uma expressão cron de 5 campos: minuto, hora, dia do mês, mês e dia da
+// semana. Cada campo é um bitset dos valores aceitos
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// everySchedule dispara em intervalos fixos (@every 5m)
+type everySchedule struct {
+	interval time.Duration
+}
+
+// cronField define os limites de um campo da expressão
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// cronMacros são os atalhos aceitos no lugar dos 5 campos
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseJobSchedule interpreta uma expressão cron de 5 campos ("0 3 * * *"), um atalho
+// (@daily, @hourly, ...) ou um intervalo fixo ("@every 15m"). Os campos aceitam "*",
+// listas (1,15), intervalos (1-5) e passos (*/10, 0-30/5); no dia da semana, 0 e 7 são domingo
+func ParseJobSchedule(spec string) (JobSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if interval, ok := strings.CutPrefix(spec, "@every "); ok {
+		duration, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid schedule interval '%s'", interval)
+		}
+		return everySchedule{interval: duration}, nil
+	}
+	if expanded, ok := cronMacros[spec]; ok {
+		spec = expanded
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("invalid schedule '%s': expected 5 fields (minute hour day month weekday)", spec)
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		value, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule '%s': %w", spec, err)
+		}
+		bits[i] = value
+	}
+
+	// Domingo pode ser 0 ou 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &cronSchedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+// parseCronField converte um campo (lista de itens) no bitset dos valores aceitos
+func parseCronField(value string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed <= 0 {
+				return 0, fmt.Errorf("invalid step '%s' in %s", stepPart, field.name)
+			}
+			step = parsed
+		}
+
+		start, end := field.min, field.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = cronValue(from, field); err != nil {
+				return 0, err
+			}
+			if end, err = cronValue(to, field); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range '%s' in %s", rangePart, field.name)
+			}
+		default:
+			parsed, err := cronValue(rangePart, field)
+			if err != nil {
+				return 0, err
+			}
+			start = parsed
+			if !hasStep {
+				end = parsed
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// cronValue converte um valor numérico do campo, validando os limites
+func cronValue(value string, field cronField) (int, error) {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < field.min || parsed > field.max {
+		return 0, fmt.Errorf("invalid %s '%s' (%d-%d)", field.name, value, field.min, field.max)
+	}
+	return parsed, nil
+}
+
+// Next retorna o próximo disparo após o instante informado (no fuso horário de after).
+// Retorna o instante zero se não houver disparo nos próximos 5 anos (ex: 31 de fevereiro)
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchDay aplica a regra do cron: com dia do mês e dia da semana restritos, basta um
+// deles coincidir
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next retorna o instante após o intervalo
+func (s everySchedule) Next(after time.Time) time.Time {
+	return after.Add(s.interval)
+}
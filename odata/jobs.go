@@ -0,0 +1,290 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// =======================================================================================
+// JOBS AGENDADOS
+// =======================================================================================
+
+// DefaultJobLockTTL é a validade padrão do lock distribuído de um job
+const DefaultJobLockTTL = 30 * time.Minute
+
+// JobHandler é executado a cada disparo de um job agendado
+type JobHandler func(ctx *JobContext) error
+
+// JobLocker garante que cada disparo de um job rode em apenas uma instância da aplicação
+// (Redis, advisory lock do banco, etc). O ttl limita o lock caso a instância caia durante a
+// execução; unlock é chamado ao fim do job
+type JobLocker interface {
+	TryLock(ctx context.Context, name string, ttl time.Duration) (unlock func(), acquired bool, err error)
+}
+
+// JobOption configura um job agendado
+type JobOption func(*scheduledJob)
+
+// WithJobName define o nome do job (logs e lock distribuído). Padrão: a expressão de
+// agendamento
+func WithJobName(name string) JobOption {
+	return func(job *scheduledJob) {
+		job.name = name
+	}
+}
+
+// WithJobLockTTL define a validade do lock distribuído do job (padrão: DefaultJobLockTTL)
+func WithJobLockTTL(ttl time.Duration) JobOption {
+	return func(job *scheduledJob) {
+		job.lockTTL = ttl
+	}
+}
+
+// JobContext é o contexto de execução de um job: cancelado no shutdown do servidor, com
+// acesso ao provider, ao ObjectManager e aos tenants
+type JobContext struct {
+	context.Context
+	Name        string    // Nome do job
+	ScheduledAt time.Time // Instante agendado do disparo
+
+	server   *Server
+	tenantID string
+	manager  *ObjectManager
+}
+
+// GetTenantID retorna o tenant da execução (vazio fora de ForEachTenant)
+func (c *JobContext) GetTenantID() string {
+	return c.tenantID
+}
+
+// GetProvider retorna o provider do tenant da execução (ou o provider padrão)
+func (c *JobContext) GetProvider() DatabaseProvider {
+	if c.tenantID != "" && c.server.multiTenantPool != nil {
+		return c.server.multiTenantPool.GetProvider(c.tenantID)
+	}
+	return c.server.provider
+}
+
+// GetManager retorna o ObjectManager da execução, criado no primeiro uso
+func (c *JobContext) GetManager() *ObjectManager {
+	if c.manager == nil {
+		c.manager = NewObjectManager(c.GetProvider(), c)
+	}
+	return c.manager
+}
+
+// ForEachTenant executa fn para cada tenant do pool multi-tenant (em sequência, por
+// padrão), com provider e ObjectManager próprios. Veja JobScheduler.ForEachTenant
+func (c *JobContext) ForEachTenant(fn func(ctx *JobContext) error, opts ...TenantJobOption) error {
+	_, err := c.server.Jobs().ForEachTenant(c, fn, opts...)
+	return err
+}
+
+// scheduledJob é um job registrado no scheduler
+type scheduledJob struct {
+	name     string
+	spec     string
+	schedule JobSchedule
+	handler  JobHandler
+	lockTTL  time.Duration
+	running  atomic.Bool
+}
+
+// JobScheduler executa os jobs agendados do servidor. Os jobs iniciam com o servidor
+// (Start) e são interrompidos no shutdown, que aguarda as execuções em andamento
+type JobScheduler struct {
+	server *Server
+	locker JobLocker
+
+	mu      sync.Mutex
+	jobs    []*scheduledJob
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+}
+
+// Jobs retorna o scheduler de jobs do servidor
+func (s *Server) Jobs() *JobScheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jobs == nil {
+		s.jobs = &JobScheduler{server: s}
+	}
+	return s.jobs
+}
+
+// Schedule agenda um job (ex: "0 3 * * *" para todo dia às 3h, no fuso horário do
+// servidor). Veja ParseJobSchedule para a sintaxe
+func (s *Server) Schedule(spec string, handler JobHandler, opts ...JobOption) error {
+	return s.Jobs().Schedule(spec, handler, opts...)
+}
+
+// SetJobLocker define o lock distribuído dos jobs, para que cada disparo rode em apenas uma
+// instância. Sem locker, apenas execuções sobrepostas na mesma instância são evitadas
+func (s *Server) SetJobLocker(locker JobLocker) *Server {
+	jobs := s.Jobs()
+	jobs.mu.Lock()
+	jobs.locker = locker
+	jobs.mu.Unlock()
+	return s
+}
+
+// Schedule agenda um job. Se o scheduler já estiver em execução, o job passa a rodar
+// imediatamente
+func (j *JobScheduler) Schedule(spec string, handler JobHandler, opts ...JobOption) error {
+	if handler == nil {
+		return fmt.Errorf("job handler is required")
+	}
+	schedule, err := ParseJobSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	job := &scheduledJob{name: spec, spec: spec, schedule: schedule, handler: handler, lockTTL: DefaultJobLockTTL}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, existing := range j.jobs {
+		if existing.name == job.name {
+			return fmt.Errorf("job '%s' already scheduled", job.name)
+		}
+	}
+	j.jobs = append(j.jobs, job)
+	if j.ctx != nil {
+		j.startJob(job)
+	}
+	return nil
+}
+
+// Start inicia os jobs agendados. Chamado automaticamente na inicialização do servidor
+func (j *JobScheduler) Start() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.ctx != nil {
+		return
+	}
+
+	j.ctx, j.cancel = context.WithCancel(context.Background())
+	for _, job := range j.jobs {
+		j.startJob(job)
+	}
+	if len(j.jobs) > 0 {
+		j.server.logger.Printf("⏰ %d jobs agendados iniciados", len(j.jobs))
+	}
+}
+
+// Stop cancela os jobs e aguarda as execuções em andamento até o fim de ctx
+func (j *JobScheduler) Stop(ctx context.Context) error {
+	j.mu.Lock()
+	if j.ctx == nil {
+		j.mu.Unlock()
+		return nil
+	}
+	j.cancel()
+	j.ctx, j.cancel = nil, nil
+	j.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		j.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("jobs still running at shutdown: %w", ctx.Err())
+	}
+}
+
+// RunNow executa o job imediatamente (fora do agendamento), respeitando o lock
+func (j *JobScheduler) RunNow(ctx context.Context, name string) error {
+	j.mu.Lock()
+	var job *scheduledJob
+	for _, candidate := range j.jobs {
+		if candidate.name == name {
+			job = candidate
+		}
+	}
+	j.mu.Unlock()
+	if job == nil {
+		return fmt.Errorf("job '%s' not found", name)
+	}
+	return j.runJob(ctx, job, time.Now())
+}
+
+// startJob inicia o loop de agendamento do job (chamado com j.mu travado)
+func (j *JobScheduler) startJob(job *scheduledJob) {
+	ctx := j.ctx
+	j.running.Add(1)
+	go func() {
+		defer j.running.Done()
+		for {
+			next := job.schedule.Next(time.Now().In(j.server.timeZone()))
+			if next.IsZero() {
+				j.server.logger.Printf("⚠️ Job %s sem próximos disparos para '%s'", job.name, job.spec)
+				return
+			}
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			if err := j.runJob(ctx, job, next); err != nil {
+				j.server.logger.Printf("❌ Job %s falhou: %v", job.name, err)
+			}
+		}
+	}()
+}
+
+// runJob executa o handler com o lock do job. Disparos com a execução anterior ainda em
+// andamento ou com o lock em outra instância são ignorados
+func (j *JobScheduler) runJob(ctx context.Context, job *scheduledJob, scheduledAt time.Time) (err error) {
+	if !job.running.CompareAndSwap(false, true) {
+		j.server.logger.Printf("⏭️ Job %s ignorado: execução anterior em andamento", job.name)
+		return nil
+	}
+	defer job.running.Store(false)
+
+	j.mu.Lock()
+	locker := j.locker
+	j.mu.Unlock()
+	if locker != nil {
+		unlock, acquired, err := locker.TryLock(ctx, job.name, job.lockTTL)
+		if err != nil {
+			return fmt.Errorf("lock: %w", err)
+		}
+		if !acquired {
+			j.server.logger.Printf("⏭️ Job %s ignorado: em execução em outra instância", job.name)
+			return nil
+		}
+		if unlock != nil {
+			defer unlock()
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	start := time.Now()
+	jobCtx := &JobContext{Context: ctx, Name: job.name, ScheduledAt: scheduledAt, server: j.server}
+	if err := job.handler(jobCtx); err != nil {
+		return err
+	}
+	j.server.logger.Printf("✅ Job %s concluído em %v", job.name, time.Since(start))
+	return nil
+}
@@ -0,0 +1,180 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// =======================================================================================
+// JOBS POR TENANT
+// =======================================================================================
+
+// TenantJobOption configura a execução de ForEachTenant
+type TenantJobOption func(*tenantJobConfig)
+
+// tenantJobConfig contém as opções de ForEachTenant
+type tenantJobConfig struct {
+	concurrency int
+	timeout     time.Duration
+	tenants     []string
+}
+
+// WithTenantConcurrency limita quantos tenants são processados em paralelo (padrão: 1)
+func WithTenantConcurrency(concurrency int) TenantJobOption {
+	return func(config *tenantJobConfig) {
+		config.concurrency = concurrency
+	}
+}
+
+// WithTenantTimeout limita o tempo de execução de cada tenant
+func WithTenantTimeout(timeout time.Duration) TenantJobOption {
+	return func(config *tenantJobConfig) {
+		config.timeout = timeout
+	}
+}
+
+// WithTenants restringe a execução aos tenants informados (padrão: todos os tenants do pool)
+func WithTenants(tenants ...string) TenantJobOption {
+	return func(config *tenantJobConfig) {
+		config.tenants = tenants
+	}
+}
+
+// TenantJobResult é o resultado da execução em um tenant
+type TenantJobResult struct {
+	TenantID string
+	Err      error
+	Duration time.Duration
+}
+
+// TenantJobReport reúne os resultados de ForEachTenant, na ordem dos tenants
+type TenantJobReport struct {
+	Results []TenantJobResult
+}
+
+// Failed retorna os resultados com erro
+func (r *TenantJobReport) Failed() []TenantJobResult {
+	var failed []TenantJobResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err retorna os erros dos tenants juntos, identificados pelo tenant (nil se todos tiveram
+// sucesso)
+func (r *TenantJobReport) Err() error {
+	var errs []error
+	for _, result := range r.Failed() {
+		errs = append(errs, fmt.Errorf("tenant %s: %w", result.TenantID, result.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// ForEachTenant executa fn para cada tenant do pool multi-tenant, com provider e
+// ObjectManager próprios por tenant. Erros e panics de um tenant não afetam os demais e
+// são reportados por tenant; o cancelamento de ctx interrompe os tenants ainda não
+// iniciados. Sem multi-tenant, fn é executada uma vez com o provider padrão. O erro
+// retornado é o de TenantJobReport.Err
+func (j *JobScheduler) ForEachTenant(ctx context.Context, fn func(ctx *JobContext) error, opts ...TenantJobOption) (*TenantJobReport, error) {
+	config := tenantJobConfig{concurrency: 1}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.concurrency < 1 {
+		config.concurrency = 1
+	}
+
+	parent := &JobContext{Context: ctx, server: j.server}
+	if jobCtx, ok := ctx.(*JobContext); ok {
+		parent.Name, parent.ScheduledAt = jobCtx.Name, jobCtx.ScheduledAt
+	}
+
+	tenants, err := j.tenantList(config.tenants)
+	if err != nil {
+		return &TenantJobReport{}, err
+	}
+	report := &TenantJobReport{Results: make([]TenantJobResult, len(tenants))}
+
+	semaphore := make(chan struct{}, config.concurrency)
+	var wg sync.WaitGroup
+	for i, tenant := range tenants {
+		report.Results[i].TenantID = tenant
+		select {
+		case <-ctx.Done():
+			report.Results[i].Err = ctx.Err()
+			continue
+		case semaphore <- struct{}{}:
+		}
+		// Com a vaga liberada e o contexto cancelado ao mesmo tempo, o select pode escolher a vaga
+		if err := ctx.Err(); err != nil {
+			<-semaphore
+			report.Results[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *TenantJobResult) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			start := time.Now()
+			result.Err = j.runTenant(parent, result.TenantID, config.timeout, fn)
+			result.Duration = time.Since(start)
+			if result.Err != nil {
+				j.server.logger.Printf("❌ Job %s falhou no tenant %s: %v", parent.Name, result.TenantID, result.Err)
+			}
+		}(&report.Results[i])
+	}
+	wg.Wait()
+
+	return report, report.Err()
+}
+
+// tenantList retorna os tenants da execução, em ordem. Sem multi-tenant, retorna um único
+// tenant vazio (provider padrão)
+func (j *JobScheduler) tenantList(selected []string) ([]string, error) {
+	if j.server.multiTenantPool == nil {
+		if len(selected) > 0 {
+			return nil, fmt.Errorf("multi-tenant is not enabled")
+		}
+		return []string{""}, nil
+	}
+
+	available := j.server.multiTenantPool.GetTenantList()
+	if len(selected) == 0 {
+		sort.Strings(available)
+		return available, nil
+	}
+	for _, tenant := range selected {
+		if !containsString(available, tenant) {
+			return nil, fmt.Errorf("tenant '%s' not found", tenant)
+		}
+	}
+	return selected, nil
+}
+
+// runTenant executa fn no tenant com contexto próprio (timeout) e recuperação de panic
+func (j *JobScheduler) runTenant(parent *JobContext, tenant string, timeout time.Duration, fn func(ctx *JobContext) error) (err error) {
+	ctx := parent.Context
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	return fn(&JobContext{Context: ctx, Name: parent.Name, ScheduledAt: parent.ScheduledAt, server: j.server, tenantID: tenant})
+}
@@ -0,0 +1,139 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTenantJobsTestServer(t *testing.T, tenants ...string) *Server {
+	t.Helper()
+
+	server := newBatchGetTestServer(t)
+	pool := &MultiTenantProviderPool{
+		providers: make(map[string]DatabaseProvider),
+		config:    &MultiTenantConfig{DefaultTenant: tenants[0]},
+		logger:    log.New(os.Stdout, "[TEST] ", log.LstdFlags),
+	}
+	for _, tenant := range tenants {
+		db, err := sql.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		pool.providers[tenant] = NewMySQLProvider(db)
+	}
+	server.multiTenantPool = pool
+	return server
+}
+
+func TestJobScheduler_ForEachTenant(t *testing.T) {
+	t.Run("Cada tenant com provider próprio e erros por tenant", func(t *testing.T) {
+		server := newTenantJobsTestServer(t, "acme", "globex", "initech")
+
+		var mu sync.Mutex
+		providers := make(map[string]DatabaseProvider)
+		report, err := server.Jobs().ForEachTenant(context.Background(), func(ctx *JobContext) error {
+			mu.Lock()
+			providers[ctx.GetTenantID()] = ctx.GetManager().provider
+			mu.Unlock()
+
+			switch ctx.GetTenantID() {
+			case "globex":
+				return errors.New("quota excedida")
+			case "initech":
+				panic("boom")
+			}
+			return nil
+		})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "tenant globex: quota excedida")
+		assert.ErrorContains(t, err, "tenant initech: panic: boom")
+		require.Len(t, report.Results, 3)
+		assert.Equal(t, "acme", report.Results[0].TenantID)
+		assert.NoError(t, report.Results[0].Err)
+		assert.Len(t, report.Failed(), 2)
+
+		for tenant, provider := range providers {
+			assert.Same(t, server.multiTenantPool.providers[tenant], provider, tenant)
+		}
+	})
+
+	t.Run("Limite de concorrência", func(t *testing.T) {
+		server := newTenantJobsTestServer(t, "t1", "t2", "t3", "t4", "t5", "t6")
+
+		var active, peak atomic.Int32
+		report, err := server.Jobs().ForEachTenant(context.Background(), func(ctx *JobContext) error {
+			current := active.Add(1)
+			defer active.Add(-1)
+			for {
+				old := peak.Load()
+				if current <= old || peak.CompareAndSwap(old, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}, WithTenantConcurrency(2))
+
+		require.NoError(t, err)
+		assert.Len(t, report.Results, 6)
+		assert.Equal(t, int32(2), peak.Load())
+	})
+
+	t.Run("Timeout por tenant e seleção de tenants", func(t *testing.T) {
+		server := newTenantJobsTestServer(t, "acme", "globex")
+
+		report, err := server.Jobs().ForEachTenant(context.Background(), func(ctx *JobContext) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, WithTenants("globex"), WithTenantTimeout(10*time.Millisecond))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Len(t, report.Results, 1)
+		assert.Equal(t, "globex", report.Results[0].TenantID)
+
+		_, err = server.Jobs().ForEachTenant(context.Background(), func(ctx *JobContext) error { return nil }, WithTenants("umbrella"))
+		assert.ErrorContains(t, err, "tenant 'umbrella' not found")
+	})
+
+	t.Run("Cancelamento interrompe os tenants restantes", func(t *testing.T) {
+		server := newTenantJobsTestServer(t, "a", "b", "c")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls atomic.Int32
+		report, err := server.Jobs().ForEachTenant(ctx, func(ctx *JobContext) error {
+			calls.Add(1)
+			cancel()
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Len(t, report.Failed(), 2)
+	})
+
+	t.Run("Job agendado itera os tenants", func(t *testing.T) {
+		server := newTenantJobsTestServer(t, "acme", "globex")
+
+		var mu sync.Mutex
+		var seen []string
+		require.NoError(t, server.Schedule("@daily", func(ctx *JobContext) error {
+			return ctx.ForEachTenant(func(tenant *JobContext) error {
+				assert.Equal(t, "nightly", tenant.Name)
+				mu.Lock()
+				seen = append(seen, tenant.GetTenantID())
+				mu.Unlock()
+				return nil
+			}, WithTenantConcurrency(2))
+		}, WithJobName("nightly")))
+		require.NoError(t, server.Jobs().RunNow(context.Background(), "nightly"))
+		assert.ElementsMatch(t, []string{"acme", "globex"}, seen)
+	})
+}
@@ -0,0 +1,140 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJobSchedule(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", value)
+		require.NoError(t, err)
+		return parsed
+	}
+	next := func(spec, after string) string {
+		schedule, err := ParseJobSchedule(spec)
+		require.NoError(t, err, spec)
+		return schedule.Next(at(after)).Format("2006-01-02 15:04")
+	}
+
+	assert.Equal(t, "2024-05-11 03:00", next("0 3 * * *", "2024-05-10 03:00"))
+	assert.Equal(t, "2024-05-10 03:00", next("0 3 * * *", "2024-05-10 02:59"))
+	assert.Equal(t, "2024-05-10 10:15", next("*/15 * * * *", "2024-05-10 10:01"))
+	assert.Equal(t, "2024-05-13 08:00", next("0 8 * * 1-5", "2024-05-10 09:00"), "sexta depois do horário vai para segunda")
+	assert.Equal(t, "2024-05-12 00:00", next("0 0 * * 7", "2024-05-10 09:00"), "7 é domingo")
+	assert.Equal(t, "2024-06-01 00:00", next("@monthly", "2024-05-10 09:00"))
+	assert.Equal(t, "2024-05-13 00:00", next("0 0 15 * 1", "2024-05-10 09:00"), "dia 15 ou segunda-feira")
+	assert.Equal(t, "2024-05-15 00:00", next("0 0 15 * 1", "2024-05-13 09:00"))
+	assert.Equal(t, "2024-05-10 09:05", next("@every 5m", "2024-05-10 09:00"))
+	assert.Equal(t, "2028-02-29 12:00", next("0 12 29 2 *", "2024-03-01 00:00"))
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "5-1 * * * *", "*/0 * * * *", "@every x"} {
+		_, err := ParseJobSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+type denyLocker struct {
+	calls atomic.Int32
+}
+
+func (l *denyLocker) TryLock(ctx context.Context, name string, ttl time.Duration) (func(), bool, error) {
+	l.calls.Add(1)
+	return nil, false, nil
+}
+
+func TestJobScheduler(t *testing.T) {
+	t.Run("Executa no agendamento e para no Stop", func(t *testing.T) {
+		server := newBatchGetTestServer(t)
+		var runs atomic.Int32
+		require.NoError(t, server.Schedule("@every 10ms", func(ctx *JobContext) error {
+			assert.NotNil(t, ctx.GetProvider())
+			assert.NotNil(t, ctx.GetManager())
+			runs.Add(1)
+			return nil
+		}, WithJobName("aggregate")))
+		assert.Error(t, server.Schedule("@every 1h", func(ctx *JobContext) error { return nil }, WithJobName("aggregate")))
+		assert.Error(t, server.Schedule("0 25 * * *", func(ctx *JobContext) error { return nil }))
+
+		server.Jobs().Start()
+		assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		require.NoError(t, server.Jobs().Stop(context.Background()))
+
+		stopped := runs.Load()
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, stopped, runs.Load())
+	})
+
+	t.Run("Lock distribuído", func(t *testing.T) {
+		server := newBatchGetTestServer(t)
+		locker := &denyLocker{}
+		server.SetJobLocker(locker)
+
+		ran := false
+		require.NoError(t, server.Schedule("0 3 * * *", func(ctx *JobContext) error {
+			ran = true
+			return nil
+		}, WithJobName("nightly")))
+		require.NoError(t, server.Jobs().RunNow(context.Background(), "nightly"))
+		assert.False(t, ran, "outra instância detém o lock")
+		assert.Equal(t, int32(1), locker.calls.Load())
+	})
+
+	t.Run("Execuções sobrepostas são ignoradas", func(t *testing.T) {
+		server := newBatchGetTestServer(t)
+		release := make(chan struct{})
+		var runs atomic.Int32
+		require.NoError(t, server.Schedule("@daily", func(ctx *JobContext) error {
+			runs.Add(1)
+			<-release
+			return nil
+		}, WithJobName("slow")))
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.Jobs().RunNow(context.Background(), "slow")
+		}()
+		assert.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, time.Millisecond)
+		require.NoError(t, server.Jobs().RunNow(context.Background(), "slow"))
+		close(release)
+		wg.Wait()
+		assert.Equal(t, int32(1), runs.Load())
+	})
+
+	t.Run("Erros e panics são retornados", func(t *testing.T) {
+		server := newBatchGetTestServer(t)
+		require.NoError(t, server.Schedule("@hourly", func(ctx *JobContext) error {
+			return errors.New("falha")
+		}, WithJobName("failing")))
+		require.NoError(t, server.Schedule("@hourly", func(ctx *JobContext) error {
+			panic("boom")
+		}, WithJobName("panicking")))
+
+		assert.EqualError(t, server.Jobs().RunNow(context.Background(), "failing"), "falha")
+		assert.ErrorContains(t, server.Jobs().RunNow(context.Background(), "panicking"), "panic: boom")
+		assert.Error(t, server.Jobs().RunNow(context.Background(), "missing"))
+	})
+
+	t.Run("ForEachTenant sem multi-tenant usa o provider padrão", func(t *testing.T) {
+		server := newBatchGetTestServer(t)
+		var tenants []string
+		require.NoError(t, server.Schedule("@hourly", func(ctx *JobContext) error {
+			return ctx.ForEachTenant(func(tenantCtx *JobContext) error {
+				tenants = append(tenants, tenantCtx.GetTenantID())
+				assert.Equal(t, server.provider, tenantCtx.GetProvider())
+				return nil
+			})
+		}, WithJobName("tenants")))
+		require.NoError(t, server.Jobs().RunNow(context.Background(), "tenants"))
+		assert.Equal(t, []string{""}, tenants)
+	})
+}
@@ -0,0 +1,501 @@
+package odata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// SERIALIZAÇÃO JSON RÁPIDA DAS RESPOSTAS DE ENTIDADES
+// =======================================================================================
+
+// O serializador rápido (ServerConfig.FastJSONEncoding) escreve as respostas de entidades
+// direto em um buffer reaproveitado, usando encoders pré-compilados por entidade (chaves já
+// escapadas e conversão escolhida pelo tipo da propriedade). A saída é idêntica à do
+// encoding/json; valores de tipos não tratados aqui usam json.Marshal
+
+// jsonBufferPool reaproveita os buffers de serialização entre as requisições
+var jsonBufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 16*1024)
+		return &buf
+	},
+}
+
+// maxPooledJSONBuffer evita manter no pool buffers de respostas muito grandes
+const maxPooledJSONBuffer = 4 * 1024 * 1024
+
+// jsonValueEncoder serializa o valor de uma propriedade
+type jsonValueEncoder func(buf []byte, value any, ieee754 bool) ([]byte, error)
+
+// jsonFieldEncoder é o encoder pré-compilado de uma propriedade
+type jsonFieldEncoder struct {
+	key     []byte // "Nome":
+	linkKey []byte // "Nome@odata.navigationLink":
+	encode  jsonValueEncoder
+	// Entidade relacionada (navegações expandidas)
+	relatedType string
+}
+
+// entityJSONEncoder agrupa os encoders das propriedades de uma entidade
+type entityJSONEncoder struct {
+	fields map[string]*jsonFieldEncoder
+}
+
+// entityJSONEncoder retorna o encoder da entidade, compilado no primeiro uso
+func (s *Server) entityJSONEncoder(metadata EntityMetadata) *entityJSONEncoder {
+	if cached, ok := s.jsonEncoders.Load(metadata.Name); ok {
+		return cached.(*entityJSONEncoder)
+	}
+	encoder, _ := s.jsonEncoders.LoadOrStore(metadata.Name, compileEntityJSONEncoder(metadata))
+	return encoder.(*entityJSONEncoder)
+}
+
+// relatedJSONEncoder retorna o encoder da entidade de uma navegação expandida
+func (s *Server) relatedJSONEncoder(relatedType string) *entityJSONEncoder {
+	if relatedType == "" {
+		return nil
+	}
+	service := s.relatedEntityService(relatedType)
+	if service == nil {
+		return nil
+	}
+	return s.entityJSONEncoder(service.GetMetadata())
+}
+
+// compileEntityJSONEncoder pré-calcula as chaves e escolhe a conversão de cada propriedade
+func compileEntityJSONEncoder(metadata EntityMetadata) *entityJSONEncoder {
+	encoder := &entityJSONEncoder{fields: make(map[string]*jsonFieldEncoder, len(metadata.Properties))}
+	for _, prop := range metadata.Properties {
+		field := &jsonFieldEncoder{
+			key:     append(appendJSONString(nil, prop.Name), ':'),
+			linkKey: append(appendJSONString(nil, prop.Name+"@odata.navigationLink"), ':'),
+			encode:  appendJSONValue,
+		}
+		if prop.IsNavigation {
+			field.relatedType = prop.RelatedType
+		} else {
+			field.encode = jsonEncoderForType(prop)
+		}
+		encoder.fields[prop.Name] = field
+	}
+	return encoder
+}
+
+// jsonEncoderForType escolhe a serialização pelo tipo da propriedade. Valores de outro tipo
+// (ex: fallback da conversão no scan) seguem para appendJSONValue
+func jsonEncoderForType(prop PropertyMetadata) jsonValueEncoder {
+	if isDecimalProperty(prop) {
+		return appendJSONValue
+	}
+	switch prop.Type {
+	case "string":
+		return func(buf []byte, value any, ieee754 bool) ([]byte, error) {
+			if v, ok := value.(string); ok {
+				return appendJSONString(buf, v), nil
+			}
+			return appendJSONValue(buf, value, ieee754)
+		}
+	case "int64":
+		return func(buf []byte, value any, ieee754 bool) ([]byte, error) {
+			if v, ok := value.(int64); ok {
+				if ieee754 {
+					buf = append(buf, '"')
+					buf = strconv.AppendInt(buf, v, 10)
+					return append(buf, '"'), nil
+				}
+				return strconv.AppendInt(buf, v, 10), nil
+			}
+			return appendJSONValue(buf, value, ieee754)
+		}
+	case "float64", "double":
+		return func(buf []byte, value any, ieee754 bool) ([]byte, error) {
+			if v, ok := value.(float64); ok {
+				return appendJSONFloat(buf, v, 64)
+			}
+			return appendJSONValue(buf, value, ieee754)
+		}
+	case "bool", "boolean":
+		return func(buf []byte, value any, ieee754 bool) ([]byte, error) {
+			if v, ok := value.(bool); ok {
+				return strconv.AppendBool(buf, v), nil
+			}
+			return appendJSONValue(buf, value, ieee754)
+		}
+	default:
+		return appendJSONValue
+	}
+}
+
+// writeFastJSON serializa a resposta com os encoders pré-compilados. Retorna false quando o
+// tipo da resposta não é tratado pelo serializador rápido
+func (s *Server) writeFastJSON(c fiber.Ctx, metadata EntityMetadata, body interface{}, ieee754 bool) (bool, error) {
+	switch body.(type) {
+	case *ODataResponse, *OrderedEntity:
+	default:
+		return false, nil
+	}
+
+	bufPtr := jsonBufferPool.Get().(*[]byte)
+	buf, err := s.appendJSONResponse((*bufPtr)[:0], s.entityJSONEncoder(metadata), body, ieee754)
+	if err == nil {
+		// SetBody copia para o buffer da resposta, permitindo devolver o buffer ao pool
+		c.Response().SetBody(buf)
+		contentType := fiber.MIMEApplicationJSONCharsetUTF8
+		if ieee754 {
+			contentType = ieee754ContentType
+		}
+		c.Response().Header.SetContentType(contentType)
+	}
+	if cap(buf) <= maxPooledJSONBuffer {
+		*bufPtr = buf
+		jsonBufferPool.Put(bufPtr)
+	}
+	return true, err
+}
+
+// appendJSONResponse serializa uma ODataResponse ou entidade
+func (s *Server) appendJSONResponse(buf []byte, encoder *entityJSONEncoder, body interface{}, ieee754 bool) ([]byte, error) {
+	response, ok := body.(*ODataResponse)
+	if !ok {
+		return s.appendJSONEntity(buf, encoder, body.(*OrderedEntity), ieee754)
+	}
+	if response == nil {
+		return append(buf, "null"...), nil
+	}
+
+	var err error
+	buf = append(buf, '{')
+	if response.Context != "" {
+		buf = append(buf, `"@odata.context":`...)
+		buf = appendJSONString(buf, response.Context)
+		buf = append(buf, ',')
+	}
+	if response.Count != nil {
+		buf = append(buf, `"@odata.count":`...)
+		if ieee754 {
+			buf = append(buf, '"')
+			buf = strconv.AppendInt(buf, *response.Count, 10)
+			buf = append(buf, '"')
+		} else {
+			buf = strconv.AppendInt(buf, *response.Count, 10)
+		}
+		buf = append(buf, ',')
+	}
+	for _, annotation := range response.Annotations {
+		buf = append(appendJSONString(buf, annotation.Name), ':')
+		if buf, err = appendJSONValue(buf, annotation.Value, false); err != nil {
+			return buf, err
+		}
+		buf = append(buf, ',')
+	}
+	if response.NextLink != "" {
+		buf = append(buf, `"@odata.nextLink":`...)
+		buf = appendJSONString(buf, response.NextLink)
+		buf = append(buf, ',')
+	}
+
+	buf = append(buf, `"value":`...)
+	if buf, err = s.appendJSONEntities(buf, encoder, response.Value, ieee754); err != nil {
+		return buf, err
+	}
+
+	if response.Error != nil {
+		buf = append(buf, `,"error":`...)
+		if buf, err = appendJSONMarshal(buf, response.Error, false); err != nil {
+			return buf, err
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// appendJSONEntities serializa uma coleção de entidades (ou entidade única) da resposta
+func (s *Server) appendJSONEntities(buf []byte, encoder *entityJSONEncoder, value interface{}, ieee754 bool) ([]byte, error) {
+	var err error
+	switch v := value.(type) {
+	case *OrderedEntity:
+		return s.appendJSONEntity(buf, encoder, v, ieee754)
+	case []*OrderedEntity:
+		buf = append(buf, '[')
+		for i, entity := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = s.appendJSONEntity(buf, encoder, entity, ieee754); err != nil {
+				return buf, err
+			}
+		}
+		return append(buf, ']'), nil
+	case []interface{}:
+		if v == nil {
+			return append(buf, "null"...), nil
+		}
+		buf = append(buf, '[')
+		for i, item := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if entity, ok := item.(*OrderedEntity); ok {
+				buf, err = s.appendJSONEntity(buf, encoder, entity, ieee754)
+			} else {
+				buf, err = appendJSONValue(buf, item, ieee754)
+			}
+			if err != nil {
+				return buf, err
+			}
+		}
+		return append(buf, ']'), nil
+	default:
+		return appendJSONValue(buf, value, ieee754)
+	}
+}
+
+// appendJSONEntity serializa as anotações e a entidade na ordem das propriedades, seguida
+// dos links de navegação, como OrderedEntity.MarshalJSON
+func (s *Server) appendJSONEntity(buf []byte, encoder *entityJSONEncoder, entity *OrderedEntity, ieee754 bool) ([]byte, error) {
+	if entity == nil {
+		return append(buf, "null"...), nil
+	}
+
+	var err error
+	buf = append(buf, '{')
+	first := true
+	for _, annotation := range entity.Annotations {
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+
+		buf = append(appendJSONString(buf, annotation.Name), ':')
+		if buf, err = appendJSONValue(buf, annotation.Value, false); err != nil {
+			return buf, err
+		}
+	}
+	for _, prop := range entity.Properties {
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+
+		var field *jsonFieldEncoder
+		if encoder != nil {
+			field = encoder.fields[prop.Name]
+		}
+		if field == nil {
+			// Propriedade fora dos metadados (ex: $compute)
+			buf = append(appendJSONString(buf, prop.Name), ':')
+			buf, err = s.appendJSONNested(buf, nil, prop.Value, ieee754)
+		} else if field.relatedType != "" {
+			buf = append(buf, field.key...)
+			buf, err = s.appendJSONNested(buf, s.relatedJSONEncoder(field.relatedType), prop.Value, ieee754)
+		} else {
+			buf = append(buf, field.key...)
+			buf, err = field.encode(buf, prop.Value, ieee754)
+		}
+		if err != nil {
+			return buf, err
+		}
+	}
+
+	for _, link := range entity.NavigationLinks {
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+
+		if field := encoder.field(link.Name); field != nil {
+			buf = append(buf, field.linkKey...)
+		} else {
+			buf = append(appendJSONString(buf, link.Name+"@odata.navigationLink"), ':')
+		}
+		buf = appendJSONString(buf, link.URL)
+	}
+	return append(buf, '}'), nil
+}
+
+// field retorna o encoder da propriedade (nil para encoders ou propriedades desconhecidas)
+func (e *entityJSONEncoder) field(name string) *jsonFieldEncoder {
+	if e == nil {
+		return nil
+	}
+	return e.fields[name]
+}
+
+// appendJSONNested serializa o valor de uma navegação expandida ou propriedade desconhecida,
+// usando o encoder da entidade relacionada quando há entidades
+func (s *Server) appendJSONNested(buf []byte, encoder *entityJSONEncoder, value interface{}, ieee754 bool) ([]byte, error) {
+	switch value.(type) {
+	case *OrderedEntity, []*OrderedEntity, []interface{}:
+		return s.appendJSONEntities(buf, encoder, value, ieee754)
+	default:
+		return appendJSONValue(buf, value, ieee754)
+	}
+}
+
+// appendJSONValue serializa valores escalares sem reflexão; os demais tipos usam
+// json.Marshal. Com ieee754, int64 e Decimal são serializados como strings (ieee754Value)
+func appendJSONValue(buf []byte, value any, ieee754 bool) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, "null"...), nil
+	case string:
+		return appendJSONString(buf, v), nil
+	case bool:
+		return strconv.AppendBool(buf, v), nil
+	case int64:
+		if ieee754 {
+			buf = append(buf, '"')
+			buf = strconv.AppendInt(buf, v, 10)
+			return append(buf, '"'), nil
+		}
+		return strconv.AppendInt(buf, v, 10), nil
+	case uint64:
+		if ieee754 {
+			buf = append(buf, '"')
+			buf = strconv.AppendUint(buf, v, 10)
+			return append(buf, '"'), nil
+		}
+		return strconv.AppendUint(buf, v, 10), nil
+	case int:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int32:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int16:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int8:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case uint:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint32:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint16:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case float64:
+		return appendJSONFloat(buf, v, 64)
+	case float32:
+		return appendJSONFloat(buf, float64(v), 32)
+	case Decimal:
+		if !v.Valid {
+			return append(buf, "null"...), nil
+		}
+		if ieee754 {
+			return appendJSONString(buf, v.String()), nil
+		}
+		return append(buf, v.Val...), nil
+	case time.Time:
+		if y := v.Year(); y < 0 || y > 9999 {
+			// Fora do intervalo do RFC 3339: json.Marshal retorna o erro
+			return appendJSONMarshal(buf, v, false)
+		}
+		buf = append(buf, '"')
+		buf = v.AppendFormat(buf, time.RFC3339Nano)
+		return append(buf, '"'), nil
+	case []byte:
+		if v == nil {
+			return append(buf, "null"...), nil
+		}
+		buf = append(buf, '"')
+		buf = base64.StdEncoding.AppendEncode(buf, v)
+		return append(buf, '"'), nil
+	default:
+		return appendJSONMarshal(buf, value, ieee754)
+	}
+}
+
+// appendJSONMarshal serializa com json.Marshal os tipos não tratados pelo serializador rápido
+func appendJSONMarshal(buf []byte, value any, ieee754 bool) ([]byte, error) {
+	if ieee754 {
+		value = ieee754Value(value)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, encoded...), nil
+}
+
+// appendJSONFloat serializa números como o encoding/json (formato do ES6: notação científica
+// abaixo de 1e-6 e a partir de 1e21)
+func appendJSONFloat(buf []byte, f float64, bits int) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return buf, &json.UnsupportedValueError{Str: strconv.FormatFloat(f, 'g', -1, bits)}
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	buf = strconv.AppendFloat(buf, f, format, -1, bits)
+	if format == 'e' {
+		// e-09 vira e-9
+		n := len(buf)
+		if n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	}
+	return buf, nil
+}
+
+const jsonHex = "0123456789abcdef"
+
+// appendJSONString escapa a string como o encoding/json, inclusive <, > e & (HTML) e os
+// separadores U+2028/U+2029
+func appendJSONString(buf []byte, s string) []byte {
+	if !utf8.ValidString(s) {
+		// A substituição de bytes inválidos varia entre versões do encoding/json
+		encoded, _ := json.Marshal(s)
+		return append(buf, encoded...)
+	}
+
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			buf = append(buf, s[start:i]...)
+			switch b {
+			case '\\', '"':
+				buf = append(buf, '\\', b)
+			case '\b':
+				buf = append(buf, '\\', 'b')
+			case '\f':
+				buf = append(buf, '\\', 'f')
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', jsonHex[b>>4], jsonHex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == '\u2028' || c == '\u2029' {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', jsonHex[c&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
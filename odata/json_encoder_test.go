@@ -0,0 +1,134 @@
+package odata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestAppendJSONValue_MatchesEncodingJSON(t *testing.T) {
+	values := []any{
+		nil, true, "texto simples", `aspas " e \ barra`, "<script>&amp;</script>", "linha\nnova\t\b\f\x01",
+		"acentuação ✓", "separadores \u2028 \u2029", "utf8 inválido \xff",
+		int64(-9007199254740993), int32(7), int(42), uint64(math.MaxUint64), uint16(3),
+		float64(0), math.Copysign(0, -1), 1.5, 0.1, 1e20, 1e21, 1e-6, 1e-7, -123456789.125,
+		float32(0.1), float32(1e-7), float32(3.4e38),
+		time.Date(2026, 10, 16, 13, 45, 7, 120000000, time.UTC),
+		time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("BRT", -3*3600)),
+		[]byte("binário"), []byte(nil),
+		Decimal{Val: "12.50", Valid: true}, Decimal{},
+		map[string]interface{}{"b": 1, "a": "x"}, []string{"a", "b"},
+	}
+
+	for _, value := range values {
+		expected, err := json.Marshal(value)
+		require.NoError(t, err)
+
+		actual, err := appendJSONValue(nil, value, false)
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(actual), "%#v", value)
+	}
+
+	_, err := appendJSONValue(nil, math.Inf(1), false)
+	assert.Error(t, err)
+
+	actual, err := appendJSONValue(nil, int64(9007199254740993), true)
+	require.NoError(t, err)
+	assert.Equal(t, `"9007199254740993"`, string(actual))
+}
+
+func TestServer_FastJSONEncoding(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	_, err := server.provider.GetConnection().Exec("UPDATE customers SET name = 'Ana & <Bruno>' WHERE id = 1")
+	require.NoError(t, err)
+
+	get := func(t *testing.T, path, accept string) (string, string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body), resp.Header.Get("Content-Type")
+	}
+
+	for _, tt := range []struct {
+		path   string
+		accept string
+	}{
+		{"/odata/Orders?$count=true&$orderby=id", ""},
+		{"/odata/Orders?$expand=Items,Customer", ""},
+		{"/odata/Orders(1)?$expand=Items", ""},
+		{"/odata/Orders?$select=id,status&$top=1", ""},
+		{"/odata/Orders?$count=true&$expand=Items", "application/json;IEEE754Compatible=true"},
+		{"/odata/Orders?$filter=" + url.QueryEscape("id eq 99"), ""},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			server.SetFastJSONEncoding(false)
+			expected, expectedType := get(t, tt.path, tt.accept)
+
+			server.SetFastJSONEncoding(true)
+			actual, actualType := get(t, tt.path, tt.accept)
+
+			assert.Equal(t, expected, actual)
+			assert.Equal(t, expectedType, actualType)
+		})
+	}
+
+	server.SetFastJSONEncoding(true)
+	body, _ := get(t, "/odata/Orders?$expand=Customer&$filter="+url.QueryEscape("id eq 1"), "")
+	assert.Contains(t, body, `"name":"Ana \u0026 \u003cBruno\u003e"`, "escapa HTML como o encoding/json")
+}
+
+func BenchmarkServer_WriteEntityJSON(b *testing.B) {
+	server := &Server{config: DefaultServerConfig(), entities: make(map[string]EntityService)}
+	service := NewBaseEntityService(nil, EntityMetadata{
+		Name: "Products",
+		Properties: []PropertyMetadata{
+			{Name: "id", Type: "int64", IsKey: true},
+			{Name: "name", Type: "string"},
+			{Name: "price", Type: "float64"},
+		},
+	}, server)
+
+	values := make([]interface{}, 10000)
+	for i := range values {
+		entity := NewOrderedEntity()
+		entity.Set("id", int64(i))
+		entity.Set("name", fmt.Sprintf("Produto %d", i))
+		entity.Set("price", float64(i)*1.25)
+		values[i] = entity
+	}
+	response := &ODataResponse{Context: "$metadata#Products", Value: values}
+
+	app := fiber.New()
+	for _, fast := range []bool{false, true} {
+		b.Run(fmt.Sprintf("fast=%v", fast), func(b *testing.B) {
+			server.SetFastJSONEncoding(fast)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				c := app.AcquireCtx(&fasthttp.RequestCtx{})
+				if err := server.writeEntityJSON(c, service, response); err != nil {
+					b.Fatal(err)
+				}
+				app.ReleaseCtx(c)
+			}
+		})
+	}
+}
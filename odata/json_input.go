@@ -0,0 +1,188 @@
+package odata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// =======================================================================================
+// NÚMEROS NOS CORPOS JSON DE ENTRADA
+// =======================================================================================
+
+// JSONNumberMode define como os números dos corpos JSON de POST, PUT, PATCH, $batch e $sync
+// são decodificados
+type JSONNumberMode string
+
+const (
+	// JSONNumberTyped converte os números pelo tipo da propriedade: inteiros para int64/uint64
+	// sem perda de precisão, Edm.Decimal para Decimal e os demais para float64 (padrão)
+	JSONNumberTyped JSONNumberMode = "typed"
+	// JSONNumberUseNumber mantém todos os números como json.Number, para conversão nos eventos
+	JSONNumberUseNumber JSONNumberMode = "number"
+	// JSONNumberFloat64 decodifica todos os números como float64 (comportamento do
+	// encoding/json, com perda de precisão acima de 2^53)
+	JSONNumberFloat64 JSONNumberMode = "float64"
+)
+
+// parseJSONNumberMode converte o valor da configuração (.env) para JSONNumberMode
+func parseJSONNumberMode(value string) JSONNumberMode {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return JSONNumberTyped
+	}
+	for _, mode := range []JSONNumberMode{JSONNumberTyped, JSONNumberUseNumber, JSONNumberFloat64} {
+		if strings.EqualFold(value, string(mode)) {
+			return mode
+		}
+	}
+	log.Printf("⚠️ Modo de números JSON inválido '%s', usando typed", value)
+	return JSONNumberTyped
+}
+
+// jsonNumberMode retorna o modo configurado (JSONNumberTyped se não definido)
+func (s *Server) jsonNumberMode() JSONNumberMode {
+	if s == nil || s.config == nil || s.config.JSONNumberMode == "" {
+		return JSONNumberTyped
+	}
+	return s.config.JSONNumberMode
+}
+
+// decodeJSONBody decodifica o corpo JSON em dst. Fora do modo float64, os números chegam
+// como json.Number, sem perda de precisão
+func (s *Server) decodeJSONBody(body []byte, dst any) error {
+	if s.jsonNumberMode() == JSONNumberFloat64 {
+		return json.Unmarshal(body, dst)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(dst); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid character after top-level value")
+	}
+	return nil
+}
+
+// decodeEntityJSON decodifica o corpo JSON de uma entidade, convertendo os números segundo
+// o JSONNumberMode
+func (s *Server) decodeEntityJSON(body []byte, metadata EntityMetadata) (map[string]any, error) {
+	var entity map[string]any
+	if err := s.decodeJSONBody(body, &entity); err != nil {
+		return nil, err
+	}
+	s.convertEntityNumbers(entity, metadata)
+	return entity, nil
+}
+
+// convertEntityNumbers converte os json.Number da entidade pelo tipo das propriedades (modo
+// typed), incluindo as entidades aninhadas das navegações (deep insert/update)
+func (s *Server) convertEntityNumbers(entity map[string]any, metadata EntityMetadata) {
+	if s.jsonNumberMode() != JSONNumberTyped {
+		return
+	}
+	s.convertEntityNumbersDepth(entity, metadata, 0)
+}
+
+// convertEntityNumbersDepth converte os números da entidade, limitando a profundidade das
+// navegações aninhadas
+func (s *Server) convertEntityNumbersDepth(entity map[string]any, metadata EntityMetadata, depth int) {
+	for name, value := range entity {
+		if prop := findPropertyByName(metadata, name); prop != nil {
+			entity[name] = convertJSONNumber(value, *prop)
+			continue
+		}
+
+		navigation := findNavigationProperty(metadata, name)
+		if navigation == nil || depth >= maxJSONNumberDepth {
+			entity[name] = jsonNumbersToFloat(value)
+			continue
+		}
+		related, err := getRelatedEntityMetadata(s, navigation.RelatedType)
+		if err != nil {
+			entity[name] = jsonNumbersToFloat(value)
+			continue
+		}
+		switch nested := value.(type) {
+		case map[string]any:
+			s.convertEntityNumbersDepth(nested, related, depth+1)
+		case []any:
+			for i, item := range nested {
+				if child, ok := item.(map[string]any); ok {
+					s.convertEntityNumbersDepth(child, related, depth+1)
+				} else {
+					nested[i] = jsonNumbersToFloat(item)
+				}
+			}
+		default:
+			entity[name] = jsonNumbersToFloat(value)
+		}
+	}
+}
+
+// maxJSONNumberDepth limita a conversão das navegações aninhadas (ciclos de metadados)
+const maxJSONNumberDepth = 32
+
+// findNavigationProperty busca uma propriedade de navegação pelo nome, sem diferenciar
+// maiúsculas/minúsculas
+func findNavigationProperty(metadata EntityMetadata, name string) *PropertyMetadata {
+	for i, prop := range metadata.Properties {
+		if prop.IsNavigation && strings.EqualFold(prop.Name, name) {
+			return &metadata.Properties[i]
+		}
+	}
+	return nil
+}
+
+// convertJSONNumber converte o json.Number para o tipo da propriedade: inteiros exatos para
+// int64/uint64, Edm.Decimal para Decimal e os demais para float64
+func convertJSONNumber(value any, prop PropertyMetadata) any {
+	number, ok := value.(json.Number)
+	if !ok {
+		return jsonNumbersToFloat(value)
+	}
+
+	if isDecimalProperty(prop) {
+		if decimal, err := NewDecimal(number.String()); err == nil {
+			return decimal
+		}
+	}
+	switch prop.Type {
+	case "int", "int8", "int16", "int32", "int64":
+		if integer, err := number.Int64(); err == nil {
+			return integer
+		}
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		if integer, err := strconv.ParseUint(number.String(), 10, 64); err == nil {
+			return integer
+		}
+	}
+	return jsonNumbersToFloat(number)
+}
+
+// jsonNumbersToFloat converte os json.Number (inclusive em objetos e arrays) para float64,
+// como o encoding/json faria
+func jsonNumbersToFloat(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]any:
+		for key, item := range v {
+			v[key] = jsonNumbersToFloat(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = jsonNumbersToFloat(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,96 @@
+package odata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_JSONNumberMode(t *testing.T) {
+	const bigID = "9007199254740993" // 2^53 + 1: não representável em float64
+
+	t.Run("typed keeps int64 precision end to end", func(t *testing.T) {
+		server := newBatchGetTestServer(t)
+		server.router = fiber.New()
+		server.setupEntityRoutes("Products")
+
+		var received any
+		server.OnEntityInserting("Products", func(args EventArgs) error {
+			received = args.(*EntityInsertingArgs).Data["id"]
+			return nil
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/odata/Products", strings.NewReader(`{"id": `+bigID+`, "name": "Cabo", "price": 19.9}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, int64(9007199254740993), received)
+
+		var id int64
+		var price float64
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT id, price FROM products WHERE name = 'Cabo'").Scan(&id, &price))
+		assert.Equal(t, int64(9007199254740993), id)
+		assert.Equal(t, 19.9, price)
+
+		req = httptest.NewRequest(http.MethodPatch, "/odata/Products("+bigID+")", strings.NewReader(`{"price": 25}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err = server.router.Test(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Less(t, resp.StatusCode, 300)
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT price FROM products WHERE id = "+bigID).Scan(&price))
+		assert.Equal(t, 25.0, price)
+	})
+
+	t.Run("typed converts nested navigations", func(t *testing.T) {
+		server := newPatchDeltaTestServer(t)
+		metadata := server.GetEntityService("Orders").GetMetadata()
+
+		entity, err := server.decodeEntityJSON([]byte(`{"id": `+bigID+`, "status": 1.5, "Items": [{"order_id": `+bigID+`}], "extra": {"n": 2}}`), metadata)
+		require.NoError(t, err)
+		assert.Equal(t, int64(9007199254740993), entity["id"])
+		assert.Equal(t, 1.5, entity["status"])
+		assert.Equal(t, int64(9007199254740993), entity["Items"].([]any)[0].(map[string]any)["order_id"])
+		assert.Equal(t, 2.0, entity["extra"].(map[string]any)["n"])
+	})
+
+	t.Run("number and float64 modes", func(t *testing.T) {
+		server := newPatchDeltaTestServer(t)
+		metadata := server.GetEntityService("Customers").GetMetadata()
+
+		server.SetJSONNumberMode(JSONNumberUseNumber)
+		entity, err := server.decodeEntityJSON([]byte(`{"id": `+bigID+`}`), metadata)
+		require.NoError(t, err)
+		assert.Equal(t, json.Number(bigID), entity["id"])
+
+		server.SetJSONNumberMode(JSONNumberFloat64)
+		entity, err = server.decodeEntityJSON([]byte(`{"id": `+bigID+`}`), metadata)
+		require.NoError(t, err)
+		assert.Equal(t, float64(9007199254740992), entity["id"])
+	})
+
+	t.Run("invalid bodies", func(t *testing.T) {
+		server := newPatchDeltaTestServer(t)
+		metadata := server.GetEntityService("Customers").GetMetadata()
+
+		for _, body := range []string{``, `{"id": 1`, `{"id": 1} {"id": 2}`, `[1]`} {
+			_, err := server.decodeEntityJSON([]byte(body), metadata)
+			assert.Error(t, err, body)
+		}
+	})
+
+	t.Run("parse mode from configuration", func(t *testing.T) {
+		assert.Equal(t, JSONNumberTyped, parseJSONNumberMode(""))
+		assert.Equal(t, JSONNumberUseNumber, parseJSONNumberMode("Number"))
+		assert.Equal(t, JSONNumberFloat64, parseJSONNumberMode("float64"))
+		assert.Equal(t, JSONNumberTyped, parseJSONNumberMode("bogus"))
+	})
+}
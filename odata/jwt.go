@@ -2,6 +2,7 @@ package odata
 
 import (
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
@@ -16,6 +17,12 @@ type JWTConfig struct {
 	RefreshIn  time.Duration
 	Algorithm  string
 	ContextKey string // Chave para armazenar o token no contexto (padrão: "user")
+
+	// Chaves com kid para rotação. Quando informadas, substituem SecretKey na assinatura;
+	// tokens sem kid continuam validados com SecretKey (veja RotateKey)
+	Keys        []JWTKey
+	KeyProvider JWTKeyProvider // Chaves adicionais de uma fonte externa (opcional)
+	keysMu      sync.RWMutex
 }
 
 // NewRouterJWTAuth retorna middleware JWT
@@ -29,22 +36,15 @@ func (s *Server) NewRouterJWTAuth(config ...*JWTConfig) fiber.Handler {
 		if err != nil {
 			s.logger.Printf("⚠️  Erro ao carregar config do .env: %v, usando padrões", err)
 			jwtConfig = defaultJWTConfig()
-		} else {
-			jwtConfig = &JWTConfig{
-				SecretKey:  envConfig.JWTSecretKey,
-				Issuer:     envConfig.JWTIssuer,
-				ExpiresIn:  envConfig.JWTExpiresIn,
-				RefreshIn:  envConfig.JWTRefreshIn,
-				Algorithm:  envConfig.JWTAlgorithm,
-				ContextKey: "user",
-			}
+		} else if jwtConfig, err = jwtConfigFromEnv(envConfig); err != nil {
+			panic(err.Error())
 		}
 	} else {
 		jwtConfig = config[0]
 	}
 
 	// Validar secret key
-	if jwtConfig.SecretKey == "" {
+	if !jwtConfig.hasKeys() {
 		panic("JWT SecretKey é obrigatório! Configure JWT_SECRET_KEY no arquivo .env")
 	}
 
@@ -61,10 +61,7 @@ func (s *Server) NewRouterJWTAuth(config ...*JWTConfig) fiber.Handler {
 			if s.config.EnableLogging {
 				s.logger.Printf("❌ JWT: Token não fornecido para %s %s", c.Method(), c.Path())
 			}
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error":   "Unauthorized",
-				"message": "Token não fornecido",
-			})
+			return s.writeODataError(c, fiber.StatusUnauthorized, NewODataError("Unauthorized", "Token não fornecido"), nil)
 		}
 
 		// Verificar se tem prefixo "Bearer "
@@ -72,32 +69,21 @@ func (s *Server) NewRouterJWTAuth(config ...*JWTConfig) fiber.Handler {
 			if s.config.EnableLogging {
 				s.logger.Printf("❌ JWT: Formato de token inválido para %s %s", c.Method(), c.Path())
 			}
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error":   "Unauthorized",
-				"message": "Formato de token inválido",
-			})
+			return s.writeODataError(c, fiber.StatusUnauthorized, NewODataError("Unauthorized", "Formato de token inválido"), nil)
 		}
 
 		// Extrair token
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
 		// Validar token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Validar algoritmo
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(jwtConfig.SecretKey), nil
-		})
+		// Validar token (apenas HMAC, com a chave do kid ou SecretKey)
+		token, err := jwt.Parse(tokenString, jwtConfig.keyFunc())
 
 		if err != nil || !token.Valid {
 			if s.config.EnableLogging {
 				s.logger.Printf("❌ JWT: Token inválido ou expirado para %s %s - Erro: %v", c.Method(), c.Path(), err)
 			}
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error":   "Unauthorized",
-				"message": "Token inválido ou expirado",
-			})
+			return s.writeODataError(c, fiber.StatusUnauthorized, NewODataError("Unauthorized", "Token inválido ou expirado"), nil)
 		}
 
 		// Extrair claims
@@ -166,11 +152,8 @@ func GenerateJWT(claims jwt.MapClaims, config ...*JWTConfig) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		jwtConfig = &JWTConfig{
-			SecretKey: envConfig.JWTSecretKey,
-			Issuer:    envConfig.JWTIssuer,
-			ExpiresIn: envConfig.JWTExpiresIn,
-			Algorithm: envConfig.JWTAlgorithm,
+		if jwtConfig, err = jwtConfigFromEnv(envConfig); err != nil {
+			return "", err
 		}
 	} else {
 		jwtConfig = config[0]
@@ -188,11 +171,8 @@ func GenerateJWT(claims jwt.MapClaims, config ...*JWTConfig) (string, error) {
 		claims["exp"] = now.Add(jwtConfig.ExpiresIn).Unix()
 	}
 
-	// Criar token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Assinar token
-	return token.SignedString([]byte(jwtConfig.SecretKey))
+	// Assinar token com a chave atual (kid no header durante a rotação)
+	return jwtConfig.signToken(claims)
 }
 
 // GenerateRefreshToken gera um refresh token (função standalone)
@@ -205,11 +185,8 @@ func GenerateRefreshToken(claims jwt.MapClaims, config ...*JWTConfig) (string, e
 		if err != nil {
 			return "", err
 		}
-		jwtConfig = &JWTConfig{
-			SecretKey: envConfig.JWTSecretKey,
-			Issuer:    envConfig.JWTIssuer,
-			RefreshIn: envConfig.JWTRefreshIn,
-			Algorithm: envConfig.JWTAlgorithm,
+		if jwtConfig, err = jwtConfigFromEnv(envConfig); err != nil {
+			return "", err
 		}
 	} else {
 		jwtConfig = config[0]
@@ -227,11 +204,8 @@ func GenerateRefreshToken(claims jwt.MapClaims, config ...*JWTConfig) (string, e
 		claims["exp"] = now.Add(jwtConfig.RefreshIn).Unix()
 	}
 
-	// Criar token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Assinar token
-	return token.SignedString([]byte(jwtConfig.SecretKey))
+	// Assinar token com a chave atual (kid no header durante a rotação)
+	return jwtConfig.signToken(claims)
 }
 
 // ValidateJWT valida um token JWT (função standalone)
@@ -244,17 +218,15 @@ func ValidateJWT(tokenString string, config ...*JWTConfig) (jwt.MapClaims, error
 		if err != nil {
 			return nil, err
 		}
-		jwtConfig = &JWTConfig{
-			SecretKey: envConfig.JWTSecretKey,
+		if jwtConfig, err = jwtConfigFromEnv(envConfig); err != nil {
+			return nil, err
 		}
 	} else {
 		jwtConfig = config[0]
 	}
 
 	// Parse token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(jwtConfig.SecretKey), nil
-	})
+	token, err := jwt.Parse(tokenString, jwtConfig.keyFunc())
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,202 @@
+package odata
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// CHAVES ASSIMÉTRICAS (RS256/ES256) E JWKS
+// =======================================================================================
+
+// JWKSPath é o caminho padrão do JWKS publicado por ServeJWKS
+const JWKSPath = "/.well-known/jwks.json"
+
+// LoadJWTKeyFile carrega uma chave RSA ou ECDSA de um arquivo PEM (veja ParseJWTKeyPEM)
+func LoadJWTKeyFile(id, path string) (JWTKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return JWTKey{}, fmt.Errorf("failed to read jwt key file: %w", err)
+	}
+	return ParseJWTKeyPEM(id, data)
+}
+
+// ParseJWTKeyPEM converte uma chave PEM em JWTKey. Chaves privadas (PKCS#8, PKCS#1 ou EC)
+// assinam e validam tokens; chaves públicas (PKIX ou PKCS#1) apenas validam. Sem id, o kid é
+// o thumbprint da chave (RFC 7638)
+func ParseJWTKeyPEM(id string, data []byte) (JWTKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return JWTKey{}, fmt.Errorf("invalid jwt key: no PEM block found")
+	}
+
+	key := JWTKey{ID: id}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return JWTKey{}, fmt.Errorf("invalid jwt private key: %w", err)
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return JWTKey{}, fmt.Errorf("unsupported jwt private key type %T", parsed)
+		}
+		key.PrivateKey = signer
+	case "RSA PRIVATE KEY":
+		parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return JWTKey{}, fmt.Errorf("invalid jwt private key: %w", err)
+		}
+		key.PrivateKey = parsed
+	case "EC PRIVATE KEY":
+		parsed, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return JWTKey{}, fmt.Errorf("invalid jwt private key: %w", err)
+		}
+		key.PrivateKey = parsed
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return JWTKey{}, fmt.Errorf("invalid jwt public key: %w", err)
+		}
+		key.PublicKey = parsed
+	case "RSA PUBLIC KEY":
+		parsed, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return JWTKey{}, fmt.Errorf("invalid jwt public key: %w", err)
+		}
+		key.PublicKey = parsed
+	default:
+		return JWTKey{}, fmt.Errorf("unsupported PEM block type '%s'", block.Type)
+	}
+
+	jwk, err := publicJWK(key.publicKey())
+	if err != nil {
+		return JWTKey{}, err
+	}
+	if key.ID == "" {
+		key.ID = jwkThumbprint(jwk)
+	}
+	return key, nil
+}
+
+// JWKS retorna o JSON Web Key Set com as chaves públicas aceitas na validação (inclusive chaves
+// agendadas, para que os validadores já as conheçam na rotação). Chaves HMAC nunca são publicadas
+func (c *JWTConfig) JWKS() (map[string]interface{}, error) {
+	keys, err := c.allKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	jwks := make([]map[string]string, 0, len(keys))
+	for _, key := range keys {
+		public := key.publicKey()
+		if public == nil || !key.acceptedAt(now) {
+			continue
+		}
+		jwk, err := publicJWK(public)
+		if err != nil {
+			return nil, err
+		}
+		jwk["kid"] = key.ID
+		jwk["use"] = "sig"
+		jwk["alg"] = key.algorithm()
+		jwks = append(jwks, jwk)
+	}
+	return map[string]interface{}{"keys": jwks}, nil
+}
+
+// ServeJWKS publica as chaves públicas da configuração em JWKSPath, para validação dos tokens
+// por outros serviços. Sem configuração, usa a configuração JWT do servidor
+func (s *Server) ServeJWKS(config ...*JWTConfig) *Server {
+	s.router.Get(JWKSPath, func(c fiber.Ctx) error {
+		jwtConfig := s.config.JWTConfig
+		if len(config) > 0 && config[0] != nil {
+			jwtConfig = config[0]
+		}
+		if jwtConfig == nil {
+			return s.writeODataError(c, fiber.StatusNotFound, NewODataError("NotFound", "JWKS not configured"), nil)
+		}
+
+		jwks, err := jwtConfig.JWKS()
+		if err != nil {
+			return s.writeODataError(c, fiber.StatusInternalServerError, NewODataError("InternalError", "Failed to load JWT keys"), err)
+		}
+		c.Set("Cache-Control", "public, max-age=300")
+		return c.JSON(jwks)
+	})
+	return s
+}
+
+// hasPublicKeys verifica se a configuração possui chaves assimétricas a publicar
+func (c *JWTConfig) hasPublicKeys() bool {
+	c.keysMu.RLock()
+	defer c.keysMu.RUnlock()
+	if c.KeyProvider != nil {
+		return true
+	}
+	for _, key := range c.Keys {
+		if key.publicKey() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// publicJWK converte a chave pública nos membros obrigatórios do JWK (RFC 7518)
+func publicJWK(public crypto.PublicKey) (map[string]string, error) {
+	encode := base64.RawURLEncoding.EncodeToString
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{
+			"kty": "RSA",
+			"n":   encode(key.N.Bytes()),
+			"e":   encode(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		return map[string]string{
+			"kty": "EC",
+			"crv": key.Curve.Params().Name,
+			"x":   encode(key.X.FillBytes(make([]byte, size))),
+			"y":   encode(key.Y.FillBytes(make([]byte, size))),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported jwt public key type %T", public)
+	}
+}
+
+// jwkThumbprint calcula o thumbprint SHA-256 do JWK (RFC 7638)
+func jwkThumbprint(jwk map[string]string) string {
+	members := []string{"e", "kty", "n"}
+	if jwk["kty"] == "EC" {
+		members = []string{"crv", "kty", "x", "y"}
+	}
+	// Membros obrigatórios em ordem lexicográfica, sem espaços
+	canonical := "{"
+	for i, member := range members {
+		if i > 0 {
+			canonical += ","
+		}
+		name, _ := json.Marshal(member)
+		value, _ := json.Marshal(jwk[member])
+		canonical += string(name) + ":" + string(value)
+	}
+	canonical += "}"
+
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,183 @@
+package odata
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeJWTKeyPEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+	return path
+}
+
+func TestJWTConfig_AsymmetricKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaDER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+	rsaJWTKey, err := LoadJWTKeyFile("rsa-1", writeJWTKeyPEM(t, "PRIVATE KEY", rsaDER))
+	require.NoError(t, err)
+
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	ecJWTKey, err := LoadJWTKeyFile("", writeJWTKeyPEM(t, "EC PRIVATE KEY", ecDER))
+	require.NoError(t, err)
+	assert.NotEmpty(t, ecJWTKey.ID, "kid derivado do thumbprint")
+
+	t.Run("RS256", func(t *testing.T) {
+		config := &JWTConfig{Issuer: "test", ExpiresIn: time.Hour, Keys: []JWTKey{rsaJWTKey}}
+		token, err := GenerateJWT(jwt.MapClaims{"sub": "ana"}, config)
+		require.NoError(t, err)
+
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, "RS256", parsed.Header["alg"])
+		assert.Equal(t, "rsa-1", parsed.Header["kid"])
+
+		claims, err := ValidateJWT(token, config)
+		require.NoError(t, err)
+		assert.Equal(t, "ana", claims["sub"])
+	})
+
+	t.Run("ES256 e rotação de HMAC para chave assimétrica", func(t *testing.T) {
+		config := &JWTConfig{SecretKey: "legacy-secret", Issuer: "test", ExpiresIn: time.Hour}
+		legacy, err := GenerateJWT(jwt.MapClaims{"sub": "ana"}, config)
+		require.NoError(t, err)
+
+		require.NoError(t, config.RotateKey(ecJWTKey, time.Hour))
+		token, err := GenerateJWT(jwt.MapClaims{"sub": "ana"}, config)
+		require.NoError(t, err)
+		parsed, _, _ := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+		assert.Equal(t, "ES256", parsed.Header["alg"])
+
+		_, err = ValidateJWT(token, config)
+		assert.NoError(t, err)
+		_, err = ValidateJWT(legacy, config)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Chave pública apenas valida", func(t *testing.T) {
+		publicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+		require.NoError(t, err)
+		publicKey, err := ParseJWTKeyPEM("rsa-1", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+		require.NoError(t, err)
+
+		issuer := &JWTConfig{ExpiresIn: time.Hour, Keys: []JWTKey{rsaJWTKey}}
+		validator := &JWTConfig{Keys: []JWTKey{publicKey}}
+
+		token, err := GenerateJWT(jwt.MapClaims{"sub": "ana"}, issuer)
+		require.NoError(t, err)
+		_, err = ValidateJWT(token, validator)
+		assert.NoError(t, err)
+
+		_, err = GenerateJWT(jwt.MapClaims{"sub": "ana"}, validator)
+		assert.ErrorContains(t, err, "no active jwt signing key")
+
+		// Token HS256 assinado com a chave pública como segredo (confusão de algoritmo)
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "admin"})
+		forged.Header["kid"] = "rsa-1"
+		forgedToken, err := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+		require.NoError(t, err)
+		_, err = ValidateJWT(forgedToken, validator)
+		assert.Error(t, err)
+	})
+
+	t.Run("JWKS", func(t *testing.T) {
+		server := &Server{router: fiber.New(), config: DefaultServerConfig()}
+		server.config.JWTConfig = &JWTConfig{
+			SecretKey: "not-published",
+			ExpiresIn: time.Hour,
+			Keys:      []JWTKey{rsaJWTKey, ecJWTKey},
+		}
+		server.ServeJWKS()
+
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, JWKSPath, nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		assert.NotContains(t, string(body), "not-published")
+
+		var jwks struct {
+			Keys []map[string]string `json:"keys"`
+		}
+		require.NoError(t, json.Unmarshal(body, &jwks))
+		require.Len(t, jwks.Keys, 2)
+		assert.Equal(t, "RSA", jwks.Keys[0]["kty"])
+		assert.Equal(t, "RS256", jwks.Keys[0]["alg"])
+		assert.Equal(t, "EC", jwks.Keys[1]["kty"])
+		assert.Equal(t, "P-256", jwks.Keys[1]["crv"])
+		assert.Equal(t, ecJWTKey.ID, jwks.Keys[1]["kid"])
+
+		// Um validador externo reconstrói a chave RSA a partir do JWKS
+		n, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0]["n"])
+		require.NoError(t, err)
+		e, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0]["e"])
+		require.NoError(t, err)
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+
+		token, err := GenerateJWT(jwt.MapClaims{"sub": "ana"}, &JWTConfig{ExpiresIn: time.Hour, Keys: []JWTKey{rsaJWTKey}})
+		require.NoError(t, err)
+		_, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return public, nil })
+		assert.NoError(t, err)
+	})
+
+	t.Run("Configuração via .env", func(t *testing.T) {
+		env := &EnvConfig{Variables: map[string]string{
+			"JWT_ENABLED":          "true",
+			"JWT_PRIVATE_KEY_FILE": writeJWTKeyPEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)),
+			"JWT_KEY_ID":           "env-rsa",
+		}}
+		env.parseVariables()
+		config := env.ToServerConfig().JWTConfig
+		require.NotNil(t, config)
+		require.Len(t, config.Keys, 1)
+		assert.Equal(t, "env-rsa", config.Keys[0].ID)
+		assert.True(t, config.hasPublicKeys())
+	})
+
+	t.Run("Chave privada inválida não volta para JWT_SECRET_KEY", func(t *testing.T) {
+		for name, file := range map[string]string{
+			"PEM inválido":        writeJWTKeyPEM(t, "RSA PRIVATE KEY", []byte("não é uma chave")),
+			"arquivo inexistente": filepath.Join(t.TempDir(), "missing.pem"),
+		} {
+			env := &EnvConfig{Variables: map[string]string{
+				"JWT_ENABLED":          "true",
+				"JWT_SECRET_KEY":       "shared-secret",
+				"JWT_PRIVATE_KEY_FILE": file,
+			}}
+			env.parseVariables()
+
+			_, err := jwtConfigFromEnv(env)
+			assert.ErrorContains(t, err, "JWT_PRIVATE_KEY_FILE", name)
+
+			serverConfig := env.ToServerConfig()
+			assert.Nil(t, serverConfig.JWTConfig, name)
+			assert.False(t, serverConfig.EnableJWT, name)
+		}
+	})
+}
@@ -0,0 +1,317 @@
+package odata
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// =======================================================================================
+// ROTAÇÃO DE CHAVES JWT (MÚLTIPLAS CHAVES COM KID)
+// =======================================================================================
+
+// JWTKey é uma chave de assinatura JWT identificada por kid. A chave assina os tokens emitidos
+// a partir de NotBefore (a chave válida mais recente é usada) e é aceita na validação até
+// ExpiresAt, o que mantém válidos os tokens da chave anterior durante a rotação
+type JWTKey struct {
+	ID        string    // Identificador enviado no header "kid" dos tokens
+	Secret    string    // Chave HMAC
+	NotBefore time.Time // Início do uso para assinatura (zero = imediatamente)
+	ExpiresAt time.Time // Fim da aceitação na validação (zero = sem expiração)
+
+	legacy bool // SecretKey da configuração (sem kid)
+
+	// Chaves assimétricas (RSA ou ECDSA), no lugar de Secret. Chaves apenas com PublicKey
+	// validam tokens emitidos por outro serviço, mas não assinam (veja LoadJWTKeyFile)
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+
+	// Algoritmo (HS256, RS256, ES256...). Vazio deriva da chave: HS256 para Secret, RS256 para
+	// RSA e ES256/ES384/ES512 conforme a curva ECDSA
+	Algorithm string
+}
+
+// JWTKeyProvider fornece chaves JWT de uma fonte externa (ex: KMS, Vault). As chaves
+// retornadas são somadas a JWTConfig.Keys a cada assinatura e validação, por isso
+// implementações com acesso remoto devem manter um cache
+type JWTKeyProvider interface {
+	JWTKeys(ctx context.Context) ([]JWTKey, error)
+}
+
+// algorithm retorna o algoritmo de assinatura da chave
+func (k JWTKey) algorithm() string {
+	if k.Algorithm != "" {
+		return k.Algorithm
+	}
+	switch key := k.publicKey().(type) {
+	case *rsa.PublicKey:
+		return "RS256"
+	case *ecdsa.PublicKey:
+		switch key.Curve.Params().BitSize {
+		case 384:
+			return "ES384"
+		case 521:
+			return "ES512"
+		default:
+			return "ES256"
+		}
+	}
+	return "HS256"
+}
+
+// publicKey retorna a chave pública das chaves assimétricas (nil para HMAC)
+func (k JWTKey) publicKey() crypto.PublicKey {
+	if k.PublicKey != nil {
+		return k.PublicKey
+	}
+	if k.PrivateKey != nil {
+		return k.PrivateKey.Public()
+	}
+	return nil
+}
+
+// signing retorna o método e a chave usados para assinar tokens
+func (k JWTKey) signing() (jwt.SigningMethod, interface{}, error) {
+	method := jwt.GetSigningMethod(k.algorithm())
+	if method == nil {
+		return nil, nil, fmt.Errorf("unsupported jwt algorithm '%s'", k.algorithm())
+	}
+	if k.PrivateKey != nil {
+		return method, k.PrivateKey, nil
+	}
+	if k.Secret != "" {
+		return method, []byte(k.Secret), nil
+	}
+	return nil, nil, fmt.Errorf("jwt key '%s' cannot sign tokens", k.ID)
+}
+
+// verificationKey retorna a chave de validação, se a chave aceitar o método do token. O
+// algoritmo precisa coincidir, impedindo que um token HS256 seja validado com a chave pública
+func (k JWTKey) verificationKey(method jwt.SigningMethod) (jwt.VerificationKey, bool) {
+	if public := k.publicKey(); public != nil {
+		return public, method.Alg() == k.algorithm()
+	}
+	if _, ok := method.(*jwt.SigningMethodHMAC); !ok || k.Secret == "" {
+		return nil, false
+	}
+	return []byte(k.Secret), k.Algorithm == "" || method.Alg() == k.Algorithm
+}
+
+// activeAt verifica se a chave pode assinar tokens no instante informado
+func (k JWTKey) activeAt(now time.Time) bool {
+	return !now.Before(k.NotBefore) && k.acceptedAt(now)
+}
+
+// acceptedAt verifica se a chave ainda é aceita na validação no instante informado
+func (k JWTKey) acceptedAt(now time.Time) bool {
+	return k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt)
+}
+
+// RotateKey passa a assinar os tokens com a nova chave. As chaves atuais sem expiração
+// continuam aceitas na validação pelo período de grace (tempo para os tokens já emitidos
+// expirarem, normalmente ExpiresIn ou RefreshIn)
+func (c *JWTConfig) RotateKey(key JWTKey, grace time.Duration) error {
+	if key.ID == "" || (key.Secret == "" && key.PrivateKey == nil) {
+		return fmt.Errorf("jwt key requires an id and a secret or private key")
+	}
+
+	c.keysMu.Lock()
+	defer c.keysMu.Unlock()
+
+	now := time.Now()
+	keys := c.Keys
+	if len(keys) == 0 && c.SecretKey != "" {
+		// A chave única (sem kid) vira uma chave anterior, aceita durante o período de grace
+		keys = []JWTKey{{Secret: c.SecretKey}}
+	}
+
+	rotated := make([]JWTKey, 0, len(keys)+1)
+	for _, existing := range keys {
+		if existing.ID == key.ID {
+			return fmt.Errorf("jwt key '%s' already exists", key.ID)
+		}
+		if !existing.acceptedAt(now) {
+			continue
+		}
+		if existing.ExpiresAt.IsZero() {
+			existing.ExpiresAt = now.Add(grace)
+		}
+		rotated = append(rotated, existing)
+	}
+	if key.NotBefore.IsZero() {
+		key.NotBefore = now
+	}
+	c.Keys = append(rotated, key)
+	c.SecretKey = ""
+	return nil
+}
+
+// hasKeys verifica se a configuração possui alguma chave
+func (c *JWTConfig) hasKeys() bool {
+	c.keysMu.RLock()
+	defer c.keysMu.RUnlock()
+	return c.SecretKey != "" || len(c.Keys) > 0 || c.KeyProvider != nil
+}
+
+// allKeys retorna as chaves configuradas seguidas pelas do KeyProvider. Sem chaves, SecretKey
+// é retornada como chave HMAC sem kid
+func (c *JWTConfig) allKeys() ([]JWTKey, error) {
+	c.keysMu.RLock()
+	keys := append([]JWTKey(nil), c.Keys...)
+	if c.SecretKey != "" {
+		// Tokens sem kid (emitidos antes da rotação) continuam validados com SecretKey
+		keys = append(keys, JWTKey{Secret: c.SecretKey, legacy: true})
+	}
+	provider := c.KeyProvider
+	c.keysMu.RUnlock()
+
+	if provider != nil {
+		provided, err := provider.JWTKeys(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwt keys: %w", err)
+		}
+		keys = append(keys, provided...)
+	}
+	return keys, nil
+}
+
+// signingKey retorna a chave usada para assinar novos tokens: a chave ativa com o NotBefore
+// mais recente ou, sem outras chaves, SecretKey (sem kid)
+func (c *JWTConfig) signingKey(now time.Time) (JWTKey, error) {
+	keys, err := c.allKeys()
+	if err != nil {
+		return JWTKey{}, err
+	}
+
+	var current, legacy *JWTKey
+	hasSigners := false
+	for i := range keys {
+		key := &keys[i]
+		if key.legacy {
+			legacy = key
+			continue
+		}
+		if key.PrivateKey == nil && key.Secret == "" {
+			continue // Apenas validação
+		}
+		hasSigners = true
+		if key.activeAt(now) && (current == nil || !key.NotBefore.Before(current.NotBefore)) {
+			current = key
+		}
+	}
+	if current != nil {
+		return *current, nil
+	}
+	if legacy != nil && !hasSigners {
+		return *legacy, nil
+	}
+	return JWTKey{}, fmt.Errorf("no active jwt signing key")
+}
+
+// signToken assina os claims com a chave atual, informando o kid no header
+func (c *JWTConfig) signToken(claims jwt.MapClaims) (string, error) {
+	key, err := c.signingKey(time.Now())
+	if err != nil {
+		return "", err
+	}
+	method, signingKey, err := key.signing()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(method, claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	return token.SignedString(signingKey)
+}
+
+// verificationKeys retorna as chaves aceitas para o token: as chaves do kid informado cujo
+// algoritmo coincide com o do token. Tokens sem kid (emitidos antes da rotação) são validados
+// com SecretKey e com as chaves sem ID
+func (c *JWTConfig) verificationKeys(token *jwt.Token, now time.Time) ([]jwt.VerificationKey, error) {
+	all, err := c.allKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	var keys []jwt.VerificationKey
+	for _, key := range all {
+		if key.ID != kid || !key.acceptedAt(now) {
+			continue
+		}
+		if verificationKey, ok := key.verificationKey(token.Method); ok {
+			keys = append(keys, verificationKey)
+		}
+	}
+	if len(keys) == 0 {
+		if kid != "" {
+			return nil, fmt.Errorf("unknown or expired jwt key '%s' for %s", kid, token.Method.Alg())
+		}
+		return nil, fmt.Errorf("no jwt verification key for %s", token.Method.Alg())
+	}
+	return keys, nil
+}
+
+// keyFunc retorna o jwt.Keyfunc que seleciona as chaves válidas pelo kid e pelo algoritmo
+func (c *JWTConfig) keyFunc() jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		keys, err := c.verificationKeys(token, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) == 1 {
+			return keys[0], nil
+		}
+		return jwt.VerificationKeySet{Keys: keys}, nil
+	}
+}
+
+// jwtConfigFromEnv cria a configuração JWT a partir do .env, incluindo a chave privada
+// (JWT_PRIVATE_KEY_FILE) e a chave anterior durante a rotação (JWT_PREVIOUS_SECRET_KEY).
+// Uma chave privada configurada que não pode ser carregada é um erro: a assinatura nunca
+// volta silenciosamente para HS256 com JWT_SECRET_KEY
+func jwtConfigFromEnv(c *EnvConfig) (*JWTConfig, error) {
+	config := &JWTConfig{
+		SecretKey:  c.JWTSecretKey,
+		Issuer:     c.JWTIssuer,
+		ExpiresIn:  c.JWTExpiresIn,
+		RefreshIn:  c.JWTRefreshIn,
+		Algorithm:  c.JWTAlgorithm,
+		ContextKey: "user",
+	}
+	if c.JWTPrivateKeyFile != "" {
+		key, err := LoadJWTKeyFile(c.JWTKeyID, c.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("chave privada JWT inválida (JWT_PRIVATE_KEY_FILE): %w", err)
+		}
+		// A chave assimétrica assina os tokens; JWT_SECRET_KEY só valida tokens antigos
+		if c.JWTAlgorithm != "" && c.JWTAlgorithm != "HS256" {
+			key.Algorithm = c.JWTAlgorithm
+		}
+		config.Keys = []JWTKey{key}
+		return config, nil
+	}
+	if c.JWTKeyID == "" && c.JWTPreviousSecretKey == "" {
+		return config, nil
+	}
+
+	// A chave anterior vem primeiro: com o mesmo NotBefore, a última chave assina os tokens
+	if c.JWTPreviousSecretKey != "" {
+		previous := JWTKey{ID: c.JWTPreviousKeyID, Secret: c.JWTPreviousSecretKey}
+		if c.JWTPreviousKeyExpiresAt != "" {
+			if expiresAt, err := time.Parse(time.RFC3339, c.JWTPreviousKeyExpiresAt); err == nil {
+				previous.ExpiresAt = expiresAt
+			}
+		}
+		config.Keys = append(config.Keys, previous)
+	}
+	config.Keys = append(config.Keys, JWTKey{ID: c.JWTKeyID, Secret: c.JWTSecretKey})
+	config.SecretKey = ""
+	return config, nil
+}
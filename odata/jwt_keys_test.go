@@ -0,0 +1,113 @@
+package odata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tokenKid(t *testing.T, token string) interface{} {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	return parsed.Header["kid"]
+}
+
+func TestJWTConfig_RotateKey(t *testing.T) {
+	config := &JWTConfig{SecretKey: "legacy-secret", Issuer: "test", ExpiresIn: time.Hour}
+
+	legacy, err := GenerateJWT(jwt.MapClaims{"sub": "ana"}, config)
+	require.NoError(t, err)
+	assert.Nil(t, tokenKid(t, legacy))
+
+	require.NoError(t, config.RotateKey(JWTKey{ID: "2025-10", Secret: "october-secret"}, time.Hour))
+
+	current, err := GenerateJWT(jwt.MapClaims{"sub": "ana"}, config)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-10", tokenKid(t, current))
+
+	t.Run("Tokens da chave anterior continuam válidos durante o grace", func(t *testing.T) {
+		_, err := ValidateJWT(legacy, config)
+		assert.NoError(t, err)
+		_, err = ValidateJWT(current, config)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Segunda rotação", func(t *testing.T) {
+		require.NoError(t, config.RotateKey(JWTKey{ID: "2025-11", Secret: "november-secret"}, time.Hour))
+		next, err := GenerateJWT(jwt.MapClaims{"sub": "ana"}, config)
+		require.NoError(t, err)
+		assert.Equal(t, "2025-11", tokenKid(t, next))
+
+		_, err = ValidateJWT(current, config)
+		assert.NoError(t, err)
+		assert.Error(t, config.RotateKey(JWTKey{ID: "2025-11", Secret: "x"}, time.Hour), "kid duplicado")
+	})
+
+	t.Run("Chaves expiradas deixam de ser aceitas", func(t *testing.T) {
+		config.keysMu.Lock()
+		for i := range config.Keys {
+			if config.Keys[i].ID != "2025-11" {
+				config.Keys[i].ExpiresAt = time.Now().Add(-time.Second)
+			}
+		}
+		config.keysMu.Unlock()
+
+		_, err := ValidateJWT(legacy, config)
+		assert.Error(t, err)
+		_, err = ValidateJWT(current, config)
+		assert.ErrorContains(t, err, "2025-10")
+	})
+}
+
+func TestJWTConfig_ScheduledKeys(t *testing.T) {
+	now := time.Now()
+	config := &JWTConfig{
+		Issuer:    "test",
+		ExpiresIn: time.Hour,
+		Keys: []JWTKey{
+			{ID: "old", Secret: "old-secret", ExpiresAt: now.Add(time.Hour)},
+			{ID: "current", Secret: "current-secret", NotBefore: now.Add(-time.Minute)},
+			{ID: "next", Secret: "next-secret", NotBefore: now.Add(24 * time.Hour)},
+		},
+	}
+
+	token, err := GenerateJWT(jwt.MapClaims{"sub": "ana"}, config)
+	require.NoError(t, err)
+	assert.Equal(t, "current", tokenKid(t, token), "a chave agendada ainda não assina")
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "ana"})
+	forged.Header["kid"] = "current"
+	forgedToken, err := forged.SignedString([]byte("old-secret"))
+	require.NoError(t, err)
+	_, err = ValidateJWT(forgedToken, config)
+	assert.Error(t, err, "o kid seleciona a chave de validação")
+
+	_, err = ValidateJWT(forgedToken, &JWTConfig{Keys: []JWTKey{{ID: "other", Secret: "old-secret"}}})
+	assert.ErrorContains(t, err, "unknown or expired jwt key")
+}
+
+func TestJWTConfigFromEnv_PreviousKey(t *testing.T) {
+	env := &EnvConfig{Variables: map[string]string{
+		"JWT_SECRET_KEY":              "new-secret",
+		"JWT_KEY_ID":                  "v2",
+		"JWT_PREVIOUS_SECRET_KEY":     "old-secret",
+		"JWT_PREVIOUS_KEY_EXPIRES_AT": time.Now().Add(time.Hour).Format(time.RFC3339),
+	}}
+	env.parseVariables()
+	config, err := jwtConfigFromEnv(env)
+	require.NoError(t, err)
+
+	require.Len(t, config.Keys, 2)
+	token, err := GenerateJWT(jwt.MapClaims{"sub": "ana"}, config)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", tokenKid(t, token))
+
+	oldToken, err := GenerateJWT(jwt.MapClaims{"sub": "ana"}, &JWTConfig{SecretKey: "old-secret", ExpiresIn: time.Hour})
+	require.NoError(t, err)
+	_, err = ValidateJWT(oldToken, config)
+	assert.NoError(t, err, "tokens sem kid emitidos com a chave anterior")
+}
@@ -0,0 +1,201 @@
+package odata
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// =======================================================================================
+// LISTENER: SO_REUSEPORT E HANDOFF DO SOCKET (RESTART SEM DOWNTIME)
+// =======================================================================================
+
+const (
+	// ListenerFDEnv informa ao novo processo o descritor do socket herdado no handoff
+	ListenerFDEnv = "GODATA_LISTENER_FD"
+
+	// ListenerParentPIDEnv informa ao novo processo o PID do processo que deve ser drenado
+	ListenerParentPIDEnv = "GODATA_PARENT_PID"
+
+	// listenFDsStart é o primeiro descritor passado pela ativação de socket do systemd
+	listenFDsStart = 3
+)
+
+// useCustomListener indica se o servidor precisa criar o próprio listener em vez de
+// delegar ao Fiber (SO_REUSEPORT, handoff ou socket herdado)
+func (s *Server) useCustomListener() bool {
+	return s.config.ReusePort || s.config.EnableHandoff || inheritedListenerFD() >= 0
+}
+
+// inheritedListenerFD retorna o descritor do socket herdado (handoff do go-data ou
+// ativação de socket do systemd), ou -1 se não houver
+func inheritedListenerFD() int {
+	if value := os.Getenv(ListenerFDEnv); value != "" {
+		if fd, err := strconv.Atoi(value); err == nil && fd >= 0 {
+			return fd
+		}
+	}
+
+	// systemd: LISTEN_PID identifica o processo destinatário e LISTEN_FDS a quantidade
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err == nil && pid == os.Getpid() {
+		if count, err := strconv.Atoi(os.Getenv("LISTEN_FDS")); err == nil && count > 0 {
+			return listenFDsStart
+		}
+	}
+	return -1
+}
+
+// createListener cria o listener do servidor: reaproveita o socket herdado quando
+// existir ou abre um novo (com SO_REUSEPORT, se habilitado). Aplica TLS se configurado
+func (s *Server) createListener(addr string) (net.Listener, error) {
+	var ln net.Listener
+
+	if fd := inheritedListenerFD(); fd >= 0 {
+		file := os.NewFile(uintptr(fd), "godata-listener")
+		inherited, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao reutilizar socket herdado (fd %d): %w", fd, err)
+		}
+		s.logger.Printf("♻️ Reutilizando socket herdado (fd %d) em %s", fd, inherited.Addr())
+		ln = inherited
+
+		// Evita que processos filhos deste processo herdem o mesmo descritor
+		os.Unsetenv(ListenerFDEnv)
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+	} else {
+		lc := net.ListenConfig{}
+		if s.config.ReusePort {
+			lc.Control = reusePortControl
+		}
+		created, err := lc.Listen(context.Background(), "tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao abrir listener em %s: %w", addr, err)
+		}
+		ln = created
+	}
+
+	tlsConfig, err := s.listenerTLSConfig()
+	if err != nil {
+		ln.Close()
+		return nil, err
+	}
+
+	// Guarda o listener TCP (sem TLS) para o handoff
+	s.mu.Lock()
+	s.tcpListener = ln
+	s.mu.Unlock()
+
+	if tlsConfig != nil {
+		return tls.NewListener(ln, tlsConfig), nil
+	}
+	return ln, nil
+}
+
+// listenerTLSConfig monta a configuração TLS do listener próprio (nil sem TLS)
+func (s *Server) listenerTLSConfig() (*tls.Config, error) {
+	if s.config.CertFile != "" && s.config.CertKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.config.CertFile, s.config.CertKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao carregar certificado TLS: %w", err)
+		}
+		return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+	}
+	if s.config.TLSConfig != nil {
+		return s.config.TLSConfig.Clone(), nil
+	}
+	return nil, nil
+}
+
+// Handoff inicia uma nova instância do executável que herda o socket do servidor.
+// Quando a nova instância começa a atender, ela envia SIGTERM a este processo, que
+// drena as requisições em andamento (graceful shutdown) e encerra
+func (s *Server) Handoff() error {
+	s.mu.RLock()
+	ln := s.tcpListener
+	s.mu.RUnlock()
+
+	if ln == nil {
+		return fmt.Errorf("handoff requer o listener próprio (habilite EnableHandoff antes de Start)")
+	}
+
+	file, err := listenerFile(ln)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("erro ao localizar executável: %w", err)
+	}
+
+	pid, err := startHandoffProcess(executable, os.Args[1:], file)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar novo processo: %w", err)
+	}
+
+	s.logger.Printf("♻️ Handoff do socket %s para o processo %d", ln.Addr(), pid)
+	return nil
+}
+
+// listenerFile duplica o descritor do listener para repassá-lo ao novo processo
+func listenerFile(ln net.Listener) (*os.File, error) {
+	filer, ok := ln.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, fmt.Errorf("listener %T não suporta handoff", ln)
+	}
+	file, err := filer.File()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter descritor do listener: %w", err)
+	}
+	return file, nil
+}
+
+// handoffEnv monta o ambiente do novo processo, substituindo variáveis de handoff
+// herdadas de um handoff anterior
+func handoffEnv(fd int) []string {
+	env := make([]string, 0, len(os.Environ())+2)
+	for _, entry := range os.Environ() {
+		switch {
+		case hasEnvKey(entry, ListenerFDEnv), hasEnvKey(entry, ListenerParentPIDEnv),
+			hasEnvKey(entry, "LISTEN_PID"), hasEnvKey(entry, "LISTEN_FDS"):
+			continue
+		}
+		env = append(env, entry)
+	}
+	return append(env,
+		fmt.Sprintf("%s=%d", ListenerFDEnv, fd),
+		fmt.Sprintf("%s=%d", ListenerParentPIDEnv, os.Getpid()),
+	)
+}
+
+// hasEnvKey verifica se a entrada KEY=VALUE pertence à variável informada
+func hasEnvKey(entry, key string) bool {
+	return strings.HasPrefix(entry, key+"=")
+}
+
+// notifyHandoffParent sinaliza ao processo anterior que a nova instância está pronta,
+// para que ele inicie o graceful shutdown
+func (s *Server) notifyHandoffParent() {
+	value := os.Getenv(ListenerParentPIDEnv)
+	if value == "" {
+		return
+	}
+	os.Unsetenv(ListenerParentPIDEnv)
+
+	pid, err := strconv.Atoi(value)
+	if err != nil || pid <= 0 || pid == os.Getpid() {
+		return
+	}
+	if err := signalShutdown(pid); err != nil {
+		s.logger.Printf("Aviso: não foi possível sinalizar o processo anterior (%d): %v", pid, err)
+		return
+	}
+	s.logger.Printf("♻️ Processo anterior (%d) sinalizado para drenar conexões", pid)
+}
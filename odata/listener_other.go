@@ -0,0 +1,27 @@
+//go:build !unix
+
+package odata
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// handoffSignal é nil fora de sistemas Unix: o handoff não é suportado
+var handoffSignal os.Signal
+
+// reusePortControl não é suportado fora de sistemas Unix
+func reusePortControl(network, address string, conn syscall.RawConn) error {
+	return fmt.Errorf("SO_REUSEPORT não é suportado nesta plataforma")
+}
+
+// startHandoffProcess não é suportado fora de sistemas Unix
+func startHandoffProcess(executable string, args []string, listener *os.File) (int, error) {
+	return 0, fmt.Errorf("handoff de socket não é suportado nesta plataforma")
+}
+
+// signalShutdown não é suportado fora de sistemas Unix
+func signalShutdown(pid int) error {
+	return fmt.Errorf("sinalização de processo não é suportada nesta plataforma")
+}
@@ -0,0 +1,86 @@
+package odata
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newListenerTestServer() *Server {
+	return &Server{config: DefaultServerConfig(), logger: log.New(&bytes.Buffer{}, "", 0)}
+}
+
+func TestServer_createListener(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SO_REUSEPORT e handoff de socket são suportados apenas em Unix")
+	}
+
+	t.Run("Padrão delega ao Fiber", func(t *testing.T) {
+		server := newListenerTestServer()
+		assert.False(t, server.useCustomListener())
+	})
+
+	t.Run("SO_REUSEPORT permite duas instâncias na mesma porta", func(t *testing.T) {
+		first := newListenerTestServer().SetReusePort(true)
+		ln1, err := first.createListener("127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln1.Close()
+
+		second := newListenerTestServer().SetReusePort(true)
+		ln2, err := second.createListener(ln1.Addr().String())
+		require.NoError(t, err)
+		defer ln2.Close()
+
+		assert.Equal(t, ln1.Addr().String(), ln2.Addr().String())
+	})
+
+	t.Run("Reutiliza o socket herdado", func(t *testing.T) {
+		original, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer original.Close()
+
+		file, err := listenerFile(original)
+		require.NoError(t, err)
+		defer file.Close()
+
+		t.Setenv(ListenerFDEnv, fmt.Sprint(file.Fd()))
+
+		server := newListenerTestServer()
+		assert.True(t, server.useCustomListener())
+
+		ln, err := server.createListener("127.0.0.1:1")
+		require.NoError(t, err)
+		defer ln.Close()
+
+		assert.Equal(t, original.Addr().String(), ln.Addr().String())
+		assert.Empty(t, os.Getenv(ListenerFDEnv))
+		assert.Same(t, ln, server.tcpListener)
+	})
+}
+
+func TestHandoffEnv(t *testing.T) {
+	t.Setenv(ListenerFDEnv, "9")
+	t.Setenv(ListenerParentPIDEnv, "1")
+
+	env := handoffEnv(3)
+
+	var fds, parents []string
+	for _, entry := range env {
+		if strings.HasPrefix(entry, ListenerFDEnv+"=") {
+			fds = append(fds, entry)
+		}
+		if strings.HasPrefix(entry, ListenerParentPIDEnv+"=") {
+			parents = append(parents, entry)
+		}
+	}
+	assert.Equal(t, []string{ListenerFDEnv + "=3"}, fds)
+	assert.Equal(t, []string{fmt.Sprintf("%s=%d", ListenerParentPIDEnv, os.Getpid())}, parents)
+}
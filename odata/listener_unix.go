@@ -0,0 +1,52 @@
+//go:build unix
+
+package odata
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// handoffSignal é o sinal que dispara o handoff do socket para uma nova instância
+var handoffSignal os.Signal = syscall.SIGHUP
+
+// reusePortControl habilita SO_REUSEADDR e SO_REUSEPORT no socket, permitindo que
+// outra instância escute na mesma porta durante o restart
+func reusePortControl(network, address string, conn syscall.RawConn) error {
+	var sockErr error
+	err := conn.Control(func(fd uintptr) {
+		if sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEADDR, 1); sockErr != nil {
+			return
+		}
+		sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+	})
+	if err != nil {
+		return err
+	}
+	return sockErr
+}
+
+// startHandoffProcess inicia o novo processo repassando o socket como fd 3
+func startHandoffProcess(executable string, args []string, listener *os.File) (int, error) {
+	cmd := exec.Command(executable, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{listener}
+	cmd.Env = handoffEnv(listenFDsStart)
+
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+	// O processo filho segue independente; libera os recursos do lado do pai
+	go cmd.Wait()
+	return cmd.Process.Pid, nil
+}
+
+// signalShutdown envia SIGTERM ao processo anterior
+func signalShutdown(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
@@ -0,0 +1,253 @@
+package odata
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// MODO DE MANUTENÇÃO
+// =======================================================================================
+
+// Abrangência do modo de manutenção
+const (
+	MaintenanceWrites = "writes" // Recusa apenas operações de escrita (padrão)
+	MaintenanceAll    = "all"    // Recusa todas as requisições
+)
+
+// MaintenanceCode é o código de erro das requisições recusadas pelo modo de manutenção
+const MaintenanceCode = "MaintenanceMode"
+
+// MaintenanceConfig configura o modo de manutenção global. Ativo, as requisições afetadas
+// respondem 503 com a mensagem e o Retry-After (ServerConfig.MaintenanceRetryAfter), exceto
+// as de usuários com uma das roles ou de um dos IPs liberados
+type MaintenanceConfig struct {
+	Enabled      bool     // Ativa o modo de manutenção
+	Mode         string   // MaintenanceWrites (padrão) ou MaintenanceAll
+	Message      string   // Mensagem retornada às requisições recusadas
+	AllowedRoles []string // Roles liberadas durante a manutenção
+	AllowedIPs   []string // IPs ou redes CIDR liberados durante a manutenção
+	Signal       bool     // SIGUSR1 alterna o modo de manutenção (Unix)
+}
+
+// DefaultMaintenanceConfig retorna configuração padrão do modo de manutenção (desativado)
+func DefaultMaintenanceConfig() *MaintenanceConfig {
+	return &MaintenanceConfig{
+		Enabled: false,
+		Mode:    MaintenanceWrites,
+		Message: "Serviço em manutenção. Tente novamente mais tarde",
+	}
+}
+
+// SetMaintenance define a configuração do modo de manutenção
+func (s *Server) SetMaintenance(config *MaintenanceConfig) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config.Maintenance = config
+	return s
+}
+
+// SetMaintenanceMode ativa ou desativa o modo de manutenção em tempo de execução
+func (s *Server) SetMaintenanceMode(enabled bool) *Server {
+	s.updateMaintenance(func(config *MaintenanceConfig) { config.Enabled = enabled })
+	if enabled {
+		s.logger.Printf("🚧 Modo de manutenção ativado")
+	} else {
+		s.logger.Printf("✅ Modo de manutenção desativado")
+	}
+	return s
+}
+
+// GetMaintenance retorna uma cópia da configuração atual do modo de manutenção
+func (s *Server) GetMaintenance() MaintenanceConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.config.Maintenance == nil {
+		return *DefaultMaintenanceConfig()
+	}
+	return *s.config.Maintenance
+}
+
+// updateMaintenance altera a configuração sobre uma cópia, sem afetar requisições em andamento
+func (s *Server) updateMaintenance(update func(*MaintenanceConfig)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	config := *DefaultMaintenanceConfig()
+	if s.config.Maintenance != nil {
+		config = *s.config.Maintenance
+	}
+	update(&config)
+	s.config.Maintenance = &config
+}
+
+// toggleMaintenance alterna o modo de manutenção (sinal de manutenção)
+func (s *Server) toggleMaintenance() {
+	s.SetMaintenanceMode(!s.GetMaintenance().Enabled)
+}
+
+// MaintenanceMiddleware recusa as requisições afetadas pelo modo de manutenção. Rotas de
+// entidades e o $batch são verificados nos handlers, depois dos middlewares de autenticação
+// da entidade (roles liberadas); as rotas administrativas e o health check nunca são afetados
+func (s *Server) MaintenanceMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if !s.GetMaintenance().Enabled || s.maintenanceExempt(c.Path()) {
+			return c.Next()
+		}
+		if err := s.checkMaintenance(c, c.Method()); err != nil {
+			for header, value := range s.maintenanceHeaders(err) {
+				c.Set(header, value)
+			}
+			return s.writeODataError(c, err.Status, err, nil)
+		}
+		return c.Next()
+	}
+}
+
+// maintenanceExempt verifica se a rota é verificada depois (entidades e $batch) ou não é
+// afetada pela manutenção (administração e health check)
+func (s *Server) maintenanceExempt(path string) bool {
+	if path == "/health" || path == s.config.RoutePrefix+"/$batch" {
+		return true
+	}
+	adminPrefix := "/admin"
+	if s.config.DiagnosticsConfig != nil && s.config.DiagnosticsConfig.AdminPrefix != "" {
+		adminPrefix = s.config.DiagnosticsConfig.AdminPrefix
+	}
+	if path == adminPrefix || strings.HasPrefix(path, adminPrefix+"/") {
+		return true
+	}
+	entityName, _, _ := strings.Cut(s.extractEntityName(path), "/")
+	return s.GetEntityService(entityName) != nil
+}
+
+// checkMaintenance recusa a operação se o modo de manutenção a afetar e a requisição não
+// vier de um IP ou usuário liberado
+func (s *Server) checkMaintenance(c fiber.Ctx, operation string) *ODataError {
+	config := s.GetMaintenance()
+	if !maintenanceAffects(config, operation) || maintenanceAllowed(config, c) {
+		return nil
+	}
+	return maintenanceError(config)
+}
+
+// checkMaintenanceOperation recusa uma operação de uma requisição já verificada por
+// checkMaintenance (ex: operações do $batch de um cliente não liberado)
+func (s *Server) checkMaintenanceOperation(operation string) *ODataError {
+	config := s.GetMaintenance()
+	if !maintenanceAffects(config, operation) {
+		return nil
+	}
+	return maintenanceError(config)
+}
+
+// maintenanceAffects verifica se a operação é recusada pelo modo de manutenção
+func maintenanceAffects(config MaintenanceConfig, operation string) bool {
+	if !config.Enabled {
+		return false
+	}
+	if config.Mode == MaintenanceAll {
+		return true
+	}
+	switch operation {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// maintenanceAllowed verifica se o IP ou o usuário da requisição estão liberados
+func maintenanceAllowed(config MaintenanceConfig, c fiber.Ctx) bool {
+	if user := GetCurrentUser(c); user != nil && len(config.AllowedRoles) > 0 && user.HasAnyRole(config.AllowedRoles...) {
+		return true
+	}
+	if len(config.AllowedIPs) == 0 {
+		return false
+	}
+	ip := net.ParseIP(c.IP())
+	if ip == nil {
+		return false
+	}
+	for _, allowed := range config.AllowedIPs {
+		if _, network, err := net.ParseCIDR(allowed); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+		} else if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// maintenanceError cria o erro 503 do modo de manutenção
+func maintenanceError(config MaintenanceConfig) *ODataError {
+	message := config.Message
+	if message == "" {
+		message = DefaultMaintenanceConfig().Message
+	}
+	return NewODataError(MaintenanceCode, message).WithStatus(http.StatusServiceUnavailable)
+}
+
+// =======================================================================================
+// ENDPOINT ADMINISTRATIVO
+// =======================================================================================
+
+// maintenancePatch alterações do modo de manutenção pelo endpoint administrativo
+type maintenancePatch struct {
+	Enabled      *bool     `json:"enabled"`
+	Mode         *string   `json:"mode"`
+	Message      *string   `json:"message"`
+	AllowedRoles *[]string `json:"allowedRoles"`
+	AllowedIPs   *[]string `json:"allowedIPs"`
+}
+
+// maintenanceStatus representação JSON do modo de manutenção
+func maintenanceStatus(config MaintenanceConfig) fiber.Map {
+	return fiber.Map{
+		"enabled":      config.Enabled,
+		"mode":         config.Mode,
+		"message":      config.Message,
+		"allowedRoles": config.AllowedRoles,
+		"allowedIPs":   config.AllowedIPs,
+	}
+}
+
+// handleAdminMaintenance retorna o estado do modo de manutenção
+func (s *Server) handleAdminMaintenance(c fiber.Ctx) error {
+	return c.JSON(maintenanceStatus(s.GetMaintenance()))
+}
+
+// handleAdminSetMaintenance altera o modo de manutenção ({"enabled": true, "mode": "all"})
+func (s *Server) handleAdminSetMaintenance(c fiber.Ctx) error {
+	var patch maintenancePatch
+	if err := c.Bind().Body(&patch); err != nil {
+		return s.writeODataError(c, http.StatusBadRequest,
+			NewODataError("BadRequest", "Invalid JSON"), err)
+	}
+	if patch.Mode != nil && *patch.Mode != MaintenanceWrites && *patch.Mode != MaintenanceAll {
+		return s.writeODataError(c, http.StatusBadRequest,
+			NewODataError("BadRequest", "mode must be 'writes' or 'all'"), nil)
+	}
+
+	s.updateMaintenance(func(config *MaintenanceConfig) {
+		if patch.Mode != nil {
+			config.Mode = *patch.Mode
+		}
+		if patch.Message != nil {
+			config.Message = *patch.Message
+		}
+		if patch.AllowedRoles != nil {
+			config.AllowedRoles = *patch.AllowedRoles
+		}
+		if patch.AllowedIPs != nil {
+			config.AllowedIPs = *patch.AllowedIPs
+		}
+	})
+	if patch.Enabled != nil {
+		s.SetMaintenanceMode(*patch.Enabled)
+	}
+	return c.JSON(maintenanceStatus(s.GetMaintenance()))
+}
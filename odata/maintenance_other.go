@@ -0,0 +1,8 @@
+//go:build !unix
+
+package odata
+
+import "os"
+
+// maintenanceSignal é nil fora de sistemas Unix: use o endpoint /admin/maintenance
+var maintenanceSignal os.Signal
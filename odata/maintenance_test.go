@@ -0,0 +1,125 @@
+package odata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_MaintenanceMode(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	app := fiber.New()
+	app.Use(server.MaintenanceMiddleware())
+	app.Post("/odata/Notify", func(c fiber.Ctx) error { return c.SendStatus(http.StatusNoContent) })
+	app.Get("/health", func(c fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+	// Autenticação na rota da entidade: executa depois do middleware de manutenção
+	entityAuth := func(c fiber.Ctx) error {
+		if role := c.Get("X-Role"); role != "" {
+			c.Locals(UserContextKey, &UserIdentity{Username: "dba", Roles: []string{role}})
+		}
+		return c.Next()
+	}
+	app.Get("/odata/OrderItems(*)", entityAuth, server.handleEntityById)
+	app.Delete("/odata/OrderItems(*)", entityAuth, server.handleEntityById)
+
+	request := func(method, path, role string) *http.Response {
+		req := httptest.NewRequest(method, path, nil)
+		if role != "" {
+			req.Header.Set("X-Role", role)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	server.SetMaintenance(&MaintenanceConfig{Mode: MaintenanceWrites, Message: "Migração em andamento", AllowedRoles: []string{"dba"}})
+	assert.Equal(t, http.StatusNoContent, request(http.MethodPost, "/odata/Notify", "").StatusCode, "desativado")
+
+	server.SetMaintenanceMode(true)
+
+	t.Run("Escritas recusadas", func(t *testing.T) {
+		resp := request(http.MethodDelete, "/odata/OrderItems(1)", "")
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "60", resp.Header.Get(fiber.HeaderRetryAfter))
+		assert.Equal(t, int64(1), orderItemOwner(t, server, 1))
+
+		assert.Equal(t, http.StatusServiceUnavailable, request(http.MethodPost, "/odata/Notify", "").StatusCode)
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/odata/OrderItems(1)", "").StatusCode)
+	})
+
+	t.Run("Role liberada", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/odata/OrderItems(1)", "dba").StatusCode)
+	})
+
+	t.Run("Todas as requisições", func(t *testing.T) {
+		server.updateMaintenance(func(config *MaintenanceConfig) { config.Mode = MaintenanceAll })
+
+		assert.Equal(t, http.StatusServiceUnavailable, request(http.MethodGet, "/odata/OrderItems(2)", "").StatusCode)
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/health", "").StatusCode)
+	})
+
+	t.Run("IP liberado", func(t *testing.T) {
+		server.updateMaintenance(func(config *MaintenanceConfig) { config.AllowedIPs = []string{"10.0.0.1", "0.0.0.0/8"} })
+		defer server.updateMaintenance(func(config *MaintenanceConfig) { config.AllowedIPs = nil })
+
+		assert.Equal(t, http.StatusNoContent, request(http.MethodPost, "/odata/Notify", "").StatusCode)
+	})
+
+	t.Run("Operações do $batch", func(t *testing.T) {
+		server.updateMaintenance(func(config *MaintenanceConfig) { config.Mode = MaintenanceWrites })
+		processor := NewBatchProcessor(server)
+
+		resp, err := processor.executeOperation(context.Background(), &BatchHTTPOperation{Method: "DELETE", URL: "/odata/OrderItems(2)", ContentID: "1"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "Migração em andamento")
+
+		resp, err = processor.executeOperation(context.Background(), &BatchHTTPOperation{Method: "GET", URL: "/odata/OrderItems(2)", ContentID: "2"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		processor.maintenanceAllowed = true
+		resp, err = processor.executeOperation(context.Background(), &BatchHTTPOperation{Method: "DELETE", URL: "/odata/OrderItems(2)", ContentID: "3"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+}
+
+func TestServer_AdminMaintenance(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	server.SetDiagnostics(&DiagnosticsConfig{
+		Enabled:     true,
+		AdminPrefix: "/admin",
+		Auth: func(c fiber.Ctx) error {
+			c.Locals(UserContextKey, &UserIdentity{Username: "root", Admin: true})
+			return c.Next()
+		},
+	})
+
+	patch := func(body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPatch, "/admin/maintenance", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, http.StatusBadRequest, patch(`{"mode":"reads"}`).StatusCode)
+	assert.False(t, server.GetMaintenance().Enabled)
+
+	require.Equal(t, http.StatusOK, patch(`{"enabled":true,"mode":"all","allowedIPs":["192.168.0.0/16"]}`).StatusCode)
+	config := server.GetMaintenance()
+	assert.True(t, config.Enabled)
+	assert.Equal(t, MaintenanceAll, config.Mode)
+	assert.Equal(t, []string{"192.168.0.0/16"}, config.AllowedIPs)
+	assert.Equal(t, DefaultMaintenanceConfig().Message, config.Message)
+
+	server.toggleMaintenance()
+	assert.False(t, server.GetMaintenance().Enabled)
+}
@@ -0,0 +1,11 @@
+//go:build unix
+
+package odata
+
+import (
+	"os"
+	"syscall"
+)
+
+// maintenanceSignal é o sinal que alterna o modo de manutenção (MaintenanceConfig.Signal)
+var maintenanceSignal os.Signal = syscall.SIGUSR1
@@ -9,13 +9,21 @@ import (
 )
 
 // EntityMapper é responsável por mapear structs para metadados OData
-type EntityMapper struct{}
+type EntityMapper struct {
+	namingPolicy NamingPolicy
+}
 
 // NewEntityMapper cria uma nova instância do mapper
 func NewEntityMapper() *EntityMapper {
 	return &EntityMapper{}
 }
 
+// WithNamingPolicy define a política de nomes das propriedades mapeadas
+func (m *EntityMapper) WithNamingPolicy(policy NamingPolicy) *EntityMapper {
+	m.namingPolicy = policy
+	return m
+}
+
 // MapEntity mapeia uma struct para EntityMetadata usando tags
 func (m *EntityMapper) MapEntity(entity interface{}) (EntityMetadata, error) {
 	t := reflect.TypeOf(entity)
@@ -83,16 +91,13 @@ func (m *EntityMapper) mapField(field reflect.StructField) (*PropertyMetadata, e
 		return m.mapRelationship(field)
 	}
 
-	// Determina o nome da propriedade usando a tag JSON se disponível
-	propertyName := field.Name
-	if jsonTag := field.Tag.Get("json"); jsonTag != "" && jsonTag != "-" {
-		// Remove opções adicionais da tag JSON (como ,omitempty)
-		propertyName = strings.Split(jsonTag, ",")[0]
-	}
+	// Determina o nome da propriedade pela política de nomes (padrão: tag JSON)
+	propertyName := m.namingPolicy.propertyName(field)
 
 	prop := &PropertyMetadata{
-		Name: propertyName,
-		Type: m.mapGoType(field.Type),
+		Name:      propertyName,
+		FieldName: field.Name,
+		Type:      m.mapGoType(field.Type),
 	}
 
 	// Mapeia tags
@@ -105,15 +110,12 @@ func (m *EntityMapper) mapField(field reflect.StructField) (*PropertyMetadata, e
 
 // mapRelationship mapeia relacionamentos entre entidades
 func (m *EntityMapper) mapRelationship(field reflect.StructField) (*PropertyMetadata, error) {
-	// Determina o nome da propriedade usando a tag JSON se disponível
-	propertyName := field.Name
-	if jsonTag := field.Tag.Get("json"); jsonTag != "" && jsonTag != "-" {
-		// Remove opções adicionais da tag JSON (como ,omitempty)
-		propertyName = strings.Split(jsonTag, ",")[0]
-	}
+	// Determina o nome da propriedade pela política de nomes (padrão: tag JSON)
+	propertyName := m.namingPolicy.propertyName(field)
 
 	prop := &PropertyMetadata{
 		Name:         propertyName,
+		FieldName:    field.Name,
 		Type:         "relationship",
 		IsNavigation: true,
 	}
@@ -215,13 +217,18 @@ func (m *EntityMapper) mapTags(field reflect.StructField, prop *PropertyMetadata
 
 		// Processa as flags para definir propriedades do metadata
 		for _, flag := range propFlags {
-			switch flag {
-			case "Required":
+			switch strings.ToLower(flag) {
+			case "required":
 				prop.IsNullable = false
-			case "Unique":
-				// Será processado pelos providers
+			case "unique":
+				// Será processado pelos providers e pela geração de DDL
+			case "encrypted":
+				prop.IsEncrypted = true
 			}
 		}
+
+		// Processa as opções (ex: "[required]; length:100; default" ou "length:100")
+		m.parsePropOptions(propTag, prop)
 	}
 
 	// Tag odata
@@ -246,12 +253,15 @@ func (m *EntityMapper) mapTags(field reflect.StructField, prop *PropertyMetadata
 
 // parseODataTag processa a tag odata
 func (m *EntityMapper) parseODataTag(odata string, prop *PropertyMetadata) error {
-	parts := strings.Split(odata, ";")
+	// Aceita opções separadas por ";" ou "," (ex: "filterable,sortable,searchable")
+	parts := strings.FieldsFunc(odata, func(r rune) bool { return r == ';' || r == ',' })
 
 	for _, part := range parts {
 		part = strings.TrimSpace(part)
 
 		switch {
+		case part == "searchable":
+			prop.IsSearchable = true
 		case part == "not null":
 			prop.IsNullable = false
 		case part == "null":
@@ -270,6 +280,8 @@ func (m *EntityMapper) parseODataTag(odata string, prop *PropertyMetadata) error
 			if scale, err := strconv.Atoi(strings.TrimPrefix(part, "scale:")); err == nil {
 				prop.Scale = scale
 			}
+		case strings.HasPrefix(part, "enum:"):
+			prop.EnumValues = parseEnumValues(strings.TrimPrefix(part, "enum:"))
 		}
 	}
 
@@ -417,21 +429,76 @@ func (m *EntityMapper) parseCascade(cascade string) ([]string, error) {
 
 // parseProp processa a tag prop
 func (m *EntityMapper) parseProp(propTag string) ([]string, error) {
-	// Remove colchetes se existirem
-	propTag = strings.Trim(propTag, "[]")
+	// Segmentos separados por ';': flags ("[required, unique]") ou opções ("length:100")
+	var propFlags []string
+	for _, segment := range strings.Split(propTag, ";") {
+		segment = strings.TrimSpace(segment)
+		if isPropOption(segment) {
+			continue
+		}
 
-	// Divide por vírgulas
-	parts := strings.Split(propTag, ",")
+		// Remove colchetes se existirem e divide por vírgulas
+		for _, part := range strings.Split(strings.Trim(segment, "[]"), ",") {
+			part = strings.TrimSpace(part)
+			if part != "" {
+				propFlags = append(propFlags, part)
+			}
+		}
+	}
 
-	var propFlags []string
-	for _, part := range parts {
+	return propFlags, nil
+}
+
+// isPropOption verifica se o segmento da tag prop é uma opção (chave:valor ou default)
+// e não uma lista de flags. Tags só com opções (prop:"length:100") não têm flags
+func isPropOption(segment string) bool {
+	return segment == "default" || strings.Contains(segment, ":")
+}
+
+// parsePropOptions processa as opções da tag prop (length, precision, scale, default, encryption),
+// em qualquer segmento da tag
+func (m *EntityMapper) parsePropOptions(propTag string, prop *PropertyMetadata) {
+	for _, part := range strings.Split(propTag, ";") {
 		part = strings.TrimSpace(part)
-		if part != "" {
-			propFlags = append(propFlags, part)
+		if !isPropOption(part) {
+			continue
+		}
+
+		switch {
+		case part == "default":
+			prop.HasDefault = true
+		case strings.HasPrefix(part, "default:"):
+			prop.HasDefault = true
+			prop.DefaultValue = strings.TrimSpace(strings.TrimPrefix(part, "default:"))
+		case strings.HasPrefix(part, "length:"):
+			if length, err := strconv.Atoi(strings.TrimPrefix(part, "length:")); err == nil {
+				prop.MaxLength = length
+			}
+		case strings.HasPrefix(part, "precision:"):
+			if precision, err := strconv.Atoi(strings.TrimPrefix(part, "precision:")); err == nil {
+				prop.Precision = precision
+			}
+		case strings.HasPrefix(part, "scale:"):
+			if scale, err := strconv.Atoi(strings.TrimPrefix(part, "scale:")); err == nil {
+				prop.Scale = scale
+			}
+		case strings.EqualFold(part, "encryption:deterministic"):
+			prop.DeterministicEncryption = true
+		case strings.HasPrefix(part, "enum:"):
+			prop.EnumValues = parseEnumValues(strings.TrimPrefix(part, "enum:"))
 		}
 	}
+}
 
-	return propFlags, nil
+// parseEnumValues processa os valores permitidos da opção enum (ex: "ativo|inativo")
+func parseEnumValues(value string) []string {
+	var values []string
+	for _, member := range strings.Split(value, "|") {
+		if member = strings.TrimSpace(member); member != "" {
+			values = append(values, member)
+		}
+	}
+	return values
 }
 
 // parseTableName processa o nome da tabela e schema
@@ -496,7 +563,7 @@ func (m *EntityMapper) isPrimitiveType(t reflect.Type) bool {
 	}
 
 	switch t {
-	case reflect.TypeOf(time.Time{}):
+	case reflect.TypeOf(time.Time{}), reflect.TypeOf(Decimal{}):
 		return true
 	default:
 		return false
@@ -550,6 +617,9 @@ func (m *EntityMapper) mapGoType(t reflect.Type) string {
 		if t == reflect.TypeOf(time.Time{}) {
 			return "time.Time"
 		}
+		if t == reflect.TypeOf(Decimal{}) {
+			return "decimal"
+		}
 		return "object"
 	default:
 		return "string"
@@ -608,6 +678,11 @@ func MapEntityFromStruct(entity interface{}) (EntityMetadata, error) {
 	return mapper.MapEntity(entity)
 }
 
+// MapEntityWithNamingPolicy mapeia a struct gerando os nomes das propriedades pela política
+func MapEntityWithNamingPolicy(entity interface{}, policy NamingPolicy) (EntityMetadata, error) {
+	return NewEntityMapper().WithNamingPolicy(policy).MapEntity(entity)
+}
+
 // isMetadataField verifica se um campo é de metadados
 func (m *EntityMapper) isMetadataField(field reflect.StructField) bool {
 	// Lista de nomes de campos de metadados
@@ -526,6 +526,50 @@ func TestMapEntity_PropFlags(t *testing.T) {
 	})
 }
 
+func TestMapEntity_PropOptionOnlyTags(t *testing.T) {
+	type Product struct {
+		ID         int64  `json:"id" primaryKey:"idGenerator:auto"`
+		Category   string `json:"category" prop:"length:100"`
+		SalesCount int    `json:"sales_count" prop:"default:0"`
+		Code       string `json:"code" prop:"length:20; [required, unique]"`
+		Legacy     string `json:"legacy" prop:"[required]; length:30"`
+	}
+
+	metadata, err := NewEntityMapper().MapEntity(Product{})
+	require.NoError(t, err)
+
+	findProp := func(name string) *PropertyMetadata {
+		for i := range metadata.Properties {
+			if metadata.Properties[i].Name == name {
+				return &metadata.Properties[i]
+			}
+		}
+		return nil
+	}
+
+	category := findProp("category")
+	require.NotNil(t, category)
+	assert.Equal(t, 100, category.MaxLength)
+	assert.Empty(t, category.PropFlags, "opções não são flags")
+
+	sales := findProp("sales_count")
+	require.NotNil(t, sales)
+	assert.True(t, sales.HasDefault)
+	assert.Equal(t, "0", sales.DefaultValue)
+	assert.Empty(t, sales.PropFlags)
+
+	code := findProp("code")
+	require.NotNil(t, code)
+	assert.Equal(t, 20, code.MaxLength)
+	assert.Equal(t, []string{"required", "unique"}, code.PropFlags)
+	assert.False(t, code.IsNullable)
+
+	legacy := findProp("legacy")
+	require.NotNil(t, legacy)
+	assert.Equal(t, 30, legacy.MaxLength)
+	assert.Equal(t, []string{"required"}, legacy.PropFlags)
+}
+
 func TestMapEntity_MetadataFieldsIgnored(t *testing.T) {
 	mapper := NewEntityMapper()
 	user := UserWithTable{}
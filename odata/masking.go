@@ -0,0 +1,226 @@
+package odata
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// =======================================================================================
+// MÁSCARA DE PROPRIEDADES SENSÍVEIS NAS RESPOSTAS
+// =======================================================================================
+
+// MaskFunc mascara o valor de uma propriedade sensível na resposta
+type MaskFunc func(value any) any
+
+// MaskingPolicy define como uma propriedade é mascarada e quem vê o valor original
+type MaskingPolicy struct {
+	Mask MaskFunc
+	// Roles que veem o valor original. Administradores (UserIdentity.Admin) sempre veem
+	UnmaskedRoles []string
+}
+
+// propertyMask é a política de máscara registrada para uma propriedade
+type propertyMask struct {
+	property string
+	policy   MaskingPolicy
+}
+
+// SetMaskingPolicy registra a máscara de uma propriedade da entidade. A máscara é aplicada na
+// serialização das respostas (inclusive entidades expandidas e operações GET do $batch),
+// exceto para usuários com uma das roles da política. Filtros e gravações usam o valor real
+func (s *Server) SetMaskingPolicy(entityName, propertyName string, policy MaskingPolicy) error {
+	if policy.Mask == nil {
+		return fmt.Errorf("masking policy for '%s' requires a mask function", propertyName)
+	}
+
+	service := s.GetEntityService(entityName)
+	if service == nil {
+		return fmt.Errorf("entity '%s' not found", entityName)
+	}
+	prop := findPropertyByName(service.GetMetadata(), propertyName)
+	if prop == nil {
+		return fmt.Errorf("property '%s' not found in entity '%s'", propertyName, entityName)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maskingPolicies == nil {
+		s.maskingPolicies = make(map[string][]propertyMask)
+	}
+	masks := s.maskingPolicies[entityName]
+	for i, existing := range masks {
+		if existing.property == prop.Name {
+			masks[i].policy = policy
+			return nil
+		}
+	}
+	s.maskingPolicies[entityName] = append(masks, propertyMask{property: prop.Name, policy: policy})
+	return nil
+}
+
+// MaskAll substitui o valor por "****", sem revelar o tamanho
+func MaskAll(value any) any {
+	if value == nil {
+		return nil
+	}
+	return "****"
+}
+
+// MaskKeepLast mantém os últimos n caracteres e mascara os demais
+func MaskKeepLast(n int) MaskFunc {
+	return func(value any) any {
+		if value == nil {
+			return nil
+		}
+		runes := []rune(fmt.Sprint(value))
+		for i := 0; i < len(runes)-n; i++ {
+			runes[i] = '*'
+		}
+		return string(runes)
+	}
+}
+
+// MaskEmail mantém a primeira letra e o domínio do email (ex: a********@example.com)
+func MaskEmail(value any) any {
+	if value == nil {
+		return nil
+	}
+	email := fmt.Sprint(value)
+	local, domain, found := strings.Cut(email, "@")
+	if !found || local == "" {
+		return MaskAll(value)
+	}
+	runes := []rune(local)
+	return string(runes[0]) + strings.Repeat("*", len(runes)-1) + "@" + domain
+}
+
+// MaskCPF mascara os dígitos do CPF exceto os verificadores, mantendo a formatação
+// (ex: ***.***.***-09)
+func MaskCPF(value any) any {
+	return maskDigits(value, 2)
+}
+
+// MaskPhone mascara os dígitos do telefone exceto os 4 últimos, mantendo a formatação
+// (ex: (**) *****-4321)
+func MaskPhone(value any) any {
+	return maskDigits(value, 4)
+}
+
+// maskDigits mascara os dígitos do valor, exceto os últimos keep, preservando os separadores
+func maskDigits(value any, keep int) any {
+	if value == nil {
+		return nil
+	}
+	runes := []rune(fmt.Sprint(value))
+
+	digits := 0
+	for _, r := range runes {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+
+	for i, r := range runes {
+		if !unicode.IsDigit(r) {
+			continue
+		}
+		if digits > keep {
+			runes[i] = '*'
+		}
+		digits--
+	}
+	return string(runes)
+}
+
+// hasMaskingPolicies verifica se há alguma máscara registrada
+func (s *Server) hasMaskingPolicies() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.maskingPolicies) > 0
+}
+
+// getMaskingPolicies retorna as máscaras registradas para a entidade
+func (s *Server) getMaskingPolicies(entityName string) []propertyMask {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.maskingPolicies[entityName]
+}
+
+// isUnmasked verifica se o usuário vê o valor original da propriedade
+func (policy MaskingPolicy) isUnmasked(user *UserIdentity) bool {
+	return user != nil && (user.Admin || user.HasAnyRole(policy.UnmaskedRoles...))
+}
+
+// applyMaskingPolicies mascara as propriedades sensíveis da resposta para o usuário.
+// Entidades são alteradas no lugar, pois pertencem à requisição
+func (s *Server) applyMaskingPolicies(user *UserIdentity, service EntityService, body interface{}) {
+	if service == nil || !s.hasMaskingPolicies() {
+		return
+	}
+	s.maskValue(user, service.GetMetadata(), body)
+}
+
+// maskValue percorre a resposta aplicando as máscaras da entidade
+func (s *Server) maskValue(user *UserIdentity, metadata EntityMetadata, value interface{}) {
+	switch v := value.(type) {
+	case *ODataResponse:
+		if v != nil {
+			s.maskValue(user, metadata, v.Value)
+		}
+	case []interface{}:
+		for _, item := range v {
+			s.maskValue(user, metadata, item)
+		}
+	case []*OrderedEntity:
+		for _, item := range v {
+			s.maskValue(user, metadata, item)
+		}
+	case *OrderedEntity:
+		if v != nil {
+			for _, prop := range v.Properties {
+				if masked, ok := s.maskField(user, metadata, prop.Name, prop.Value); ok {
+					v.Set(prop.Name, masked)
+				}
+			}
+		}
+	case *OrderedEntityResponse:
+		if v != nil {
+			for i := range v.Fields {
+				if masked, ok := s.maskField(user, metadata, v.Fields[i].Name, v.Fields[i].Value); ok {
+					v.Fields[i].Value = masked
+				}
+			}
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			if masked, ok := s.maskField(user, metadata, key, item); ok {
+				v[key] = masked
+			}
+		}
+	}
+}
+
+// maskField retorna o valor mascarado do campo, ou percorre a entidade expandida quando o
+// campo é uma navegação
+func (s *Server) maskField(user *UserIdentity, metadata EntityMetadata, name string, value interface{}) (interface{}, bool) {
+	for _, mask := range s.getMaskingPolicies(metadata.Name) {
+		if strings.EqualFold(mask.property, name) {
+			if mask.policy.isUnmasked(user) {
+				return nil, false
+			}
+			return mask.policy.Mask(value), true
+		}
+	}
+
+	for _, prop := range metadata.Properties {
+		if prop.IsNavigation && strings.EqualFold(prop.Name, name) {
+			if related := s.relatedEntityService(prop.RelatedType); related != nil {
+				s.maskValue(user, related.GetMetadata(), value)
+			}
+			break
+		}
+	}
+	return nil, false
+}
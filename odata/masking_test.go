@@ -0,0 +1,114 @@
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskFunctions(t *testing.T) {
+	assert.Equal(t, "a********@example.com", MaskEmail("ana.silva@example.com"))
+	assert.Equal(t, "****", MaskEmail("invalido"))
+	assert.Equal(t, "***.***.***-09", MaskCPF("123.456.789-09"))
+	assert.Equal(t, "*********09", MaskCPF("12345678909"))
+	assert.Equal(t, "(**) *****-4321", MaskPhone("(11) 98765-4321"))
+	assert.Equal(t, "****", MaskAll(42))
+	assert.Equal(t, "*****ra", MaskKeepLast(2)("Cadeira"))
+	assert.Nil(t, MaskEmail(nil))
+	assert.Nil(t, MaskKeepLast(2)(nil))
+}
+
+func TestServer_SetMaskingPolicy_Validation(t *testing.T) {
+	server := newBatchGetTestServer(t)
+
+	assert.Error(t, server.SetMaskingPolicy("Unknown", "name", MaskingPolicy{Mask: MaskAll}))
+	assert.Error(t, server.SetMaskingPolicy("Products", "unknown", MaskingPolicy{Mask: MaskAll}))
+	assert.Error(t, server.SetMaskingPolicy("Products", "name", MaskingPolicy{}))
+	assert.NoError(t, server.SetMaskingPolicy("Products", "NAME", MaskingPolicy{Mask: MaskAll}))
+	assert.NoError(t, server.SetMaskingPolicy("Products", "name", MaskingPolicy{Mask: MaskKeepLast(2)}), "substitui a política")
+	assert.Len(t, server.getMaskingPolicies("Products"), 1)
+}
+
+func TestServer_MaskingPolicies(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.router.Use(func(c fiber.Ctx) error {
+		switch c.Get("X-Test-User") {
+		case "manager":
+			c.Locals(UserContextKey, &UserIdentity{Username: "ana", Roles: []string{"manager"}})
+		case "admin":
+			c.Locals(UserContextKey, &UserIdentity{Username: "root", Admin: true})
+		case "user":
+			c.Locals(UserContextKey, &UserIdentity{Username: "bruno", Roles: []string{"user"}})
+		}
+		return c.Next()
+	})
+	server.setupEntityRoutes("Products")
+
+	require.NoError(t, server.SetMaskingPolicy("Products", "name", MaskingPolicy{
+		Mask:          MaskKeepLast(2),
+		UnmaskedRoles: []string{"manager"},
+	}))
+
+	get := func(t *testing.T, path, user string) map[string]any {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Test-User", user)
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+
+	t.Run("Mascara para usuários sem a role", func(t *testing.T) {
+		for _, user := range []string{"", "user"} {
+			body := get(t, "/odata/Products?$orderby=id", user)
+			first := body["value"].([]any)[0].(map[string]any)
+			assert.Equal(t, "***se", first["name"], user)
+			assert.Equal(t, float64(10), first["price"])
+		}
+	})
+
+	t.Run("Role liberada e administrador veem o valor original", func(t *testing.T) {
+		for _, user := range []string{"manager", "admin"} {
+			body := get(t, "/odata/Products?$orderby=id", user)
+			assert.Equal(t, "Mouse", body["value"].([]any)[0].(map[string]any)["name"], user)
+		}
+	})
+
+	t.Run("Entidade por chave", func(t *testing.T) {
+		body := get(t, "/odata/Products(2)", "user")
+		assert.Equal(t, "*****do", body["name"])
+	})
+
+	t.Run("Filtro usa o valor real", func(t *testing.T) {
+		body := get(t, "/odata/Products?$filter="+url.QueryEscape("name eq 'Monitor'"), "user")
+		values := body["value"].([]any)
+		require.Len(t, values, 1)
+		assert.Equal(t, "*****or", values[0].(map[string]any)["name"])
+	})
+
+	t.Run("Operações GET do $batch", func(t *testing.T) {
+		processor := NewBatchProcessor(server)
+		resp, err := processor.executeOperation(context.Background(), &BatchHTTPOperation{Method: "GET", URL: "/odata/Products(1)"}, map[string]interface{}{})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(resp.Body))
+		assert.Contains(t, string(resp.Body), `"name":"***se"`)
+
+		processor.user = &UserIdentity{Username: "ana", Roles: []string{"manager"}}
+		resp, err = processor.executeOperation(context.Background(), &BatchHTTPOperation{Method: "GET", URL: "/odata/Products(1)"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Contains(t, string(resp.Body), `"name":"Mouse"`)
+	})
+}
@@ -0,0 +1,302 @@
+package odata
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// ODATA.METADATA: NÍVEL DAS ANOTAÇÕES DE CONTROLE NA RESPOSTA
+// =======================================================================================
+
+// MetadataLevel é o nível de anotações de controle pedido pelo cliente no parâmetro
+// odata.metadata do Accept (ou do $format)
+type MetadataLevel string
+
+const (
+	// MetadataMinimal inclui apenas o @odata.context, @odata.count e @odata.nextLink (padrão)
+	MetadataMinimal MetadataLevel = "minimal"
+	// MetadataFull inclui também @odata.type, @odata.id, @odata.editLink e os
+	// @odata.navigationLink de cada entidade
+	MetadataFull MetadataLevel = "full"
+	// MetadataNone remove as anotações, exceto @odata.count e @odata.nextLink
+	MetadataNone MetadataLevel = "none"
+)
+
+// parseMetadataLevel extrai o odata.metadata do media type (Accept, Content-Type ou
+// $format). O Accept pode ter vários media ranges; vale o primeiro com o parâmetro
+func parseMetadataLevel(mediaType string) (MetadataLevel, bool) {
+	for _, mediaRange := range strings.Split(mediaType, ",") {
+		for _, param := range strings.Split(mediaRange, ";") {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || !strings.EqualFold(strings.TrimSpace(name), "odata.metadata") {
+				continue
+			}
+			switch level := MetadataLevel(strings.ToLower(strings.Trim(strings.TrimSpace(value), `"`))); level {
+			case MetadataMinimal, MetadataFull, MetadataNone:
+				return level, true
+			}
+		}
+	}
+	return MetadataMinimal, false
+}
+
+// metadataLevel retorna o nível pedido no $format ou no Accept. Sem o parâmetro, ou com
+// um valor desconhecido, a resposta usa o nível minimal
+func (s *Server) metadataLevel(c fiber.Ctx) (MetadataLevel, bool) {
+	if level, ok := parseMetadataLevel(c.Query("$format")); ok {
+		return level, true
+	}
+	return parseMetadataLevel(c.Get(fiber.HeaderAccept))
+}
+
+// setMetadataContentType inclui o odata.metadata pedido no Content-Type da resposta
+func setMetadataContentType(c fiber.Ctx, level MetadataLevel) {
+	contentType := string(c.Response().Header.ContentType())
+	if _, ok := parseMetadataLevel(contentType); ok || contentType == "" {
+		return
+	}
+	c.Response().Header.SetContentType(contentType + ";odata.metadata=" + string(level))
+}
+
+// applyMetadataLevel ajusta as anotações da resposta ao nível pedido, com URLs absolutas no
+// @odata.context, @odata.id, @odata.editLink e @odata.navigationLink, e inclui as anotações
+// customizadas aceitas pelo odata.include-annotations. Entidades e mapas são alterados no
+// lugar, pois pertencem à requisição
+func (s *Server) applyMetadataLevel(c fiber.Ctx, level MetadataLevel, service EntityService, body interface{}) {
+	writer := &metadataLevelWriter{
+		server:      s,
+		level:       level,
+		annotations: newAnnotationFilter(c.Get("Prefer"), level),
+		envelope:    responseAnnotations(c),
+	}
+	if service == nil {
+		// Respostas sem entity set recebem apenas as anotações do envelope
+		if response, ok := body.(*ODataResponse); ok && response != nil {
+			response.Annotations = writer.annotations.filter(append(response.Annotations, writer.envelope...))
+		}
+		return
+	}
+	writer.links = s.newEntityLinks(c)
+	if level != MetadataNone {
+		writer.projection = contextProjection(c)
+	}
+	writer.apply(service, body, true)
+}
+
+// metadataLevelWriter percorre a resposta aplicando o nível às entidades e às navegações
+// expandidas
+type metadataLevelWriter struct {
+	server     *Server
+	level      MetadataLevel
+	links      *entityLinks
+	projection string // Lista de seleção do @odata.context ($select/$expand)
+
+	annotations *annotationFilter // odata.include-annotations do Prefer
+	envelope    []OrderedProperty // Anotações de AddResponseAnnotation
+}
+
+// apply aplica o nível ao valor; root indica o corpo da resposta (único com @odata.context)
+func (w *metadataLevelWriter) apply(service EntityService, value interface{}, root bool) {
+	switch v := value.(type) {
+	case *ODataResponse:
+		if v == nil {
+			return
+		}
+		v.Context = w.context(service, v.Context, root, false)
+		if root {
+			v.Annotations = append(v.Annotations, w.envelope...)
+		}
+		v.Annotations = w.annotations.filter(v.Annotations)
+		w.apply(service, v.Value, false)
+	case []interface{}:
+		for _, item := range v {
+			w.apply(service, item, false)
+		}
+	case []*OrderedEntity:
+		for _, item := range v {
+			w.apply(service, item, false)
+		}
+	case *OrderedEntity:
+		if v == nil {
+			return
+		}
+		annotations, links := w.controlInformation(service, v.Get, v.NavigationLinks)
+		v.Annotations = w.entityAnnotations(annotations, v.Annotations, root)
+		v.NavigationLinks = links
+		for _, prop := range v.Properties {
+			w.applyNavigation(service, prop.Name, prop.Value)
+		}
+	case *OrderedEntityResponse:
+		if v == nil {
+			return
+		}
+		v.Context = w.context(service, v.Context, root, true)
+		fields := make(map[string]interface{}, len(v.Fields))
+		for _, field := range v.Fields {
+			fields[field.Name] = field.Value
+		}
+		links := make([]NavigationLink, len(v.NavigationLinks))
+		for i, link := range v.NavigationLinks {
+			links[i] = NavigationLink{Name: link.Name, URL: link.URL}
+		}
+		annotations, links := w.controlInformation(service, mapGetter(fields), links)
+		v.Annotations = w.entityAnnotations(annotations, v.Annotations, root)
+		v.NavigationLinks = v.NavigationLinks[:0]
+		for _, link := range links {
+			v.AddNavigationLink(link.Name, link.URL)
+		}
+		for _, field := range v.Fields {
+			w.applyNavigation(service, field.Name, field.Value)
+		}
+	case map[string]interface{}:
+		if context, ok := v["@odata.context"].(string); ok {
+			if context = w.context(service, context, root, true); context != "" {
+				v["@odata.context"] = context
+			} else {
+				delete(v, "@odata.context")
+			}
+		}
+		var links []NavigationLink
+		for key, item := range v {
+			if name, ok := strings.CutSuffix(key, "@odata.navigationLink"); ok {
+				url, _ := item.(string)
+				links = append(links, NavigationLink{Name: name, URL: url})
+				delete(v, key)
+			}
+		}
+		annotations, links := w.controlInformation(service, mapGetter(v), links)
+		for key := range v {
+			if isCustomAnnotation(key) && !w.annotations.includes(key) {
+				delete(v, key)
+			}
+		}
+		if root {
+			annotations = append(annotations, w.annotations.filter(append([]OrderedProperty(nil), w.envelope...))...)
+		}
+		for _, annotation := range annotations {
+			v[annotation.Name] = annotation.Value
+		}
+		for _, link := range links {
+			v[link.Name+"@odata.navigationLink"] = link.URL
+		}
+		for key, item := range v {
+			w.applyNavigation(service, key, item)
+		}
+	}
+}
+
+// entityAnnotations junta as anotações de controle às anotações já presentes na entidade (e,
+// no corpo da resposta, às do envelope), removendo as customizadas não incluídas
+func (w *metadataLevelWriter) entityAnnotations(control, existing []OrderedProperty, root bool) []OrderedProperty {
+	annotations := control
+	if root {
+		existing = append(existing, w.envelope...)
+	}
+	for _, annotation := range w.annotations.filter(existing) {
+		annotations = setOrderedProperty(annotations, annotation.Name, annotation.Value)
+	}
+	return annotations
+}
+
+// context retorna o @odata.context da resposta no nível pedido: absoluto e com a projeção
+// no corpo da resposta, removido com odata.metadata=none
+func (w *metadataLevelWriter) context(service EntityService, context string, root, entity bool) string {
+	if w.level == MetadataNone || context == "" {
+		return ""
+	}
+	if root && strings.HasPrefix(context, "$metadata") {
+		return w.links.contextURL(service, w.projection, entity)
+	}
+	return context
+}
+
+// controlInformation retorna as anotações de controle e os links de navegação da entidade no
+// nível pedido. Com full, a entidade recebe @odata.type, @odata.id e @odata.editLink (id e
+// editLink omitidos sem os valores das chaves, ex: $select sem a chave) e os links de
+// navegação passam a ser absolutos; nos demais níveis os links são removidos
+func (w *metadataLevelWriter) controlInformation(service EntityService, get func(name string) (interface{}, bool), links []NavigationLink) ([]OrderedProperty, []NavigationLink) {
+	if w.level != MetadataFull {
+		return nil, links[:0]
+	}
+
+	annotations := []OrderedProperty{{Name: "@odata.type", Value: "#Default." + w.links.route(service).setName}}
+	id, ok := w.links.entityID(service, get)
+	if !ok {
+		return annotations, links
+	}
+	annotations = append(annotations,
+		OrderedProperty{Name: "@odata.id", Value: id},
+		OrderedProperty{Name: "@odata.editLink", Value: id})
+
+	for i := range links {
+		links[i].URL = id + "/" + links[i].Name
+	}
+	return annotations, links
+}
+
+// applyNavigation aplica o nível às entidades de uma navegação expandida
+func (w *metadataLevelWriter) applyNavigation(service EntityService, name string, value interface{}) {
+	for _, prop := range service.GetMetadata().Properties {
+		if prop.IsNavigation && strings.EqualFold(prop.Name, name) {
+			if related := w.server.relatedEntityService(prop.RelatedType); related != nil {
+				w.apply(related, value, false)
+			}
+			return
+		}
+	}
+}
+
+// entityKeyPredicate monta o predicado de chave da entidade: (1), ('abc') ou
+// (Chave1=1,Chave2='abc') para chaves compostas
+func entityKeyPredicate(metadata EntityMetadata, get func(name string) (interface{}, bool)) (string, bool) {
+	var keys []PropertyMetadata
+	for _, prop := range metadata.Properties {
+		if prop.IsKey {
+			keys = append(keys, prop)
+		}
+	}
+	if len(keys) == 0 {
+		return "", false
+	}
+
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value, ok := get(key.Name)
+		if !ok || value == nil {
+			return "", false
+		}
+		literal := keyLiteral(value)
+		if len(keys) > 1 {
+			literal = key.Name + "=" + literal
+		}
+		values = append(values, literal)
+	}
+	return "(" + strings.Join(values, ",") + ")", true
+}
+
+// keyLiteral formata o valor da chave como literal OData (strings entre aspas simples,
+// com escape de aspas e dos caracteres reservados da URL)
+func keyLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "'" + url.PathEscape(strings.ReplaceAll(v, "'", "''")) + "'"
+	case []byte:
+		return "'" + url.PathEscape(strings.ReplaceAll(string(v), "'", "''")) + "'"
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// mapGetter adapta um map ao formato de leitura de OrderedEntity.Get
+func mapGetter(values map[string]interface{}) func(name string) (interface{}, bool) {
+	return func(name string) (interface{}, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
@@ -0,0 +1,121 @@
+package odata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetadataLevel(t *testing.T) {
+	level, ok := parseMetadataLevel("application/json;odata.metadata=full;IEEE754Compatible=true")
+	assert.True(t, ok)
+	assert.Equal(t, MetadataFull, level)
+
+	level, ok = parseMetadataLevel("text/plain, application/json; odata.metadata=NONE")
+	assert.True(t, ok)
+	assert.Equal(t, MetadataNone, level)
+
+	level, ok = parseMetadataLevel("application/json;odata.metadata=verbose")
+	assert.False(t, ok, "valor desconhecido")
+	assert.Equal(t, MetadataMinimal, level)
+
+	_, ok = parseMetadataLevel("application/json")
+	assert.False(t, ok)
+}
+
+func TestEntityKeyPredicate(t *testing.T) {
+	metadata := EntityMetadata{Properties: []PropertyMetadata{
+		{Name: "OrderID", IsKey: true},
+		{Name: "Code", IsKey: true},
+		{Name: "Name"},
+	}}
+	values := map[string]interface{}{"OrderID": int64(7), "Code": "O'Brien & Filhos"}
+
+	predicate, ok := entityKeyPredicate(metadata, mapGetter(values))
+	require.True(t, ok)
+	assert.Equal(t, "(OrderID=7,Code='O%27%27Brien%20&%20Filhos')", predicate)
+
+	_, ok = entityKeyPredicate(metadata, mapGetter(map[string]interface{}{"OrderID": int64(7)}))
+	assert.False(t, ok, "chave ausente")
+}
+
+func TestServer_MetadataLevel(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	metadata := server.entities["Products"].GetMetadata()
+	metadata.Properties = append(metadata.Properties, PropertyMetadata{Name: "Reviews", IsNavigation: true, IsCollection: true, RelatedType: "Reviews"})
+	server.entities["Products"] = NewBaseEntityService(server.provider, metadata, server)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	get := func(t *testing.T, path, accept string) (map[string]interface{}, string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &result))
+		return result, resp.Header.Get("Content-Type")
+	}
+	first := func(body map[string]interface{}) map[string]interface{} {
+		return body["value"].([]interface{})[0].(map[string]interface{})
+	}
+
+	t.Run("Minimal é o padrão e omite os navigationLinks", func(t *testing.T) {
+		body, contentType := get(t, "/odata/Products?$top=1", "")
+		assert.Equal(t, "http://example.com/odata/$metadata#Products", body["@odata.context"])
+		assert.NotContains(t, first(body), "Reviews@odata.navigationLink")
+		assert.NotContains(t, first(body), "@odata.id")
+		assert.NotContains(t, contentType, "odata.metadata")
+	})
+
+	t.Run("Full adiciona tipo, id, editLink e navigationLinks", func(t *testing.T) {
+		body, contentType := get(t, "/odata/Products?$top=1", "application/json;odata.metadata=full")
+		entity := first(body)
+		assert.Equal(t, "#Default.Products", entity["@odata.type"])
+		assert.Equal(t, "http://example.com/odata/Products(1)", entity["@odata.id"])
+		assert.Equal(t, "http://example.com/odata/Products(1)", entity["@odata.editLink"])
+		assert.Equal(t, "http://example.com/odata/Products(1)/Reviews", entity["Reviews@odata.navigationLink"])
+		assert.Contains(t, contentType, "odata.metadata=full")
+	})
+
+	t.Run("Full na entidade única", func(t *testing.T) {
+		body, _ := get(t, "/odata/Products(2)?$format=application/json;odata.metadata=full", "")
+		assert.Equal(t, "http://example.com/odata/$metadata#Products/$entity", body["@odata.context"])
+		assert.Equal(t, "http://example.com/odata/Products(2)", body["@odata.id"])
+		assert.Equal(t, "http://example.com/odata/Products(2)/Reviews", body["Reviews@odata.navigationLink"])
+	})
+
+	t.Run("None remove as anotações e mantém o count", func(t *testing.T) {
+		body, contentType := get(t, "/odata/Products?$count=true&$top=1", "application/json;odata.metadata=none")
+		assert.NotContains(t, body, "@odata.context")
+		assert.Equal(t, float64(3), body["@odata.count"])
+		assert.Equal(t, map[string]interface{}{"id": float64(1), "name": "Mouse", "price": float64(10)}, first(body))
+		assert.Contains(t, contentType, "odata.metadata=none")
+
+		single, _ := get(t, "/odata/Products(2)", "application/json;odata.metadata=none")
+		assert.Equal(t, map[string]interface{}{"id": float64(2), "name": "Teclado", "price": float64(50)}, single)
+	})
+
+	t.Run("Serializador rápido", func(t *testing.T) {
+		server.config.FastJSONEncoding = true
+		defer func() { server.config.FastJSONEncoding = false }()
+
+		body, _ := get(t, "/odata/Products?$top=1", "application/json;odata.metadata=full")
+		entity := first(body)
+		assert.Equal(t, "http://example.com/odata/Products(1)", entity["@odata.id"])
+		assert.Equal(t, "http://example.com/odata/Products(1)/Reviews", entity["Reviews@odata.navigationLink"])
+	})
+}
@@ -0,0 +1,75 @@
+package odata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// TUNELAMENTO DE MÉTODOS (X-HTTP-Method)
+// =======================================================================================
+
+// Headers aceitos para tunelar PUT/PATCH/DELETE em um POST (clientes atrás de proxies ou
+// firewalls que bloqueiam esses métodos)
+const (
+	HeaderHTTPMethod         = "X-HTTP-Method"
+	HeaderHTTPMethodOverride = "X-HTTP-Method-Override"
+)
+
+// tunneledMethod retorna o método efetivo de uma requisição: o informado no X-HTTP-Method
+// quando o método real é POST (MERGE, do OData v3, equivale a PATCH). Sem override, ou com
+// o tunelamento desabilitado, retorna o próprio método
+func (s *Server) tunneledMethod(method, override string) (string, error) {
+	override = strings.ToUpper(strings.TrimSpace(override))
+	if override == "" || method != fiber.MethodPost || (s.config != nil && s.config.DisableMethodOverride) {
+		return method, nil
+	}
+
+	switch override {
+	case fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return override, nil
+	case "MERGE":
+		return fiber.MethodPatch, nil
+	}
+	return "", fmt.Errorf("method '%s' cannot be tunneled through %s", override, HeaderHTTPMethod)
+}
+
+// MethodOverrideMiddleware aplica o X-HTTP-Method (ou X-HTTP-Method-Override) dos POSTs
+// antes do roteamento, de modo que a requisição é tratada pelas rotas do método tunelado.
+// Desabilitado com ServerConfig.DisableMethodOverride
+func (s *Server) MethodOverrideMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		override := c.Get(HeaderHTTPMethod)
+		if override == "" {
+			override = c.Get(HeaderHTTPMethodOverride)
+		}
+		method, err := s.tunneledMethod(c.Method(), override)
+		if err != nil {
+			s.writeError(c, fiber.StatusBadRequest, "InvalidMethodOverride", err.Error())
+			return nil
+		}
+		if method != c.Method() {
+			c.Method(method)
+		}
+		return c.Next()
+	}
+}
+
+// tunnelBatchOperation aplica o X-HTTP-Method de uma operação do $batch
+func (bp *BatchProcessor) tunnelBatchOperation(operation *BatchHTTPOperation) error {
+	if bp.server == nil {
+		return nil
+	}
+	override := headerValue(operation.Headers, HeaderHTTPMethod)
+	if override == "" {
+		override = headerValue(operation.Headers, HeaderHTTPMethodOverride)
+	}
+	method, err := bp.server.tunneledMethod(operation.Method, override)
+	if err != nil {
+		return err
+	}
+	operation.Method = method
+	return nil
+}
@@ -0,0 +1,85 @@
+package odata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_MethodOverride(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.router.Use(server.MethodOverrideMiddleware())
+	server.setupEntityRoutes("Products")
+	db := server.provider.GetConnection()
+
+	tunnel := func(t *testing.T, header, method, path, body string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(header, method)
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	name := func(t *testing.T, id int) string {
+		t.Helper()
+		var value string
+		require.NoError(t, db.QueryRow("SELECT name FROM products WHERE id = ?", id).Scan(&value))
+		return value
+	}
+
+	t.Run("PATCH", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, tunnel(t, HeaderHTTPMethod, "patch", "/odata/Products(1)", `{"name":"Mouse Pro"}`))
+		assert.Equal(t, "Mouse Pro", name(t, 1))
+	})
+
+	t.Run("MERGE equivale a PATCH", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, tunnel(t, HeaderHTTPMethodOverride, "MERGE", "/odata/Products(2)", `{"name":"Teclado BR"}`))
+		assert.Equal(t, "Teclado BR", name(t, 2))
+	})
+
+	t.Run("DELETE", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, tunnel(t, HeaderHTTPMethod, "DELETE", "/odata/Products(3)", ""))
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM products WHERE id = 3").Scan(&count))
+		assert.Zero(t, count)
+	})
+
+	t.Run("Método não tunelável", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, tunnel(t, HeaderHTTPMethod, "GET", "/odata/Products", ""))
+	})
+
+	t.Run("Desabilitado", func(t *testing.T) {
+		server.config.DisableMethodOverride = true
+		defer func() { server.config.DisableMethodOverride = false }()
+
+		assert.Equal(t, http.StatusMethodNotAllowed, tunnel(t, HeaderHTTPMethod, "PATCH", "/odata/Products(1)", `{"name":"Outro"}`))
+		assert.Equal(t, "Mouse Pro", name(t, 1))
+	})
+}
+
+func TestBatchProcessor_MethodOverride(t *testing.T) {
+	processor := NewBatchProcessor(&Server{config: DefaultServerConfig()})
+
+	batchReq, err := processor.parseJSONBatch([]byte(`{"requests":[
+		{"id":"1","method":"POST","url":"/odata/Products(1)","headers":{"x-http-method":"DELETE"}},
+		{"id":"2","method":"POST","url":"/odata/Products","body":{"name":"A"}}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, batchReq.Parts, 2)
+	assert.Equal(t, "DELETE", batchReq.Parts[0].Request.Method)
+	assert.Equal(t, "POST", batchReq.Parts[1].Request.Method)
+
+	operation, err := processor.parseOperation([]byte("POST /odata/Products(1) HTTP/1.1\r\nX-HTTP-Method: PATCH\r\nContent-Type: application/json\r\n\r\n{\"name\":\"B\"}"))
+	require.NoError(t, err)
+	assert.Equal(t, "PATCH", operation.Method)
+
+	_, err = processor.parseJSONBatch([]byte(`{"requests":[{"id":"1","method":"POST","url":"/odata/Products","headers":{"X-HTTP-Method":"GET"}}]}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+)
+
+// =======================================================================================
+// MÓDULOS (PACOTES DE ENTIDADES, EVENTOS, OPERAÇÕES E MIGRATIONS)
+// =======================================================================================
+
+// Module é um pacote de funcionalidade autocontido: Register registra no servidor as suas
+// entidades, eventos, operações de serviço, jobs e middlewares. Módulos que implementam
+// Name() string são identificados por ele (nos logs e contra registro duplicado)
+type Module interface {
+	Register(server *Server) error
+}
+
+// ModuleMigrator é implementado pelos módulos com migrations de banco. Migrate é executado
+// uma vez por módulo: no Start, antes de aceitar requisições, ou no UseModule quando o
+// servidor já está em execução
+type ModuleMigrator interface {
+	Migrate(ctx context.Context, server *Server) error
+}
+
+// moduleEntry é um módulo registrado por UseModule
+type moduleEntry struct {
+	name     string
+	module   Module
+	migrated bool
+}
+
+// funcModule é o módulo criado por NewModule
+type funcModule struct {
+	name     string
+	register func(server *Server) error
+}
+
+// NewModule cria um módulo a partir de uma função de registro
+func NewModule(name string, register func(server *Server) error) Module {
+	return &funcModule{name: name, register: register}
+}
+
+// Name retorna o nome do módulo
+func (m *funcModule) Name() string {
+	return m.name
+}
+
+// Register executa a função de registro do módulo
+func (m *funcModule) Register(server *Server) error {
+	return m.register(server)
+}
+
+// moduleName retorna o nome do módulo (Name) ou o seu tipo
+func moduleName(module Module) (string, bool) {
+	if named, ok := module.(interface{ Name() string }); ok && named.Name() != "" {
+		return named.Name(), true
+	}
+	return fmt.Sprintf("%T", module), false
+}
+
+// UseModule registra os módulos no servidor, na ordem informada. Os módulos com nome não
+// podem ser registrados duas vezes. Com o servidor em execução, as entidades do módulo são
+// publicadas no roteador dinâmico e as migrations executadas imediatamente
+func (s *Server) UseModule(modules ...Module) error {
+	for _, module := range modules {
+		if module == nil {
+			return fmt.Errorf("módulo nil")
+		}
+		name, named := moduleName(module)
+		if named && s.hasModule(name) {
+			return fmt.Errorf("módulo %s já registrado", name)
+		}
+
+		if err := module.Register(s); err != nil {
+			return fmt.Errorf("erro ao registrar módulo %s: %w", name, err)
+		}
+
+		entry := &moduleEntry{name: name, module: module}
+		s.mu.Lock()
+		s.modules = append(s.modules, entry)
+		running := s.running
+		s.mu.Unlock()
+		s.logger.Printf("📦 Módulo '%s' registrado", name)
+
+		if running {
+			if err := s.migrateModule(context.Background(), entry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetModules retorna os nomes dos módulos registrados, na ordem de registro
+func (s *Server) GetModules() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, len(s.modules))
+	for i, entry := range s.modules {
+		names[i] = entry.name
+	}
+	return names
+}
+
+// hasModule verifica se o módulo com o nome informado já foi registrado
+func (s *Server) hasModule(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, entry := range s.modules {
+		if entry.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// runModuleMigrations executa as migrations pendentes dos módulos, na ordem de registro
+func (s *Server) runModuleMigrations(ctx context.Context) error {
+	s.mu.RLock()
+	entries := append([]*moduleEntry(nil), s.modules...)
+	s.mu.RUnlock()
+
+	for _, entry := range entries {
+		if err := s.migrateModule(ctx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateModule executa a migration do módulo, se houver e ainda não tiver sido executada
+func (s *Server) migrateModule(ctx context.Context, entry *moduleEntry) error {
+	migrator, ok := entry.module.(ModuleMigrator)
+	if !ok {
+		return nil
+	}
+
+	s.mu.Lock()
+	if entry.migrated {
+		s.mu.Unlock()
+		return nil
+	}
+	entry.migrated = true
+	s.mu.Unlock()
+
+	if err := migrator.Migrate(ctx, s); err != nil {
+		s.mu.Lock()
+		entry.migrated = false
+		s.mu.Unlock()
+		return fmt.Errorf("erro na migration do módulo %s: %w", entry.name, err)
+	}
+	s.logger.Printf("🛠️  Migration do módulo '%s' executada", entry.name)
+	return nil
+}
@@ -0,0 +1,104 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ShippingLabel struct {
+	TableName string `table:"shipping_labels"`
+	ID        int64  `json:"id" column:"id" primaryKey:"idGenerator:none"`
+	OrderID   int64  `json:"order_id" column:"order_id"`
+	Carrier   string `json:"carrier" column:"carrier"`
+}
+
+// shippingModule empacota entidade, evento, operação de serviço e migration
+type shippingModule struct {
+	inserted   int
+	migrations int
+}
+
+func (m *shippingModule) Name() string { return "shipping" }
+
+func (m *shippingModule) Register(server *Server) error {
+	if err := server.RegisterEntity("ShippingLabels", ShippingLabel{}); err != nil {
+		return err
+	}
+	server.OnEntityInserted("ShippingLabels", func(args EventArgs) error {
+		m.inserted++
+		return nil
+	})
+	server.Function("Carriers", nil, "Collection(Edm.String)", func(c fiber.Ctx) error {
+		return c.JSON(map[string]any{"value": []string{"correios"}})
+	})
+	return nil
+}
+
+func (m *shippingModule) Migrate(ctx context.Context, server *Server) error {
+	m.migrations++
+	_, err := server.provider.GetConnection().ExecContext(ctx,
+		"CREATE TABLE IF NOT EXISTS shipping_labels (id INTEGER PRIMARY KEY, order_id INTEGER, carrier TEXT)")
+	return err
+}
+
+func TestServer_UseModule(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	server.router = fiber.New()
+	module := &shippingModule{}
+
+	require.NoError(t, server.UseModule(module))
+	require.NoError(t, server.runModuleMigrations(context.Background()))
+	require.NoError(t, server.runModuleMigrations(context.Background()))
+	assert.Equal(t, 1, module.migrations, "migration executada uma vez")
+	assert.Equal(t, []string{"shipping"}, server.GetModules())
+
+	_, err := server.GetEntityService("ShippingLabels").Create(context.Background(), map[string]any{"id": 1, "order_id": 1, "carrier": "correios"})
+	require.NoError(t, err)
+
+	resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/ShippingLabels(1)", nil))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, err = server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Carriers", nil))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	t.Run("módulo duplicado", func(t *testing.T) {
+		assert.EqualError(t, server.UseModule(&shippingModule{}), "módulo shipping já registrado")
+	})
+
+	t.Run("erro no registro", func(t *testing.T) {
+		err := server.UseModule(NewModule("billing", func(server *Server) error {
+			return errors.New("provider ausente")
+		}))
+		assert.EqualError(t, err, "erro ao registrar módulo billing: provider ausente")
+		assert.Equal(t, []string{"shipping"}, server.GetModules())
+	})
+
+	t.Run("servidor em execução", func(t *testing.T) {
+		startTestRouter(server)
+		late := &lateModule{}
+		require.NoError(t, server.UseModule(late))
+		assert.True(t, late.migrated, "migration executada no UseModule")
+		assert.Equal(t, []string{"shipping", "*odata.lateModule"}, server.GetModules())
+	})
+}
+
+type lateModule struct {
+	migrated bool
+}
+
+func (m *lateModule) Register(server *Server) error { return nil }
+
+func (m *lateModule) Migrate(ctx context.Context, server *Server) error {
+	m.migrated = true
+	return nil
+}
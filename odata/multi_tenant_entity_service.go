@@ -114,6 +114,17 @@ func (s *MultiTenantEntityService) Query(ctx context.Context, options QueryOptio
 	return result.(*ODataResponse), nil
 }
 
+// GetCount conta as entidades usando o provider apropriado
+func (s *MultiTenantEntityService) GetCount(ctx context.Context, options QueryOptions) (int64, error) {
+	result, err := s.withProviderContext(ctx, "GetCount", func() (any, error) {
+		return s.BaseEntityService.GetCount(ctx, options)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int64), nil
+}
+
 // Get executa uma consulta de entidade específica usando o provider apropriado
 func (s *MultiTenantEntityService) Get(ctx context.Context, keys map[string]any) (any, error) {
 	return s.withProviderContext(ctx, "Get", func() (any, error) {
@@ -219,7 +230,7 @@ func (s *MultiTenantEntityService) ValidateTenantAccess(ctx context.Context, ope
 
 	// Verifica se a conexão está saudável
 	if db := provider.GetConnection(); db != nil {
-		if err := db.Ping(); err != nil {
+		if err := db.PingContext(ctx); err != nil {
 			return fmt.Errorf("conexão não saudável para tenant '%s': %w", tenantID, err)
 		}
 	}
@@ -226,7 +226,7 @@ func (s *Server) MultiTenantHealthCheck() fiber.Handler {
 
 		// Testa a conexão
 		if db := provider.GetConnection(); db != nil {
-			if err := db.Ping(); err != nil {
+			if err := db.PingContext(c.Context()); err != nil {
 				return fiber.NewError(fiber.StatusServiceUnavailable,
 					fmt.Sprintf("Conexão com banco não disponível para tenant '%s': %v", tenantID, err))
 			}
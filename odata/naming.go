@@ -0,0 +1,199 @@
+package odata
+
+import (
+	"log"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// =======================================================================================
+// POLÍTICA DE NOMES DAS PROPRIEDADES
+// =======================================================================================
+
+// NamingPolicy define como os nomes das propriedades são gerados a partir dos campos da
+// struct. O nome resultante é usado em todo lugar: $metadata, $select/$filter/$orderby,
+// payloads e respostas. Colunas (tag column) não são afetadas
+type NamingPolicy string
+
+const (
+	// NamingPolicyJSONTag usa a tag json do campo ou, sem ela, o nome do campo (padrão)
+	NamingPolicyJSONTag NamingPolicy = "json"
+	// NamingPolicyFieldName usa o nome do campo Go, ignorando a tag json
+	NamingPolicyFieldName NamingPolicy = "field"
+	// NamingPolicyCamelCase converte o nome do campo Go para camelCase (CustomerID → customerId)
+	NamingPolicyCamelCase NamingPolicy = "camelCase"
+	// NamingPolicyPascalCase converte o nome do campo Go para PascalCase (CustomerID → CustomerId)
+	NamingPolicyPascalCase NamingPolicy = "PascalCase"
+	// NamingPolicySnakeCase converte o nome do campo Go para snake_case (CustomerID → customer_id)
+	NamingPolicySnakeCase NamingPolicy = "snake_case"
+)
+
+// WithNamingPolicy define a política de nomes das propriedades da entidade, sobrescrevendo a
+// NamingPolicy do servidor
+func WithNamingPolicy(policy NamingPolicy) EntityOption {
+	return func(config *EntityConfig) {
+		config.NamingPolicy = policy
+	}
+}
+
+// parseNamingPolicy converte o valor da configuração (SERVER_NAMING_POLICY) para a política,
+// sem diferenciar maiúsculas/minúsculas. Valores desconhecidos usam a tag json
+func parseNamingPolicy(value string) NamingPolicy {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return NamingPolicyJSONTag
+	}
+	for _, policy := range []NamingPolicy{NamingPolicyJSONTag, NamingPolicyFieldName, NamingPolicyCamelCase, NamingPolicyPascalCase, NamingPolicySnakeCase} {
+		if strings.EqualFold(value, string(policy)) {
+			return policy
+		}
+	}
+	log.Printf("⚠️ Política de nomes inválida '%s', usando a tag json", value)
+	return NamingPolicyJSONTag
+}
+
+// propertyName retorna o nome da propriedade do campo segundo a política
+func (p NamingPolicy) propertyName(field reflect.StructField) string {
+	switch p {
+	case NamingPolicyFieldName:
+		return field.Name
+	case NamingPolicyCamelCase, NamingPolicyPascalCase, NamingPolicySnakeCase:
+		return p.convert(field.Name)
+	default:
+		return jsonFieldName(field)
+	}
+}
+
+// jsonFieldName retorna o nome da tag json do campo (sem opções como omitempty) ou o nome
+// do campo quando não há tag
+func jsonFieldName(field reflect.StructField) string {
+	if jsonTag := field.Tag.Get("json"); jsonTag != "" && jsonTag != "-" {
+		if name := strings.Split(jsonTag, ",")[0]; name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// convert aplica a convenção ao nome. Siglas são tratadas como palavras (HTTPServer →
+// httpServer, HttpServer, http_server)
+func (p NamingPolicy) convert(name string) string {
+	words := splitNameWords(name)
+	if len(words) == 0 {
+		return name
+	}
+
+	var sb strings.Builder
+	for i, word := range words {
+		lower := strings.ToLower(word)
+		switch p {
+		case NamingPolicySnakeCase:
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteString(lower)
+		case NamingPolicyCamelCase:
+			if i == 0 {
+				sb.WriteString(lower)
+			} else {
+				sb.WriteString(capitalize(lower))
+			}
+		default:
+			sb.WriteString(capitalize(lower))
+		}
+	}
+	return sb.String()
+}
+
+// splitNameWords separa o nome em palavras por "_", "-", espaços e mudanças de caixa
+// (CustomerID → Customer, ID; HTTPServer → HTTP, Server)
+func splitNameWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == ' ' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i > start && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// capitalize coloca a primeira letra em maiúscula
+func capitalize(word string) string {
+	runes := []rune(word)
+	if len(runes) == 0 {
+		return word
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// resolveRelationshipNames alinha os nomes usados nos relacionamentos (que podem vir de nomes
+// de colunas, como em association/manyAssociation) com os nomes das propriedades das
+// entidades registradas, para que $expand funcione com qualquer política de nomes
+func (s *Server) resolveRelationshipNames() {
+	s.mu.RLock()
+	services := make([]EntityService, 0, len(s.entities))
+	for _, service := range s.entities {
+		services = append(services, service)
+	}
+	s.mu.RUnlock()
+
+	for _, service := range services {
+		metadata := service.GetMetadata()
+		for _, prop := range metadata.Properties {
+			if !prop.IsNavigation || prop.Relationship == nil {
+				continue
+			}
+			// Só grava nomes alterados: com o servidor em execução (RegisterEntity após o Start),
+			// os relacionamentos já resolvidos são lidos pelas requisições em andamento
+			if local := resolvePropertyReference(metadata, prop.Relationship.LocalProperty); local != prop.Relationship.LocalProperty {
+				prop.Relationship.LocalProperty = local
+			}
+			if related := s.relatedEntityService(prop.RelatedType); related != nil {
+				if referenced := resolvePropertyReference(related.GetMetadata(), prop.Relationship.ReferencedProperty); referenced != prop.Relationship.ReferencedProperty {
+					prop.Relationship.ReferencedProperty = referenced
+				}
+			}
+		}
+	}
+}
+
+// resolvePropertyReference retorna o nome da propriedade referenciada por nome, coluna ou
+// campo Go. Referências já válidas ou desconhecidas são mantidas
+func resolvePropertyReference(metadata EntityMetadata, reference string) string {
+	if reference == "" {
+		return reference
+	}
+	for _, prop := range metadata.Properties {
+		if !prop.IsNavigation && prop.Name == reference {
+			return reference
+		}
+	}
+	for _, prop := range metadata.Properties {
+		if prop.IsNavigation {
+			continue
+		}
+		if strings.EqualFold(prop.Name, reference) || strings.EqualFold(prop.ColumnName, reference) || strings.EqualFold(prop.FieldName, reference) {
+			return prop.Name
+		}
+	}
+	return reference
+}
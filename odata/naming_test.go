@@ -0,0 +1,160 @@
+package odata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamingPolicy_Convert(t *testing.T) {
+	tests := []struct {
+		name                 string
+		camel, pascal, snake string
+	}{
+		{"CustomerID", "customerId", "CustomerId", "customer_id"},
+		{"HTTPServer", "httpServer", "HttpServer", "http_server"},
+		{"Name", "name", "Name", "name"},
+		{"Address2Line", "address2Line", "Address2Line", "address2_line"},
+		{"unit_price", "unitPrice", "UnitPrice", "unit_price"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.camel, NamingPolicyCamelCase.convert(tt.name))
+		assert.Equal(t, tt.pascal, NamingPolicyPascalCase.convert(tt.name))
+		assert.Equal(t, tt.snake, NamingPolicySnakeCase.convert(tt.name))
+	}
+
+	assert.Equal(t, NamingPolicySnakeCase, parseNamingPolicy("SNAKE_CASE"))
+	assert.Equal(t, NamingPolicyJSONTag, parseNamingPolicy(""))
+	assert.Equal(t, NamingPolicyJSONTag, parseNamingPolicy("kebab"))
+}
+
+type NamingCustomer struct {
+	TableName  string        `table:"naming_customers"`
+	CustomerID int64         `json:"id" column:"id" primaryKey:"idGenerator:none"`
+	FullName   string        `json:"name" column:"full_name"`
+	Orders     []NamingOrder `json:"orders" manyAssociation:"foreignKey:customer_id; references:id"`
+}
+
+type NamingOrder struct {
+	TableName   string  `table:"naming_orders"`
+	OrderID     int64   `json:"id" column:"id" primaryKey:"idGenerator:none"`
+	CustomerID  int64   `json:"customer_id" column:"customer_id"`
+	TotalAmount float64 `json:"total" column:"total_amount"`
+}
+
+func TestMapEntityWithNamingPolicy(t *testing.T) {
+	names := func(metadata EntityMetadata) []string {
+		var result []string
+		for _, prop := range metadata.Properties {
+			result = append(result, prop.Name)
+		}
+		return result
+	}
+
+	for policy, expected := range map[NamingPolicy][]string{
+		NamingPolicyJSONTag:    {"id", "name", "orders"},
+		NamingPolicyFieldName:  {"CustomerID", "FullName", "Orders"},
+		NamingPolicyCamelCase:  {"customerId", "fullName", "orders"},
+		NamingPolicyPascalCase: {"CustomerId", "FullName", "Orders"},
+		NamingPolicySnakeCase:  {"customer_id", "full_name", "orders"},
+	} {
+		metadata, err := MapEntityWithNamingPolicy(NamingCustomer{}, policy)
+		require.NoError(t, err)
+		assert.Equal(t, expected, names(metadata), policy)
+		assert.Equal(t, []string{expected[0]}, metadata.Keys, policy)
+		assert.Equal(t, "full_name", metadata.Properties[1].ColumnName, "colunas não mudam")
+		assert.Equal(t, "FullName", metadata.Properties[1].FieldName)
+	}
+}
+
+func TestServer_NamingPolicy(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	for _, stmt := range []string{
+		"CREATE TABLE naming_customers (id INTEGER PRIMARY KEY, full_name TEXT)",
+		"CREATE TABLE naming_orders (id INTEGER PRIMARY KEY, customer_id INTEGER, total_amount REAL)",
+		"INSERT INTO naming_customers VALUES (1, 'Ana'), (2, 'Bruno')",
+		"INSERT INTO naming_orders VALUES (10, 1, 99.5)",
+	} {
+		_, err := server.provider.GetConnection().Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	server.SetNamingPolicy(NamingPolicySnakeCase)
+	require.NoError(t, server.RegisterEntity("NamingCustomers", NamingCustomer{}))
+	require.NoError(t, server.RegisterEntity("NamingOrders", NamingOrder{}, WithNamingPolicy(NamingPolicyCamelCase)))
+
+	request := func(t *testing.T, method, path, body string) (int, string) {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		payload, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(payload)
+	}
+
+	t.Run("$select, $filter, $expand e resposta usam a política", func(t *testing.T) {
+		query := url.Values{
+			"$filter": {"full_name eq 'Ana'"},
+			"$expand": {"orders"},
+		}
+		status, body := request(t, http.MethodGet, "/odata/NamingCustomers?"+query.Encode(), "")
+		require.Equal(t, http.StatusOK, status, body)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal([]byte(body), &response))
+		values := response["value"].([]any)
+		require.Len(t, values, 1)
+		customer := values[0].(map[string]any)
+		assert.Equal(t, "Ana", customer["full_name"])
+		assert.NotContains(t, customer, "name")
+
+		orders := customer["orders"].([]any)
+		require.Len(t, orders, 1)
+		assert.Equal(t, float64(99.5), orders[0].(map[string]any)["totalAmount"], "política da entidade relacionada")
+
+		status, body = request(t, http.MethodGet, "/odata/NamingCustomers?$select=full_name&$orderby=full_name%20desc", "")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Contains(t, body, `"value":[{"full_name":"Bruno"},{"full_name":"Ana"}]`)
+	})
+
+	t.Run("Payload usa a política da entidade", func(t *testing.T) {
+		status, body := request(t, http.MethodPost, "/odata/NamingOrders", `{"orderId": 11, "customerId": 2, "totalAmount": 10}`)
+		require.Equal(t, http.StatusCreated, status, body)
+		assert.Contains(t, body, `"totalAmount":10`)
+
+		var total float64
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT total_amount FROM naming_orders WHERE id = 11").Scan(&total))
+		assert.Equal(t, float64(10), total)
+	})
+
+	t.Run("Struct em Create usa os nomes da política", func(t *testing.T) {
+		_, err := server.GetEntityService("NamingCustomers").Create(t.Context(), NamingCustomer{CustomerID: 3, FullName: "Carla"})
+		require.NoError(t, err)
+
+		var name string
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT full_name FROM naming_customers WHERE id = 3").Scan(&name))
+		assert.Equal(t, "Carla", name)
+	})
+
+	t.Run("$metadata", func(t *testing.T) {
+		server.router.Get("/odata/$metadata", server.handleMetadata)
+		status, body := request(t, http.MethodGet, "/odata/$metadata", "")
+		require.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, "full_name")
+		assert.Contains(t, body, "totalAmount")
+	})
+}
@@ -0,0 +1,342 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// =======================================================================================
+// JOINS DE NAVEGAÇÕES N:1 ($orderby=Category/Name)
+// =======================================================================================
+
+// navigationResolver resolve os metadados da entidade relacionada a uma navegação
+type navigationResolver func(relatedType string) (EntityMetadata, error)
+
+// navigationJoin é um LEFT JOIN gerado para uma navegação N:1 referenciada na query.
+// A entidade relacionada entra como tabela derivada expondo apenas as colunas usadas,
+// com aliases únicos, para que as colunas não qualificadas da query principal
+// continuem sem ambiguidade
+type navigationJoin struct {
+	alias       string         // alias da tabela derivada (ex: nav_category)
+	localColumn string         // chave estrangeira na entidade principal
+	refColumn   string         // coluna referenciada na entidade relacionada
+	related     EntityMetadata // entidade relacionada
+	columns     []string       // colunas da entidade relacionada usadas na query
+}
+
+// navigationJoins acumula os JOINs necessários para os caminhos de navegação da query
+type navigationJoins struct {
+	metadata EntityMetadata
+	resolver navigationResolver
+	joins    []*navigationJoin
+	byName   map[string]*navigationJoin
+}
+
+// newNavigationJoins cria o acumulador de JOINs da entidade principal
+func newNavigationJoins(metadata EntityMetadata, resolver navigationResolver) *navigationJoins {
+	return &navigationJoins{
+		metadata: metadata,
+		resolver: resolver,
+		byName:   make(map[string]*navigationJoin),
+	}
+}
+
+// column resolve um caminho "Navegação/Propriedade" para a coluna qualificada pelo alias
+// do JOIN, registrando o JOIN na primeira referência. Apenas navegações N:1 de um nível
+func (n *navigationJoins) column(path string) (string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("navigation path %s is not supported: only one single-valued navigation level is allowed", path)
+	}
+	if n == nil || n.resolver == nil {
+		return "", fmt.Errorf("navigation path %s requires the entity service to resolve related entities", path)
+	}
+
+	join, err := n.join(parts[0])
+	if err != nil {
+		return "", err
+	}
+
+	var relatedProp *PropertyMetadata
+	for i, prop := range join.related.Properties {
+		if strings.EqualFold(prop.Name, parts[1]) && !prop.IsNavigation {
+			relatedProp = &join.related.Properties[i]
+			break
+		}
+	}
+	if relatedProp == nil {
+		return "", fmt.Errorf("property %s not found in entity %s", parts[1], join.related.Name)
+	}
+
+	column := columnNameOf(*relatedProp)
+	found := false
+	for _, used := range join.columns {
+		if strings.EqualFold(used, column) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		join.columns = append(join.columns, column)
+	}
+
+	return join.alias + "." + join.columnAlias(column), nil
+}
+
+// join retorna (ou cria) o JOIN da navegação informada
+func (n *navigationJoins) join(navigation string) (*navigationJoin, error) {
+	key := strings.ToLower(navigation)
+	if join, ok := n.byName[key]; ok {
+		return join, nil
+	}
+
+	var navProp *PropertyMetadata
+	for i, prop := range n.metadata.Properties {
+		if prop.IsNavigation && strings.EqualFold(prop.Name, navigation) {
+			navProp = &n.metadata.Properties[i]
+			break
+		}
+	}
+	if navProp == nil {
+		return nil, fmt.Errorf("navigation property %s not found in entity %s", navigation, n.metadata.Name)
+	}
+	if navProp.IsCollection || navProp.ManyAssociation != nil {
+		return nil, fmt.Errorf("navigation property %s is a collection: only single-valued navigations are supported", navigation)
+	}
+	if navProp.Relationship == nil || navProp.Relationship.LocalProperty == "" || navProp.Relationship.ReferencedProperty == "" {
+		return nil, fmt.Errorf("navigation property %s has no relationship metadata", navigation)
+	}
+
+	relatedType := navProp.RelatedType
+	if navProp.Association != nil && navProp.Association.RelatedEntity != "" {
+		relatedType = navProp.Association.RelatedEntity
+	}
+	related, err := n.resolver(relatedType)
+	if err != nil && relatedType != navProp.RelatedType {
+		related, err = n.resolver(navProp.RelatedType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve navigation property %s: %w", navigation, err)
+	}
+
+	join := &navigationJoin{
+		alias:       "nav_" + sanitizeAliasPart(navProp.Name),
+		localColumn: resolveColumnName(n.metadata, navProp.Relationship.LocalProperty),
+		refColumn:   resolveColumnName(related, navProp.Relationship.ReferencedProperty),
+		related:     related,
+	}
+	n.joins = append(n.joins, join)
+	n.byName[key] = join
+	return join, nil
+}
+
+// columnAlias retorna o alias único de uma coluna da entidade relacionada
+func (j *navigationJoin) columnAlias(column string) string {
+	return j.alias + "_" + sanitizeAliasPart(column)
+}
+
+// sql gera os LEFT JOINs registrados para a tabela principal
+func (n *navigationJoins) sql(mainTable string) string {
+	if n == nil || len(n.joins) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	for _, join := range n.joins {
+		relatedTable := join.related.TableName
+		if relatedTable == "" {
+			relatedTable = join.related.Name
+		}
+
+		keyAlias := join.alias + "_key"
+		columns := []string{fmt.Sprintf("%s AS %s", join.refColumn, keyAlias)}
+		for _, column := range join.columns {
+			columns = append(columns, fmt.Sprintf("%s AS %s", column, join.columnAlias(column)))
+		}
+
+		// Linhas excluídas logicamente não participam da ordenação
+		var where string
+		if column := softDeleteColumn(join.related); column != "" {
+			where = " WHERE " + column + " IS NULL"
+		}
+
+		// LEFT JOIN preserva registros sem a entidade relacionada (FK nula)
+		fmt.Fprintf(&builder, " LEFT JOIN (SELECT %s FROM %s%s) %s ON %s.%s = %s.%s",
+			strings.Join(columns, ", "), relatedTable, where, join.alias, join.alias, keyAlias, mainTable, join.localColumn)
+	}
+	return builder.String()
+}
+
+// resolveColumnName retorna a coluna de uma propriedade (por nome ou coluna) ou o próprio nome
+func resolveColumnName(metadata EntityMetadata, name string) string {
+	for _, prop := range metadata.Properties {
+		if prop.IsNavigation {
+			continue
+		}
+		if strings.EqualFold(prop.Name, name) || strings.EqualFold(prop.ColumnName, name) {
+			return columnNameOf(prop)
+		}
+	}
+	return name
+}
+
+// sanitizeAliasPart mantém apenas caracteres válidos em identificadores SQL
+func sanitizeAliasPart(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return '_'
+	}, name)
+}
+
+// =======================================================================================
+// FILTROS EM CAMINHOS DE NAVEGAÇÃO ($filter=Category/Name eq 'X')
+// =======================================================================================
+
+// navigationFilter é uma condição sobre a entidade relacionada, traduzida em EXISTS
+type navigationFilter struct {
+	mainTable   string
+	relatedSQL  string // tabela relacionada com alias
+	alias       string
+	localColumn string
+	refColumn   string
+	related     EntityMetadata
+	condition   *ParseNode // condição com as propriedades da entidade relacionada
+}
+
+// resolveNavigationFilters substitui as condições do filtro que usam caminhos de navegação
+// N:1 por nós EXISTS correlacionados. EXISTS (em vez de JOIN) mantém a cardinalidade da
+// query principal e funciona igualmente na query de $count
+func resolveNavigationFilters(node *ParseNode, metadata EntityMetadata, resolver navigationResolver) (*ParseNode, error) {
+	if node == nil || node.Token == nil || !hasNavigationPath(node) {
+		return node, nil
+	}
+
+	// Operadores lógicos: resolve cada lado separadamente
+	if node.Token.Type == int(FilterTokenLogical) {
+		clone := &ParseNode{Token: node.Token, Parent: node.Parent}
+		for _, child := range node.Children {
+			resolved, err := resolveNavigationFilters(child, metadata, resolver)
+			if err != nil {
+				return nil, err
+			}
+			resolved.Parent = clone
+			clone.Children = append(clone.Children, resolved)
+		}
+		return clone, nil
+	}
+
+	// Condição folha (comparação ou função booleana): todas as referências devem ser
+	// da mesma navegação
+	var navigation string
+	var err error
+	condition := cloneParseTree(node, nil)
+	walkParseTree(condition, func(n *ParseNode) {
+		if err != nil || n.Token == nil || n.Token.Type != int(FilterTokenProperty) {
+			return
+		}
+		parts := strings.Split(n.Token.Value, "/")
+		switch {
+		case len(parts) == 1:
+			err = fmt.Errorf("condition on navigation path cannot reference property %s of %s", n.Token.Value, metadata.Name)
+		case len(parts) > 2:
+			err = fmt.Errorf("navigation path %s is not supported: only one single-valued navigation level is allowed", n.Token.Value)
+		case navigation != "" && !strings.EqualFold(navigation, parts[0]):
+			err = fmt.Errorf("a single condition cannot reference navigations %s and %s", navigation, parts[0])
+		default:
+			navigation = parts[0]
+			n.Token = &Token{Type: n.Token.Type, Value: parts[1], SemanticType: n.Token.SemanticType}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resolver == nil {
+		return nil, fmt.Errorf("navigation path %s in filter requires the entity service to resolve related entities", navigation)
+	}
+	join, err := newNavigationJoins(metadata, resolver).join(navigation)
+	if err != nil {
+		return nil, err
+	}
+
+	mainTable := metadata.TableName
+	if mainTable == "" {
+		mainTable = metadata.Name
+	}
+	relatedTable := join.related.TableName
+	if relatedTable == "" {
+		relatedTable = join.related.Name
+	}
+
+	// Sem configuração própria, a condição segue o modo case-insensitive da entidade consultada
+	related := join.related
+	if related.CaseInsensitive == nil {
+		related.CaseInsensitive = metadata.CaseInsensitive
+	}
+
+	return &ParseNode{
+		Token: &Token{
+			Type:  int(FilterTokenNavigationExists),
+			Value: navigation,
+			SemanticReference: &navigationFilter{
+				mainTable:   mainTable,
+				relatedSQL:  relatedTable + " " + join.alias,
+				alias:       join.alias,
+				localColumn: join.localColumn,
+				refColumn:   join.refColumn,
+				related:     related,
+				condition:   condition,
+			},
+		},
+		Parent: node.Parent,
+	}, nil
+}
+
+// buildNavigationExistsNamed gera o EXISTS correlacionado de uma condição de navegação.
+// As colunas não qualificadas da condição resolvem para a tabela da subquery
+func (qb *QueryBuilder) buildNavigationExistsNamed(ctx context.Context, node *ParseNode, namedArgs *NamedArgs) (string, error) {
+	nav, ok := node.Token.SemanticReference.(*navigationFilter)
+	if !ok || nav == nil {
+		return "", fmt.Errorf("navigation filter %s has no resolved relationship", node.Token.Value)
+	}
+
+	condition, err := qb.buildNodeExpressionNamed(ctx, nav.condition, nav.related, namedArgs)
+	if err != nil {
+		return "", err
+	}
+	// Linhas excluídas logicamente da entidade relacionada não atendem à condição
+	if column := softDeleteColumn(nav.related); column != "" {
+		condition = fmt.Sprintf("%s.%s IS NULL AND %s", nav.alias, column, condition)
+	}
+
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %s WHERE %s.%s = %s.%s AND %s)",
+		nav.relatedSQL, nav.alias, nav.refColumn, nav.mainTable, nav.localColumn, condition), nil
+}
+
+// hasNavigationPath verifica se a árvore referencia algum caminho de navegação
+func hasNavigationPath(node *ParseNode) bool {
+	found := false
+	walkParseTree(node, func(n *ParseNode) {
+		if n.Token != nil && n.Token.Type == int(FilterTokenProperty) && strings.Contains(n.Token.Value, "/") {
+			found = true
+		}
+	})
+	return found
+}
+
+// walkParseTree percorre a árvore em pré-ordem
+func walkParseTree(node *ParseNode, visit func(*ParseNode)) {
+	if node == nil {
+		return
+	}
+	visit(node)
+	for _, child := range node.Children {
+		walkParseTree(child, visit)
+	}
+}
@@ -0,0 +1,198 @@
+package odata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newNavigationTestServer estende o servidor de Products com Categories (N:1 via category_id)
+func newNavigationTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := newBatchGetTestServer(t)
+	conn := server.provider.GetConnection()
+	for _, stmt := range []string{
+		"CREATE TABLE categories (id INTEGER PRIMARY KEY, name TEXT)",
+		"INSERT INTO categories (id, name) VALUES (1, 'Periféricos'), (2, 'Displays')",
+		"ALTER TABLE products ADD COLUMN category_id INTEGER",
+		"UPDATE products SET category_id = CASE id WHEN 3 THEN 2 ELSE 1 END",
+		"INSERT INTO products (id, name, price, category_id) VALUES (4, 'Cabo', 5, NULL)",
+	} {
+		_, err := conn.Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	products := server.entities["Products"].GetMetadata()
+	products.Properties = append(products.Properties,
+		PropertyMetadata{Name: "category_id", ColumnName: "category_id", Type: "int64"},
+		PropertyMetadata{
+			Name:         "Category",
+			Type:         "relationship",
+			IsNavigation: true,
+			RelatedType:  "Category",
+			Association:  &AssociationMetadata{ForeignKey: "category_id", References: "id", RelatedEntity: "Categories"},
+			Relationship: &RelationshipMetadata{LocalProperty: "category_id", ReferencedProperty: "id"},
+		},
+	)
+	server.entities["Products"] = NewBaseEntityService(server.provider, products, server)
+	server.entities["Categories"] = NewBaseEntityService(server.provider, EntityMetadata{
+		Name:      "Categories",
+		TableName: "categories",
+		Keys:      []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "name", ColumnName: "name", Type: "string"},
+		},
+	}, server)
+
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+	return server
+}
+
+// navigationTestUser recria as rotas de Products com um middleware que autentica o usuário
+// informado na função retornada (nil = anônimo)
+func navigationTestUser(server *Server) func(user *UserIdentity) {
+	var current *UserIdentity
+	server.router = fiber.New()
+	server.router.Use(func(c fiber.Ctx) error {
+		if current != nil {
+			c.Locals(UserContextKey, current)
+		}
+		return c.Next()
+	})
+	server.setupEntityRoutes("Products")
+	return func(user *UserIdentity) { current = user }
+}
+
+// updateNavigationCategories altera os metadados de Categories no servidor de navegação
+func updateNavigationCategories(server *Server, update func(metadata *EntityMetadata)) {
+	metadata := server.entities["Categories"].GetMetadata()
+	update(&metadata)
+	server.entities["Categories"] = NewBaseEntityService(server.provider, metadata, server)
+}
+
+// productNames retorna os nomes dos produtos retornados pela consulta
+func productNames(t *testing.T, server *Server, query string) []string {
+	t.Helper()
+
+	status, body := getComputeTestProducts(t, server, query)
+	require.Equal(t, http.StatusOK, status, body)
+
+	var names []string
+	for _, value := range body["value"].([]any) {
+		names = append(names, value.(map[string]any)["name"].(string))
+	}
+	return names
+}
+
+func TestServer_OrderByNavigationProperty(t *testing.T) {
+	server := newNavigationTestServer(t)
+
+	t.Run("Ordena pela propriedade da entidade relacionada", func(t *testing.T) {
+		names := productNames(t, server, "$orderby="+url.QueryEscape("Category/name desc,name"))
+		assert.Equal(t, []string{"Mouse", "Teclado", "Monitor"}, names[:3], "Periféricos > Displays")
+		assert.Len(t, names, 4, "produtos sem categoria são mantidos (LEFT JOIN)")
+	})
+
+	t.Run("Combina com $filter, $top e colunas de mesmo nome", func(t *testing.T) {
+		var sqls []string
+		server.OnQueryBuilt("Products", func(args EventArgs) error {
+			sqls = append(sqls, args.(*QueryBuiltArgs).SQL)
+			return nil
+		})
+
+		names := productNames(t, server, "$filter="+url.QueryEscape("price gt 6")+"&$orderby="+url.QueryEscape("Category/name,price desc")+"&$top=2")
+		assert.Equal(t, []string{"Monitor", "Teclado"}, names)
+		require.NotEmpty(t, sqls)
+		assert.Contains(t, sqls[0], "LEFT JOIN (SELECT id AS nav_category_key, name AS nav_category_name FROM categories) nav_category ON nav_category.nav_category_key = products.category_id")
+	})
+
+	t.Run("Navegação inexistente ou propriedade inválida", func(t *testing.T) {
+		for _, orderBy := range []string{"Supplier/name", "Category/missing", "Category/Parent/name"} {
+			status, body := getComputeTestProducts(t, server, "$orderby="+url.QueryEscape(orderBy))
+			assert.NotEqual(t, http.StatusOK, status, body)
+		}
+	})
+}
+
+func TestServer_FilterNavigationProperty(t *testing.T) {
+	server := newNavigationTestServer(t)
+
+	t.Run("Filtra pela propriedade da entidade relacionada", func(t *testing.T) {
+		names := productNames(t, server, "$filter="+url.QueryEscape("Category/name eq 'Displays'"))
+		assert.Equal(t, []string{"Monitor"}, names)
+	})
+
+	t.Run("Funções e operadores lógicos com propriedades locais", func(t *testing.T) {
+		names := productNames(t, server, "$filter="+url.QueryEscape("tolower(Category/name) eq 'periféricos' and price gt 20")+"&$orderby=name")
+		assert.Equal(t, []string{"Teclado"}, names)
+
+		names = productNames(t, server, "$filter="+url.QueryEscape("Category/name ne 'Displays' or price lt 6")+"&$orderby=name")
+		assert.Equal(t, []string{"Cabo", "Mouse", "Teclado"}, names)
+	})
+
+	t.Run("Gera EXISTS correlacionado e aplica ao $count", func(t *testing.T) {
+		var sqls []string
+		server.OnQueryBuilt("Products", func(args EventArgs) error {
+			sqls = append(sqls, args.(*QueryBuiltArgs).SQL)
+			return nil
+		})
+
+		status, body := getComputeTestProducts(t, server, "$filter="+url.QueryEscape("Category/name eq 'Periféricos'")+"&$count=true")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.EqualValues(t, 2, body["@odata.count"])
+		require.NotEmpty(t, sqls)
+		assert.Contains(t, sqls[0], "EXISTS (SELECT 1 FROM categories nav_category WHERE nav_category.id = products.category_id AND")
+	})
+
+	t.Run("Caminhos inválidos retornam 400", func(t *testing.T) {
+		for _, filter := range []string{
+			"Supplier/name eq 'X'",
+			"Category/Parent/name eq 'X'",
+			"Category/name eq name",
+		} {
+			status, body := getComputeTestProducts(t, server, "$filter="+url.QueryEscape(filter))
+			assert.Equal(t, http.StatusBadRequest, status, filter, body)
+		}
+
+		status, body := getComputeTestProducts(t, server, "$filter="+url.QueryEscape("Category/missing eq 'X'"))
+		assert.NotEqual(t, http.StatusOK, status, body)
+	})
+}
+
+func TestServer_NavigationEntityAuth(t *testing.T) {
+	server := newNavigationTestServer(t)
+	setUser := navigationTestUser(server)
+	server.entityAuth["Categories"] = EntityAuthConfig{RequireAuth: true, RequireAdmin: true}
+
+	for _, query := range []string{
+		"$filter=" + url.QueryEscape("Category/name eq 'Displays'"),
+		"$orderby=" + url.QueryEscape("Category/name desc"),
+	} {
+		setUser(nil)
+		status, _ := getComputeTestProducts(t, server, query)
+		assert.Equal(t, http.StatusUnauthorized, status, query)
+
+		setUser(&UserIdentity{Username: "ana"})
+		status, _ = getComputeTestProducts(t, server, query)
+		assert.Equal(t, http.StatusForbidden, status, query)
+
+		setUser(&UserIdentity{Username: "ana", Admin: true})
+		status, body := getComputeTestProducts(t, server, query)
+		assert.Equal(t, http.StatusOK, status, query, body)
+	}
+
+	// O /$count resolve as navegações do $filter sem passar pelo Query
+	setUser(nil)
+	resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Products/$count?$filter="+url.QueryEscape("Category/name eq 'Displays'"), nil))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
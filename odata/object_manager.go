@@ -30,7 +30,7 @@ type BatchOperation struct {
 
 // TxManager representa uma transação ativa
 type TxManager struct {
-	tx         *sql.Tx
+	tx         *scopedTx
 	manager    *ObjectManager
 	operations []BatchOperation
 }
@@ -45,6 +45,7 @@ type ObjectManager struct {
 	cachedUpdates bool                        // Modo cached updates
 	pendingOps    []BatchOperation            // Operações pendentes
 	attachedObjs  map[string]bool             // Objetos attached ao manager
+	uow           *unitOfWork                 // Unidade de trabalho ativa (Begin/Commit)
 	mu            sync.RWMutex                // Thread safety
 	logger        *log.Logger
 }
@@ -84,6 +85,7 @@ func (om *ObjectManager) Find(entityName string, key string) (any, error) {
 	if cached := om.getFromCache(entityName, key); cached != nil {
 		om.logger.Printf("📦 Cache hit para %s:%s", entityName, key)
 		om.attachToManager(cached)
+		om.trackEntity(entityName, key, cached.Entity)
 		return cached.Entity, nil
 	}
 
@@ -99,7 +101,7 @@ func (om *ObjectManager) Find(entityName string, key string) (any, error) {
 	keyProperty := om.getKeyProperty(entityMetadata)
 	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", entityMetadata.TableName, keyProperty.Name)
 
-	rows, err := om.ExecuteQuery(query, []any{key})
+	rows, err := om.ExecuteQuery(query, key)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar entidade: %w", err)
 	}
@@ -133,6 +135,7 @@ func (om *ObjectManager) Find(entityName string, key string) (any, error) {
 	// Adiciona ao cache
 	om.addToCache(entityName, key, result)
 	om.attachToManager(result)
+	om.trackEntity(entityName, key, result)
 
 	return result, nil
 }
@@ -147,6 +150,11 @@ func (om *ObjectManager) FindCached(entityName string, key string) (any, error)
 
 // Save marca uma entidade para inserção
 func (om *ObjectManager) Save(entity any) error {
+	// Na unidade de trabalho, a inserção é gravada no Commit
+	if queued, err := om.queueOperation("INSERT", entity); queued {
+		return err
+	}
+
 	entityData := om.entityToMap(entity)
 	entityName := om.getEntityType(entity)
 	key := om.extractKey(entityData, entityName)
@@ -180,6 +188,10 @@ func (om *ObjectManager) Save(entity any) error {
 
 // Update marca uma entidade para atualização
 func (om *ObjectManager) Update(entity any) error {
+	if queued, err := om.queueOperation("UPDATE", entity); queued {
+		return err
+	}
+
 	entityData := om.entityToMap(entity)
 	entityName := om.getEntityType(entity)
 	key := om.extractKey(entityData, entityName)
@@ -263,6 +275,10 @@ func (om *ObjectManager) FlushAll() error {
 
 // Remove marca uma entidade para remoção
 func (om *ObjectManager) Remove(entity any) error {
+	if queued, err := om.queueOperation("DELETE", entity); queued {
+		return err
+	}
+
 	entityData := om.entityToMap(entity)
 	entityName := om.getEntityType(entity)
 	key := om.extractKey(entityData, entityName)
@@ -430,13 +446,14 @@ func (om *ObjectManager) GetChangedObjects() []any {
 // 4. TRANSACTION MANAGEMENT
 // ==================================================
 
-// BeginTransaction inicia uma nova transação
+// BeginTransaction inicia uma nova transação. Com o contexto de Server.WithTransaction, é
+// um savepoint da transação do contexto
 func (om *ObjectManager) BeginTransaction() (*TxManager, error) {
 	conn := om.GetConnection()
 	if conn == nil {
 		return nil, fmt.Errorf("database connection is nil")
 	}
-	tx, err := conn.BeginTx(om.context, nil)
+	tx, err := beginSessionTx(om.context, om.provider, nil)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
 	}
@@ -585,13 +602,29 @@ func (om *ObjectManager) GetConnection() *sql.DB {
 
 // ExecuteQuery executa uma query customizada
 func (om *ObjectManager) ExecuteQuery(query string, args ...any) (*sql.Rows, error) {
+	return om.executeQueryContext(om.context, query, args...)
+}
+
+// executeQueryContext executa a query com o contexto informado (cancelamento de cursores)
+func (om *ObjectManager) executeQueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
 	conn := om.GetConnection()
 	if conn == nil {
 		return nil, fmt.Errorf("conexão com banco não disponível")
 	}
 
+	// Na unidade de trabalho, as consultas enxergam a transação
+	if uow := om.currentUnitOfWork(); uow != nil {
+		om.logger.Printf("🔍 Executando query em transação: %s", query)
+		return uow.tx.tx.QueryContext(ctx, query, args...)
+	}
+
+	// Com o contexto de Server.WithTransaction, as consultas usam a transação do contexto
+	querier, err := sessionQuerier(ctx, om.provider, conn)
+	if err != nil {
+		return nil, err
+	}
 	om.logger.Printf("🔍 Executando query: %s", query)
-	return conn.QueryContext(om.context, query, args...)
+	return querier.QueryContext(ctx, query, args...)
 }
 
 // ExecuteQueryTransaction executa query dentro de uma transação
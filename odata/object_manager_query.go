@@ -0,0 +1,427 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ==================================================
+// 8. CONSULTAS PARAMETRIZADAS
+// ==================================================
+
+// queryParamPrefix identifica os marcadores "?" no filtro até a vinculação dos valores
+const queryParamPrefix = "__odata_param_"
+
+// queryIdentifierRegex valida nomes de propriedades usados em WhereEq, OrderBy e Select
+var queryIdentifierRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ObjectQuery monta consultas sobre uma entidade do ObjectManager. Os valores são sempre
+// vinculados como parâmetros (Where com "?" ou WhereEq) e nunca concatenados ao SQL
+type ObjectQuery struct {
+	manager    *ObjectManager
+	entityName string
+	filters    []*ParseNode
+	properties map[string]bool
+	selected   []string
+	orderBy    []OrderByExpression
+	top        int
+	skip       int
+	err        error
+}
+
+// Query inicia uma consulta parametrizada sobre a entidade
+func (om *ObjectManager) Query(entityName string) *ObjectQuery {
+	return &ObjectQuery{
+		manager:    om,
+		entityName: entityName,
+		properties: make(map[string]bool),
+	}
+}
+
+// Where adiciona um filtro na sintaxe do $filter, com "?" no lugar de cada valor. Os valores
+// são vinculados na ordem dos marcadores, como parâmetros da query:
+//
+//	manager.Query("Products").Where("category eq ? and price gt ?", category, 100)
+//
+// Nunca monte o filtro concatenando valores recebidos do usuário: use "?" ou WhereEq
+func (q *ObjectQuery) Where(filter string, args ...any) *ObjectQuery {
+	if q.err != nil {
+		return q
+	}
+
+	expression, count := replaceQueryPlaceholders(filter)
+	if count != len(args) {
+		q.err = fmt.Errorf("filtro com %d parâmetros, mas %d valores foram informados", count, len(args))
+		return q
+	}
+
+	parsed, err := ParseFilterString(q.manager.context, expression)
+	if err != nil {
+		q.err = fmt.Errorf("filtro inválido: %w", err)
+		return q
+	}
+	if parsed == nil || parsed.Tree == nil {
+		return q
+	}
+
+	if err := q.bindParameters(parsed.Tree, args); err != nil {
+		q.err = err
+		return q
+	}
+	q.filters = append(q.filters, parsed.Tree)
+	return q
+}
+
+// WhereEq adiciona o filtro "property eq value", com o valor vinculado como parâmetro
+func (q *ObjectQuery) WhereEq(property string, value any) *ObjectQuery {
+	if q.err == nil && !queryIdentifierRegex.MatchString(property) {
+		q.err = fmt.Errorf("propriedade inválida: '%s'", property)
+	}
+	return q.Where(property+" eq ?", value)
+}
+
+// OrderBy adiciona a ordenação na sintaxe do $orderby: propriedades separadas por vírgula,
+// seguidas opcionalmente de "asc" ou "desc" (ex: "category, price desc")
+func (q *ObjectQuery) OrderBy(orderBy string) *ObjectQuery {
+	if q.err != nil {
+		return q
+	}
+
+	for _, item := range strings.Split(orderBy, ",") {
+		parts := strings.Fields(item)
+		if len(parts) == 0 || len(parts) > 2 || !queryIdentifierRegex.MatchString(parts[0]) {
+			q.err = fmt.Errorf("ordenação inválida: '%s'", strings.TrimSpace(item))
+			return q
+		}
+
+		direction := OrderAsc
+		if len(parts) == 2 {
+			switch strings.ToLower(parts[1]) {
+			case "asc":
+			case "desc":
+				direction = OrderDesc
+			default:
+				q.err = fmt.Errorf("ordenação inválida: '%s'", strings.TrimSpace(item))
+				return q
+			}
+		}
+		q.properties[parts[0]] = true
+		q.orderBy = append(q.orderBy, OrderByExpression{Property: parts[0], Direction: direction})
+	}
+	return q
+}
+
+// Select restringe as colunas retornadas (propriedades separadas por vírgula). Sem Select,
+// todas as colunas da tabela são retornadas
+func (q *ObjectQuery) Select(properties string) *ObjectQuery {
+	if q.err != nil {
+		return q
+	}
+
+	for _, property := range strings.Split(properties, ",") {
+		property = strings.TrimSpace(property)
+		if !queryIdentifierRegex.MatchString(property) {
+			q.err = fmt.Errorf("propriedade inválida: '%s'", property)
+			return q
+		}
+		q.properties[property] = true
+		q.selected = append(q.selected, property)
+	}
+	return q
+}
+
+// Top limita a quantidade de registros retornados
+func (q *ObjectQuery) Top(top int) *ObjectQuery {
+	q.top = top
+	return q
+}
+
+// Skip ignora os primeiros registros do resultado
+func (q *ObjectQuery) Skip(skip int) *ObjectQuery {
+	q.skip = skip
+	return q
+}
+
+// List executa a consulta e retorna as entidades. As entidades já presentes no cache são
+// retornadas na mesma instância (identity map) e as novas passam a ser gerenciadas
+func (q *ObjectQuery) List() ([]map[string]any, error) {
+	cursor, err := q.Stream(q.manager.context)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	results := make([]map[string]any, 0)
+	for cursor.Next() {
+		results = append(results, q.manage(cursor.Row()))
+	}
+	return results, cursor.Err()
+}
+
+// Iterate percorre o resultado com um cursor, chamando fn a cada registro, sem carregar
+// tudo em memória (exportações, jobs em lote). Os registros não entram no cache do
+// ObjectManager. A iteração para no primeiro erro de fn, que é retornado, ou no
+// cancelamento de ctx
+func (q *ObjectQuery) Iterate(ctx context.Context, fn func(row map[string]any) error) error {
+	cursor, err := q.Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		if err := fn(cursor.Row()); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// Stream executa a consulta e retorna um cursor aberto sobre o resultado. O cursor mantém a
+// conexão (ou a transação da unidade de trabalho) ocupada até Close, que deve ser sempre
+// chamado. Os registros não entram no cache do ObjectManager
+func (q *ObjectQuery) Stream(ctx context.Context) (*ObjectCursor, error) {
+	query, args, err := q.build()
+	if err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = q.manager.context
+	}
+
+	rows, err := q.manager.executeQueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar %s: %w", q.entityName, err)
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+	return &ObjectCursor{ctx: ctx, rows: rows, columns: columns}, nil
+}
+
+// ObjectCursor percorre o resultado de uma consulta registro a registro
+type ObjectCursor struct {
+	ctx     context.Context
+	rows    *sql.Rows
+	columns []string
+	row     map[string]any
+	err     error
+}
+
+// Next avança para o próximo registro. Retorna false no fim do resultado, em caso de erro
+// ou de cancelamento do contexto (ver Err)
+func (c *ObjectCursor) Next() bool {
+	if c.err != nil {
+		return false
+	}
+	if err := c.ctx.Err(); err != nil {
+		c.err = err
+		return false
+	}
+	if !c.rows.Next() {
+		c.err = c.rows.Err()
+		return false
+	}
+
+	values := make([]any, len(c.columns))
+	valuePtrs := make([]any, len(c.columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+	if err := c.rows.Scan(valuePtrs...); err != nil {
+		c.err = err
+		return false
+	}
+
+	c.row = make(map[string]any, len(c.columns))
+	for i, col := range c.columns {
+		c.row[col] = values[i]
+	}
+	return true
+}
+
+// Columns retorna as colunas do resultado, na ordem da consulta
+func (c *ObjectCursor) Columns() []string {
+	return c.columns
+}
+
+// Row retorna o registro atual (um novo map a cada Next)
+func (c *ObjectCursor) Row() map[string]any {
+	return c.row
+}
+
+// Err retorna o erro que interrompeu a iteração (nil ao chegar ao fim do resultado)
+func (c *ObjectCursor) Err() error {
+	return c.err
+}
+
+// Close libera o cursor e a conexão
+func (c *ObjectCursor) Close() error {
+	return c.rows.Close()
+}
+
+// manage aplica o identity map à entidade lida: reutiliza a instância em cache ou passa a
+// gerenciar a nova, como em Find
+func (q *ObjectQuery) manage(result map[string]any) map[string]any {
+	om := q.manager
+	if !om.hasValidID(result) {
+		return result
+	}
+
+	key := om.extractKey(result, q.entityName)
+	if cached := om.getFromCache(q.entityName, key); cached != nil {
+		if entity, ok := cached.Entity.(map[string]any); ok {
+			return entity
+		}
+	}
+	om.addToCache(q.entityName, key, result)
+	om.attachToManager(result)
+	om.trackEntity(q.entityName, key, result)
+	return result
+}
+
+// build monta o SELECT com o query builder do provider (dialeto do banco)
+func (q *ObjectQuery) build() (string, []any, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+
+	builderProvider, ok := q.manager.provider.(interface{ GetQueryBuilder() *QueryBuilder })
+	if !ok {
+		return "", nil, fmt.Errorf("provider %s não suporta consultas parametrizadas", q.manager.provider.GetDriverName())
+	}
+	qb := builderProvider.GetQueryBuilder()
+
+	metadata := *q.manager.findEntityMetadata(q.entityName)
+	var tree *ParseNode
+	for _, filter := range q.filters {
+		walkParseTree(filter, func(node *ParseNode) {
+			if node.Token != nil && node.Token.Type == int(FilterTokenProperty) {
+				q.properties[node.Token.Value] = true
+			}
+		})
+		if tree == nil {
+			tree = filter
+			continue
+		}
+		tree = &ParseNode{
+			Token:    &Token{Type: int(FilterTokenLogical), Value: "and"},
+			Children: []*ParseNode{tree, filter},
+		}
+	}
+	for property := range q.properties {
+		metadata.Properties = append(metadata.Properties, PropertyMetadata{Name: property, ColumnName: property})
+	}
+
+	var query strings.Builder
+	var args []any
+	query.WriteString("SELECT ")
+	if len(q.selected) > 0 {
+		query.WriteString(qb.BuildSelectClause(metadata, q.selected))
+	} else {
+		query.WriteString("*")
+	}
+	query.WriteString(" FROM ")
+	query.WriteString(metadata.TableName)
+
+	if tree != nil {
+		where, whereArgs, err := qb.BuildWhereClause(q.manager.context, tree, metadata)
+		if err != nil {
+			return "", nil, fmt.Errorf("erro ao montar filtro: %w", err)
+		}
+		query.WriteString(" WHERE ")
+		query.WriteString(where)
+		args = append(args, whereArgs...)
+	}
+	if orderBy := qb.BuildOrderByClause(metadata, q.orderBy); orderBy != "" {
+		query.WriteString(" ORDER BY ")
+		query.WriteString(orderBy)
+	}
+	if limit := qb.BuildLimitClause(q.top, q.skip); limit != "" {
+		query.WriteString(" ")
+		query.WriteString(strings.TrimSpace(limit))
+	}
+
+	return query.String(), args, nil
+}
+
+// bindParameters substitui os marcadores da árvore pelos literais com os valores informados.
+// O valor fica em SemanticReference e chega ao banco como parâmetro, nunca como texto SQL
+func (q *ObjectQuery) bindParameters(tree *ParseNode, args []any) error {
+	var bindErr error
+	walkParseTree(tree, func(node *ParseNode) {
+		if bindErr != nil || node.Token == nil || node.Token.Type != int(FilterTokenProperty) {
+			return
+		}
+		if !strings.HasPrefix(node.Token.Value, queryParamPrefix) {
+			return
+		}
+
+		var index int
+		if _, err := fmt.Sscanf(node.Token.Value, queryParamPrefix+"%d", &index); err != nil || index >= len(args) {
+			bindErr = fmt.Errorf("parâmetro inválido: %s", node.Token.Value)
+			return
+		}
+		token, err := queryParameterToken(args[index])
+		if err != nil {
+			bindErr = fmt.Errorf("parâmetro %d: %w", index+1, err)
+			return
+		}
+		node.Token = token
+	})
+	return bindErr
+}
+
+// queryParameterToken converte o valor do parâmetro no literal correspondente do $filter
+func queryParameterToken(value any) (*Token, error) {
+	switch v := value.(type) {
+	case nil:
+		return &Token{Type: int(FilterTokenNull), Value: "null"}, nil
+	case string:
+		return &Token{Type: int(FilterTokenString), Value: "'" + v + "'", SemanticReference: v}, nil
+	case bool:
+		return &Token{Type: int(FilterTokenBoolean), Value: fmt.Sprintf("%t", v)}, nil
+	case time.Time:
+		return &Token{Type: int(FilterTokenDateTime), Value: v.Format(time.RFC3339Nano)}, nil
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return &Token{Type: int(FilterTokenNumber), Value: fmt.Sprintf("%v", value), SemanticReference: value}, nil
+	}
+	return nil, fmt.Errorf("tipo %T não suportado", value)
+}
+
+// replaceQueryPlaceholders troca cada "?" fora de literais string por um marcador e retorna
+// a expressão e a quantidade de marcadores
+func replaceQueryPlaceholders(filter string) (string, int) {
+	var expression strings.Builder
+	count := 0
+	inString := false
+	for i := 0; i < len(filter); i++ {
+		ch := filter[i]
+		switch {
+		case ch == '\'':
+			inString = !inString
+		case ch == '\\' && inString && i+1 < len(filter):
+			expression.WriteByte(ch)
+			i++
+			ch = filter[i]
+		case ch == '?' && !inString:
+			fmt.Fprintf(&expression, "%s%d", queryParamPrefix, count)
+			count++
+			continue
+		}
+		expression.WriteByte(ch)
+	}
+	return expression.String(), count
+}
@@ -0,0 +1,144 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectManager_Query(t *testing.T) {
+	names := func(rows []map[string]any) []string {
+		result := make([]string, 0, len(rows))
+		for _, row := range rows {
+			result = append(result, row["name"].(string))
+		}
+		return result
+	}
+
+	t.Run("Valores vinculados como parâmetros", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+
+		rows, err := manager.Query("products").Where("name eq ? or price gt ?", "Mouse", 100).OrderBy("id").List()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Mouse", "Monitor"}, names(rows))
+
+		rows, err = manager.Query("products").WhereEq("name", "Teclado").List()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Teclado"}, names(rows))
+	})
+
+	t.Run("Tentativa de injeção é tratada como valor", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+
+		rows, err := manager.Query("products").WhereEq("name", "x' or '1'='1").List()
+		require.NoError(t, err)
+		assert.Empty(t, rows)
+
+		rows, err = manager.Query("products").Where("name eq ?", "Mouse') or (1 eq 1").List()
+		require.NoError(t, err)
+		assert.Empty(t, rows)
+	})
+
+	t.Run("Ordenação, Top e Skip", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+
+		rows, err := manager.Query("products").Where("price ge ?", 10).OrderBy("price desc").Top(2).Skip(1).List()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Teclado", "Mouse"}, names(rows))
+	})
+
+	t.Run("Identity map compartilhado com Find", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+
+		mouse, err := manager.Find("products", "1")
+		require.NoError(t, err)
+		rows, err := manager.Query("products").WhereEq("id", 1).List()
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.True(t, sameEntity(mouse, rows[0]))
+	})
+
+	t.Run("Erros de uso", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+
+		_, err := manager.Query("products").Where("name eq ? and price eq ?", "Mouse").List()
+		assert.ErrorContains(t, err, "2 parâmetros")
+
+		_, err = manager.Query("products").WhereEq("name eq 'x' or id", 1).List()
+		assert.ErrorContains(t, err, "propriedade inválida")
+
+		_, err = manager.Query("products").OrderBy("id; DROP TABLE products").List()
+		assert.ErrorContains(t, err, "ordenação inválida")
+
+		_, err = manager.Query("products").Where("name eq ?", struct{}{}).List()
+		assert.ErrorContains(t, err, "não suportado")
+
+		// "?" dentro de literais não é um parâmetro
+		rows, err := manager.Query("products").Where("name eq '?'").List()
+		require.NoError(t, err)
+		assert.Empty(t, rows)
+	})
+}
+
+func TestObjectManager_QueryIterate(t *testing.T) {
+	t.Run("Iterate percorre o resultado sem usar o cache", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+
+		var names []string
+		err := manager.Query("products").Where("price lt ?", 100).OrderBy("id").Iterate(context.Background(), func(row map[string]any) error {
+			names = append(names, row["name"].(string))
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Mouse", "Teclado"}, names)
+		assert.False(t, manager.IsCached("products", "1"))
+	})
+
+	t.Run("Erro do callback interrompe a iteração", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+
+		calls := 0
+		err := manager.Query("products").Iterate(context.Background(), func(row map[string]any) error {
+			calls++
+			return errors.New("falha na exportação")
+		})
+		assert.EqualError(t, err, "falha na exportação")
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Cancelamento do contexto", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		calls := 0
+		err := manager.Query("products").OrderBy("id").Iterate(ctx, func(row map[string]any) error {
+			calls++
+			cancel()
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Stream retorna um cursor", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+
+		cursor, err := manager.Query("products").OrderBy("price desc").Stream(context.Background())
+		require.NoError(t, err)
+		defer cursor.Close()
+
+		var names []string
+		for cursor.Next() {
+			names = append(names, cursor.Row()["name"].(string))
+		}
+		require.NoError(t, cursor.Err())
+		assert.Equal(t, []string{"Monitor", "Teclado", "Mouse"}, names)
+
+		_, err = manager.Query("products").Where("name eq ?").Stream(context.Background())
+		assert.Error(t, err)
+	})
+}
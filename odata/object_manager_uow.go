@@ -0,0 +1,432 @@
+package odata
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// ==================================================
+// 7. UNIT OF WORK
+// ==================================================
+
+// unitOfWork mantém a transação e as alterações de um Begin até o Commit/Rollback
+type unitOfWork struct {
+	tx      *TxManager
+	tracked map[string]*uowEntry // Entidades carregadas por Find: "EntityName:Key" -> entrada
+	ops     []uowOperation       // Save, Update (detached) e Remove, na ordem das chamadas
+	applied []uowOperation       // Operações já gravadas na transação (Begin/Commit aninhados)
+	levels  []*uowLevel          // Begin aninhados, do mais externo ao mais interno
+}
+
+// uowLevel é um Begin aninhado: um savepoint na transação da unidade de trabalho e o estado
+// das entidades rastreadas no momento do Begin, restaurado pelo Rollback do nível
+type uowLevel struct {
+	savepoint *changesetSavepoint
+	applied   int                  // Operações gravadas antes do Begin
+	entries   map[string]*uowEntry // Entidades rastreadas no Begin
+	values    map[string]map[string]any
+}
+
+// uowOperation é uma operação enfileirada na unidade de trabalho
+type uowOperation struct {
+	opType     string // "INSERT", "UPDATE", "DELETE"
+	entityName string
+	key        string
+	entity     any
+	data       map[string]any
+}
+
+// uowEntry é uma entidade rastreada, com os valores carregados do banco
+type uowEntry struct {
+	entityName string
+	key        string
+	entity     any
+	snapshot   map[string]any // Valores carregados do banco (restaurados pelo Rollback)
+	flushed    map[string]any // Valores já gravados na transação (base das alterações)
+}
+
+// Begin inicia uma unidade de trabalho: as consultas passam a usar uma transação e as
+// alterações (Save, Update, Remove e modificações em entidades obtidas por Find) ficam
+// pendentes até Commit, que as grava de forma atômica. Um Begin com a unidade de trabalho
+// já iniciada cria um nível aninhado (SAVEPOINT): o Commit correspondente grava as
+// alterações pendentes na transação e o Rollback desfaz apenas as feitas após o Begin
+func (om *ObjectManager) Begin() error {
+	if uow := om.currentUnitOfWork(); uow != nil {
+		return om.beginNested(uow)
+	}
+
+	tx, err := om.BeginTransaction()
+	if err != nil {
+		return err
+	}
+
+	om.mu.Lock()
+	om.uow = &unitOfWork{tx: tx, tracked: make(map[string]*uowEntry)}
+	om.mu.Unlock()
+	return nil
+}
+
+// InUnitOfWork verifica se há uma unidade de trabalho ativa
+func (om *ObjectManager) InUnitOfWork() bool {
+	om.mu.RLock()
+	defer om.mu.RUnlock()
+	return om.uow != nil
+}
+
+// Commit grava as alterações pendentes na transação da unidade de trabalho e a confirma. Em
+// caso de erro, a transação é desfeita (como em Rollback) e nenhuma alteração é aplicada
+func (om *ObjectManager) Commit() error {
+	uow := om.currentUnitOfWork()
+	if uow == nil {
+		return fmt.Errorf("nenhuma unidade de trabalho ativa")
+	}
+	if len(uow.levels) > 0 {
+		return om.commitNested(uow)
+	}
+
+	if err := om.flushUnitOfWork(uow); err != nil {
+		om.Rollback()
+		return err
+	}
+	if err := om.CommitTransaction(uow.tx); err != nil {
+		om.Rollback()
+		return err
+	}
+
+	om.mu.Lock()
+	om.uow = nil
+	om.mu.Unlock()
+
+	// Os valores gravados passam a ser o estado atual das entidades no cache
+	for cacheKey := range uow.tracked {
+		om.mu.Lock()
+		delete(om.changes, cacheKey)
+		if cached := om.cache[cacheKey]; cached != nil {
+			cached.IsChanged = false
+		}
+		om.mu.Unlock()
+	}
+	for _, op := range uow.applied {
+		switch op.opType {
+		case "INSERT":
+			om.addToCache(op.entityName, op.key, op.entity)
+		case "DELETE":
+			om.removeFromCache(op.entityName, op.key)
+		}
+	}
+	return nil
+}
+
+// Rollback desfaz a transação da unidade de trabalho, descarta as operações pendentes e
+// restaura as entidades rastreadas (map) aos valores carregados do banco. Em um nível
+// aninhado, desfaz apenas as alterações feitas após o Begin correspondente
+func (om *ObjectManager) Rollback() error {
+	uow := om.currentUnitOfWork()
+	if uow == nil {
+		return fmt.Errorf("nenhuma unidade de trabalho ativa")
+	}
+	if len(uow.levels) > 0 {
+		return om.rollbackNested(uow)
+	}
+
+	om.mu.Lock()
+	om.uow = nil
+	om.mu.Unlock()
+
+	for cacheKey, entry := range uow.tracked {
+		om.restoreTrackedEntity(cacheKey, entry, entry.snapshot)
+	}
+
+	return om.RollbackTransaction(uow.tx)
+}
+
+// restoreTrackedEntity restaura uma entidade rastreada (map) aos valores informados
+func (om *ObjectManager) restoreTrackedEntity(cacheKey string, entry *uowEntry, values map[string]any) bool {
+	om.mu.Lock()
+	delete(om.changes, cacheKey)
+	om.mu.Unlock()
+
+	entityMap, ok := entry.entity.(map[string]any)
+	if !ok {
+		// Structs não são restauradas: saem do cache para serem recarregadas
+		om.removeFromCache(entry.entityName, entry.key)
+		return false
+	}
+	for k := range entityMap {
+		delete(entityMap, k)
+	}
+	for k, v := range values {
+		entityMap[k] = v
+	}
+	return true
+}
+
+// beginNested grava as alterações pendentes e cria o savepoint de um Begin aninhado
+func (om *ObjectManager) beginNested(uow *unitOfWork) error {
+	if err := om.flushUnitOfWork(uow); err != nil {
+		return err
+	}
+
+	sp, err := newSavepoint(om.context, om.provider, uow.tx.tx.Tx, fmt.Sprintf("uow_%d", len(uow.levels)+1))
+	if err != nil {
+		return err
+	}
+
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	level := &uowLevel{
+		savepoint: sp,
+		applied:   len(uow.applied),
+		entries:   make(map[string]*uowEntry, len(uow.tracked)),
+		values:    make(map[string]map[string]any, len(uow.tracked)),
+	}
+	for cacheKey, entry := range uow.tracked {
+		level.entries[cacheKey] = entry
+		level.values[cacheKey] = copyValues(entry.flushed)
+	}
+	uow.levels = append(uow.levels, level)
+	return nil
+}
+
+// commitNested grava as alterações do nível na transação e libera o savepoint. Em caso de
+// erro, o nível é desfeito (como em Rollback)
+func (om *ObjectManager) commitNested(uow *unitOfWork) error {
+	if err := om.flushUnitOfWork(uow); err != nil {
+		om.rollbackNested(uow)
+		return err
+	}
+
+	level := uow.levels[len(uow.levels)-1]
+	om.mu.Lock()
+	uow.levels = uow.levels[:len(uow.levels)-1]
+	om.mu.Unlock()
+	return level.savepoint.releaseSavepoint(om.context)
+}
+
+// rollbackNested volta ao savepoint do nível, descarta as operações feitas após o Begin e
+// restaura as entidades rastreadas ao estado do Begin
+func (om *ObjectManager) rollbackNested(uow *unitOfWork) error {
+	om.mu.Lock()
+	level := uow.levels[len(uow.levels)-1]
+	uow.levels = uow.levels[:len(uow.levels)-1]
+	uow.ops = nil
+	uow.applied = uow.applied[:level.applied]
+	tracked := uow.tracked
+	uow.tracked = make(map[string]*uowEntry, len(level.entries))
+	om.mu.Unlock()
+
+	for cacheKey, entry := range tracked {
+		if _, existed := level.entries[cacheKey]; !existed {
+			// Carregada após o Begin: volta aos valores do banco, sem rastreamento
+			om.restoreTrackedEntity(cacheKey, entry, entry.snapshot)
+		}
+	}
+	for cacheKey, entry := range level.entries {
+		values := level.values[cacheKey]
+		if om.restoreTrackedEntity(cacheKey, entry, values) {
+			entry.flushed = copyValues(values)
+			om.mu.Lock()
+			uow.tracked[cacheKey] = entry
+			om.mu.Unlock()
+		}
+	}
+
+	return level.savepoint.rollback(om.context)
+}
+
+// UnitOfWork executa fn em uma unidade de trabalho: Commit se fn retornar nil, Rollback em
+// caso de erro ou panic
+func (om *ObjectManager) UnitOfWork(fn func() error) error {
+	if err := om.Begin(); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			om.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(); err != nil {
+		om.Rollback()
+		return err
+	}
+	return om.Commit()
+}
+
+// currentUnitOfWork retorna a unidade de trabalho ativa (nil se não houver)
+func (om *ObjectManager) currentUnitOfWork() *unitOfWork {
+	om.mu.RLock()
+	defer om.mu.RUnlock()
+	return om.uow
+}
+
+// trackEntity registra uma entidade carregada por Find na unidade de trabalho ativa
+func (om *ObjectManager) trackEntity(entityName, key string, entity any) {
+	uow := om.currentUnitOfWork()
+	if uow == nil {
+		return
+	}
+
+	cacheKey := om.buildCacheKey(entityName, key)
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	if _, exists := uow.tracked[cacheKey]; exists {
+		return
+	}
+
+	snapshot := copyValues(om.entityToMap(entity))
+	uow.tracked[cacheKey] = &uowEntry{entityName: entityName, key: key, entity: entity, snapshot: snapshot, flushed: copyValues(snapshot)}
+}
+
+// copyValues copia os valores de uma entidade
+func copyValues(values map[string]any) map[string]any {
+	copied := make(map[string]any, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+	return copied
+}
+
+// trackedEntry retorna a entrada da entidade rastreada (a mesma instância retornada por Find)
+func (uow *unitOfWork) trackedEntry(entity any) (string, *uowEntry) {
+	for cacheKey, entry := range uow.tracked {
+		if sameEntity(entry.entity, entity) {
+			return cacheKey, entry
+		}
+	}
+	return "", nil
+}
+
+// queueOperation adiciona Save, Update ou Remove à unidade de trabalho ativa. Retorna false
+// se não houver unidade de trabalho
+func (om *ObjectManager) queueOperation(opType string, entity any) (bool, error) {
+	uow := om.currentUnitOfWork()
+	if uow == nil {
+		return false, nil
+	}
+
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	cacheKey, entry := uow.trackedEntry(entity)
+	switch {
+	case entry != nil && opType == "UPDATE":
+		// Entidades rastreadas são comparadas com o snapshot no Commit
+		om.changes[cacheKey] = true
+		return true, nil
+	case entry != nil && opType == "DELETE":
+		delete(uow.tracked, cacheKey)
+		uow.ops = append(uow.ops, uowOperation{opType: opType, entityName: entry.entityName, key: entry.key, entity: entity, data: entry.snapshot})
+		return true, nil
+	}
+
+	entityName := om.getEntityType(entity)
+	if entityName == "" {
+		return true, fmt.Errorf("entidade %T não identificada: use structs ou entidades obtidas por Find", entity)
+	}
+	entityData := om.entityToMap(entity)
+	uow.ops = append(uow.ops, uowOperation{
+		opType:     opType,
+		entityName: entityName,
+		key:        om.extractKey(entityData, entityName),
+		entity:     entity,
+		data:       entityData,
+	})
+	return true, nil
+}
+
+// flushUnitOfWork executa as operações pendentes e as alterações das entidades rastreadas
+// na transação da unidade de trabalho
+func (om *ObjectManager) flushUnitOfWork(uow *unitOfWork) error {
+	for i, op := range uow.ops {
+		if err := om.execUnitOfWorkOperation(uow.tx.tx.Tx, op.opType, op.entityName, op.data, op.data); err != nil {
+			return fmt.Errorf("erro no %s %d (%s:%s): %w", op.opType, i, op.entityName, op.key, err)
+		}
+	}
+
+	for _, entry := range uow.tracked {
+		current := om.entityToMap(entry.entity)
+		changed := make(map[string]any)
+		for k, v := range current {
+			if original, ok := entry.flushed[k]; !ok || !reflect.DeepEqual(original, v) {
+				changed[k] = v
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		if err := om.execUnitOfWorkOperation(uow.tx.tx.Tx, "UPDATE", entry.entityName, changed, entry.snapshot); err != nil {
+			return fmt.Errorf("erro no UPDATE (%s:%s): %w", entry.entityName, entry.key, err)
+		}
+		entry.flushed = copyValues(current)
+	}
+
+	om.logger.Printf("✅ Unidade de trabalho gravada: %d operações, %d entidades rastreadas", len(uow.ops), len(uow.tracked))
+
+	om.mu.Lock()
+	uow.applied = append(uow.applied, uow.ops...)
+	uow.ops = nil
+	om.mu.Unlock()
+	return nil
+}
+
+// execUnitOfWorkOperation monta a query com o provider (dialeto do banco) e a executa na
+// transação. keyData contém o valor da chave da entidade
+func (om *ObjectManager) execUnitOfWorkOperation(tx *sql.Tx, opType, entityName string, data, keyData map[string]any) error {
+	metadata := *om.findEntityMetadata(entityName)
+	keyProperty := om.getKeyProperty(&metadata)
+	metadata.Keys = []string{keyProperty.Name}
+	metadata.Properties = append(metadata.Properties, PropertyMetadata{Name: keyProperty.Name, ColumnName: keyProperty.Name, IsKey: true})
+	for name := range data {
+		if name != keyProperty.Name {
+			metadata.Properties = append(metadata.Properties, PropertyMetadata{Name: name, ColumnName: name})
+		}
+	}
+	keyValues := map[string]any{keyProperty.Name: keyData[keyProperty.Name]}
+
+	var query string
+	var args []any
+	var err error
+	switch opType {
+	case "INSERT":
+		query, args, err = om.provider.BuildInsertQuery(metadata, data)
+	case "UPDATE":
+		updateData := make(map[string]any, len(data))
+		for k, v := range data {
+			if k != keyProperty.Name {
+				updateData[k] = v
+			}
+		}
+		if len(updateData) == 0 {
+			return nil
+		}
+		query, args, err = om.provider.BuildUpdateQuery(metadata, updateData, keyValues)
+	case "DELETE":
+		query, args, err = om.provider.BuildDeleteQuery(metadata, keyValues)
+	default:
+		return fmt.Errorf("operação '%s' não suportada", opType)
+	}
+	if err != nil {
+		return err
+	}
+
+	om.logger.Printf("💾 Executando %s para %s na unidade de trabalho", opType, entityName)
+	_, err = tx.ExecContext(om.context, query, args...)
+	return err
+}
+
+// sameEntity verifica se os dois valores são a mesma instância (map ou ponteiro)
+func sameEntity(a, b any) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() != vb.Kind() {
+		return false
+	}
+	switch va.Kind() {
+	case reflect.Map, reflect.Ptr:
+		return va.Pointer() == vb.Pointer()
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUnitOfWorkTestManager(t *testing.T) (*ObjectManager, *sql.DB) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE products (id INTEGER PRIMARY KEY, name TEXT, price REAL)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO products (id, name, price) VALUES (1, 'Mouse', 10), (2, 'Teclado', 50), (3, 'Monitor', 900)")
+	require.NoError(t, err)
+
+	return NewObjectManager(NewMySQLProvider(db), context.Background()), db
+}
+
+func queryProducts(t *testing.T, db *sql.DB) map[int64]string {
+	t.Helper()
+	rows, err := db.Query("SELECT id, name, price FROM products")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	products := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var name string
+		var price float64
+		require.NoError(t, rows.Scan(&id, &name, &price))
+		products[id] = name + ":" + strconv.FormatFloat(price, 'f', -1, 64)
+	}
+	return products
+}
+
+func TestObjectManager_UnitOfWork(t *testing.T) {
+	type Products struct {
+		ID    int64   `json:"id"`
+		Name  string  `json:"name"`
+		Price float64 `json:"price"`
+	}
+
+	t.Run("Commit grava as alterações de forma atômica", func(t *testing.T) {
+		manager, db := newUnitOfWorkTestManager(t)
+		require.NoError(t, manager.Begin())
+
+		mouse, err := manager.Find("products", "1")
+		require.NoError(t, err)
+		keyboard, err := manager.Find("products", "2")
+		require.NoError(t, err)
+		monitor, err := manager.Find("products", "3")
+		require.NoError(t, err)
+
+		// Alterações em entidades rastreadas são detectadas sem chamar Update
+		mouse.(map[string]any)["price"] = 15.0
+		keyboard.(map[string]any)["name"] = "Teclado ABNT2"
+		require.NoError(t, manager.Update(keyboard))
+		require.NoError(t, manager.Save(&Products{ID: 4, Name: "Cabo", Price: 5}))
+		require.NoError(t, manager.Remove(monitor))
+
+		require.NoError(t, manager.Commit())
+		assert.False(t, manager.InUnitOfWork())
+		assert.Equal(t, map[int64]string{1: "Mouse:15", 2: "Teclado ABNT2:50", 4: "Cabo:5"}, queryProducts(t, db))
+		assert.True(t, manager.IsCached("Products", "4"))
+		assert.False(t, manager.IsCached("products", "3"))
+	})
+
+	t.Run("Erro no Commit desfaz todas as alterações", func(t *testing.T) {
+		manager, db := newUnitOfWorkTestManager(t)
+		require.NoError(t, manager.Begin())
+
+		mouse, err := manager.Find("products", "1")
+		require.NoError(t, err)
+		mouse.(map[string]any)["price"] = 99.0
+		require.NoError(t, manager.Save(&Products{ID: 2, Name: "Duplicado"}))
+
+		assert.Error(t, manager.Commit())
+		assert.False(t, manager.InUnitOfWork())
+		assert.Equal(t, 10.0, mouse.(map[string]any)["price"], "entidade restaurada")
+		assert.Equal(t, "Mouse:10", queryProducts(t, db)[1])
+	})
+
+	t.Run("UnitOfWork faz rollback em caso de erro", func(t *testing.T) {
+		manager, db := newUnitOfWorkTestManager(t)
+
+		err := manager.UnitOfWork(func() error {
+			monitor, err := manager.Find("products", "3")
+			if err != nil {
+				return err
+			}
+			monitor.(map[string]any)["price"] = 1.0
+			return errors.New("estoque insuficiente")
+		})
+		assert.EqualError(t, err, "estoque insuficiente")
+		assert.Equal(t, "Monitor:900", queryProducts(t, db)[3])
+
+		require.NoError(t, manager.UnitOfWork(func() error {
+			monitor, err := manager.Find("products", "3")
+			if err != nil {
+				return err
+			}
+			monitor.(map[string]any)["price"] = 850.0
+			return nil
+		}))
+		assert.Equal(t, "Monitor:850", queryProducts(t, db)[3])
+	})
+
+	t.Run("Rollback aninhado preserva as alterações externas", func(t *testing.T) {
+		manager, db := newUnitOfWorkTestManager(t)
+		require.NoError(t, manager.Begin())
+
+		mouse, err := manager.Find("products", "1")
+		require.NoError(t, err)
+		mouse.(map[string]any)["price"] = 12.0
+
+		require.NoError(t, manager.Begin())
+		mouse.(map[string]any)["price"] = 99.0
+		keyboard, err := manager.Find("products", "2")
+		require.NoError(t, err)
+		keyboard.(map[string]any)["name"] = "Teclado sem fio"
+		require.NoError(t, manager.Save(&Products{ID: 5, Name: "Hub", Price: 30}))
+		require.NoError(t, manager.Rollback())
+
+		assert.True(t, manager.InUnitOfWork())
+		assert.Equal(t, 12.0, mouse.(map[string]any)["price"], "restaurada ao estado do Begin aninhado")
+		assert.Equal(t, "Teclado", keyboard.(map[string]any)["name"])
+
+		require.NoError(t, manager.Commit())
+		assert.False(t, manager.InUnitOfWork())
+		assert.Equal(t, map[int64]string{1: "Mouse:12", 2: "Teclado:50", 3: "Monitor:900"}, queryProducts(t, db))
+		assert.False(t, manager.IsCached("Products", "5"))
+	})
+
+	t.Run("Commit aninhado é desfeito pelo Rollback externo", func(t *testing.T) {
+		manager, db := newUnitOfWorkTestManager(t)
+
+		// Código de biblioteca compõe UnitOfWork sem saber se já existe uma unidade externa
+		reprice := func(id string, price float64) error {
+			return manager.UnitOfWork(func() error {
+				product, err := manager.Find("products", id)
+				if err != nil {
+					return err
+				}
+				product.(map[string]any)["price"] = price
+				return nil
+			})
+		}
+
+		err := manager.UnitOfWork(func() error {
+			if err := reprice("3", 800); err != nil {
+				return err
+			}
+			assert.True(t, manager.InUnitOfWork())
+			return errors.New("pedido cancelado")
+		})
+		assert.EqualError(t, err, "pedido cancelado")
+		assert.Equal(t, "Monitor:900", queryProducts(t, db)[3])
+
+		require.NoError(t, reprice("3", 750))
+		assert.Equal(t, "Monitor:750", queryProducts(t, db)[3])
+	})
+
+	t.Run("Commit e Rollback sem Begin", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+		assert.Error(t, manager.Commit())
+		assert.Error(t, manager.Rollback())
+	})
+}
@@ -0,0 +1,143 @@
+package odata
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// PAYLOAD DE ERRO PADRÃO OData
+// =======================================================================================
+
+// ODataErrorResponse representa o corpo de uma resposta de erro: {"error": {...}}
+type ODataErrorResponse struct {
+	Error *ODataError `json:"error"`
+}
+
+// ODataInnerError contém detalhes internos do erro, incluídos apenas no modo de debug
+type ODataInnerError struct {
+	Type       string `json:"type,omitempty"`
+	Message    string `json:"message,omitempty"`
+	StackTrace string `json:"stacktrace,omitempty"`
+}
+
+// Error implementa a interface error, permitindo retornar *ODataError de handlers
+func (e *ODataError) Error() string {
+	if e.Target != "" {
+		return fmt.Sprintf("%s: %s (%s)", e.Code, e.Message, e.Target)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// WithStatus define o status HTTP usado quando o erro é retornado por um handler
+func (e *ODataError) WithStatus(status int) *ODataError {
+	e.Status = status
+	return e
+}
+
+// WithTarget define o alvo do erro (propriedade, entidade ou opção de query)
+func (e *ODataError) WithTarget(target string) *ODataError {
+	e.Target = target
+	return e
+}
+
+// WithDetail adiciona um detalhe ao erro
+func (e *ODataError) WithDetail(code, message, target string) *ODataError {
+	e.Details = append(e.Details, ODataErrorDetail{Code: code, Message: message, Target: target})
+	return e
+}
+
+// ODataErrorCode retorna o código de erro padrão para um status HTTP
+func ODataErrorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "BadRequest"
+	case http.StatusUnauthorized:
+		return "Unauthorized"
+	case http.StatusForbidden:
+		return "Forbidden"
+	case http.StatusNotFound:
+		return "NotFound"
+	case http.StatusMethodNotAllowed:
+		return "MethodNotAllowed"
+	case http.StatusConflict:
+		return "Conflict"
+	case http.StatusPreconditionFailed:
+		return "PreconditionFailed"
+	case http.StatusRequestEntityTooLarge:
+		return "PayloadTooLarge"
+	case http.StatusFailedDependency:
+		return "FailedDependency"
+	case http.StatusTooManyRequests:
+		return "TooManyRequests"
+	case http.StatusNotImplemented:
+		return "NotImplemented"
+	case http.StatusServiceUnavailable:
+		return "ServiceUnavailable"
+	case http.StatusGatewayTimeout:
+		return "Timeout"
+	default:
+		if status >= http.StatusInternalServerError {
+			return "InternalServerError"
+		}
+		return http.StatusText(status)
+	}
+}
+
+// toODataError converte um erro qualquer em *ODataError e status HTTP
+func toODataError(err error) (*ODataError, int) {
+	var odataErr *ODataError
+	if errors.As(err, &odataErr) {
+		status := odataErr.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		return odataErr, status
+	}
+
+	if violation, ok := constraintViolation(err); ok {
+		return violation.ODataError(), violation.StatusCode()
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return NewODataError(ODataErrorCode(fiberErr.Code), fiberErr.Message), fiberErr.Code
+	}
+
+	return NewODataError(ODataErrorCode(http.StatusInternalServerError), err.Error()), http.StatusInternalServerError
+}
+
+// debugErrors indica se as respostas de erro devem incluir innererror
+func (s *Server) debugErrors() bool {
+	return s.config != nil && s.config.DebugErrors
+}
+
+// writeODataError escreve o payload de erro OData. No modo de debug inclui innererror
+// com o tipo e a mensagem da causa (se informada) e o stack trace
+func (s *Server) writeODataError(c fiber.Ctx, status int, odataErr *ODataError, cause error) error {
+	if s.debugErrors() && odataErr.InnerError == nil {
+		inner := &ODataInnerError{StackTrace: string(debug.Stack())}
+		if cause != nil {
+			inner.Type = fmt.Sprintf("%T", cause)
+			inner.Message = cause.Error()
+		}
+		odataErr.InnerError = inner
+	}
+
+	c.Set("Content-Type", "application/json")
+	return c.Status(status).JSON(ODataErrorResponse{Error: odataErr})
+}
+
+// handleFiberError é o ErrorHandler do Fiber: converte erros retornados por handlers e
+// middlewares (fiber.NewError, *ODataError ou erros comuns) no payload OData
+func (s *Server) handleFiberError(c fiber.Ctx, err error) error {
+	odataErr, status := toODataError(err)
+	if status >= http.StatusInternalServerError && s.logger != nil {
+		s.logger.Printf("❌ Erro em %s %s: %v", c.Method(), c.Path(), err)
+	}
+	return s.writeODataError(c, status, odataErr, err)
+}
@@ -0,0 +1,138 @@
+package odata
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeODataError(t *testing.T, resp *http.Response) map[string]interface{} {
+	t.Helper()
+
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &payload), string(raw))
+	assert.NotContains(t, payload, "value", "payload de erro não deve conter value")
+
+	errorObj, ok := payload["error"].(map[string]interface{})
+	require.True(t, ok, string(raw))
+	return errorObj
+}
+
+func TestODataErrorCode(t *testing.T) {
+	assert.Equal(t, "BadRequest", ODataErrorCode(http.StatusBadRequest))
+	assert.Equal(t, "Forbidden", ODataErrorCode(http.StatusForbidden))
+	assert.Equal(t, "TooManyRequests", ODataErrorCode(http.StatusTooManyRequests))
+	assert.Equal(t, "InternalServerError", ODataErrorCode(http.StatusBadGateway))
+}
+
+func TestToODataError(t *testing.T) {
+	odataErr, status := toODataError(NewODataError("Conflict", "versão desatualizada").WithStatus(http.StatusConflict))
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, "Conflict", odataErr.Code)
+
+	odataErr, status = toODataError(fiber.NewError(fiber.StatusForbidden, "Entidade Users é apenas leitura"))
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, "Forbidden", odataErr.Code)
+	assert.Equal(t, "Entidade Users é apenas leitura", odataErr.Message)
+
+	odataErr, status = toODataError(errors.New("falha inesperada"))
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, "InternalServerError", odataErr.Code)
+}
+
+func TestServer_handleFiberError(t *testing.T) {
+	server := &Server{config: DefaultServerConfig()}
+
+	app := fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
+	app.Get("/forbidden", func(c fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusForbidden, "Role necessária para acessar Users")
+	})
+	app.Get("/odata-error", func(c fiber.Ctx) error {
+		return NewODataError("ValidationError", "Dados inválidos").
+			WithStatus(fiber.StatusBadRequest).
+			WithTarget("Users").
+			WithDetail("Required", "Campo obrigatório", "email")
+	})
+
+	t.Run("fiber.NewError vira payload OData", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/forbidden", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		errorObj := decodeODataError(t, resp)
+		assert.Equal(t, "Forbidden", errorObj["code"])
+		assert.Equal(t, "Role necessária para acessar Users", errorObj["message"])
+		assert.NotContains(t, errorObj, "innererror")
+	})
+
+	t.Run("ODataError preserva status, target e details", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/odata-error", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		errorObj := decodeODataError(t, resp)
+		assert.Equal(t, "ValidationError", errorObj["code"])
+		assert.Equal(t, "Users", errorObj["target"])
+		details := errorObj["details"].([]interface{})
+		require.Len(t, details, 1)
+		assert.Equal(t, "email", details[0].(map[string]interface{})["target"])
+	})
+
+	t.Run("Modo debug inclui innererror", func(t *testing.T) {
+		server.SetDebugErrors(true)
+		defer server.SetDebugErrors(false)
+
+		app := fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
+		app.Get("/boom", func(c fiber.Ctx) error {
+			return errors.New("conexão perdida")
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/boom", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+		errorObj := decodeODataError(t, resp)
+		inner, ok := errorObj["innererror"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "conexão perdida", inner["message"])
+		assert.Equal(t, "*errors.errorString", inner["type"])
+		assert.Contains(t, inner["stacktrace"], "writeODataError")
+	})
+}
+
+func TestServer_RateLimitErrorPayload(t *testing.T) {
+	server := &Server{config: DefaultServerConfig(), logger: log.New(io.Discard, "", 0)}
+	server.SetRateLimitConfig(&RateLimitConfig{
+		Enabled:           true,
+		RequestsPerMinute: 1,
+		BurstSize:         1,
+		WindowSize:        time.Minute,
+		KeyGenerator:      defaultKeyGenerator,
+	})
+
+	app := fiber.New()
+	app.Use(server.RateLimitMiddleware())
+	app.Get("/", func(c fiber.Ctx) error { return c.SendString("ok") })
+
+	_, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	errorObj := decodeODataError(t, resp)
+	assert.Equal(t, "RateLimitExceeded", errorObj["code"])
+	assert.Equal(t, "rate_limit", errorObj["target"])
+}
@@ -0,0 +1,69 @@
+package odata
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestODataParser_ParseOrderByExpressions(t *testing.T) {
+	expressions, err := NewODataParser().ParseOrderBy("price mul 2 desc, tolower(name), concat(name, 'x') asc, id")
+	require.NoError(t, err)
+	require.Len(t, expressions, 4)
+
+	assert.Equal(t, OrderDesc, expressions[0].Direction)
+	require.NotNil(t, expressions[0].Expression)
+	assert.Equal(t, "mul", expressions[0].Expression.Token.Value)
+
+	assert.Equal(t, OrderAsc, expressions[1].Direction)
+	require.NotNil(t, expressions[1].Expression)
+	assert.Equal(t, "tolower", expressions[1].Expression.Token.Value)
+
+	assert.Equal(t, "concat(name, 'x')", expressions[2].Property)
+	require.NotNil(t, expressions[2].Expression)
+
+	assert.Equal(t, "id", expressions[3].Property)
+	assert.Nil(t, expressions[3].Expression)
+
+	_, err = NewODataParser().ParseOrderBy("price mul")
+	assert.Error(t, err)
+}
+
+func TestServer_OrderByExpressions(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	names := func(t *testing.T, orderBy string) []string {
+		t.Helper()
+		status, body := getComputeTestProducts(t, server, "$orderby="+url.QueryEscape(orderBy))
+		require.Equal(t, http.StatusOK, status, body)
+
+		var result []string
+		for _, value := range body["value"].([]any) {
+			result = append(result, value.(map[string]any)["name"].(string))
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"Monitor", "Teclado", "Mouse"}, names(t, "price mul 2 desc"))
+	assert.Equal(t, []string{"Monitor", "Mouse", "Teclado"}, names(t, "tolower(name)"))
+	// mul tem precedência: price - id*100 = -90, -150, 600
+	assert.Equal(t, []string{"Teclado", "Mouse", "Monitor"}, names(t, "price sub id mul 100, name desc"))
+
+	t.Run("Com $filter e $compute", func(t *testing.T) {
+		query := "$compute=" + url.QueryEscape("price mul 3 as Triple") +
+			"&$filter=" + url.QueryEscape("price gt 20") +
+			"&$orderby=" + url.QueryEscape("(Triple sub price) mul 10 desc")
+		status, body := getComputeTestProducts(t, server, query)
+		require.Equal(t, http.StatusOK, status, body)
+
+		values := body["value"].([]any)
+		require.Len(t, values, 2)
+		assert.Equal(t, "Monitor", values[0].(map[string]any)["name"])
+	})
+}
@@ -0,0 +1,451 @@
+package odata
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// PAGINAÇÃO DIRIGIDA PELO SERVIDOR (@odata.nextLink)
+// =======================================================================================
+
+// queryOptionSkipToken posiciona a próxima página no modo keyset
+const queryOptionSkipToken = "$skiptoken"
+
+// TruncatedAnnotation indica que a coleção foi truncada pelo MaxRowsPerRequest do servidor
+const TruncatedAnnotation = "@godata.truncated"
+
+// PaginationMode define como o @odata.nextLink posiciona a próxima página
+type PaginationMode int
+
+const (
+	// PaginationOffset usa $skip: simples e compatível com qualquer $orderby, mas inserções e
+	// exclusões entre as páginas deslocam o resultado (linhas repetidas ou perdidas)
+	PaginationOffset PaginationMode = iota
+	// PaginationKeyset usa $skiptoken com os valores de ordenação do último registro (mais a
+	// chave como desempate): estável sob escritas concorrentes e sem custo de OFFSET
+	PaginationKeyset
+)
+
+// PaginationConfig configura a paginação dirigida pelo servidor de uma entidade
+type PaginationConfig struct {
+	PageSize int            // Registros por página; consultas maiores recebem @odata.nextLink
+	Mode     PaginationMode // Posicionamento da próxima página (padrão: PaginationOffset)
+}
+
+// WithPagination limita as páginas da coleção a PageSize registros e gera o @odata.nextLink
+// das seguintes. No modo keyset, ordenações não suportadas usam $skip automaticamente
+func WithPagination(config PaginationConfig) EntityOption {
+	return func(entityConfig *EntityConfig) {
+		entityConfig.Pagination = &config
+	}
+}
+
+// registerPagination guarda a configuração de paginação da entidade
+func (s *Server) registerPagination(entityName string, config PaginationConfig) error {
+	if config.PageSize <= 0 {
+		return fmt.Errorf("PageSize da paginação deve ser maior que zero")
+	}
+	if config.Mode != PaginationOffset && config.Mode != PaginationKeyset {
+		return fmt.Errorf("modo de paginação inválido: %d", config.Mode)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.paginations == nil {
+		s.paginations = make(map[string]PaginationConfig)
+	}
+	s.paginations[entityName] = config
+	return nil
+}
+
+// getPagination retorna a configuração de paginação da entidade, se houver
+func (s *Server) getPagination(entityName string) (PaginationConfig, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	config, ok := s.paginations[entityName]
+	return config, ok
+}
+
+// keysetItem é uma propriedade da ordenação do keyset
+type keysetItem struct {
+	prop PropertyMetadata
+	desc bool
+}
+
+// pagination é a página de uma consulta paginada pelo servidor
+type pagination struct {
+	mode      PaginationMode
+	limit     int          // Registros da página
+	remaining *int         // $top do cliente restante após a página (nil: sem $top)
+	skip      int          // $skip da requisição (modo offset)
+	order     []keysetItem // Ordenação com desempate pela chave (modo keyset)
+	capped    bool         // Página limitada pelo MaxRowsPerRequest (busca um registro a mais)
+	truncated bool         // A consulta tinha registros além do MaxRowsPerRequest
+}
+
+// preparePagination limita as options à página e, no modo keyset, completa o $orderby com a
+// chave e aplica o $skiptoken. Retorna nil quando a consulta cabe em uma página
+func (s *Server) preparePagination(c fiber.Ctx, entityName string, metadata EntityMetadata, options *QueryOptions) (*pagination, error) {
+	token := c.Query(queryOptionSkipToken)
+	maxRows := s.maxRowsPerRequest()
+	config, ok := s.getPagination(entityName)
+	if !ok {
+		if token != "" {
+			return nil, fmt.Errorf("$skiptoken is not supported by entity %s", entityName)
+		}
+		if maxRows == 0 {
+			return nil, nil
+		}
+		config = PaginationConfig{PageSize: maxRows, Mode: PaginationOffset}
+	}
+
+	page := &pagination{mode: config.Mode, limit: config.PageSize}
+	if maxRows > 0 && page.limit >= maxRows {
+		page.limit = maxRows
+		page.capped = true
+	}
+	if options.Top != nil {
+		top := int(*options.Top)
+		if top <= page.limit && token == "" {
+			return nil, nil
+		}
+		remaining := 0
+		if top > page.limit {
+			remaining = top - page.limit
+		} else {
+			page.limit = top
+		}
+		page.remaining = &remaining
+	}
+	if options.Skip != nil {
+		page.skip = int(*options.Skip)
+	}
+
+	if page.mode == PaginationKeyset {
+		order, ok := s.keysetOrder(metadata, options)
+		if !ok {
+			// Fallback: ordenação sem keyset estável usa $skip
+			page.mode = PaginationOffset
+		} else {
+			page.order = order
+			options.OrderBy = keysetOrderBy(order)
+		}
+	}
+
+	if token != "" {
+		if page.mode != PaginationKeyset {
+			return nil, fmt.Errorf("invalid $skiptoken: keyset pagination is not available for this query")
+		}
+		if err := s.applySkipToken(options, page.order, token); err != nil {
+			return nil, err
+		}
+	}
+
+	top := GoDataTopQuery(page.limit)
+	if page.capped {
+		// O registro a mais indica se o limite truncou a consulta
+		top++
+	}
+	options.Top = &top
+	return page, nil
+}
+
+// maxRowsPerRequest retorna o limite de registros por resposta de coleção (0 = sem limite)
+func (s *Server) maxRowsPerRequest() int {
+	if s.config == nil || s.config.MaxRowsPerRequest < 0 {
+		return 0
+	}
+	return s.config.MaxRowsPerRequest
+}
+
+// truncate descarta o registro a mais buscado pela página limitada pelo MaxRowsPerRequest
+func (p *pagination) truncate(results []interface{}) []interface{} {
+	if !p.capped || len(results) <= p.limit {
+		return results
+	}
+	p.truncated = true
+	return results[:p.limit]
+}
+
+// truncatedAnnotation descreve o truncamento pelo MaxRowsPerRequest
+func (p *pagination) truncatedAnnotation() map[string]interface{} {
+	return map[string]interface{}{
+		"maxRows": p.limit,
+		"message": fmt.Sprintf("Result truncated to %d rows by the server limit: use @odata.nextLink or $top to read the remaining rows", p.limit),
+	}
+}
+
+// keysetOrder resolve o $orderby em propriedades comparáveis, completado pela chave. Retorna
+// false quando o keyset não é confiável: expressões, navegações, propriedades nullable ou
+// criptografadas, tipos sem literal estável ou propriedades fora do $select
+func (s *Server) keysetOrder(metadata EntityMetadata, options *QueryOptions) ([]keysetItem, bool) {
+	if len(metadata.Keys) == 0 {
+		return nil, false
+	}
+	caseInsensitive := s.config != nil && s.config.CaseInsensitive
+	if metadata.CaseInsensitive != nil {
+		caseInsensitive = *metadata.CaseInsensitive
+	}
+
+	var order []keysetItem
+	used := make(map[string]bool)
+	add := func(name string, desc bool) bool {
+		prop := findPropertyByName(metadata, name)
+		if prop == nil || used[prop.Name] || !keysetComparable(*prop) {
+			return false
+		}
+		used[prop.Name] = true
+		order = append(order, keysetItem{prop: *prop, desc: desc})
+		return true
+	}
+
+	for _, part := range strings.Split(options.OrderBy, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		desc := false
+		if len(fields) == 2 {
+			switch strings.ToLower(fields[1]) {
+			case "asc":
+			case "desc":
+				desc = true
+			default:
+				return nil, false
+			}
+		}
+		if len(fields) > 2 || !add(fields[0], desc) {
+			return nil, false
+		}
+	}
+	for _, prop := range metadata.Properties {
+		if prop.IsKey && !used[prop.Name] && !add(prop.Name, false) {
+			return nil, false
+		}
+	}
+
+	for i, item := range order {
+		// No modo case-insensitive, o eq dos desempates ignoraria maiúsculas/minúsculas
+		if caseInsensitive && item.prop.Type == "string" && i < len(order)-1 {
+			return nil, false
+		}
+		if !keysetSelected(options.Select, item.prop.Name) {
+			return nil, false
+		}
+	}
+	return order, true
+}
+
+// keysetComparable verifica se a propriedade pode posicionar o keyset
+func keysetComparable(prop PropertyMetadata) bool {
+	if prop.IsNavigation || prop.IsEncrypted || (prop.IsNullable && !prop.IsKey) {
+		return false
+	}
+	switch prop.Type {
+	case "string", "bool", "time.Time",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64":
+		return true
+	}
+	return false
+}
+
+// keysetSelected verifica se o $select inclui a propriedade (os valores do último registro
+// formam o $skiptoken)
+func keysetSelected(selectQuery *GoDataSelectQuery, name string) bool {
+	if selectQuery == nil || strings.TrimSpace(selectQuery.RawValue) == "" {
+		return true
+	}
+	for _, item := range strings.Split(selectQuery.RawValue, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" || strings.EqualFold(item, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// keysetOrderBy gera o $orderby do keyset
+func keysetOrderBy(order []keysetItem) string {
+	parts := make([]string, len(order))
+	for i, item := range order {
+		parts[i] = item.prop.Name + " asc"
+		if item.desc {
+			parts[i] = item.prop.Name + " desc"
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// applySkipToken combina ao $filter a condição dos registros após o do token:
+// (a gt va) or (a eq va and b gt vb) or ...
+func (s *Server) applySkipToken(options *QueryOptions, order []keysetItem, token string) error {
+	literals, err := decodeSkipToken(order, token)
+	if err != nil {
+		return err
+	}
+
+	clauses := make([]string, len(order))
+	for i, item := range order {
+		var conditions []string
+		for j, previous := range order[:i] {
+			conditions = append(conditions, fmt.Sprintf("%s eq %s", previous.prop.Name, literals[j]))
+		}
+		operator := "gt"
+		if item.desc {
+			operator = "lt"
+		}
+		conditions = append(conditions, fmt.Sprintf("%s %s %s", item.prop.Name, operator, literals[i]))
+		clauses[i] = "(" + strings.Join(conditions, " and ") + ")"
+	}
+
+	raw := strings.Join(clauses, " or ")
+	if options.Filter != nil && options.Filter.RawValue != "" {
+		raw = "(" + options.Filter.RawValue + ") and (" + raw + ")"
+	}
+	filter, err := ParseFilterString(context.Background(), raw)
+	if err != nil {
+		return fmt.Errorf("invalid $skiptoken: %w", err)
+	}
+	if err := resolveDateTimeLiterals(filter.Tree, s.timeZone()); err != nil {
+		return fmt.Errorf("invalid $skiptoken: %w", err)
+	}
+	options.Filter = filter
+	return nil
+}
+
+// encodeSkipToken serializa os valores de ordenação do registro (JSON em base64 URL-safe)
+func encodeSkipToken(order []keysetItem, row interface{}) (string, bool) {
+	get := entityValueGetter(row)
+	if get == nil {
+		return "", false
+	}
+	values := make([]interface{}, len(order))
+	for i, item := range order {
+		value, ok := get(item.prop.Name)
+		if !ok || value == nil {
+			return "", false
+		}
+		if t, ok := value.(time.Time); ok {
+			value = t.UTC().Format(time.RFC3339Nano)
+		}
+		values[i] = value
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", false
+	}
+	return base64.RawURLEncoding.EncodeToString(data), true
+}
+
+// decodeSkipToken converte os valores do token em literais OData, validando-os pelo tipo
+// de cada propriedade da ordenação
+func decodeSkipToken(order []keysetItem, token string) ([]string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid $skiptoken")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var values []interface{}
+	if err := decoder.Decode(&values); err != nil || len(values) != len(order) {
+		return nil, fmt.Errorf("invalid $skiptoken")
+	}
+
+	literals := make([]string, len(order))
+	for i, item := range order {
+		literal, ok := keysetLiteral(item.prop, values[i])
+		if !ok {
+			return nil, fmt.Errorf("invalid $skiptoken value for %s", item.prop.Name)
+		}
+		literals[i] = literal
+	}
+	return literals, nil
+}
+
+// keysetLiteral formata um valor do token como literal OData do tipo da propriedade
+func keysetLiteral(prop PropertyMetadata, value interface{}) (string, bool) {
+	switch prop.Type {
+	case "string":
+		s, ok := value.(string)
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'", ok
+	case "bool":
+		b, ok := value.(bool)
+		return strconv.FormatBool(b), ok
+	case "time.Time":
+		s, ok := value.(string)
+		if !ok {
+			return "", false
+		}
+		if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+			return "", false
+		}
+		return s, true
+	}
+	number, ok := value.(json.Number)
+	if !ok {
+		return "", false
+	}
+	if _, err := number.Float64(); err != nil {
+		return "", false
+	}
+	return number.String(), true
+}
+
+// nextLink retorna o @odata.nextLink da página, ou vazio se ela é a última
+func (p *pagination) nextLink(s *Server, c fiber.Ctx, results []interface{}) string {
+	if len(results) < p.limit || (p.remaining != nil && *p.remaining == 0) || (p.capped && !p.truncated) {
+		return ""
+	}
+
+	overrides := map[string]string{}
+	removed := map[string]bool{}
+	if p.remaining != nil {
+		overrides["$top"] = strconv.Itoa(*p.remaining)
+	}
+	if p.mode == PaginationKeyset {
+		token, ok := encodeSkipToken(p.order, results[len(results)-1])
+		if !ok {
+			s.logger.Printf("⚠️ Paginação keyset: valores de ordenação ausentes no último registro")
+			return ""
+		}
+		overrides[queryOptionSkipToken] = token
+		// A posição vem do token; a contagem já foi informada na primeira página
+		removed["$skip"] = true
+		removed["$count"] = true
+	} else {
+		overrides["$skip"] = strconv.Itoa(p.skip + p.limit)
+	}
+
+	var params []string
+	for _, param := range strings.Split(string(c.Request().URI().QueryString()), "&") {
+		if param == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(param, "=")
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+		if _, overridden := overrides[name]; overridden || removed[name] {
+			continue
+		}
+		params = append(params, param)
+	}
+	for _, name := range []string{"$top", "$skip", queryOptionSkipToken} {
+		if value, ok := overrides[name]; ok {
+			params = append(params, name+"="+url.QueryEscape(value))
+		}
+	}
+	return s.requestBaseURL(c) + c.Path() + "?" + strings.Join(params, "&")
+}
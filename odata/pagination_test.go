@@ -0,0 +1,187 @@
+package odata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPaginationTestServer(t *testing.T, mode PaginationMode) *Server {
+	t.Helper()
+
+	server := newBatchGetTestServer(t)
+	require.NoError(t, server.registerPagination("Products", PaginationConfig{PageSize: 2, Mode: mode}))
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+	return server
+}
+
+// paginationPage busca uma página e retorna os nomes dos produtos e o nextLink (sem a origem)
+func paginationPage(t *testing.T, server *Server, path string) ([]string, string) {
+	t.Helper()
+	resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, path)
+
+	var body struct {
+		NextLink string                   `json:"@odata.nextLink"`
+		Value    []map[string]interface{} `json:"value"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	names := make([]string, len(body.Value))
+	for i, row := range body.Value {
+		names[i], _ = row["name"].(string)
+	}
+	return names, strings.TrimPrefix(body.NextLink, "http://example.com")
+}
+
+func TestServer_PaginationOffset(t *testing.T) {
+	server := newPaginationTestServer(t, PaginationOffset)
+
+	names, next := paginationPage(t, server, "/odata/Products?$orderby=id")
+	assert.Equal(t, []string{"Mouse", "Teclado"}, names)
+	assert.Equal(t, "/odata/Products?$orderby=id&$skip=2", next)
+
+	names, next = paginationPage(t, server, next)
+	assert.Equal(t, []string{"Monitor"}, names)
+	assert.Empty(t, next)
+
+	t.Run("$top menor que a página", func(t *testing.T) {
+		names, next := paginationPage(t, server, "/odata/Products?$top=1")
+		assert.Len(t, names, 1)
+		assert.Empty(t, next)
+	})
+
+	t.Run("$top maior que a página", func(t *testing.T) {
+		names, next := paginationPage(t, server, "/odata/Products?$orderby=id&$top=3")
+		assert.Len(t, names, 2)
+		assert.Equal(t, "/odata/Products?$orderby=id&$top=1&$skip=2", next)
+	})
+
+	t.Run("$skiptoken sem keyset", func(t *testing.T) {
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Products?$skiptoken=abc", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestServer_PaginationKeyset(t *testing.T) {
+	server := newPaginationTestServer(t, PaginationKeyset)
+	db := server.provider.GetConnection()
+
+	names, next := paginationPage(t, server, "/odata/Products?$orderby=price%20desc")
+	assert.Equal(t, []string{"Monitor", "Teclado"}, names)
+	require.Contains(t, next, "$skiptoken=")
+	assert.NotContains(t, next, "$skip=")
+
+	// Uma linha inserida antes da posição não repete o último registro da página anterior
+	_, err := db.Exec("INSERT INTO products (id, name, price) VALUES (4, 'Notebook', 5000)")
+	require.NoError(t, err)
+
+	names, next = paginationPage(t, server, next)
+	assert.Equal(t, []string{"Mouse"}, names)
+	assert.Empty(t, next)
+
+	t.Run("Desempate pela chave", func(t *testing.T) {
+		_, err := db.Exec("UPDATE products SET price = 10")
+		require.NoError(t, err)
+
+		var all []string
+		path := "/odata/Products?$orderby=price"
+		for path != "" {
+			var names []string
+			names, path = paginationPage(t, server, path)
+			all = append(all, names...)
+		}
+		assert.Equal(t, []string{"Mouse", "Teclado", "Monitor", "Notebook"}, all)
+	})
+
+	t.Run("Fallback para $skip", func(t *testing.T) {
+		names, next := paginationPage(t, server, "/odata/Products?$select=name&$orderby=id")
+		assert.Len(t, names, 2)
+		assert.Equal(t, "/odata/Products?$select=name&$orderby=id&$skip=2", next, "chave fora do $select")
+	})
+
+	t.Run("Token inválido", func(t *testing.T) {
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Products?$skiptoken=invalido", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestKeysetSkipToken(t *testing.T) {
+	order := []keysetItem{
+		{prop: PropertyMetadata{Name: "name", Type: "string"}},
+		{prop: PropertyMetadata{Name: "id", Type: "int64", IsKey: true}},
+	}
+	token, ok := encodeSkipToken(order, map[string]interface{}{"name": "D'Ávila", "id": int64(7)})
+	require.True(t, ok)
+
+	literals, err := decodeSkipToken(order, token)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"'D''Ávila'", "7"}, literals)
+
+	_, ok = encodeSkipToken(order, map[string]interface{}{"name": "Mouse"})
+	assert.False(t, ok, "sem a chave")
+
+	_, err = decodeSkipToken(order[:1], token)
+	assert.Error(t, err, "ordenação diferente")
+}
+
+func TestServer_MaxRowsPerRequest(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.SetMaxRowsPerRequest(2)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	get := func(t *testing.T, path string) map[string]interface{} {
+		t.Helper()
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode, path)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+
+	t.Run("Sem $top", func(t *testing.T) {
+		body := get(t, "/odata/Products?$orderby=id")
+		assert.Len(t, body["value"], 2)
+		assert.Equal(t, "http://example.com/odata/Products?$orderby=id&$skip=2", body["@odata.nextLink"])
+		require.Contains(t, body, TruncatedAnnotation)
+		assert.Equal(t, 2.0, body[TruncatedAnnotation].(map[string]interface{})["maxRows"])
+
+		names, next := paginationPage(t, server, "/odata/Products?$orderby=id&$skip=2")
+		assert.Equal(t, []string{"Monitor"}, names)
+		assert.Empty(t, next)
+	})
+
+	t.Run("Resultado dentro do limite", func(t *testing.T) {
+		body := get(t, "/odata/Products?$filter=price%20gt%2020")
+		assert.Len(t, body["value"], 2)
+		assert.NotContains(t, body, "@odata.nextLink")
+		assert.NotContains(t, body, TruncatedAnnotation)
+	})
+
+	t.Run("$top maior que o limite", func(t *testing.T) {
+		body := get(t, "/odata/Products?$orderby=id&$top=3")
+		assert.Len(t, body["value"], 2)
+		assert.Equal(t, "http://example.com/odata/Products?$orderby=id&$top=1&$skip=2", body["@odata.nextLink"])
+	})
+
+	t.Run("PageSize da entidade maior que o limite", func(t *testing.T) {
+		require.NoError(t, server.registerPagination("Products", PaginationConfig{PageSize: 10, Mode: PaginationKeyset}))
+		body := get(t, "/odata/Products?$orderby=id")
+		assert.Len(t, body["value"], 2)
+		assert.Contains(t, body["@odata.nextLink"], "$skiptoken=")
+		assert.Contains(t, body, TruncatedAnnotation)
+	})
+}
@@ -0,0 +1,76 @@
+package odata
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// =======================================================================================
+// PARAMETER ALIASES ($filter=Name eq @p&@p='X')
+// =======================================================================================
+
+// maxParameterAliasDepth limita aliases que referenciam outros aliases (evita ciclos)
+const maxParameterAliasDepth = 8
+
+// isParameterAlias verifica se o parâmetro da query é um alias (@nome)
+func isParameterAlias(key string) bool {
+	return len(key) > 1 && key[0] == '@'
+}
+
+// resolveParameterAliases substitui as referências @nome da expressão pelos valores dos
+// parâmetros de mesmo nome da query. Referências dentro de literais string são mantidas
+// e aliases não informados valem null, como define a especificação OData
+func resolveParameterAliases(expression string, values url.Values) (string, error) {
+	return resolveParameterAliasesDepth(expression, values, 0)
+}
+
+func resolveParameterAliasesDepth(expression string, values url.Values, depth int) (string, error) {
+	if !strings.Contains(expression, "@") {
+		return expression, nil
+	}
+	if depth >= maxParameterAliasDepth {
+		return "", fmt.Errorf("parameter aliases nested too deeply (possible cycle)")
+	}
+
+	var result strings.Builder
+	inString := false
+	for i := 0; i < len(expression); i++ {
+		char := expression[i]
+		if char == '\'' {
+			// Aspas escapadas ('') alternam duas vezes e mantêm o estado
+			inString = !inString
+		}
+		if inString || char != '@' || i+1 >= len(expression) || !isAliasStartChar(expression[i+1]) {
+			result.WriteByte(char)
+			continue
+		}
+
+		end := i + 1
+		for end < len(expression) && isAliasChar(expression[end]) {
+			end++
+		}
+		name := expression[i:end]
+
+		value := "null"
+		if aliasValues, ok := values[name]; ok && len(aliasValues) > 0 && strings.TrimSpace(aliasValues[0]) != "" {
+			resolved, err := resolveParameterAliasesDepth(strings.TrimSpace(aliasValues[0]), values, depth+1)
+			if err != nil {
+				return "", err
+			}
+			value = resolved
+		}
+		result.WriteString(value)
+		i = end - 1
+	}
+
+	return result.String(), nil
+}
+
+func isAliasStartChar(char byte) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func isAliasChar(char byte) bool {
+	return isAliasStartChar(char) || (char >= '0' && char <= '9')
+}
@@ -0,0 +1,81 @@
+package odata
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveParameterAliases(t *testing.T) {
+	values := url.Values{
+		"@name":  {"'Mouse'"},
+		"@list":  {"('Mouse','Monitor')"},
+		"@outer": {"@name"},
+		"@a":     {"@b"},
+		"@b":     {"@a"},
+	}
+
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{"Name eq @name", "Name eq 'Mouse'"},
+		{"Name in @list", "Name in ('Mouse','Monitor')"},
+		{"Name eq @outer", "Name eq 'Mouse'"},
+		{"Name eq @missing", "Name eq null"},
+		{"Email eq 'user@name' and Name eq @name", "Email eq 'user@name' and Name eq 'Mouse'"},
+		{"Name eq 'it''s @name'", "Name eq 'it''s @name'"},
+		{"Price gt 10", "Price gt 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			resolved, err := resolveParameterAliases(tt.expression, values)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resolved)
+		})
+	}
+
+	t.Run("Aliases cíclicos retornam erro", func(t *testing.T) {
+		_, err := resolveParameterAliases("Name eq @a", values)
+		assert.Error(t, err)
+	})
+}
+
+func TestServer_FilterInOperatorAndParameterAliases(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	names := func(t *testing.T, query string) []string {
+		t.Helper()
+		status, body := getComputeTestProducts(t, server, query)
+		require.Equal(t, http.StatusOK, status, body)
+		result := []string{}
+		for _, value := range body["value"].([]any) {
+			result = append(result, value.(map[string]any)["name"].(string))
+		}
+		return result
+	}
+
+	t.Run("Operador in", func(t *testing.T) {
+		assert.Equal(t, []string{"Mouse", "Monitor"}, names(t, "$filter="+url.QueryEscape("name in ('Mouse','Monitor')")))
+		assert.Equal(t, []string{"Monitor"}, names(t, "$filter="+url.QueryEscape("id in (1,3) and price gt 20")))
+	})
+
+	t.Run("Aliases no $filter e no $orderby", func(t *testing.T) {
+		query := "$filter=" + url.QueryEscape("price gt @min and name in @names") +
+			"&@min=5&@names=" + url.QueryEscape("('Mouse','Teclado')") +
+			"&$orderby=" + url.QueryEscape("@sort")
+		query += "&@sort=" + url.QueryEscape("price desc")
+		assert.Equal(t, []string{"Teclado", "Mouse"}, names(t, query))
+	})
+
+	t.Run("Alias não informado vale null", func(t *testing.T) {
+		assert.Empty(t, names(t, "$filter="+url.QueryEscape("name eq @missing")))
+	})
+}
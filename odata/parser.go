@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -32,6 +33,8 @@ func GetGlobalParser() *ODataParser {
 				"$format":      true,
 				"$apply":       true,
 				"$inlinecount": true,
+				"$asof":        true,
+				"$skiptoken":   true,
 			},
 		}
 	})
@@ -77,8 +80,8 @@ func (p *ODataParser) getCaseInsensitiveValue(values url.Values, key string) str
 // validateQueryParameters valida os parâmetros da query seguindo compliance OData
 func (p *ODataParser) validateQueryParameters(values url.Values, config ComplianceConfig) error {
 	for key, vals := range values {
-		// Verifica se o parâmetro é suportado
-		if !p.supportedParams[strings.ToLower(key)] && config == ComplianceStrict {
+		// Verifica se o parâmetro é suportado (aliases @p são referenciados pelas opções)
+		if !p.supportedParams[strings.ToLower(key)] && !isParameterAlias(key) && config == ComplianceStrict {
 			return fmt.Errorf("query parameter '%s' is not supported", key)
 		}
 
@@ -106,6 +109,10 @@ func (p *ODataParser) ParseQueryOptionsWithConfig(values url.Values, config Comp
 
 	// Parse $filter (case insensitive)
 	if filter := p.getCaseInsensitiveValue(values, "$filter"); filter != "" {
+		filter, err := resolveParameterAliases(filter, values)
+		if err != nil {
+			return options, fmt.Errorf("invalid $filter: %w", err)
+		}
 		filterQuery, err := ParseFilterString(context.Background(), filter)
 		if err != nil {
 			return options, fmt.Errorf("invalid $filter: %w", err)
@@ -115,6 +122,10 @@ func (p *ODataParser) ParseQueryOptionsWithConfig(values url.Values, config Comp
 
 	// Parse $orderby (case insensitive)
 	if orderBy := p.getCaseInsensitiveValue(values, "$orderby"); orderBy != "" {
+		orderBy, err := resolveParameterAliases(orderBy, values)
+		if err != nil {
+			return options, fmt.Errorf("invalid $orderby: %w", err)
+		}
 		options.OrderBy = orderBy
 	}
 
@@ -781,6 +792,9 @@ func (p *ODataParser) parseFunctionExpression(expression, function string) (Filt
 	return expr, nil
 }
 
+// orderByPropertyPattern reconhece propriedades simples (ou caminhos) no $orderby
+var orderByPropertyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(/[A-Za-z_][A-Za-z0-9_]*)*$`)
+
 // ParseOrderBy faz o parsing de uma expressão de ordenação OData
 func (p *ODataParser) ParseOrderBy(orderBy string) ([]OrderByExpression, error) {
 	if orderBy == "" {
@@ -788,7 +802,8 @@ func (p *ODataParser) ParseOrderBy(orderBy string) ([]OrderByExpression, error)
 	}
 
 	expressions := []OrderByExpression{}
-	parts := strings.Split(orderBy, ",")
+	// Divide apenas nas vírgulas de nível superior (ex: concat(Name, Code) desc)
+	parts := p.splitExpandParts(orderBy)
 
 	for _, part := range parts {
 		part = strings.TrimSpace(part)
@@ -808,6 +823,18 @@ func (p *ODataParser) ParseOrderBy(orderBy string) ([]OrderByExpression, error)
 			expr.Property = part
 		}
 
+		// $it/Propriedade é a própria propriedade da instância atual
+		expr.Property = strings.TrimPrefix(expr.Property, "$it/")
+
+		// Expressões aritméticas e funções reutilizam o parser de $filter
+		if !orderByPropertyPattern.MatchString(expr.Property) {
+			tree, err := ParseExpressionString(context.Background(), expr.Property)
+			if err != nil || tree == nil {
+				return nil, fmt.Errorf("invalid orderby expression '%s': %v", expr.Property, err)
+			}
+			expr.Expression = tree
+		}
+
 		expressions = append(expressions, expr)
 	}
 
@@ -0,0 +1,293 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestParseRemovedReason(t *testing.T) {
+	empty := map[string]interface{}{}
+	deleted := map[string]interface{}{"reason": "deleted"}
+	changed := map[string]interface{}{"reason": "changed"}
+
+	tests := []struct {
+		name    string
+		removed interface{}
+		format  string
+		reason  string
+		wantErr bool
+	}{
+		{"both aceita objeto vazio", empty, "both", RemovedReasonDeleted, false},
+		{"both aceita motivo", changed, "both", RemovedReasonChanged, false},
+		{"padrão é both", deleted, "", RemovedReasonDeleted, false},
+		{"empty aceita objeto vazio", empty, "empty", RemovedReasonDeleted, false},
+		{"empty rejeita motivo", deleted, "empty", "", true},
+		{"with_reason aceita motivo", changed, "with_reason", RemovedReasonChanged, false},
+		{"with_reason rejeita objeto vazio", empty, "with_reason", "", true},
+		{"motivo inválido", map[string]interface{}{"reason": "archived"}, "both", "", true},
+		{"não objeto", true, "both", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := parseRemovedReason(tt.removed, tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func newPatchDeltaTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range []string{
+		"CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER, status TEXT)",
+		"CREATE TABLE order_items (id INTEGER PRIMARY KEY AUTOINCREMENT, order_id INTEGER, product TEXT)",
+		"INSERT INTO customers (id, name) VALUES (1, 'Ana'), (2, 'Bruno')",
+		"INSERT INTO orders (id, customer_id, status) VALUES (1, 1, 'open'), (2, 1, 'open')",
+		"INSERT INTO order_items (id, order_id, product) VALUES (1, 1, 'Mouse'), (2, 1, 'Teclado'), (3, 2, 'Monitor')",
+	} {
+		_, err = db.Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+	provider := NewMySQLProvider(db)
+	server := &Server{
+		provider:     provider,
+		router:       fiber.New(),
+		entities:     make(map[string]EntityService),
+		parser:       NewODataParser(),
+		urlParser:    NewURLParser(),
+		logger:       logger,
+		config:       DefaultServerConfig(),
+		entityAuth:   make(map[string]EntityAuthConfig),
+		eventManager: NewEntityEventManager(logger),
+	}
+
+	server.entities["Customers"] = NewBaseEntityService(provider, EntityMetadata{
+		Name:      "Customers",
+		TableName: "customers",
+		Keys:      []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "name", ColumnName: "name", Type: "string"},
+		},
+	}, server)
+	server.entities["Orders"] = NewBaseEntityService(provider, EntityMetadata{
+		Name:      "Orders",
+		TableName: "orders",
+		Keys:      []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "customer_id", ColumnName: "customer_id", Type: "int64", IsNullable: true},
+			{Name: "status", ColumnName: "status", Type: "string"},
+			{Name: "Customer", IsNavigation: true, RelatedType: "Customers",
+				Association:  &AssociationMetadata{ForeignKey: "customer_id", References: "id"},
+				Relationship: &RelationshipMetadata{LocalProperty: "customer_id", ReferencedProperty: "id"}},
+			{Name: "Items", IsNavigation: true, IsCollection: true, RelatedType: "OrderItems",
+				ManyAssociation: &ManyAssociationMetadata{ForeignKey: "order_id", References: "id"},
+				Relationship:    &RelationshipMetadata{LocalProperty: "id", ReferencedProperty: "order_id"}},
+		},
+	}, server)
+	server.entities["OrderItems"] = NewBaseEntityService(provider, EntityMetadata{
+		Name:      "OrderItems",
+		TableName: "order_items",
+		Keys:      []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "order_id", ColumnName: "order_id", Type: "int64", IsNullable: true},
+			{Name: "product", ColumnName: "product", Type: "string"},
+		},
+	}, server)
+
+	server.setupEntityRoutes("Orders")
+	return server
+}
+
+// orderItemOwner retorna o order_id do item (nil se desassociado, "gone" se excluído)
+func orderItemOwner(t *testing.T, server *Server, id int) interface{} {
+	t.Helper()
+	var orderID sql.NullInt64
+	err := server.provider.GetConnection().QueryRow("SELECT order_id FROM order_items WHERE id = ?", id).Scan(&orderID)
+	if err == sql.ErrNoRows {
+		return "gone"
+	}
+	require.NoError(t, err)
+	if !orderID.Valid {
+		return nil
+	}
+	return orderID.Int64
+}
+
+func TestBaseEntityService_PatchDelta(t *testing.T) {
+	ctx := context.Background()
+	keys := map[string]any{"id": int64(1)}
+
+	t.Run("Nav@delta com @removed, @id e novos itens", func(t *testing.T) {
+		server := newPatchDeltaTestServer(t)
+		service := server.entities["Orders"].(*BaseEntityService)
+
+		_, report, err := service.PatchWithReport(ctx, keys, map[string]interface{}{
+			"status": "closed",
+			"Items@delta": []interface{}{
+				map[string]interface{}{"@id": "OrderItems(1)", "@removed": map[string]interface{}{"reason": "deleted"}},
+				map[string]interface{}{"@id": "OrderItems(2)", "@removed": map[string]interface{}{"reason": "changed"}},
+				map[string]interface{}{"@id": "OrderItems(3)"},
+				map[string]interface{}{"product": "Cabo"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, report.Operations, 5)
+
+		assert.Equal(t, "gone", orderItemOwner(t, server, 1), "reason deleted exclui")
+		assert.Nil(t, orderItemOwner(t, server, 2), "reason changed apenas desassocia")
+		assert.Equal(t, int64(1), orderItemOwner(t, server, 3), "@id associa a entidade existente")
+		assert.Equal(t, int64(1), orderItemOwner(t, server, 4), "novo item recebe a chave do pai")
+
+		var status string
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT status FROM orders WHERE id = 1").Scan(&status))
+		assert.Equal(t, "closed", status, "raiz identificada pelas chaves da URL")
+
+		var orders int
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT COUNT(*) FROM orders").Scan(&orders))
+		assert.Equal(t, 2, orders, "raiz sem id no payload não vira INSERT")
+	})
+
+	t.Run("Anotações OData 4.0 continuam aceitas", func(t *testing.T) {
+		server := newPatchDeltaTestServer(t)
+		service := server.entities["Orders"].(*BaseEntityService)
+
+		_, err := service.Patch(ctx, keys, map[string]interface{}{
+			"Items": []interface{}{
+				map[string]interface{}{"@odata.id": "OrderItems(1)", "@odata.removed": map[string]interface{}{}},
+				map[string]interface{}{"@odata.id": "OrderItems(2)", "product": "Teclado BR"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "gone", orderItemOwner(t, server, 1))
+
+		var product string
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT product FROM order_items WHERE id = 2").Scan(&product))
+		assert.Equal(t, "Teclado BR", product)
+	})
+
+	t.Run("Referência N:1 preenche a chave estrangeira da raiz", func(t *testing.T) {
+		server := newPatchDeltaTestServer(t)
+		service := server.entities["Orders"].(*BaseEntityService)
+
+		result, err := service.Patch(ctx, keys, map[string]interface{}{
+			"Customer": map[string]interface{}{"@id": "Customers(2)"},
+		})
+		require.NoError(t, err)
+		customerID, _ := result.(*OrderedEntity).Get("customer_id")
+		assert.Equal(t, int64(2), customerID)
+
+		_, err = service.Patch(ctx, keys, map[string]interface{}{
+			"Customer": map[string]interface{}{"@id": "Customers(2)", "@removed": map[string]interface{}{"reason": "changed"}},
+		})
+		require.NoError(t, err)
+		var owner sql.NullInt64
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT customer_id FROM orders WHERE id = 1").Scan(&owner))
+		assert.False(t, owner.Valid, "reason changed desassocia sem excluir")
+	})
+
+	t.Run("@removed na raiz exclui a entidade", func(t *testing.T) {
+		server := newPatchDeltaTestServer(t)
+		service := server.entities["Orders"].(*BaseEntityService)
+
+		result, report, err := service.PatchWithReport(ctx, map[string]any{"id": int64(2)}, map[string]interface{}{
+			"@removed": map[string]interface{}{"reason": "deleted"},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, result)
+		require.Len(t, report.Operations, 1)
+		assert.Equal(t, "DELETE", report.Operations[0].Type)
+	})
+
+	t.Run("Item removido sem chave é rejeitado", func(t *testing.T) {
+		server := newPatchDeltaTestServer(t)
+		service := server.entities["Orders"].(*BaseEntityService)
+
+		_, err := service.Patch(ctx, keys, map[string]interface{}{
+			"Items@delta": []interface{}{map[string]interface{}{"@removed": map[string]interface{}{}}},
+		})
+		require.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, entityErrorStatus(err, 0))
+	})
+}
+
+func TestHandleUpdateEntity_PatchRemovedFormat(t *testing.T) {
+	patch := func(t *testing.T, server *Server, body string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPatch, "/odata/Orders(1)", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusBadRequest {
+			var payload map[string]interface{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+			assert.Equal(t, "InvalidDeltaPayload", payload["error"].(map[string]interface{})["code"])
+		}
+		return resp.StatusCode
+	}
+
+	emptyRemoved := `{"Items@delta": [{"@id": "OrderItems(1)", "@removed": {}}]}`
+	reasonRemoved := `{"Items@delta": [{"@id": "OrderItems(1)", "@removed": {"reason": "deleted"}}]}`
+
+	tests := []struct {
+		format string
+		body   string
+		status int
+	}{
+		{"both", emptyRemoved, http.StatusOK},
+		{"both", reasonRemoved, http.StatusOK},
+		{"empty", emptyRemoved, http.StatusOK},
+		{"empty", reasonRemoved, http.StatusBadRequest},
+		{"with_reason", reasonRemoved, http.StatusOK},
+		{"with_reason", emptyRemoved, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.body, func(t *testing.T) {
+			server := newPatchDeltaTestServer(t)
+			server.config.PatchRemovedFormat = tt.format
+
+			assert.Equal(t, tt.status, patch(t, server, tt.body))
+			if tt.status == http.StatusBadRequest {
+				assert.Equal(t, int64(1), orderItemOwner(t, server, 1), "nada é gravado")
+			} else {
+				assert.Equal(t, "gone", orderItemOwner(t, server, 1))
+			}
+		})
+	}
+
+	t.Run("@removed na raiz responde 204", func(t *testing.T) {
+		server := newPatchDeltaTestServer(t)
+		assert.Equal(t, http.StatusNoContent, patch(t, server, `{"@removed": {}}`))
+	})
+}
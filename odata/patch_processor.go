@@ -3,7 +3,7 @@ package odata
 import (
 	"context"
 	"fmt"
-	"log"
+	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
@@ -18,15 +18,62 @@ type PatchOperation struct {
 	EntityName    string                 // Nome da entidade para lookup de serviço
 }
 
+// Anotações do payload delta do PATCH. O OData 4.01 usa @removed, @id e Nav@delta; as
+// formas com prefixo odata. (OData 4.0) continuam aceitas
+var (
+	removedAnnotations = []string{"@removed", "@odata.removed"}
+	idAnnotations      = []string{"@id", "@odata.id"}
+	deltaAnnotations   = []string{"@delta", "@odata.delta"}
+)
+
+// Motivos de remoção do @removed: deleted exclui a entidade relacionada; changed apenas
+// desfaz a associação com a entidade pai
+const (
+	RemovedReasonDeleted = "deleted"
+	RemovedReasonChanged = "changed"
+)
+
+// getAnnotation retorna o valor da primeira anotação encontrada na entidade
+func getAnnotation(entity map[string]interface{}, names []string) (interface{}, bool) {
+	for _, name := range names {
+		if value, exists := entity[name]; exists {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// navigationValue retorna o valor de uma propriedade de navegação no payload, aceitando a
+// forma delta (ex: "Items@delta")
+func navigationValue(entity map[string]interface{}, name string) (interface{}, bool) {
+	if value, exists := entity[name]; exists {
+		return value, true
+	}
+	for _, suffix := range deltaAnnotations {
+		if value, exists := entity[name+suffix]; exists {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// invalidDeltaError cria o erro 400 de um payload delta inválido
+func invalidDeltaError(format string, args ...interface{}) error {
+	return NewODataError("InvalidDeltaPayload", fmt.Sprintf(format, args...)).WithStatus(http.StatusBadRequest)
+}
+
 // hasHierarchicalStructure verifica se o JSON tem estrutura hierárquica que requer processamento avançado
 // Retorna true se:
-// - Tem propriedades com @odata.removed
-// - Tem propriedades de navegação com objetos/arrays aninhados
-// - Tem @odata.id em objetos aninhados
+// - Tem @removed/@odata.removed
+// - Tem propriedades de navegação com objetos/arrays aninhados (inclusive Nav@delta)
+// - Tem @id/@odata.id em objetos aninhados
 func hasHierarchicalStructure(data map[string]interface{}, metadata EntityMetadata) bool {
-	// Verifica se tem @odata.removed
+	// Verifica se tem @removed
+	if _, found := getAnnotation(data, removedAnnotations); found {
+		return true
+	}
 	for key := range data {
-		if key == "@odata.removed" || strings.HasSuffix(key, "@odata.removed") {
+		if strings.HasSuffix(key, "@odata.removed") {
 			return true
 		}
 	}
@@ -34,7 +81,7 @@ func hasHierarchicalStructure(data map[string]interface{}, metadata EntityMetada
 	// Verifica propriedades de navegação com objetos/arrays aninhados
 	for _, prop := range metadata.Properties {
 		if prop.IsNavigation {
-			if value, exists := data[prop.Name]; exists {
+			if value, exists := navigationValue(data, prop.Name); exists {
 				// Verifica se é array ou objeto
 				if isNestedStructure(value) {
 					return true
@@ -43,7 +90,7 @@ func hasHierarchicalStructure(data map[string]interface{}, metadata EntityMetada
 		}
 	}
 
-	// Verifica se tem @odata.id em objetos aninhados
+	// Verifica se tem @id em objetos aninhados
 	return hasNestedODataID(data)
 }
 
@@ -80,13 +127,12 @@ func isMapOrStruct(value interface{}) bool {
 	return val.Kind() == reflect.Map || val.Kind() == reflect.Struct
 }
 
-// hasNestedODataID verifica se há @odata.id em objetos aninhados
+// hasNestedODataID verifica se há @id/@odata.id em objetos aninhados
 func hasNestedODataID(data map[string]interface{}) bool {
-	for key, value := range data {
-		// Verifica se a própria chave é @odata.id
-		if key == "@odata.id" {
-			return true
-		}
+	if _, found := getAnnotation(data, idAnnotations); found {
+		return true
+	}
+	for _, value := range data {
 
 		// Verifica recursivamente em objetos aninhados
 		if valueMap, ok := value.(map[string]interface{}); ok {
@@ -109,10 +155,17 @@ func hasNestedODataID(data map[string]interface{}) bool {
 	return false
 }
 
-// identifyOperation identifica o tipo de operação (INSERT/UPDATE/DELETE) baseado no objeto e metadados
+// identifyOperation identifica o tipo de operação (INSERT/UPDATE/DELETE) baseado no objeto e metadados.
+// Um @removed fora do formato aceito (PatchRemovedFormat) é rejeitado
 func identifyOperation(entity map[string]interface{}, metadata EntityMetadata, removedFormat string) (string, error) {
-	// Verifica se tem @odata.removed
-	if hasRemovedAnnotation(entity, removedFormat) {
+	// Verifica se tem @removed
+	if removed, found := getAnnotation(entity, removedAnnotations); found {
+		if _, err := parseRemovedReason(removed, removedFormat); err != nil {
+			return "", err
+		}
+		return "DELETE", nil
+	}
+	if hasRemovedAnnotation(entity) {
 		return "DELETE", nil
 	}
 
@@ -126,49 +179,53 @@ func identifyOperation(entity map[string]interface{}, metadata EntityMetadata, r
 	return "INSERT", nil
 }
 
-// hasRemovedAnnotation verifica se o objeto tem @odata.removed
-func hasRemovedAnnotation(entity map[string]interface{}, removedFormat string) bool {
-	// Verifica @odata.removed direto
-	if removed, exists := entity["@odata.removed"]; exists {
-		return isValidRemovedFormat(removed, removedFormat)
-	}
-
-	// Verifica propriedades com sufixo @odata.removed
+// hasRemovedAnnotation verifica se o objeto tem propriedades com sufixo @odata.removed
+func hasRemovedAnnotation(entity map[string]interface{}) bool {
 	for key := range entity {
 		if strings.HasSuffix(key, "@odata.removed") {
 			return true
 		}
 	}
-
 	return false
 }
 
-// isValidRemovedFormat valida o formato de @odata.removed conforme configuração
-func isValidRemovedFormat(removed interface{}, format string) bool {
-	if removed == nil {
-		return false
+// parseRemovedReason valida o @removed conforme o formato configurado e retorna o motivo
+// da remoção (deleted quando omitido):
+//   - "empty": apenas objeto vazio {}
+//   - "with_reason": apenas com motivo, ex: {"reason": "deleted"}
+//   - "both": aceita os dois formatos
+func parseRemovedReason(removed interface{}, format string) (string, error) {
+	removedMap, ok := removed.(map[string]interface{})
+	if !ok {
+		return "", invalidDeltaError("@removed must be an object")
+	}
+
+	reason := ""
+	if value, exists := removedMap["reason"]; exists {
+		reason, _ = value.(string)
+		if reason != RemovedReasonDeleted && reason != RemovedReasonChanged {
+			return "", invalidDeltaError("invalid @removed reason '%v': must be '%s' or '%s'", value, RemovedReasonDeleted, RemovedReasonChanged)
+		}
 	}
 
 	switch format {
 	case "empty":
-		// Apenas objeto vazio {}
-		if removedMap, ok := removed.(map[string]interface{}); ok {
-			return len(removedMap) == 0
+		if len(removedMap) > 0 {
+			return "", invalidDeltaError("@removed must be an empty object")
 		}
-		return false
 	case "with_reason":
-		// Apenas com propriedades como {"reason": "deleted"}
-		if removedMap, ok := removed.(map[string]interface{}); ok {
-			return len(removedMap) > 0
+		if reason == "" {
+			return "", invalidDeltaError("@removed must specify a reason")
 		}
-		return false
 	case "both", "":
-		// Aceita ambos os formatos
-		_, ok := removed.(map[string]interface{})
-		return ok
 	default:
-		return false
+		return "", fmt.Errorf("unknown PATCH removed format '%s'", format)
+	}
+
+	if reason == "" {
+		reason = RemovedReasonDeleted
 	}
+	return reason, nil
 }
 
 // extractKeysFromEntity extrai chaves primárias de um objeto JSON
@@ -177,8 +234,8 @@ func isValidRemovedFormat(removed interface{}, format string) bool {
 func extractKeysFromEntity(entity map[string]interface{}, metadata EntityMetadata) map[string]interface{} {
 	keys := make(map[string]interface{})
 
-	// Primeiro, verifica se tem @odata.id
-	if odataID, exists := entity["@odata.id"]; exists {
+	// Primeiro, verifica se tem @id
+	if odataID, exists := getAnnotation(entity, idAnnotations); exists {
 		// Extrai ID do @odata.id (formato: "/EntityName(id)" ou "/EntityName(key1=value1,key2=value2)")
 		if idStr, ok := odataID.(string); ok {
 			extractedKeys := parseODataID(idStr, metadata)
@@ -346,11 +403,123 @@ func hasAllKeys(keys map[string]interface{}, metadata EntityMetadata) bool {
 	return len(keys) == keyCount
 }
 
-// processNavigationProperty processa propriedades de navegação recursivamente
+// isEntityReference verifica se o objeto é apenas uma referência (@id sem propriedades)
+func isEntityReference(entity map[string]interface{}) bool {
+	if _, found := getAnnotation(entity, idAnnotations); !found {
+		return false
+	}
+	for key := range entity {
+		if !strings.Contains(key, "@") {
+			return false
+		}
+	}
+	return true
+}
+
+// cleanDeltaPayload remove as anotações e as propriedades de navegação do objeto, deixando
+// apenas as propriedades gravadas na entidade
+func cleanDeltaPayload(entity map[string]interface{}, metadata EntityMetadata) map[string]interface{} {
+	clean := make(map[string]interface{})
+	for key, value := range entity {
+		if !strings.Contains(key, "@") {
+			clean[key] = value
+		}
+	}
+	for _, prop := range metadata.Properties {
+		if prop.IsNavigation {
+			delete(clean, prop.Name)
+		}
+	}
+	return clean
+}
+
+// hasNonKeyValues verifica se a entidade tem propriedades além das chaves a gravar
+func hasNonKeyValues(entity map[string]interface{}, metadata EntityMetadata) bool {
+	for key := range entity {
+		if prop := findPropertyByName(metadata, key); prop == nil || !prop.IsKey {
+			return true
+		}
+	}
+	return false
+}
+
+// navigationLink descreve como a entidade relacionada é associada à entidade pai
+type navigationLink struct {
+	// Propriedade que recebe a chave: na entidade relacionada (1:N) ou na entidade pai (N:1)
+	property string
+	// Valor da associação: chave da entidade pai (1:N) ou da relacionada (N:1)
+	value interface{}
+	// Se o valor da associação é conhecido (entidade pai ainda não inserida não tem chave)
+	known bool
+}
+
+// collectionLink resolve a chave estrangeira de uma navegação 1:N (manyAssociation)
+func collectionLink(parent map[string]interface{}, parentMetadata EntityMetadata, prop PropertyMetadata, relatedMetadata EntityMetadata) (*navigationLink, error) {
+	assoc := prop.ManyAssociation
+	if assoc == nil || assoc.ForeignKey == "" {
+		return nil, invalidDeltaError("navigation property '%s' has no foreign key to link or unlink entities", prop.Name)
+	}
+	if assoc.JoinTable != "" {
+		return nil, invalidDeltaError("linking entities through '%s' (many-to-many) is not supported", prop.Name)
+	}
+
+	link := &navigationLink{property: assoc.ForeignKey}
+	if fkProp := findPropertyByColumnName(relatedMetadata, assoc.ForeignKey); fkProp != nil {
+		link.property = fkProp.Name
+	}
+
+	var refProp *PropertyMetadata
+	if assoc.References != "" {
+		refProp = findPropertyByColumnName(parentMetadata, assoc.References)
+	} else {
+		refProp = getFirstKeyProperty(parentMetadata)
+	}
+	if refProp != nil {
+		if value, exists := parent[refProp.Name]; exists {
+			link.value, link.known = value, true
+		} else if value, exists := extractKeysFromEntity(parent, parentMetadata)[refProp.Name]; exists {
+			link.value, link.known = value, true
+		}
+	}
+	return link, nil
+}
+
+// referenceLink resolve a chave estrangeira de uma navegação N:1 (association)
+func referenceLink(parentMetadata EntityMetadata, prop PropertyMetadata, relatedMetadata EntityMetadata, relatedKeys map[string]interface{}) (*navigationLink, error) {
+	assoc := prop.Association
+	if assoc == nil || assoc.ForeignKey == "" {
+		return nil, invalidDeltaError("navigation property '%s' has no foreign key to link or unlink entities", prop.Name)
+	}
+
+	link := &navigationLink{property: assoc.ForeignKey}
+	if fkProp := findPropertyByColumnName(parentMetadata, assoc.ForeignKey); fkProp != nil {
+		link.property = fkProp.Name
+	}
+
+	var refProp *PropertyMetadata
+	if assoc.References != "" {
+		refProp = findPropertyByColumnName(relatedMetadata, assoc.References)
+	} else {
+		refProp = getFirstKeyProperty(relatedMetadata)
+	}
+	if refProp != nil {
+		link.value, link.known = relatedKeys[refProp.Name]
+	}
+	return link, nil
+}
+
+// processNavigationProperty processa propriedades de navegação recursivamente. Coleções
+// podem ser enviadas como array simples ou delta (Nav@delta); em ambos os casos apenas as
+// entidades informadas são alteradas. Cada item pode ser:
+//   - entidade nova (sem chave): INSERT, com a chave estrangeira preenchida a partir do pai
+//   - entidade existente (com chave ou @id): UPDATE
+//   - referência (@id sem propriedades): associa a entidade existente ao pai
+//   - @removed: reason "deleted" (padrão) exclui; reason "changed" apenas desassocia
 func processNavigationProperty(
 	ctx context.Context,
 	server *Server,
 	entity map[string]interface{},
+	parentMetadata EntityMetadata,
 	prop PropertyMetadata,
 	navigationPath string,
 	removedFormat string,
@@ -360,8 +529,8 @@ func processNavigationProperty(
 		return nil
 	}
 
-	value, exists := entity[prop.Name]
-	if !exists {
+	value, exists := navigationValue(entity, prop.Name)
+	if !exists || value == nil {
 		return nil
 	}
 
@@ -370,97 +539,149 @@ func processNavigationProperty(
 		newPath = navigationPath + "." + prop.Name
 	}
 
+	// Obtém metadados da entidade relacionada
+	relatedMetadata, err := getRelatedEntityMetadata(server, prop.RelatedType)
+	if err != nil {
+		return fmt.Errorf("failed to get metadata for %s: %w", prop.RelatedType, err)
+	}
+
 	// Processa coleção (array)
 	if prop.IsCollection {
-		if valueSlice, ok := value.([]interface{}); ok {
-			for _, item := range valueSlice {
-				if itemMap, ok := item.(map[string]interface{}); ok {
-					// Obtém metadados da entidade relacionada
-					relatedMetadata, err := getRelatedEntityMetadata(server, prop.RelatedType)
-					if err != nil {
-						log.Printf("Warning: Failed to get metadata for %s: %v", prop.RelatedType, err)
-						continue
-					}
+		items, ok := value.([]interface{})
+		if !ok {
+			return invalidDeltaError("navigation property '%s' must be an array", prop.Name)
+		}
+		for _, item := range items {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				return invalidDeltaError("items of navigation property '%s' must be objects", prop.Name)
+			}
+			if err := processNestedEntity(ctx, server, entity, parentMetadata, prop, itemMap, relatedMetadata, newPath, removedFormat, operations); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
-					// Identifica operação
-					opType, err := identifyOperation(itemMap, relatedMetadata, removedFormat)
-					if err != nil {
-						log.Printf("Warning: Failed to identify operation: %v", err)
-						continue
-					}
+	// Processa entidade única
+	valueMap, ok := value.(map[string]interface{})
+	if !ok {
+		return invalidDeltaError("navigation property '%s' must be an object", prop.Name)
+	}
+	return processNestedEntity(ctx, server, entity, parentMetadata, prop, valueMap, relatedMetadata, newPath, removedFormat, operations)
+}
 
-					// Extrai chaves
-					keys := extractKeysFromEntity(itemMap, relatedMetadata)
+// processNestedEntity gera as operações de um item de navegação do payload
+func processNestedEntity(
+	ctx context.Context,
+	server *Server,
+	parent map[string]interface{},
+	parentMetadata EntityMetadata,
+	prop PropertyMetadata,
+	item map[string]interface{},
+	relatedMetadata EntityMetadata,
+	navigationPath string,
+	removedFormat string,
+	operations *[]PatchOperation,
+) error {
+	keys := extractKeysFromEntity(item, relatedMetadata)
+	newOperation := func(opType string, entity map[string]interface{}) PatchOperation {
+		return PatchOperation{
+			Type:           opType,
+			Entity:         entity,
+			Keys:           keys,
+			NavigationPath: navigationPath,
+			EntityName:     prop.RelatedType,
+		}
+	}
 
-					// Remove @odata.removed do objeto antes de processar
-					cleanEntity := cleanRemovedAnnotation(itemMap)
+	removed, isRemoved := getAnnotation(item, removedAnnotations)
+	if isRemoved || isEntityReference(item) {
+		if !hasAllKeys(keys, relatedMetadata) {
+			return invalidDeltaError("entity in '%s' must have its key or @id to be linked or removed", navigationPath)
+		}
 
-					*operations = append(*operations, PatchOperation{
-						Type:          opType,
-						Entity:        cleanEntity,
-						Keys:          keys,
-						NavigationPath: newPath,
-						EntityName:    prop.RelatedType,
-					})
+		reason := ""
+		if isRemoved {
+			var err error
+			if reason, err = parseRemovedReason(removed, removedFormat); err != nil {
+				return err
+			}
+		}
 
-					// Processa recursivamente propriedades de navegação aninhadas
-					if err := processPatchRecursive(ctx, server, itemMap, relatedMetadata, newPath, removedFormat, operations); err != nil {
-						log.Printf("Warning: Failed to process nested navigation: %v", err)
-					}
+		// Navegação N:1: a chave estrangeira fica na entidade pai
+		if !prop.IsCollection {
+			link, err := referenceLink(parentMetadata, prop, relatedMetadata, keys)
+			if err != nil && !(isRemoved && reason == RemovedReasonDeleted) {
+				return err
+			}
+			if link != nil {
+				if isRemoved {
+					parent[link.property] = nil
+				} else if link.known {
+					parent[link.property] = link.value
 				}
 			}
-		}
-	} else {
-		// Processa entidade única
-		if valueMap, ok := value.(map[string]interface{}); ok {
-			// Obtém metadados da entidade relacionada
-			relatedMetadata, err := getRelatedEntityMetadata(server, prop.RelatedType)
-			if err != nil {
-				return fmt.Errorf("failed to get metadata for %s: %w", prop.RelatedType, err)
+			if reason == RemovedReasonDeleted {
+				*operations = append(*operations, newOperation("DELETE", nil))
 			}
+			return nil
+		}
 
-			// Identifica operação
-			opType, err := identifyOperation(valueMap, relatedMetadata, removedFormat)
-			if err != nil {
-				return fmt.Errorf("failed to identify operation: %w", err)
+		// Navegação 1:N: a chave estrangeira fica na entidade relacionada
+		if reason == RemovedReasonDeleted {
+			*operations = append(*operations, newOperation("DELETE", nil))
+			return nil
+		}
+		link, err := collectionLink(parent, parentMetadata, prop, relatedMetadata)
+		if err != nil {
+			return err
+		}
+		var fkValue interface{}
+		if !isRemoved {
+			if !link.known {
+				return invalidDeltaError("cannot link entities in '%s' to a parent without key", navigationPath)
 			}
+			fkValue = link.value
+		}
+		*operations = append(*operations, newOperation("UPDATE", map[string]interface{}{link.property: fkValue}))
+		return nil
+	}
 
-			// Extrai chaves
-			keys := extractKeysFromEntity(valueMap, relatedMetadata)
-
-			// Remove @odata.removed do objeto antes de processar
-			cleanEntity := cleanRemovedAnnotation(valueMap)
+	// Processa recursivamente as navegações aninhadas antes de gravar o item (referências
+	// N:1 preenchem a chave estrangeira do próprio item)
+	if err := processPatchRecursive(ctx, server, item, relatedMetadata, navigationPath, removedFormat, operations); err != nil {
+		return err
+	}
 
-			*operations = append(*operations, PatchOperation{
-				Type:          opType,
-				Entity:        cleanEntity,
-				Keys:          keys,
-				NavigationPath: newPath,
-				EntityName:    prop.RelatedType,
-			})
+	opType := "INSERT"
+	if hasAllKeys(keys, relatedMetadata) {
+		opType = "UPDATE"
+	}
+	cleanEntity := cleanDeltaPayload(item, relatedMetadata)
 
-			// Processa recursivamente propriedades de navegação aninhadas
-			return processPatchRecursive(ctx, server, valueMap, relatedMetadata, newPath, removedFormat, operations)
+	// Itens novos de coleções 1:N recebem a chave do pai, quando não informada
+	if opType == "INSERT" && prop.IsCollection && prop.ManyAssociation != nil && prop.ManyAssociation.JoinTable == "" {
+		if link, err := collectionLink(parent, parentMetadata, prop, relatedMetadata); err == nil && link.known {
+			if _, exists := cleanEntity[link.property]; !exists {
+				cleanEntity[link.property] = link.value
+			}
 		}
 	}
 
-	return nil
-}
-
-// cleanRemovedAnnotation remove @odata.removed do objeto
-func cleanRemovedAnnotation(entity map[string]interface{}) map[string]interface{} {
-	clean := make(map[string]interface{})
-	for key, value := range entity {
-		if key != "@odata.removed" && !strings.HasSuffix(key, "@odata.removed") {
-			clean[key] = value
-		}
+	if opType == "UPDATE" && !hasNonKeyValues(cleanEntity, relatedMetadata) {
+		return nil
 	}
-	return clean
+	*operations = append(*operations, newOperation(opType, cleanEntity))
+	return nil
 }
 
 // getRelatedEntityMetadata obtém metadados de uma entidade relacionada
 func getRelatedEntityMetadata(server *Server, entityName string) (EntityMetadata, error) {
-	service := server.GetEntityService(entityName)
+	if server == nil {
+		return EntityMetadata{}, fmt.Errorf("entity service not found: %s", entityName)
+	}
+	service := server.relatedEntityService(entityName)
 	if service == nil {
 		return EntityMetadata{}, fmt.Errorf("entity service not found: %s", entityName)
 	}
@@ -480,12 +701,11 @@ func processPatchRecursive(
 	// Processa propriedades de navegação
 	for _, prop := range metadata.Properties {
 		if prop.IsNavigation {
-			if err := processNavigationProperty(ctx, server, entity, prop, navigationPath, removedFormat, operations); err != nil {
-				log.Printf("Warning: Failed to process navigation property %s: %v", prop.Name, err)
+			if err := processNavigationProperty(ctx, server, entity, metadata, prop, navigationPath, removedFormat, operations); err != nil {
+				return err
 			}
 		}
 	}
 
 	return nil
 }
-
@@ -0,0 +1,132 @@
+package odata
+
+import (
+	"net/http"
+	"strings"
+)
+
+// =======================================================================================
+// RELATÓRIO DE OPERAÇÕES DO PATCH HIERÁRQUICO
+// =======================================================================================
+
+const (
+	// PreferPatchReport é a preferência (header Prefer) que solicita o relatório de operações do PATCH
+	PreferPatchReport = "godata.patch-report"
+
+	// PatchOperationsAnnotation é a anotação da resposta que contém o relatório de operações
+	PatchOperationsAnnotation = "@godata.operations"
+)
+
+// PatchOperationResult descreve uma operação aninhada executada pelo PATCH
+type PatchOperationResult struct {
+	EntityName     string                 `json:"entity"`
+	NavigationPath string                 `json:"navigationPath,omitempty"`
+	Type           string                 `json:"type"` // INSERT, UPDATE, DELETE
+	Keys           map[string]interface{} `json:"keys,omitempty"`
+	Status         int                    `json:"status"` // 201/200/204 em sucesso, 404/500 na falha, 424 se não executada
+	Error          string                 `json:"error,omitempty"`
+}
+
+// PatchReport relatório das operações executadas por um PATCH
+type PatchReport struct {
+	Operations []PatchOperationResult `json:"operations"`
+	RolledBack bool                   `json:"rolledBack"` // Transação desfeita; nenhuma operação foi persistida
+}
+
+// add registra o resultado de uma operação
+func (r *PatchReport) add(op PatchOperation, keys map[string]interface{}, err error) {
+	result := PatchOperationResult{
+		EntityName:     op.EntityName,
+		NavigationPath: op.NavigationPath,
+		Type:           op.Type,
+		Keys:           keys,
+		Status:         patchSuccessStatus(op.Type),
+	}
+	if err != nil {
+		result.Status = patchErrorStatus(err)
+		result.Error = err.Error()
+		if violation, ok := constraintViolation(err); ok {
+			result.Error = violation.Message()
+		}
+	}
+	r.Operations = append(r.Operations, result)
+}
+
+// skip registra operações não executadas devido a uma falha anterior
+func (r *PatchReport) skip(ops []PatchOperation) {
+	for _, op := range ops {
+		r.Operations = append(r.Operations, PatchOperationResult{
+			EntityName:     op.EntityName,
+			NavigationPath: op.NavigationPath,
+			Type:           op.Type,
+			Keys:           op.Keys,
+			Status:         http.StatusFailedDependency,
+		})
+	}
+}
+
+// FailedOperation retorna a operação que falhou, se houver
+func (r *PatchReport) FailedOperation() *PatchOperationResult {
+	if r == nil {
+		return nil
+	}
+	for i := range r.Operations {
+		if r.Operations[i].Error != "" {
+			return &r.Operations[i]
+		}
+	}
+	return nil
+}
+
+// patchSuccessStatus retorna o status HTTP equivalente ao sucesso da operação
+func patchSuccessStatus(opType string) int {
+	switch opType {
+	case "INSERT":
+		return http.StatusCreated
+	case "DELETE":
+		return http.StatusNoContent
+	default:
+		return http.StatusOK
+	}
+}
+
+// patchErrorStatus classifica o erro de uma operação. Registros inexistentes (por
+// exemplo, removidos por outro cliente) são reportados como 404
+func patchErrorStatus(err error) int {
+	if violation, ok := constraintViolation(err); ok {
+		return violation.StatusCode()
+	}
+	message := err.Error()
+	if strings.Contains(message, "not found") || strings.Contains(message, "no rows updated") || strings.Contains(message, "no rows deleted") {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// attachPatchReport adiciona o relatório de operações à entidade retornada
+func attachPatchReport(entity interface{}, report *PatchReport) interface{} {
+	switch e := entity.(type) {
+	case *OrderedEntity:
+		e.Set(PatchOperationsAnnotation, report.Operations)
+		return e
+	case map[string]interface{}:
+		e[PatchOperationsAnnotation] = report.Operations
+		return e
+	default:
+		return map[string]interface{}{
+			"value":                   entity,
+			PatchOperationsAnnotation: report.Operations,
+		}
+	}
+}
+
+// hasPreference verifica se o header Prefer contém a preferência informada
+func hasPreference(prefer, name string) bool {
+	for _, preference := range strings.Split(prefer, ",") {
+		token, _, _ := strings.Cut(strings.TrimSpace(preference), "=")
+		if strings.EqualFold(strings.TrimSpace(token), name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func newPatchReportTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range []string{
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, customer TEXT)",
+		"CREATE TABLE order_items (id INTEGER PRIMARY KEY AUTOINCREMENT, order_id INTEGER, product TEXT)",
+		"INSERT INTO orders (id, customer) VALUES (1, 'Ana')",
+		"INSERT INTO order_items (id, order_id, product) VALUES (1, 1, 'Mouse'), (2, 1, 'Teclado')",
+	} {
+		_, err = db.Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+	provider := NewMySQLProvider(db)
+	server := &Server{
+		provider:     provider,
+		entities:     make(map[string]EntityService),
+		parser:       NewODataParser(),
+		urlParser:    NewURLParser(),
+		logger:       logger,
+		config:       DefaultServerConfig(),
+		entityAuth:   make(map[string]EntityAuthConfig),
+		eventManager: NewEntityEventManager(logger),
+	}
+
+	server.entities["Orders"] = NewBaseEntityService(provider, EntityMetadata{
+		Name:      "Orders",
+		TableName: "orders",
+		Keys:      []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "customer", ColumnName: "customer", Type: "string"},
+			{Name: "Items", IsNavigation: true, IsCollection: true, RelatedType: "OrderItems"},
+		},
+	}, server)
+	server.entities["OrderItems"] = NewBaseEntityService(provider, EntityMetadata{
+		Name:      "OrderItems",
+		TableName: "order_items",
+		Keys:      []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "order_id", ColumnName: "order_id", Type: "int64"},
+			{Name: "product", ColumnName: "product", Type: "string"},
+		},
+	}, server)
+	return server
+}
+
+func TestBaseEntityService_PatchWithReport(t *testing.T) {
+	ctx := context.Background()
+	keys := map[string]any{"id": int64(1)}
+
+	t.Run("Reporta cada operação aninhada", func(t *testing.T) {
+		server := newPatchReportTestServer(t)
+		service := server.entities["Orders"].(*BaseEntityService)
+
+		_, report, err := service.PatchWithReport(ctx, keys, map[string]interface{}{
+			"id":       1,
+			"customer": "Ana Maria",
+			"Items": []interface{}{
+				map[string]interface{}{"id": 1, "@odata.removed": map[string]interface{}{}},
+				map[string]interface{}{"id": 2, "product": "Teclado BR"},
+				map[string]interface{}{"order_id": 1, "product": "Monitor"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, report.Operations, 4)
+		assert.False(t, report.RolledBack)
+
+		types := make([]string, 0)
+		for _, op := range report.Operations {
+			types = append(types, op.Type)
+		}
+		assert.Equal(t, []string{"DELETE", "UPDATE", "UPDATE", "INSERT"}, types)
+
+		assert.Equal(t, "OrderItems", report.Operations[0].EntityName)
+		assert.Equal(t, "Items", report.Operations[0].NavigationPath)
+		assert.Equal(t, http.StatusNoContent, report.Operations[0].Status)
+		assert.Equal(t, http.StatusCreated, report.Operations[3].Status)
+		assert.Equal(t, int64(3), report.Operations[3].Keys["id"], "INSERT deve reportar a chave gerada")
+	})
+
+	t.Run("Indica a operação que falhou e as não executadas", func(t *testing.T) {
+		server := newPatchReportTestServer(t)
+		service := server.entities["Orders"].(*BaseEntityService)
+
+		_, report, err := service.PatchWithReport(ctx, keys, map[string]interface{}{
+			"id": 1,
+			"Items": []interface{}{
+				map[string]interface{}{"id": 99, "product": "Inexistente"},
+				map[string]interface{}{"order_id": 1, "product": "Monitor"},
+			},
+		})
+		require.Error(t, err)
+		assert.True(t, report.RolledBack)
+
+		failed := report.FailedOperation()
+		require.NotNil(t, failed)
+		assert.Equal(t, http.StatusNotFound, failed.Status)
+		assert.Equal(t, int64(99), toInt64(failed.Keys["id"]))
+
+		last := report.Operations[len(report.Operations)-1]
+		assert.Equal(t, "INSERT", last.Type)
+		assert.Equal(t, http.StatusFailedDependency, last.Status)
+	})
+}
+
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+func TestHandleUpdateEntity_PatchReportPreference(t *testing.T) {
+	server := newPatchReportTestServer(t)
+	service := server.entities["Orders"]
+
+	app := fiber.New()
+	app.Patch("/odata/Orders(1)", func(c fiber.Ctx) error {
+		return server.handleUpdateEntity(c, service, map[string]interface{}{"id": int64(1)})
+	})
+
+	send := func(body, prefer string) (*http.Response, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPatch, "/odata/Orders(1)", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if prefer != "" {
+			req.Header.Set("Prefer", prefer)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		raw, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(raw, &payload), string(raw))
+		return resp, payload
+	}
+
+	t.Run("Sem preferência mantém a resposta original", func(t *testing.T) {
+		resp, payload := send(`{"id":1,"customer":"Ana","Items":[{"id":2,"product":"Teclado BR"}]}`, "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotContains(t, payload, PatchOperationsAnnotation)
+		assert.Empty(t, resp.Header.Get("Preference-Applied"))
+	})
+
+	t.Run("Com preferência inclui o relatório", func(t *testing.T) {
+		resp, payload := send(`{"id":1,"customer":"Ana","Items":[{"order_id":1,"product":"Webcam"}]}`, PreferPatchReport)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, PreferPatchReport, resp.Header.Get("Preference-Applied"))
+		assert.Equal(t, "Ana", payload["customer"])
+
+		operations, ok := payload[PatchOperationsAnnotation].([]interface{})
+		require.True(t, ok)
+		require.Len(t, operations, 2)
+		assert.Equal(t, "UPDATE", operations[0].(map[string]interface{})["type"])
+		assert.Equal(t, "INSERT", operations[1].(map[string]interface{})["type"])
+	})
+
+	t.Run("Falha retorna o relatório no erro", func(t *testing.T) {
+		resp, payload := send(`{"id":1,"customer":"Ana","Items":[{"id":42,"product":"X"}]}`, PreferPatchReport)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		odataErr := payload["error"].(map[string]interface{})
+		assert.Equal(t, "Items", odataErr["target"])
+		assert.Contains(t, payload, PatchOperationsAnnotation)
+	})
+}
+
+func TestHasPreference(t *testing.T) {
+	assert.True(t, hasPreference("return=minimal, godata.patch-report", PreferPatchReport))
+	assert.True(t, hasPreference("GODATA.PATCH-REPORT", PreferPatchReport))
+	assert.False(t, hasPreference("return=representation", PreferPatchReport))
+}
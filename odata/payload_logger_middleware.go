@@ -51,7 +51,11 @@ func (s *Server) PayloadLoggerMiddleware() fiber.Handler {
 		// Tenta obter o body da response
 		// Nota: No Fiber, após c.Next() o body pode já ter sido enviado
 		// Vamos tentar capturar do Response().Body()
-		responseBody := c.Response().Body()
+		// Corpo em streaming (ex: $batch multipart) não é lido: consumiria o stream
+		var responseBody []byte
+		if !c.Response().IsBodyStream() {
+			responseBody = c.Response().Body()
+		}
 
 		if len(responseBody) > 0 {
 			formattedResponse := formatPayload(responseBody, responseContentType)
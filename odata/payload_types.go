@@ -0,0 +1,310 @@
+package odata
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// =======================================================================================
+// CONVERSÃO TIPADA DOS PAYLOADS DE ESCRITA
+// =======================================================================================
+
+// preparePayloadValues valida e converte os valores do payload de escrita para o tipo
+// declarado de cada propriedade antes da montagem do INSERT/UPDATE. Valores incompatíveis
+// retornam um ODataError 400 com um detalhe por propriedade, nomeando o tipo esperado
+func (s *BaseEntityService) preparePayloadValues(metadata EntityMetadata, data map[string]any) error {
+	if err := convertPayloadTypes(metadata, data, s.server.timeZone()); err != nil {
+		return err
+	}
+	s.normalizeDateTimeValues(metadata, data)
+	s.normalizeDecimalValues(metadata, data)
+	s.normalizeInt64Values(metadata, data)
+	return nil
+}
+
+// convertPayloadTypes converte os valores das propriedades do payload. Valores já tipados em
+// Go (time.Time, Decimal, driver.Valuer) são mantidos; propriedades desconhecidas, navegações
+// e tipos complexos seguem sem conversão
+func convertPayloadTypes(metadata EntityMetadata, data map[string]any, location *time.Location) error {
+	badRequest := NewODataError(ODataErrorCode(http.StatusBadRequest), "Invalid property values").WithStatus(http.StatusBadRequest)
+	for _, prop := range metadata.Properties {
+		if prop.IsNavigation {
+			continue
+		}
+		value, exists := data[prop.Name]
+		if !exists {
+			continue
+		}
+		converted, err := convertPayloadValue(value, prop, location)
+		if err != nil {
+			badRequest.WithDetail("InvalidPropertyValue",
+				fmt.Sprintf("Property '%s' expects %s: %v", prop.Name, payloadEdmType(prop), err), prop.Name)
+			continue
+		}
+		data[prop.Name] = converted
+	}
+
+	if len(badRequest.Details) > 0 {
+		if len(badRequest.Details) == 1 {
+			badRequest.Message = badRequest.Details[0].Message
+			badRequest.Target = badRequest.Details[0].Target
+		}
+		return badRequest
+	}
+	return nil
+}
+
+// convertPayloadValue converte um valor para o tipo da propriedade
+func convertPayloadValue(value any, prop PropertyMetadata, location *time.Location) (any, error) {
+	value, ok := payloadScalar(value)
+	if !ok || value == nil {
+		return value, nil
+	}
+
+	var converted any
+	var err error
+	switch {
+	case isDecimalProperty(prop):
+		converted, err = payloadDecimal(value)
+	case prop.Type == "int" || prop.Type == "int32":
+		converted, err = payloadInteger(value, math.MinInt32, math.MaxInt32)
+	case prop.Type == "int64":
+		converted, err = payloadInteger(value, math.MinInt64, math.MaxInt64)
+	case prop.Type == "float32" || prop.Type == "float64":
+		converted, err = payloadFloat(value)
+	case prop.Type == "bool":
+		converted, err = payloadBool(value)
+	case prop.Type == "time.Time":
+		converted, err = payloadDateTime(value, location)
+	case prop.Type == "string":
+		converted, err = payloadString(value, prop)
+	default:
+		return value, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(prop.EnumValues) > 0 {
+		member := fmt.Sprint(converted)
+		for _, allowed := range prop.EnumValues {
+			if member == allowed {
+				return converted, nil
+			}
+		}
+		return nil, fmt.Errorf("'%s' is not an allowed value (%s)", member, strings.Join(prop.EnumValues, ", "))
+	}
+	return converted, nil
+}
+
+// payloadScalar resolve ponteiros e tipos nomeados (ex: type Status string) para o valor
+// escalar. Retorna false para valores já tipados que seguem sem conversão
+func payloadScalar(value any) (any, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, true
+	case time.Time, Decimal, json.Number, string, bool, int64, float64:
+		return v, true
+	case *time.Time:
+		if v == nil {
+			return nil, true
+		}
+		return *v, true
+	case driver.Valuer:
+		return v, false
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, true
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), true
+	case reflect.Bool:
+		return rv.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return rv.Interface(), false
+		}
+		return int64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.Struct:
+		if t, ok := rv.Interface().(time.Time); ok {
+			return t, true
+		}
+		if d, ok := rv.Interface().(Decimal); ok {
+			return d, true
+		}
+	case reflect.Map, reflect.Slice, reflect.Array:
+		// Objetos e arrays só são aceitos por propriedades complexas (sem conversão)
+		return value, true
+	}
+	return value, false
+}
+
+// payloadInteger converte para int64 dentro do intervalo do tipo. Strings numéricas são
+// aceitas (IEEE754Compatible); números com parte fracionária não
+func payloadInteger(value any, min, max int64) (any, error) {
+	var integer int64
+	switch v := value.(type) {
+	case int64:
+		integer = v
+	case float64:
+		if v != math.Trunc(v) || math.IsInf(v, 0) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return nil, fmt.Errorf("%v is not a valid integer", v)
+		}
+		integer = int64(v)
+	case json.Number:
+		parsed, err := v.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid integer", v)
+		}
+		integer = parsed
+	case string:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a valid integer", v)
+		}
+		integer = parsed
+	default:
+		return nil, fmt.Errorf("%s is not a valid integer", payloadKind(value))
+	}
+	if integer < min || integer > max {
+		return nil, fmt.Errorf("%d is out of range", integer)
+	}
+	return integer, nil
+}
+
+// payloadFloat converte para float64. Strings aceitam números e INF, -INF e NaN
+func payloadFloat(value any) (any, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid number", v)
+		}
+		return parsed, nil
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a valid number", v)
+		}
+		return parsed, nil
+	}
+	return nil, fmt.Errorf("%s is not a valid number", payloadKind(value))
+}
+
+// payloadDecimal converte para Decimal sem passar por float64 (números e strings)
+func payloadDecimal(value any) (any, error) {
+	decimal, err := toDecimal(value)
+	if err != nil {
+		return nil, fmt.Errorf("'%v' is not a valid decimal", value)
+	}
+	return decimal, nil
+}
+
+// payloadBool aceita booleanos e as strings "true" e "false"
+func payloadBool(value any) (any, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("'%s' is not a valid boolean", v)
+	}
+	return nil, fmt.Errorf("%s is not a valid boolean", payloadKind(value))
+}
+
+// payloadDateTime aceita time.Time e literais ISO 8601 (sem offset, no fuso do servidor)
+func payloadDateTime(value any, location *time.Location) (any, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		parsed, err := parseDateTimeLiteral(strings.TrimSpace(v), location)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a valid date/time (ISO 8601)", v)
+		}
+		return parsed, nil
+	}
+	return nil, fmt.Errorf("%s is not a valid date/time", payloadKind(value))
+}
+
+// payloadString aceita apenas strings, respeitando o tamanho máximo da propriedade
+func payloadString(value any, prop PropertyMetadata) (any, error) {
+	text, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a string", payloadKind(value))
+	}
+	if prop.MaxLength > 0 && utf8.RuneCountInString(text) > prop.MaxLength {
+		return nil, fmt.Errorf("value exceeds the maximum length of %d", prop.MaxLength)
+	}
+	return text, nil
+}
+
+// payloadKind descreve o tipo JSON do valor nas mensagens de erro
+func payloadKind(value any) string {
+	switch value.(type) {
+	case bool:
+		return "a boolean"
+	case string:
+		return "a string"
+	case int64, float64, json.Number, Decimal:
+		return "a number"
+	case time.Time:
+		return "a date/time"
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map:
+		return "an object"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return fmt.Sprintf("a value of type %T", value)
+}
+
+// payloadEdmType retorna o tipo EDM da propriedade nas mensagens de erro
+func payloadEdmType(prop PropertyMetadata) string {
+	if isDecimalProperty(prop) {
+		return "Edm.Decimal"
+	}
+	switch prop.Type {
+	case "int", "int32":
+		return "Edm.Int32"
+	case "int64":
+		return "Edm.Int64"
+	case "float32":
+		return "Edm.Single"
+	case "float64":
+		return "Edm.Double"
+	case "bool":
+		return "Edm.Boolean"
+	case "time.Time":
+		return "Edm.DateTimeOffset"
+	}
+	return "Edm.String"
+}
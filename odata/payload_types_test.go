@@ -0,0 +1,135 @@
+package odata
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type payloadStatus string
+
+func TestConvertPayloadTypes(t *testing.T) {
+	type Ticket struct {
+		ID       int64     `json:"id" primaryKey:"idGenerator:auto"`
+		Title    string    `json:"title" odata:"length:10"`
+		Status   string    `json:"status" odata:"enum:open|closed"`
+		Priority int32     `json:"priority"`
+		Urgent   bool      `json:"urgent"`
+		Cost     Decimal   `json:"cost" prop:"precision:10; scale:2"`
+		Score    float64   `json:"score"`
+		OpenedAt time.Time `json:"opened_at"`
+		Tags     []string  `json:"tags"`
+	}
+	metadata, err := NewEntityMapper().MapEntity(Ticket{})
+	require.NoError(t, err)
+
+	t.Run("converts to the declared types", func(t *testing.T) {
+		data := map[string]any{
+			"id":        json.Number("9007199254740993"),
+			"title":     "Impressora",
+			"status":    payloadStatus("open"),
+			"priority":  float64(3),
+			"urgent":    "true",
+			"cost":      json.Number("10.25"),
+			"score":     "INF",
+			"opened_at": "2024-05-10T08:30:00-03:00",
+			"tags":      []any{"a", "b"},
+			"other":     1.5,
+		}
+		require.NoError(t, convertPayloadTypes(metadata, data, time.UTC))
+
+		assert.Equal(t, int64(9007199254740993), data["id"])
+		assert.Equal(t, "open", data["status"])
+		assert.Equal(t, int64(3), data["priority"])
+		assert.Equal(t, true, data["urgent"])
+		assert.Equal(t, "10.25", data["cost"].(Decimal).String())
+		assert.True(t, data["score"].(float64) > 0)
+		assert.Equal(t, time.Date(2024, 5, 10, 11, 30, 0, 0, time.UTC), data["opened_at"].(time.Time).UTC())
+		assert.Equal(t, []any{"a", "b"}, data["tags"])
+		assert.Equal(t, 1.5, data["other"])
+	})
+
+	t.Run("reports every invalid property", func(t *testing.T) {
+		err := convertPayloadTypes(metadata, map[string]any{
+			"id":        1.5,
+			"title":     "Título longo demais",
+			"status":    "pending",
+			"priority":  float64(1 << 40),
+			"urgent":    1.0,
+			"cost":      "dez",
+			"opened_at": "ontem",
+			"score":     map[string]any{},
+		}, time.UTC)
+
+		var odataErr *ODataError
+		require.True(t, errors.As(err, &odataErr))
+		assert.Equal(t, http.StatusBadRequest, odataErr.Status)
+
+		messages := make(map[string]string)
+		for _, detail := range odataErr.Details {
+			assert.Equal(t, "InvalidPropertyValue", detail.Code)
+			messages[detail.Target] = detail.Message
+		}
+		assert.Equal(t, "Property 'id' expects Edm.Int64: 1.5 is not a valid integer", messages["id"])
+		assert.Equal(t, "Property 'title' expects Edm.String: value exceeds the maximum length of 10", messages["title"])
+		assert.Equal(t, "Property 'status' expects Edm.String: 'pending' is not an allowed value (open, closed)", messages["status"])
+		assert.Equal(t, "Property 'priority' expects Edm.Int32: 1099511627776 is out of range", messages["priority"])
+		assert.Equal(t, "Property 'urgent' expects Edm.Boolean: a number is not a valid boolean", messages["urgent"])
+		assert.Equal(t, "Property 'cost' expects Edm.Decimal: 'dez' is not a valid decimal", messages["cost"])
+		assert.Equal(t, "Property 'opened_at' expects Edm.DateTimeOffset: 'ontem' is not a valid date/time (ISO 8601)", messages["opened_at"])
+		assert.Equal(t, "Property 'score' expects Edm.Double: an object is not a valid number", messages["score"])
+	})
+
+	t.Run("single invalid property targets it", func(t *testing.T) {
+		err := convertPayloadTypes(metadata, map[string]any{"title": 42.0, "urgent": nil}, time.UTC)
+
+		var odataErr *ODataError
+		require.True(t, errors.As(err, &odataErr))
+		assert.Equal(t, "title", odataErr.Target)
+		assert.Equal(t, "Property 'title' expects Edm.String: a number is not a string", odataErr.Message)
+	})
+}
+
+func TestServer_TypedWritePayload(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	send := func(t *testing.T, method, target, body string) (int, map[string]any) {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var decoded map[string]any
+		json.NewDecoder(resp.Body).Decode(&decoded)
+		return resp.StatusCode, decoded
+	}
+
+	status, body := send(t, http.MethodPost, "/odata/Products", `{"id": 10, "name": "Cabo", "price": "barato"}`)
+	require.Equal(t, http.StatusBadRequest, status, body)
+	odataErr := body["error"].(map[string]any)
+	assert.Equal(t, "price", odataErr["target"])
+	assert.Equal(t, "Property 'price' expects Edm.Double: 'barato' is not a valid number", odataErr["message"])
+
+	status, body = send(t, http.MethodPatch, "/odata/Products(1)", `{"name": 7}`)
+	require.Equal(t, http.StatusBadRequest, status, body)
+	assert.Equal(t, "name", body["error"].(map[string]any)["target"])
+
+	var name string
+	require.NoError(t, server.provider.GetConnection().QueryRow("SELECT name FROM products WHERE id = 1").Scan(&name))
+	assert.Equal(t, "Mouse", name)
+
+	status, body = send(t, http.MethodPost, "/odata/Products", `{"id": 10, "name": "Cabo", "price": "12.5"}`)
+	require.Equal(t, http.StatusCreated, status, body)
+	assert.Equal(t, 12.5, body["price"])
+}
@@ -15,6 +15,7 @@ type BaseProvider struct {
 	driverName    string
 	queryBuilder  *QueryBuilder
 	computeParser *ComputeParser
+	session       *SessionConfig
 	searchParser  *SearchParser
 }
 
@@ -98,6 +99,17 @@ func (p *BaseProvider) BuildSelectQueryOptimized(ctx context.Context, metadata E
 	query.WriteString(selectClause)
 	args = append(args, computeArgs...)
 
+	// ORDER BY é construído antes do FROM: caminhos de navegação (Category/Name) geram JOINs
+	joins := newNavigationJoins(metadata, options.navigationResolver)
+	var orderByClause string
+	var orderByArgs []interface{}
+	if options.OrderBy != "" {
+		orderByClause, orderByArgs, err = p.buildOrderByClause(ctx, options.OrderBy, metadata, options.Compute, joins, options.rootReferences)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build order by clause: %w", err)
+		}
+	}
+
 	// FROM clause
 	tableName := metadata.TableName
 	if tableName == "" {
@@ -105,6 +117,7 @@ func (p *BaseProvider) BuildSelectQueryOptimized(ctx context.Context, metadata E
 	}
 	query.WriteString(" FROM ")
 	query.WriteString(tableName)
+	query.WriteString(joins.sql(tableName))
 
 	// WHERE clause - combina filtro e busca
 	whereClause, whereArgs, err := p.buildWhereWithSearch(ctx, metadata, options)
@@ -118,15 +131,10 @@ func (p *BaseProvider) BuildSelectQueryOptimized(ctx context.Context, metadata E
 	}
 
 	// ORDER BY clause
-	if options.OrderBy != "" {
-		orderByClause, err := p.BuildOrderByClause(options.OrderBy, metadata)
-		if err != nil {
-			return "", nil, fmt.Errorf("failed to build order by clause: %w", err)
-		}
-		if orderByClause != "" {
-			query.WriteString(" ORDER BY ")
-			query.WriteString(orderByClause)
-		}
+	if orderByClause != "" {
+		query.WriteString(" ORDER BY ")
+		query.WriteString(orderByClause)
+		args = append(args, orderByArgs...)
 	}
 
 	// LIMIT/OFFSET clause
@@ -293,17 +301,38 @@ func (p *BaseProvider) buildCondition(expr FilterExpression, prop PropertyMetada
 
 // BuildOrderByClause constrói a cláusula ORDER BY baseada no orderBy OData
 func (p *BaseProvider) BuildOrderByClause(orderBy string, metadata EntityMetadata) (string, error) {
+	return p.BuildOrderByClauseContext(context.Background(), orderBy, metadata)
+}
+
+// BuildOrderByClauseContext constrói a cláusula ORDER BY com o contexto da requisição
+func (p *BaseProvider) BuildOrderByClauseContext(ctx context.Context, orderBy string, metadata EntityMetadata) (string, error) {
+	clause, args, err := p.buildOrderByClause(ctx, orderBy, metadata, nil, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(args) > 0 {
+		return "", fmt.Errorf("orderby expressions with literal values require BuildSelectQueryOptimized")
+	}
+	return clause, nil
+}
+
+// buildOrderByClause constrói o ORDER BY aceitando expressões (com os templates do $filter),
+// aliases de $compute, ordenados pela coluna calculada no SELECT (todos os dialetos
+// aceitam aliases do SELECT no ORDER BY), e caminhos de navegação N:1, registrados em joins.
+// As referências $root das expressões usam as subqueries resolvidas pelo serviço (roots)
+func (p *BaseProvider) buildOrderByClause(ctx context.Context, orderBy string, metadata EntityMetadata, computeOption *ComputeOption, joins *navigationJoins, roots map[string]*rootReference) (string, []interface{}, error) {
 	if orderBy == "" {
-		return "", nil
+		return "", nil, nil
 	}
 
 	parser := NewODataParser()
 	expressions, err := parser.ParseOrderBy(orderBy)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	var orderClauses []string
+	var args []interface{}
 
 	for _, expr := range expressions {
 		// Encontra a propriedade nos metadados
@@ -315,8 +344,37 @@ func (p *BaseProvider) BuildOrderByClause(orderBy string, metadata EntityMetadat
 			}
 		}
 
+		direction := "ASC"
+		if expr.Direction == OrderDesc {
+			direction = "DESC"
+		}
+
+		if expr.Expression != nil {
+			tree := attachRootReferences(inlineComputeAliases(expr.Expression, computeOption, metadata), roots)
+			exprSQL, exprArgs, err := p.GetQueryBuilder().buildNodeExpression(ctx, tree, metadata)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid orderby expression '%s': %w", expr.Property, err)
+			}
+			orderClauses = append(orderClauses, fmt.Sprintf("%s %s", exprSQL, direction))
+			args = append(args, exprArgs...)
+			continue
+		}
+
+		if prop == nil && strings.Contains(expr.Property, "/") {
+			column, err := joins.column(expr.Property)
+			if err != nil {
+				return "", nil, err
+			}
+			orderClauses = append(orderClauses, fmt.Sprintf("%s %s", column, direction))
+			continue
+		}
+
 		if prop == nil {
-			return "", fmt.Errorf("property %s not found in entity %s", expr.Property, metadata.Name)
+			if computed := computeOption.FindAlias(expr.Property); computed != nil {
+				orderClauses = append(orderClauses, fmt.Sprintf("%s %s", p.GetQueryBuilder().QuoteIdentifier(computed.Alias), direction))
+				continue
+			}
+			return "", nil, fmt.Errorf("property %s not found in entity %s", expr.Property, metadata.Name)
 		}
 
 		columnName := prop.ColumnName
@@ -324,15 +382,10 @@ func (p *BaseProvider) BuildOrderByClause(orderBy string, metadata EntityMetadat
 			columnName = prop.Name
 		}
 
-		direction := "ASC"
-		if expr.Direction == OrderDesc {
-			direction = "DESC"
-		}
-
 		orderClauses = append(orderClauses, fmt.Sprintf("%s %s", columnName, direction))
 	}
 
-	return strings.Join(orderClauses, ", "), nil
+	return strings.Join(orderClauses, ", "), args, nil
 }
 
 // buildStringFunctionCondition constrói condições para funções de string
@@ -564,7 +617,7 @@ func (p *BaseProvider) MapGoTypeToSQL(goType string) string {
 		return "VARCHAR"
 	case "int", "int32", "int64":
 		return "INTEGER"
-	case "float32", "float64":
+	case "float32", "float64", "decimal":
 		return "DECIMAL"
 	case "bool":
 		return "BOOLEAN"
@@ -1,6 +1,7 @@
 package odata
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -117,6 +118,15 @@ func (p *MySQLProvider) Connect(connectionString string) error {
 
 // BuildSelectQuery constrói uma query SELECT específica para MySQL
 func (p *MySQLProvider) BuildSelectQuery(entity EntityMetadata, options QueryOptions) (string, []interface{}, error) {
+	return p.BuildSelectQueryContext(context.Background(), entity, options)
+}
+
+// BuildSelectQueryContext constrói a query SELECT de BuildSelectQuery com o contexto da requisição
+func (p *MySQLProvider) BuildSelectQueryContext(ctx context.Context, entity EntityMetadata, options QueryOptions) (string, []interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return "", nil, fmt.Errorf("context cancelled before building query: %w", err)
+	}
+
 	// SELECT clause
 	selectFields := GetSelectedProperties(options.Select)
 	selectClause, err := p.BuildSelectClause(selectFields, entity)
@@ -147,7 +157,7 @@ func (p *MySQLProvider) BuildSelectQuery(entity EntityMetadata, options QueryOpt
 
 	// ORDER BY clause
 	if options.OrderBy != "" {
-		orderByClause, err := p.BuildOrderByClause(options.OrderBy, entity)
+		orderByClause, err := p.BuildOrderByClauseContext(ctx, options.OrderBy, entity)
 		if err != nil {
 			return "", nil, err
 		}
@@ -402,6 +412,8 @@ func (p *MySQLProvider) MapGoTypeToSQL(goType string) string {
 		return "FLOAT"
 	case "float64":
 		return "DOUBLE"
+	case "decimal":
+		return "DECIMAL(38,10)"
 	case "bool":
 		return "BOOLEAN"
 	case "time.Time":
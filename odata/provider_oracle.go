@@ -144,6 +144,15 @@ func (p *OracleProvider) BuildSelectQueryOptimized(ctx context.Context, entity E
 
 // BuildSelectQuery constrói uma query SELECT específica para Oracle
 func (p *OracleProvider) BuildSelectQuery(entity EntityMetadata, options QueryOptions) (string, []interface{}, error) {
+	return p.BuildSelectQueryContext(context.Background(), entity, options)
+}
+
+// BuildSelectQueryContext constrói a query SELECT de BuildSelectQuery com o contexto da requisição
+func (p *OracleProvider) BuildSelectQueryContext(ctx context.Context, entity EntityMetadata, options QueryOptions) (string, []interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return "", nil, fmt.Errorf("context cancelled before building query: %w", err)
+	}
+
 	// SELECT clause
 	selectFields := GetSelectedProperties(options.Select)
 	selectClause, err := p.BuildSelectClause(selectFields, entity)
@@ -164,7 +173,7 @@ func (p *OracleProvider) BuildSelectQuery(entity EntityMetadata, options QueryOp
 	// WHERE clause - usa argumentos nomeados para Oracle
 	if options.Filter != nil && options.Filter.Tree != nil {
 		// Sanitiza a query antes de construir WHERE para evitar caracteres inválidos
-		whereClause, namedArgs, err := p.buildSanitizedWhereClauseNamed(context.Background(), options.Filter.Tree, entity)
+		whereClause, namedArgs, err := p.buildSanitizedWhereClauseNamed(ctx, options.Filter.Tree, entity)
 		if err != nil {
 			return "", nil, fmt.Errorf("failed to build where clause: %w", err)
 		}
@@ -176,7 +185,7 @@ func (p *OracleProvider) BuildSelectQuery(entity EntityMetadata, options QueryOp
 
 	// ORDER BY clause
 	if options.OrderBy != "" {
-		orderByClause, err := p.BuildOrderByClause(options.OrderBy, entity)
+		orderByClause, err := p.BuildOrderByClauseContext(ctx, options.OrderBy, entity)
 		if err != nil {
 			return "", nil, err
 		}
@@ -461,6 +470,70 @@ func (p *OracleProvider) BuildUpdateQuery(entity EntityMetadata, data map[string
 	return query, []interface{}{namedArgs.GetArgs()}, nil
 }
 
+// buildReturningInto acrescenta RETURNING ... INTO à query de INSERT/UPDATE, para obter a
+// linha gravada sem um SELECT adicional
+func (p *OracleProvider) buildReturningInto(entity EntityMetadata, query string, args []interface{}) (*returningInto, error) {
+	returning := &returningInto{}
+
+	// Os builders agrupam os argumentos nomeados em um único slice
+	for _, arg := range args {
+		if nested, ok := arg.([]interface{}); ok {
+			returning.args = append(returning.args, nested...)
+		} else {
+			returning.args = append(returning.args, arg)
+		}
+	}
+
+	var placeholders []string
+	for _, prop := range entity.Properties {
+		if prop.IsNavigation {
+			continue
+		}
+		columnName := prop.ColumnName
+		if columnName == "" {
+			columnName = prop.Name
+		}
+
+		paramName := fmt.Sprintf("ret%d", len(returning.columns)+1)
+		output := oracleReturningOutput(prop)
+		returning.columns = append(returning.columns, columnName)
+		returning.outputs = append(returning.outputs, output)
+		returning.args = append(returning.args, sql.Named(paramName, sql.Out{Dest: output}))
+		placeholders = append(placeholders, ":"+paramName)
+	}
+
+	if len(placeholders) == 0 {
+		return nil, fmt.Errorf("no columns found for returning")
+	}
+
+	returning.query = p.sanitizeOracleQuery(fmt.Sprintf("%s RETURNING %s INTO %s",
+		query,
+		strings.Join(returning.columns, ", "),
+		strings.Join(placeholders, ", ")))
+
+	return returning, nil
+}
+
+// oracleReturningOutput cria a variável de saída da coluna retornada conforme o tipo da
+// propriedade (booleanos são NUMBER(1) no Oracle)
+func oracleReturningOutput(prop PropertyMetadata) interface{} {
+	if isDecimalProperty(prop) {
+		return &sql.NullString{}
+	}
+	switch prop.Type {
+	case "int64", "int32", "int", "bool", "boolean":
+		return &sql.NullInt64{}
+	case "float64", "double", "float32", "single":
+		return &sql.NullFloat64{}
+	case "time.Time":
+		return &sql.NullTime{}
+	case "[]byte", "binary":
+		return new([]byte)
+	default:
+		return &sql.NullString{}
+	}
+}
+
 // BuildDeleteQuery constrói uma query DELETE específica para Oracle
 func (p *OracleProvider) BuildDeleteQuery(entity EntityMetadata, keyValues map[string]interface{}) (string, []interface{}, error) {
 	tableName := entity.TableName
@@ -621,6 +694,8 @@ func (p *OracleProvider) MapGoTypeToSQL(goType string) string {
 		return "NUMBER(7,2)"
 	case "float64":
 		return "NUMBER(15,2)"
+	case "decimal":
+		return "NUMBER"
 	case "bool":
 		return "NUMBER(1)"
 	case "time.Time":
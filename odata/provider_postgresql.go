@@ -1,6 +1,7 @@
 package odata
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -118,6 +119,15 @@ func (p *PostgreSQLProvider) Connect(connectionString string) error {
 
 // BuildSelectQuery constrói uma query SELECT específica para PostgreSQL
 func (p *PostgreSQLProvider) BuildSelectQuery(entity EntityMetadata, options QueryOptions) (string, []interface{}, error) {
+	return p.BuildSelectQueryContext(context.Background(), entity, options)
+}
+
+// BuildSelectQueryContext constrói a query SELECT de BuildSelectQuery com o contexto da requisição
+func (p *PostgreSQLProvider) BuildSelectQueryContext(ctx context.Context, entity EntityMetadata, options QueryOptions) (string, []interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return "", nil, fmt.Errorf("context cancelled before building query: %w", err)
+	}
+
 	// SELECT clause
 	selectFields := GetSelectedProperties(options.Select)
 	selectClause, err := p.BuildSelectClause(selectFields, entity)
@@ -148,7 +158,7 @@ func (p *PostgreSQLProvider) BuildSelectQuery(entity EntityMetadata, options Que
 
 	// ORDER BY clause
 	if options.OrderBy != "" {
-		orderByClause, err := p.BuildOrderByClause(options.OrderBy, entity)
+		orderByClause, err := p.BuildOrderByClauseContext(ctx, options.OrderBy, entity)
 		if err != nil {
 			return "", nil, err
 		}
@@ -242,6 +252,11 @@ func (p *PostgreSQLProvider) BuildInsertQuery(entity EntityMetadata, data map[st
 	return query, args, nil
 }
 
+// writesReturnRows indica que INSERT e UPDATE terminam em RETURNING * e devolvem a linha gravada
+func (p *PostgreSQLProvider) writesReturnRows() bool {
+	return true
+}
+
 // BuildUpdateQuery constrói uma query UPDATE específica para PostgreSQL
 func (p *PostgreSQLProvider) BuildUpdateQuery(entity EntityMetadata, data map[string]interface{}, keyValues map[string]interface{}) (string, []interface{}, error) {
 	tableName := entity.TableName
@@ -413,6 +428,8 @@ func (p *PostgreSQLProvider) MapGoTypeToSQL(goType string) string {
 		return "REAL"
 	case "float64":
 		return "DOUBLE PRECISION"
+	case "decimal":
+		return "NUMERIC"
 	case "bool":
 		return "BOOLEAN"
 	case "time.Time":
@@ -0,0 +1,81 @@
+package odata
+
+import "fmt"
+
+// =======================================================================================
+// PROVIDERS NOMEADOS (ENTIDADES DE BANCOS DIFERENTES NO MESMO SERVIDOR)
+// =======================================================================================
+
+// AddProvider registra um provider adicional (com seu próprio pool de conexões) com o nome
+// informado. As entidades registradas com WithProvider(name) usam esse provider em vez do
+// padrão. Deve ser chamado antes do registro das entidades
+func (s *Server) AddProvider(name string, provider DatabaseProvider) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.providers == nil {
+		s.providers = make(map[string]DatabaseProvider)
+	}
+	s.providers[name] = provider
+	return s
+}
+
+// GetNamedProvider retorna o provider registrado com AddProvider (nil se não existir)
+func (s *Server) GetNamedProvider(name string) DatabaseProvider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.providers[name]
+}
+
+// GetNamedProviders retorna os providers registrados com AddProvider
+func (s *Server) GetNamedProviders() map[string]DatabaseProvider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	providers := make(map[string]DatabaseProvider, len(s.providers))
+	for name, provider := range s.providers {
+		providers[name] = provider
+	}
+	return providers
+}
+
+// WithProvider associa a entidade ao provider registrado com AddProvider. A entidade usa
+// sempre esse provider, inclusive no modo multi-tenant (banco compartilhado entre tenants)
+func WithProvider(name string) EntityOption {
+	return func(config *EntityConfig) {
+		config.Provider = name
+	}
+}
+
+// resolveEntityProvider retorna o provider nomeado da configuração da entidade
+func (s *Server) resolveEntityProvider(config *EntityConfig) (DatabaseProvider, error) {
+	provider := s.GetNamedProvider(config.Provider)
+	if provider == nil {
+		return nil, fmt.Errorf("provider '%s' não registrado (use AddProvider)", config.Provider)
+	}
+	return provider, nil
+}
+
+// serviceProvider retorna o provider usado pelo serviço da entidade (o padrão para os
+// serviços multi-tenant, que resolvem o provider por requisição)
+func (s *Server) serviceProvider(service EntityService) DatabaseProvider {
+	if base, ok := service.(*BaseEntityService); ok && base.provider != nil {
+		return base.provider
+	}
+	return s.provider
+}
+
+// closeNamedProviders fecha os providers registrados com AddProvider (exceto o padrão, já
+// fechado pelo Shutdown). Chamado com s.mu bloqueado
+func (s *Server) closeNamedProviders() {
+	closed := map[DatabaseProvider]bool{s.provider: true}
+	for name, provider := range s.providers {
+		if provider == nil || closed[provider] {
+			continue
+		}
+		closed[provider] = true
+		if err := provider.Close(); err != nil {
+			s.logger.Printf("Erro ao fechar provider %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type AnalyticsPageView struct {
+	TableName string `table:"page_views"`
+	ID        int64  `json:"id" column:"id" primaryKey:"idGenerator:none"`
+	OrderID   int64  `json:"order_id" column:"order_id"`
+	Path      string `json:"path" column:"path"`
+}
+
+func newNamedProviderTestServer(t *testing.T) (*Server, *sql.DB) {
+	t.Helper()
+
+	server := newPatchDeltaTestServer(t)
+	server.router = fiber.New()
+
+	analytics, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { analytics.Close() })
+	analytics.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		"CREATE TABLE page_views (id INTEGER PRIMARY KEY, order_id INTEGER, path TEXT)",
+		"INSERT INTO page_views VALUES (1, 1, '/checkout'), (2, 2, '/home')",
+	} {
+		_, err := analytics.Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	server.AddProvider("analytics", NewMySQLProvider(analytics))
+	require.NoError(t, server.RegisterEntity("PageViews", AnalyticsPageView{}, WithProvider("analytics")))
+	return server, analytics
+}
+
+func TestServer_NamedProviders(t *testing.T) {
+	server, analytics := newNamedProviderTestServer(t)
+
+	t.Run("unknown provider", func(t *testing.T) {
+		err := server.RegisterEntity("Visits", AnalyticsPageView{}, WithProvider("warehouse"))
+		assert.ErrorContains(t, err, "provider 'warehouse' não registrado")
+	})
+
+	t.Run("query uses the entity provider", func(t *testing.T) {
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/PageViews?$orderby=id", nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body struct {
+			Value []map[string]any `json:"value"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.Len(t, body.Value, 2)
+		assert.Equal(t, "/checkout", body.Value[0]["path"])
+	})
+
+	t.Run("expand resolves the related provider", func(t *testing.T) {
+		orders := server.GetEntityService("Orders").(*BaseEntityService)
+		pageViews := server.GetEntityService("PageViews")
+		assert.Same(t, server.GetNamedProvider("analytics"), orders.relatedProvider(pageViews.GetMetadata()))
+		assert.Same(t, server.provider, orders.relatedProvider(server.GetEntityService("Customers").GetMetadata()))
+	})
+
+	t.Run("changeset runs in the entity database", func(t *testing.T) {
+		processor := NewBatchProcessor(server)
+		responses, err := processor.executeChangeset(context.Background(), []*BatchHTTPOperation{
+			{Method: "POST", URL: "PageViews", Body: []byte(`{"id": 3, "order_id": 1, "path": "/cart"}`)},
+		}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, responses[0].StatusCode, string(responses[0].Body))
+
+		var count int
+		require.NoError(t, analytics.QueryRow("SELECT COUNT(*) FROM page_views").Scan(&count))
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("changeset across providers is rejected", func(t *testing.T) {
+		processor := NewBatchProcessor(server)
+		_, err := processor.executeChangeset(context.Background(), []*BatchHTTPOperation{
+			{Method: "PATCH", URL: "Orders(1)", Body: []byte(`{"status": "closed"}`)},
+			{Method: "POST", URL: "PageViews", Body: []byte(`{"id": 4, "order_id": 1, "path": "/done"}`)},
+		}, map[string]interface{}{})
+
+		var opErr *batchOperationError
+		require.True(t, errors.As(err, &opErr))
+		assert.Equal(t, 1, opErr.Index)
+		assert.Equal(t, http.StatusBadRequest, opErr.Response.StatusCode)
+
+		var status string
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT status FROM orders WHERE id = 1").Scan(&status))
+		assert.Equal(t, "open", status)
+	})
+}
+
+func TestServer_AutoMigrate_NamedProviders(t *testing.T) {
+	server, analytics := newNamedProviderTestServer(t)
+	_, err := analytics.Exec("DROP TABLE page_views")
+	require.NoError(t, err)
+
+	results, err := server.AutoMigrate(context.Background(), AutoMigrateOptions{CreateTables: true})
+	require.NoError(t, err)
+	for _, result := range results {
+		assert.Equal(t, result.EntityName == "PageViews", result.TableCreated, result.EntityName)
+	}
+
+	var count int
+	require.NoError(t, analytics.QueryRow("SELECT COUNT(*) FROM page_views").Scan(&count))
+	assert.Equal(t, 0, count)
+}
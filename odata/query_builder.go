@@ -145,8 +145,7 @@ func (qb *QueryBuilder) buildNodeExpression(ctx context.Context, node *ParseNode
 
 	case int(FilterTokenString):
 		// String literal
-		value := strings.Trim(node.Token.Value, "'")
-		return "?", []interface{}{value}, nil
+		return "?", []interface{}{stringLiteralValue(node.Token)}, nil
 
 	case int(FilterTokenNumber):
 		// Número literal - usa SemanticReference se disponível (valor tipado original)
@@ -164,6 +163,22 @@ func (qb *QueryBuilder) buildNodeExpression(ctx context.Context, node *ParseNode
 		// Null literal
 		return "NULL", []interface{}{}, nil
 
+	case int(FilterTokenDateTime), int(FilterTokenDate), int(FilterTokenTime):
+		// Literais de data/hora no formato do banco
+		template, value, err := qb.buildDateTimeLiteral(node)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf(template, "?"), []interface{}{value}, nil
+
+	case int(FilterTokenDuration):
+		// Duração literal - total de segundos
+		seconds, err := parseDurationLiteral(node.Token.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		return "?", []interface{}{seconds}, nil
+
 	case int(FilterTokenLogical), int(FilterTokenComparison), int(FilterTokenArithmetic):
 		// Operadores binários
 		return qb.buildBinaryOperatorExpression(ctx, node, metadata)
@@ -172,6 +187,10 @@ func (qb *QueryBuilder) buildNodeExpression(ctx context.Context, node *ParseNode
 		// Funções
 		return qb.buildFunctionExpression(ctx, node, metadata)
 
+	case int(FilterTokenRootReference):
+		// Valor de outra entidade ($root/Products(1)/Price) em subquery escalar
+		return qb.buildRootReference(node, func(value interface{}) string { return "?" })
+
 	default:
 		return "", nil, fmt.Errorf("unsupported token type: %v", node.Token.Type)
 	}
@@ -197,8 +216,7 @@ func (qb *QueryBuilder) buildNodeExpressionNamed(ctx context.Context, node *Pars
 
 	case int(FilterTokenString):
 		// String literal
-		value := strings.Trim(node.Token.Value, "'")
-		placeholder := namedArgs.AddArg(value)
+		placeholder := namedArgs.AddArg(stringLiteralValue(node.Token))
 		return placeholder, nil
 
 	case int(FilterTokenNumber):
@@ -226,6 +244,18 @@ func (qb *QueryBuilder) buildNodeExpressionNamed(ctx context.Context, node *Pars
 		// Null literal
 		return "NULL", nil
 
+	case int(FilterTokenDateTime), int(FilterTokenDate), int(FilterTokenTime):
+		// Literais de data/hora no formato do banco
+		return qb.buildDateTimeLiteralNamed(node, namedArgs)
+
+	case int(FilterTokenDuration):
+		// Duração literal - total de segundos
+		seconds, err := parseDurationLiteral(node.Token.Value)
+		if err != nil {
+			return "", err
+		}
+		return namedArgs.AddArg(seconds), nil
+
 	case int(FilterTokenLogical), int(FilterTokenComparison), int(FilterTokenArithmetic):
 		// Operadores binários
 		return qb.buildBinaryOperatorExpressionNamed(ctx, node, metadata, namedArgs)
@@ -234,11 +264,29 @@ func (qb *QueryBuilder) buildNodeExpressionNamed(ctx context.Context, node *Pars
 		// Funções
 		return qb.buildFunctionExpressionNamed(ctx, node, metadata, namedArgs)
 
+	case int(FilterTokenNavigationExists):
+		// Condição sobre caminho de navegação (ex: Category/Name eq 'X')
+		return qb.buildNavigationExistsNamed(ctx, node, namedArgs)
+
+	case int(FilterTokenRootReference):
+		// Valor de outra entidade ($root/Products(1)/Price) em subquery escalar
+		sql, _, err := qb.buildRootReference(node, namedArgs.AddArg)
+		return sql, err
+
 	default:
 		return "", fmt.Errorf("unsupported token type: %v", node.Token.Type)
 	}
 }
 
+// stringLiteralValue retorna o valor de um literal string: o valor original em
+// SemanticReference (parâmetros vinculados) ou o texto sem as aspas
+func stringLiteralValue(token *Token) string {
+	if value, ok := token.SemanticReference.(string); ok {
+		return value
+	}
+	return strings.Trim(token.Value, "'")
+}
+
 // buildPropertyExpression constrói expressão para propriedade
 func (qb *QueryBuilder) buildPropertyExpression(node *ParseNode, metadata EntityMetadata) (string, []interface{}, error) {
 	propertyName := node.Token.Value
@@ -284,6 +332,13 @@ func (qb *QueryBuilder) buildBinaryOperatorExpression(ctx context.Context, node
 			allArgs = append(allArgs, valArgs...)
 		}
 
+		if qb.isCaseInsensitiveComparison(operator, node.Children, metadata) {
+			propertyExpr = lowerExpression(propertyExpr)
+			for i := range valuesExpr {
+				valuesExpr[i] = lowerExpression(valuesExpr[i])
+			}
+		}
+
 		// Combina valores com vírgula
 		valuesStr := strings.Join(valuesExpr, ", ")
 
@@ -293,6 +348,11 @@ func (qb *QueryBuilder) buildBinaryOperatorExpression(ctx context.Context, node
 		return expression, append(propertyArgs, allArgs...), nil
 	}
 
+	// Soma/subtração de duração a uma data (ex: now() sub duration'P7D')
+	if isDurationArithmetic(node) {
+		return qb.buildDurationArithmetic(ctx, node, metadata)
+	}
+
 	// Operadores binários tradicionais
 	if len(node.Children) != 2 {
 		return "", nil, fmt.Errorf("binary operator %s expects 2 children, got %d", operator, len(node.Children))
@@ -347,6 +407,16 @@ func (qb *QueryBuilder) buildBinaryOperatorExpression(ctx context.Context, node
 		}
 	}
 
+	// Comparações com null usam IS NULL/IS NOT NULL (= NULL nunca é verdadeiro)
+	if expression, ok := nullComparison(operator, node.Children, leftExpr, rightExpr); ok {
+		return expression, append(leftArgs, rightArgs...), nil
+	}
+
+	// Modo case-insensitive: compara os dois lados em minúsculas
+	if qb.isCaseInsensitiveComparison(operator, node.Children, metadata) {
+		leftExpr, rightExpr = lowerExpression(leftExpr), lowerExpression(rightExpr)
+	}
+
 	// Combina argumentos
 	args := append(leftArgs, rightArgs...)
 
@@ -356,6 +426,28 @@ func (qb *QueryBuilder) buildBinaryOperatorExpression(ctx context.Context, node
 	return expression, args, nil
 }
 
+// nullComparison converte eq/ne com o literal null em IS NULL/IS NOT NULL
+func nullComparison(operator string, children []*ParseNode, leftExpr, rightExpr string) (string, bool) {
+	if operator != "eq" && operator != "ne" {
+		return "", false
+	}
+	isNull := func(node *ParseNode) bool {
+		return node != nil && node.Token != nil && node.Token.Type == int(FilterTokenNull)
+	}
+	expr := leftExpr
+	switch {
+	case isNull(children[1]):
+	case isNull(children[0]):
+		expr = rightExpr
+	default:
+		return "", false
+	}
+	if operator == "eq" {
+		return "(" + expr + " IS NULL)", true
+	}
+	return "(" + expr + " IS NOT NULL)", true
+}
+
 // buildBinaryOperatorExpressionNamed constrói expressão para operador binário usando argumentos nomeados
 func (qb *QueryBuilder) buildBinaryOperatorExpressionNamed(ctx context.Context, node *ParseNode, metadata EntityMetadata, namedArgs *NamedArgs) (string, error) {
 	operator := node.Token.Value
@@ -377,7 +469,7 @@ func (qb *QueryBuilder) buildBinaryOperatorExpressionNamed(ctx context.Context,
 		// Constrói lista de valores para IN
 		var valuesExpr []string
 		for i := 1; i < len(node.Children); i++ {
-			valExpr, err := qb.buildNodeExpressionNamed(ctx, node.Children[i], metadata, namedArgs)
+			valExpr, err := qb.buildOperandNamed(ctx, node.Children[i], node.Children[0], metadata, namedArgs)
 			if err != nil {
 				return "", err
 			}
@@ -385,6 +477,13 @@ func (qb *QueryBuilder) buildBinaryOperatorExpressionNamed(ctx context.Context,
 			valuesExpr = append(valuesExpr, valExpr)
 		}
 
+		if qb.isCaseInsensitiveComparison(operator, node.Children, metadata) {
+			propertyExpr = lowerExpression(propertyExpr)
+			for i := range valuesExpr {
+				valuesExpr[i] = lowerExpression(valuesExpr[i])
+			}
+		}
+
 		// Combina valores com vírgula
 		valuesStr := strings.Join(valuesExpr, ", ")
 
@@ -394,6 +493,11 @@ func (qb *QueryBuilder) buildBinaryOperatorExpressionNamed(ctx context.Context,
 		return expression, nil
 	}
 
+	// Soma/subtração de duração a uma data (ex: now() sub duration'P7D')
+	if isDurationArithmetic(node) {
+		return qb.buildDurationArithmeticNamed(ctx, node, metadata, namedArgs)
+	}
+
 	// Operadores binários tradicionais
 	if len(node.Children) != 2 {
 		return "", fmt.Errorf("binary operator %s expects 2 children, got %d", node.Token.Value, len(node.Children))
@@ -416,16 +520,26 @@ func (qb *QueryBuilder) buildBinaryOperatorExpressionNamed(ctx context.Context,
 	}
 
 	// Constrói expressões para os filhos
-	leftExpr, err := qb.buildNodeExpressionNamed(ctx, node.Children[0], metadata, namedArgs)
+	leftExpr, err := qb.buildOperandNamed(ctx, node.Children[0], node.Children[1], metadata, namedArgs)
 	if err != nil {
 		return "", err
 	}
 
-	rightExpr, err := qb.buildNodeExpressionNamed(ctx, node.Children[1], metadata, namedArgs)
+	rightExpr, err := qb.buildOperandNamed(ctx, node.Children[1], node.Children[0], metadata, namedArgs)
 	if err != nil {
 		return "", err
 	}
 
+	// Comparações com null usam IS NULL/IS NOT NULL (= NULL nunca é verdadeiro)
+	if expression, ok := nullComparison(operator, node.Children, leftExpr, rightExpr); ok {
+		return expression, nil
+	}
+
+	// Modo case-insensitive: compara os dois lados em minúsculas
+	if qb.isCaseInsensitiveComparison(operator, node.Children, metadata) {
+		leftExpr, rightExpr = lowerExpression(leftExpr), lowerExpression(rightExpr)
+	}
+
 	// Aplica template
 	expression := fmt.Sprintf(template, leftExpr, rightExpr)
 
@@ -443,7 +557,14 @@ func (qb *QueryBuilder) buildFunctionExpressionNamed(ctx context.Context, node *
 	// Constrói expressões para argumentos
 	argExpressions := make([]string, len(node.Children))
 
+	prepareTemplate, hasPrepare := qb.prepareMap[functionName]
 	for i, child := range node.Children {
+		// Padrões de LIKE (contains, startswith, endswith) são aplicados ao literal
+		if hasPrepare && i > 0 && child.Token != nil && child.Token.Type == int(FilterTokenString) {
+			argExpressions[i] = namedArgs.AddArg(fmt.Sprintf(prepareTemplate, stringLiteralValue(child.Token)))
+			continue
+		}
+
 		expr, err := qb.buildNodeExpressionNamed(ctx, child, metadata, namedArgs)
 		if err != nil {
 			return "", err
@@ -451,6 +572,12 @@ func (qb *QueryBuilder) buildFunctionExpressionNamed(ctx context.Context, node *
 		argExpressions[i] = expr
 	}
 
+	if qb.lowerFunctionOperands() && qb.isCaseInsensitiveComparison(functionName, node.Children, metadata) {
+		for i := range argExpressions {
+			argExpressions[i] = lowerExpression(argExpressions[i])
+		}
+	}
+
 	// Aplica template baseado no número de argumentos
 	var expression string
 	switch len(argExpressions) {
@@ -481,15 +608,28 @@ func (qb *QueryBuilder) buildFunctionExpression(ctx context.Context, node *Parse
 	argExpressions := make([]string, len(node.Children))
 	allArgs := make([]interface{}, 0)
 
+	prepareTemplate, hasPrepare := qb.prepareMap[functionName]
 	for i, child := range node.Children {
 		expr, args, err := qb.buildNodeExpression(ctx, child, metadata)
 		if err != nil {
 			return "", nil, err
 		}
+		// Padrões de LIKE (contains, startswith, endswith) são aplicados ao literal
+		if hasPrepare && i > 0 && child.Token != nil && child.Token.Type == int(FilterTokenString) && len(args) == 1 {
+			if strValue, ok := args[0].(string); ok {
+				args[0] = fmt.Sprintf(prepareTemplate, strValue)
+			}
+		}
 		argExpressions[i] = expr
 		allArgs = append(allArgs, args...)
 	}
 
+	if qb.lowerFunctionOperands() && qb.isCaseInsensitiveComparison(functionName, node.Children, metadata) {
+		for i := range argExpressions {
+			argExpressions[i] = lowerExpression(argExpressions[i])
+		}
+	}
+
 	// Aplica template baseado no número de argumentos
 	var expression string
 	switch len(argExpressions) {
@@ -578,6 +718,18 @@ func (qb *QueryBuilder) convertValueToPropertyType(value interface{}, propertyNa
 	// Encontra a propriedade nos metadados (comparação case-insensitive)
 	for _, prop := range metadata.Properties {
 		if strings.EqualFold(prop.Name, propertyName) {
+			// Edm.Decimal: envia o valor exato, sem passar por float64
+			if isDecimalProperty(prop) {
+				if str, ok := value.(string); ok {
+					value = strings.TrimRight(str, "mMdDfF")
+				}
+				decimal, err := toDecimal(value)
+				if err != nil {
+					return nil, err
+				}
+				return decimal, nil
+			}
+
 			// Converte o valor para o tipo correto
 			switch prop.Type {
 			case "int64":
@@ -1057,17 +1209,17 @@ func (qb *QueryBuilder) BuildSearchSQL(ctx context.Context, searchOption *Search
 	}
 
 	// Obtém propriedades pesquisáveis
-	searchableProps := qb.getSearchableProperties(metadata)
+	searchableProps := SearchableProperties(metadata)
 	if len(searchableProps) == 0 {
-		return "", nil, fmt.Errorf("no searchable properties found in entity %s", metadata.Name)
+		return "", nil, errNoSearchableProperties(metadata.Name)
 	}
 
 	// Constrói SQL para a expressão de busca
-	return qb.buildSearchExpression(ctx, searchOption.Expression, searchableProps)
+	return qb.buildSearchExpression(ctx, searchOption.Expression, searchableProps, metadata.Search)
 }
 
 // buildSearchExpression constrói SQL para uma expressão de busca
-func (qb *QueryBuilder) buildSearchExpression(ctx context.Context, expr *SearchExpression, searchableProps []PropertyMetadata) (string, []interface{}, error) {
+func (qb *QueryBuilder) buildSearchExpression(ctx context.Context, expr *SearchExpression, searchableProps []PropertyMetadata, config *SearchConfig) (string, []interface{}, error) {
 	if expr == nil {
 		return "", nil, nil
 	}
@@ -1080,19 +1232,19 @@ func (qb *QueryBuilder) buildSearchExpression(ctx context.Context, expr *SearchE
 
 	switch expr.Type {
 	case SearchExpressionTerm:
-		return qb.buildSearchTerm(ctx, expr.Value, searchableProps)
+		return qb.buildSearchTerm(ctx, expr.Value, searchableProps, config)
 
 	case SearchExpressionPhrase:
-		return qb.buildSearchPhrase(ctx, expr.Value, searchableProps)
+		return qb.buildSearchPhrase(ctx, expr.Value, searchableProps, config)
 
 	case SearchExpressionAND:
-		return qb.buildSearchBinaryOperator(ctx, expr, "AND", searchableProps)
+		return qb.buildSearchBinaryOperator(ctx, expr, "AND", searchableProps, config)
 
 	case SearchExpressionOR:
-		return qb.buildSearchBinaryOperator(ctx, expr, "OR", searchableProps)
+		return qb.buildSearchBinaryOperator(ctx, expr, "OR", searchableProps, config)
 
 	case SearchExpressionNOT:
-		return qb.buildSearchUnaryOperator(ctx, expr, "NOT", searchableProps)
+		return qb.buildSearchUnaryOperator(ctx, expr, "NOT", searchableProps, config)
 
 	default:
 		return "", nil, fmt.Errorf("unsupported search expression type: %v", expr.Type)
@@ -1100,7 +1252,7 @@ func (qb *QueryBuilder) buildSearchExpression(ctx context.Context, expr *SearchE
 }
 
 // buildSearchTerm constrói SQL para um termo de busca
-func (qb *QueryBuilder) buildSearchTerm(ctx context.Context, term string, searchableProps []PropertyMetadata) (string, []interface{}, error) {
+func (qb *QueryBuilder) buildSearchTerm(ctx context.Context, term string, searchableProps []PropertyMetadata, config *SearchConfig) (string, []interface{}, error) {
 	select {
 	case <-ctx.Done():
 		return "", nil, ctx.Err()
@@ -1114,9 +1266,6 @@ func (qb *QueryBuilder) buildSearchTerm(ctx context.Context, term string, search
 	var conditions []string
 	var params []interface{}
 
-	// Verifica se o termo tem wildcards
-	hasWildcard := strings.Contains(term, "*")
-
 	for _, prop := range searchableProps {
 		columnName := prop.ColumnName
 		if columnName == "" {
@@ -1124,31 +1273,10 @@ func (qb *QueryBuilder) buildSearchTerm(ctx context.Context, term string, search
 		}
 		quotedColumn := qb.QuoteIdentifier(columnName)
 
-		if hasWildcard {
-			// Converte wildcards OData para SQL
-			sqlPattern := strings.ReplaceAll(term, "*", "%")
-
-			// Usa full-text search se disponível, senão LIKE
-			if qb.supportsFullTextSearch() {
-				condition, param := qb.buildFullTextSearchCondition(quotedColumn, sqlPattern)
-				conditions = append(conditions, condition)
-				params = append(params, param)
-			} else {
-				conditions = append(conditions, fmt.Sprintf("%s LIKE ?", quotedColumn))
-				params = append(params, sqlPattern)
-			}
-		} else {
-			// Busca exata ou contém
-			if qb.supportsFullTextSearch() {
-				condition, param := qb.buildFullTextSearchCondition(quotedColumn, term)
-				conditions = append(conditions, condition)
-				params = append(params, param)
-			} else {
-				// Busca por substring
-				conditions = append(conditions, fmt.Sprintf("%s LIKE ?", quotedColumn))
-				params = append(params, "%"+term+"%")
-			}
-		}
+		// Estratégia (full-text, trigram ou LIKE) e normalização vêm da configuração da entidade
+		condition, param := qb.buildSearchCondition(quotedColumn, term, false, config)
+		conditions = append(conditions, condition)
+		params = append(params, param)
 	}
 
 	if len(conditions) == 0 {
@@ -1160,7 +1288,7 @@ func (qb *QueryBuilder) buildSearchTerm(ctx context.Context, term string, search
 }
 
 // buildSearchPhrase constrói SQL para uma frase de busca
-func (qb *QueryBuilder) buildSearchPhrase(ctx context.Context, phrase string, searchableProps []PropertyMetadata) (string, []interface{}, error) {
+func (qb *QueryBuilder) buildSearchPhrase(ctx context.Context, phrase string, searchableProps []PropertyMetadata, config *SearchConfig) (string, []interface{}, error) {
 	if phrase == "" {
 		return "", nil, fmt.Errorf("empty search phrase")
 	}
@@ -1175,16 +1303,9 @@ func (qb *QueryBuilder) buildSearchPhrase(ctx context.Context, phrase string, se
 		}
 		quotedColumn := qb.QuoteIdentifier(columnName)
 
-		// Para frases, usa busca exata
-		if qb.supportsFullTextSearch() {
-			condition, param := qb.buildFullTextPhraseCondition(quotedColumn, phrase)
-			conditions = append(conditions, condition)
-			params = append(params, param)
-		} else {
-			// Busca por substring exata
-			conditions = append(conditions, fmt.Sprintf("%s LIKE ?", quotedColumn))
-			params = append(params, "%"+phrase+"%")
-		}
+		condition, param := qb.buildSearchCondition(quotedColumn, phrase, true, config)
+		conditions = append(conditions, condition)
+		params = append(params, param)
 	}
 
 	if len(conditions) == 0 {
@@ -1196,17 +1317,17 @@ func (qb *QueryBuilder) buildSearchPhrase(ctx context.Context, phrase string, se
 }
 
 // buildSearchBinaryOperator constrói SQL para operadores binários (AND, OR)
-func (qb *QueryBuilder) buildSearchBinaryOperator(ctx context.Context, expr *SearchExpression, operator string, searchableProps []PropertyMetadata) (string, []interface{}, error) {
+func (qb *QueryBuilder) buildSearchBinaryOperator(ctx context.Context, expr *SearchExpression, operator string, searchableProps []PropertyMetadata, config *SearchConfig) (string, []interface{}, error) {
 	if len(expr.Children) != 2 {
 		return "", nil, fmt.Errorf("%s operator requires exactly 2 operands", operator)
 	}
 
-	leftSQL, leftParams, err := qb.buildSearchExpression(ctx, expr.Children[0], searchableProps)
+	leftSQL, leftParams, err := qb.buildSearchExpression(ctx, expr.Children[0], searchableProps, config)
 	if err != nil {
 		return "", nil, err
 	}
 
-	rightSQL, rightParams, err := qb.buildSearchExpression(ctx, expr.Children[1], searchableProps)
+	rightSQL, rightParams, err := qb.buildSearchExpression(ctx, expr.Children[1], searchableProps, config)
 	if err != nil {
 		return "", nil, err
 	}
@@ -1227,12 +1348,12 @@ func (qb *QueryBuilder) buildSearchBinaryOperator(ctx context.Context, expr *Sea
 }
 
 // buildSearchUnaryOperator constrói SQL para operadores unários (NOT)
-func (qb *QueryBuilder) buildSearchUnaryOperator(ctx context.Context, expr *SearchExpression, operator string, searchableProps []PropertyMetadata) (string, []interface{}, error) {
+func (qb *QueryBuilder) buildSearchUnaryOperator(ctx context.Context, expr *SearchExpression, operator string, searchableProps []PropertyMetadata, config *SearchConfig) (string, []interface{}, error) {
 	if len(expr.Children) != 1 {
 		return "", nil, fmt.Errorf("%s operator requires exactly 1 operand", operator)
 	}
 
-	operandSQL, operandParams, err := qb.buildSearchExpression(ctx, expr.Children[0], searchableProps)
+	operandSQL, operandParams, err := qb.buildSearchExpression(ctx, expr.Children[0], searchableProps, config)
 	if err != nil {
 		return "", nil, err
 	}
@@ -1240,41 +1361,6 @@ func (qb *QueryBuilder) buildSearchUnaryOperator(ctx context.Context, expr *Sear
 	return fmt.Sprintf("%s (%s)", operator, operandSQL), operandParams, nil
 }
 
-// getSearchableProperties obtém propriedades pesquisáveis de um metadata
-func (qb *QueryBuilder) getSearchableProperties(metadata EntityMetadata) []PropertyMetadata {
-	var searchableProps []PropertyMetadata
-
-	for _, prop := range metadata.Properties {
-		if qb.isSearchableProperty(prop) {
-			searchableProps = append(searchableProps, prop)
-		}
-	}
-
-	return searchableProps
-}
-
-// isSearchableProperty verifica se uma propriedade é pesquisável
-func (qb *QueryBuilder) isSearchableProperty(prop PropertyMetadata) bool {
-	if prop.IsNavigation {
-		return false
-	}
-
-	// Verifica tipos de dados pesquisáveis
-	propType := strings.ToLower(prop.Type)
-	searchableTypes := []string{
-		"string", "text", "varchar", "nvarchar", "char", "nchar",
-		"clob", "nclob", "longtext", "mediumtext", "tinytext",
-	}
-
-	for _, searchableType := range searchableTypes {
-		if strings.Contains(propType, searchableType) {
-			return true
-		}
-	}
-
-	return false
-}
-
 // supportsFullTextSearch verifica se o banco suporta full-text search
 func (qb *QueryBuilder) supportsFullTextSearch() bool {
 	return qb.dialect.SupportsFullTextSearch()
@@ -1324,6 +1410,11 @@ func (qb *QueryBuilder) CombineSearchWithFilter(ctx context.Context, searchSQL,
 
 // parseNumericValue converte um valor string para o tipo numérico apropriado
 func (qb *QueryBuilder) parseNumericValue(value string) (interface{}, error) {
+	// Literais com sufixo m/M são Edm.Decimal
+	if strings.HasSuffix(value, "m") || strings.HasSuffix(value, "M") {
+		return NewDecimal(value[:len(value)-1])
+	}
+
 	// Remove sufixos específicos de tipo se presentes
 	cleanValue := value
 	suffixes := []string{"d", "D", "f", "F", "m", "M"}
@@ -0,0 +1,51 @@
+package odata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextQueryBuilder(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	service := server.GetEntityService("Products")
+	metadata := service.GetMetadata()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("providers implement the context builder", func(t *testing.T) {
+		for _, provider := range []DatabaseProvider{NewMySQLProvider(), NewPostgreSQLProvider(), NewOracleProvider()} {
+			builder, ok := provider.(ContextQueryBuilder)
+			require.True(t, ok, "%T", provider)
+
+			_, _, err := builder.BuildSelectQueryContext(canceled, metadata, QueryOptions{})
+			assert.ErrorIs(t, err, context.Canceled, "%T", provider)
+		}
+	})
+
+	t.Run("legacy builders keep working", func(t *testing.T) {
+		provider := NewMySQLProvider()
+		query, _, err := provider.BuildSelectQuery(metadata, QueryOptions{OrderBy: "price desc"})
+		require.NoError(t, err)
+		assert.Contains(t, query, "ORDER BY price DESC")
+
+		clause, err := provider.BuildOrderByClauseContext(context.Background(), "name", metadata)
+		require.NoError(t, err)
+		assert.Equal(t, "name ASC", clause)
+	})
+
+	t.Run("canceled request context stops the query", func(t *testing.T) {
+		_, err := service.Get(canceled, map[string]any{"id": int64(1)})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = service.Query(canceled, QueryOptions{})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		entity, err := service.Get(context.Background(), map[string]any{"id": int64(1)})
+		require.NoError(t, err)
+		assert.NotNil(t, entity)
+	})
+}
@@ -0,0 +1,230 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// DRY-RUN DE CONSULTAS ($debug)
+// =======================================================================================
+
+// queryDryRunKey marca o contexto de uma consulta que não deve ser executada
+type queryDryRunKey struct{}
+
+// queryDryRun acumula os comandos SQL gerados em modo dry-run
+type queryDryRun struct {
+	mu         sync.Mutex
+	statements []DryRunStatement
+}
+
+// DryRunStatement é um comando SQL gerado (e não executado) em modo dry-run
+type DryRunStatement struct {
+	Operation string   `json:"operation"`
+	SQL       string   `json:"sql"`
+	Args      []any    `json:"args"`
+	Hints     []string `json:"hints,omitempty"`
+}
+
+// optimizerHintPattern localiza hints de otimizador (/*+ hint */) no SQL
+var optimizerHintPattern = regexp.MustCompile(`/\*\+\s*(.*?)\s*\*/`)
+
+// withQueryDryRun retorna um contexto em que as consultas são geradas, mas não executadas
+func withQueryDryRun(ctx context.Context, dryRun *queryDryRun) context.Context {
+	return context.WithValue(ctx, queryDryRunKey{}, dryRun)
+}
+
+// queryDryRunFrom retorna o dry-run do contexto, se houver
+func queryDryRunFrom(ctx context.Context) *queryDryRun {
+	dryRun, _ := ctx.Value(queryDryRunKey{}).(*queryDryRun)
+	return dryRun
+}
+
+// recordDryRun registra o SQL que seria executado, após os handlers de QueryBuilt (que podem
+// adicionar hints e predicados)
+func (s *BaseEntityService) recordDryRun(ctx context.Context, dryRun *queryDryRun, operation, query string, args []any) error {
+	trace, err := s.beginQuery(ctx, operation, query, args)
+	if err != nil {
+		return err
+	}
+
+	statement := DryRunStatement{Operation: operation, SQL: trace.query, Args: trace.args}
+	if statement.Args == nil {
+		statement.Args = []any{}
+	}
+	for _, match := range optimizerHintPattern.FindAllStringSubmatch(trace.query, -1) {
+		statement.Hints = append(statement.Hints, match[1])
+	}
+
+	dryRun.mu.Lock()
+	dryRun.statements = append(dryRun.statements, statement)
+	dryRun.mu.Unlock()
+	return nil
+}
+
+// handleQueryDebug lida com GET {RoutePrefix}/$debug/{EntitySet}...: aplica todo o pipeline da
+// consulta (filtro padrão, presets, chaves, interceptors e QueryBuilt) e retorna o SQL gerado,
+// os argumentos e as options resultantes, sem executar a consulta
+func (s *Server) handleQueryDebug(c fiber.Ctx) error {
+	path, presetName, isPreset := cutQueryPresetPath("/" + c.Params("*"))
+	isCount := strings.HasSuffix(path, "/$count")
+	hasKey := strings.Contains(path, "(")
+
+	entityName := s.extractEntityName(s.routePrefix() + path)
+	service := s.GetEntityService(entityName)
+	if service == nil {
+		return s.writeODataError(c, http.StatusNotFound,
+			NewODataError("EntityNotFound", fmt.Sprintf("Entity '%s' not found", entityName)), nil)
+	}
+	metadata := service.GetMetadata()
+
+	var presetFilter string
+	if isPreset {
+		preset, found := findQueryPreset(metadata, presetName)
+		if !found || hasKey {
+			return s.writeODataError(c, http.StatusNotFound,
+				NewODataError("NotFound", fmt.Sprintf("Function 'Default.%s' not found", presetName)), nil)
+		}
+		presetFilter = preset.Filter
+	}
+
+	ctx, cancel := s.requestContext(c, entityName)
+	defer cancel()
+	dryRun := &queryDryRun{}
+	ctx = withQueryDryRun(ctx, dryRun)
+
+	options, err := s.parseQueryOptions(c)
+	if err == nil && isCount {
+		err = s.checkHiddenProperties(&options, metadata)
+	} else if err == nil {
+		err = s.applySelectPolicy(&options, metadata)
+	}
+	if err == nil && !hasKey {
+		err = s.applyDefaultFilters(&options, metadata, presetFilter)
+	}
+	if err == nil && hasKey {
+		var keys map[string]interface{}
+		keys, err = s.extractKeys(path, metadata)
+		if err == nil {
+			options, err = s.applyKeyFilter(ctx, service, keys, options)
+		}
+	}
+	if err != nil {
+		return s.writeODataError(c, http.StatusBadRequest, NewODataError("InvalidQuery", err.Error()), err)
+	}
+
+	if isCount {
+		_, err = s.getEntityCount(ctx, service, options, entityName)
+	} else {
+		err = s.runQueryInterceptors(ctx, entityName, QueryOperationSelect, !hasKey, &options, func(options QueryOptions) error {
+			_, err := service.Query(ctx, options)
+			return err
+		})
+	}
+	if err != nil {
+		s.writeEntityError(c, fiber.StatusBadRequest, "InvalidQuery", err)
+		return nil
+	}
+
+	return c.JSON(map[string]interface{}{
+		"entity":     entityName,
+		"dryRun":     true,
+		"query":      c.Queries(),
+		"options":    debugQueryOptions(options),
+		"statements": dryRun.statements,
+	})
+}
+
+// debugQueryOptions descreve as options efetivas da consulta (após filtro padrão, presets e
+// interceptors), incluindo a árvore do $filter
+func debugQueryOptions(options QueryOptions) map[string]interface{} {
+	result := make(map[string]interface{})
+	if options.Filter != nil {
+		result["filter"] = map[string]interface{}{
+			"raw":  options.Filter.RawValue,
+			"tree": debugParseNode(options.Filter.Tree),
+		}
+	}
+	if options.OrderBy != "" {
+		result["orderby"] = options.OrderBy
+	}
+	if options.Select != nil {
+		result["select"] = options.Select.RawValue
+	}
+	if options.Expand != nil {
+		// As entidades relacionadas são buscadas após a consulta principal, a partir dos resultados
+		result["expand"] = options.Expand.RawValue
+	}
+	if options.Top != nil {
+		result["top"] = int(*options.Top)
+	}
+	if options.Skip != nil {
+		result["skip"] = int(*options.Skip)
+	}
+	if options.Count != nil {
+		result["count"] = bool(*options.Count)
+	}
+	if options.Compute != nil {
+		var expressions []string
+		for _, expression := range options.Compute.Expressions {
+			expressions = append(expressions, expression.Expression+" as "+expression.Alias)
+		}
+		result["compute"] = expressions
+	}
+	if options.Search != nil {
+		result["search"] = options.Search.RawQuery
+	}
+	return result
+}
+
+// filterTokenTypeNames nomeia os tipos de token na árvore do $debug
+var filterTokenTypeNames = map[int]string{
+	int(FilterTokenProperty):         "property",
+	int(FilterTokenFunction):         "function",
+	int(FilterTokenArithmetic):       "arithmetic",
+	int(FilterTokenString):           "string",
+	int(FilterTokenNumber):           "number",
+	int(FilterTokenLogical):          "logical",
+	int(FilterTokenComparison):       "comparison",
+	int(FilterTokenBoolean):          "boolean",
+	int(FilterTokenNull):             "null",
+	int(FilterTokenDateTime):         "datetime",
+	int(FilterTokenDate):             "date",
+	int(FilterTokenTime):             "time",
+	int(FilterTokenGuid):             "guid",
+	int(FilterTokenDuration):         "duration",
+	int(FilterTokenGeographyPoint):   "geography",
+	int(FilterTokenGeometryPoint):    "geometry",
+	int(FilterTokenNavigationExists): "navigation",
+	int(FilterTokenRootReference):    "root",
+}
+
+// debugParseNode converte a árvore de parse em mapas serializáveis
+func debugParseNode(node *ParseNode) map[string]interface{} {
+	if node == nil || node.Token == nil {
+		return nil
+	}
+
+	tokenType, ok := filterTokenTypeNames[node.Token.Type]
+	if !ok {
+		tokenType = fmt.Sprintf("%d", node.Token.Type)
+	}
+	result := map[string]interface{}{
+		"type":  tokenType,
+		"value": node.Token.Value,
+	}
+	if len(node.Children) > 0 {
+		children := make([]map[string]interface{}, 0, len(node.Children))
+		for _, child := range node.Children {
+			children = append(children, debugParseNode(child))
+		}
+		result["children"] = children
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package odata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_QueryDebug(t *testing.T) {
+	server := newQueryPresetTestServer(t)
+
+	auth := func(c fiber.Ctx) error {
+		if c.Get("X-Test-User") == "admin" {
+			c.Locals(UserContextKey, &UserIdentity{Username: "root", Admin: true})
+		}
+		return c.Next()
+	}
+	server.SetDiagnostics(&DiagnosticsConfig{Enabled: true, QueryDebug: true, Auth: auth})
+
+	server.OnQueryBuilt("PresetProduct", func(args EventArgs) error {
+		args.(*QueryBuiltArgs).AddHint("INDEX(preset_products idx_active)")
+		return nil
+	})
+	executed := 0
+	server.OnQueryExecutedGlobal(func(args EventArgs) error {
+		executed++
+		return nil
+	})
+
+	type debugResponse struct {
+		Entity     string            `json:"entity"`
+		Options    map[string]any    `json:"options"`
+		Statements []DryRunStatement `json:"statements"`
+	}
+	get := func(t *testing.T, path string, query url.Values, user string) (int, debugResponse, string) {
+		t.Helper()
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if user != "" {
+			req.Header.Set("X-Test-User", user)
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		var result debugResponse
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.Unmarshal(body, &result), string(body))
+		}
+		return resp.StatusCode, result, string(body)
+	}
+
+	t.Run("Coleção com filtro padrão, $count e hints", func(t *testing.T) {
+		status, result, body := get(t, "/odata/$debug/PresetProducts",
+			url.Values{"$filter": {"price gt 5"}, "$top": {"3"}, "$count": {"true"}}, "admin")
+		require.Equal(t, http.StatusOK, status, body)
+
+		assert.Equal(t, "PresetProducts", result.Entity)
+		require.Len(t, result.Statements, 2)
+		assert.Equal(t, QueryOperationSelect, result.Statements[0].Operation)
+		assert.Contains(t, result.Statements[0].SQL, "is_active")
+		assert.Contains(t, result.Statements[0].SQL, "/*+ INDEX(preset_products idx_active) */")
+		assert.Equal(t, []string{"INDEX(preset_products idx_active)"}, result.Statements[0].Hints)
+		assert.NotEmpty(t, result.Statements[0].Args)
+		assert.Equal(t, QueryOperationCount, result.Statements[1].Operation)
+
+		filter := result.Options["filter"].(map[string]any)
+		assert.Equal(t, "(is_active eq true) and (price gt 5)", filter["raw"])
+		tree := filter["tree"].(map[string]any)
+		assert.Equal(t, "logical", tree["type"])
+		assert.Equal(t, "and", tree["value"])
+		assert.Equal(t, float64(3), result.Options["top"])
+	})
+
+	t.Run("Acesso por chave e preset", func(t *testing.T) {
+		status, result, body := get(t, "/odata/$debug/PresetProducts(3)", nil, "admin")
+		require.Equal(t, http.StatusOK, status, body)
+		require.Len(t, result.Statements, 1)
+		assert.Contains(t, result.Statements[0].SQL, "WHERE (id = ")
+		assert.NotContains(t, result.Statements[0].SQL, "is_active =", "acesso por chave não usa o filtro padrão")
+
+		status, result, body = get(t, "/odata/$debug/PresetProducts/Default.Expensive()/$count", nil, "admin")
+		require.Equal(t, http.StatusOK, status, body)
+		require.Len(t, result.Statements, 1)
+		assert.Contains(t, result.Statements[0].SQL, "is_active =")
+		assert.Contains(t, result.Statements[0].SQL, "price >")
+	})
+
+	t.Run("Nenhuma query é executada", func(t *testing.T) {
+		assert.Zero(t, executed)
+	})
+
+	t.Run("Erros", func(t *testing.T) {
+		status, _, _ := get(t, "/odata/$debug/Unknown", nil, "admin")
+		assert.Equal(t, http.StatusNotFound, status)
+
+		status, _, _ = get(t, "/odata/$debug/PresetProducts", url.Values{"$filter": {"price gt"}}, "admin")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _, _ = get(t, "/odata/$debug/PresetProducts", nil, "")
+		assert.Equal(t, http.StatusUnauthorized, status)
+	})
+}
@@ -0,0 +1,280 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// =======================================================================================
+// EVENTOS DE QUERY (SQL GERADO E EXECUTADO)
+// =======================================================================================
+
+const (
+	// Eventos de query SQL
+	EventQueryBuilt    EventType = "QueryBuilt"
+	EventQueryExecuted EventType = "QueryExecuted"
+)
+
+// Operações SQL reportadas nos eventos de query
+const (
+	QueryOperationSelect = "SELECT"
+	QueryOperationCount  = "COUNT"
+	QueryOperationInsert = "INSERT"
+	QueryOperationUpdate = "UPDATE"
+	QueryOperationDelete = "DELETE"
+)
+
+// QueryBuiltArgs argumentos para o evento QueryBuilt (antes da execução, cancelável).
+// Handlers podem alterar SQL/Args diretamente ou usar AddHint/AddPredicate
+type QueryBuiltArgs struct {
+	*BaseEventArgs
+	Operation string
+	SQL       string
+	Args      []any
+	Dialect   string // mysql, postgresql, oracle ou default
+}
+
+// QueryExecutedArgs argumentos para o evento QueryExecuted (após a execução)
+type QueryExecutedArgs struct {
+	*BaseEventArgs
+	Operation string
+	SQL       string
+	Args      []any
+	Duration  time.Duration
+	RowCount  int64 // Linhas retornadas (SELECT/COUNT) ou afetadas (INSERT/UPDATE/DELETE); -1 se desconhecido
+	Error     error
+}
+
+// AddHint insere um hint de otimizador (/*+ hint */) logo após o comando SQL
+func (a *QueryBuiltArgs) AddHint(hint string) {
+	trimmed := strings.TrimLeft(a.SQL, " \t\r\n")
+	for _, keyword := range []string{"SELECT", "INSERT", "UPDATE", "DELETE"} {
+		if len(trimmed) >= len(keyword) && strings.EqualFold(trimmed[:len(keyword)], keyword) {
+			a.SQL = trimmed[:len(keyword)] + " /*+ " + hint + " */" + trimmed[len(keyword):]
+			return
+		}
+	}
+	a.SQL = "/*+ " + hint + " */ " + a.SQL
+}
+
+// AddPredicate adiciona um predicado (combinado com AND) à cláusula WHERE da query.
+// Parâmetros devem usar "?" no predicado; no PostgreSQL são renumerados para $n
+func (a *QueryBuiltArgs) AddPredicate(predicate string, args ...any) error {
+	if a.Operation == QueryOperationInsert {
+		return fmt.Errorf("predicados não são suportados em INSERT")
+	}
+
+	if a.Dialect == "postgresql" {
+		predicate = renumberPlaceholders(predicate, len(a.Args)+1)
+	}
+
+	a.SQL = insertPredicate(a.SQL, predicate)
+	a.Args = append(a.Args, args...)
+	return nil
+}
+
+// insertPredicate insere o predicado na cláusula WHERE de nível superior da query
+func insertPredicate(query, predicate string) string {
+	positions := topLevelClausePositions(query)
+
+	end := len(query)
+	whereAt, hasWhere := positions["WHERE"]
+	for _, clause := range []string{"GROUP BY", "HAVING", "ORDER BY", "LIMIT", "OFFSET", "FETCH", "RETURNING"} {
+		if pos, ok := positions[clause]; ok && pos < end && (!hasWhere || pos > whereAt) {
+			end = pos
+		}
+	}
+
+	head := strings.TrimRight(query[:end], " ")
+	tail := strings.TrimLeft(query[end:], " ")
+	if tail != "" {
+		tail = " " + tail
+	}
+
+	if hasWhere {
+		condition := strings.TrimSpace(query[whereAt+len("WHERE") : end])
+		return strings.TrimRight(query[:whereAt], " ") + " WHERE (" + condition + ") AND (" + predicate + ")" + tail
+	}
+	return head + " WHERE " + predicate + tail
+}
+
+// topLevelClausePositions localiza a primeira ocorrência de cada cláusula fora de
+// parênteses e literais
+func topLevelClausePositions(query string) map[string]int {
+	clauses := []string{"WHERE", "GROUP BY", "HAVING", "ORDER BY", "LIMIT", "OFFSET", "FETCH", "RETURNING"}
+	positions := make(map[string]int)
+	upper := strings.ToUpper(query)
+
+	depth := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		if quote != 0 {
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch ch {
+		case '\'', '"', '`':
+			quote = ch
+			continue
+		case '(':
+			depth++
+			continue
+		case ')':
+			depth--
+			continue
+		}
+
+		if depth != 0 || (i > 0 && !isSQLSpace(query[i-1])) {
+			continue
+		}
+		for _, clause := range clauses {
+			if _, found := positions[clause]; found {
+				continue
+			}
+			if strings.HasPrefix(upper[i:], clause) {
+				next := i + len(clause)
+				if next == len(query) || isSQLSpace(query[next]) {
+					positions[clause] = i
+				}
+			}
+		}
+	}
+	return positions
+}
+
+// isSQLSpace verifica se o caractere separa tokens SQL
+func isSQLSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+// renumberPlaceholders converte "?" em placeholders numerados ($n) a partir de start
+func renumberPlaceholders(predicate string, start int) string {
+	var sb strings.Builder
+	var quote byte
+	for i := 0; i < len(predicate); i++ {
+		ch := predicate[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '?':
+			sb.WriteString(fmt.Sprintf("$%d", start))
+			start++
+			continue
+		}
+		sb.WriteByte(ch)
+	}
+	return sb.String()
+}
+
+// queryTrace acompanha uma query entre os eventos QueryBuilt e QueryExecuted
+type queryTrace struct {
+	service   *BaseEntityService
+	ctx       context.Context
+	operation string
+	query     string
+	args      []any
+	start     time.Time
+}
+
+// hasQueryHandlers verifica se há handlers para o evento, evitando custo quando não há
+func (s *BaseEntityService) hasQueryHandlers(eventType EventType) bool {
+	if s.server == nil || s.server.eventManager == nil {
+		return false
+	}
+	return s.server.eventManager.GetHandlerCount(eventType, s.metadata.Name) > 0
+}
+
+// newQueryEventContext cria o contexto dos eventos de query
+func (s *BaseEntityService) newQueryEventContext(ctx context.Context) *EventContext {
+	return &EventContext{
+		Context:          ctx,
+		EntityName:       s.metadata.Name,
+		Timestamp:        time.Now().Unix(),
+		Extra:            make(map[string]interface{}),
+		DatabaseProvider: s.provider,
+	}
+}
+
+// beginQuery dispara QueryBuilt (permitindo alterar a query) e inicia a medição
+func (s *BaseEntityService) beginQuery(ctx context.Context, operation, query string, args []any) (*queryTrace, error) {
+	trace := &queryTrace{service: s, ctx: ctx, operation: operation, query: query, args: args}
+
+	if s.hasQueryHandlers(EventQueryBuilt) {
+		dialect := "default"
+		if s.provider != nil {
+			dialect = GetDialect(s.provider.GetDriverName()).GetName()
+		}
+
+		builtArgs := &QueryBuiltArgs{
+			BaseEventArgs: &BaseEventArgs{
+				Context:    s.newQueryEventContext(ctx),
+				EventType:  EventQueryBuilt,
+				EntityName: s.metadata.Name,
+				canCancel:  true,
+			},
+			Operation: operation,
+			SQL:       query,
+			Args:      args,
+			Dialect:   dialect,
+		}
+		if err := s.server.eventManager.Emit(builtArgs); err != nil {
+			return nil, err
+		}
+		trace.query = builtArgs.SQL
+		trace.args = builtArgs.Args
+	}
+
+	trace.start = time.Now()
+	return trace, nil
+}
+
+// finish registra a query se for lenta e dispara QueryExecuted com duração, quantidade
+// de linhas e erro
+func (t *queryTrace) finish(rowCount int64, err error) {
+	duration := time.Since(t.start)
+	t.recordIfSlow(duration, rowCount, err)
+
+	s := t.service
+	if !s.hasQueryHandlers(EventQueryExecuted) {
+		return
+	}
+
+	executedArgs := &QueryExecutedArgs{
+		BaseEventArgs: &BaseEventArgs{
+			Context:    s.newQueryEventContext(t.ctx),
+			EventType:  EventQueryExecuted,
+			EntityName: s.metadata.Name,
+			canCancel:  false,
+		},
+		Operation: t.operation,
+		SQL:       t.query,
+		Args:      t.args,
+		Duration:  duration,
+		RowCount:  rowCount,
+		Error:     err,
+	}
+	if emitErr := s.server.eventManager.Emit(executedArgs); emitErr != nil {
+		s.server.logger.Printf("Erro no evento %s: %v", EventQueryExecuted, emitErr)
+	}
+}
+
+// queryOperationOf identifica a operação a partir do comando SQL
+func queryOperationOf(query string) string {
+	trimmed := strings.ToUpper(strings.TrimSpace(query))
+	for _, op := range []string{QueryOperationInsert, QueryOperationUpdate, QueryOperationDelete} {
+		if strings.HasPrefix(trimmed, op) {
+			return op
+		}
+	}
+	return QueryOperationSelect
+}
@@ -0,0 +1,120 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func newQueryEventsTestService(t *testing.T) (*Server, *BaseEntityService) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE products (id INTEGER PRIMARY KEY, name TEXT, active INTEGER)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO products (id, name, active) VALUES (1, 'a', 1), (2, 'b', 0), (3, 'c', 1)")
+	require.NoError(t, err)
+
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+	provider := &MockDatabaseProvider{connection: db}
+	server := &Server{
+		provider:     provider,
+		entities:     make(map[string]EntityService),
+		logger:       logger,
+		config:       DefaultServerConfig(),
+		entityAuth:   make(map[string]EntityAuthConfig),
+		eventManager: NewEntityEventManager(logger),
+	}
+	metadata := EntityMetadata{
+		Name:      "Products",
+		TableName: "products",
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "name", ColumnName: "name", Type: "string"},
+			{Name: "active", ColumnName: "active", Type: "int64"},
+		},
+	}
+	service := NewBaseEntityService(provider, metadata, server)
+	server.entities["Products"] = service
+	return server, service
+}
+
+func TestQueryEvents_BuiltCanAddPredicate(t *testing.T) {
+	server, service := newQueryEventsTestService(t)
+
+	server.OnQueryBuilt("Products", func(args EventArgs) error {
+		built := args.(*QueryBuiltArgs)
+		assert.Equal(t, QueryOperationSelect, built.Operation)
+		return built.AddPredicate("active = ?", 1)
+	})
+
+	var executed *QueryExecutedArgs
+	server.OnQueryExecutedGlobal(func(args EventArgs) error {
+		executed = args.(*QueryExecutedArgs)
+		return nil
+	})
+
+	response, err := service.Query(context.Background(), QueryOptions{})
+	require.NoError(t, err)
+
+	assert.Len(t, response.Value, 2)
+	require.NotNil(t, executed)
+	assert.Equal(t, "SELECT * FROM products WHERE active = ?", executed.SQL)
+	assert.Equal(t, []any{1}, executed.Args)
+	assert.Equal(t, int64(2), executed.RowCount)
+	assert.NoError(t, executed.Error)
+	assert.Greater(t, executed.Duration.Nanoseconds(), int64(0))
+}
+
+func TestQueryEvents_CancelBuiltQuery(t *testing.T) {
+	server, service := newQueryEventsTestService(t)
+
+	server.OnQueryBuiltGlobal(func(args EventArgs) error {
+		args.Cancel("consulta bloqueada")
+		return nil
+	})
+
+	_, err := service.Query(context.Background(), QueryOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "consulta bloqueada")
+}
+
+func TestQueryBuiltArgs_SQLRewriting(t *testing.T) {
+	t.Run("Predicado em query com WHERE, ORDER BY e subquery", func(t *testing.T) {
+		args := &QueryBuiltArgs{
+			SQL:     "SELECT * FROM t WHERE a = $1 OR b IN (SELECT x FROM y WHERE z = 1) ORDER BY a LIMIT 10",
+			Args:    []any{5},
+			Dialect: "postgresql",
+		}
+		require.NoError(t, args.AddPredicate("tenant_id = ?", 7))
+		assert.Equal(t, "SELECT * FROM t WHERE (a = $1 OR b IN (SELECT x FROM y WHERE z = 1)) AND (tenant_id = $2) ORDER BY a LIMIT 10", args.SQL)
+		assert.Equal(t, []any{5, 7}, args.Args)
+	})
+
+	t.Run("Predicado sem WHERE antes da paginação", func(t *testing.T) {
+		args := &QueryBuiltArgs{SQL: "SELECT * FROM t ORDER BY 'WHERE' OFFSET 5 ROWS", Dialect: "oracle"}
+		require.NoError(t, args.AddPredicate("deleted = 0"))
+		assert.Equal(t, "SELECT * FROM t WHERE deleted = 0 ORDER BY 'WHERE' OFFSET 5 ROWS", args.SQL)
+	})
+
+	t.Run("INSERT não aceita predicado", func(t *testing.T) {
+		args := &QueryBuiltArgs{SQL: "INSERT INTO t (a) VALUES (?)", Operation: QueryOperationInsert}
+		assert.Error(t, args.AddPredicate("a = 1"))
+	})
+
+	t.Run("Hint após o comando", func(t *testing.T) {
+		args := &QueryBuiltArgs{SQL: "SELECT a FROM t"}
+		args.AddHint("INDEX(t idx_a)")
+		assert.Equal(t, "SELECT /*+ INDEX(t idx_a) */ a FROM t", args.SQL)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net/http"
 )
 
 // =======================================================================================
@@ -12,14 +13,22 @@ import (
 // =======================================================================================
 
 // executeQuery executa uma query SQL com contexto e retorna as rows
-func (s *BaseEntityService) executeQuery(ctx context.Context, query string, args []any) (*sql.Rows, error) {
+// O chamador deve finalizar o trace com a quantidade de linhas lidas (evento QueryExecuted)
+func (s *BaseEntityService) executeQuery(ctx context.Context, query string, args []any) (*sql.Rows, *queryTrace, error) {
 	// Verifica se a conexão está disponível (GetConnection já faz ping e valida)
 	conn := s.provider.GetConnection()
 
 	if conn == nil {
-		return nil, fmt.Errorf("database connection is nil - make sure the provider is properly connected")
+		return nil, nil, fmt.Errorf("database connection is nil - make sure the provider is properly connected")
 	}
 
+	// Dispara QueryBuilt (handlers podem alterar a query)
+	trace, err := s.beginQuery(ctx, QueryOperationSelect, query, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	query, args = trace.query, trace.args
+
 	// Log da query SQL se DB_LOG_SQL estiver habilitado
 	if s.shouldLogSQL() {
 		log.Printf("🔍 [SQL] QUERY: %s", query)
@@ -28,16 +37,22 @@ func (s *BaseEntityService) executeQuery(ctx context.Context, query string, args
 		}
 	}
 
-	// Executa a query
-	rows, err := conn.QueryContext(ctx, query, args...)
+	// Executa a query (na conexão da sessão da requisição, se houver)
+	querier, err := sessionQuerier(ctx, s.provider, conn)
+	if err != nil {
+		trace.finish(0, err)
+		return nil, nil, err
+	}
+	rows, err := querier.QueryContext(ctx, query, args...)
 	if err != nil {
 		if s.shouldLogSQL() {
 			log.Printf("❌ [SQL] ERRO: %v", err)
 		}
-		return nil, err
+		trace.finish(0, err)
+		return nil, nil, err
 	}
 
-	return rows, nil
+	return rows, trace, nil
 }
 
 // executeExec executa um comando SQL (INSERT, UPDATE, DELETE) com contexto
@@ -48,6 +63,13 @@ func (s *BaseEntityService) executeExec(ctx context.Context, query string, args
 		return nil, fmt.Errorf("database connection is nil - make sure the provider is properly connected")
 	}
 
+	// Dispara QueryBuilt (handlers podem alterar a query)
+	trace, err := s.beginQuery(ctx, queryOperationOf(query), query, args)
+	if err != nil {
+		return nil, err
+	}
+	query, args = trace.query, trace.args
+
 	// Log da query SQL se DB_LOG_SQL estiver habilitado
 	if s.shouldLogSQL() {
 		log.Printf("🔍 [SQL] EXEC: %s", query)
@@ -56,15 +78,33 @@ func (s *BaseEntityService) executeExec(ctx context.Context, query string, args
 		}
 	}
 
-	result, err := conn.ExecContext(ctx, query, args...)
+	querier, err := sessionQuerier(ctx, s.provider, conn)
+	if err != nil {
+		trace.finish(-1, err)
+		return nil, err
+	}
+	result, err := querier.ExecContext(ctx, query, args...)
 	if err != nil && s.shouldLogSQL() {
 		log.Printf("❌ [SQL] ERRO: %v", err)
 	}
+
+	rowsAffected := int64(-1)
+	if err == nil {
+		if n, rowsErr := result.RowsAffected(); rowsErr == nil {
+			rowsAffected = n
+		}
+	}
+	trace.finish(rowsAffected, err)
+
 	return result, err
 }
 
 // GetCount retorna a contagem de registros que atendem às opções de consulta
 func (s *BaseEntityService) GetCount(ctx context.Context, options QueryOptions) (int64, error) {
+	if err := s.applySoftDeleteFilter(ctx, &options); err != nil {
+		return 0, err
+	}
+
 	// Constrói a query de count usando o provider
 	tableName := s.metadata.TableName
 	if tableName == "" {
@@ -77,24 +117,76 @@ func (s *BaseEntityService) GetCount(ctx context.Context, options QueryOptions)
 	var err error
 
 	if options.Filter != nil && options.Filter.Tree != nil {
-		whereClause, args, err = ConvertFilterToSQL(ctx, options.Filter, s.metadata)
+		// Referências $root viram subqueries escalares (já resolvidas quando vem do Query)
+		if options.Filter, err = s.resolveRootFilter(ctx, options.Filter); err != nil {
+			return 0, err
+		}
+		// Navegações N:1 no $filter viram subqueries EXISTS (já resolvidas quando vem do Query)
+		if s.server != nil && hasNavigationPath(options.Filter.Tree) {
+			if err := s.checkNavigationReads(ctx, navigationPathsInTree(options.Filter.Tree), "$filter"); err != nil {
+				return 0, err
+			}
+			tree, err := resolveNavigationFilters(options.Filter.Tree, s.queryMetadata(), s.getRelatedEntityMetadata)
+			if err != nil {
+				return 0, NewODataError("InvalidFilter", err.Error()).
+					WithStatus(http.StatusBadRequest).
+					WithTarget("$filter")
+			}
+			options.Filter = &GoDataFilterQuery{Tree: tree, RawValue: options.Filter.RawValue}
+		}
+		if err := s.prepareEncryptedFilter(ctx, options.Filter); err != nil {
+			return 0, err
+		}
+		whereClause, args, err = ConvertFilterToSQL(ctx, options.Filter, s.queryMetadata())
 		if err != nil {
 			return 0, fmt.Errorf("failed to build where clause for count: %w", err)
 		}
 	}
 
+	// $search restringe a contagem da mesma forma que a listagem
+	if options.Search != nil {
+		options.Search, err = s.prepareSearchOption(ctx, options.Search)
+		if err != nil {
+			return 0, err
+		}
+		qb := NewQueryBuilder("mysql")
+		if builderProvider, ok := s.provider.(interface{ GetQueryBuilder() *QueryBuilder }); ok {
+			qb = builderProvider.GetQueryBuilder()
+		}
+		searchClause, searchArgs, err := qb.BuildSearchSQL(ctx, options.Search, s.queryMetadata())
+		if err != nil {
+			return 0, fmt.Errorf("failed to build search clause for count: %w", err)
+		}
+		whereClause, args, err = qb.CombineSearchWithFilter(ctx, searchClause, whereClause, searchArgs, args)
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	// Constrói a query COUNT com o provider
 	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName)
 	if whereClause != "" {
 		query += " WHERE " + whereClause
 	}
 
+	// Dry-run ($debug): registra o SQL sem executá-lo
+	if dryRun := queryDryRunFrom(ctx); dryRun != nil {
+		return 0, s.recordDryRun(ctx, dryRun, QueryOperationCount, query, args)
+	}
+
 	// Executa a query
 	conn := s.provider.GetConnection()
 	if conn == nil {
 		return 0, fmt.Errorf("database connection is nil")
 	}
 
+	// Dispara QueryBuilt (handlers podem alterar a query)
+	trace, err := s.beginQuery(ctx, QueryOperationCount, query, args)
+	if err != nil {
+		return 0, err
+	}
+	query, args = trace.query, trace.args
+
 	// Log da query SQL se DB_LOG_SQL estiver habilitado
 	if s.shouldLogSQL() {
 		log.Printf("🔍 [SQL] COUNT: %s", query)
@@ -103,15 +195,22 @@ func (s *BaseEntityService) GetCount(ctx context.Context, options QueryOptions)
 		}
 	}
 
-	row := conn.QueryRowContext(ctx, query, args...)
+	querier, err := sessionQuerier(ctx, s.provider, conn)
+	if err != nil {
+		trace.finish(0, err)
+		return 0, err
+	}
+	row := querier.QueryRowContext(ctx, query, args...)
 
 	var count int64
 	if err := row.Scan(&count); err != nil {
 		if s.shouldLogSQL() {
 			log.Printf("❌ [SQL] ERRO: %v", err)
 		}
+		trace.finish(0, err)
 		return 0, fmt.Errorf("failed to execute count query: %w", err)
 	}
+	trace.finish(1, nil)
 
 	return count, nil
 }
@@ -0,0 +1,137 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// INTERCEPTORS DO PIPELINE DE CONSULTA
+// =======================================================================================
+
+// QueryInterceptor intercepta consultas depois do parse das query options e antes da geração
+// do SQL. Pode reescrever as options (injetar filtros, limitar $top), recusar a consulta
+// retornando um erro (use *ODataError para definir o status HTTP) ou observar o resultado
+// após next(). Para interromper a consulta, retorne um erro sem chamar next
+type QueryInterceptor func(ctx *QueryInterceptorContext, options *QueryOptions, next func() error) error
+
+// QueryInterceptorContext identifica a consulta interceptada. Implementa context.Context
+type QueryInterceptorContext struct {
+	context.Context
+	EntityName   string // Nome de registro da entidade
+	Operation    string // QueryOperationSelect ou QueryOperationCount
+	IsCollection bool   // false para acesso por chave (/Entidade(1))
+}
+
+// FiberContext retorna o contexto Fiber da requisição (nil fora de requisições HTTP, ex: $batch)
+func (c *QueryInterceptorContext) FiberContext() fiber.Ctx {
+	if fc, ok := c.Value(FiberContextKey).(fiber.Ctx); ok {
+		return fc
+	}
+	return nil
+}
+
+// User retorna o usuário autenticado da requisição, se houver
+func (c *QueryInterceptorContext) User() *UserIdentity {
+	if fc := c.FiberContext(); fc != nil {
+		return GetCurrentUser(fc)
+	}
+	return nil
+}
+
+// WithQueryInterceptor adiciona interceptors de consulta à entidade, executados depois dos
+// interceptors globais (UseQueryInterceptor)
+func WithQueryInterceptor(interceptors ...QueryInterceptor) EntityOption {
+	return func(config *EntityConfig) {
+		config.QueryInterceptors = append(config.QueryInterceptors, interceptors...)
+	}
+}
+
+// UseQueryInterceptor adiciona interceptors executados em todas as consultas (coleção, acesso
+// por chave e $count, inclusive em $batch), na ordem de registro
+func (s *Server) UseQueryInterceptor(interceptors ...QueryInterceptor) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queryInterceptors = append(s.queryInterceptors, interceptors...)
+	return s
+}
+
+// AddFilter combina a expressão com o $filter atual usando AND
+func (o *QueryOptions) AddFilter(filter string) error {
+	raw := filter
+	if o.Filter != nil && o.Filter.RawValue != "" {
+		raw = "(" + o.Filter.RawValue + ") and (" + filter + ")"
+	}
+	parsed, err := ParseFilterString(context.Background(), raw)
+	if err != nil {
+		return fmt.Errorf("invalid $filter: %w", err)
+	}
+	o.Filter = parsed
+	return nil
+}
+
+// LimitTop limita o $top ao máximo informado, aplicando-o também quando o cliente não envia $top
+func (o *QueryOptions) LimitTop(max int) {
+	if o.Top == nil || int(*o.Top) > max {
+		top := GoDataTopQuery(max)
+		o.Top = &top
+	}
+}
+
+// queryInterceptorsFor retorna os interceptors globais seguidos pelos da entidade
+func (s *Server) queryInterceptorsFor(entityName string) []QueryInterceptor {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entityInterceptors := s.entityQueryInterceptors[entityName]
+	if len(s.queryInterceptors) == 0 && len(entityInterceptors) == 0 {
+		return nil
+	}
+	interceptors := make([]QueryInterceptor, 0, len(s.queryInterceptors)+len(entityInterceptors))
+	interceptors = append(interceptors, s.queryInterceptors...)
+	return append(interceptors, entityInterceptors...)
+}
+
+// runQueryInterceptors executa a cadeia de interceptors e, ao final, a consulta com as options
+// resultantes
+func (s *Server) runQueryInterceptors(ctx context.Context, entityName, operation string, isCollection bool, options *QueryOptions, query func(QueryOptions) error) error {
+	interceptors := s.queryInterceptorsFor(entityName)
+	if len(interceptors) == 0 {
+		return query(*options)
+	}
+
+	interceptorCtx := &QueryInterceptorContext{
+		Context:      ctx,
+		EntityName:   entityName,
+		Operation:    operation,
+		IsCollection: isCollection,
+	}
+
+	index, executed := 0, false
+	var next func() error
+	next = func() error {
+		if index < len(interceptors) {
+			interceptor := interceptors[index]
+			index++
+			return interceptor(interceptorCtx, options, next)
+		}
+
+		// Filtros adicionados pelos interceptors também usam o fuso do servidor
+		if err := s.prepareDateTimeFilter(options); err != nil {
+			return NewODataError("InvalidFilter", err.Error()).
+				WithStatus(http.StatusBadRequest).
+				WithTarget("$filter")
+		}
+		executed = true
+		return query(*options)
+	}
+	if err := next(); err != nil {
+		return err
+	}
+	if !executed {
+		return fmt.Errorf("query interceptor for '%s' returned without calling next", entityName)
+	}
+	return nil
+}
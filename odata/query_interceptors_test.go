@@ -0,0 +1,127 @@
+package odata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryOptions_AddFilterAndLimitTop(t *testing.T) {
+	var options QueryOptions
+	require.NoError(t, options.AddFilter("price gt 10"))
+	assert.Equal(t, "price gt 10", options.Filter.RawValue)
+
+	require.NoError(t, options.AddFilter("name eq 'Mouse'"))
+	assert.Equal(t, "(price gt 10) and (name eq 'Mouse')", options.Filter.RawValue)
+	assert.Error(t, options.AddFilter("price gt"))
+
+	options.LimitTop(50)
+	assert.Equal(t, GoDataTopQuery(50), *options.Top)
+	top := GoDataTopQuery(10)
+	options.Top = &top
+	options.LimitTop(50)
+	assert.Equal(t, GoDataTopQuery(10), *options.Top)
+}
+
+func TestServer_QueryInterceptors(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	var calls []string
+	server.UseQueryInterceptor(func(ctx *QueryInterceptorContext, options *QueryOptions, next func() error) error {
+		calls = append(calls, "global:"+ctx.EntityName+":"+ctx.Operation)
+		options.LimitTop(2)
+		return next()
+	})
+	_, err := server.provider.GetConnection().Exec("CREATE TABLE preset_products (id INTEGER PRIMARY KEY, name TEXT, price REAL, is_active INTEGER)")
+	require.NoError(t, err)
+	_, err = server.provider.GetConnection().Exec("INSERT INTO preset_products VALUES (1, 'Mouse', 10, 1), (2, 'Monitor', 900, 1), (3, 'Cabo', 5, 0)")
+	require.NoError(t, err)
+	require.NoError(t, server.RegisterEntity("PresetProducts", PresetProduct{},
+		WithQueryInterceptor(func(ctx *QueryInterceptorContext, options *QueryOptions, next func() error) error {
+			calls = append(calls, "entity")
+			if ctx.FiberContext() != nil && ctx.FiberContext().Get("X-Deny") != "" {
+				return NewODataError("Forbidden", "Query denied").WithStatus(http.StatusForbidden)
+			}
+			if err := options.AddFilter("is_active eq true"); err != nil {
+				return err
+			}
+			return next()
+		}),
+	))
+
+	get := func(t *testing.T, path string, headers ...string) (int, string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("Interceptor global limita $top", func(t *testing.T) {
+		calls = nil
+		status, body := get(t, "/odata/Products?$orderby=id")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Contains(t, body, `"name":"Teclado"`)
+		assert.NotContains(t, body, `"name":"Monitor"`)
+		assert.Equal(t, []string{"global:Products:SELECT"}, calls)
+
+		_, body = get(t, "/odata/Products?$top=1")
+		assert.NotContains(t, body, `"name":"Teclado"`)
+	})
+
+	t.Run("Interceptor da entidade injeta filtro após o global", func(t *testing.T) {
+		calls = nil
+		query := url.Values{"$filter": {"price lt 100"}}
+		status, body := get(t, "/odata/PresetProducts?"+query.Encode())
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Contains(t, body, `"name":"Mouse"`)
+		assert.NotContains(t, body, `"name":"Cabo"`)
+		assert.Equal(t, []string{"global:PresetProducts:SELECT", "entity"}, calls)
+
+		calls = nil
+		_, body = get(t, "/odata/PresetProducts/$count")
+		assert.Equal(t, "2", body)
+		assert.Equal(t, []string{"global:PresetProducts:COUNT", "entity"}, calls)
+
+		status, _ = get(t, "/odata/PresetProducts(3)")
+		assert.Equal(t, http.StatusNotFound, status, "acesso por chave também passa pelos interceptors")
+	})
+
+	t.Run("Erro do interceptor define o status", func(t *testing.T) {
+		status, body := get(t, "/odata/PresetProducts", "X-Deny", "1")
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.Contains(t, body, "Query denied")
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		calls = nil
+		resp, err := NewBatchProcessor(server).executeOperation(context.Background(), &BatchHTTPOperation{Method: "GET", URL: "PresetProducts/$count"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, "2", string(resp.Body))
+		assert.Equal(t, []string{"global:PresetProducts:COUNT", "entity"}, calls)
+	})
+
+	t.Run("Interceptor sem next", func(t *testing.T) {
+		server.UseQueryInterceptor(func(ctx *QueryInterceptorContext, options *QueryOptions, next func() error) error {
+			return nil
+		})
+		status, body := get(t, "/odata/Products")
+		assert.Equal(t, http.StatusInternalServerError, status)
+		assert.Contains(t, body, "without calling next")
+	})
+}
@@ -0,0 +1,174 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// FILTRO PADRÃO E PRESETS DE CONSULTA POR ENTIDADE
+// =======================================================================================
+
+// QueryPreset é uma consulta nomeada da entidade, exposta como função vinculada à coleção
+// (ex: GET /Products/Default.Active()). O filtro é combinado com o $filter do cliente
+type QueryPreset struct {
+	Name   string
+	Filter string
+}
+
+// WithDefaultFilter define um filtro aplicado a todas as consultas da coleção (incluindo
+// $count e presets), combinado com AND ao $filter do cliente. Ex: "is_active eq true"
+func WithDefaultFilter(filter string) EntityOption {
+	return func(config *EntityConfig) {
+		config.DefaultFilter = filter
+	}
+}
+
+// WithQueryPreset adiciona uma consulta nomeada à entidade, acessível em
+// /Entidade/Default.Nome(). O filtro do preset é combinado com o filtro padrão e com o
+// $filter do cliente
+func WithQueryPreset(name, filter string) EntityOption {
+	return func(config *EntityConfig) {
+		config.QueryPresets = append(config.QueryPresets, QueryPreset{Name: name, Filter: filter})
+	}
+}
+
+// validateQueryFilters valida a sintaxe do filtro padrão e dos presets no registro da
+// entidade, para que erros de configuração não apareçam apenas nas requisições
+func validateQueryFilters(metadata EntityMetadata) error {
+	if _, err := ParseFilterString(context.Background(), metadata.DefaultFilter); err != nil {
+		return fmt.Errorf("filtro padrão inválido: %w", err)
+	}
+
+	names := make(map[string]bool, len(metadata.QueryPresets))
+	for _, preset := range metadata.QueryPresets {
+		if !isValidPresetName(preset.Name) {
+			return fmt.Errorf("nome de preset inválido: '%s'", preset.Name)
+		}
+		if names[strings.ToLower(preset.Name)] {
+			return fmt.Errorf("preset '%s' duplicado", preset.Name)
+		}
+		names[strings.ToLower(preset.Name)] = true
+
+		if strings.TrimSpace(preset.Filter) == "" {
+			return fmt.Errorf("preset '%s' sem filtro", preset.Name)
+		}
+		if _, err := ParseFilterString(context.Background(), preset.Filter); err != nil {
+			return fmt.Errorf("filtro do preset '%s' inválido: %w", preset.Name, err)
+		}
+	}
+	return nil
+}
+
+// isValidPresetName verifica se o nome do preset é um identificador OData simples
+func isValidPresetName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// findQueryPreset retorna o preset da entidade pelo nome
+func findQueryPreset(metadata EntityMetadata, name string) (QueryPreset, bool) {
+	for _, preset := range metadata.QueryPresets {
+		if preset.Name == name {
+			return preset, true
+		}
+	}
+	return QueryPreset{}, false
+}
+
+// applyDefaultFilters combina, com AND, o filtro padrão da entidade, o filtro do preset e o
+// $filter do cliente. O cliente só consegue restringir o resultado, nunca removê-los
+func (s *Server) applyDefaultFilters(options *QueryOptions, metadata EntityMetadata, presetFilter string) error {
+	var filters []string
+	if metadata.DefaultFilter != "" {
+		filters = append(filters, metadata.DefaultFilter)
+	}
+	if presetFilter != "" {
+		filters = append(filters, presetFilter)
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+	if options.Filter != nil && options.Filter.RawValue != "" {
+		filters = append(filters, options.Filter.RawValue)
+	}
+
+	raw := filters[0]
+	if len(filters) > 1 {
+		raw = "(" + strings.Join(filters, ") and (") + ")"
+	}
+
+	filter, err := ParseFilterString(context.Background(), raw)
+	if err != nil {
+		return fmt.Errorf("invalid $filter: %w", err)
+	}
+	if err := resolveDateTimeLiterals(filter.Tree, s.timeZone()); err != nil {
+		return fmt.Errorf("invalid $filter: %w", err)
+	}
+	options.Filter = filter
+	return nil
+}
+
+// queryPresetPath retorna o caminho da função do preset (sem o prefixo e a entidade)
+func queryPresetPath(name string) string {
+	return "/Default." + name + "()"
+}
+
+// handleQueryPreset cria o handler de GET /Entidade/Default.Preset() e do respectivo $count
+func (s *Server) handleQueryPreset(entityName string, preset QueryPreset, count bool) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		service := s.GetEntityService(entityName)
+		if service == nil {
+			s.writeError(c, fiber.StatusNotFound, "EntityNotFound", fmt.Sprintf("Entity '%s' not found", entityName))
+			return nil
+		}
+		if count {
+			return s.writeCollectionCount(c, service, entityName, preset.Filter)
+		}
+		return s.writeCollection(c, service, entityName, preset.Filter)
+	}
+}
+
+// buildQueryPresetFunctions descreve os presets das entidades publicadas no prefixo como
+// funções vinculadas no $metadata
+func (s *Server) buildQueryPresetFunctions(prefix string) []FunctionMetadata {
+	var functions []FunctionMetadata
+	for setName, name := range s.entitySetNames(prefix) {
+		for _, preset := range s.GetEntityService(name).GetMetadata().QueryPresets {
+			functions = append(functions, FunctionMetadata{
+				Name:             preset.Name,
+				Namespace:        "Default",
+				IsBound:          true,
+				IsComposable:     true,
+				BindingParameter: "Collection(Default." + setName + ")",
+				ReturnType:       "Collection(Default." + setName + ")",
+			})
+		}
+	}
+	return functions
+}
+
+// cutQueryPresetPath separa a chamada de preset do caminho (ex: /odata/Products/Default.Active()/$count
+// → /odata/Products/$count e "Active"). Retorna false quando o caminho não chama um preset
+func cutQueryPresetPath(path string) (string, string, bool) {
+	before, after, found := strings.Cut(path, "/Default.")
+	if !found || strings.Contains(before, "(") {
+		return path, "", false
+	}
+	name, rest, found := strings.Cut(after, "()")
+	if !found || !isValidPresetName(name) {
+		return path, "", false
+	}
+	return before + rest, name, true
+}
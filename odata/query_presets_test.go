@@ -0,0 +1,195 @@
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type PresetProduct struct {
+	TableName string  `table:"preset_products"`
+	ID        int64   `json:"id" column:"id" primaryKey:"idGenerator:none"`
+	Name      string  `json:"name" column:"name"`
+	Price     float64 `json:"price" column:"price"`
+	IsActive  bool    `json:"is_active" column:"is_active"`
+}
+
+func newQueryPresetTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	for _, stmt := range []string{
+		"CREATE TABLE preset_products (id INTEGER PRIMARY KEY, name TEXT, price REAL, is_active INTEGER)",
+		"INSERT INTO preset_products VALUES (1, 'Mouse', 10, 1), (2, 'Monitor', 900, 1), (3, 'Servidor', 5000, 0), (4, 'Cabo', 5, 0)",
+	} {
+		_, err := server.provider.GetConnection().Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, server.RegisterEntity("PresetProducts", PresetProduct{},
+		WithDefaultFilter("is_active eq true"),
+		WithQueryPreset("Expensive", "price gt 100"),
+		WithQueryPreset("Cheap", "price lt 20"),
+	))
+	return server
+}
+
+func TestServer_DefaultFilterAndQueryPresets(t *testing.T) {
+	server := newQueryPresetTestServer(t)
+
+	get := func(t *testing.T, path string, query url.Values) (int, string) {
+		t.Helper()
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+	ids := func(t *testing.T, body string) []float64 {
+		t.Helper()
+		var response struct {
+			Value []map[string]any `json:"value"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &response), body)
+		result := []float64{}
+		for _, entity := range response.Value {
+			result = append(result, entity["id"].(float64))
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		query    url.Values
+		expected []float64
+	}{
+		{"Filtro padrão", "/odata/PresetProducts", url.Values{"$orderby": {"id"}}, []float64{1, 2}},
+		{"Filtro padrão com $filter do cliente", "/odata/PresetProducts", url.Values{"$filter": {"name eq 'Monitor' or name eq 'Servidor'"}}, []float64{2}},
+		{"Preset", "/odata/PresetProducts/Default.Expensive()", nil, []float64{2}},
+		{"Preset com $filter do cliente", "/odata/PresetProducts/Default.Cheap()", url.Values{"$filter": {"name ne 'Mouse'"}}, []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, tt.path, tt.query)
+			require.Equal(t, http.StatusOK, status, body)
+			assert.Equal(t, tt.expected, ids(t, body))
+		})
+	}
+
+	t.Run("$count", func(t *testing.T) {
+		_, body := get(t, "/odata/PresetProducts/$count", nil)
+		assert.Equal(t, "2", body)
+
+		_, body = get(t, "/odata/PresetProducts/Default.Expensive()/$count", nil)
+		assert.Equal(t, "1", body)
+
+		_, body = get(t, "/odata/PresetProducts", url.Values{"$count": {"true"}, "$top": {"1"}})
+		assert.Contains(t, body, `"@odata.count":2`)
+	})
+
+	t.Run("Acesso por chave não usa o filtro padrão", func(t *testing.T) {
+		status, body := get(t, "/odata/PresetProducts(3)", nil)
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Contains(t, body, `"name":"Servidor"`)
+	})
+
+	t.Run("Preset inexistente", func(t *testing.T) {
+		status, _ := get(t, "/odata/PresetProducts/Default.Unknown()", nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("$metadata", func(t *testing.T) {
+		metadata := server.buildMetadataJSON()
+		require.Len(t, metadata.Functions, 2)
+		names := []string{metadata.Functions[0].Name, metadata.Functions[1].Name}
+		assert.ElementsMatch(t, []string{"Expensive", "Cheap"}, names)
+		assert.True(t, metadata.Functions[0].IsBound)
+		assert.Equal(t, "Collection(Default.PresetProducts)", metadata.Functions[0].BindingParameter)
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		processor := NewBatchProcessor(server)
+		for path, expected := range map[string]string{
+			"PresetProducts/$count":                     "2",
+			"PresetProducts/Default.Expensive()/$count": "1",
+		} {
+			resp, err := processor.executeOperation(context.Background(), &BatchHTTPOperation{Method: "GET", URL: path}, map[string]interface{}{})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode, string(resp.Body))
+			assert.Equal(t, expected, string(resp.Body), path)
+		}
+
+		resp, err := processor.executeOperation(context.Background(), &BatchHTTPOperation{Method: "GET", URL: "PresetProducts/Default.Unknown()"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestServer_DefaultFilterNavigationPaths(t *testing.T) {
+	server := newNavigationTestServer(t)
+	updateNavigationCategories(server, func(metadata *EntityMetadata) {
+		metadata.DefaultFilter = "id ne 2"
+	})
+
+	for option, value := range map[string]string{
+		"$filter":  "Category/name eq 'Displays'",
+		"$orderby": "Category/name desc",
+	} {
+		status, body := getComputeTestProducts(t, server, option+"="+url.QueryEscape(value))
+		if assert.Equal(t, http.StatusBadRequest, status, option) {
+			assert.Contains(t, body["error"].(map[string]any)["message"], "default filter", option)
+		}
+	}
+}
+
+func TestServer_RegisterEntity_InvalidQueryFilters(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+
+	for _, opt := range []EntityOption{
+		WithDefaultFilter("is_active eq"),
+		WithQueryPreset("Expensive", "price gt"),
+		WithQueryPreset("Default.Expensive", "price gt 100"),
+		WithQueryPreset("Cheap", ""),
+	} {
+		assert.Error(t, server.RegisterEntity("PresetProducts", PresetProduct{}, opt))
+	}
+
+	err := server.RegisterEntity("PresetProducts", PresetProduct{},
+		WithQueryPreset("Expensive", "price gt 100"),
+		WithQueryPreset("expensive", "price gt 200"))
+	assert.ErrorContains(t, err, "duplicado")
+}
+
+func TestCutQueryPresetPath(t *testing.T) {
+	tests := []struct {
+		path, expectedPath, expectedName string
+		found                            bool
+	}{
+		{"/odata/Products/Default.Active()", "/odata/Products", "Active", true},
+		{"Products/Default.Active()/$count", "Products/$count", "Active", true},
+		{"/odata/Products(1)/Default.Active()", "/odata/Products(1)/Default.Active()", "", false},
+		{"/odata/Products", "/odata/Products", "", false},
+		{"/odata/Products/Default.Active", "/odata/Products/Default.Active", "", false},
+	}
+	for _, tt := range tests {
+		path, name, found := cutQueryPresetPath(tt.path)
+		assert.Equal(t, tt.expectedPath, path, tt.path)
+		assert.Equal(t, tt.expectedName, name, tt.path)
+		assert.Equal(t, tt.found, found, tt.path)
+	}
+}
@@ -0,0 +1,107 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// TIMEOUT E CANCELAMENTO DE QUERIES POR REQUISIÇÃO
+// =======================================================================================
+
+// StatusClientClosedRequest é o status registrado quando o cliente desconecta antes da
+// resposta (convenção do nginx; a resposta não chega ao cliente)
+const StatusClientClosedRequest = 499
+
+// disconnectPollInterval é o intervalo de verificação de desconexão do cliente
+const disconnectPollInterval = 100 * time.Millisecond
+
+// ErrClientDisconnected é a causa do cancelamento quando o cliente fecha a conexão
+var ErrClientDisconnected = errors.New("cliente desconectou antes da resposta")
+
+// queryTimeout retorna o timeout de query da entidade (WithQueryTimeout) ou o do servidor
+func (s *Server) queryTimeout(entityName string) time.Duration {
+	s.mu.RLock()
+	timeout, ok := s.entityQueryTimeouts[entityName]
+	s.mu.RUnlock()
+	if ok {
+		return timeout
+	}
+	if s.config == nil {
+		return 0
+	}
+	return s.config.QueryTimeout
+}
+
+// requestContext cria o contexto das chamadas ao provider de uma requisição: herda o
+// contexto do Fiber, aplica o QueryTimeout e é cancelado se o cliente desconectar. Com
+// SessionConfig no provider, as queries compartilham uma conexão preparada (dbSession).
+// O cancel deve ser chamado ao final do handler
+func (s *Server) requestContext(c fiber.Ctx, entityName string) (context.Context, context.CancelFunc) {
+	sessionCtx, releaseSession := withDBSession(context.WithValue(c.Context(), FiberContextKey, c))
+	base, cancelCause := context.WithCancelCause(sessionCtx)
+
+	ctx, cancelTimeout := base, context.CancelFunc(func() {})
+	if timeout := s.queryTimeout(entityName); timeout > 0 {
+		ctx, cancelTimeout = context.WithTimeout(base, timeout)
+	}
+
+	watchClientDisconnect(ctx, c.RequestCtx().Conn(), cancelCause)
+
+	return ctx, func() {
+		cancelTimeout()
+		cancelCause(nil)
+		releaseSession()
+	}
+}
+
+// watchClientDisconnect cancela o contexto quando o cliente fecha a conexão, abortando
+// a query em andamento. Encerra junto com o contexto
+func watchClientDisconnect(ctx context.Context, conn net.Conn, cancel context.CancelCauseFunc) {
+	if conn == nil || !canDetectDisconnect(conn) {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(disconnectPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if connClosed(conn) {
+					cancel(ErrClientDisconnected)
+					return
+				}
+			}
+		}
+	}()
+}
+
+// queryContextError converte erros causados pelo timeout ou cancelamento do contexto em
+// *ODataError (504 QueryTimeout ou 499 ClientClosedRequest). Demais erros são mantidos
+func (s *Server) queryContextError(ctx context.Context, entityName string, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+
+	if errors.Is(context.Cause(ctx), ErrClientDisconnected) {
+		return NewODataError("ClientClosedRequest", "Client closed the connection before the response").
+			WithStatus(StatusClientClosedRequest)
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return NewODataError("QueryTimeout",
+			fmt.Sprintf("The query exceeded the timeout of %s", s.queryTimeout(entityName))).
+			WithStatus(http.StatusGatewayTimeout).
+			WithTarget(entityName)
+	}
+	return err
+}
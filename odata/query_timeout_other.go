@@ -0,0 +1,15 @@
+//go:build !unix
+
+package odata
+
+import "net"
+
+// canDetectDisconnect não é suportado fora de sistemas Unix: apenas o timeout se aplica
+func canDetectDisconnect(conn net.Conn) bool {
+	return false
+}
+
+// connClosed não é suportado fora de sistemas Unix
+func connClosed(conn net.Conn) bool {
+	return false
+}
@@ -0,0 +1,141 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowQuery nunca retorna linhas e só termina quando interrompida pelo contexto
+const slowQuery = "WITH RECURSIVE r(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM r) " +
+	"SELECT x AS id, '' AS name, 0 AS price FROM r WHERE x < 0"
+
+func TestServer_QueryTimeout(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	_, err := server.provider.GetConnection().Exec("CREATE TRIGGER slow_insert BEFORE INSERT ON products BEGIN " +
+		"SELECT count(*) FROM (" + slowQuery + "); END")
+	require.NoError(t, err)
+
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+	server.SetQueryTimeout(50 * time.Millisecond)
+
+	server.OnQueryBuilt("Products", func(args EventArgs) error {
+		built := args.(*QueryBuiltArgs)
+		if built.Operation == QueryOperationSelect {
+			built.SQL = slowQuery
+			built.Args = nil
+		}
+		return nil
+	})
+
+	var errorStatus int
+	server.OnEntityError("Products", func(args EventArgs) error {
+		errorStatus = args.(*EntityErrorArgs).StatusCode
+		return nil
+	})
+
+	// Executado antes das leituras: o SQLite em memória descarta a conexão interrompida
+	t.Run("Erro de timeout em escrita é reportado ao evento com 504", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/odata/Products", strings.NewReader(`{"id": 10, "name": "Cabo", "price": 5}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := server.router.Test(req, fiber.TestConfig{Timeout: 10 * time.Second})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode, string(body))
+		assert.Equal(t, http.StatusGatewayTimeout, errorStatus)
+	})
+
+	t.Run("Query lenta retorna 504", func(t *testing.T) {
+		start := time.Now()
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Products", nil), fiber.TestConfig{Timeout: 10 * time.Second})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+		assert.Less(t, time.Since(start), 5*time.Second)
+
+		odataErr := decodeODataError(t, resp)
+		assert.Equal(t, "QueryTimeout", odataErr["code"])
+		assert.Equal(t, "Products", odataErr["target"])
+	})
+
+	t.Run("Timeout da entidade sobrescreve o do servidor", func(t *testing.T) {
+		server.entityQueryTimeouts = map[string]time.Duration{"Products": 20 * time.Millisecond}
+		defer func() { server.entityQueryTimeouts = nil }()
+
+		assert.Equal(t, 20*time.Millisecond, server.queryTimeout("Products"))
+		assert.Equal(t, 50*time.Millisecond, server.queryTimeout("Orders"))
+
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Products(1)", nil), fiber.TestConfig{Timeout: 10 * time.Second})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	})
+
+}
+
+func TestServer_queryContextError(t *testing.T) {
+	server := &Server{config: DefaultServerConfig()}
+	original := errors.New("driver: interrupted")
+
+	t.Run("Contexto ativo mantém o erro", func(t *testing.T) {
+		assert.Same(t, original, server.queryContextError(context.Background(), "Products", original))
+	})
+
+	t.Run("Desconexão do cliente", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(ErrClientDisconnected)
+
+		var odataErr *ODataError
+		require.ErrorAs(t, server.queryContextError(ctx, "Products", original), &odataErr)
+		assert.Equal(t, "ClientClosedRequest", odataErr.Code)
+		assert.Equal(t, StatusClientClosedRequest, odataErr.Status)
+	})
+}
+
+func TestConnClosed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("detecção de desconexão é suportada apenas em Unix")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.True(t, canDetectDisconnect(conn))
+	assert.False(t, connClosed(conn))
+
+	// Dados pendentes não são consumidos pela verificação
+	_, err = client.Write([]byte("x"))
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, connClosed(conn))
+
+	buf := make([]byte, 1)
+	_, err = conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "x", string(buf))
+
+	client.Close()
+	assert.Eventually(t, func() bool { return connClosed(conn) }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,49 @@
+//go:build unix
+
+package odata
+
+import (
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// rawConn retorna a conexão TCP subjacente (removendo TLS) com acesso ao descritor
+func rawConn(conn net.Conn) (syscall.RawConn, bool) {
+	if tlsConn, ok := conn.(interface{ NetConn() net.Conn }); ok {
+		conn = tlsConn.NetConn()
+	}
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return nil, false
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return nil, false
+	}
+	return raw, true
+}
+
+// canDetectDisconnect indica se a conexão permite verificar desconexão do cliente
+func canDetectDisconnect(conn net.Conn) bool {
+	_, ok := rawConn(conn)
+	return ok
+}
+
+// connClosed verifica, sem consumir dados do socket (MSG_PEEK), se o cliente fechou a conexão
+func connClosed(conn net.Conn) bool {
+	raw, ok := rawConn(conn)
+	if !ok {
+		return false
+	}
+
+	closed := false
+	err := raw.Read(func(fd uintptr) bool {
+		var buf [1]byte
+		n, _, err := unix.Recvfrom(int(fd), buf[:], unix.MSG_PEEK|unix.MSG_DONTWAIT)
+		closed = (n == 0 && err == nil) || err == unix.ECONNRESET
+		return true // não aguarda dados: apenas verifica o estado atual
+	})
+	return closed || err != nil
+}
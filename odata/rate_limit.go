@@ -244,17 +244,12 @@ func (s *Server) RateLimitMiddleware() fiber.Handler {
 
 		// Se não é permitido, retorna erro 429
 		if !allowed {
-			errorResponse := ODataResponse{
-				Error: &ODataError{
-					Code:    "RateLimitExceeded",
-					Message: fmt.Sprintf("Rate limit exceeded. Try again in %d seconds.", info.RetryAfter),
-					Target:  "rate_limit",
-				},
-			}
-
-			c.Set("Content-Type", "application/json")
-			c.Status(http.StatusTooManyRequests)
-			return c.JSON(errorResponse)
+			odataErr := NewODataErrorWithTarget(
+				"RateLimitExceeded",
+				fmt.Sprintf("Rate limit exceeded. Try again in %d seconds.", info.RetryAfter),
+				"rate_limit",
+			)
+			return s.writeODataError(c, http.StatusTooManyRequests, odataErr, nil)
 		}
 
 		// Continua para o próximo middleware/handler
@@ -0,0 +1,128 @@
+package odata
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// RECUPERAÇÃO DE PANICS
+// =======================================================================================
+
+// RecoverConfig configura a recuperação de panics em handlers, middlewares e eventos
+type RecoverConfig struct {
+	Enabled       bool                                               // Converte panics em erros 500 OData (se false, o panic se propaga)
+	LogStackTrace bool                                               // Loga o stack trace junto com o ID da requisição
+	OnPanic       func(c fiber.Ctx, value interface{}, stack []byte) // Callback opcional (métricas externas, alertas)
+}
+
+// DefaultRecoverConfig retorna configuração padrão de recuperação de panics
+func DefaultRecoverConfig() *RecoverConfig {
+	return &RecoverConfig{
+		Enabled:       true,
+		LogStackTrace: true,
+	}
+}
+
+// PanicError representa um panic recuperado, preservando o valor e o stack trace
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error implementa a interface error
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// recoverConfig retorna a configuração de recuperação em uso
+func (s *Server) recoverConfig() *RecoverConfig {
+	if s.config == nil || s.config.RecoverConfig == nil {
+		return DefaultRecoverConfig()
+	}
+	return s.config.RecoverConfig
+}
+
+// RecoverMiddleware converte panics ocorridos nos handlers seguintes em erros 500 no
+// formato OData. A configuração é lida a cada requisição, permitindo alterá-la após a
+// criação do servidor
+func (s *Server) RecoverMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) (err error) {
+		config := s.recoverConfig()
+		if !config.Enabled {
+			return c.Next()
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = s.handlePanic(c, config, r, debug.Stack())
+			}
+		}()
+
+		return c.Next()
+	}
+}
+
+// handlePanic registra o panic (log, métrica e callback) e escreve a resposta de erro
+func (s *Server) handlePanic(c fiber.Ctx, config *RecoverConfig, value interface{}, stack []byte) error {
+	s.panicCount.Add(1)
+
+	requestID := c.Get("X-Request-ID")
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+	c.Set("X-Request-ID", requestID)
+
+	if s.logger != nil {
+		if config.LogStackTrace {
+			s.logger.Printf("🔥 PANIC em %s %s [request_id=%s]: %v\n%s", c.Method(), c.Path(), requestID, value, stack)
+		} else {
+			s.logger.Printf("🔥 PANIC em %s %s [request_id=%s]: %v", c.Method(), c.Path(), requestID, value)
+		}
+	}
+
+	if config.OnPanic != nil {
+		func() {
+			defer func() {
+				if r := recover(); r != nil && s.logger != nil {
+					s.logger.Printf("❌ PANIC no callback OnPanic [request_id=%s]: %v", requestID, r)
+				}
+			}()
+			config.OnPanic(c, value, stack)
+		}()
+	}
+
+	odataErr := NewODataError(ODataErrorCode(http.StatusInternalServerError), "An unexpected error occurred").
+		WithDetail("RequestId", requestID, "")
+	if s.debugErrors() {
+		odataErr.InnerError = &ODataInnerError{
+			Type:       fmt.Sprintf("%T", value),
+			Message:    fmt.Sprint(value),
+			StackTrace: string(stack),
+		}
+	}
+	return s.writeODataError(c, http.StatusInternalServerError, odataErr, &PanicError{Value: value, Stack: stack})
+}
+
+// GetPanicCount retorna a quantidade de panics recuperados em handlers e eventos
+func (s *Server) GetPanicCount() int64 {
+	count := s.panicCount.Load()
+	if s.eventManager != nil {
+		count += s.eventManager.PanicCount()
+	}
+	return count
+}
+
+// newRequestID gera um identificador para correlacionar o log do panic com a resposta
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,119 @@
+package odata
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_RecoverMiddleware(t *testing.T) {
+	newApp := func(server *Server) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
+		app.Use(server.RecoverMiddleware())
+		app.Get("/panic", func(c fiber.Ctx) error {
+			panic("mapa nil")
+		})
+		return app
+	}
+
+	t.Run("Converte panic em erro 500 OData", func(t *testing.T) {
+		var logs bytes.Buffer
+		server := &Server{config: DefaultServerConfig(), logger: log.New(&logs, "", 0)}
+
+		var captured interface{}
+		server.config.RecoverConfig.OnPanic = func(c fiber.Ctx, value interface{}, stack []byte) {
+			captured = value
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+		req.Header.Set("X-Request-ID", "req-42")
+		resp, err := newApp(server).Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "req-42", resp.Header.Get("X-Request-ID"))
+
+		odataErr := decodeODataError(t, resp)
+		assert.Equal(t, "InternalServerError", odataErr["code"])
+		assert.NotContains(t, odataErr["message"], "mapa nil")
+		assert.NotContains(t, odataErr, "innererror")
+
+		assert.Equal(t, "mapa nil", captured)
+		assert.Equal(t, int64(1), server.GetPanicCount())
+		assert.Contains(t, logs.String(), "request_id=req-42")
+		assert.Contains(t, logs.String(), "recover_test.go")
+	})
+
+	t.Run("Gera request ID e inclui stack no modo debug", func(t *testing.T) {
+		server := &Server{config: DefaultServerConfig(), logger: log.New(&bytes.Buffer{}, "", 0)}
+		server.SetDebugErrors(true)
+
+		resp, err := newApp(server).Test(httptest.NewRequest(http.MethodGet, "/panic", nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("X-Request-ID"))
+
+		inner, ok := decodeODataError(t, resp)["innererror"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "mapa nil", inner["message"])
+		assert.NotEmpty(t, inner["stacktrace"])
+	})
+
+	t.Run("Desabilitado propaga o panic", func(t *testing.T) {
+		server := &Server{config: DefaultServerConfig(), logger: log.New(&bytes.Buffer{}, "", 0)}
+		server.SetRecoverConfig(&RecoverConfig{Enabled: false})
+
+		// Middleware externo registra o panic que atravessou o RecoverMiddleware
+		propagated := false
+		app := fiber.New()
+		app.Use(func(c fiber.Ctx) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					propagated = true
+					err = c.SendStatus(http.StatusTeapot)
+				}
+			}()
+			return c.Next()
+		})
+		app.Use(server.RecoverMiddleware())
+		app.Get("/panic", func(c fiber.Ctx) error {
+			panic("mapa nil")
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/panic", nil))
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.True(t, propagated)
+		assert.Equal(t, int64(0), server.GetPanicCount())
+	})
+}
+
+func TestEntityEventManager_PanicReturnsError(t *testing.T) {
+	var logs bytes.Buffer
+	manager := NewEntityEventManager(log.New(&logs, "", 0))
+	manager.Subscribe(EventEntityInserting, "Products", EventHandlerFunc(func(args EventArgs) error {
+		panic("handler quebrado")
+	}))
+
+	ctx := &EventContext{EntityName: "Products", RequestID: "req-7", Extra: make(map[string]interface{})}
+	err := manager.Emit(NewEntityInsertingArgs(ctx, map[string]interface{}{"name": "Mouse"}))
+	require.Error(t, err)
+
+	var panicErr *PanicError
+	require.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "handler quebrado", panicErr.Value)
+	assert.Equal(t, int64(1), manager.PanicCount())
+	assert.True(t, strings.Contains(logs.String(), "request_id=req-7"))
+}
@@ -0,0 +1,291 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// LIXEIRA DAS ENTIDADES COM EXCLUSÃO LÓGICA
+// =======================================================================================
+
+// DefaultRecycleBinPurgeSchedule é o agendamento padrão do expurgo da lixeira
+const DefaultRecycleBinPurgeSchedule = "@hourly"
+
+// recycleBinSegment é o segmento da lixeira nas rotas (ex: /odata/$trash/Products)
+const recycleBinSegment = "/$trash"
+
+// recycleBinRestoreAction é a ação de restauração (POST /odata/$trash/Products(1)/Default.Restore)
+const recycleBinRestoreAction = "/Default.Restore"
+
+// recycleBinPurgeBatchSize é a quantidade de linhas expiradas lidas por vez no expurgo
+const recycleBinPurgeBatchSize = 500
+
+// RecycleBinConfig configura a lixeira de uma entidade com exclusão lógica
+type RecycleBinConfig struct {
+	Roles         []string      // Roles com acesso à lixeira, além dos admins (vazio: quem acessa a entidade)
+	Retention     time.Duration // Tempo na lixeira até a exclusão definitiva (0: sem expurgo)
+	PurgeSchedule string        // Agendamento do expurgo (padrão: DefaultRecycleBinPurgeSchedule)
+}
+
+// WithRecycleBin expõe as linhas excluídas logicamente (WithSoftDelete) em
+// /$trash/Entidade, com a ação de restauração e o expurgo após o período de retenção
+func WithRecycleBin(config RecycleBinConfig) EntityOption {
+	return func(entityConfig *EntityConfig) {
+		entityConfig.RecycleBin = &config
+	}
+}
+
+// registerRecycleBin guarda a configuração da lixeira e agenda o expurgo da entidade
+func (s *Server) registerRecycleBin(entityName string, config RecycleBinConfig) error {
+	if config.Retention > 0 {
+		schedule := config.PurgeSchedule
+		if schedule == "" {
+			schedule = DefaultRecycleBinPurgeSchedule
+		}
+		err := s.Schedule(schedule, func(ctx *JobContext) error {
+			return s.purgeRecycleBinJob(ctx, entityName)
+		}, WithJobName("recycle-bin:"+entityName))
+		if err != nil {
+			return fmt.Errorf("expurgo da lixeira: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.recycleBins == nil {
+		s.recycleBins = make(map[string]RecycleBinConfig)
+	}
+	s.recycleBins[entityName] = config
+	return nil
+}
+
+// getRecycleBin retorna a configuração da lixeira da entidade, se houver
+func (s *Server) getRecycleBin(entityName string) (RecycleBinConfig, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	config, ok := s.recycleBins[entityName]
+	return config, ok
+}
+
+// setupRecycleBinRoutes registra as rotas da lixeira com os middlewares da entidade
+func (s *Server) setupRecycleBinRoutes(router fiber.Router, entityName, prefix, setName string, middlewares []any) {
+	config, ok := s.getRecycleBin(entityName)
+	if !ok {
+		return
+	}
+
+	// Os middlewares da entidade (ex: autenticação) executam antes da verificação das roles
+	register := func(method, path string, handler fiber.Handler) {
+		chain := append(append([]any{}, middlewares...), s.recycleBinAccess(config), handler)
+		router.Add([]string{method}, path, chain[0], chain[1:]...)
+	}
+	path := prefix + recycleBinSegment + "/" + setName
+	register(fiber.MethodGet, path, s.handleRecycleBin(entityName, false))
+	register(fiber.MethodGet, path+"/$count", s.handleRecycleBin(entityName, true))
+	register(fiber.MethodPost, path+"(*)"+recycleBinRestoreAction, s.handleRecycleBinEntity(entityName))
+	register(fiber.MethodDelete, path+"(*)", s.handleRecycleBinEntity(entityName))
+}
+
+// recycleBinAccess restringe a lixeira aos admins e às roles configuradas
+func (s *Server) recycleBinAccess(config RecycleBinConfig) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if len(config.Roles) == 0 {
+			return c.Next()
+		}
+		user := GetCurrentUser(c)
+		if user == nil {
+			return s.writeODataError(c, http.StatusUnauthorized,
+				NewODataError("Unauthorized", "Authentication required"), nil)
+		}
+		if !user.Admin && !user.HasAnyRole(config.Roles...) {
+			return s.writeODataError(c, http.StatusForbidden,
+				NewODataError("Forbidden", "Recycle bin access denied"), nil)
+		}
+		return c.Next()
+	}
+}
+
+// handleRecycleBin lista (ou conta) as linhas excluídas da entidade, com as mesmas query
+// options da coleção
+func (s *Server) handleRecycleBin(entityName string, count bool) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		service := s.GetEntityService(entityName)
+		if service == nil {
+			s.writeError(c, fiber.StatusNotFound, "EntityNotFound", fmt.Sprintf("Entity '%s' not found", entityName))
+			return nil
+		}
+
+		c.SetContext(withSoftDeleteScope(c.Context(), softDeleteTrashed))
+		if count {
+			return s.writeCollectionCount(c, service, entityName, "")
+		}
+		return s.writeCollection(c, service, entityName, "")
+	}
+}
+
+// handleRecycleBinEntity restaura (POST .../Default.Restore) ou exclui definitivamente
+// (DELETE) uma entidade da lixeira
+func (s *Server) handleRecycleBinEntity(entityName string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		service := s.GetEntityService(entityName)
+		if service == nil {
+			s.writeError(c, fiber.StatusNotFound, "EntityNotFound", fmt.Sprintf("Entity '%s' not found", entityName))
+			return nil
+		}
+
+		path := strings.TrimSuffix(c.Path(), recycleBinRestoreAction)
+		if !strings.HasSuffix(path, ")") {
+			s.writeError(c, fiber.StatusNotFound, "NotFound", "Resource not found")
+			return nil
+		}
+		keys, err := s.extractKeys(path, service.GetMetadata())
+		if err != nil {
+			s.writeError(c, fiber.StatusBadRequest, "InvalidKey", err.Error())
+			return nil
+		}
+
+		ctx, cancel := s.requestContext(c, entityName)
+		defer cancel()
+
+		if c.Method() == fiber.MethodDelete {
+			if err := service.Delete(withSoftDeleteScope(ctx, softDeleteTrashed), keys); err != nil {
+				s.writeRecycleBinError(c, ctx, entityName, "DeleteError", err)
+				return nil
+			}
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+
+		restorable, ok := service.(restorableEntityService)
+		if !ok {
+			s.writeError(c, fiber.StatusNotImplemented, "NotImplemented", "Entity service does not support restore")
+			return nil
+		}
+		restored, err := restorable.Restore(ctx, keys)
+		if err != nil {
+			s.writeRecycleBinError(c, ctx, entityName, "RestoreError", err)
+			return nil
+		}
+		return s.writeEntityJSON(c, service, restored)
+	}
+}
+
+// writeRecycleBinError escreve o erro de uma operação na lixeira (404 quando a entidade não
+// está na lixeira)
+func (s *Server) writeRecycleBinError(c fiber.Ctx, ctx context.Context, entityName, code string, err error) {
+	err = s.queryContextError(ctx, entityName, err)
+	if strings.Contains(err.Error(), "not found") {
+		s.writeError(c, fiber.StatusNotFound, "EntityNotFound", err.Error())
+		return
+	}
+	s.writeEntityError(c, fiber.StatusInternalServerError, code, err)
+}
+
+// PurgeRecycleBin exclui definitivamente as linhas da lixeira da entidade excluídas antes de
+// olderThan e retorna a quantidade removida. Executado pelo job de expurgo (Retention)
+func (s *Server) PurgeRecycleBin(ctx context.Context, entityName string, olderThan time.Time) (int, error) {
+	service := s.GetEntityService(entityName)
+	if service == nil {
+		return 0, fmt.Errorf("entity '%s' not found", entityName)
+	}
+	metadata := service.GetMetadata()
+	if metadata.SoftDeleteProperty == "" {
+		return 0, fmt.Errorf("entity '%s' does not use soft delete", entityName)
+	}
+
+	filter, err := ParseFilterString(ctx, fmt.Sprintf("%s lt %s", metadata.SoftDeleteProperty, olderThan.UTC().Format(time.RFC3339)))
+	if err != nil {
+		return 0, err
+	}
+	if err := resolveDateTimeLiterals(filter.Tree, time.UTC); err != nil {
+		return 0, err
+	}
+	top := GoDataTopQuery(recycleBinPurgeBatchSize)
+
+	ctx = withSoftDeleteScope(ctx, softDeleteTrashed)
+	purged := 0
+	for {
+		// Cada lote relê as linhas expiradas: as já removidas deixam de aparecer
+		response, err := service.Query(ctx, QueryOptions{Filter: filter, Top: &top})
+		if err != nil {
+			return purged, err
+		}
+		rows, _ := response.Value.([]interface{})
+		if len(rows) == 0 {
+			return purged, nil
+		}
+		for _, row := range rows {
+			keys, ok := entityKeyValues(metadata, row)
+			if !ok {
+				return purged, fmt.Errorf("entity '%s': key values not found in recycle bin row", entityName)
+			}
+			if err := service.Delete(ctx, keys); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(rows) < recycleBinPurgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// purgeRecycleBinJob expurga a lixeira da entidade (em cada tenant, no modo multi-tenant)
+func (s *Server) purgeRecycleBinJob(ctx *JobContext, entityName string) error {
+	config, ok := s.getRecycleBin(entityName)
+	if !ok || config.Retention <= 0 {
+		return nil
+	}
+	cutoff := time.Now().Add(-config.Retention)
+
+	purge := func(ctx context.Context) error {
+		purged, err := s.PurgeRecycleBin(ctx, entityName, cutoff)
+		if purged > 0 {
+			s.logger.Printf("🗑️ Lixeira de %s: %d registro(s) excluído(s) definitivamente", entityName, purged)
+		}
+		return err
+	}
+	if s.multiTenantPool == nil {
+		return purge(ctx)
+	}
+	return ctx.ForEachTenant(func(tenant *JobContext) error {
+		return purge(context.WithValue(tenant, TenantContextKey, tenant.GetTenantID()))
+	})
+}
+
+// entityKeyValues extrai os valores das chaves de uma linha retornada pelo serviço
+func entityKeyValues(metadata EntityMetadata, row interface{}) (map[string]interface{}, bool) {
+	get := entityValueGetter(row)
+	if get == nil {
+		return nil, false
+	}
+
+	keys := make(map[string]interface{})
+	for _, prop := range metadata.Properties {
+		if !prop.IsKey {
+			continue
+		}
+		value, ok := get(prop.Name)
+		if !ok || value == nil {
+			return nil, false
+		}
+		keys[prop.Name] = value
+	}
+	return keys, len(keys) > 0
+}
+
+// entityValueGetter retorna o acesso às propriedades de uma linha retornada pelo serviço
+func entityValueGetter(row interface{}) func(name string) (interface{}, bool) {
+	switch v := row.(type) {
+	case *OrderedEntity:
+		return v.Get
+	case map[string]interface{}:
+		return mapGetter(v)
+	}
+	return nil
+}
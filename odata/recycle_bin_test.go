@@ -0,0 +1,186 @@
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRecycleBinTestServer cria o servidor de teste com Products em exclusão lógica e lixeira
+// restrita à role "auditor" (usuário informado no header X-Test-Role)
+func newRecycleBinTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := newBatchGetTestServer(t)
+	_, err := server.provider.GetConnection().Exec("ALTER TABLE products ADD COLUMN deleted_at DATETIME")
+	require.NoError(t, err)
+
+	metadata := server.entities["Products"].GetMetadata()
+	metadata.Properties = append(metadata.Properties, PropertyMetadata{Name: "deletedAt", ColumnName: "deleted_at", Type: "time.Time", IsNullable: true})
+	metadata.SoftDeleteProperty = "deletedAt"
+	require.NoError(t, validateSoftDelete(metadata))
+	server.entities["Products"] = NewBaseEntityService(server.provider, metadata, server)
+	require.NoError(t, server.registerRecycleBin("Products", RecycleBinConfig{Roles: []string{"auditor"}}))
+
+	server.router = fiber.New()
+	server.router.Use(func(c fiber.Ctx) error {
+		if role := c.Get("X-Test-Role"); role != "" {
+			c.Locals(UserContextKey, &UserIdentity{Username: "teste", Roles: []string{role}})
+		}
+		return c.Next()
+	})
+	server.setupEntityRoutes("Products")
+	return server
+}
+
+func TestServer_SoftDelete(t *testing.T) {
+	server := newRecycleBinTestServer(t)
+
+	resp, body := recycleBinRequest(t, server, http.MethodDelete, "/odata/Products(1)", "")
+	require.Equal(t, http.StatusNoContent, resp.StatusCode, body)
+
+	var deletedAt interface{}
+	require.NoError(t, server.provider.GetConnection().QueryRow("SELECT deleted_at FROM products WHERE id = 1").Scan(&deletedAt))
+	assert.NotNil(t, deletedAt, "a linha é mantida com a data de exclusão")
+
+	resp, body = recycleBinRequest(t, server, http.MethodGet, "/odata/Products", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Len(t, recycleBinValues(t, body), 2)
+
+	resp, _ = recycleBinRequest(t, server, http.MethodGet, "/odata/Products(1)", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	_, body = recycleBinRequest(t, server, http.MethodGet, "/odata/Products/$count", "")
+	assert.Equal(t, "2", body)
+
+	resp, _ = recycleBinRequest(t, server, http.MethodDelete, "/odata/Products(1)", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "já excluída")
+}
+
+func TestServer_RecycleBin(t *testing.T) {
+	server := newRecycleBinTestServer(t)
+	resp, body := recycleBinRequest(t, server, http.MethodDelete, "/odata/Products(1)", "")
+	require.Equal(t, http.StatusNoContent, resp.StatusCode, body)
+
+	t.Run("Acesso restrito às roles", func(t *testing.T) {
+		resp, _ := recycleBinRequest(t, server, http.MethodGet, "/odata/$trash/Products", "")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp, _ = recycleBinRequest(t, server, http.MethodGet, "/odata/$trash/Products", "vendedor")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Lista as linhas excluídas", func(t *testing.T) {
+		resp, body := recycleBinRequest(t, server, http.MethodGet, "/odata/$trash/Products?$select=id,name", "auditor")
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		values := recycleBinValues(t, body)
+		require.Len(t, values, 1)
+		assert.Equal(t, "Mouse", values[0]["name"])
+
+		_, body = recycleBinRequest(t, server, http.MethodGet, "/odata/$trash/Products/$count", "auditor")
+		assert.Equal(t, "1", body)
+	})
+
+	t.Run("Restaura a entidade", func(t *testing.T) {
+		resp, body := recycleBinRequest(t, server, http.MethodPost, "/odata/$trash/Products(2)/Default.Restore", "auditor")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, "fora da lixeira: %s", body)
+
+		resp, body = recycleBinRequest(t, server, http.MethodPost, "/odata/$trash/Products(1)/Default.Restore", "auditor")
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		var restored map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(body), &restored))
+		assert.Equal(t, "Mouse", restored["name"])
+		assert.Nil(t, restored["deletedAt"])
+
+		resp, _ = recycleBinRequest(t, server, http.MethodGet, "/odata/Products(1)", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Exclusão definitiva", func(t *testing.T) {
+		resp, _ := recycleBinRequest(t, server, http.MethodDelete, "/odata/Products(3)", "")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, _ = recycleBinRequest(t, server, http.MethodDelete, "/odata/$trash/Products(2)", "auditor")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, "fora da lixeira")
+
+		resp, body := recycleBinRequest(t, server, http.MethodDelete, "/odata/$trash/Products(3)", "auditor")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode, body)
+
+		var count int
+		require.NoError(t, server.provider.GetConnection().QueryRow("SELECT COUNT(*) FROM products WHERE id = 3").Scan(&count))
+		assert.Zero(t, count)
+	})
+}
+
+func TestServer_PurgeRecycleBin(t *testing.T) {
+	server := newRecycleBinTestServer(t)
+	for _, path := range []string{"/odata/Products(1)", "/odata/Products(2)"} {
+		resp, body := recycleBinRequest(t, server, http.MethodDelete, path, "")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode, body)
+	}
+
+	purged, err := server.PurgeRecycleBin(context.Background(), "Products", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged, "dentro do período de retenção")
+
+	purged, err = server.PurgeRecycleBin(context.Background(), "Products", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+
+	var count int
+	require.NoError(t, server.provider.GetConnection().QueryRow("SELECT COUNT(*) FROM products").Scan(&count))
+	assert.Equal(t, 1, count, "apenas a linha ativa permanece")
+
+	_, err = server.PurgeRecycleBin(context.Background(), "Desconhecida", time.Now())
+	assert.Error(t, err)
+}
+
+func recycleBinRequest(t *testing.T, server *Server, method, path, role string) (*http.Response, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if role != "" {
+		req.Header.Set("X-Test-Role", role)
+	}
+	resp, err := server.router.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func recycleBinValues(t *testing.T, body string) []map[string]interface{} {
+	t.Helper()
+	var response struct {
+		Value []map[string]interface{} `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &response))
+	return response.Value
+}
+
+func TestServer_SoftDeleteNavigationPaths(t *testing.T) {
+	server := newNavigationTestServer(t)
+	_, err := server.provider.GetConnection().Exec("ALTER TABLE categories ADD COLUMN deleted_at TEXT")
+	require.NoError(t, err)
+	_, err = server.provider.GetConnection().Exec("UPDATE categories SET deleted_at = '2024-01-01' WHERE id = 2")
+	require.NoError(t, err)
+	updateNavigationCategories(server, func(metadata *EntityMetadata) {
+		metadata.Properties = append(metadata.Properties, PropertyMetadata{Name: "deletedAt", ColumnName: "deleted_at", Type: "time.Time", IsNullable: true})
+		metadata.SoftDeleteProperty = "deletedAt"
+	})
+
+	names := productNames(t, server, "$filter="+url.QueryEscape("Category/name eq 'Displays'"))
+	assert.Empty(t, names, "a categoria excluída não atende ao filtro")
+
+	names = productNames(t, server, "$orderby="+url.QueryEscape("Category/name desc,name"))
+	assert.Equal(t, []string{"Mouse", "Teclado", "Cabo", "Monitor"}, names, "a categoria excluída ordena como ausente")
+}
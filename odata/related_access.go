@@ -0,0 +1,163 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// LEITURA DE OUTRAS ENTIDADES NA CONSULTA (NAVEGAÇÕES E $root)
+// =======================================================================================
+
+// relatedRead é a leitura de uma propriedade de outra entidade dentro da consulta: um
+// caminho de navegação ($filter=Category/Name eq 'X', $orderby=Category/Name) ou uma
+// referência $root/Categories(1)/Name
+type relatedRead struct {
+	entityName string                 // Nome registrado da entidade lida
+	metadata   EntityMetadata         // Metadados da entidade lida
+	property   *PropertyMetadata      // Propriedade lida
+	keys       map[string]interface{} // Chave da linha lida ($root); nil nas navegações
+	path       string                 // Caminho na consulta, usado nas mensagens de erro
+	target     string                 // Opção de consulta ($filter ou $orderby)
+}
+
+// checkRelatedRead aplica à leitura as proteções de um GET direto da entidade lida: a
+// configuração de autenticação e a autorização da entidade (401/403), as propriedades
+// ocultas e criptografadas e o filtro padrão (400). Sem essas verificações, o valor
+// comparado ou ordenado vazaria por meio da consulta de outra entidade. As linhas excluídas
+// logicamente são descartadas na própria subquery ou JOIN (softDeleteColumn)
+func (s *BaseEntityService) checkRelatedRead(ctx context.Context, read relatedRead) error {
+	if s.server == nil {
+		return nil
+	}
+
+	if err := s.server.checkEntityAuthConfig(ctx, read.entityName); err != nil {
+		return err
+	}
+	if err := s.server.authorizeEntity(ctx, &AuthorizationRequest{
+		EntityName: read.entityName,
+		Operation:  fiber.MethodGet,
+		User:       contextUser(ctx),
+		Keys:       read.keys,
+	}); err != nil {
+		return err
+	}
+
+	if read.property.IsNavigation || isHiddenProperty(read.metadata, read.property.Name) {
+		return invalidQueryOption(read.target, hiddenPropertyError(read.metadata, read.target, read.property.Name).Error())
+	}
+	// O literal comparado estaria em texto puro e a coluna guarda o texto cifrado
+	if read.property.IsEncrypted {
+		return invalidQueryOption(read.target, fmt.Sprintf("%s cannot reference the encrypted property %s of %s", read.path, read.property.Name, read.metadata.Name))
+	}
+	// O filtro padrão é uma expressão OData da entidade lida, que a subquery não consegue
+	// aplicar: a leitura é recusada para não ignorar o filtro
+	if read.metadata.DefaultFilter != "" {
+		return invalidQueryOption(read.target, fmt.Sprintf("%s cannot reference %s, which has a default filter", read.path, read.metadata.Name))
+	}
+	return nil
+}
+
+// checkNavigationReads aplica checkRelatedRead a cada caminho de navegação N:1 da opção de
+// consulta. Caminhos inválidos são ignorados aqui e recusados na construção da query
+func (s *BaseEntityService) checkNavigationReads(ctx context.Context, paths []string, target string) error {
+	if s.server == nil {
+		return nil
+	}
+	for _, path := range paths {
+		navigation, property, ok := strings.Cut(path, "/")
+		if !ok || strings.Contains(property, "/") {
+			continue
+		}
+		join, err := newNavigationJoins(s.queryMetadata(), s.localEntityMetadata).join(navigation)
+		if err != nil {
+			continue
+		}
+		prop := findEntityProperty(join.related, property)
+		if prop == nil {
+			continue
+		}
+		service := s.server.relatedEntityService(join.related.Name)
+		if err := s.checkRelatedRead(ctx, relatedRead{
+			entityName: s.server.registeredEntityName(service, join.related.Name),
+			metadata:   join.related,
+			property:   prop,
+			path:       path,
+			target:     target,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkEntityAuthConfig aplica a configuração de autenticação da entidade (RequireAuth,
+// RequireAdmin, RequiredRoles e RequiredScopes), como RequireEntityAuth faz nas rotas
+func (s *Server) checkEntityAuthConfig(ctx context.Context, entityName string) error {
+	authConfig, exists := s.GetEntityAuth(entityName)
+	if !exists || !authConfig.RequireAuth {
+		return nil
+	}
+
+	user := contextUser(ctx)
+	if user == nil {
+		return NewODataError("Unauthorized", "Autenticação requerida para acessar "+entityName).WithStatus(http.StatusUnauthorized)
+	}
+	if authConfig.RequireAdmin && !user.Admin {
+		return NewODataError("Forbidden", "Privilégios de administrador requeridos para acessar "+entityName).WithStatus(http.StatusForbidden)
+	}
+	if len(authConfig.RequiredRoles) > 0 && !user.HasAnyRole(authConfig.RequiredRoles...) {
+		return NewODataError("Forbidden", "Role necessária para acessar "+entityName).WithStatus(http.StatusForbidden)
+	}
+	if len(authConfig.RequiredScopes) > 0 && !user.HasAnyScope(authConfig.RequiredScopes...) {
+		return NewODataError("Forbidden", "Scope necessário para acessar "+entityName).WithStatus(http.StatusForbidden)
+	}
+	return nil
+}
+
+// navigationPathsInTree retorna os caminhos de navegação referenciados na expressão
+func navigationPathsInTree(tree *ParseNode) []string {
+	var paths []string
+	walkParseTree(tree, func(node *ParseNode) {
+		if node.Token != nil && node.Token.Type == int(FilterTokenProperty) && strings.Contains(node.Token.Value, "/") {
+			paths = append(paths, node.Token.Value)
+		}
+	})
+	return paths
+}
+
+// navigationPathsInOrderBy retorna os caminhos de navegação do $orderby. Erros de sintaxe
+// são reportados na construção da query
+func navigationPathsInOrderBy(orderBy string) []string {
+	if !strings.Contains(orderBy, "/") {
+		return nil
+	}
+	expressions, err := NewODataParser().ParseOrderBy(orderBy)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, expr := range expressions {
+		if expr.Expression != nil {
+			paths = append(paths, navigationPathsInTree(expr.Expression)...)
+		} else if strings.Contains(expr.Property, "/") {
+			paths = append(paths, expr.Property)
+		}
+	}
+	return paths
+}
+
+// invalidQueryOption retorna o erro 400 de uma opção de consulta inválida
+func invalidQueryOption(target, message string) error {
+	code := "InvalidFilter"
+	if target == "$orderby" {
+		code = "InvalidOrderBy"
+	}
+	return NewODataError(code, message).
+		WithStatus(http.StatusBadRequest).
+		WithTarget(target)
+}
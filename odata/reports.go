@@ -0,0 +1,321 @@
+package odata
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// RELATÓRIOS (CSV, XLSX E PDF)
+// =======================================================================================
+
+// ReportFormat é o formato de saída de um relatório
+type ReportFormat string
+
+const (
+	ReportCSV  ReportFormat = "csv"
+	ReportXLSX ReportFormat = "xlsx"
+	ReportPDF  ReportFormat = "pdf"
+)
+
+// ReportColumn mapeia uma coluna do resultado para uma coluna do relatório
+type ReportColumn struct {
+	Field string // Coluna retornada pela consulta
+	Title string // Cabeçalho no relatório
+}
+
+// ReportOptions configura a geração do relatório
+type ReportOptions struct {
+	Format   ReportFormat
+	Title    string         // Título do PDF e nome da planilha XLSX
+	FileName string         // Nome do arquivo no download, sem extensão (padrão: entidade)
+	Columns  []ReportColumn // Padrão: propriedades da entidade registrada ou colunas da consulta
+}
+
+// reportWriter escreve o relatório em um formato, registro a registro
+type reportWriter interface {
+	begin(w io.Writer, title string, columns []ReportColumn) error
+	writeRow(values []any) error
+	end() error
+}
+
+// ParseReportFormat converte o formato informado pelo cliente (csv, xlsx/excel ou pdf)
+func ParseReportFormat(format string) (ReportFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "csv":
+		return ReportCSV, nil
+	case "xlsx", "excel":
+		return ReportXLSX, nil
+	case "pdf":
+		return ReportPDF, nil
+	}
+	return "", fmt.Errorf("unsupported report format '%s' (csv, xlsx, pdf)", format)
+}
+
+// ContentType retorna o Content-Type do formato
+func (f ReportFormat) ContentType() string {
+	switch f {
+	case ReportXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ReportPDF:
+		return "application/pdf"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// newReportWriter cria o writer do formato
+func newReportWriter(format ReportFormat) (reportWriter, error) {
+	switch format {
+	case ReportCSV:
+		return &csvReportWriter{}, nil
+	case ReportXLSX:
+		return &xlsxReportWriter{}, nil
+	case ReportPDF:
+		return &pdfReportWriter{}, nil
+	}
+	return nil, fmt.Errorf("unsupported report format '%s' (csv, xlsx, pdf)", format)
+}
+
+// WriteReport executa a consulta e escreve o relatório em w, percorrendo o resultado com um
+// cursor (sem carregar todos os registros em memória)
+func WriteReport(w io.Writer, query *ObjectQuery, options ReportOptions) error {
+	writer, err := newReportWriter(options.Format)
+	if err != nil {
+		return err
+	}
+	cursor, err := query.Stream(query.manager.context)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	return writeReport(w, writer, cursor, reportColumns(options.Columns, cursor), options.Title)
+}
+
+// SendReport executa a consulta e envia o relatório como download, em streaming. Sem
+// colunas nas opções, o relatório usa as propriedades da entidade registrada no servidor
+// (cabeçalho com o nome da propriedade) ou, se não houver, as colunas da consulta. Erros
+// na consulta são retornados antes do envio; erros durante o streaming são registrados no log
+func SendReport(c fiber.Ctx, query *ObjectQuery, options ReportOptions) error {
+	writer, err := newReportWriter(options.Format)
+	if err != nil {
+		return NewODataError(ODataErrorCode(fiber.StatusBadRequest), err.Error()).WithStatus(fiber.StatusBadRequest)
+	}
+
+	server := getServerFromContext(c)
+	if len(options.Columns) == 0 && server != nil {
+		options.Columns = server.entityReportColumns(query)
+	}
+
+	cursor, err := query.Stream(query.manager.context)
+	if err != nil {
+		return err
+	}
+	columns := reportColumns(options.Columns, cursor)
+
+	fileName := options.FileName
+	if fileName == "" {
+		fileName = query.entityName
+	}
+	c.Set("Content-Type", options.Format.ContentType())
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName+"."+string(options.Format)))
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		defer cursor.Close()
+		if err := writeReport(w, writer, cursor, columns, options.Title); err != nil && server != nil {
+			server.logger.Printf("❌ Erro ao gerar relatório %s: %v", fileName, err)
+		}
+	})
+}
+
+// SaveReport executa a consulta e grava o relatório no armazenamento na chave informada
+// (ex: server.Storage()), retornando o tamanho do arquivo. O relatório é gerado em um
+// arquivo temporário e enviado ao término
+func SaveReport(ctx context.Context, storage Storage, key string, query *ObjectQuery, options ReportOptions) (int64, error) {
+	if storage == nil {
+		return 0, fmt.Errorf("storage is required")
+	}
+	file, err := os.CreateTemp("", "odata-report-*")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	buffered := bufio.NewWriter(file)
+	if err := WriteReport(buffered, query, options); err != nil {
+		return 0, err
+	}
+	if err := buffered.Flush(); err != nil {
+		return 0, err
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if err := storage.Save(ctx, key, file, size, options.Format.ContentType()); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// writeReport escreve o cabeçalho, os registros do cursor e o rodapé do relatório
+func writeReport(w io.Writer, writer reportWriter, cursor *ObjectCursor, columns []ReportColumn, title string) error {
+	if err := writer.begin(w, title, columns); err != nil {
+		return err
+	}
+
+	values := make([]any, len(columns))
+	for cursor.Next() {
+		row := cursor.Row()
+		for i, column := range columns {
+			values[i] = row[column.Field]
+		}
+		if err := writer.writeRow(values); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	return writer.end()
+}
+
+// reportColumns retorna as colunas configuradas ou, sem configuração, as colunas da consulta
+func reportColumns(configured []ReportColumn, cursor *ObjectCursor) []ReportColumn {
+	if len(configured) > 0 {
+		return configured
+	}
+	columns := make([]ReportColumn, 0, len(cursor.Columns()))
+	for _, name := range cursor.Columns() {
+		columns = append(columns, ReportColumn{Field: name, Title: name})
+	}
+	return columns
+}
+
+// entityReportColumns mapeia as colunas da consulta para as propriedades da entidade
+// registrada (pelo nome da entidade ou da tabela). Propriedades de navegação e fora do
+// Select são ignoradas
+func (s *Server) entityReportColumns(query *ObjectQuery) []ReportColumn {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for name, service := range s.entities {
+		metadata := service.GetMetadata()
+		if !strings.EqualFold(name, query.entityName) && !strings.EqualFold(metadata.TableName, query.entityName) {
+			continue
+		}
+
+		var columns []ReportColumn
+		for _, prop := range metadata.Properties {
+			column := prop.ColumnName
+			if column == "" {
+				column = prop.Name
+			}
+			if prop.IsNavigation || (len(query.selected) > 0 && !containsFold(query.selected, column) && !containsFold(query.selected, prop.Name)) {
+				continue
+			}
+			columns = append(columns, ReportColumn{Field: column, Title: prop.Name})
+		}
+		return columns
+	}
+	return nil
+}
+
+// containsFold verifica se a lista contém o valor, sem diferenciar maiúsculas
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatReportValue converte o valor de uma coluna em texto
+func formatReportValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02 15:04:05")
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// reportNumber retorna o valor numérico da coluna (para células numéricas no XLSX)
+func reportNumber(value any) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("%d", value), true
+	case reflect.Float32, reflect.Float64:
+		f := reflect.ValueOf(value).Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// ==================================================
+// CSV
+// ==================================================
+
+// csvReportWriter escreve o relatório em CSV (RFC 4180)
+type csvReportWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func (r *csvReportWriter) begin(w io.Writer, title string, columns []ReportColumn) error {
+	r.writer = csv.NewWriter(w)
+	r.record = make([]string, len(columns))
+	for i, column := range columns {
+		r.record[i] = column.Title
+	}
+	return r.writer.Write(r.record)
+}
+
+func (r *csvReportWriter) writeRow(values []any) error {
+	for i, value := range values {
+		r.record[i] = formatReportValue(value)
+	}
+	return r.writer.Write(r.record)
+}
+
+func (r *csvReportWriter) end() error {
+	r.writer.Flush()
+	return r.writer.Error()
+}
@@ -0,0 +1,214 @@
+package odata
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ==================================================
+// PDF
+// ==================================================
+
+// Layout do PDF: A4 paisagem, em pontos
+const (
+	pdfPageWidth   = 842.0
+	pdfPageHeight  = 595.0
+	pdfMargin      = 36.0
+	pdfTitleSize   = 14.0
+	pdfFontSize    = 9.0
+	pdfRowHeight   = 14.0
+	pdfFooterSpace = 24.0
+)
+
+// pdfReportWriter escreve o relatório em PDF, em tabela com cabeçalho repetido em cada
+// página. Cada página é gravada ao ser concluída; apenas a página atual fica em memória
+type pdfReportWriter struct {
+	out     *countingWriter
+	title   string
+	columns []ReportColumn
+	width   float64 // Largura de cada coluna
+
+	offsets []int64 // Posição de cada objeto no arquivo (índice = número do objeto - 1)
+	pages   []int   // Números dos objetos de página
+	page    bytes.Buffer
+	y       float64
+}
+
+// countingWriter conta os bytes escritos (posições da tabela xref)
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (r *pdfReportWriter) begin(w io.Writer, title string, columns []ReportColumn) error {
+	r.out = &countingWriter{w: w}
+	r.title = title
+	r.columns = columns
+	if len(columns) > 0 {
+		r.width = (pdfPageWidth - 2*pdfMargin) / float64(len(columns))
+	}
+
+	// Objetos 1 (catálogo), 2 (páginas, gravado no fim), 3 e 4 (fontes)
+	r.offsets = make([]int64, 4)
+	if _, err := io.WriteString(r.out, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"); err != nil {
+		return err
+	}
+	if err := r.writeObject(1, "<< /Type /Catalog /Pages 2 0 R >>"); err != nil {
+		return err
+	}
+	if err := r.writeObject(3, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"); err != nil {
+		return err
+	}
+	if err := r.writeObject(4, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>"); err != nil {
+		return err
+	}
+
+	r.startPage()
+	return nil
+}
+
+func (r *pdfReportWriter) writeRow(values []any) error {
+	if r.y-pdfRowHeight < pdfMargin+pdfFooterSpace {
+		if err := r.finishPage(); err != nil {
+			return err
+		}
+		r.startPage()
+	}
+
+	texts := make([]string, len(values))
+	for i, value := range values {
+		texts[i] = formatReportValue(value)
+	}
+	r.writeTableRow("F1", texts)
+	return nil
+}
+
+func (r *pdfReportWriter) end() error {
+	if err := r.finishPage(); err != nil {
+		return err
+	}
+
+	kids := make([]string, len(r.pages))
+	for i, page := range r.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	if err := r.writeObject(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(r.pages))); err != nil {
+		return err
+	}
+
+	info := len(r.offsets) + 1
+	r.offsets = append(r.offsets, 0)
+	if err := r.writeObject(info, fmt.Sprintf("<< /Title (%s) /Producer (go-data) /CreationDate (D:%s) >>",
+		pdfEscape(r.title), time.Now().UTC().Format("20060102150405Z"))); err != nil {
+		return err
+	}
+
+	xref := r.out.n
+	var b strings.Builder
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(r.offsets)+1)
+	for _, offset := range r.offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(r.offsets)+1, info, xref)
+	_, err := io.WriteString(r.out, b.String())
+	return err
+}
+
+// startPage inicia uma página com o título (na primeira) e o cabeçalho da tabela
+func (r *pdfReportWriter) startPage() {
+	r.page.Reset()
+	r.y = pdfPageHeight - pdfMargin
+
+	if len(r.pages) == 0 && r.title != "" {
+		r.y -= pdfTitleSize
+		r.writeText("F2", pdfTitleSize, pdfMargin, r.y, r.title)
+		r.y -= pdfTitleSize / 2
+	}
+
+	header := make([]string, len(r.columns))
+	for i, column := range r.columns {
+		header[i] = column.Title
+	}
+	r.writeTableRow("F2", header)
+	fmt.Fprintf(&r.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, r.y+4, pdfPageWidth-pdfMargin, r.y+4)
+}
+
+// finishPage grava a página atual com o número no rodapé
+func (r *pdfReportWriter) finishPage() error {
+	r.writeText("F1", pdfFontSize, pdfPageWidth-pdfMargin-60, pdfMargin, fmt.Sprintf("Página %d", len(r.pages)+1))
+
+	content := len(r.offsets) + 1
+	page := content + 1
+	r.offsets = append(r.offsets, 0, 0)
+	if err := r.writeObject(content, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", r.page.Len(), r.page.String())); err != nil {
+		return err
+	}
+	if err := r.writeObject(page, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+		pdfPageWidth, pdfPageHeight, content)); err != nil {
+		return err
+	}
+	r.pages = append(r.pages, page)
+	return nil
+}
+
+// writeTableRow escreve uma linha da tabela, truncando o texto à largura da coluna
+func (r *pdfReportWriter) writeTableRow(font string, texts []string) {
+	r.y -= pdfRowHeight
+	for i, text := range texts {
+		r.writeText(font, pdfFontSize, pdfMargin+float64(i)*r.width, r.y, pdfTruncate(text, r.width-4, pdfFontSize))
+	}
+}
+
+// writeText escreve um texto na posição (x, y) da página atual
+func (r *pdfReportWriter) writeText(font string, size, x, y float64, text string) {
+	fmt.Fprintf(&r.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(text))
+}
+
+// writeObject grava um objeto indireto, registrando sua posição
+func (r *pdfReportWriter) writeObject(number int, body string) error {
+	r.offsets[number-1] = r.out.n
+	_, err := fmt.Fprintf(r.out, "%d 0 obj\n%s\nendobj\n", number, body)
+	return err
+}
+
+// pdfTruncate corta o texto que excede a largura, com largura média de caractere da Helvetica
+func pdfTruncate(text string, width, size float64) string {
+	limit := int(width / (size * 0.5))
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	if limit <= 3 {
+		return string(runes[:max(limit, 0)])
+	}
+	return string(runes[:limit-3]) + "..."
+}
+
+// pdfEscape converte o texto para WinAnsi (Latin-1) e escapa os caracteres especiais de
+// strings PDF. Caracteres fora do Latin-1 são substituídos por '?'
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x7f || (r >= 0xa0 && r <= 0xff):
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package odata
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteReport(t *testing.T) {
+	t.Run("CSV com colunas mapeadas", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+		db := manager.provider.GetConnection()
+		_, err := db.Exec("INSERT INTO products (id, name, price) VALUES (4, 'Cabo, \"USB\"', NULL)")
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		err = WriteReport(&out, manager.Query("products").Where("id gt ?", 1).OrderBy("id"), ReportOptions{
+			Format:  ReportCSV,
+			Columns: []ReportColumn{{Field: "name", Title: "Produto"}, {Field: "price", Title: "Preço"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Produto,Preço\nTeclado,50\nMonitor,900\n\"Cabo, \"\"USB\"\"\",\n", out.String())
+	})
+
+	t.Run("XLSX legível com células tipadas", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+
+		var out bytes.Buffer
+		require.NoError(t, WriteReport(&out, manager.Query("products").Select("name, price").OrderBy("id"), ReportOptions{Format: ReportXLSX, Title: "Produtos <2024>"}))
+
+		archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+		require.NoError(t, err)
+		parts := make(map[string]string)
+		for _, file := range archive.File {
+			reader, err := file.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			parts[file.Name] = string(content)
+		}
+
+		assert.Contains(t, parts, "[Content_Types].xml")
+		assert.Contains(t, parts["xl/workbook.xml"], `name="Produtos &lt;2024&gt;"`)
+		sheet := parts["xl/worksheets/sheet1.xml"]
+		assert.Contains(t, sheet, `<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">name</t></is></c>`)
+		assert.Contains(t, sheet, `<c r="A4" t="inlineStr"><is><t xml:space="preserve">Monitor</t></is></c><c r="B4"><v>900</v></c>`)
+	})
+
+	t.Run("PDF com tabela", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+
+		var out bytes.Buffer
+		require.NoError(t, WriteReport(&out, manager.Query("products").OrderBy("id"), ReportOptions{Format: ReportPDF, Title: "Relatório (produtos)"}))
+
+		pdf := out.String()
+		assert.True(t, len(pdf) > 0 && pdf[:8] == "%PDF-1.4")
+		assert.Contains(t, pdf, "(Monitor) Tj")
+		assert.Contains(t, pdf, "(Relat\xf3rio \\(produtos\\)) Tj")
+		assert.Contains(t, pdf, "/Count 1")
+		assert.True(t, bytes.HasSuffix(out.Bytes(), []byte("%%EOF\n")))
+	})
+
+	t.Run("PDF quebra páginas", func(t *testing.T) {
+		manager, _ := newUnitOfWorkTestManager(t)
+		db := manager.provider.GetConnection()
+		for i := 10; i < 90; i++ {
+			_, err := db.Exec("INSERT INTO products (id, name, price) VALUES (?, 'Item', 1)", i)
+			require.NoError(t, err)
+		}
+
+		var out bytes.Buffer
+		require.NoError(t, WriteReport(&out, manager.Query("products"), ReportOptions{Format: ReportPDF}))
+		assert.Contains(t, out.String(), "/Count 3")
+		assert.Contains(t, out.String(), "(P\xe1gina 3) Tj")
+	})
+
+	t.Run("Formato inválido", func(t *testing.T) {
+		_, err := ParseReportFormat("docx")
+		assert.Error(t, err)
+		format, err := ParseReportFormat("Excel")
+		require.NoError(t, err)
+		assert.Equal(t, ReportXLSX, format)
+	})
+}
+
+func TestSendReport(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
+	server.router.Get("/Reports/Products", func(c fiber.Ctx) error {
+		c.Locals("odata_server", server)
+		format, err := ParseReportFormat(c.Query("format", "csv"))
+		if err != nil {
+			return NewODataError(ODataErrorCode(http.StatusBadRequest), err.Error()).WithStatus(http.StatusBadRequest)
+		}
+		query := NewObjectManager(server.provider, context.Background()).Query("products").Select(c.Query("select", "id, name, price")).OrderBy("id")
+		return SendReport(c, query, ReportOptions{Format: format, FileName: "produtos"})
+	})
+
+	get := func(target string) (*http.Response, string) {
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	resp, body := get("/Reports/Products?select=name")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="produtos.csv"`, resp.Header.Get("Content-Disposition"))
+	assert.Equal(t, "name\nMouse\nTeclado\nMonitor\n", body)
+
+	resp, _ = get("/Reports/Products?format=xlsx")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, ReportXLSX.ContentType(), resp.Header.Get("Content-Type"))
+
+	resp, _ = get("/Reports/Products?format=docx")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = get("/Reports/Products?select=missing")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode, "erro na consulta antes do streaming")
+}
+
+func TestSaveReport(t *testing.T) {
+	manager, _ := newUnitOfWorkTestManager(t)
+	storage := NewFileSystemStorage(t.TempDir())
+
+	size, err := SaveReport(context.Background(), storage, "reports/produtos.csv", manager.Query("products").Select("name").OrderBy("id"), ReportOptions{Format: ReportCSV})
+	require.NoError(t, err)
+
+	reader, err := storage.Open(context.Background(), "reports/produtos.csv")
+	require.NoError(t, err)
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "name\nMouse\nTeclado\nMonitor\n", string(content))
+	assert.Equal(t, int64(len(content)), size)
+}
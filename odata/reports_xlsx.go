@@ -0,0 +1,151 @@
+package odata
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ==================================================
+// XLSX (SpreadsheetML)
+// ==================================================
+
+// xlsxStaticParts são as partes fixas do pacote XLSX (uma planilha, estilo 1 em negrito)
+var xlsxStaticParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`},
+	{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs></styleSheet>`},
+}
+
+// xlsxReportWriter escreve o relatório em uma planilha XLSX. As linhas são gravadas
+// diretamente no zip, sem manter a planilha em memória
+type xlsxReportWriter struct {
+	zip   *zip.Writer
+	sheet io.Writer
+	row   int
+}
+
+func (r *xlsxReportWriter) begin(w io.Writer, title string, columns []ReportColumn) error {
+	r.zip = zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		if err := r.writePart(part.name, part.content); err != nil {
+			return err
+		}
+	}
+
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="` +
+		xmlEscape(xlsxSheetName(title)) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if err := r.writePart("xl/workbook.xml", workbook); err != nil {
+		return err
+	}
+
+	sheet, err := r.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	r.sheet = sheet
+	if _, err := io.WriteString(r.sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return err
+	}
+
+	header := make([]any, len(columns))
+	for i, column := range columns {
+		header[i] = column.Title
+	}
+	return r.writeCells(header, ` s="1"`)
+}
+
+func (r *xlsxReportWriter) writeRow(values []any) error {
+	return r.writeCells(values, "")
+}
+
+func (r *xlsxReportWriter) end() error {
+	if _, err := io.WriteString(r.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return r.zip.Close()
+}
+
+// writePart grava uma parte fixa do pacote
+func (r *xlsxReportWriter) writePart(name, content string) error {
+	part, err := r.zip.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, content)
+	return err
+}
+
+// writeCells grava uma linha da planilha: números e booleanos como células tipadas, o
+// restante como texto
+func (r *xlsxReportWriter) writeCells(values []any, style string) error {
+	r.row++
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, r.row)
+	for i, value := range values {
+		ref := xlsxColumnName(i) + fmt.Sprint(r.row)
+		if number, ok := reportNumber(value); ok {
+			fmt.Fprintf(&b, `<c r="%s"%s><v>%s</v></c>`, ref, style, number)
+			continue
+		}
+		if boolean, ok := value.(bool); ok {
+			v := 0
+			if boolean {
+				v = 1
+			}
+			fmt.Fprintf(&b, `<c r="%s"%s t="b"><v>%d</v></c>`, ref, style, v)
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		fmt.Fprintf(&b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, xmlEscape(formatReportValue(value)))
+	}
+	b.WriteString(`</row>`)
+	_, err := io.WriteString(r.sheet, b.String())
+	return err
+}
+
+// xlsxColumnName converte o índice da coluna na letra da planilha (0 = A, 26 = AA)
+func xlsxColumnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// xlsxSheetName ajusta o título às regras de nome de planilha (até 31 caracteres, sem : \ / ? * [ ])
+func xlsxSheetName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(title))
+	for utf8.RuneCountInString(name) > 31 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" {
+		return "Sheet1"
+	}
+	return name
+}
+
+// xmlEscape escapa o texto para conteúdo e atributos XML, removendo caracteres inválidos
+func xmlEscape(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
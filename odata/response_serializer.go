@@ -0,0 +1,67 @@
+package odata
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// SERIALIZADOR DAS RESPOSTAS DE ENTIDADES
+// =======================================================================================
+
+// ResponseSerializer escreve o corpo das respostas de entidades (coleções, entidade única,
+// POST/PUT/PATCH e navegações). Recebe o corpo já com as máscaras, o nível de odata.metadata
+// e as anotações aplicados: *ODataResponse, *OrderedEntity, *OrderedEntityResponse ou map
+type ResponseSerializer interface {
+	Serialize(ctx *SerializationContext, body interface{}) error
+}
+
+// ResponseSerializerFunc adapta uma função a ResponseSerializer
+type ResponseSerializerFunc func(ctx *SerializationContext, body interface{}) error
+
+// Serialize implementa ResponseSerializer
+func (f ResponseSerializerFunc) Serialize(ctx *SerializationContext, body interface{}) error {
+	return f(ctx, body)
+}
+
+// SerializationContext descreve a resposta sendo serializada
+type SerializationContext struct {
+	Ctx           fiber.Ctx     // Contexto Fiber da requisição
+	Service       EntityService // Serviço da entidade (nil em respostas sem entity set)
+	MetadataLevel MetadataLevel // Nível de odata.metadata pedido pelo cliente
+	IEEE754       bool          // Edm.Int64 e Edm.Decimal devem ser enviados como strings
+}
+
+// EntityName retorna o nome da entidade da resposta (vazio sem entity set)
+func (ctx *SerializationContext) EntityName() string {
+	if ctx.Service == nil {
+		return ""
+	}
+	return ctx.Service.GetMetadata().Name
+}
+
+// SetResponseSerializer substitui o serializador das respostas de entidades. Para envolver o
+// serializador padrão (ex: campos extras no envelope), chame DefaultResponseSerializer
+// dentro do novo serializador. nil restaura o padrão
+func (s *Server) SetResponseSerializer(serializer ResponseSerializer) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responseSerializer = serializer
+	return s
+}
+
+// DefaultResponseSerializer retorna o serializador JSON padrão do servidor, que respeita o
+// FastJSONEncoding e o IEEE754Compatible
+func (s *Server) DefaultResponseSerializer() ResponseSerializer {
+	return ResponseSerializerFunc(s.serializeJSON)
+}
+
+// getResponseSerializer retorna o serializador configurado ou o padrão
+func (s *Server) getResponseSerializer() ResponseSerializer {
+	s.mu.RLock()
+	serializer := s.responseSerializer
+	s.mu.RUnlock()
+	if serializer == nil {
+		return s.DefaultResponseSerializer()
+	}
+	return serializer
+}
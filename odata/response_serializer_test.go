@@ -0,0 +1,75 @@
+package odata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ResponseSerializer(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	get := func(t *testing.T, path, accept string) (string, string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body), resp.Header.Get("Content-Type")
+	}
+
+	t.Run("Envolve o serializador padrão", func(t *testing.T) {
+		defaultSerializer := server.DefaultResponseSerializer()
+		var received *SerializationContext
+		server.SetResponseSerializer(ResponseSerializerFunc(func(ctx *SerializationContext, body interface{}) error {
+			received = ctx
+			if response, ok := body.(*ODataResponse); ok {
+				response.Annotations = append(response.Annotations, OrderedProperty{Name: "@myapp.version", Value: "2"})
+			}
+			return defaultSerializer.Serialize(ctx, body)
+		}))
+		defer server.SetResponseSerializer(nil)
+
+		body, contentType := get(t, "/odata/Products?$top=1", "application/json;IEEE754Compatible=true;odata.metadata=full")
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(body), &result))
+		assert.Equal(t, "2", result["@myapp.version"])
+		assert.Equal(t, "1", result["value"].([]interface{})[0].(map[string]interface{})["id"], "IEEE754 mantido")
+		assert.Contains(t, contentType, "odata.metadata=full")
+
+		require.NotNil(t, received)
+		assert.Equal(t, "Products", received.EntityName())
+		assert.Equal(t, MetadataFull, received.MetadataLevel)
+		assert.True(t, received.IEEE754)
+	})
+
+	t.Run("Substitui o serializador", func(t *testing.T) {
+		server.SetResponseSerializer(ResponseSerializerFunc(func(ctx *SerializationContext, body interface{}) error {
+			response := body.(*ODataResponse)
+			return ctx.Ctx.JSON(fiber.Map{"_embedded": fiber.Map{"products": response.Value}}, "application/hal+json")
+		}))
+		defer server.SetResponseSerializer(nil)
+
+		body, contentType := get(t, "/odata/Products?$top=1", "")
+		assert.Equal(t, "application/hal+json", contentType)
+		assert.JSONEq(t, `{"_embedded":{"products":[{"id":1,"name":"Mouse","price":10}]}}`, body)
+	})
+
+	t.Run("nil restaura o padrão", func(t *testing.T) {
+		body, _ := get(t, "/odata/Products?$top=1", "")
+		assert.JSONEq(t, `{"@odata.context":"http://example.com/odata/$metadata#Products","value":[{"id":1,"name":"Mouse","price":10}]}`, body)
+	})
+}
@@ -1,10 +1,10 @@
 package odata
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // =======================================================================================
@@ -21,32 +21,7 @@ func (s *BaseEntityService) convertValueToPropertyType(value any, propertyName s
 	// Encontra a propriedade nos metadados
 	for _, prop := range metadata.Properties {
 		if strings.EqualFold(prop.Name, propertyName) {
-			// Converte o valor para o tipo correto
-			switch prop.Type {
-			case "int64":
-				return s.convertToInt64(value)
-			case "int32", "int":
-				return s.convertToInt32(value)
-			case "float64", "double":
-				return s.convertToFloat64(value)
-			case "float32", "single":
-				return s.convertToFloat32(value)
-			case "string":
-				return s.convertToString(value), nil
-			case "bool", "boolean":
-				return s.convertToBool(value)
-			case "[]byte", "binary":
-				return s.convertToBytes(value)
-			default:
-				// Para tipos não mapeados ou personalizados, aplica conversão básica
-				switch v := value.(type) {
-				case []byte:
-					// Por padrão, converte []byte para string se não for tipo binário
-					return string(v), nil
-				default:
-					return value, nil
-				}
-			}
+			return s.propertyConverter(prop)(value)
 		}
 	}
 
@@ -54,6 +29,74 @@ func (s *BaseEntityService) convertValueToPropertyType(value any, propertyName s
 	return value, nil
 }
 
+// valueConverter converte um valor não nulo lido do banco para o tipo da propriedade
+type valueConverter func(value any) (any, error)
+
+// propertyConverter seleciona a conversão do tipo da propriedade, permitindo resolvê-la uma
+// única vez por propriedade (planos de scan) em vez de a cada valor
+func (s *BaseEntityService) propertyConverter(prop PropertyMetadata) valueConverter {
+	// Edm.Decimal (tipo Decimal ou float com precision) mantém os dígitos exatos
+	if isDecimalProperty(prop) {
+		return func(value any) (any, error) {
+			return s.convertToDecimal(value, prop)
+		}
+	}
+
+	// Converte o valor para o tipo correto
+	switch prop.Type {
+	case "int64":
+		return s.convertToInt64
+	case "int32", "int":
+		return s.convertToInt32
+	case "float64", "double":
+		return s.convertToFloat64
+	case "float32", "single":
+		return s.convertToFloat32
+	case "string":
+		return func(value any) (any, error) {
+			return s.convertToString(value), nil
+		}
+	case "bool", "boolean":
+		return s.convertToBool
+	case "[]byte", "binary":
+		return s.convertToBytes
+	case "time.Time":
+		return func(value any) (any, error) {
+			return s.convertToTime(value), nil
+		}
+	default:
+		// Para tipos não mapeados ou personalizados, aplica conversão básica
+		return func(value any) (any, error) {
+			return rawColumnValue(value), nil
+		}
+	}
+}
+
+// rawColumnValue retorna o valor da coluna sem conversão de tipo ([]byte vira string, exceto
+// para propriedades binárias, que usam propertyConverter)
+func rawColumnValue(value any) any {
+	if v, ok := value.([]byte); ok {
+		return string(v)
+	}
+	return value
+}
+
+// convertToTime serializa datas sempre em UTC; valores textuais (ex: SQLite) são
+// interpretados como UTC, o formato de gravação
+func (s *BaseEntityService) convertToTime(value any) any {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC()
+	case []byte:
+		return s.convertToTime(string(v))
+	case string:
+		if parsed, err := parseDateTimeLiteral(v, time.UTC); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
 // convertToInt64 converte valor para int64
 func (s *BaseEntityService) convertToInt64(value any) (any, error) {
 	switch v := value.(type) {
@@ -377,158 +420,6 @@ func (s *BaseEntityService) compareValues(left, right any, operator string) bool
 	return false
 }
 
-// =======================================================================================
-// COMPUTE PROCESSING
-// =======================================================================================
-
-// applyComputeToResults aplica campos computados aos resultados seguindo a ordem OData v4
-func (s *BaseEntityService) applyComputeToResults(ctx context.Context, results []any, computeOption *ComputeOption) ([]any, error) {
-	if computeOption == nil || len(computeOption.Expressions) == 0 {
-		return results, nil
-	}
-
-	// Para cada resultado, calcula os campos computados
-	for i, result := range results {
-		orderedEntity, ok := result.(*OrderedEntity)
-		if !ok {
-			continue
-		}
-
-		// Calcula cada expressão computada
-		for _, expr := range computeOption.Expressions {
-			computedValue, err := s.evaluateComputeExpression(ctx, expr, orderedEntity)
-			if err != nil {
-				return nil, fmt.Errorf("failed to evaluate compute expression '%s': %w", expr.Expression, err)
-			}
-
-			// Adiciona o campo computado ao resultado
-			orderedEntity.Set(expr.Alias, computedValue)
-		}
-
-		results[i] = orderedEntity
-	}
-
-	return results, nil
-}
-
-// evaluateComputeExpression avalia uma expressão computada
-func (s *BaseEntityService) evaluateComputeExpression(ctx context.Context, expr ComputeExpression, entity *OrderedEntity) (any, error) {
-	if expr.ParseTree == nil {
-		return nil, fmt.Errorf("compute expression has no parse tree")
-	}
-
-	return s.evaluateComputeNode(ctx, expr.ParseTree, entity)
-}
-
-// evaluateComputeNode avalia um nó da árvore de compute
-func (s *BaseEntityService) evaluateComputeNode(ctx context.Context, node *ParseNode, entity *OrderedEntity) (any, error) {
-	if node == nil {
-		return nil, fmt.Errorf("compute node is nil")
-	}
-
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	default:
-	}
-
-	switch node.Token.Type {
-	case int(FilterTokenProperty):
-		// Obtém valor da propriedade
-		value, exists := entity.Get(node.Token.Value)
-		if !exists {
-			return nil, fmt.Errorf("property %s not found", node.Token.Value)
-		}
-		return value, nil
-
-	case int(FilterTokenString):
-		// String literal
-		value := node.Token.Value
-		if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
-			value = value[1 : len(value)-1] // Remove aspas
-		}
-		return value, nil
-
-	case int(FilterTokenNumber):
-		// Número literal
-		return node.Token.Value, nil
-
-	case int(FilterTokenArithmetic):
-		// Operador aritmético
-		if len(node.Children) != 2 {
-			return nil, fmt.Errorf("arithmetic operator requires 2 operands")
-		}
-
-		left, err := s.evaluateComputeNode(ctx, node.Children[0], entity)
-		if err != nil {
-			return nil, err
-		}
-
-		right, err := s.evaluateComputeNode(ctx, node.Children[1], entity)
-		if err != nil {
-			return nil, err
-		}
-
-		return s.evaluateArithmeticOperation(node.Token.Value, left, right)
-
-	default:
-		return nil, fmt.Errorf("unsupported compute token type: %v", node.Token.Type)
-	}
-}
-
-// evaluateArithmeticOperation avalia operações aritméticas
-func (s *BaseEntityService) evaluateArithmeticOperation(operator string, left, right any) (any, error) {
-	// Converte para números
-	leftNum, err := s.convertToNumber(left)
-	if err != nil {
-		return nil, fmt.Errorf("left operand is not a number: %w", err)
-	}
-
-	rightNum, err := s.convertToNumber(right)
-	if err != nil {
-		return nil, fmt.Errorf("right operand is not a number: %w", err)
-	}
-
-	switch operator {
-	case "add":
-		return leftNum + rightNum, nil
-	case "sub":
-		return leftNum - rightNum, nil
-	case "mul":
-		return leftNum * rightNum, nil
-	case "div":
-		if rightNum == 0 {
-			return nil, fmt.Errorf("division by zero")
-		}
-		return leftNum / rightNum, nil
-	default:
-		return nil, fmt.Errorf("unsupported arithmetic operator: %s", operator)
-	}
-}
-
-// convertToNumber converte um valor para número
-func (s *BaseEntityService) convertToNumber(value any) (float64, error) {
-	switch v := value.(type) {
-	case int:
-		return float64(v), nil
-	case int32:
-		return float64(v), nil
-	case int64:
-		return float64(v), nil
-	case float32:
-		return float64(v), nil
-	case float64:
-		return v, nil
-	case string:
-		if num, err := strconv.ParseFloat(v, 64); err == nil {
-			return num, nil
-		}
-		return 0, fmt.Errorf("cannot convert string '%s' to number", v)
-	default:
-		return 0, fmt.Errorf("cannot convert %T to number", value)
-	}
-}
-
 // =======================================================================================
 // SELECT PROCESSING
 // =======================================================================================
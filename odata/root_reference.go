@@ -0,0 +1,228 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// =======================================================================================
+// REFERÊNCIAS $it E $root NAS EXPRESSÕES
+// =======================================================================================
+
+// resolveItReferences remove o prefixo $it/ das propriedades: fora de expressões lambda,
+// $it é a instância atual e $it/Name equivale a Name
+func resolveItReferences(tokens []*Token) error {
+	for _, token := range tokens {
+		if token.Type != int(FilterTokenProperty) || !strings.HasPrefix(token.Value, "$it") {
+			continue
+		}
+		path := strings.TrimPrefix(token.Value, "$it")
+		if path == "" {
+			return fmt.Errorf("$it must be followed by a property path (ex: $it/Name)")
+		}
+		token.Value = strings.TrimPrefix(path, "/")
+	}
+	return nil
+}
+
+// rootReference é uma referência $root/EntitySet(chave)/Propriedade resolvida: a coluna
+// da entidade referenciada, filtrada pelas colunas e valores da chave
+type rootReference struct {
+	table            string
+	column           string
+	keyColumn        []string
+	keyValue         []interface{}
+	softDeleteColumn string // Linhas excluídas logicamente não são referenciadas
+}
+
+// parseRootReference separa o entity set, o predicado de chave e o caminho da referência
+// $root/EntitySet(chave)/Propriedade
+func parseRootReference(value string) (entitySet, keyPredicate, path string, err error) {
+	reference := strings.TrimPrefix(value, "$root/")
+	start := strings.Index(reference, "(")
+	end := strings.LastIndex(reference, ")")
+	if start <= 0 || end < start || !strings.HasPrefix(reference[end+1:], "/") {
+		return "", "", "", fmt.Errorf("invalid $root reference %s: expected $root/EntitySet(key)/Property", value)
+	}
+	return reference[:start], reference[start : end+1], reference[end+2:], nil
+}
+
+// resolveRootFilter resolve as referências $root do filtro para a entidade e a coluna
+// referenciadas. A leitura da entidade referenciada passa pelas proteções de um GET direto
+// (checkRelatedRead): o $root não pode expor valores que o GET recusaria
+func (s *BaseEntityService) resolveRootFilter(ctx context.Context, filter *GoDataFilterQuery) (*GoDataFilterQuery, error) {
+	if filter == nil || filter.Tree == nil || !hasRootReference(filter.Tree) {
+		return filter, nil
+	}
+
+	var err error
+	tree := cloneParseTree(filter.Tree, nil)
+	walkParseTree(tree, func(node *ParseNode) {
+		if err != nil || node.Token == nil || node.Token.Type != int(FilterTokenRootReference) {
+			return
+		}
+		if _, resolved := node.Token.SemanticReference.(*rootReference); resolved {
+			return
+		}
+		var reference *rootReference
+		if reference, err = s.resolveRootReference(ctx, node.Token.Value, "$filter"); err == nil {
+			node.Token = &Token{Type: node.Token.Type, Value: node.Token.Value, SemanticType: node.Token.SemanticType, SemanticReference: reference}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GoDataFilterQuery{Tree: tree, RawValue: filter.RawValue}, nil
+}
+
+// resolveRootOrderBy resolve as referências $root das expressões do $orderby, com as mesmas
+// regras do $filter. O provider analisa o $orderby novamente ao montar o ORDER BY e usa as
+// subqueries retornadas, indexadas pelo valor da referência
+func (s *BaseEntityService) resolveRootOrderBy(ctx context.Context, orderBy string) (map[string]*rootReference, error) {
+	if !strings.Contains(orderBy, "$root") {
+		return nil, nil
+	}
+	expressions, err := NewODataParser().ParseOrderBy(orderBy)
+	if err != nil {
+		// O erro de sintaxe é informado pelo provider ao montar o ORDER BY
+		return nil, nil
+	}
+
+	references := make(map[string]*rootReference)
+	for _, expr := range expressions {
+		walkParseTree(expr.Expression, func(node *ParseNode) {
+			if err != nil || node.Token == nil || node.Token.Type != int(FilterTokenRootReference) {
+				return
+			}
+			if _, resolved := references[node.Token.Value]; resolved {
+				return
+			}
+			var reference *rootReference
+			if reference, err = s.resolveRootReference(ctx, node.Token.Value, "$orderby"); err == nil {
+				references[node.Token.Value] = reference
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return references, nil
+}
+
+// attachRootReferences associa às referências $root da expressão as subqueries resolvidas
+// pelo serviço, em uma cópia da árvore
+func attachRootReferences(tree *ParseNode, references map[string]*rootReference) *ParseNode {
+	if len(references) == 0 || !hasRootReference(tree) {
+		return tree
+	}
+	tree = cloneParseTree(tree, nil)
+	walkParseTree(tree, func(node *ParseNode) {
+		if node.Token == nil || node.Token.Type != int(FilterTokenRootReference) {
+			return
+		}
+		if reference, ok := references[node.Token.Value]; ok {
+			node.Token = &Token{Type: node.Token.Type, Value: node.Token.Value, SemanticType: node.Token.SemanticType, SemanticReference: reference}
+		}
+	})
+	return tree
+}
+
+// resolveRootReference resolve uma referência $root/EntitySet(chave)/Propriedade. Os erros
+// de referência apontam para a opção de consulta que a contém (target)
+func (s *BaseEntityService) resolveRootReference(ctx context.Context, value, target string) (*rootReference, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return invalidQueryOption(target, fmt.Sprintf(format, args...))
+	}
+
+	entitySet, keyPredicate, path, err := parseRootReference(value)
+	if err != nil {
+		return nil, invalid("%v", err)
+	}
+	if s.server == nil {
+		return nil, invalid("$root reference %s requires the entity service to resolve entity sets", value)
+	}
+	service := s.server.GetEntityService(entitySet)
+	if service == nil {
+		return nil, invalid("entity set %s referenced by %s not found", entitySet, value)
+	}
+	metadata, err := s.localEntityMetadata(entitySet)
+	if err != nil {
+		return nil, err
+	}
+
+	prop := findPropertyByName(metadata, path)
+	if prop == nil {
+		return nil, invalid("$root reference %s must end with a property of %s", value, entitySet)
+	}
+	keys, err := s.server.extractKeys(keyPredicate, metadata)
+	if err != nil {
+		return nil, invalid("invalid key in $root reference %s: %v", value, err)
+	}
+
+	if err := s.checkRelatedRead(ctx, relatedRead{
+		entityName: s.server.registeredEntityName(service, entitySet),
+		metadata:   metadata,
+		property:   prop,
+		keys:       keys,
+		path:       value,
+		target:     target,
+	}); err != nil {
+		return nil, err
+	}
+
+	reference := &rootReference{table: metadata.TableName, column: prop.ColumnName}
+	if reference.table == "" {
+		reference.table = metadata.Name
+	}
+	if reference.column == "" {
+		reference.column = prop.Name
+	}
+	for _, key := range metadata.Properties {
+		keyValue, ok := keys[key.Name]
+		if !key.IsKey || !ok {
+			continue
+		}
+		column := key.ColumnName
+		if column == "" {
+			column = key.Name
+		}
+		reference.keyColumn = append(reference.keyColumn, column)
+		reference.keyValue = append(reference.keyValue, keyValue)
+	}
+	if len(reference.keyColumn) == 0 {
+		return nil, invalid("invalid key in $root reference %s", value)
+	}
+	reference.softDeleteColumn = softDeleteColumn(metadata)
+	return reference, nil
+}
+
+// buildRootReference gera a subquery escalar da referência $root. A chave identifica no
+// máximo uma linha; sem ela (ou com a linha excluída logicamente) o valor é NULL
+func (qb *QueryBuilder) buildRootReference(node *ParseNode, placeholder func(value interface{}) string) (string, []interface{}, error) {
+	reference, ok := node.Token.SemanticReference.(*rootReference)
+	if !ok || reference == nil {
+		return "", nil, fmt.Errorf("$root reference %s must be resolved by the entity service", node.Token.Value)
+	}
+
+	conditions := make([]string, len(reference.keyColumn))
+	for i, column := range reference.keyColumn {
+		conditions[i] = fmt.Sprintf("%s = %s", column, placeholder(reference.keyValue[i]))
+	}
+	if reference.softDeleteColumn != "" {
+		conditions = append(conditions, reference.softDeleteColumn+" IS NULL")
+	}
+	return fmt.Sprintf("(SELECT %s FROM %s WHERE %s)", reference.column, reference.table, strings.Join(conditions, " AND ")),
+		append([]interface{}(nil), reference.keyValue...), nil
+}
+
+// hasRootReference verifica se a árvore possui referências $root
+func hasRootReference(node *ParseNode) bool {
+	found := false
+	walkParseTree(node, func(n *ParseNode) {
+		if n.Token != nil && n.Token.Type == int(FilterTokenRootReference) {
+			found = true
+		}
+	})
+	return found
+}
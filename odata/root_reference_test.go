@@ -0,0 +1,222 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter_ItAndRootReferences(t *testing.T) {
+	t.Run("$it é a instância atual", func(t *testing.T) {
+		filter, err := ParseFilterString(context.Background(), "$it/name eq 'Mouse' and $it/Category/name ne 'X'")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"name", "Category/name"}, GetFilterProperties(filter))
+	})
+
+	t.Run("$root vira operando", func(t *testing.T) {
+		filter, err := ParseFilterString(context.Background(), "price lt $root/Products(OrderID=7,Code='A(1)')/price")
+		require.NoError(t, err)
+		right := filter.Tree.Children[1].Token
+		assert.Equal(t, int(FilterTokenRootReference), right.Type)
+
+		entitySet, key, path, err := parseRootReference(right.Value)
+		require.NoError(t, err)
+		assert.Equal(t, "Products", entitySet)
+		assert.Equal(t, "(OrderID=7,Code='A(1)')", key)
+		assert.Equal(t, "price", path)
+	})
+
+	t.Run("referências inválidas", func(t *testing.T) {
+		for _, filter := range []string{"$it eq 1", "price lt $root/Products/price", "price lt $root/Products(1)", "$itself eq 1"} {
+			_, err := ParseFilterString(context.Background(), filter)
+			assert.Error(t, err, filter)
+		}
+	})
+
+	t.Run("$orderby com $it", func(t *testing.T) {
+		expressions, err := NewODataParser().ParseOrderBy("$it/price desc,$it/price mul 2")
+		require.NoError(t, err)
+		assert.Equal(t, "price", expressions[0].Property)
+		assert.Nil(t, expressions[0].Expression)
+		assert.NotNil(t, expressions[1].Expression)
+	})
+
+	t.Run("$orderby com $root", func(t *testing.T) {
+		expressions, err := NewODataParser().ParseOrderBy("round(price sub $root/Products(OrderID=7,Code='A')/price) desc,name")
+		require.NoError(t, err)
+		require.Len(t, expressions, 2)
+		assert.Equal(t, OrderDesc, expressions[0].Direction)
+		assert.True(t, hasRootReference(expressions[0].Expression))
+	})
+}
+
+func TestServer_FilterRootReference(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	t.Run("compara com o valor de outra entidade", func(t *testing.T) {
+		names := productNames(t, server, "$filter="+url.QueryEscape("$it/price gt $root/Products(2)/price")+"&$count=true")
+		assert.Equal(t, []string{"Monitor"}, names)
+
+		names = productNames(t, server, "$filter="+url.QueryEscape("price le $root/Products(2)/price")+"&$orderby="+url.QueryEscape("$it/price desc"))
+		assert.Equal(t, []string{"Teclado", "Mouse"}, names)
+	})
+
+	t.Run("erros de referência", func(t *testing.T) {
+		for _, filter := range []string{"price gt $root/Orders(1)/price", "price gt $root/Products(1)/cost"} {
+			status, body := getComputeTestProducts(t, server, "$filter="+url.QueryEscape(filter))
+			assert.Equal(t, http.StatusBadRequest, status, filter)
+			assert.Equal(t, "$filter", body["error"].(map[string]any)["target"], filter)
+		}
+	})
+
+	t.Run("ordena por expressões com o valor de outra entidade", func(t *testing.T) {
+		orderBy := "$orderby=" + url.QueryEscape("$it/price sub $root/Products(2)/price desc,name")
+		assert.Equal(t, []string{"Monitor", "Teclado", "Mouse"}, productNames(t, server, orderBy))
+
+		filter := "$filter=" + url.QueryEscape("price ne $root/Products(2)/price")
+		orderBy = "$orderby=" + url.QueryEscape("round(price div $root/Products(2)/price)")
+		assert.Equal(t, []string{"Mouse", "Monitor"}, productNames(t, server, filter+"&"+orderBy))
+	})
+
+	t.Run("erros de referência no $orderby", func(t *testing.T) {
+		for _, orderBy := range []string{"price sub $root/Orders(1)/price", "price sub $root/Products(1)/cost"} {
+			status, body := getComputeTestProducts(t, server, "$orderby="+url.QueryEscape(orderBy))
+			if assert.Equal(t, http.StatusBadRequest, status, orderBy) {
+				assert.Equal(t, "$orderby", body["error"].(map[string]any)["target"], orderBy)
+			}
+		}
+	})
+
+	t.Run("respeita a autorização da entidade referenciada", func(t *testing.T) {
+		server.entityAuthorizers = map[string][]EntityAuthorizer{
+			"Products": {func(ctx context.Context, request *AuthorizationRequest) error {
+				if request.Keys != nil && request.Keys["id"] == int64(3) {
+					return errors.New("produto restrito")
+				}
+				return nil
+			}},
+		}
+		t.Cleanup(func() { server.entityAuthorizers = nil })
+
+		status, _ := getComputeTestProducts(t, server, "$filter="+url.QueryEscape("price lt $root/Products(3)/price"))
+		assert.Equal(t, http.StatusForbidden, status)
+
+		names := productNames(t, server, "$filter="+url.QueryEscape("price lt $root/Products(2)/price"))
+		assert.Equal(t, []string{"Mouse"}, names)
+
+		status, _ = getComputeTestProducts(t, server, "$orderby="+url.QueryEscape("price sub $root/Products(3)/price"))
+		assert.Equal(t, http.StatusForbidden, status)
+	})
+}
+
+func TestServer_FilterRootReferenceProtections(t *testing.T) {
+	newServer := func(t *testing.T, configure func(metadata *EntityMetadata)) (*Server, **UserIdentity) {
+		t.Helper()
+		server := newBatchGetTestServer(t)
+		db := server.provider.GetConnection()
+		_, err := db.Exec("CREATE TABLE suppliers (id INTEGER PRIMARY KEY, name TEXT, price REAL, secret TEXT, deleted_at TEXT)")
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO suppliers (id, name, price, secret, deleted_at) VALUES (1, 'Ativo', 40, 'x', NULL), (2, 'Excluído', 60, 'y', '2024-01-01')")
+		require.NoError(t, err)
+
+		metadata := EntityMetadata{
+			Name:      "Suppliers",
+			TableName: "suppliers",
+			Keys:      []string{"id"},
+			Properties: []PropertyMetadata{
+				{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+				{Name: "name", ColumnName: "name", Type: "string"},
+				{Name: "price", ColumnName: "price", Type: "float64"},
+				{Name: "secret", ColumnName: "secret", Type: "string", IsEncrypted: true},
+				{Name: "deletedAt", ColumnName: "deleted_at", Type: "time.Time", IsNullable: true},
+			},
+		}
+		if configure != nil {
+			configure(&metadata)
+		}
+		server.entities["Suppliers"] = NewBaseEntityService(server.provider, metadata, server)
+
+		var user *UserIdentity
+		server.router = fiber.New()
+		server.router.Use(func(c fiber.Ctx) error {
+			if user != nil {
+				c.Locals(UserContextKey, user)
+			}
+			return c.Next()
+		})
+		server.setupEntityRoutes("Products")
+		return server, &user
+	}
+
+	assertInvalid := func(t *testing.T, server *Server, filter string) {
+		t.Helper()
+		status, body := getComputeTestProducts(t, server, "$filter="+url.QueryEscape(filter))
+		if assert.Equal(t, http.StatusBadRequest, status, filter) {
+			assert.Equal(t, "$filter", body["error"].(map[string]any)["target"], filter)
+		}
+	}
+
+	t.Run("recusa propriedades ocultas", func(t *testing.T) {
+		server, _ := newServer(t, func(metadata *EntityMetadata) {
+			metadata.ExposedProperties = []string{"name"}
+		})
+		assertInvalid(t, server, "price lt $root/Suppliers(1)/price")
+
+		names := productNames(t, server, "$filter="+url.QueryEscape("name ne $root/Suppliers(1)/name"))
+		assert.Len(t, names, 3)
+	})
+
+	t.Run("recusa propriedades criptografadas", func(t *testing.T) {
+		server, _ := newServer(t, nil)
+		assertInvalid(t, server, "name eq $root/Suppliers(1)/secret")
+	})
+
+	t.Run("recusa entidades com filtro padrão", func(t *testing.T) {
+		server, _ := newServer(t, func(metadata *EntityMetadata) {
+			metadata.DefaultFilter = "price gt 50"
+		})
+		assertInvalid(t, server, "price lt $root/Suppliers(1)/price")
+	})
+
+	t.Run("ignora linhas excluídas logicamente", func(t *testing.T) {
+		server, _ := newServer(t, func(metadata *EntityMetadata) {
+			metadata.SoftDeleteProperty = "deletedAt"
+		})
+		names := productNames(t, server, "$filter="+url.QueryEscape("price lt $root/Suppliers(1)/price"))
+		assert.Equal(t, []string{"Mouse"}, names)
+
+		names = productNames(t, server, "$filter="+url.QueryEscape("price lt $root/Suppliers(2)/price"))
+		assert.Empty(t, names, "a linha excluída não é referenciada")
+	})
+
+	t.Run("aplica a configuração de autenticação da entidade", func(t *testing.T) {
+		server, user := newServer(t, nil)
+		server.entityAuth["Suppliers"] = EntityAuthConfig{RequireAuth: true, RequiredRoles: []string{"compras"}}
+		filter := "$filter=" + url.QueryEscape("price lt $root/Suppliers(1)/price")
+
+		status, _ := getComputeTestProducts(t, server, filter)
+		assert.Equal(t, http.StatusUnauthorized, status)
+
+		*user = &UserIdentity{Username: "ana", Roles: []string{"vendas"}}
+		status, _ = getComputeTestProducts(t, server, filter)
+		assert.Equal(t, http.StatusForbidden, status)
+
+		*user = &UserIdentity{Username: "ana", Roles: []string{"compras"}}
+		assert.Equal(t, []string{"Mouse"}, productNames(t, server, filter))
+
+		server.entityAuth["Suppliers"] = EntityAuthConfig{RequireAuth: true, RequireAdmin: true}
+		status, _ = getComputeTestProducts(t, server, filter)
+		assert.Equal(t, http.StatusForbidden, status)
+
+		(*user).Admin = true
+		assert.Equal(t, []string{"Mouse"}, productNames(t, server, filter))
+	})
+}
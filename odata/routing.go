@@ -23,6 +23,14 @@ func (s *Server) setupBaseRoutes() {
 	// Rota para server info
 	s.router.Get("/info", s.handleServerInfo)
 
+	// Rotas de diagnóstico (pprof, goroutines e estatísticas) se habilitadas
+	s.setupDiagnosticsRoutes()
+
+	// JWKS quando os tokens são assinados com chaves assimétricas (RS256/ES256)
+	if s.config.JWTConfig != nil && s.config.JWTConfig.hasPublicKeys() {
+		s.ServeJWKS()
+	}
+
 	// Rotas específicas para multi-tenant
 	if s.multiTenantConfig != nil && s.multiTenantConfig.Enabled {
 		// Rota para informações dos tenants
@@ -36,9 +44,20 @@ func (s *Server) setupBaseRoutes() {
 	}
 }
 
-// setupEntityRoutes configura as rotas para uma entidade
+// setupEntityRoutes configura as rotas para uma entidade. Com o servidor em execução, as rotas
+// vão para o roteador dinâmico (a árvore de rotas do Fiber não pode mudar durante o Listen)
 func (s *Server) setupEntityRoutes(entityName string) {
-	prefix := s.config.RoutePrefix
+	if s.IsRunning() {
+		s.addDynamicEntityRoutes(entityName)
+		return
+	}
+	s.addEntityRoutes(s.router, entityName)
+}
+
+// addEntityRoutes registra as rotas da entidade no roteador
+func (s *Server) addEntityRoutes(router fiber.Router, entityName string) {
+	route := s.entityRoute(entityName)
+	prefix, setName := route.prefix, route.setName
 
 	// Obter configuração de autenticação da entidade (se houver)
 	entityAuth, hasAuth := s.GetEntityAuth(entityName)
@@ -57,7 +76,7 @@ func (s *Server) setupEntityRoutes(entityName string) {
 	if hasAuth && entityAuth.ReadOnly {
 		readOnlyMiddleware := func(c fiber.Ctx) error {
 			method := c.Method()
-			if method != "GET" && method != "OPTIONS" {
+			if method != "GET" && method != "HEAD" && method != "OPTIONS" {
 				return fiber.NewError(fiber.StatusForbidden, "Entidade "+entityName+" é apenas leitura")
 			}
 			return c.Next()
@@ -82,18 +101,18 @@ func (s *Server) setupEntityRoutes(entityName string) {
 	if isOperationAllowed("GET") {
 		if len(middlewares) > 0 {
 			// Fiber v3: handler PRIMEIRO, depois middlewares
-			s.router.Get(prefix+"/"+entityName, s.handleEntityCollection, middlewares...)
+			router.Get(prefix+"/"+setName, s.handleEntityCollection, middlewares...)
 		} else {
-			s.router.Get(prefix+"/"+entityName, s.handleEntityCollection)
+			router.Get(prefix+"/"+setName, s.handleEntityCollection)
 		}
 	}
 
 	if isOperationAllowed("POST") && !(hasAuth && entityAuth.ReadOnly) {
 		if len(middlewares) > 0 {
 			// Fiber v3: handler PRIMEIRO, depois middlewares
-			s.router.Post(prefix+"/"+entityName, s.handleEntityCollection, middlewares...)
+			router.Post(prefix+"/"+setName, s.handleEntityCollection, middlewares...)
 		} else {
-			s.router.Post(prefix+"/"+entityName, s.handleEntityCollection)
+			router.Post(prefix+"/"+setName, s.handleEntityCollection)
 		}
 	}
 
@@ -101,36 +120,36 @@ func (s *Server) setupEntityRoutes(entityName string) {
 	if isOperationAllowed("GET") {
 		if len(middlewares) > 0 {
 			// Fiber v3: handler PRIMEIRO, depois middlewares
-			s.router.Get(prefix+"/"+entityName+"(*)", s.handleEntityById, middlewares...)
+			router.Get(prefix+"/"+setName+"(*)", s.handleEntityById, middlewares...)
 		} else {
-			s.router.Get(prefix+"/"+entityName+"(*)", s.handleEntityById)
+			router.Get(prefix+"/"+setName+"(*)", s.handleEntityById)
 		}
 	}
 
 	if isOperationAllowed("PUT") && !(hasAuth && entityAuth.ReadOnly) {
 		if len(middlewares) > 0 {
 			// Fiber v3: handler PRIMEIRO, depois middlewares
-			s.router.Put(prefix+"/"+entityName+"(*)", s.handleEntityById, middlewares...)
+			router.Put(prefix+"/"+setName+"(*)", s.handleEntityById, middlewares...)
 		} else {
-			s.router.Put(prefix+"/"+entityName+"(*)", s.handleEntityById)
+			router.Put(prefix+"/"+setName+"(*)", s.handleEntityById)
 		}
 	}
 
 	if isOperationAllowed("PATCH") && !(hasAuth && entityAuth.ReadOnly) {
 		if len(middlewares) > 0 {
 			// Fiber v3: handler PRIMEIRO, depois middlewares
-			s.router.Patch(prefix+"/"+entityName+"(*)", s.handleEntityById, middlewares...)
+			router.Patch(prefix+"/"+setName+"(*)", s.handleEntityById, middlewares...)
 		} else {
-			s.router.Patch(prefix+"/"+entityName+"(*)", s.handleEntityById)
+			router.Patch(prefix+"/"+setName+"(*)", s.handleEntityById)
 		}
 	}
 
 	if isOperationAllowed("DELETE") && !(hasAuth && entityAuth.ReadOnly) {
 		if len(middlewares) > 0 {
 			// Fiber v3: handler PRIMEIRO, depois middlewares
-			s.router.Delete(prefix+"/"+entityName+"(*)", s.handleEntityById, middlewares...)
+			router.Delete(prefix+"/"+setName+"(*)", s.handleEntityById, middlewares...)
 		} else {
-			s.router.Delete(prefix+"/"+entityName+"(*)", s.handleEntityById)
+			router.Delete(prefix+"/"+setName+"(*)", s.handleEntityById)
 		}
 	}
 
@@ -138,15 +157,53 @@ func (s *Server) setupEntityRoutes(entityName string) {
 	if isOperationAllowed("GET") {
 		if len(middlewares) > 0 {
 			// Fiber v3: handler PRIMEIRO, depois middlewares
-			s.router.Get(prefix+"/"+entityName+"/$count", s.handleEntityCount, middlewares...)
+			router.Get(prefix+"/"+setName+"/$count", s.handleEntityCount, middlewares...)
 		} else {
-			s.router.Get(prefix+"/"+entityName+"/$count", s.handleEntityCount)
+			router.Get(prefix+"/"+setName+"/$count", s.handleEntityCount)
+		}
+	}
+
+	// Presets de consulta como funções vinculadas à coleção (GET /Entidade/Default.Preset())
+	if isOperationAllowed("GET") {
+		if service := s.GetEntityService(entityName); service != nil {
+			for _, preset := range service.GetMetadata().QueryPresets {
+				path := prefix + "/" + setName + queryPresetPath(preset.Name)
+				router.Get(path, s.handleQueryPreset(entityName, preset, false), middlewares...)
+				router.Get(path+"/$count", s.handleQueryPreset(entityName, preset, true), middlewares...)
+			}
 		}
 	}
 
-	// Rota OPTIONS para CORS se habilitado
-	if s.config.EnableCORS {
-		s.router.Options(prefix+"/"+entityName, s.handleOptions)
-		s.router.Options(prefix+"/"+entityName+"(*)", s.handleOptions)
+	// Lixeira das linhas excluídas logicamente (WithRecycleBin)
+	s.setupRecycleBinRoutes(router, entityName, prefix, setName, middlewares)
+
+	// Exportação assíncrona (WithExport)
+	if isOperationAllowed("GET") {
+		s.setupExportRoutes(router, entityName, prefix, setName, middlewares)
+	}
+
+	// Sincronização em lote (WithSync) e importação de arquivos (WithImport)
+	if isOperationAllowed("POST") && !(hasAuth && entityAuth.ReadOnly) {
+		s.setupSyncRoute(router, entityName, prefix, setName, middlewares)
+		s.setupImportRoute(router, entityName, prefix, setName, middlewares)
+	}
+
+	// OPTIONS informa os métodos aceitos (Allow); o preflight CORS é respondido pelo middleware
+	var collectionMethods, entityMethods []string
+	if isOperationAllowed("GET") {
+		collectionMethods = append(collectionMethods, fiber.MethodGet, fiber.MethodHead)
+		entityMethods = append(entityMethods, fiber.MethodGet, fiber.MethodHead)
+	}
+	if !(hasAuth && entityAuth.ReadOnly) {
+		if isOperationAllowed("POST") {
+			collectionMethods = append(collectionMethods, fiber.MethodPost)
+		}
+		for _, method := range []string{fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete} {
+			if isOperationAllowed(method) {
+				entityMethods = append(entityMethods, method)
+			}
+		}
 	}
+	router.Options(prefix+"/"+setName, s.handleEntityOptions(collectionMethods))
+	router.Options(prefix+"/"+setName+"(*)", s.handleEntityOptions(entityMethods))
 }
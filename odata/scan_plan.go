@@ -0,0 +1,151 @@
+package odata
+
+import (
+	"strings"
+)
+
+// =======================================================================================
+// PLANOS DE SCAN DE RESULTADOS
+// =======================================================================================
+
+// maxCachedScanPlans limita os planos guardados por entidade (cada combinação de $select gera
+// um conjunto de colunas diferente)
+const maxCachedScanPlans = 64
+
+// scanField mapeia uma coluna do resultado para uma propriedade da entidade
+type scanField struct {
+	column  int
+	name    string
+	convert valueConverter // nil para colunas sem propriedade nos metadados
+}
+
+// scanPlan guarda o mapeamento coluna → propriedade de um conjunto de colunas, resolvido uma
+// única vez em vez de a cada linha
+type scanPlan struct {
+	fields      []scanField
+	navigation  []PropertyMetadata
+	keyProperty string
+}
+
+// getScanPlan retorna o plano de scan das colunas, criando-o na primeira consulta com esse
+// conjunto de colunas
+func (s *BaseEntityService) getScanPlan(columns []string) *scanPlan {
+	cacheKey := strings.Join(columns, "\x00")
+
+	s.scanPlansMu.RLock()
+	plan, exists := s.scanPlans[cacheKey]
+	s.scanPlansMu.RUnlock()
+	if exists {
+		return plan
+	}
+
+	plan = s.buildScanPlan(columns)
+
+	s.scanPlansMu.Lock()
+	if s.scanPlans == nil {
+		s.scanPlans = make(map[string]*scanPlan)
+	}
+	if len(s.scanPlans) < maxCachedScanPlans {
+		s.scanPlans[cacheKey] = plan
+	}
+	s.scanPlansMu.Unlock()
+
+	return plan
+}
+
+// buildScanPlan resolve as colunas na ordem da resposta: primeiro as propriedades na ordem dos
+// metadados, depois as colunas que não estão nos metadados (ex: $compute)
+func (s *BaseEntityService) buildScanPlan(columns []string) *scanPlan {
+	plan := &scanPlan{fields: make([]scanField, 0, len(columns))}
+	added := make(map[string]bool, len(columns))
+
+	for _, prop := range s.metadata.Properties {
+		if prop.IsNavigation {
+			plan.navigation = append(plan.navigation, prop)
+			continue
+		}
+		if prop.IsKey && plan.keyProperty == "" {
+			plan.keyProperty = prop.Name
+		}
+
+		colName := prop.ColumnName
+		if colName == "" {
+			colName = prop.Name
+		}
+		for i, col := range columns {
+			if col == colName {
+				plan.fields = append(plan.fields, scanField{column: i, name: prop.Name, convert: s.propertyConverter(prop)})
+				added[prop.Name] = true
+				break
+			}
+		}
+	}
+
+	for i, col := range columns {
+		propName := s.getPropertyNameByColumn(col)
+		if propName == "" {
+			propName = col
+		}
+		if added[propName] {
+			continue
+		}
+
+		field := scanField{column: i, name: propName}
+		for _, prop := range s.metadata.Properties {
+			if strings.EqualFold(prop.Name, propName) || strings.EqualFold(prop.ColumnName, propName) {
+				field.convert = s.propertyConverter(prop)
+				break
+			}
+		}
+		plan.fields = append(plan.fields, field)
+		added[propName] = true
+	}
+
+	return plan
+}
+
+// scanEntity monta a entidade ordenada a partir dos valores de uma linha
+func (s *BaseEntityService) scanEntity(plan *scanPlan, values []any, expandOptions []ExpandOption) *OrderedEntity {
+	result := newOrderedEntityWithCapacity(len(plan.fields), len(plan.navigation))
+
+	// Os nomes do plano são únicos, então as propriedades são adicionadas sem busca
+	for _, field := range plan.fields {
+		value := values[field.column]
+		if value != nil {
+			if field.convert == nil {
+				value = rawColumnValue(value)
+			} else if converted, err := field.convert(value); err == nil {
+				value = converted
+			} else {
+				// Em caso de erro na conversão, usa o valor original como fallback
+				value = rawColumnValue(value)
+			}
+		}
+		result.Properties = append(result.Properties, OrderedProperty{Name: field.name, Value: value})
+		result.data[field.name] = value
+	}
+
+	// Só adiciona navigationLink se a propriedade NÃO está sendo expandida
+	for _, prop := range plan.navigation {
+		if isExpandedProperty(expandOptions, prop.Name) {
+			continue
+		}
+		var keyValue any
+		if plan.keyProperty != "" {
+			keyValue = result.data[plan.keyProperty]
+		}
+		result.SetNavigationProperty(prop.Name, s.buildNavigationLink(prop, keyValue))
+	}
+
+	return result
+}
+
+// isExpandedProperty verifica se a navegação está sendo expandida (case-insensitive)
+func isExpandedProperty(expandOptions []ExpandOption, name string) bool {
+	for _, expandOption := range expandOptions {
+		if strings.EqualFold(expandOption.Property, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package odata
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func newScanPlanTestService(t testing.TB, rows int) *BaseEntityService {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE products (id INTEGER PRIMARY KEY, product_name TEXT, price REAL, active INTEGER)")
+	require.NoError(t, err)
+	for i := 1; i <= rows; i++ {
+		_, err = db.Exec("INSERT INTO products VALUES (?, ?, ?, ?)", i, fmt.Sprintf("Produto %d", i), float64(i)*1.5, i%2)
+		require.NoError(t, err)
+	}
+
+	return NewBaseEntityService(NewMySQLProvider(db), EntityMetadata{
+		Name:      "Products",
+		TableName: "products",
+		Keys:      []string{"id"},
+		Properties: []PropertyMetadata{
+			{Name: "ID", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "Name", ColumnName: "product_name", Type: "string"},
+			{Name: "Price", ColumnName: "price", Type: "float64"},
+			{Name: "Active", ColumnName: "active", Type: "bool"},
+			{Name: "Category", IsNavigation: true, RelatedType: "Categories"},
+		},
+	}, nil)
+}
+
+func scanScanPlanQuery(t testing.TB, service *BaseEntityService, query string, expand []ExpandOption) []any {
+	t.Helper()
+	rows, err := service.provider.GetConnection().Query(query)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	results, err := service.scanRows(rows, expand)
+	require.NoError(t, err)
+	return results
+}
+
+func TestBaseEntityService_ScanRows_Plan(t *testing.T) {
+	service := newScanPlanTestService(t, 2)
+
+	t.Run("Ordem dos metadados, conversões e colunas extras", func(t *testing.T) {
+		results := scanScanPlanQuery(t, service, "SELECT price * 2 AS Doubled, active, product_name, id, price FROM products ORDER BY id", nil)
+		require.Len(t, results, 2)
+
+		entity := results[0].(*OrderedEntity)
+		names := make([]string, 0, len(entity.Properties))
+		for _, prop := range entity.Properties {
+			names = append(names, prop.Name)
+		}
+		assert.Equal(t, []string{"ID", "Name", "Price", "Active", "Doubled"}, names)
+
+		id, _ := entity.Get("ID")
+		active, _ := entity.Get("Active")
+		doubled, _ := entity.Get("Doubled")
+		assert.Equal(t, int64(1), id)
+		assert.Equal(t, true, active)
+		assert.Equal(t, float64(3), doubled)
+
+		require.Len(t, entity.NavigationLinks, 1)
+		assert.Equal(t, "/Products(1)/Category", entity.NavigationLinks[0].URL)
+	})
+
+	t.Run("Navegação expandida não gera link", func(t *testing.T) {
+		results := scanScanPlanQuery(t, service, "SELECT id FROM products", []ExpandOption{{Property: "category"}})
+		assert.Empty(t, results[0].(*OrderedEntity).NavigationLinks)
+	})
+
+	t.Run("Nulos e plano reaproveitado por conjunto de colunas", func(t *testing.T) {
+		_, err := service.provider.GetConnection().Exec("UPDATE products SET product_name = NULL WHERE id = 2")
+		require.NoError(t, err)
+
+		results := scanScanPlanQuery(t, service, "SELECT id, product_name FROM products ORDER BY id", nil)
+		name, exists := results[1].(*OrderedEntity).Get("Name")
+		assert.True(t, exists)
+		assert.Nil(t, name)
+
+		plan := service.getScanPlan([]string{"id", "product_name"})
+		assert.Same(t, plan, service.getScanPlan([]string{"id", "product_name"}))
+		assert.NotSame(t, plan, service.getScanPlan([]string{"id"}))
+	})
+
+	t.Run("Limite de planos em cache", func(t *testing.T) {
+		for i := 0; i < maxCachedScanPlans+10; i++ {
+			service.getScanPlan([]string{"id", fmt.Sprintf("c%d", i)})
+		}
+		assert.Len(t, service.scanPlans, maxCachedScanPlans)
+	})
+}
+
+func BenchmarkBaseEntityService_ScanRows(b *testing.B) {
+	service := newScanPlanTestService(b, 1000)
+	query := "SELECT " + strings.Join([]string{"id", "product_name", "price", "active"}, ", ") + " FROM products"
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanScanPlanQuery(b, service, query, nil)
+	}
+}
@@ -0,0 +1,127 @@
+package odata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// =======================================================================================
+// DDL GENERATION
+// =======================================================================================
+
+// GenerateDDL retorna os comandos CREATE TABLE/INDEX de todas as entidades registradas
+// usando o dialeto do provider informado (ou o provider padrão do servidor se nil).
+// Útil para pipelines de deploy revisarem o schema esperado pela aplicação.
+func (s *Server) GenerateDDL(provider DatabaseProvider) ([]string, error) {
+	if provider == nil {
+		provider = s.provider
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("nenhum provider informado para geração de DDL")
+	}
+
+	builder := newSchemaDDLBuilder(provider)
+
+	var statements []string
+	for _, name := range s.sortedEntityNames() {
+		service := s.GetEntityService(name)
+		if service == nil {
+			continue
+		}
+		metadata := service.GetMetadata()
+
+		statements = append(statements, builder.buildCreateTable(metadata))
+		statements = append(statements, builder.buildCreateIndexes(metadata)...)
+		if metadata.History {
+			history := historyMetadata(metadata)
+			statements = append(statements, builder.buildCreateTable(history))
+			statements = append(statements, builder.buildCreateIndexes(history)...)
+		}
+	}
+
+	return statements, nil
+}
+
+// GenerateDDLScript retorna o DDL como um script único separado por ';'
+func (s *Server) GenerateDDLScript(provider DatabaseProvider) (string, error) {
+	statements, err := s.GenerateDDL(provider)
+	if err != nil {
+		return "", err
+	}
+	if len(statements) == 0 {
+		return "", nil
+	}
+	return strings.Join(statements, ";\n") + ";\n", nil
+}
+
+// buildCreateIndexes gera índices únicos (prop Unique) e índices para chaves estrangeiras
+func (b *schemaDDLBuilder) buildCreateIndexes(metadata EntityMetadata) []string {
+	tableName := metadata.TableName
+	if tableName == "" {
+		tableName = metadata.Name
+	}
+
+	var statements []string
+	indexed := make(map[string]bool)
+
+	for _, prop := range metadata.Properties {
+		if prop.IsNavigation || prop.IsKey || !hasPropFlag(prop.PropFlags, "Unique") {
+			continue
+		}
+		column := columnNameOf(prop)
+		indexed[strings.ToLower(column)] = true
+		statements = append(statements, fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s)",
+			b.indexName("ux", tableName, column), b.qualifiedTableName(metadata), column))
+	}
+
+	// Chaves estrangeiras de associações N:1 ficam na tabela local
+	for _, prop := range metadata.Properties {
+		if !prop.IsNavigation || prop.Association == nil || prop.Association.ForeignKey == "" {
+			continue
+		}
+		column := prop.Association.ForeignKey
+		if indexed[strings.ToLower(column)] || !hasColumn(metadata, column) {
+			continue
+		}
+		indexed[strings.ToLower(column)] = true
+		statements = append(statements, fmt.Sprintf("CREATE INDEX %s ON %s (%s)",
+			b.indexName("ix", tableName, column), b.qualifiedTableName(metadata), column))
+	}
+
+	// Versões do histórico são buscadas pelas chaves da entidade e pelo fim da validade
+	if len(metadata.historyIndex) > 0 {
+		statements = append(statements, fmt.Sprintf("CREATE INDEX %s ON %s (%s)",
+			b.indexName("ix", tableName, historyValidToColumn), b.qualifiedTableName(metadata), strings.Join(metadata.historyIndex, ", ")))
+	}
+
+	return statements
+}
+
+// indexName gera um nome de índice respeitando o limite de identificadores do Oracle
+func (b *schemaDDLBuilder) indexName(prefix, tableName, column string) string {
+	name := strings.ToLower(fmt.Sprintf("%s_%s_%s", prefix, tableName, column))
+	if b.dialect == "oracle" && len(name) > 30 {
+		name = name[:30]
+	}
+	return name
+}
+
+// hasPropFlag verifica se uma flag da tag prop está presente (case-insensitive)
+func hasPropFlag(flags []string, want string) bool {
+	for _, f := range flags {
+		if strings.EqualFold(f, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasColumn verifica se a entidade possui uma coluna (não navegação) com o nome informado
+func hasColumn(metadata EntityMetadata, column string) bool {
+	for _, prop := range metadata.Properties {
+		if !prop.IsNavigation && strings.EqualFold(columnNameOf(prop), column) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package odata
+
+import (
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ddlCustomer struct {
+	TableName string    `table:"customers;schema=sales"`
+	ID        int64     `json:"id" column:"id" primaryKey:"idGenerator:auto"`
+	Email     string    `json:"email" column:"email" prop:"[required, Unique]; length:150"`
+	Balance   float64   `json:"balance" column:"balance" prop:"[required]; precision:12; scale:2; default:0"`
+	CreatedAt time.Time `json:"created_at" column:"created_at" prop:"[required]; default:CURRENT_TIMESTAMP"`
+	RegionID  int64     `json:"region_id" column:"region_id"`
+
+	Region *ddlRegion `json:"Region" association:"foreignKey:region_id; references:id"`
+}
+
+type ddlRegion struct {
+	ID   int64  `json:"id" column:"id" primaryKey:"idGenerator:auto"`
+	Name string `json:"name" column:"name" prop:"[required]; length:60"`
+}
+
+func newDDLTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := &Server{
+		entities:   make(map[string]EntityService),
+		logger:     log.New(os.Stdout, "[TEST] ", log.LstdFlags),
+		config:     DefaultServerConfig(),
+		entityAuth: make(map[string]EntityAuthConfig),
+	}
+	for name, entity := range map[string]interface{}{"Customers": ddlCustomer{}, "Regions": ddlRegion{}} {
+		metadata, err := MapEntityFromStruct(entity)
+		require.NoError(t, err)
+		server.entities[name] = NewBaseEntityService(nil, metadata, server)
+	}
+	return server
+}
+
+func TestGenerateDDL_PostgreSQL(t *testing.T) {
+	server := newDDLTestServer(t)
+	provider := &PostgreSQLProvider{BaseProvider: BaseProvider{driverName: "pgx"}}
+
+	statements, err := server.GenerateDDL(provider)
+	require.NoError(t, err)
+	require.Len(t, statements, 4)
+
+	customers := statements[0]
+	assert.True(t, strings.HasPrefix(customers, "CREATE TABLE sales.customers ("))
+	assert.Contains(t, customers, "id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL")
+	assert.Contains(t, customers, "email VARCHAR(150) NOT NULL")
+	assert.Contains(t, customers, "balance DECIMAL(12,2) DEFAULT 0 NOT NULL")
+	assert.Contains(t, customers, "created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL")
+	assert.Contains(t, customers, "PRIMARY KEY (id)")
+
+	assert.Equal(t, "CREATE UNIQUE INDEX ux_customers_email ON sales.customers (email)", statements[1])
+	assert.Equal(t, "CREATE INDEX ix_customers_region_id ON sales.customers (region_id)", statements[2])
+	assert.True(t, strings.HasPrefix(statements[3], "CREATE TABLE ddlregion ("))
+}
+
+func TestGenerateDDL_PropOptionOnlyTags(t *testing.T) {
+	type ddlProduct struct {
+		TableName  string `table:"products"`
+		ID         int64  `json:"id" column:"id" primaryKey:"idGenerator:auto"`
+		Category   string `json:"category" column:"category" prop:"length:100"`
+		SalesCount int    `json:"sales_count" column:"sales_count" prop:"default:0"`
+	}
+	metadata, err := MapEntityFromStruct(ddlProduct{})
+	require.NoError(t, err)
+
+	stmt := newSchemaDDLBuilder(&PostgreSQLProvider{BaseProvider: BaseProvider{driverName: "pgx"}}).buildCreateTable(metadata)
+	assert.Contains(t, stmt, "category VARCHAR(100)")
+	assert.Contains(t, stmt, "sales_count INTEGER DEFAULT 0")
+}
+
+func TestGenerateDDL_UsesServerProviderWhenNil(t *testing.T) {
+	server := newDDLTestServer(t)
+
+	_, err := server.GenerateDDL(nil)
+	assert.Error(t, err)
+
+	server.provider = &MySQLProvider{BaseProvider: BaseProvider{driverName: "mysql"}}
+	script, err := server.GenerateDDLScript(nil)
+	require.NoError(t, err)
+	assert.Contains(t, script, "id BIGINT AUTO_INCREMENT NOT NULL")
+	assert.True(t, strings.HasSuffix(script, ";\n"))
+}
+
+func TestMapEntity_PropOptions(t *testing.T) {
+	metadata, err := MapEntityFromStruct(ddlCustomer{})
+	require.NoError(t, err)
+
+	props := make(map[string]PropertyMetadata)
+	for _, p := range metadata.Properties {
+		props[p.Name] = p
+	}
+
+	assert.Equal(t, 150, props["email"].MaxLength)
+	assert.Contains(t, props["email"].PropFlags, "Unique")
+	assert.Equal(t, 12, props["balance"].Precision)
+	assert.Equal(t, 2, props["balance"].Scale)
+	assert.True(t, props["created_at"].HasDefault)
+	assert.Equal(t, "CURRENT_TIMESTAMP", props["created_at"].DefaultValue)
+}
@@ -0,0 +1,358 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// =======================================================================================
+// SCHEMA SYNC / AUTO-MIGRATION
+// =======================================================================================
+
+// AutoMigrateOptions controla o comportamento da sincronização de schema
+type AutoMigrateOptions struct {
+	DryRun       bool // Se true, apenas gera e loga o DDL sem executá-lo
+	CreateTables bool // Cria tabelas inexistentes
+	AddColumns   bool // Adiciona colunas que existem nos metadados mas não na tabela
+}
+
+// DefaultAutoMigrateOptions retorna opções padrão (cria tabelas e adiciona colunas)
+func DefaultAutoMigrateOptions() AutoMigrateOptions {
+	return AutoMigrateOptions{
+		DryRun:       false,
+		CreateTables: true,
+		AddColumns:   true,
+	}
+}
+
+// MigrationResult representa o resultado da sincronização de uma entidade
+type MigrationResult struct {
+	EntityName   string
+	TableName    string
+	TableCreated bool
+	AddedColumns []string
+	Statements   []string
+}
+
+// AutoMigrate sincroniza as tabelas do banco com os metadados das entidades registradas
+// Destinado a ambientes de desenvolvimento e protótipos - nunca remove colunas ou tabelas
+func (s *Server) AutoMigrate(ctx context.Context, opts AutoMigrateOptions) ([]MigrationResult, error) {
+	if s.provider == nil && len(s.GetNamedProviders()) == 0 {
+		return nil, fmt.Errorf("auto-migrate requer um provider configurado")
+	}
+
+	// Cada entidade é migrada no banco do seu provider (WithProvider)
+	builders := make(map[DatabaseProvider]*schemaDDLBuilder)
+
+	var results []MigrationResult
+	for _, name := range s.sortedEntityNames() {
+		service := s.GetEntityService(name)
+		if service == nil {
+			continue
+		}
+		metadata := service.GetMetadata()
+
+		provider := s.serviceProvider(service)
+		if provider == nil {
+			return results, fmt.Errorf("auto-migrate requer um provider configurado")
+		}
+		conn := provider.GetConnection()
+		if conn == nil && !opts.DryRun {
+			return results, fmt.Errorf("auto-migrate requer uma conexão de banco válida")
+		}
+		builder, ok := builders[provider]
+		if !ok {
+			builder = newSchemaDDLBuilder(provider)
+			builders[provider] = builder
+		}
+
+		result, err := s.migrateEntity(ctx, conn, builder, metadata, opts)
+		if err != nil {
+			return results, fmt.Errorf("erro ao migrar entidade %s: %w", name, err)
+		}
+		result.EntityName = name
+		results = append(results, result)
+
+		if metadata.History {
+			result, err := s.migrateEntity(ctx, conn, builder, historyMetadata(metadata), opts)
+			if err != nil {
+				return results, fmt.Errorf("erro ao migrar histórico da entidade %s: %w", name, err)
+			}
+			result.EntityName = name
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// migrateEntity cria a tabela ou adiciona colunas faltantes para uma entidade
+func (s *Server) migrateEntity(ctx context.Context, conn *sql.DB, builder *schemaDDLBuilder, metadata EntityMetadata, opts AutoMigrateOptions) (MigrationResult, error) {
+	result := MigrationResult{TableName: builder.qualifiedTableName(metadata)}
+
+	var existing map[string]bool
+	var err error
+	if conn != nil {
+		existing, err = builder.existingColumns(ctx, conn, metadata)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if existing == nil {
+		if !opts.CreateTables {
+			return result, nil
+		}
+		result.TableCreated = true
+		result.Statements = append(result.Statements, builder.buildCreateTable(metadata))
+		result.Statements = append(result.Statements, builder.buildCreateIndexes(metadata)...)
+	} else if opts.AddColumns {
+		for _, prop := range metadata.Properties {
+			if prop.IsNavigation {
+				continue
+			}
+			column := columnNameOf(prop)
+			if existing[strings.ToLower(column)] {
+				continue
+			}
+			result.AddedColumns = append(result.AddedColumns, column)
+			result.Statements = append(result.Statements, builder.buildAddColumn(metadata, prop))
+		}
+	}
+
+	for _, stmt := range result.Statements {
+		if opts.DryRun {
+			s.logger.Printf("📝 [AutoMigrate] (dry-run) %s", stmt)
+			continue
+		}
+		s.logger.Printf("🛠️  [AutoMigrate] %s", stmt)
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return result, fmt.Errorf("falha ao executar DDL '%s': %w", stmt, err)
+		}
+	}
+
+	return result, nil
+}
+
+// sortedEntityNames retorna os nomes das entidades em ordem determinística
+func (s *Server) sortedEntityNames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.entities))
+	for name := range s.entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runAutoMigrate executa a sincronização configurada em ServerConfig antes do start
+func (s *Server) runAutoMigrate(ctx context.Context) error {
+	if !s.config.AutoMigrate {
+		return nil
+	}
+
+	opts := DefaultAutoMigrateOptions()
+	opts.DryRun = s.config.AutoMigrateDryRun
+
+	results, err := s.AutoMigrate(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	for _, r := range results {
+		changed += len(r.Statements)
+	}
+	s.logger.Printf("✅ [AutoMigrate] %d entidades verificadas, %d comandos DDL gerados", len(results), changed)
+	return nil
+}
+
+// =======================================================================================
+// DDL BUILDER
+// =======================================================================================
+
+// schemaDDLBuilder gera DDL específico para o dialeto do provider
+type schemaDDLBuilder struct {
+	provider DatabaseProvider
+	dialect  string
+}
+
+// newSchemaDDLBuilder cria um builder de DDL para o provider
+func newSchemaDDLBuilder(provider DatabaseProvider) *schemaDDLBuilder {
+	return &schemaDDLBuilder{
+		provider: provider,
+		dialect:  GetDialect(provider.GetDriverName()).GetName(),
+	}
+}
+
+// qualifiedTableName retorna o nome da tabela com schema quando presente
+func (b *schemaDDLBuilder) qualifiedTableName(metadata EntityMetadata) string {
+	tableName := metadata.TableName
+	if tableName == "" {
+		tableName = metadata.Name
+	}
+	if metadata.Schema != "" {
+		return metadata.Schema + "." + tableName
+	}
+	return tableName
+}
+
+// existingColumns retorna as colunas existentes (em minúsculas) ou nil se a tabela não existe.
+// Demais erros (conexão, timeout, permissão) são retornados: tratá-los como tabela
+// inexistente geraria um CREATE TABLE sobre uma tabela existente
+func (b *schemaDDLBuilder) existingColumns(ctx context.Context, conn *sql.DB, metadata EntityMetadata) (map[string]bool, error) {
+	// SELECT sem linhas é portável entre os bancos suportados e expõe os nomes das colunas
+	query := fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", b.qualifiedTableName(metadata))
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("falha ao verificar a tabela %s: %w", b.qualifiedTableName(metadata), err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler colunas de %s: %w", metadata.TableName, err)
+	}
+
+	existing := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		existing[strings.ToLower(col)] = true
+	}
+	return existing, nil
+}
+
+// isTableNotFoundError reconhece o erro de tabela inexistente de cada banco suportado:
+// SQLSTATE 42P01 (PostgreSQL), 1146 (MySQL), ORA-00942 (Oracle), "no such table" (SQLite)
+// e "Invalid object name" (SQL Server)
+func isTableNotFoundError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "42P01"
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1146
+	}
+
+	message := err.Error()
+	return strings.Contains(message, "ORA-00942") ||
+		strings.Contains(message, "no such table") ||
+		strings.Contains(message, "Invalid object name")
+}
+
+// buildCreateTable gera o CREATE TABLE para uma entidade
+func (b *schemaDDLBuilder) buildCreateTable(metadata EntityMetadata) string {
+	var columns []string
+	var keys []string
+
+	for _, prop := range metadata.Properties {
+		if prop.IsNavigation {
+			continue
+		}
+		columns = append(columns, b.buildColumnDefinition(metadata, prop))
+		if prop.IsKey {
+			keys = append(keys, columnNameOf(prop))
+		}
+	}
+
+	if len(keys) > 0 {
+		columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(keys, ", ")))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (%s)", b.qualifiedTableName(metadata), strings.Join(columns, ", "))
+}
+
+// buildAddColumn gera o ALTER TABLE para adicionar uma coluna
+func (b *schemaDDLBuilder) buildAddColumn(metadata EntityMetadata, prop PropertyMetadata) string {
+	definition := b.buildColumnDefinition(metadata, prop)
+	if b.dialect == "oracle" {
+		return fmt.Sprintf("ALTER TABLE %s ADD (%s)", b.qualifiedTableName(metadata), definition)
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", b.qualifiedTableName(metadata), definition)
+}
+
+// buildColumnDefinition gera a definição de uma coluna (nome, tipo e restrições)
+func (b *schemaDDLBuilder) buildColumnDefinition(metadata EntityMetadata, prop PropertyMetadata) string {
+	definition := columnNameOf(prop) + " " + b.columnType(prop)
+
+	if b.isAutoIncrement(metadata, prop) {
+		switch b.dialect {
+		case "mysql":
+			definition += " AUTO_INCREMENT"
+		case "postgresql", "oracle":
+			definition += " GENERATED BY DEFAULT AS IDENTITY"
+		}
+	}
+
+	if prop.DefaultValue != "" {
+		definition += " DEFAULT " + prop.DefaultValue
+	}
+
+	if prop.IsKey || !prop.IsNullable {
+		definition += " NOT NULL"
+	}
+
+	return definition
+}
+
+// columnType resolve o tipo SQL de uma propriedade respeitando length/precision
+func (b *schemaDDLBuilder) columnType(prop PropertyMetadata) string {
+	switch prop.Type {
+	case "string":
+		length := prop.MaxLength
+		if length <= 0 {
+			if b.dialect == "oracle" {
+				length = 255
+			} else {
+				return b.provider.MapGoTypeToSQL(prop.Type)
+			}
+		}
+		if prop.IsEncrypted {
+			length = encryptedColumnLength(length)
+		}
+		if b.dialect == "oracle" {
+			return fmt.Sprintf("VARCHAR2(%d)", length)
+		}
+		return fmt.Sprintf("VARCHAR(%d)", length)
+	case "float32", "float64", "decimal":
+		if prop.Precision > 0 {
+			if b.dialect == "oracle" {
+				return fmt.Sprintf("NUMBER(%d,%d)", prop.Precision, prop.Scale)
+			}
+			return fmt.Sprintf("DECIMAL(%d,%d)", prop.Precision, prop.Scale)
+		}
+	}
+	return b.provider.MapGoTypeToSQL(prop.Type)
+}
+
+// isAutoIncrement verifica se a propriedade é uma chave inteira única gerada pelo banco
+func (b *schemaDDLBuilder) isAutoIncrement(metadata EntityMetadata, prop PropertyMetadata) bool {
+	if !prop.IsKey || len(metadata.Keys) > 1 {
+		return false
+	}
+	if prop.IDGenerator != "" && prop.IDGenerator != "auto" && prop.IDGenerator != "identity" {
+		return false
+	}
+	return prop.Type == "int" || prop.Type == "int32" || prop.Type == "int64"
+}
+
+// columnNameOf retorna o nome da coluna de uma propriedade
+func columnNameOf(prop PropertyMetadata) string {
+	if prop.ColumnName != "" {
+		return prop.ColumnName
+	}
+	return prop.Name
+}
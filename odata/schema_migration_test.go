@@ -0,0 +1,198 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v3"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func newMigrationTestServer(t *testing.T, db *sql.DB) *Server {
+	t.Helper()
+
+	provider := &MockDatabaseProvider{connection: db}
+	server := &Server{
+		provider:   provider,
+		entities:   make(map[string]EntityService),
+		logger:     log.New(os.Stdout, "[TEST] ", log.LstdFlags),
+		config:     DefaultServerConfig(),
+		entityAuth: make(map[string]EntityAuthConfig),
+	}
+
+	metadata := EntityMetadata{
+		Name:      "Products",
+		TableName: "products",
+		Keys:      []string{"ID"},
+		Properties: []PropertyMetadata{
+			{Name: "ID", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "Name", ColumnName: "name", Type: "string", MaxLength: 100},
+			{Name: "Price", ColumnName: "price", Type: "float64", Precision: 10, Scale: 2, IsNullable: true},
+			{Name: "Category", Type: "relationship", IsNavigation: true},
+		},
+	}
+	server.entities["Products"] = NewBaseEntityService(provider, metadata, server)
+	return server
+}
+
+func TestAutoMigrate_CreatesMissingTable(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	server := newMigrationTestServer(t, db)
+
+	results, err := server.AutoMigrate(context.Background(), DefaultAutoMigrateOptions())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	assert.True(t, results[0].TableCreated)
+	require.Len(t, results[0].Statements, 1)
+	stmt := results[0].Statements[0]
+	assert.Contains(t, stmt, "CREATE TABLE products")
+	assert.Contains(t, stmt, "name VARCHAR(100) NOT NULL")
+	assert.Contains(t, stmt, "price DECIMAL(10,2)")
+	assert.Contains(t, stmt, "PRIMARY KEY (id)")
+	assert.NotContains(t, stmt, "Category")
+
+	_, err = db.Exec("INSERT INTO products (id, name, price) VALUES (1, 'x', 1.5)")
+	assert.NoError(t, err)
+}
+
+func TestAutoMigrate_AddsMissingColumns(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE products (id BIGINT NOT NULL PRIMARY KEY, name VARCHAR(100))")
+	require.NoError(t, err)
+
+	server := newMigrationTestServer(t, db)
+
+	results, err := server.AutoMigrate(context.Background(), DefaultAutoMigrateOptions())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	assert.False(t, results[0].TableCreated)
+	assert.Equal(t, []string{"price"}, results[0].AddedColumns)
+	assert.Equal(t, "ALTER TABLE products ADD COLUMN price DECIMAL(10,2)", results[0].Statements[0])
+
+	_, err = db.Exec("SELECT price FROM products")
+	assert.NoError(t, err)
+}
+
+func TestAutoMigrate_DryRunDoesNotExecute(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	server := newMigrationTestServer(t, db)
+
+	opts := DefaultAutoMigrateOptions()
+	opts.DryRun = true
+	results, err := server.AutoMigrate(context.Background(), opts)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.NotEmpty(t, results[0].Statements)
+
+	_, err = db.Exec("SELECT * FROM products")
+	assert.Error(t, err, "dry-run não deve criar a tabela")
+}
+
+func TestAutoMigrate_QueryErrorIsNotMissingTable(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	server := newMigrationTestServer(t, db)
+	db.Close()
+
+	results, err := server.AutoMigrate(context.Background(), DefaultAutoMigrateOptions())
+	require.Error(t, err, "erro de conexão não pode virar CREATE TABLE")
+	for _, result := range results {
+		assert.False(t, result.TableCreated)
+	}
+}
+
+func TestIsTableNotFoundError(t *testing.T) {
+	assert.True(t, isTableNotFoundError(&pgconn.PgError{Code: "42P01", Message: `relation "products" does not exist`}))
+	assert.False(t, isTableNotFoundError(&pgconn.PgError{Code: "42501", Message: "permission denied for table products"}))
+	assert.True(t, isTableNotFoundError(fmt.Errorf("query: %w", &mysql.MySQLError{Number: 1146, Message: "Table 'app.products' doesn't exist"})))
+	assert.False(t, isTableNotFoundError(&mysql.MySQLError{Number: 1142, Message: "SELECT command denied"}))
+	assert.True(t, isTableNotFoundError(errors.New("ORA-00942: table or view does not exist")))
+	assert.False(t, isTableNotFoundError(errors.New("ORA-12170: TNS:Connect timeout occurred")))
+	assert.True(t, isTableNotFoundError(errors.New("SQL logic error: no such table: products (1)")))
+	assert.False(t, isTableNotFoundError(context.DeadlineExceeded))
+	assert.False(t, isTableNotFoundError(sql.ErrConnDone))
+}
+
+func TestServer_StartWithAutoMigrate(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	server := newMigrationTestServer(t, db)
+	server.router = fiber.New()
+	server.config.Host = "127.0.0.1"
+	server.config.Port = 0
+	server.config.AutoMigrate = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- server.startWithContext(ctx) }()
+
+	require.Eventually(t, server.IsRunning, 5*time.Second, 10*time.Millisecond, "Start não deve travar com AutoMigrate")
+
+	_, err = db.Exec("SELECT id, name, price FROM products")
+	assert.NoError(t, err, "a tabela deve ser criada antes do start")
+
+	require.NoError(t, server.Shutdown())
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("servidor não encerrou")
+	}
+}
+
+func TestSchemaDDLBuilder_Dialects(t *testing.T) {
+	metadata := EntityMetadata{
+		Name:      "Users",
+		TableName: "users",
+		Schema:    "app",
+		Keys:      []string{"ID"},
+		Properties: []PropertyMetadata{
+			{Name: "ID", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "Email", ColumnName: "email", Type: "string"},
+		},
+	}
+
+	t.Run("PostgreSQL identity", func(t *testing.T) {
+		builder := newSchemaDDLBuilder(&PostgreSQLProvider{BaseProvider: BaseProvider{driverName: "pgx"}})
+		stmt := builder.buildCreateTable(metadata)
+		assert.True(t, strings.HasPrefix(stmt, "CREATE TABLE app.users"))
+		assert.Contains(t, stmt, "id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL")
+		assert.Contains(t, stmt, "email TEXT NOT NULL")
+	})
+
+	t.Run("MySQL auto increment", func(t *testing.T) {
+		builder := newSchemaDDLBuilder(&MySQLProvider{BaseProvider: BaseProvider{driverName: "mysql"}})
+		assert.Contains(t, builder.buildCreateTable(metadata), "id BIGINT AUTO_INCREMENT NOT NULL")
+	})
+
+	t.Run("Oracle add column", func(t *testing.T) {
+		builder := newSchemaDDLBuilder(&OracleProvider{BaseProvider: &BaseProvider{driverName: "oracle"}})
+		stmt := builder.buildAddColumn(metadata, metadata.Properties[1])
+		assert.Equal(t, "ALTER TABLE app.users ADD (email VARCHAR2(255) NOT NULL)", stmt)
+	})
+}
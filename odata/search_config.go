@@ -0,0 +1,79 @@
+package odata
+
+import (
+	"net/http"
+	"strings"
+)
+
+// =======================================================================================
+// CONFIGURAÇÃO DE $SEARCH POR ENTIDADE
+// =======================================================================================
+
+// SearchConfig configura o $search de uma entidade
+type SearchConfig struct {
+	// Propriedades pesquisáveis usadas quando nenhuma propriedade da entidade tem a tag
+	// odata:"searchable" (ex: entidades registradas sem struct ou de bibliotecas externas)
+	Properties []string
+
+	// Estratégia de busca (padrão: fulltext quando o banco suporta, senão like)
+	Strategy SearchStrategy
+
+	// Normalização: compara sem diferenciar maiúsculas/minúsculas e/ou acentos
+	IgnoreCase    bool
+	IgnoreAccents bool
+
+	// Idioma do full-text search no PostgreSQL (padrão: english; ex: portuguese)
+	Language string
+}
+
+// WithSearchableProperties define as propriedades pesquisáveis da entidade quando a struct
+// não usa a tag odata:"searchable"
+func WithSearchableProperties(properties ...string) EntityOption {
+	return func(config *EntityConfig) {
+		if config.Search == nil {
+			config.Search = &SearchConfig{}
+		}
+		config.Search.Properties = append(config.Search.Properties, properties...)
+	}
+}
+
+// WithSearch define a configuração de $search da entidade (estratégia, normalização e
+// propriedades pesquisáveis quando a struct não usa a tag odata:"searchable")
+func WithSearch(search *SearchConfig) EntityOption {
+	return func(config *EntityConfig) {
+		config.Search = search
+	}
+}
+
+// SearchableProperties retorna as propriedades que participam do $search: as marcadas com
+// odata:"searchable" ou, na ausência da tag, as listadas no SearchConfig da entidade.
+// Sem nenhuma das duas a entidade não é pesquisável (evita varrer colunas de texto grandes)
+func SearchableProperties(metadata EntityMetadata) []PropertyMetadata {
+	var searchableProps []PropertyMetadata
+	for _, prop := range metadata.Properties {
+		if prop.IsSearchable && !prop.IsNavigation {
+			searchableProps = append(searchableProps, prop)
+		}
+	}
+	if len(searchableProps) > 0 || metadata.Search == nil {
+		return searchableProps
+	}
+
+	for _, name := range metadata.Search.Properties {
+		for _, prop := range metadata.Properties {
+			if strings.EqualFold(prop.Name, name) && !prop.IsNavigation {
+				searchableProps = append(searchableProps, prop)
+				break
+			}
+		}
+	}
+	return searchableProps
+}
+
+// errNoSearchableProperties indica que a entidade não tem propriedades pesquisáveis
+func errNoSearchableProperties(entityName string) *ODataError {
+	return NewODataError("SearchNotSupported",
+		"Entity '"+entityName+"' has no searchable properties").
+		WithStatus(http.StatusBadRequest).
+		WithTarget("$search")
+}
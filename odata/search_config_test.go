@@ -0,0 +1,257 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+type searchTestArticle struct {
+	ID      int64  `json:"id" primaryKey:"idGenerator:none"`
+	Title   string `json:"title" odata:"filterable,sortable,searchable"`
+	Summary string `json:"summary" odata:"searchable; length:200"`
+	Body    string `json:"body"`
+}
+
+func TestMapEntity_SearchableTag(t *testing.T) {
+	metadata, err := MapEntityFromStruct(searchTestArticle{})
+	require.NoError(t, err)
+
+	searchable := map[string]bool{}
+	for _, prop := range metadata.Properties {
+		searchable[prop.Name] = prop.IsSearchable
+	}
+	assert.True(t, searchable["title"])
+	assert.True(t, searchable["summary"])
+	assert.False(t, searchable["body"])
+
+	var names []string
+	for _, prop := range SearchableProperties(metadata) {
+		names = append(names, prop.Name)
+	}
+	assert.Equal(t, []string{"title", "summary"}, names)
+}
+
+func TestSearchableProperties(t *testing.T) {
+	properties := []PropertyMetadata{
+		{Name: "Name", ColumnName: "name", Type: "string"},
+		{Name: "Notes", ColumnName: "notes", Type: "string"},
+		{Name: "Category", Type: "Category", IsNavigation: true},
+	}
+
+	t.Run("Sem tag nem configuração não há propriedades pesquisáveis", func(t *testing.T) {
+		metadata := EntityMetadata{Name: "Products", Properties: properties}
+		assert.Empty(t, SearchableProperties(metadata))
+	})
+
+	t.Run("Configuração da entidade como fallback", func(t *testing.T) {
+		config := &EntityConfig{}
+		WithSearchableProperties("name", "Category")(config)
+
+		metadata := EntityMetadata{Name: "Products", Properties: properties, Search: config.Search}
+		props := SearchableProperties(metadata)
+		require.Len(t, props, 1)
+		assert.Equal(t, "Name", props[0].Name)
+	})
+
+	t.Run("Tag tem precedência sobre a configuração", func(t *testing.T) {
+		tagged := append([]PropertyMetadata{}, properties...)
+		tagged[1].IsSearchable = true
+
+		metadata := EntityMetadata{Name: "Products", Properties: tagged, Search: &SearchConfig{Properties: []string{"Name"}}}
+		props := SearchableProperties(metadata)
+		require.Len(t, props, 1)
+		assert.Equal(t, "Notes", props[0].Name)
+	})
+}
+
+func TestQueryBuilder_BuildSearchSQL_SearchableOnly(t *testing.T) {
+	metadata, err := MapEntityFromStruct(searchTestArticle{})
+	require.NoError(t, err)
+
+	qb := NewQueryBuilder("default")
+	search, err := NewSearchParser().ParseSearch(context.Background(), "golang")
+	require.NoError(t, err)
+
+	sqlStr, args, err := qb.BuildSearchSQL(context.Background(), search, metadata)
+	require.NoError(t, err)
+	assert.Contains(t, sqlStr, "title")
+	assert.Contains(t, sqlStr, "summary")
+	assert.NotContains(t, sqlStr, "body")
+	assert.Len(t, args, 2)
+
+	_, _, err = qb.BuildSearchSQL(context.Background(), search, EntityMetadata{Name: "Logs", Properties: []PropertyMetadata{{Name: "message", Type: "string"}}})
+	var odataErr *ODataError
+	require.ErrorAs(t, err, &odataErr)
+	assert.Equal(t, "SearchNotSupported", odataErr.Code)
+	assert.Equal(t, 400, odataErr.Status)
+}
+
+func TestBaseEntityService_QueryWithSearch(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE searchtestarticle (id INTEGER PRIMARY KEY, title TEXT, summary TEXT, body TEXT)")
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO searchtestarticle (id, title, summary, body) VALUES
+		(1, 'Go na prática', 'Concorrência', 'texto'),
+		(2, 'Bancos de dados', 'SQL', 'menciona golang apenas no corpo')`)
+	require.NoError(t, err)
+
+	metadata, err := MapEntityFromStruct(searchTestArticle{})
+	require.NoError(t, err)
+	metadata.TableName = "searchtestarticle"
+
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+	server := &Server{config: DefaultServerConfig(), logger: logger, eventManager: NewEntityEventManager(logger)}
+	provider := &MySQLProvider{BaseProvider: BaseProvider{db: db, driverName: "sqlite"}}
+	service := NewBaseEntityService(provider, metadata, server)
+
+	response, err := service.Query(context.Background(), QueryOptions{Search: &SearchOption{RawQuery: "go*"}})
+	require.NoError(t, err)
+	results := response.Value.([]any)
+	require.Len(t, results, 1, "a coluna body não é pesquisável")
+
+	value, _ := results[0].(*OrderedEntity).Get("id")
+	assert.EqualValues(t, 1, value)
+}
+
+func TestQueryBuilder_SearchStrategies(t *testing.T) {
+	accentless := &SearchConfig{IgnoreCase: true, IgnoreAccents: true}
+	column := "name"
+
+	t.Run("Sem configuração mantém o full-text do dialeto", func(t *testing.T) {
+		condition, param := NewQueryBuilder("postgresql").buildSearchCondition(column, "joão", false, nil)
+		assert.Equal(t, "to_tsvector('english', name) @@ plainto_tsquery('english', ?)", condition)
+		assert.Equal(t, "joão", param)
+	})
+
+	t.Run("Full-text no PostgreSQL com idioma e unaccent", func(t *testing.T) {
+		config := &SearchConfig{Strategy: SearchStrategyFullText, IgnoreAccents: true, Language: "portuguese"}
+		condition, param := NewQueryBuilder("postgresql").buildSearchCondition(column, "São Paulo", true, config)
+		assert.Equal(t, "to_tsvector('portuguese', odata_unaccent(name)) @@ phraseto_tsquery('portuguese', ?)", condition)
+		assert.Equal(t, "Sao Paulo", param)
+	})
+
+	t.Run("Idioma inválido volta ao padrão", func(t *testing.T) {
+		config := &SearchConfig{Language: "english'); DROP TABLE x; --"}
+		assert.Equal(t, "english", config.searchLanguage())
+	})
+
+	t.Run("Trigram no PostgreSQL usa similaridade", func(t *testing.T) {
+		config := &SearchConfig{Strategy: SearchStrategyTrigram, IgnoreCase: true, IgnoreAccents: true}
+		condition, param := NewQueryBuilder("postgresql").buildSearchCondition(column, "João", false, config)
+		assert.Equal(t, "LOWER(odata_unaccent(name)) % ?", condition)
+		assert.Equal(t, "joao", param)
+
+		condition, param = NewQueryBuilder("postgresql").buildSearchCondition(column, "Jo*", false, config)
+		assert.Equal(t, "LOWER(odata_unaccent(name)) LIKE ?", condition)
+		assert.Equal(t, "jo%", param)
+	})
+
+	t.Run("Trigram sem pg_trgm usa LIKE", func(t *testing.T) {
+		config := &SearchConfig{Strategy: SearchStrategyTrigram}
+		condition, param := NewQueryBuilder("oracle").buildSearchCondition(column, "joão", false, config)
+		assert.Equal(t, "name LIKE ?", condition)
+		assert.Equal(t, "%joão%", param)
+	})
+
+	t.Run("LIKE normalizado por dialeto", func(t *testing.T) {
+		config := &SearchConfig{Strategy: SearchStrategyLike, IgnoreCase: true, IgnoreAccents: true}
+
+		condition, param := NewQueryBuilder("mysql").buildSearchCondition(column, "João", false, config)
+		assert.Equal(t, "name COLLATE utf8mb4_0900_ai_ci LIKE ?", condition)
+		assert.Equal(t, "%joao%", param)
+
+		condition, _ = NewQueryBuilder("oracle").buildSearchCondition(column, "João", false, config)
+		assert.Equal(t, "LOWER(TRANSLATE(name, '"+accentedChars+"', '"+unaccentedChars+"')) LIKE ?", condition)
+
+		condition, _ = NewQueryBuilder("default").buildSearchCondition(column, "João", false, accentless)
+		assert.True(t, strings.HasPrefix(condition, "LOWER(REPLACE("))
+	})
+
+	t.Run("Remoção de acentos", func(t *testing.T) {
+		assert.Equal(t, "Joao Conceicao Acucar", removeAccents("João Conceição Açúcar"))
+		assert.Equal(t, len([]rune(accentedChars)), len([]rune(unaccentedChars)))
+	})
+}
+
+func TestBaseEntityService_QueryWithAccentInsensitiveSearch(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE searchtestarticle (id INTEGER PRIMARY KEY, title TEXT, summary TEXT, body TEXT)")
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO searchtestarticle (id, title, summary, body) VALUES
+		(1, 'Visita a SÃO JOÃO', 'Festa junina', ''),
+		(2, 'Conceição', 'Açúcar e café', ''),
+		(3, 'Outro assunto', 'Nada relacionado', '')`)
+	require.NoError(t, err)
+
+	metadata, err := MapEntityFromStruct(searchTestArticle{})
+	require.NoError(t, err)
+	metadata.TableName = "searchtestarticle"
+
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+	server := &Server{config: DefaultServerConfig(), logger: logger, eventManager: NewEntityEventManager(logger)}
+	provider := &MySQLProvider{BaseProvider: BaseProvider{db: db, driverName: "sqlite"}}
+
+	search := func(t *testing.T, config *SearchConfig, raw string) []any {
+		t.Helper()
+		metadata.Search = config
+		service := NewBaseEntityService(provider, metadata, server)
+		response, err := service.Query(context.Background(), QueryOptions{Search: &SearchOption{RawQuery: raw}})
+		require.NoError(t, err)
+		return response.Value.([]any)
+	}
+
+	assert.Empty(t, search(t, nil, "joao"), "sem normalização a busca diferencia acentos")
+
+	config := &SearchConfig{Strategy: SearchStrategyLike, IgnoreCase: true, IgnoreAccents: true}
+	results := search(t, config, "joao")
+	require.Len(t, results, 1)
+	value, _ := results[0].(*OrderedEntity).Get("id")
+	assert.EqualValues(t, 1, value)
+
+	assert.Len(t, search(t, config, "ACUCAR OR \"sao joao\""), 2)
+}
+
+func TestServer_GenerateSearchIndexDDL(t *testing.T) {
+	server := NewServer()
+	type searchIndexCustomer struct {
+		ID   int64  `json:"id" primaryKey:"idGenerator:none"`
+		Name string `json:"name" odata:"searchable"`
+	}
+	require.NoError(t, server.RegisterEntity("Articles", searchTestArticle{}))
+	require.NoError(t, server.RegisterEntity("Customers", searchIndexCustomer{},
+		WithSearch(&SearchConfig{Strategy: SearchStrategyTrigram, IgnoreCase: true, IgnoreAccents: true})))
+
+	t.Run("PostgreSQL", func(t *testing.T) {
+		statements, err := server.GenerateSearchIndexDDL(&PostgreSQLProvider{BaseProvider: BaseProvider{driverName: "pgx"}})
+		require.NoError(t, err)
+		assert.Contains(t, statements, "CREATE INDEX fts_searchtestarticle_title ON searchtestarticle USING gin (to_tsvector('english', title))")
+		assert.Contains(t, statements, "CREATE EXTENSION IF NOT EXISTS pg_trgm")
+		assert.Contains(t, statements, "CREATE EXTENSION IF NOT EXISTS unaccent")
+		assert.Contains(t, statements, "CREATE INDEX trgm_searchindexcustomer_name ON searchindexcustomer USING gin ((LOWER(odata_unaccent(name))) gin_trgm_ops)")
+	})
+
+	t.Run("MySQL", func(t *testing.T) {
+		statements, err := server.GenerateSearchIndexDDL(&MySQLProvider{BaseProvider: BaseProvider{driverName: "mysql"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"CREATE FULLTEXT INDEX fts_searchtestarticle_title ON searchtestarticle (title)",
+			"CREATE FULLTEXT INDEX fts_searchtestarticle_summary ON searchtestarticle (summary)",
+		}, statements)
+	})
+}
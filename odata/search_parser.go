@@ -400,36 +400,10 @@ func (p *SearchParser) isValidWildcard(pattern string) bool {
 	return true
 }
 
-// GetSearchableProperties retorna propriedades que podem ser pesquisadas
+// GetSearchableProperties retorna propriedades que podem ser pesquisadas (tag
+// odata:"searchable" ou SearchConfig da entidade)
 func (p *SearchParser) GetSearchableProperties(metadata EntityMetadata) []PropertyMetadata {
-	var searchableProps []PropertyMetadata
-
-	for _, prop := range metadata.Properties {
-		// Apenas propriedades de texto são pesquisáveis por padrão
-		if p.isSearchableType(prop.Type) && !prop.IsNavigation {
-			searchableProps = append(searchableProps, prop)
-		}
-	}
-
-	return searchableProps
-}
-
-// isSearchableType verifica se um tipo é pesquisável
-func (p *SearchParser) isSearchableType(propType string) bool {
-	searchableTypes := []string{
-		"string", "text", "varchar", "nvarchar", "char", "nchar",
-		"clob", "nclob", "longtext", "mediumtext", "tinytext",
-	}
-
-	propTypeLower := strings.ToLower(propType)
-
-	for _, searchableType := range searchableTypes {
-		if strings.Contains(propTypeLower, searchableType) {
-			return true
-		}
-	}
-
-	return false
+	return SearchableProperties(metadata)
 }
 
 // ExtractSearchTerms extrai todos os termos de busca de uma expressão
@@ -0,0 +1,295 @@
+package odata
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// =======================================================================================
+// ESTRATÉGIAS DE $SEARCH (FULLTEXT, TRIGRAM, LIKE) E NORMALIZAÇÃO
+// =======================================================================================
+
+// SearchStrategy define como os termos do $search são comparados no banco
+type SearchStrategy string
+
+const (
+	// SearchStrategyFullText usa o full-text search nativo (tsvector, MATCH, CONTAINS)
+	SearchStrategyFullText SearchStrategy = "fulltext"
+	// SearchStrategyTrigram usa similaridade por trigramas (pg_trgm); LIKE nos demais bancos
+	SearchStrategyTrigram SearchStrategy = "trigram"
+	// SearchStrategyLike usa LIKE por substring, portável entre todos os bancos
+	SearchStrategyLike SearchStrategy = "like"
+)
+
+// defaultSearchLanguage é o idioma do full-text search do PostgreSQL sem configuração
+const defaultSearchLanguage = "english"
+
+// postgresUnaccentFunction envolve unaccent() numa função IMMUTABLE, exigida para que a
+// mesma expressão possa ser indexada (criada por GenerateSearchIndexDDL)
+const postgresUnaccentFunction = "odata_unaccent"
+
+// mysqlAccentInsensitiveCollation ignora acentos e maiúsculas/minúsculas (MySQL 8+)
+const mysqlAccentInsensitiveCollation = "utf8mb4_0900_ai_ci"
+
+// Caracteres acentuados e seus equivalentes sem acento, na mesma posição
+const (
+	accentedChars   = "ÁÀÂÃÄÉÈÊËÍÌÎÏÓÒÔÕÖÚÙÛÜÇÑáàâãäéèêëíìîïóòôõöúùûüçñ"
+	unaccentedChars = "AAAAAEEEEIIIIOOOOOUUUUCNaaaaaeeeeiiiiooooouuuucn"
+)
+
+var accentReplacements = func() map[rune]rune {
+	from, to := []rune(accentedChars), []rune(unaccentedChars)
+	replacements := make(map[rune]rune, len(from))
+	for i := range from {
+		replacements[from[i]] = to[i]
+	}
+	return replacements
+}()
+
+// removeAccents remove os acentos de um texto usando a mesma tabela aplicada no SQL
+func removeAccents(value string) string {
+	return strings.Map(func(r rune) rune {
+		if plain, ok := accentReplacements[r]; ok {
+			return plain
+		}
+		return r
+	}, value)
+}
+
+// searchLanguage retorna o idioma do full-text search (apenas letras, evitando injeção)
+func (c *SearchConfig) searchLanguage() string {
+	if c == nil || c.Language == "" {
+		return defaultSearchLanguage
+	}
+	for _, r := range c.Language {
+		if !unicode.IsLetter(r) && r != '_' {
+			return defaultSearchLanguage
+		}
+	}
+	return strings.ToLower(c.Language)
+}
+
+// resolveSearchStrategy retorna a estratégia efetiva para o dialeto do builder
+func (qb *QueryBuilder) resolveSearchStrategy(config *SearchConfig) SearchStrategy {
+	strategy := SearchStrategy("")
+	if config != nil {
+		strategy = SearchStrategy(strings.ToLower(string(config.Strategy)))
+	}
+
+	switch strategy {
+	case SearchStrategyTrigram, SearchStrategyLike:
+		return strategy
+	}
+	if qb.supportsFullTextSearch() {
+		return SearchStrategyFullText
+	}
+	return SearchStrategyLike
+}
+
+// buildSearchCondition constrói a condição de um termo (ou frase) para uma coluna
+// conforme a estratégia e a normalização configuradas na entidade
+func (qb *QueryBuilder) buildSearchCondition(column, value string, phrase bool, config *SearchConfig) (string, interface{}) {
+	wildcard := !phrase && strings.Contains(value, "*")
+	pattern := "%" + value + "%"
+	if wildcard {
+		// Converte wildcards OData para SQL
+		pattern = strings.ReplaceAll(value, "*", "%")
+	}
+
+	switch qb.resolveSearchStrategy(config) {
+	case SearchStrategyFullText:
+		if config == nil {
+			if phrase {
+				return qb.buildFullTextPhraseCondition(column, value)
+			}
+			if wildcard {
+				return qb.buildFullTextSearchCondition(column, pattern)
+			}
+			return qb.buildFullTextSearchCondition(column, value)
+		}
+		if !wildcard {
+			return qb.buildNormalizedFullTextCondition(column, value, phrase, config)
+		}
+
+	case SearchStrategyTrigram:
+		if qb.dialect.GetName() == "postgresql" && !wildcard {
+			// Operador de similaridade do pg_trgm: tolera erros de digitação
+			return fmt.Sprintf("%s %% ?", qb.normalizeSearchColumn(column, config)), normalizeSearchValue(value, config)
+		}
+	}
+
+	return fmt.Sprintf("%s LIKE ?", qb.normalizeSearchColumn(column, config)), normalizeSearchValue(pattern, config)
+}
+
+// buildNormalizedFullTextCondition aplica idioma e remoção de acentos ao full-text search.
+// MySQL e Oracle delegam a normalização à collation/configuração do índice
+func (qb *QueryBuilder) buildNormalizedFullTextCondition(column, value string, phrase bool, config *SearchConfig) (string, interface{}) {
+	if qb.dialect.GetName() != "postgresql" {
+		if phrase {
+			return qb.buildFullTextPhraseCondition(column, value)
+		}
+		return qb.buildFullTextSearchCondition(column, value)
+	}
+
+	expr := column
+	if config.IgnoreAccents {
+		expr = fmt.Sprintf("%s(%s)", postgresUnaccentFunction, column)
+		value = removeAccents(value)
+	}
+
+	queryFunction := "plainto_tsquery"
+	if phrase {
+		queryFunction = "phraseto_tsquery"
+	}
+	language := config.searchLanguage()
+	return fmt.Sprintf("to_tsvector('%s', %s) @@ %s('%s', ?)", language, expr, queryFunction, language), value
+}
+
+// normalizeSearchColumn envolve a coluna nas funções de normalização do dialeto
+func (qb *QueryBuilder) normalizeSearchColumn(column string, config *SearchConfig) string {
+	if config == nil || (!config.IgnoreAccents && !config.IgnoreCase) {
+		return column
+	}
+
+	expr := column
+	if config.IgnoreAccents {
+		switch qb.dialect.GetName() {
+		case "postgresql":
+			expr = fmt.Sprintf("%s(%s)", postgresUnaccentFunction, expr)
+		case "mysql":
+			// A collation _ai_ci já ignora acentos e maiúsculas/minúsculas
+			return fmt.Sprintf("%s COLLATE %s", expr, mysqlAccentInsensitiveCollation)
+		case "oracle":
+			expr = fmt.Sprintf("TRANSLATE(%s, '%s', '%s')", expr, accentedChars, unaccentedChars)
+		default:
+			expr = buildReplaceAccents(expr)
+		}
+	}
+	if config.IgnoreCase {
+		expr = fmt.Sprintf("LOWER(%s)", expr)
+	}
+	return expr
+}
+
+// buildReplaceAccents remove acentos com REPLACE aninhados (bancos sem TRANSLATE, ex: SQLite)
+func buildReplaceAccents(expr string) string {
+	from, to := []rune(accentedChars), []rune(unaccentedChars)
+	for i := range from {
+		expr = fmt.Sprintf("REPLACE(%s, '%c', '%c')", expr, from[i], to[i])
+	}
+	return expr
+}
+
+// normalizeSearchValue aplica ao parâmetro a mesma normalização aplicada à coluna
+func normalizeSearchValue(value string, config *SearchConfig) string {
+	if config == nil {
+		return value
+	}
+	if config.IgnoreAccents {
+		value = removeAccents(value)
+	}
+	if config.IgnoreCase {
+		value = strings.ToLower(value)
+	}
+	return value
+}
+
+// =======================================================================================
+// ÍNDICES DE BUSCA
+// =======================================================================================
+
+// GenerateSearchIndexDDL retorna os comandos que criam os índices de apoio ao $search
+// (GIN tsvector/pg_trgm, FULLTEXT, CTXSYS.CONTEXT) das propriedades pesquisáveis de todas
+// as entidades, conforme a estratégia de cada uma e o dialeto do provider (ou o provider
+// padrão do servidor se nil). Não é executado pelo AutoMigrate: extensões e índices de
+// texto costumam exigir privilégios e revisão do DBA.
+func (s *Server) GenerateSearchIndexDDL(provider DatabaseProvider) ([]string, error) {
+	if provider == nil {
+		provider = s.provider
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("nenhum provider informado para geração de DDL")
+	}
+
+	builder := newSchemaDDLBuilder(provider)
+	qb := NewQueryBuilder(provider.GetDriverName())
+
+	var statements []string
+	seen := make(map[string]bool)
+	for _, name := range s.sortedEntityNames() {
+		service := s.GetEntityService(name)
+		if service == nil {
+			continue
+		}
+		metadata := service.GetMetadata()
+
+		// Extensões e funções auxiliares são compartilhadas entre as entidades
+		for _, statement := range builder.buildSearchIndexes(qb, metadata) {
+			if seen[statement] {
+				continue
+			}
+			seen[statement] = true
+			statements = append(statements, statement)
+		}
+	}
+
+	return statements, nil
+}
+
+// buildSearchIndexes gera os índices de busca de uma entidade
+func (b *schemaDDLBuilder) buildSearchIndexes(qb *QueryBuilder, metadata EntityMetadata) []string {
+	props := SearchableProperties(metadata)
+	if len(props) == 0 {
+		return nil
+	}
+
+	tableName := metadata.TableName
+	if tableName == "" {
+		tableName = metadata.Name
+	}
+	config := metadata.Search
+	strategy := qb.resolveSearchStrategy(config)
+
+	var statements []string
+	if b.dialect == "postgresql" {
+		if config != nil && config.IgnoreAccents {
+			statements = append(statements, "CREATE EXTENSION IF NOT EXISTS unaccent",
+				"CREATE OR REPLACE FUNCTION "+postgresUnaccentFunction+"(text) RETURNS text AS "+
+					"$$ SELECT public.unaccent('public.unaccent', $1) $$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT")
+		}
+		if strategy != SearchStrategyFullText {
+			statements = append(statements, "CREATE EXTENSION IF NOT EXISTS pg_trgm")
+		}
+	}
+
+	for _, prop := range props {
+		column := columnNameOf(prop)
+		switch b.dialect {
+		case "postgresql":
+			if strategy == SearchStrategyFullText {
+				expr := column
+				if config != nil && config.IgnoreAccents {
+					expr = fmt.Sprintf("%s(%s)", postgresUnaccentFunction, column)
+				}
+				statements = append(statements, fmt.Sprintf("CREATE INDEX %s ON %s USING gin (to_tsvector('%s', %s))",
+					b.indexName("fts", tableName, column), b.qualifiedTableName(metadata), config.searchLanguage(), expr))
+			} else {
+				statements = append(statements, fmt.Sprintf("CREATE INDEX %s ON %s USING gin ((%s) gin_trgm_ops)",
+					b.indexName("trgm", tableName, column), b.qualifiedTableName(metadata), qb.normalizeSearchColumn(column, config)))
+			}
+		case "mysql":
+			if strategy == SearchStrategyFullText {
+				statements = append(statements, fmt.Sprintf("CREATE FULLTEXT INDEX %s ON %s (%s)",
+					b.indexName("fts", tableName, column), b.qualifiedTableName(metadata), column))
+			}
+		case "oracle":
+			if strategy == SearchStrategyFullText {
+				statements = append(statements, fmt.Sprintf("CREATE INDEX %s ON %s (%s) INDEXTYPE IS CTXSYS.CONTEXT",
+					b.indexName("fts", tableName, column), b.qualifiedTableName(metadata), column))
+			}
+		}
+	}
+
+	return statements
+}
@@ -0,0 +1,284 @@
+package odata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// =======================================================================================
+// PROPRIEDADES EXPOSTAS E $select OBRIGATÓRIO POR ENTIDADE
+// =======================================================================================
+
+// WithExposedProperties define as únicas propriedades da entidade expostas nas consultas,
+// independente das tags json da struct. As demais não podem ser usadas em $select, $filter,
+// $orderby, $compute ou $expand, não aparecem no $metadata e, sem $select, ficam fora da
+// projeção SQL. As chaves e as propriedades virtuais são sempre expostas
+func WithExposedProperties(properties ...string) EntityOption {
+	return func(config *EntityConfig) {
+		config.ExposedProperties = append(config.ExposedProperties, properties...)
+	}
+}
+
+// WithRequireSelect exige $select nas consultas da entidade (entidades muito largas),
+// inclusive quando ela é expandida por outra entidade
+func WithRequireSelect(required bool) EntityOption {
+	return func(config *EntityConfig) {
+		config.RequireSelect = required
+	}
+}
+
+// validateExposedProperties valida as propriedades expostas no registro da entidade
+func validateExposedProperties(metadata EntityMetadata) error {
+	for _, name := range metadata.ExposedProperties {
+		if findEntityProperty(metadata, name) == nil {
+			return fmt.Errorf("propriedade exposta '%s' não encontrada", name)
+		}
+	}
+	return nil
+}
+
+// hasSelectPolicy verifica se a entidade restringe as propriedades expostas ou exige $select
+func hasSelectPolicy(metadata EntityMetadata) bool {
+	return len(metadata.ExposedProperties) > 0 || metadata.RequireSelect
+}
+
+// isHiddenProperty verifica se o nome é uma propriedade da entidade fora da lista de
+// propriedades expostas. Nomes desconhecidos (aliases do $compute, variáveis de lambda)
+// seguem para a validação normal da consulta
+func isHiddenProperty(metadata EntityMetadata, name string) bool {
+	if len(metadata.ExposedProperties) == 0 {
+		return false
+	}
+	prop := findEntityProperty(metadata, name)
+	if prop == nil || prop.IsKey {
+		return false
+	}
+	for _, exposed := range metadata.ExposedProperties {
+		if strings.EqualFold(exposed, prop.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// findEntityProperty busca uma propriedade, inclusive de navegação, pelo nome sem diferenciar
+// maiúsculas/minúsculas
+func findEntityProperty(metadata EntityMetadata, name string) *PropertyMetadata {
+	for i, prop := range metadata.Properties {
+		if strings.EqualFold(prop.Name, name) {
+			return &metadata.Properties[i]
+		}
+	}
+	return nil
+}
+
+// hiddenPropertyError recusa a propriedade oculta como se ela não existisse na entidade
+func hiddenPropertyError(metadata EntityMetadata, option, name string) error {
+	return fmt.Errorf("invalid %s: entity '%s' has no property '%s'", option, metadata.Name, name)
+}
+
+// applySelectPolicy aplica as propriedades expostas e o $select obrigatório da entidade às
+// opções da consulta, antes do filtro padrão e da construção da query. Sem $select (ou com
+// '*'), a seleção passa a ser a lista de propriedades expostas, para que as colunas ocultas
+// nunca cheguem à projeção SQL
+func (s *Server) applySelectPolicy(options *QueryOptions, metadata EntityMetadata) error {
+	if !hasSelectPolicy(metadata) {
+		return s.checkHiddenProperties(options, metadata)
+	}
+
+	if options.Select == nil || len(options.Select.SelectItems) == 0 {
+		if metadata.RequireSelect {
+			return fmt.Errorf("$select is required for entity '%s'", metadata.Name)
+		}
+		options.Select = s.exposedSelect(metadata)
+	} else {
+		items := make([]*SelectItem, 0, len(options.Select.SelectItems))
+		for _, item := range options.Select.SelectItems {
+			name := item.Segments[0].Value
+			if name == "*" {
+				items = append(items, s.exposedSelect(metadata).SelectItems...)
+				continue
+			}
+			if isHiddenProperty(metadata, name) {
+				return hiddenPropertyError(metadata, "$select", name)
+			}
+			items = append(items, item)
+		}
+		options.Select = &GoDataSelectQuery{SelectItems: items, RawValue: options.Select.RawValue}
+	}
+
+	return s.checkHiddenProperties(options, metadata)
+}
+
+// checkHiddenProperties recusa as propriedades ocultas no $filter, $orderby, $compute e
+// $expand (também usado no /$count, que não exige $select). Nos caminhos de navegação do
+// $filter e do $orderby, as propriedades ocultas da entidade relacionada também são recusadas
+func (s *Server) checkHiddenProperties(options *QueryOptions, metadata EntityMetadata) error {
+	if options.Filter != nil {
+		if owner, name := s.hiddenPropertyInTree(options.Filter.Tree, metadata); name != "" {
+			return hiddenPropertyError(owner, "$filter", name)
+		}
+	}
+
+	if options.OrderBy != "" {
+		// Erros de sintaxe são reportados na construção da query
+		if expressions, err := NewODataParser().ParseOrderBy(options.OrderBy); err == nil {
+			for _, expr := range expressions {
+				owner, name := s.hiddenPropertyInTree(expr.Expression, metadata)
+				if expr.Expression == nil {
+					owner, name = s.hiddenPropertyInPath(metadata, expr.Property)
+				}
+				if name != "" {
+					return hiddenPropertyError(owner, "$orderby", name)
+				}
+			}
+		}
+	}
+
+	if options.Compute != nil && len(metadata.ExposedProperties) > 0 {
+		compute, err := parseComputeExpressions(options.Compute)
+		if err != nil {
+			return fmt.Errorf("invalid $compute: %w", err)
+		}
+		options.Compute = compute
+		for _, expr := range compute.Expressions {
+			if owner, name := s.hiddenPropertyInTree(expr.ParseTree, metadata); name != "" {
+				return hiddenPropertyError(owner, "$compute", name)
+			}
+		}
+	}
+
+	return s.checkExpandSelectPolicy(options.Expand, metadata)
+}
+
+// checkExpandSelectPolicy valida as navegações expandidas: navegações ocultas são recusadas
+// e entidades relacionadas com política de $select só podem ser expandidas com um $select
+// explícito, restrito às propriedades expostas
+func (s *Server) checkExpandSelectPolicy(expand *GoDataExpandQuery, metadata EntityMetadata) error {
+	if expand == nil {
+		return nil
+	}
+	for _, item := range expand.ExpandItems {
+		if len(item.Path) == 0 {
+			continue
+		}
+		name := item.Path[0].Value
+		if isHiddenProperty(metadata, name) {
+			return hiddenPropertyError(metadata, "$expand", name)
+		}
+
+		navProperty := findEntityProperty(metadata, name)
+		if navProperty == nil || !navProperty.IsNavigation {
+			continue
+		}
+		related := s.relatedEntityService(navProperty.RelatedType)
+		if related == nil {
+			continue
+		}
+		relatedMetadata := related.GetMetadata()
+
+		if hasSelectPolicy(relatedMetadata) {
+			if item.Select == nil || len(item.Select.SelectItems) == 0 {
+				return fmt.Errorf("invalid $expand: $select is required to expand '%s'", name)
+			}
+			for _, selectItem := range item.Select.SelectItems {
+				selected := selectItem.Segments[0].Value
+				if selected == "*" && len(relatedMetadata.ExposedProperties) > 0 {
+					return fmt.Errorf("invalid $expand: '*' is not allowed in $select of '%s'", name)
+				}
+				if isHiddenProperty(relatedMetadata, selected) {
+					return hiddenPropertyError(relatedMetadata, "$expand", selected)
+				}
+			}
+			if item.Filter != nil {
+				if owner, hidden := s.hiddenPropertyInTree(item.Filter.Tree, relatedMetadata); hidden != "" {
+					return hiddenPropertyError(owner, "$expand", hidden)
+				}
+			}
+		}
+
+		if err := s.checkExpandSelectPolicy(item.Expand, relatedMetadata); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exposedSelect monta o $select com as propriedades expostas (e as virtuais) da entidade
+func (s *Server) exposedSelect(metadata EntityMetadata) *GoDataSelectQuery {
+	sel := &GoDataSelectQuery{}
+	for _, prop := range metadata.Properties {
+		if !prop.IsNavigation && !isHiddenProperty(metadata, prop.Name) {
+			sel.SelectItems = append(sel.SelectItems, &SelectItem{Segments: []*Token{{Value: prop.Name}}})
+		}
+	}
+	for _, prop := range s.getVirtualProperties(metadata.Name) {
+		sel.SelectItems = append(sel.SelectItems, &SelectItem{Segments: []*Token{{Value: prop.name}}})
+	}
+	return sel
+}
+
+// hiddenPropertyInTree retorna a primeira propriedade oculta referenciada na expressão e a
+// entidade a que ela pertence (ver hiddenPropertyInPath)
+func (s *Server) hiddenPropertyInTree(tree *ParseNode, metadata EntityMetadata) (EntityMetadata, string) {
+	var owner EntityMetadata
+	var hidden string
+	walkParseTree(tree, func(node *ParseNode) {
+		if hidden != "" || node.Token == nil || node.Token.Type != int(FilterTokenProperty) {
+			return
+		}
+		owner, hidden = s.hiddenPropertyInPath(metadata, node.Token.Value)
+	})
+	return owner, hidden
+}
+
+// hiddenPropertyInPath retorna o primeiro segmento oculto de um caminho e a entidade a que
+// ele pertence. Cada navegação do caminho (ex: Category/Name) é resolvida para a entidade
+// relacionada, cujas propriedades expostas valem para os segmentos seguintes
+func (s *Server) hiddenPropertyInPath(metadata EntityMetadata, path string) (EntityMetadata, string) {
+	current := metadata
+	for _, segment := range strings.Split(path, "/") {
+		segment = strings.TrimSpace(segment)
+		if isHiddenProperty(current, segment) {
+			return current, segment
+		}
+		prop := findEntityProperty(current, segment)
+		if prop == nil || !prop.IsNavigation {
+			break
+		}
+		related := s.navigationTargetService(prop)
+		if related == nil {
+			break
+		}
+		current = related.GetMetadata()
+	}
+	return EntityMetadata{}, ""
+}
+
+// navigationTargetService retorna o serviço da entidade de destino da navegação, pela
+// entidade da associação ou, sem ela, pelo tipo relacionado
+func (s *Server) navigationTargetService(prop *PropertyMetadata) EntityService {
+	if prop.Association != nil && prop.Association.RelatedEntity != "" {
+		if service := s.relatedEntityService(prop.Association.RelatedEntity); service != nil {
+			return service
+		}
+	}
+	return s.relatedEntityService(prop.RelatedType)
+}
+
+// parseComputeExpressions analisa o $compute ainda não processado (o parser de URL guarda a
+// expressão bruta), para que as propriedades referenciadas possam ser verificadas
+func parseComputeExpressions(compute *ComputeOption) (*ComputeOption, error) {
+	if len(compute.Expressions) != 1 || compute.Expressions[0].ParseTree != nil {
+		return compute, nil
+	}
+	parsed, err := NewComputeParser().ParseCompute(context.Background(), compute.Expressions[0].Expression)
+	if err != nil {
+		return nil, err
+	}
+	if parsed == nil {
+		return compute, nil
+	}
+	return parsed, nil
+}
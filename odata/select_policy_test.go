@@ -0,0 +1,152 @@
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_SelectPolicy(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	orders := server.entities["Orders"].GetMetadata()
+	orders.ExposedProperties = []string{"status", "Items"}
+	server.entities["Orders"] = NewBaseEntityService(server.provider, orders, server)
+	items := server.entities["OrderItems"].GetMetadata()
+	items.RequireSelect = true
+	server.entities["OrderItems"] = NewBaseEntityService(server.provider, items, server)
+
+	app := fiber.New()
+	app.Get("/odata/Orders", server.handleEntityCollection)
+	app.Get("/odata/Orders(*)", server.handleEntityById)
+	app.Get("/odata/OrderItems", server.handleEntityCollection)
+
+	get := func(path string, query url.Values) (int, string) {
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("Sem $select projeta apenas as propriedades expostas", func(t *testing.T) {
+		status, body := get("/odata/Orders", nil)
+		require.Equal(t, http.StatusOK, status, body)
+		var response struct {
+			Value []map[string]interface{} `json:"value"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &response))
+		require.Len(t, response.Value, 2)
+		assert.Contains(t, response.Value[0], "id")
+		assert.Contains(t, response.Value[0], "status")
+		assert.NotContains(t, response.Value[0], "customer_id")
+
+		status, body = get("/odata/Orders(1)", nil)
+		require.Equal(t, http.StatusOK, status, body)
+		assert.NotContains(t, body, "customer_id")
+	})
+
+	t.Run("Propriedades ocultas são recusadas", func(t *testing.T) {
+		for option, value := range map[string]string{
+			"$select":  "status,customer_id",
+			"$filter":  "customer_id eq 1",
+			"$orderby": "customer_id desc",
+			"$compute": "customer_id add 1 as next",
+			"$expand":  "Customer",
+		} {
+			status, body := get("/odata/Orders", url.Values{option: {value}})
+			assert.Equal(t, http.StatusBadRequest, status, option)
+			assert.Contains(t, body, "has no property", option)
+		}
+	})
+
+	t.Run("$select obrigatório", func(t *testing.T) {
+		status, body := get("/odata/OrderItems", nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, body, "$select is required")
+
+		status, _ = get("/odata/OrderItems", url.Values{"$select": {"product"}})
+		assert.Equal(t, http.StatusOK, status)
+
+		status, body = get("/odata/Orders", url.Values{"$expand": {"Items"}})
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, body, "$select is required to expand 'Items'")
+
+		status, body = get("/odata/Orders", url.Values{"$expand": {"Items($select=product)"}})
+		assert.Equal(t, http.StatusOK, status, body)
+	})
+
+	t.Run("Operações do $batch", func(t *testing.T) {
+		processor := NewBatchProcessor(server)
+		resp, err := processor.executeOperation(context.Background(), &BatchHTTPOperation{Method: "GET", URL: "/odata/Orders(1)", ContentID: "1"}, map[string]interface{}{})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotContains(t, string(resp.Body), "customer_id")
+
+		resp, err = processor.executeOperation(context.Background(), &BatchHTTPOperation{Method: "GET", URL: "/odata/OrderItems/$count", ContentID: "2"}, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestServer_SelectPolicyNavigationPaths(t *testing.T) {
+	server := newNavigationTestServer(t)
+	updateNavigationCategories(server, func(metadata *EntityMetadata) {
+		metadata.ExposedProperties = []string{"id"}
+	})
+
+	for option, value := range map[string]string{
+		"$filter":  "Category/name eq 'Displays'",
+		"$orderby": "Category/name desc",
+	} {
+		status, body := getComputeTestProducts(t, server, option+"="+url.QueryEscape(value))
+		if assert.Equal(t, http.StatusBadRequest, status, option) {
+			assert.Contains(t, body["error"].(map[string]any)["message"], "entity 'Categories' has no property 'name'", option)
+		}
+	}
+
+	names := productNames(t, server, "$filter="+url.QueryEscape("Category/id eq 2"))
+	assert.Equal(t, []string{"Monitor"}, names)
+}
+
+func TestServer_SelectPolicyMetadata(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	orders := server.entities["Orders"].GetMetadata()
+	orders.ExposedProperties = []string{"status"}
+	server.entities["Orders"] = NewBaseEntityService(server.provider, orders, server)
+
+	for _, entity := range server.buildMetadataJSON().Entities {
+		if entity.Name != "Orders" {
+			continue
+		}
+		var names []string
+		for _, prop := range entity.Properties {
+			names = append(names, prop.Name)
+		}
+		assert.Equal(t, []string{"id", "status"}, names)
+		return
+	}
+	t.Fatal("entidade Orders ausente do $metadata")
+}
+
+func TestValidateExposedProperties(t *testing.T) {
+	metadata := EntityMetadata{Properties: []PropertyMetadata{{Name: "id", IsKey: true}, {Name: "name"}}}
+
+	metadata.ExposedProperties = []string{"Name"}
+	assert.NoError(t, validateExposedProperties(metadata))
+	assert.False(t, isHiddenProperty(metadata, "id"))
+
+	metadata.ExposedProperties = []string{"salary"}
+	assert.EqualError(t, validateExposedProperties(metadata), "propriedade exposta 'salary' não encontrada")
+	assert.True(t, isHiddenProperty(metadata, "name"))
+}
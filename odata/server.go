@@ -5,44 +5,92 @@ import (
 	"crypto/tls"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/cors"
 	fiberlogger "github.com/gofiber/fiber/v3/middleware/logger"
-	fiberrecover "github.com/gofiber/fiber/v3/middleware/recover"
 	"github.com/kardianos/service"
 )
 
 // Server representa o servidor OData
 type Server struct {
-	entities          map[string]EntityService
-	router            *fiber.App
-	parser            *ODataParser
-	urlParser         *URLParser
-	provider          DatabaseProvider         // Provider padrão
-	multiTenantPool   *MultiTenantProviderPool // Pool multi-tenant
-	multiTenantConfig *MultiTenantConfig       // Configurações multi-tenant
-	config            *ServerConfig
-	httpServer        *fiber.App // Changed from http.Server to fiber.App
-	logger            *log.Logger
-	mu                sync.RWMutex
-	running           bool
-	entityAuth        map[string]EntityAuthConfig // Configurações de autenticação por entidade
-	eventManager      *EntityEventManager         // Gerenciador de eventos de entidade
-	rateLimiter       *RateLimiter                // Rate limiter
-	auditLogger       AuditLogger                 // Audit logger
+	entities            map[string]EntityService
+	router              *fiber.App
+	parser              *ODataParser
+	urlParser           *URLParser
+	provider            DatabaseProvider            // Provider padrão
+	providers           map[string]DatabaseProvider // Providers nomeados (AddProvider)
+	multiTenantPool     *MultiTenantProviderPool    // Pool multi-tenant
+	multiTenantConfig   *MultiTenantConfig          // Configurações multi-tenant
+	config              *ServerConfig
+	httpServer          *fiber.App // Changed from http.Server to fiber.App
+	logger              *log.Logger
+	mu                  sync.RWMutex
+	running             bool
+	entityAuth          map[string]EntityAuthConfig  // Configurações de autenticação por entidade
+	entityQueryTimeouts map[string]time.Duration     // QueryTimeout por entidade (WithQueryTimeout)
+	virtualProperties   map[string][]virtualProperty // Propriedades calculadas em Go por entidade
+	maskingPolicies     map[string][]propertyMask    // Máscaras de propriedades sensíveis por entidade
+	eventManager        *EntityEventManager          // Gerenciador de eventos de entidade
+	rateLimiter         *RateLimiter                 // Rate limiter
+	auditLogger         AuditLogger                  // Audit logger
+	panicCount          atomic.Int64                 // Panics recuperados pelo RecoverMiddleware
+	tcpListener         net.Listener                 // Listener próprio (SO_REUSEPORT/handoff), quando em uso
+	diagnosticsRoutes   bool                         // Rotas de diagnóstico já registradas
+	jsonEncoders        sync.Map                     // Encoders JSON pré-compilados por entidade (FastJSONEncoding)
+	entityRoutes        map[string]entityRoute       // Prefixo/entity set por entidade (WithRoutePrefix/WithEntitySetName)
+	routePrefixes       map[string]bool              // Prefixos de rota adicionais com $metadata próprio
+	queryInterceptors   []QueryInterceptor           // Interceptors de consulta globais (UseQueryInterceptor)
+	operations          []ServiceOperation           // Operações não vinculadas (Function/Action)
+	jobs                *JobScheduler                // Jobs agendados (Schedule)
+	responseSerializer  ResponseSerializer           // Serializador das respostas de entidades (SetResponseSerializer)
+	recycleBins         map[string]RecycleBinConfig  // Lixeira por entidade (WithRecycleBin)
+	paginations         map[string]PaginationConfig  // Paginação por entidade (WithPagination)
+	syncs               map[string]SyncConfig        // Sincronização em lote por entidade (WithSync)
+	imports             map[string]ImportConfig      // Importação de arquivos por entidade (WithImport)
+	exports             map[string]ExportConfig      // Exportação assíncrona por entidade (WithExport)
+	exportJobs          *exportManager               // Jobs de exportação (SetExportOptions)
+
+	entityQueryInterceptors map[string][]QueryInterceptor // Interceptors de consulta por entidade (WithQueryInterceptor)
+	entityAuthorizers       map[string][]EntityAuthorizer // Callbacks de autorização por entidade (WithAuthorizer)
+	entityToggles           map[string]entityToggle       // Entidades desativadas ou somente leitura em tempo de execução
+
+	// Servidor montado em outra aplicação (Mount/Handler): o Listen é da aplicação hospedeira
+	embedded    bool
+	mountPrefix string
+
+	// Módulos registrados (UseModule), na ordem de registro
+	modules []*moduleEntry
+
+	// Rotas das entidades registradas com o servidor em execução (RegisterEntity após o Start)
+	dynamicRoutes    atomic.Pointer[dynamicRouter]
+	dynamicEntities  []string
+	dynamicPrefixes  []string
+	dynamicInstalled bool
+	dynamicMu        sync.Mutex
+
+	// Gerador snowflake do servidor (nó de ServerConfig.SnowflakeNodeID), criado no primeiro uso
+	snowflake     *SnowflakeGenerator
+	snowflakeErr  error
+	snowflakeOnce sync.Once
 
 	// Campos para gerenciamento de serviço
 	serviceLogger service.Logger
 	serviceCtx    context.Context
 	serviceCancel context.CancelFunc
+
+	// Destino dos logs (redirecionado para ServerConfig.LogFile ao executar como serviço)
+	logOutput *switchableWriter
+	logFile   *rotatingFileWriter
 }
 
 // NewServer cria uma nova instância do servidor OData
@@ -95,20 +143,23 @@ func NewServerWithProvider(provider DatabaseProvider, host string, port int, rou
 
 // NewMultiTenantServer cria um servidor multi-tenant
 func newMultiTenantServer(multiTenantConfig *MultiTenantConfig) *Server {
-	logger := log.New(os.Stdout, "[OData-MultiTenant] ", log.LstdFlags|log.Lshortfile)
+	logOutput := newSwitchableWriter(os.Stdout)
+	logger := log.New(logOutput, "[OData-MultiTenant] ", log.LstdFlags|log.Lshortfile)
 
 	server := &Server{
 		entities:          make(map[string]EntityService),
-		router:            fiber.New(),
 		parser:            NewODataParser(),
 		urlParser:         NewURLParser(),
 		multiTenantConfig: multiTenantConfig,
 		config:            multiTenantConfig.EnvConfig.ToServerConfig(),
 		logger:            logger,
+		logOutput:         logOutput,
 		entityAuth:        make(map[string]EntityAuthConfig),
 		eventManager:      NewEntityEventManager(logger),
 	}
 
+	server.router = fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
+
 	// Inicializa pool multi-tenant
 	server.multiTenantPool = NewMultiTenantProviderPool(multiTenantConfig, logger)
 	if err := server.multiTenantPool.InitializeProviders(); err != nil {
@@ -138,19 +189,21 @@ func NewServerWithEnv(provider DatabaseProvider) *Server {
 
 // newServerWithConfig cria uma nova instância do servidor OData com configurações personalizadas
 func newServerWithConfig(provider DatabaseProvider, config *ServerConfig) *Server {
-	logger := log.New(os.Stdout, "[OData] ", log.LstdFlags|log.Lshortfile)
+	logOutput := newSwitchableWriter(os.Stdout)
+	logger := log.New(logOutput, "[OData] ", log.LstdFlags|log.Lshortfile)
 
 	server := &Server{
 		entities:     make(map[string]EntityService),
-		router:       fiber.New(),
 		parser:       NewODataParser(),
 		urlParser:    NewURLParser(),
 		provider:     provider,
 		config:       config,
 		logger:       logger,
+		logOutput:    logOutput,
 		entityAuth:   make(map[string]EntityAuthConfig),
 		eventManager: NewEntityEventManager(logger),
 	}
+	server.router = fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
 
 	// Configurar Rate Limit se habilitado
 	if config.RateLimitConfig != nil && config.RateLimitConfig.Enabled {
@@ -176,6 +229,13 @@ func newServerWithConfig(provider DatabaseProvider, config *ServerConfig) *Serve
 		server.auditLogger = &NoOpAuditLogger{}
 	}
 
+	// Tunelamento via X-HTTP-Method: primeiro middleware, para que o roteamento e os demais
+	// middlewares vejam o método efetivo
+	server.router.Use(server.MethodOverrideMiddleware())
+
+	// Compressão gzip/brotli (EnableCompression): antes dos demais, para comprimir a resposta final
+	server.router.Use(server.CompressionMiddleware())
+
 	// Configurar Security Headers se habilitado
 	if config.SecurityHeadersConfig != nil && config.SecurityHeadersConfig.Enabled {
 		server.router.Use(SecurityHeadersMiddleware(config.SecurityHeadersConfig))
@@ -195,11 +255,15 @@ func newServerWithConfig(provider DatabaseProvider, config *ServerConfig) *Serve
 	if config.EnableLogging {
 		server.router.Use(fiberlogger.New(fiberlogger.Config{
 			Format: "${time} ${method} ${path} ${status} ${latency} ${bytesReceived} ${bytesSent}\n",
+			Stream: server.accessLogStream(),
 		}))
 	}
 
-	// Middleware de recovery sempre ativo para segurança
-	server.router.Use(fiberrecover.New())
+	// Auditoria HTTP (configurável via HTTPAuditConfig); antes do recovery para registrar panics
+	server.router.Use(server.HTTPAuditMiddleware())
+
+	// Middleware de recovery (configurável via RecoverConfig)
+	server.router.Use(server.RecoverMiddleware())
 
 	// Middleware que injeta o servidor no contexto Fiber
 	server.router.Use(func(c fiber.Ctx) error {
@@ -213,6 +277,9 @@ func newServerWithConfig(provider DatabaseProvider, config *ServerConfig) *Serve
 	// Middleware de conexão de banco de dados (transparente)
 	server.router.Use(server.DatabaseMiddleware())
 
+	// Modo de manutenção (MaintenanceConfig), alternável em tempo de execução
+	server.router.Use(server.MaintenanceMiddleware())
+
 	// Middleware de rate limit se habilitado
 	if server.rateLimiter != nil {
 		server.router.Use(server.RateLimitMiddleware())
@@ -244,15 +311,54 @@ func (s *Server) RegisterEntity(name string, entity interface{}, opts ...EntityO
 		opt(config)
 	}
 
-	metadata, err := MapEntityFromStruct(entity)
+	namingPolicy := config.NamingPolicy
+	if namingPolicy == "" && s.config != nil {
+		namingPolicy = s.config.NamingPolicy
+	}
+	metadata, err := MapEntityWithNamingPolicy(entity, namingPolicy)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
+	}
+	metadata.Search = config.Search
+	metadata.CaseInsensitive = config.CaseInsensitive
+	metadata.DefaultFilter = config.DefaultFilter
+	metadata.QueryPresets = config.QueryPresets
+	metadata.SoftDeleteProperty = config.SoftDelete
+	metadata.History = config.History
+	metadata.ExposedProperties = config.ExposedProperties
+	metadata.RequireSelect = config.RequireSelect
+	metadata.AutoValues = config.AutoValues
+	if err := validateQueryFilters(metadata); err != nil {
+		return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
+	}
+	if err := validateExposedProperties(metadata); err != nil {
+		return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
+	}
+	if err := validateAutoValues(metadata); err != nil {
+		return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
+	}
+	if err := validateSoftDelete(metadata); err != nil {
+		return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
+	}
+	if config.RecycleBin != nil && config.SoftDelete == "" {
+		return fmt.Errorf("erro ao registrar entidade %s: WithRecycleBin requer WithSoftDelete", name)
+	}
+	route, err := s.newEntityRoute(name, config)
 	if err != nil {
 		return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
 	}
 
 	var service EntityService
 
-	// Se multi-tenant estiver habilitado, usa MultiTenantEntityService
-	if s.multiTenantConfig != nil && s.multiTenantConfig.Enabled {
+	// Entidades com provider nomeado usam sempre o pool desse provider
+	if config.Provider != "" {
+		provider, err := s.resolveEntityProvider(config)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
+		}
+		service = NewBaseEntityService(provider, metadata, s)
+	} else if s.multiTenantConfig != nil && s.multiTenantConfig.Enabled {
+		// Se multi-tenant estiver habilitado, usa MultiTenantEntityService
 		service = NewMultiTenantEntityService(metadata, s)
 	} else {
 		service = NewBaseEntityService(s.provider, metadata, s)
@@ -271,8 +377,57 @@ func (s *Server) RegisterEntity(name string, entity interface{}, opts ...EntityO
 			Permissions: config.Permissions,
 		}
 	}
+	if config.QueryTimeout > 0 {
+		if s.entityQueryTimeouts == nil {
+			s.entityQueryTimeouts = make(map[string]time.Duration)
+		}
+		s.entityQueryTimeouts[name] = config.QueryTimeout
+	}
+	if len(config.QueryInterceptors) > 0 {
+		if s.entityQueryInterceptors == nil {
+			s.entityQueryInterceptors = make(map[string][]QueryInterceptor)
+		}
+		s.entityQueryInterceptors[name] = config.QueryInterceptors
+	}
+	if len(config.Authorizers) > 0 {
+		if s.entityAuthorizers == nil {
+			s.entityAuthorizers = make(map[string][]EntityAuthorizer)
+		}
+		s.entityAuthorizers[name] = config.Authorizers
+	}
 	s.mu.Unlock()
 
+	s.setEntityRoute(name, route)
+
+	if config.Pagination != nil {
+		if err := s.registerPagination(name, *config.Pagination); err != nil {
+			return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
+		}
+	}
+	if config.RecycleBin != nil {
+		if err := s.registerRecycleBin(name, *config.RecycleBin); err != nil {
+			return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
+		}
+	}
+	if config.Sync != nil {
+		if err := s.registerSync(name, metadata, *config.Sync); err != nil {
+			return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
+		}
+	}
+	if config.Import != nil {
+		if err := s.registerImport(name, metadata, *config.Import); err != nil {
+			return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
+		}
+	}
+	if config.Export != nil {
+		if err := s.registerExport(name, *config.Export); err != nil {
+			return fmt.Errorf("erro ao registrar entidade %s: %w", name, err)
+		}
+	}
+
+	// Relacionamentos podem referenciar entidades registradas antes ou depois desta
+	s.resolveRelationshipNames()
+
 	// Configura rotas FORA do lock para evitar deadlock
 	s.setupEntityRoutes(name)
 
@@ -285,6 +440,8 @@ func (s *Server) RegisterEntityWithService(name string, service EntityService) e
 	s.entities[name] = service
 	s.mu.Unlock()
 
+	s.resolveRelationshipNames()
+
 	// Configura rotas FORA do lock para evitar deadlock
 	s.setupEntityRoutes(name)
 
@@ -317,6 +474,17 @@ func (s *Server) Start() error {
 
 // StartWithContext inicia o servidor com contexto
 func (s *Server) startWithContext(ctx context.Context) error {
+	// Migrations dos módulos antes de aceitar requisições (fora do lock: usam o servidor)
+	if err := s.runModuleMigrations(ctx); err != nil {
+		return err
+	}
+
+	// Sincroniza schema antes de aceitar requisições (se habilitado). Também fora do lock:
+	// AutoMigrate consulta as entidades registradas com s.mu.RLock
+	if err := s.runAutoMigrate(ctx); err != nil {
+		return fmt.Errorf("erro na auto-migração de schema: %w", err)
+	}
+
 	s.mu.Lock()
 	if s.running {
 		s.mu.Unlock()
@@ -327,9 +495,16 @@ func (s *Server) startWithContext(ctx context.Context) error {
 
 	s.httpServer = s.router // Use the router as the server
 
+	s.installDynamicRoutes()
 	s.running = true
+	jobs := s.jobs
 	s.mu.Unlock()
 
+	// Jobs agendados rodam enquanto o servidor estiver ativo
+	if jobs != nil {
+		jobs.Start()
+	}
+
 	// Determina se está usando HTTPS ou HTTP
 	scheme := "http"
 	if s.config.TLSConfig != nil || (s.config.CertFile != "" && s.config.CertKeyFile != "") {
@@ -348,6 +523,23 @@ func (s *Server) startWithContext(ctx context.Context) error {
 	// Configurar shutdown graceful em goroutine separada
 	go s.setupGracefulShutdown(ctx)
 
+	// Listener próprio: SO_REUSEPORT, handoff ou socket herdado (restart sem downtime)
+	if s.useCustomListener() {
+		ln, err := s.createListener(addr)
+		if err != nil {
+			s.mu.Lock()
+			s.running = false
+			s.mu.Unlock()
+			return err
+		}
+		return s.httpServer.Listener(ln, fiber.ListenConfig{
+			BeforeServeFunc: func(*fiber.App) error {
+				s.notifyHandoffParent()
+				return nil
+			},
+		})
+	}
+
 	// Inicia o servidor (bloqueante)
 	if s.config.TLSConfig != nil || (s.config.CertFile != "" && s.config.CertKeyFile != "") {
 		if s.config.CertFile != "" && s.config.CertKeyFile != "" {
@@ -380,13 +572,36 @@ func (s *Server) setupGracefulShutdown(ctx context.Context) {
 	// Channel para capturar sinais do sistema
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	if s.config.EnableHandoff && handoffSignal != nil {
+		signal.Notify(sigChan, handoffSignal)
+	}
+	if s.GetMaintenance().Signal && maintenanceSignal != nil {
+		signal.Notify(sigChan, maintenanceSignal)
+	}
 
 	// Aguarda cancelamento do contexto ou sinal do sistema
-	select {
-	case <-ctx.Done():
-		s.logger.Printf("Contexto cancelado, parando servidor...")
-	case sig := <-sigChan:
-		s.logger.Printf("Sinal recebido: %v, parando servidor...", sig)
+	// O sinal de handoff inicia a nova instância; este processo segue atendendo até
+	// receber o SIGTERM enviado por ela
+waitLoop:
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Printf("Contexto cancelado, parando servidor...")
+			break waitLoop
+		case sig := <-sigChan:
+			if handoffSignal != nil && sig == handoffSignal {
+				if err := s.Handoff(); err != nil {
+					s.logger.Printf("Erro no handoff do socket: %v", err)
+				}
+				continue
+			}
+			if maintenanceSignal != nil && sig == maintenanceSignal {
+				s.toggleMaintenance()
+				continue
+			}
+			s.logger.Printf("Sinal recebido: %v, parando servidor...", sig)
+			break waitLoop
+		}
 	}
 
 	// Executa shutdown graceful
@@ -404,7 +619,7 @@ func (s *Server) Shutdown() error {
 		return fmt.Errorf("servidor não está rodando")
 	}
 
-	if s.httpServer == nil {
+	if s.httpServer == nil && !s.embedded {
 		return fmt.Errorf("servidor HTTP não inicializado")
 	}
 
@@ -414,10 +629,33 @@ func (s *Server) Shutdown() error {
 	ctx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
 	defer cancel()
 
-	// Shutdown graceful
-	if err := s.httpServer.ShutdownWithContext(ctx); err != nil {
-		s.logger.Printf("Erro durante shutdown: %v", err)
-		return err
+	// Shutdown graceful (montado em outra aplicação, o Listen é encerrado por ela)
+	if !s.embedded {
+		if err := s.httpServer.ShutdownWithContext(ctx); err != nil {
+			s.logger.Printf("Erro durante shutdown: %v", err)
+			return err
+		}
+	}
+
+	// Interrompe os jobs agendados e aguarda as execuções em andamento
+	if s.jobs != nil {
+		if err := s.jobs.Stop(ctx); err != nil {
+			s.logger.Printf("Erro ao parar jobs agendados: %v", err)
+		}
+	}
+
+	// Interrompe as exportações em andamento
+	if s.exportJobs != nil {
+		if err := s.exportJobs.stop(ctx); err != nil {
+			s.logger.Printf("Erro ao parar exportações: %v", err)
+		}
+	}
+
+	// Aguarda os handlers de evento assíncronos pendentes
+	if s.eventManager != nil {
+		if err := s.eventManager.DrainAsync(ctx); err != nil {
+			s.logger.Printf("Erro ao drenar eventos assíncronos: %v", err)
+		}
 	}
 
 	// Fechar provider se necessário
@@ -426,8 +664,10 @@ func (s *Server) Shutdown() error {
 			s.logger.Printf("Erro ao fechar provider: %v", err)
 		}
 	}
+	s.closeNamedProviders()
 
 	s.running = false
+	s.embedded = false
 	s.logger.Printf("Servidor parado com sucesso")
 	return nil
 }
@@ -450,6 +690,12 @@ func (s *Server) run() error {
 		s.logger.Printf("Aviso: Não foi possível configurar logger do serviço: %v", err)
 	}
 
+	// Redireciona os logs para o arquivo com rotação, Event Log ou syslog (ServerConfig.LogFile)
+	if err := s.redirectServiceLogs(svc); err != nil {
+		s.logger.Printf("⚠️ Não foi possível redirecionar os logs para %q: %v", s.config.LogFile, err)
+	}
+	defer s.closeServiceLogs()
+
 	err = svc.Run()
 	if err != nil {
 		if s.serviceLogger != nil {
@@ -489,7 +735,7 @@ func (s *Server) Restart() error {
 		return fmt.Errorf("erro ao criar serviço: %w", err)
 	}
 
-	s.logger.Printf("🔄 Reiniciando serviço '%s'...", s.config.Name)
+	s.logger.Printf("🔄 Reiniciando serviço '%s'...", s.serviceName())
 
 	// Para o serviço
 	if err := svc.Stop(); err != nil {
@@ -504,7 +750,7 @@ func (s *Server) Restart() error {
 		return fmt.Errorf("erro ao iniciar serviço: %w", err)
 	}
 
-	s.logger.Printf("✅ Serviço '%s' reiniciado com sucesso!", s.config.Name)
+	s.logger.Printf("✅ Serviço '%s' reiniciado com sucesso!", s.serviceName())
 	return nil
 }
 
@@ -533,12 +779,17 @@ func (s *Server) Status() (service.Status, error) {
 		statusText = "❓ Status desconhecido"
 	}
 
-	s.logger.Printf("📊 Status do serviço '%s': %s", s.config.Name, statusText)
+	s.logger.Printf("📊 Status do serviço '%s': %s", s.serviceName(), statusText)
 	return status, nil
 }
 
-// Install instala o servidor como serviço do sistema
+// Install instala o servidor como serviço do sistema, com o nome, a inicialização, a política
+// de reinício e a conta de ServerConfig.ServiceConfig
 func (s *Server) Install() error {
+	if err := s.serviceConfig().validate(); err != nil {
+		return err
+	}
+
 	wrapper := &ServiceWrapper{server: s}
 	svc, err := service.New(wrapper, s.createServiceConfig())
 	if err != nil {
@@ -556,7 +807,7 @@ func (s *Server) Install() error {
 		return fmt.Errorf("erro ao instalar serviço: %w", err)
 	}
 
-	s.logger.Printf("✅ Serviço '%s' instalado com sucesso!", s.config.Name)
+	s.logger.Printf("✅ Serviço '%s' instalado com sucesso!", s.serviceName())
 	return nil
 }
 
@@ -582,18 +833,32 @@ func (s *Server) Uninstall() error {
 		return fmt.Errorf("erro ao desinstalar serviço: %w", err)
 	}
 
-	s.logger.Printf("✅ Serviço '%s' removido com sucesso!", s.config.Name)
+	s.logger.Printf("✅ Serviço '%s' removido com sucesso!", s.serviceName())
 	return nil
 }
 
 // createServiceConfig cria a configuração do serviço baseada na configuração do servidor
+// (ServerConfig.ServiceConfig: instância, inicialização, reinício em falha e conta)
 func (s *Server) createServiceConfig() *service.Config {
+	config := s.serviceConfig()
+
 	svcConfig := &service.Config{
-		Name:        s.config.Name,
+		Name:        s.serviceName(),
 		DisplayName: s.config.DisplayName,
 		Description: s.config.Description,
+		UserName:    config.UserName,
 		Arguments:   []string{"run"},
 	}
+	if config.Instance != "" {
+		// O processo da instância identifica-se pela variável (e carrega o .env.<instância>)
+		svcConfig.DisplayName = fmt.Sprintf("%s (%s)", s.config.DisplayName, config.Instance)
+		svcConfig.EnvVars = map[string]string{ServiceInstanceEnv: config.Instance}
+	}
+
+	startType := config.StartType
+	if startType == "" {
+		startType = ServiceStartAutomatic
+	}
 
 	// Adiciona configurações específicas por plataforma
 	if runtime.GOOS == "windows" {
@@ -602,10 +867,25 @@ func (s *Server) createServiceConfig() *service.Config {
 			"Dhcp",
 		}
 		svcConfig.Option = service.KeyValue{
-			"StartType":              "automatic",
-			"OnFailure":              "restart",
-			"OnFailureDelayDuration": "5s",
-			"OnFailureResetPeriod":   10,
+			"StartType": startType,
+		}
+		if startType == ServiceStartDelayed {
+			svcConfig.Option["StartType"] = ServiceStartAutomatic
+			svcConfig.Option["DelayedAutoStart"] = true
+		}
+		if config.Password != "" {
+			svcConfig.Option["Password"] = config.Password
+		}
+		if config.RestartOnFailure {
+			delay := config.RestartDelay
+			if delay <= 0 {
+				delay = 5 * time.Second
+			}
+			svcConfig.Option["OnFailure"] = "restart"
+			svcConfig.Option["OnFailureDelayDuration"] = delay.String()
+			svcConfig.Option["OnFailureResetPeriod"] = 10
+		} else {
+			svcConfig.Option["OnFailure"] = "noaction"
 		}
 	} else {
 		// Linux/Unix
@@ -613,18 +893,20 @@ func (s *Server) createServiceConfig() *service.Config {
 			"Requires=network.target",
 			"After=network-online.target syslog.target",
 		}
+		restart := "no"
+		if config.RestartOnFailure {
+			restart = "on-failure"
+		}
 		svcConfig.Option = service.KeyValue{
-			"Restart":        "always",
-			"RestartSec":     "5",
-			"User":           "godata",
-			"Group":          "godata",
-			"LimitNOFILE":    "65536",
-			"Type":           "notify",
-			"KillMode":       "mixed",
-			"TimeoutStopSec": "30",
+			"Restart":     restart,
+			"LimitNOFILE": 65536,
 		}
 	}
 
+	if len(config.Dependencies) > 0 {
+		svcConfig.Dependencies = config.Dependencies
+	}
+
 	return svcConfig
 }
 
@@ -747,7 +1029,7 @@ func (s *Server) isSystemRoute(path string) bool {
 
 // isEntityRoute verifica se o path é uma rota de entidade OData
 func (s *Server) isEntityRoute(path string) bool {
-	for name := range s.entities {
+	for name := range s.GetEntities() {
 		entityPath := fmt.Sprintf("%s/%s", s.config.RoutePrefix, name)
 
 		// Verifica se o path corresponde a qualquer padrão de rota de entidade
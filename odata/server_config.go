@@ -25,6 +25,9 @@ type ServerConfig struct {
 	DisplayName string
 	Description string
 
+	// Instalação como serviço do sistema (instância, inicialização, reinício em falha, conta)
+	ServiceConfig *ServiceConfig
+
 	// Configurações de host e porta
 	Host string
 	Port int
@@ -45,21 +48,99 @@ type ServerConfig struct {
 	// Configurações de log
 	EnableLogging bool
 	LogLevel      string
-	LogFile       string
+	LogFile       string // Destino dos logs em modo serviço: caminho de arquivo, "eventlog" ou "syslog" (vazio = stdout)
+	DebugErrors   bool   // Inclui innererror (tipo, mensagem original e stack trace) nas respostas de erro
+
+	// Rotação do arquivo de log (LogFile): tamanho, idade, arquivos mantidos e compressão
+	LogRotation *LogRotationConfig
+
+	// Recuperação de panics em handlers e eventos
+	RecoverConfig *RecoverConfig
+
+	// Endpoints de diagnóstico (/debug/pprof, /admin/stats), protegidos por role de administrador
+	DiagnosticsConfig *DiagnosticsConfig
+
+	// Modo de manutenção global: recusa escritas (ou todas as requisições) com 503, exceto
+	// roles e IPs liberados. Alternável pelo endpoint /admin/maintenance ou por sinal
+	Maintenance *MaintenanceConfig
+
+	// Retry-After das respostas de entidades desativadas ou somente leitura em tempo de
+	// execução (SetEntityEnabled, SetEntityReadOnly) e do modo de manutenção; 0 omite o cabeçalho
+	MaintenanceRetryAfter time.Duration
 
 	// Configurações de middleware
 	EnableCompression bool
 	MaxRequestSize    int64
 
+	// Compressão gzip/brotli das respostas (brotli, tamanho mínimo e Content-Types excluídos),
+	// aplicada quando EnableCompression está habilitado
+	CompressionConfig *CompressionConfig
+
+	// Ignora o X-HTTP-Method/X-HTTP-Method-Override dos POSTs (tunelamento de PUT, PATCH e
+	// DELETE para clientes atrás de proxies restritivos)
+	DisableMethodOverride bool
+
 	// Configurações de graceful shutdown
 	ShutdownTimeout time.Duration
 
+	// Restart sem downtime (Unix)
+	ReusePort     bool // Abre o socket com SO_REUSEPORT, permitindo que outra instância escute na mesma porta
+	EnableHandoff bool // SIGHUP inicia uma nova instância que herda o socket; a atual drena e encerra
+
 	// Configurações de banco de dados
-	DBLogSQL bool // Habilita/desabilita logs de queries SQL
+	DBLogSQL     bool          // Habilita/desabilita logs de queries SQL
+	QueryTimeout time.Duration // Timeout das queries por requisição (0 = sem timeout); excedido retorna 504
+
+	// Fuso horário dos literais de data/hora sem offset no $filter e nos payloads
+	// (nil = UTC). Datas são sempre gravadas e serializadas em UTC
+	TimeZone *time.Location
+
+	// Comparações de strings no $filter (eq, ne, in, contains, startswith, endswith) sem
+	// diferenciar maiúsculas/minúsculas. Entidades podem sobrescrever com WithCaseInsensitive
+	CaseInsensitive bool
+
+	// Serializa valores Edm.Decimal como strings JSON ("12.50") em vez de números exatos,
+	// para clientes que convertem números JSON em ponto flutuante
+	DecimalAsString bool
+
+	// IEEE754Compatible serializa Edm.Int64 e Edm.Decimal como strings JSON em todas as
+	// respostas. Sem ele, o cliente pode pedir por requisição (Accept/$format com
+	// IEEE754Compatible=true)
+	IEEE754Compatible bool
+
+	// Política de nomes das propriedades das entidades registradas com RegisterEntity (padrão:
+	// tag json). Entidades podem sobrescrever com WithNamingPolicy
+	NamingPolicy NamingPolicy
+
+	// Decodificação dos números nos corpos JSON de entrada (padrão: typed, com inteiros e
+	// Edm.Decimal convertidos pelo tipo da propriedade, sem perda de precisão)
+	JSONNumberMode JSONNumberMode
+
+	// FastJSONEncoding serializa as respostas de entidades com encoders pré-compilados por
+	// entidade e buffers reaproveitados, reduzindo alocações em coleções grandes
+	FastJSONEncoding bool
+
+	// Nó do gerador snowflake (primaryKey:"idGenerator:snowflake"), de 0 a 1023. Cada instância
+	// que grava nas mesmas tabelas precisa de um nó distinto
+	SnowflakeNodeID int64
+
+	// Provedor das chaves de criptografia das propriedades prop:"[Encrypted]"
+	EncryptionKeyProvider KeyProvider
+
+	// Armazenamento de arquivos do servidor (exportações, relatórios, mídias da aplicação)
+	Storage Storage
+
+	// Log de queries lentas (threshold e captura de EXPLAIN)
+	SlowQueryConfig *SlowQueryConfig
 
 	// Configurações de prefixo
 	RoutePrefix string
 
+	// ExternalBaseURL é a URL pública do serviço (ex: https://api.exemplo.com), usada nos links
+	// absolutos das respostas (@odata.context, @odata.id, Location). Vazia, os links usam o
+	// esquema e o host da requisição, respeitando X-Forwarded-Proto/X-Forwarded-Host
+	ExternalBaseURL string
+
 	// Configurações JWT
 	EnableJWT   bool
 	JWTConfig   *JWTConfig
@@ -77,12 +158,43 @@ type ServerConfig struct {
 	// Configurações de Audit Logging
 	AuditLogConfig *AuditLogConfig
 
+	// Auditoria HTTP (request/response com redação de campos)
+	HTTPAuditConfig *HTTPAuditConfig
+
 	// Performance: Desabilita JOIN automático para expand (força batching)
 	// Default: false (usa detecção automática baseada em relacionamento)
 	DisableJoinForExpand bool
 
+	// Máximo de entidades por coleção expandida ($expand de navegações 1:N). Coleções maiores
+	// são truncadas e recebem <Navegação>@odata.nextLink com o restante (0 = sem limite)
+	MaxExpandFanOut int
+
+	// Máximo de registros por resposta de coleção, mesmo sem $top. Resultados maiores são
+	// truncados e recebem @odata.nextLink e a anotação @godata.truncated (0 = sem limite)
+	MaxRowsPerRequest int
+
+	// Máximo de entidades de um POST em lote na coleção (array JSON, resposta 207 Multi-Status).
+	// 0 usa DefaultMaxBulkItems; valores negativos desabilitam o limite
+	MaxBulkItems int
+
+	// Máximo de navegações de um mesmo $expand buscadas em paralelo (padrão: 4; 1 = sequencial)
+	MaxExpandParallelism int
+
+	// $expand de entidades de outro provider (WithProvider): chaves por consulta (padrão: 500)
+	// e máximo de chaves distintas por navegação, acima do qual a requisição falha com 400
+	// (padrão: 10000)
+	FederatedExpandBatchSize int
+	MaxFederatedExpandKeys   int
+
 	// Configurações de PATCH OData 4.01
 	PatchRemovedFormat string // Formato aceito para @odata.removed: "both", "empty", "with_reason" (default: "both")
+
+	// Configurações de Batch ($batch)
+	BatchConfig *BatchConfig
+
+	// Sincronização de schema (apenas para desenvolvimento/protótipos)
+	AutoMigrate       bool // Cria/altera tabelas conforme os metadados das entidades ao iniciar
+	AutoMigrateDryRun bool // Apenas loga o DDL que seria executado
 }
 
 // DefaultServerConfig retorna uma configuração padrão do servidor
@@ -97,10 +209,11 @@ func DefaultServerConfig() *ServerConfig {
 		AllowedOrigins:        []string{"*"},
 		AllowedMethods:        []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:        []string{"*"},
-		ExposedHeaders:        []string{"OData-Version", "Content-Type"},
+		ExposedHeaders:        []string{"OData-Version", "Content-Type", "Location", "OData-EntityId"},
 		AllowCredentials:      false,
 		EnableLogging:         true,
 		LogLevel:              "INFO",
+		LogRotation:           DefaultLogRotationConfig(),
 		EnableCompression:     false,            // Desabilitado por padrão para evitar problemas
 		MaxRequestSize:        10 * 1024 * 1024, // 10MB
 		ShutdownTimeout:       30 * time.Second,
@@ -109,7 +222,20 @@ func DefaultServerConfig() *ServerConfig {
 		SecurityHeadersConfig: DefaultSecurityHeadersConfig(),
 		RateLimitConfig:       DefaultRateLimitConfig(),
 		AuditLogConfig:        DefaultAuditLogConfig(),
+		HTTPAuditConfig:       DefaultHTTPAuditConfig(),
 		DisableJoinForExpand:  false, // JOIN automático habilitado por padrão
+		MaxExpandParallelism:  DefaultMaxExpandParallelism,
 		PatchRemovedFormat:    "both", // Aceita ambos os formatos por padrão
+		BatchConfig:           DefaultBatchConfig(),
+		RecoverConfig:         DefaultRecoverConfig(),
+		CompressionConfig:     DefaultCompressionConfig(),
+		ServiceConfig:         DefaultServiceConfig(),
+		DiagnosticsConfig:     DefaultDiagnosticsConfig(),
+		Maintenance:           DefaultMaintenanceConfig(),
+		MaintenanceRetryAfter: time.Minute,
+		SlowQueryConfig:       DefaultSlowQueryConfig(),
+
+		FederatedExpandBatchSize: DefaultFederatedExpandBatchSize,
+		MaxFederatedExpandKeys:   DefaultMaxFederatedExpandKeys,
 	}
 }
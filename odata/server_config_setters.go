@@ -20,6 +20,13 @@ func (s *Server) SetRoutePrefix(prefix string) *Server {
 	return s
 }
 
+// SetExternalBaseURL define a URL pública do serviço usada nos links absolutos das respostas
+// (ex: https://api.exemplo.com quando o servidor está atrás de um proxy)
+func (s *Server) SetExternalBaseURL(baseURL string) *Server {
+	s.config.ExternalBaseURL = baseURL
+	return s
+}
+
 // SetCORS permite habilitar/desabilitar CORS
 func (s *Server) SetCORS(enabled bool) *Server {
 	s.config.EnableCORS = enabled
@@ -56,6 +63,62 @@ func (s *Server) SetLogLevel(level string) *Server {
 	return s
 }
 
+// SetLogFile define o destino dos logs ao executar como serviço: caminho de um arquivo com
+// rotação, LogSinkEventLog ou LogSinkSyslog
+func (s *Server) SetLogFile(target string) *Server {
+	s.config.LogFile = target
+	return s
+}
+
+// SetLogRotation configura a rotação do arquivo de log (nil usa a configuração padrão)
+func (s *Server) SetLogRotation(config *LogRotationConfig) *Server {
+	s.config.LogRotation = config
+	return s
+}
+
+// SetDebugErrors habilita innererror com stack trace nas respostas de erro (apenas desenvolvimento)
+func (s *Server) SetDebugErrors(enabled bool) *Server {
+	s.config.DebugErrors = enabled
+	return s
+}
+
+// SetRecoverConfig configura a recuperação de panics (conversão em erro 500, log e callback)
+func (s *Server) SetRecoverConfig(config *RecoverConfig) *Server {
+	s.config.RecoverConfig = config
+	return s
+}
+
+// SetDiagnostics configura e registra os endpoints de diagnóstico (/admin/stats,
+// /admin/goroutines e, opcionalmente, /debug/pprof). As rotas não podem ser removidas
+// depois de registradas
+func (s *Server) SetDiagnostics(config *DiagnosticsConfig) *Server {
+	s.config.DiagnosticsConfig = config
+	s.setupDiagnosticsRoutes()
+	return s
+}
+
+// SetHTTPAudit configura a auditoria HTTP (método, URL, status, duração, usuário, tenant e,
+// opcionalmente, os corpos com redação de campos sensíveis)
+func (s *Server) SetHTTPAudit(config *HTTPAuditConfig) *Server {
+	s.config.HTTPAuditConfig = config
+	return s
+}
+
+// SetServiceConfig configura a instalação como serviço do sistema (instância, tipo de
+// inicialização, reinício em falha e conta de execução)
+func (s *Server) SetServiceConfig(config *ServiceConfig) *Server {
+	s.config.ServiceConfig = config
+	return s
+}
+
+// SetCompressionConfig habilita a compressão gzip/brotli das respostas com a configuração
+// informada (nil usa a configuração padrão)
+func (s *Server) SetCompressionConfig(config *CompressionConfig) *Server {
+	s.config.EnableCompression = true
+	s.config.CompressionConfig = config
+	return s
+}
+
 // SetMaxRequestSize permite configurar o tamanho máximo de requisição
 func (s *Server) SetMaxRequestSize(size int64) *Server {
 	s.config.MaxRequestSize = size
@@ -68,6 +131,140 @@ func (s *Server) SetShutdownTimeout(timeout time.Duration) *Server {
 	return s
 }
 
+// SetQueryTimeout define o timeout das queries de cada requisição (0 desabilita).
+// Entidades podem sobrescrever o valor com WithQueryTimeout
+func (s *Server) SetQueryTimeout(timeout time.Duration) *Server {
+	s.config.QueryTimeout = timeout
+	return s
+}
+
+// SetTimeZone define o fuso horário dos literais de data/hora sem offset (nil = UTC)
+func (s *Server) SetTimeZone(location *time.Location) *Server {
+	s.config.TimeZone = location
+	return s
+}
+
+// SetCaseInsensitive torna as comparações de strings do $filter insensíveis a
+// maiúsculas/minúsculas. Entidades podem sobrescrever o valor com WithCaseInsensitive
+func (s *Server) SetCaseInsensitive(enabled bool) *Server {
+	s.config.CaseInsensitive = enabled
+	return s
+}
+
+// SetIEEE754Compatible serializa Edm.Int64 e Edm.Decimal como strings JSON em todas as
+// respostas, evitando perda de precisão em clientes JavaScript
+func (s *Server) SetIEEE754Compatible(enabled bool) *Server {
+	s.config.IEEE754Compatible = enabled
+	return s
+}
+
+// SetNamingPolicy define a política de nomes das propriedades (tag json, nome do campo,
+// camelCase, PascalCase ou snake_case). Vale para as entidades registradas depois da chamada
+func (s *Server) SetNamingPolicy(policy NamingPolicy) *Server {
+	s.config.NamingPolicy = policy
+	return s
+}
+
+// SetJSONNumberMode define como os números dos corpos JSON de entrada são decodificados:
+// pelo tipo da propriedade (typed, padrão), como json.Number ou como float64
+func (s *Server) SetJSONNumberMode(mode JSONNumberMode) *Server {
+	s.config.JSONNumberMode = mode
+	return s
+}
+
+// SetFastJSONEncoding habilita a serialização das respostas de entidades com encoders
+// pré-compilados por entidade, sem passar por map[string]interface{} nem reflexão
+func (s *Server) SetFastJSONEncoding(enabled bool) *Server {
+	s.config.FastJSONEncoding = enabled
+	return s
+}
+
+// SetMaxExpandFanOut limita a quantidade de entidades de cada coleção expandida; o restante
+// é indicado por <Navegação>@odata.nextLink (0 desabilita)
+func (s *Server) SetMaxExpandFanOut(limit int) *Server {
+	s.config.MaxExpandFanOut = limit
+	return s
+}
+
+// SetMaxRowsPerRequest limita os registros de cada resposta de coleção; o restante é
+// indicado por @odata.nextLink e pela anotação @godata.truncated (0 desabilita)
+func (s *Server) SetMaxRowsPerRequest(limit int) *Server {
+	s.config.MaxRowsPerRequest = limit
+	return s
+}
+
+// SetMaxBulkItems limita as entidades de um POST em lote na coleção (0 usa
+// DefaultMaxBulkItems; valores negativos desabilitam o limite)
+func (s *Server) SetMaxBulkItems(limit int) *Server {
+	s.config.MaxBulkItems = limit
+	return s
+}
+
+// SetMaxExpandParallelism define quantas navegações de um $expand são buscadas em paralelo
+// (1 desabilita o paralelismo)
+func (s *Server) SetMaxExpandParallelism(limit int) *Server {
+	s.config.MaxExpandParallelism = limit
+	return s
+}
+
+// SetFederatedExpandLimits define as chaves por consulta e o máximo de chaves distintas do
+// $expand de entidades de outro provider (WithProvider)
+func (s *Server) SetFederatedExpandLimits(batchSize, maxKeys int) *Server {
+	s.config.FederatedExpandBatchSize = batchSize
+	s.config.MaxFederatedExpandKeys = maxKeys
+	return s
+}
+
+// SetSnowflakeNodeID define o nó do gerador de IDs snowflake (0 a MaxSnowflakeNodeID). Deve
+// ser chamado antes da primeira inserção
+func (s *Server) SetSnowflakeNodeID(nodeID int64) *Server {
+	s.config.SnowflakeNodeID = nodeID
+	return s
+}
+
+// SetDecimalAsString serializa os valores Edm.Decimal como strings JSON
+func (s *Server) SetDecimalAsString(enabled bool) *Server {
+	s.config.DecimalAsString = enabled
+	return s
+}
+
+// SetEncryptionKeyProvider define o provedor das chaves usadas nas propriedades
+// prop:"[Encrypted]" (ex: NewStaticKeyProvider ou uma implementação sobre um KMS)
+func (s *Server) SetEncryptionKeyProvider(provider KeyProvider) *Server {
+	s.config.EncryptionKeyProvider = provider
+	return s
+}
+
+// SetStorage define o armazenamento de arquivos do servidor (ex: NewS3Storage,
+// NewGCSStorage, NewAzureBlobStorage), usado pelas exportações sem armazenamento próprio
+func (s *Server) SetStorage(storage Storage) *Server {
+	s.config.Storage = storage
+	return s
+}
+
+// Storage retorna o armazenamento de arquivos do servidor (nil se não configurado)
+func (s *Server) Storage() Storage {
+	return s.config.Storage
+}
+
+// SetSlowQueryConfig configura o log de queries lentas
+func (s *Server) SetSlowQueryConfig(config *SlowQueryConfig) *Server {
+	s.config.SlowQueryConfig = config
+	return s
+}
+
+// SetReusePort habilita SO_REUSEPORT no socket do servidor (Unix)
+func (s *Server) SetReusePort(enabled bool) *Server {
+	s.config.ReusePort = enabled
+	return s
+}
+
+// SetHandoff habilita o handoff do socket via SIGHUP para restart sem downtime (Unix)
+func (s *Server) SetHandoff(enabled bool) *Server {
+	s.config.EnableHandoff = enabled
+	return s
+}
+
 // SetTLS permite configurar certificados TLS
 func (s *Server) SetTLS(certFile, keyFile string) *Server {
 	s.config.CertFile = certFile
@@ -129,6 +326,32 @@ func (s *Server) SetAuditLog(enabled bool, logType string) *Server {
 	return s
 }
 
+// SetAutoMigrate habilita a sincronização de schema ao iniciar o servidor
+func (s *Server) SetAutoMigrate(enabled bool, dryRun bool) *Server {
+	s.config.AutoMigrate = enabled
+	s.config.AutoMigrateDryRun = dryRun
+	return s
+}
+
+// SetAsyncEvents configura o pool de workers dos handlers de evento assíncronos
+func (s *Server) SetAsyncEvents(config *AsyncEventConfig) *Server {
+	s.eventManager.SetAsyncConfig(config)
+	return s
+}
+
+// SetMaintenanceRetryAfter define o Retry-After das respostas de entidades desativadas ou
+// somente leitura (0 omite o cabeçalho)
+func (s *Server) SetMaintenanceRetryAfter(retryAfter time.Duration) *Server {
+	s.config.MaintenanceRetryAfter = retryAfter
+	return s
+}
+
+// SetBatchConfig define os limites e o timeout das requisições $batch
+func (s *Server) SetBatchConfig(config *BatchConfig) *Server {
+	s.config.BatchConfig = config
+	return s
+}
+
 // SetProvider permite trocar o provider de banco de dados
 func (s *Server) SetProvider(provider DatabaseProvider) *Server {
 	s.provider = provider
@@ -1,91 +1,157 @@
 package odata
 
 // OnEntityGet registra um handler para o evento EntityGet
-func (s *Server) OnEntityGet(entityName string, handler func(args EventArgs) error) {
-	s.eventManager.SubscribeFunc(EventEntityGet, entityName, handler)
+func (s *Server) OnEntityGet(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeFunc(EventEntityGet, entityName, handler, opts...)
 }
 
 // OnEntityList registra um handler para o evento EntityList
-func (s *Server) OnEntityList(entityName string, handler func(args EventArgs) error) {
-	s.eventManager.SubscribeFunc(EventEntityList, entityName, handler)
+func (s *Server) OnEntityList(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeFunc(EventEntityList, entityName, handler, opts...)
 }
 
 // OnEntityInserting registra um handler para o evento EntityInserting
-func (s *Server) OnEntityInserting(entityName string, handler func(args EventArgs) error) {
-	s.eventManager.SubscribeFunc(EventEntityInserting, entityName, handler)
+func (s *Server) OnEntityInserting(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeFunc(EventEntityInserting, entityName, handler, opts...)
 }
 
 // OnEntityInserted registra um handler para o evento EntityInserted
-func (s *Server) OnEntityInserted(entityName string, handler func(args EventArgs) error) {
-	s.eventManager.SubscribeFunc(EventEntityInserted, entityName, handler)
+func (s *Server) OnEntityInserted(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeFunc(EventEntityInserted, entityName, handler, opts...)
 }
 
 // OnEntityModifying registra um handler para o evento EntityModifying
-func (s *Server) OnEntityModifying(entityName string, handler func(args EventArgs) error) {
-	s.eventManager.SubscribeFunc(EventEntityModifying, entityName, handler)
+func (s *Server) OnEntityModifying(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeFunc(EventEntityModifying, entityName, handler, opts...)
 }
 
 // OnEntityModified registra um handler para o evento EntityModified
-func (s *Server) OnEntityModified(entityName string, handler func(args EventArgs) error) {
-	s.eventManager.SubscribeFunc(EventEntityModified, entityName, handler)
+func (s *Server) OnEntityModified(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeFunc(EventEntityModified, entityName, handler, opts...)
 }
 
 // OnEntityDeleting registra um handler para o evento EntityDeleting
-func (s *Server) OnEntityDeleting(entityName string, handler func(args EventArgs) error) {
-	s.eventManager.SubscribeFunc(EventEntityDeleting, entityName, handler)
+func (s *Server) OnEntityDeleting(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeFunc(EventEntityDeleting, entityName, handler, opts...)
 }
 
 // OnEntityDeleted registra um handler para o evento EntityDeleted
-func (s *Server) OnEntityDeleted(entityName string, handler func(args EventArgs) error) {
-	s.eventManager.SubscribeFunc(EventEntityDeleted, entityName, handler)
+func (s *Server) OnEntityDeleted(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeFunc(EventEntityDeleted, entityName, handler, opts...)
 }
 
 // OnEntityError registra um handler para o evento EntityError
-func (s *Server) OnEntityError(entityName string, handler func(args EventArgs) error) {
-	s.eventManager.SubscribeFunc(EventEntityError, entityName, handler)
+func (s *Server) OnEntityError(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeFunc(EventEntityError, entityName, handler, opts...)
 }
 
 // OnEntityGetGlobal registra um handler global para o evento EntityGet
-func (s *Server) OnEntityGetGlobal(handler func(args EventArgs) error) {
-	s.eventManager.SubscribeGlobalFunc(EventEntityGet, handler)
+func (s *Server) OnEntityGetGlobal(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventEntityGet, handler, opts...)
 }
 
 // OnEntityListGlobal registra um handler global para o evento EntityList
-func (s *Server) OnEntityListGlobal(handler func(args EventArgs) error) {
-	s.eventManager.SubscribeGlobalFunc(EventEntityList, handler)
+func (s *Server) OnEntityListGlobal(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventEntityList, handler, opts...)
 }
 
 // OnEntityInsertingGlobal registra um handler global para o evento EntityInserting
-func (s *Server) OnEntityInsertingGlobal(handler func(args EventArgs) error) {
-	s.eventManager.SubscribeGlobalFunc(EventEntityInserting, handler)
+func (s *Server) OnEntityInsertingGlobal(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventEntityInserting, handler, opts...)
 }
 
 // OnEntityInsertedGlobal registra um handler global para o evento EntityInserted
-func (s *Server) OnEntityInsertedGlobal(handler func(args EventArgs) error) {
-	s.eventManager.SubscribeGlobalFunc(EventEntityInserted, handler)
+func (s *Server) OnEntityInsertedGlobal(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventEntityInserted, handler, opts...)
 }
 
 // OnEntityModifyingGlobal registra um handler global para o evento EntityModifying
-func (s *Server) OnEntityModifyingGlobal(handler func(args EventArgs) error) {
-	s.eventManager.SubscribeGlobalFunc(EventEntityModifying, handler)
+func (s *Server) OnEntityModifyingGlobal(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventEntityModifying, handler, opts...)
 }
 
 // OnEntityModifiedGlobal registra um handler global para o evento EntityModified
-func (s *Server) OnEntityModifiedGlobal(handler func(args EventArgs) error) {
-	s.eventManager.SubscribeGlobalFunc(EventEntityModified, handler)
+func (s *Server) OnEntityModifiedGlobal(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventEntityModified, handler, opts...)
 }
 
 // OnEntityDeletingGlobal registra um handler global para o evento EntityDeleting
-func (s *Server) OnEntityDeletingGlobal(handler func(args EventArgs) error) {
-	s.eventManager.SubscribeGlobalFunc(EventEntityDeleting, handler)
+func (s *Server) OnEntityDeletingGlobal(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventEntityDeleting, handler, opts...)
 }
 
 // OnEntityDeletedGlobal registra um handler global para o evento EntityDeleted
-func (s *Server) OnEntityDeletedGlobal(handler func(args EventArgs) error) {
-	s.eventManager.SubscribeGlobalFunc(EventEntityDeleted, handler)
+func (s *Server) OnEntityDeletedGlobal(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventEntityDeleted, handler, opts...)
 }
 
 // OnEntityErrorGlobal registra um handler global para o evento EntityError
-func (s *Server) OnEntityErrorGlobal(handler func(args EventArgs) error) {
-	s.eventManager.SubscribeGlobalFunc(EventEntityError, handler)
+func (s *Server) OnEntityErrorGlobal(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventEntityError, handler, opts...)
+}
+
+// OnTransactionCommitted registra um handler global disparado após o commit de um changeset
+func (s *Server) OnTransactionCommitted(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventTransactionCommitted, handler, opts...)
+}
+
+// OnTransactionRolledBack registra um handler global disparado após o rollback de um changeset
+func (s *Server) OnTransactionRolledBack(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventTransactionRolledBack, handler, opts...)
+}
+
+// OnEntityInsertedAsync registra um handler assíncrono para o evento EntityInserted
+func (s *Server) OnEntityInsertedAsync(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeAsync(EventEntityInserted, entityName, EventHandlerFunc(handler), opts...)
+}
+
+// OnEntityModifiedAsync registra um handler assíncrono para o evento EntityModified
+func (s *Server) OnEntityModifiedAsync(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeAsync(EventEntityModified, entityName, EventHandlerFunc(handler), opts...)
+}
+
+// OnEntityDeletedAsync registra um handler assíncrono para o evento EntityDeleted
+func (s *Server) OnEntityDeletedAsync(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeAsync(EventEntityDeleted, entityName, EventHandlerFunc(handler), opts...)
+}
+
+// OnEntityInsertedGlobalAsync registra um handler global assíncrono para o evento EntityInserted
+func (s *Server) OnEntityInsertedGlobalAsync(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalAsync(EventEntityInserted, EventHandlerFunc(handler), opts...)
+}
+
+// OnEntityModifiedGlobalAsync registra um handler global assíncrono para o evento EntityModified
+func (s *Server) OnEntityModifiedGlobalAsync(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalAsync(EventEntityModified, EventHandlerFunc(handler), opts...)
+}
+
+// OnEntityDeletedGlobalAsync registra um handler global assíncrono para o evento EntityDeleted
+func (s *Server) OnEntityDeletedGlobalAsync(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalAsync(EventEntityDeleted, EventHandlerFunc(handler), opts...)
+}
+
+// ListEventHandlers lista os handlers (globais e específicos) aplicáveis a uma entidade,
+// na ordem em que serão executados
+func (s *Server) ListEventHandlers(entityName string) []EventHandlerInfo {
+	return s.eventManager.ListHandlers(entityName)
+}
+
+// OnQueryBuilt registra um handler disparado antes da execução do SQL gerado para a entidade
+func (s *Server) OnQueryBuilt(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeFunc(EventQueryBuilt, entityName, handler, opts...)
+}
+
+// OnQueryExecuted registra um handler disparado após a execução do SQL da entidade
+func (s *Server) OnQueryExecuted(entityName string, handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeFunc(EventQueryExecuted, entityName, handler, opts...)
+}
+
+// OnQueryBuiltGlobal registra um handler global disparado antes da execução de qualquer SQL
+func (s *Server) OnQueryBuiltGlobal(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventQueryBuilt, handler, opts...)
+}
+
+// OnQueryExecutedGlobal registra um handler global disparado após a execução de qualquer SQL
+func (s *Server) OnQueryExecutedGlobal(handler func(args EventArgs) error, opts ...EventHandlerOption) {
+	s.eventManager.SubscribeGlobalFunc(EventQueryExecuted, handler, opts...)
 }
@@ -6,7 +6,6 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/cors"
 	fiberlogger "github.com/gofiber/fiber/v3/middleware/logger"
-	fiberrecover "github.com/gofiber/fiber/v3/middleware/recover"
 )
 
 // =======================================================================================
@@ -15,7 +14,13 @@ import (
 
 // setupMultiTenantMiddlewares configura middlewares específicos para multi-tenant
 func (s *Server) setupMultiTenantMiddlewares() {
-	// Middleware de identificação de tenant (deve ser o primeiro)
+	// Tunelamento via X-HTTP-Method (antes do roteamento)
+	s.router.Use(s.MethodOverrideMiddleware())
+
+	// Compressão gzip/brotli (EnableCompression)
+	s.router.Use(s.CompressionMiddleware())
+
+	// Middleware de identificação de tenant (primeiro após o tunelamento)
 	s.router.Use(s.TenantMiddleware())
 
 	// Middleware de informações do tenant
@@ -38,10 +43,12 @@ func (s *Server) setupMultiTenantMiddlewares() {
 	if s.config.EnableLogging {
 		s.router.Use(fiberlogger.New(fiberlogger.Config{
 			Format: "${time} ${method} ${path} ${status} ${latency} [${locals:tenant_id}]\n",
+			Stream: s.accessLogStream(),
 		}))
 	}
 
-	s.router.Use(fiberrecover.New())
+	s.router.Use(s.HTTPAuditMiddleware())
+	s.router.Use(s.RecoverMiddleware())
 
 	// Middleware que injeta o servidor no contexto Fiber
 	s.router.Use(func(c fiber.Ctx) error {
@@ -49,6 +56,9 @@ func (s *Server) setupMultiTenantMiddlewares() {
 		return c.Next()
 	})
 
+	// Modo de manutenção (MaintenanceConfig), alternável em tempo de execução
+	s.router.Use(s.MaintenanceMiddleware())
+
 	// Middleware de rate limit se habilitado
 	if s.rateLimiter != nil {
 		s.router.Use(s.RateLimitMiddleware())
@@ -2,6 +2,8 @@ package odata
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/kardianos/service"
@@ -82,3 +84,69 @@ func (sw *ServiceWrapper) Stop(svc service.Service) error {
 
 	return nil
 }
+
+// =================================================================================================
+// CONFIGURAÇÃO DA INSTALAÇÃO DO SERVIÇO
+// =================================================================================================
+
+// Tipos de inicialização do serviço (ServiceConfig.StartType)
+const (
+	ServiceStartAutomatic = "automatic" // Inicia com o sistema
+	ServiceStartDelayed   = "delayed"   // Inicia com o sistema, após os serviços automáticos (Windows)
+	ServiceStartManual    = "manual"    // Inicia apenas sob demanda
+	ServiceStartDisabled  = "disabled"  // Não pode ser iniciado
+)
+
+// ServiceInstanceEnv é a variável de ambiente com a instância do serviço em execução. Com
+// ela, LoadEnvConfig também carrega o arquivo .env.<instância> (configurações da instância)
+const ServiceInstanceEnv = "SERVICE_INSTANCE"
+
+// ServiceConfig configura a instalação do servidor como serviço do sistema (nome, descrição
+// e nome de exibição vêm de ServerConfig.Name, Description e DisplayName)
+type ServiceConfig struct {
+	Instance         string        // Instância: sufixo do nome do serviço, permite várias instalações na mesma máquina
+	StartType        string        // automatic (padrão), delayed, manual ou disabled
+	RestartOnFailure bool          // Reinicia o serviço quando o processo termina com falha
+	RestartDelay     time.Duration // Espera antes de reiniciar (Windows)
+	UserName         string        // Conta de execução (Windows: ".\usuario" ou "NT AUTHORITY\NetworkService"; Linux: usuário do systemd). Vazio usa a conta do sistema
+	Password         string        // Senha da conta de execução (Windows)
+	Dependencies     []string      // Dependências do serviço (vazio usa as padrão da plataforma)
+}
+
+// DefaultServiceConfig retorna configuração padrão do serviço
+func DefaultServiceConfig() *ServiceConfig {
+	return &ServiceConfig{
+		StartType:        ServiceStartAutomatic,
+		RestartOnFailure: true,
+		RestartDelay:     5 * time.Second,
+	}
+}
+
+// validate verifica o tipo de inicialização e o nome da instância
+func (c *ServiceConfig) validate() error {
+	switch c.StartType {
+	case "", ServiceStartAutomatic, ServiceStartDelayed, ServiceStartManual, ServiceStartDisabled:
+	default:
+		return fmt.Errorf("tipo de inicialização do serviço inválido: %s", c.StartType)
+	}
+	if strings.ContainsAny(c.Instance, ` \/:*?"<>|`) {
+		return fmt.Errorf("nome de instância do serviço inválido: %q", c.Instance)
+	}
+	return nil
+}
+
+// serviceConfig retorna a configuração do serviço em uso
+func (s *Server) serviceConfig() *ServiceConfig {
+	if s.config == nil || s.config.ServiceConfig == nil {
+		return DefaultServiceConfig()
+	}
+	return s.config.ServiceConfig
+}
+
+// serviceName retorna o nome do serviço no sistema (com o sufixo da instância)
+func (s *Server) serviceName() string {
+	if instance := s.serviceConfig().Instance; instance != "" {
+		return s.config.Name + "-" + instance
+	}
+	return s.config.Name
+}
@@ -0,0 +1,388 @@
+package odata
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kardianos/service"
+)
+
+// =================================================================================================
+// LOGS DO SERVIÇO (ARQUIVO COM ROTAÇÃO, EVENT LOG, SYSLOG)
+// =================================================================================================
+
+// Destinos especiais de ServerConfig.LogFile. Ambos usam o log do sistema da plataforma em
+// que o serviço executa (Event Log no Windows, syslog no Linux/macOS)
+const (
+	LogSinkEventLog = "eventlog" // Event Log do Windows
+	LogSinkSyslog   = "syslog"   // syslog (Linux/macOS)
+)
+
+// logBackupTimeFormat é o formato do carimbo de data/hora nos nomes dos arquivos rotacionados
+// (app-20260102-150405.000.log), ordenável lexicograficamente
+const logBackupTimeFormat = "20060102-150405.000"
+
+// LogRotationConfig configura a rotação do arquivo de log (ServerConfig.LogFile)
+type LogRotationConfig struct {
+	MaxSize    int64         // Tamanho máximo do arquivo (bytes) antes de rotacionar (0 = sem limite)
+	MaxAge     time.Duration // Idade máxima do arquivo antes de rotacionar (0 = sem limite)
+	MaxBackups int           // Arquivos rotacionados mantidos; os mais antigos são removidos (0 = todos)
+	Compress   bool          // Comprime os arquivos rotacionados com gzip (.gz)
+}
+
+// DefaultLogRotationConfig retorna configuração padrão de rotação de logs
+func DefaultLogRotationConfig() *LogRotationConfig {
+	return &LogRotationConfig{
+		MaxSize:    100 * 1024 * 1024, // 100MB
+		MaxAge:     24 * time.Hour,
+		MaxBackups: 7,
+		Compress:   true,
+	}
+}
+
+// logRotationConfig retorna a configuração de rotação de logs em uso
+func (s *Server) logRotationConfig() *LogRotationConfig {
+	if s.config == nil || s.config.LogRotation == nil {
+		return DefaultLogRotationConfig()
+	}
+	return s.config.LogRotation
+}
+
+// redirectServiceLogs direciona os logs do servidor (logger, log padrão e log de requisições)
+// para ServerConfig.LogFile ao executar como serviço: "eventlog"/"syslog" usam o log do
+// sistema; qualquer outro valor é o caminho de um arquivo com rotação. Vazio mantém stdout
+func (s *Server) redirectServiceLogs(svc service.Service) error {
+	target := ""
+	if s.config != nil {
+		target = strings.TrimSpace(s.config.LogFile)
+	}
+
+	var output io.Writer
+	switch strings.ToLower(target) {
+	case "":
+		return nil
+	case LogSinkEventLog, LogSinkSyslog:
+		systemLogger, err := svc.SystemLogger(nil)
+		if err != nil {
+			return fmt.Errorf("erro ao abrir log do sistema: %w", err)
+		}
+		output = &systemLogWriter{logger: systemLogger}
+	default:
+		file, err := newRotatingFileWriter(target, *s.logRotationConfig())
+		if err != nil {
+			return err
+		}
+		s.logFile = file
+		output = file
+	}
+
+	s.setLogOutput(output, output)
+	return nil
+}
+
+// setLogOutput troca o destino dos logs do servidor e do log padrão
+func (s *Server) setLogOutput(output, stdOutput io.Writer) {
+	if s.logOutput != nil {
+		s.logOutput.set(output)
+	} else if s.logger != nil {
+		s.logger.SetOutput(output)
+	}
+	log.SetOutput(stdOutput)
+}
+
+// closeServiceLogs fecha o arquivo de log do serviço, aguardando a compressão dos rotacionados
+func (s *Server) closeServiceLogs() {
+	if s.logFile == nil {
+		return
+	}
+	s.setLogOutput(os.Stdout, os.Stderr)
+	if err := s.logFile.Close(); err != nil {
+		log.Printf("Aviso: erro ao fechar arquivo de log: %v", err)
+	}
+	s.logFile = nil
+}
+
+// accessLogStream retorna o destino do log de requisições (stdout até o redirecionamento)
+func (s *Server) accessLogStream() io.Writer {
+	if s.logOutput == nil {
+		return os.Stdout
+	}
+	return s.logOutput
+}
+
+// switchableWriter encaminha as escritas ao destino atual, que pode ser trocado em execução
+// (middlewares registrados antes do redirecionamento dos logs)
+type switchableWriter struct {
+	mu     sync.RWMutex
+	output io.Writer
+}
+
+func newSwitchableWriter(output io.Writer) *switchableWriter {
+	return &switchableWriter{output: output}
+}
+
+func (w *switchableWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.output.Write(p)
+}
+
+func (w *switchableWriter) set(output io.Writer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.output = output
+}
+
+// systemLogWriter grava cada linha de log no log do sistema (Event Log/syslog), com nível
+// de erro ou aviso conforme o marcador da mensagem
+type systemLogWriter struct {
+	logger service.Logger
+}
+
+func (w *systemLogWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\r\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+
+		var err error
+		switch {
+		case strings.Contains(line, "❌"):
+			err = w.logger.Error(line)
+		case strings.Contains(line, "⚠️"):
+			err = w.logger.Warning(line)
+		default:
+			err = w.logger.Info(line)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// rotatingFileWriter grava em um arquivo de log, rotacionando-o por tamanho ou idade. O
+// arquivo rotacionado recebe o carimbo de data/hora no nome e é comprimido e removido
+// (MaxBackups) em segundo plano
+type rotatingFileWriter struct {
+	path   string
+	config LogRotationConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	cleanupMu sync.Mutex     // Serializa compressão e limpeza dos rotacionados
+	cleanup   sync.WaitGroup // Compressões/limpezas em andamento
+}
+
+// newRotatingFileWriter abre (ou cria) o arquivo de log, criando o diretório se necessário
+func newRotatingFileWriter(path string, config LogRotationConfig) (*rotatingFileWriter, error) {
+	w := &rotatingFileWriter{path: path, config: config, now: time.Now}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// open abre o arquivo em modo append; a idade de um arquivo existente conta da última escrita
+func (w *rotatingFileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de log: %w", err)
+	}
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir arquivo de log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("erro ao abrir arquivo de log: %w", err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = w.now()
+	if w.size > 0 {
+		w.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+func (w *rotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if w.shouldRotate(len(p)) {
+		if err := w.rotate(); err != nil {
+			if w.file == nil {
+				return 0, err
+			}
+			fmt.Fprintf(os.Stderr, "Aviso: %v\n", err)
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// shouldRotate verifica se a escrita de n bytes excede o tamanho ou se o arquivo expirou.
+// Um arquivo vazio nunca é rotacionado, mesmo que a escrita sozinha exceda MaxSize
+func (w *rotatingFileWriter) shouldRotate(n int) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.config.MaxSize > 0 && w.size+int64(n) > w.config.MaxSize {
+		return true
+	}
+	return w.config.MaxAge > 0 && w.now().Sub(w.openedAt) >= w.config.MaxAge
+}
+
+// rotate renomeia o arquivo atual com o carimbo de data/hora e abre um novo
+func (w *rotatingFileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("erro ao fechar arquivo de log: %w", err)
+	}
+	w.file = nil
+
+	ext := filepath.Ext(w.path)
+	backup := strings.TrimSuffix(w.path, ext) + "-" + w.now().Format(logBackupTimeFormat) + ext
+	if err := os.Rename(w.path, backup); err != nil {
+		// Continua gravando no arquivo atual; a rotação é tentada novamente na próxima escrita
+		if openErr := w.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("erro ao rotacionar arquivo de log: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.cleanup.Add(1)
+	go func() {
+		defer w.cleanup.Done()
+		w.cleanupMu.Lock()
+		defer w.cleanupMu.Unlock()
+
+		if w.config.Compress {
+			if err := compressLogFile(backup); err != nil {
+				fmt.Fprintf(os.Stderr, "Aviso: erro ao comprimir log rotacionado %s: %v\n", backup, err)
+			}
+		}
+		if err := w.removeOldBackups(); err != nil {
+			fmt.Fprintf(os.Stderr, "Aviso: erro ao remover logs antigos: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// backups retorna os arquivos rotacionados (comprimidos ou não), do mais antigo ao mais recente
+func (w *rotatingFileWriter) backups() ([]string, error) {
+	dir := filepath.Dir(w.path)
+	ext := filepath.Ext(w.path)
+	prefix := strings.TrimSuffix(filepath.Base(w.path), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		if _, err := time.Parse(logBackupTimeFormat, strings.TrimSuffix(stamp, ext)); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(dir, name))
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// removeOldBackups remove os arquivos rotacionados além de MaxBackups
+func (w *rotatingFileWriter) removeOldBackups() error {
+	if w.config.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+	for len(backups) > w.config.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// Close fecha o arquivo e aguarda a compressão/limpeza dos arquivos rotacionados
+func (w *rotatingFileWriter) Close() error {
+	w.mu.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.mu.Unlock()
+
+	w.cleanup.Wait()
+	return err
+}
+
+// compressLogFile comprime o arquivo com gzip (arquivo.gz) e remove o original
+func compressLogFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
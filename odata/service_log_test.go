@@ -0,0 +1,167 @@
+package odata
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFileWriter(t *testing.T) {
+	t.Run("Rotação por tamanho com compressão e limite de arquivos", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "logs", "api.log")
+		writer, err := newRotatingFileWriter(path, LogRotationConfig{MaxSize: 20, MaxBackups: 2, Compress: true})
+		require.NoError(t, err)
+
+		clock := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+		writer.now = func() time.Time {
+			clock = clock.Add(time.Second)
+			return clock
+		}
+		for i := 1; i <= 4; i++ {
+			_, err := fmt.Fprintf(writer, "linha de log %d\n", i)
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.Close())
+
+		current, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "linha de log 4\n", string(current))
+
+		backups, err := writer.backups()
+		require.NoError(t, err)
+		require.Len(t, backups, 2)
+		for i, backup := range backups {
+			require.True(t, strings.HasSuffix(backup, ".log.gz"), backup)
+
+			file, err := os.Open(backup)
+			require.NoError(t, err)
+			gz, err := gzip.NewReader(file)
+			require.NoError(t, err)
+			content, err := io.ReadAll(gz)
+			require.NoError(t, err)
+			file.Close()
+			assert.Equal(t, fmt.Sprintf("linha de log %d\n", i+2), string(content))
+		}
+	})
+
+	t.Run("Rotação por idade sem compressão", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "api.log")
+		writer, err := newRotatingFileWriter(path, LogRotationConfig{MaxAge: time.Hour})
+		require.NoError(t, err)
+
+		clock := time.Now()
+		writer.now = func() time.Time { return clock }
+		writer.openedAt = clock
+
+		_, err = writer.Write([]byte("ontem\n"))
+		require.NoError(t, err)
+		_, err = writer.Write([]byte("ainda ontem\n"))
+		require.NoError(t, err)
+
+		clock = clock.Add(2 * time.Hour)
+		_, err = writer.Write([]byte("hoje\n"))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		backups, err := writer.backups()
+		require.NoError(t, err)
+		require.Len(t, backups, 1)
+		assert.True(t, strings.HasSuffix(backups[0], ".log"))
+		content, err := os.ReadFile(backups[0])
+		require.NoError(t, err)
+		assert.Equal(t, "ontem\nainda ontem\n", string(content))
+
+		current, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "hoje\n", string(current))
+	})
+}
+
+// fakeSystemLogger registra as mensagens enviadas ao log do sistema
+type fakeSystemLogger struct {
+	entries []string
+}
+
+func (l *fakeSystemLogger) record(level string, v ...interface{}) error {
+	l.entries = append(l.entries, level+": "+fmt.Sprint(v...))
+	return nil
+}
+
+func (l *fakeSystemLogger) Error(v ...interface{}) error   { return l.record("error", v...) }
+func (l *fakeSystemLogger) Warning(v ...interface{}) error { return l.record("warning", v...) }
+func (l *fakeSystemLogger) Info(v ...interface{}) error    { return l.record("info", v...) }
+func (l *fakeSystemLogger) Errorf(format string, a ...interface{}) error {
+	return l.record("error", fmt.Sprintf(format, a...))
+}
+func (l *fakeSystemLogger) Warningf(format string, a ...interface{}) error {
+	return l.record("warning", fmt.Sprintf(format, a...))
+}
+func (l *fakeSystemLogger) Infof(format string, a ...interface{}) error {
+	return l.record("info", fmt.Sprintf(format, a...))
+}
+
+func TestSystemLogWriter(t *testing.T) {
+	systemLogger := &fakeSystemLogger{}
+	writer := &systemLogWriter{logger: systemLogger}
+
+	input := []byte("servidor iniciado\n❌ Erro ao conectar\r\n⚠️ Aviso de pool\n\n")
+	n, err := writer.Write(input)
+	require.NoError(t, err)
+	assert.Equal(t, len(input), n)
+	assert.Equal(t, []string{
+		"info: servidor iniciado",
+		"error: ❌ Erro ao conectar",
+		"warning: ⚠️ Aviso de pool",
+	}, systemLogger.entries)
+}
+
+func TestServer_RedirectServiceLogs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "godata.log")
+	server := newServerWithConfig(nil, DefaultServerConfig())
+	server.SetLogFile(path).SetLogRotation(&LogRotationConfig{MaxSize: 1024 * 1024})
+
+	defaultOutput := log.Writer()
+	defer log.SetOutput(defaultOutput)
+
+	require.NoError(t, server.redirectServiceLogs(nil))
+	server.logger.Printf("mensagem do servidor")
+	_, err := server.accessLogStream().Write([]byte("GET /odata/Products 200\n"))
+	require.NoError(t, err)
+	server.closeServiceLogs()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "[OData] ")
+	assert.Contains(t, string(content), "mensagem do servidor")
+	assert.Contains(t, string(content), "GET /odata/Products 200")
+	assert.Equal(t, os.Stdout, server.logOutput.output)
+}
+
+func TestLoadEnvConfig_LogRotation(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"),
+		[]byte("SERVER_LOG_FILE=syslog\nSERVER_LOG_MAX_SIZE=1048576\nSERVER_LOG_MAX_AGE=12h\nSERVER_LOG_MAX_BACKUPS=3\nSERVER_LOG_COMPRESS=false\n"), 0o600))
+	t.Chdir(dir)
+
+	// LoadEnvConfig injeta as variáveis no ambiente: restaura ao final do teste
+	for _, key := range []string{"SERVER_LOG_FILE", "SERVER_LOG_MAX_SIZE", "SERVER_LOG_MAX_AGE", "SERVER_LOG_MAX_BACKUPS", "SERVER_LOG_COMPRESS", ServiceInstanceEnv} {
+		t.Setenv(key, "")
+	}
+
+	config, err := LoadEnvConfig()
+	require.NoError(t, err)
+
+	serverConfig := config.ToServerConfig()
+	assert.Equal(t, LogSinkSyslog, serverConfig.LogFile)
+	assert.Equal(t, &LogRotationConfig{MaxSize: 1024 * 1024, MaxAge: 12 * time.Hour, MaxBackups: 3}, serverConfig.LogRotation)
+}
@@ -0,0 +1,120 @@
+package odata
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// OPERAÇÕES NÃO VINCULADAS (FUNCTION IMPORTS / ACTION IMPORTS)
+// =======================================================================================
+
+// ServiceOperation é uma operação não vinculada publicada no $metadata
+type ServiceOperation struct {
+	Name       string
+	IsAction   bool // POST (ActionImport); false para GET (FunctionImport)
+	Parameters []ParameterInfo
+	ReturnType string // Tipo EDM do retorno (ex: "Collection(Default.Products)", "Edm.Decimal")
+}
+
+// Function registra uma função não vinculada em GET /Nome e /Nome(), publicada no $metadata
+// como FunctionImport. params é a struct (ou nil) com as tags `param` lidas por BindParams,
+// usada para documentar os parâmetros. Os handlers seguem a convenção de server.Get
+func (s *Server) Function(name string, params any, returnType string, handlers ...fiber.Handler) fiber.Router {
+	s.registerOperation(name, false, params, returnType)
+	s.Get("/"+name+"()", handlers...)
+	return s.Get("/"+name, handlers...)
+}
+
+// Action registra uma ação não vinculada em POST /Nome, publicada no $metadata como
+// ActionImport. params documenta os parâmetros (corpo da requisição)
+func (s *Server) Action(name string, params any, returnType string, handlers ...fiber.Handler) fiber.Router {
+	s.registerOperation(name, true, params, returnType)
+	return s.Post("/"+name, handlers...)
+}
+
+// GetServiceOperations retorna as operações não vinculadas registradas
+func (s *Server) GetServiceOperations() []ServiceOperation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]ServiceOperation(nil), s.operations...)
+}
+
+// registerOperation valida e armazena a descrição da operação
+func (s *Server) registerOperation(name string, isAction bool, params any, returnType string) {
+	if !isValidPresetName(name) {
+		s.logger.Fatalf("nome de operação inválido: '%s'", name)
+	}
+
+	var parameters []ParameterInfo
+	if params != nil {
+		var err error
+		if parameters, err = DescribeParams(params); err != nil {
+			s.logger.Fatalf("parâmetros da operação '%s' inválidos: %v", name, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, operation := range s.operations {
+		if operation.Name == name {
+			s.logger.Fatalf("operação '%s' já registrada", name)
+		}
+	}
+	s.operations = append(s.operations, ServiceOperation{
+		Name:       name,
+		IsAction:   isAction,
+		Parameters: parameters,
+		ReturnType: returnType,
+	})
+}
+
+// applyServiceOperations adiciona as funções, ações e os respectivos imports ao $metadata
+func (s *Server) applyServiceOperations(metadata *MetadataResponse) {
+	for _, operation := range s.GetServiceOperations() {
+		qualifiedName := "Default." + operation.Name
+		if operation.IsAction {
+			metadata.Actions = append(metadata.Actions, ActionMetadata{
+				Name:       operation.Name,
+				Namespace:  "Default",
+				Parameters: operation.Parameters,
+				ReturnType: operation.ReturnType,
+			})
+			metadata.ActionImports = append(metadata.ActionImports, OperationImportMetadata{
+				Name:   operation.Name,
+				Action: qualifiedName,
+				URL:    operation.Name,
+			})
+			continue
+		}
+
+		metadata.Functions = append(metadata.Functions, FunctionMetadata{
+			Name:       operation.Name,
+			Namespace:  "Default",
+			Parameters: operation.Parameters,
+			ReturnType: operation.ReturnType,
+		})
+		metadata.FunctionImports = append(metadata.FunctionImports, OperationImportMetadata{
+			Name:     operation.Name,
+			Function: qualifiedName,
+			URL:      operation.Name,
+		})
+	}
+}
+
+// buildFunctionImportEntries retorna as entradas de FunctionImport do service document
+func (s *Server) buildFunctionImportEntries() []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, operation := range s.GetServiceOperations() {
+		if operation.IsAction {
+			continue
+		}
+		entries = append(entries, map[string]interface{}{
+			"name": operation.Name,
+			"kind": "FunctionImport",
+			"url":  fmt.Sprintf("%s()", operation.Name),
+		})
+	}
+	return entries
+}
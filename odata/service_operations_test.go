@@ -0,0 +1,80 @@
+package odata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceOperations(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
+	server.setupEntityRoutes("Products")
+	server.router.Get(server.routePrefix()+"/$metadata", server.handleMetadata)
+	server.router.Get(server.routePrefix()+"/", server.handleServiceDocument)
+
+	server.Function("GetTopSellingProducts", topSellingParams{}, "Collection(Default.Products)", func(c fiber.Ctx) error {
+		var params topSellingParams
+		if err := BindParams(c, &params); err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"category": params.Category, "top": params.Top})
+	})
+	server.Action("RecalculatePrices", nil, "", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	get := func(target string) *http.Response {
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Operações publicadas no $metadata", func(t *testing.T) {
+		var metadata MetadataResponse
+		require.NoError(t, json.NewDecoder(get(server.routePrefix()+"/$metadata").Body).Decode(&metadata))
+
+		require.Len(t, metadata.Functions, 1)
+		function := metadata.Functions[0]
+		assert.Equal(t, "GetTopSellingProducts", function.Name)
+		assert.False(t, function.IsBound)
+		assert.Equal(t, "Collection(Default.Products)", function.ReturnType)
+		require.Len(t, function.Parameters, 6)
+		assert.Equal(t, ParameterInfo{Name: "category", Type: "Edm.String", Required: true, Description: "Categoria dos produtos"}, function.Parameters[0])
+		assert.Equal(t, []OperationImportMetadata{{Name: "GetTopSellingProducts", Function: "Default.GetTopSellingProducts", URL: "GetTopSellingProducts"}}, metadata.FunctionImports)
+
+		require.Len(t, metadata.Actions, 1)
+		assert.Equal(t, "RecalculatePrices", metadata.Actions[0].Name)
+		assert.Equal(t, []OperationImportMetadata{{Name: "RecalculatePrices", Action: "Default.RecalculatePrices", URL: "RecalculatePrices"}}, metadata.ActionImports)
+	})
+
+	t.Run("FunctionImport no service document", func(t *testing.T) {
+		var document struct {
+			Value []map[string]string `json:"value"`
+		}
+		require.NoError(t, json.NewDecoder(get(server.routePrefix()+"/").Body).Decode(&document))
+		assert.Contains(t, document.Value, map[string]string{"name": "GetTopSellingProducts", "kind": "FunctionImport", "url": "GetTopSellingProducts()"})
+		assert.Contains(t, document.Value, map[string]string{"name": "Products", "kind": "EntitySet", "url": "Products"})
+	})
+
+	t.Run("Chamada da função", func(t *testing.T) {
+		for _, target := range []string{"/GetTopSellingProducts?category=games", "/GetTopSellingProducts()?category=games"} {
+			resp := get(server.routePrefix() + target)
+			require.Equal(t, http.StatusOK, resp.StatusCode, target)
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, "games", body["category"])
+			assert.Equal(t, float64(10), body["top"])
+		}
+		assert.Equal(t, http.StatusBadRequest, get(server.routePrefix()+"/GetTopSellingProducts").StatusCode)
+
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodPost, server.routePrefix()+"/RecalculatePrices", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+}
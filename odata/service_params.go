@@ -0,0 +1,286 @@
+package odata
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// BINDING DE PARÂMETROS DE OPERAÇÕES
+// =======================================================================================
+
+// ParameterInfo descreve um parâmetro declarado com a tag `param`, para documentação da
+// operação
+type ParameterInfo struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // Tipo EDM (Edm.Int32, Edm.String, Collection(...))
+	Required    bool   `json:"required"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// paramField é um campo da struct de parâmetros com as opções das tags
+type paramField struct {
+	index int
+	info  ParameterInfo
+}
+
+// BindParams preenche a struct apontada por dst com os parâmetros da rota e da query
+// string, convertidos para o tipo de cada campo. Os campos são declarados com tags:
+//
+//	type TopSellingParams struct {
+//	    Category string    `param:"category,required" description:"Categoria dos produtos"`
+//	    Top      int       `param:"top" default:"10"`
+//	    Since    time.Time `param:"since"`
+//	    IDs      []int64   `param:"ids"` // ids=1,2,3 ou ids=1&ids=2
+//	}
+//
+// Parâmetros ausentes recebem o default (se houver); obrigatórios ausentes e valores
+// inválidos geram um *ODataError com status 400 e um detalhe por parâmetro, que pode ser
+// retornado diretamente pelo handler
+func BindParams(c fiber.Ctx, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("BindParams requires a pointer to struct, got %T", dst)
+	}
+	fields, err := paramFields(v.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	badRequest := NewODataError(ODataErrorCode(http.StatusBadRequest), "Invalid operation parameters").WithStatus(http.StatusBadRequest)
+	for _, field := range fields {
+		values := requestParamValues(c, field.info.Name)
+		if len(values) == 0 && field.info.Default != "" {
+			values = []string{field.info.Default}
+		}
+		if len(values) == 0 {
+			if field.info.Required {
+				badRequest.WithDetail("MissingParameter", fmt.Sprintf("Parameter '%s' is required", field.info.Name), field.info.Name)
+			}
+			continue
+		}
+
+		if err := setParamValue(v.Elem().Field(field.index), values); err != nil {
+			badRequest.WithDetail("InvalidParameter", fmt.Sprintf("Parameter '%s': %v", field.info.Name, err), field.info.Name)
+		}
+	}
+
+	if len(badRequest.Details) > 0 {
+		if len(badRequest.Details) == 1 {
+			badRequest.Message = badRequest.Details[0].Message
+			badRequest.Target = badRequest.Details[0].Target
+		}
+		return badRequest
+	}
+	return nil
+}
+
+// DescribeParams retorna a descrição dos parâmetros declarados na struct (ou ponteiro para
+// struct), na ordem dos campos
+func DescribeParams(params any) ([]ParameterInfo, error) {
+	t := reflect.TypeOf(params)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("DescribeParams requires a struct, got %T", params)
+	}
+
+	fields, err := paramFields(t)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]ParameterInfo, 0, len(fields))
+	for _, field := range fields {
+		infos = append(infos, field.info)
+	}
+	return infos, nil
+}
+
+// paramFields lê as tags `param`, `default` e `description` dos campos exportados
+func paramFields(t reflect.Type) ([]paramField, error) {
+	var fields []paramField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("param")
+		if tag == "" || tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		info := ParameterInfo{
+			Name:        strings.TrimSpace(parts[0]),
+			Default:     sf.Tag.Get("default"),
+			Description: sf.Tag.Get("description"),
+		}
+		for _, option := range parts[1:] {
+			if strings.TrimSpace(option) == "required" {
+				info.Required = true
+			}
+		}
+		if info.Name == "" {
+			info.Name = sf.Name
+		}
+
+		edmType, ok := paramEdmType(sf.Type)
+		if !ok {
+			return nil, fmt.Errorf("parameter '%s' has unsupported type %s", info.Name, sf.Type)
+		}
+		info.Type = edmType
+		fields = append(fields, paramField{index: i, info: info})
+	}
+	return fields, nil
+}
+
+// requestParamValues retorna os valores do parâmetro: da rota (:name) ou da query string,
+// aceitando repetição (ids=1&ids=2) e listas separadas por vírgula (ids=1,2)
+func requestParamValues(c fiber.Ctx, name string) []string {
+	if value := c.Params(name); value != "" {
+		return []string{value}
+	}
+
+	var values []string
+	for _, raw := range c.RequestCtx().QueryArgs().PeekMulti(name) {
+		if len(raw) > 0 {
+			values = append(values, string(raw))
+		}
+	}
+	return values
+}
+
+// setParamValue converte os valores e atribui ao campo
+func setParamValue(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		var items []string
+		for _, value := range values {
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setParamScalar(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	if len(values) > 1 {
+		return fmt.Errorf("multiple values are not allowed")
+	}
+	return setParamScalar(field, values[0])
+}
+
+// setParamScalar converte um valor textual para o tipo do campo
+func setParamScalar(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setParamScalar(ptr.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		parsed, err := parseParamTime(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a valid boolean", value)
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("'%s' is not a valid integer", value)
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("'%s' is not a valid unsigned integer", value)
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("'%s' is not a valid number", value)
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// parseParamTime aceita data/hora RFC3339 ou apenas a data (2006-01-02)
+func parseParamTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse("2006-01-02", value); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("'%s' is not a valid date/time (RFC3339 or YYYY-MM-DD)", value)
+}
+
+// paramEdmType retorna o tipo EDM correspondente ao tipo Go do campo
+func paramEdmType(t reflect.Type) (string, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return "Edm.DateTimeOffset", true
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "Edm.String", true
+	case reflect.Bool:
+		return "Edm.Boolean", true
+	case reflect.Int8:
+		return "Edm.SByte", true
+	case reflect.Uint8:
+		return "Edm.Byte", true
+	case reflect.Int16:
+		return "Edm.Int16", true
+	case reflect.Int32, reflect.Uint16:
+		return "Edm.Int32", true
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "Edm.Int64", true
+	case reflect.Float32:
+		return "Edm.Single", true
+	case reflect.Float64:
+		return "Edm.Double", true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Slice || t.Elem().Kind() == reflect.Uint8 {
+			return "", false
+		}
+		if elem, ok := paramEdmType(t.Elem()); ok {
+			return "Collection(" + elem + ")", true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,99 @@
+package odata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type topSellingParams struct {
+	Category string    `param:"category,required" description:"Categoria dos produtos"`
+	Top      int       `param:"top" default:"10"`
+	MinPrice *float64  `param:"min_price"`
+	Since    time.Time `param:"since"`
+	IDs      []int64   `param:"ids"`
+	Active   bool      `param:"active" default:"true"`
+	Internal string
+}
+
+func TestBindParams(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
+
+	var bound topSellingParams
+	server.router.Get("/Service/TopSelling/:category?", func(c fiber.Ctx) error {
+		bound = topSellingParams{}
+		if err := BindParams(c, &bound); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	get := func(target string) *http.Response {
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Converte os tipos e aplica defaults", func(t *testing.T) {
+		resp := get("/Service/TopSelling?category=games&min_price=9.5&since=2024-01-31&ids=1,2&ids=3")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "games", bound.Category)
+		assert.Equal(t, 10, bound.Top)
+		require.NotNil(t, bound.MinPrice)
+		assert.Equal(t, 9.5, *bound.MinPrice)
+		assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), bound.Since)
+		assert.Equal(t, []int64{1, 2, 3}, bound.IDs)
+		assert.True(t, bound.Active)
+	})
+
+	t.Run("Parâmetro de rota", func(t *testing.T) {
+		resp := get("/Service/TopSelling/books?top=3&active=false")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "books", bound.Category)
+		assert.Equal(t, 3, bound.Top)
+		assert.Nil(t, bound.MinPrice)
+		assert.False(t, bound.Active)
+	})
+
+	t.Run("Erros geram 400 com um detalhe por parâmetro", func(t *testing.T) {
+		resp := get("/Service/TopSelling?top=abc&since=ontem")
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var body ODataErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "BadRequest", body.Error.Code)
+		require.Len(t, body.Error.Details, 3)
+		assert.Equal(t, "category", body.Error.Details[0].Target)
+		assert.Equal(t, "MissingParameter", body.Error.Details[0].Code)
+		assert.Equal(t, "top", body.Error.Details[1].Target)
+		assert.Equal(t, "since", body.Error.Details[2].Target)
+	})
+
+	t.Run("Valor único", func(t *testing.T) {
+		resp := get("/Service/TopSelling?category=a&top=1&top=2")
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestDescribeParams(t *testing.T) {
+	params, err := DescribeParams(topSellingParams{})
+	require.NoError(t, err)
+	require.Len(t, params, 6)
+	assert.Equal(t, ParameterInfo{Name: "category", Type: "Edm.String", Required: true, Description: "Categoria dos produtos"}, params[0])
+	assert.Equal(t, ParameterInfo{Name: "top", Type: "Edm.Int64", Default: "10"}, params[1])
+	assert.Equal(t, "Edm.Double", params[2].Type)
+	assert.Equal(t, "Edm.DateTimeOffset", params[3].Type)
+	assert.Equal(t, "Collection(Edm.Int64)", params[4].Type)
+
+	_, err = DescribeParams(&struct {
+		Data map[string]string `param:"data"`
+	}{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package odata
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_CreateServiceConfig(t *testing.T) {
+	server := &Server{config: DefaultServerConfig()}
+
+	t.Run("Padrão", func(t *testing.T) {
+		svcConfig := server.createServiceConfig()
+		assert.Equal(t, "godata-service", svcConfig.Name)
+		assert.Equal(t, "GoData OData Service", svcConfig.DisplayName)
+		assert.Empty(t, svcConfig.UserName)
+		assert.Empty(t, svcConfig.EnvVars)
+		if runtime.GOOS == "windows" {
+			assert.Equal(t, "automatic", svcConfig.Option["StartType"])
+			assert.Equal(t, "restart", svcConfig.Option["OnFailure"])
+			assert.Equal(t, "5s", svcConfig.Option["OnFailureDelayDuration"])
+		} else {
+			assert.Equal(t, "on-failure", svcConfig.Option["Restart"])
+		}
+	})
+
+	t.Run("Instância e conta", func(t *testing.T) {
+		server.SetServiceConfig(&ServiceConfig{
+			Instance:         "api2",
+			StartType:        ServiceStartDelayed,
+			RestartOnFailure: false,
+			UserName:         `NT AUTHORITY\NetworkService`,
+			Dependencies:     []string{"MSSQLSERVER"},
+		})
+		defer server.SetServiceConfig(DefaultServiceConfig())
+
+		svcConfig := server.createServiceConfig()
+		assert.Equal(t, "godata-service-api2", svcConfig.Name)
+		assert.Equal(t, "GoData OData Service (api2)", svcConfig.DisplayName)
+		assert.Equal(t, map[string]string{ServiceInstanceEnv: "api2"}, svcConfig.EnvVars)
+		assert.Equal(t, `NT AUTHORITY\NetworkService`, svcConfig.UserName)
+		assert.Equal(t, []string{"MSSQLSERVER"}, svcConfig.Dependencies)
+		if runtime.GOOS == "windows" {
+			assert.Equal(t, "automatic", svcConfig.Option["StartType"])
+			assert.Equal(t, true, svcConfig.Option["DelayedAutoStart"])
+			assert.Equal(t, "noaction", svcConfig.Option["OnFailure"])
+		} else {
+			assert.Equal(t, "no", svcConfig.Option["Restart"])
+		}
+	})
+
+	t.Run("Configuração inválida", func(t *testing.T) {
+		server.SetServiceConfig(&ServiceConfig{StartType: "sometimes"})
+		defer server.SetServiceConfig(DefaultServiceConfig())
+		assert.ErrorContains(t, server.Install(), "tipo de inicialização")
+
+		server.SetServiceConfig(&ServiceConfig{Instance: "api 2"})
+		assert.ErrorContains(t, server.Install(), "instância")
+	})
+}
+
+func TestLoadEnvConfig_ServiceInstance(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"),
+		[]byte("SERVER_PORT=8080\nSERVICE_NAME=erp-api\nSERVICE_START_TYPE=manual\nSERVICE_RESTART_DELAY=30s\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env.filial2"), []byte("SERVER_PORT=8081\n"), 0o600))
+	t.Chdir(dir)
+
+	// LoadEnvConfig injeta as variáveis no ambiente: restaura ao final do teste
+	for _, key := range []string{"SERVER_PORT", "SERVICE_NAME", "SERVICE_START_TYPE", "SERVICE_RESTART_DELAY"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv(ServiceInstanceEnv, "filial2")
+
+	config, err := LoadEnvConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 8081, config.ServerPort)
+
+	serverConfig := config.ToServerConfig()
+	server := &Server{config: serverConfig}
+	assert.Equal(t, "erp-api-filial2", server.serviceName())
+	assert.Equal(t, ServiceStartManual, serverConfig.ServiceConfig.StartType)
+	assert.Equal(t, 30*time.Second, serverConfig.ServiceConfig.RestartDelay)
+	assert.True(t, serverConfig.ServiceConfig.RestartOnFailure)
+}
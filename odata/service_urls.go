@@ -0,0 +1,148 @@
+package odata
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// URLS CANÔNICAS (SERVICE ROOT, CONTEXT, @odata.id E LOCATION)
+// =======================================================================================
+
+// requestBaseURL retorna a origem pública do serviço: o ExternalBaseURL configurado ou o
+// esquema e o host da requisição. Atrás de um proxy, X-Forwarded-Proto e X-Forwarded-Host
+// (primeiro valor da lista) substituem os da conexão
+func (s *Server) requestBaseURL(c fiber.Ctx) string {
+	if s.config != nil && s.config.ExternalBaseURL != "" {
+		return strings.TrimRight(s.config.ExternalBaseURL, "/")
+	}
+
+	// Os valores da conexão são lidos direto da requisição: c.Protocol() e c.Hostname() já
+	// aplicam os headers X-Forwarded-* sem validá-los
+	scheme := firstForwardedValue(c.Get("X-Forwarded-Proto"))
+	if scheme != "http" && scheme != "https" {
+		scheme = "http"
+		if c.RequestCtx().IsTLS() {
+			scheme = "https"
+		}
+	}
+	host := firstForwardedValue(c.Get("X-Forwarded-Host"))
+	if host == "" || strings.ContainsAny(host, "/\\@ ") {
+		host = string(c.Request().Host())
+	}
+	// Montado em outra aplicação (Mount), as rotas públicas têm o prefixo da montagem
+	return scheme + "://" + host + s.mountPrefix
+}
+
+// firstForwardedValue retorna o primeiro valor de um header X-Forwarded-* (proxies em
+// cadeia acrescentam valores separados por vírgula)
+func firstForwardedValue(header string) string {
+	value, _, _ := strings.Cut(header, ",")
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// metadataURL retorna a URL absoluta do $metadata do prefixo de rota
+func (s *Server) metadataURL(c fiber.Ctx, prefix string) string {
+	return s.requestBaseURL(c) + prefix + "/$metadata"
+}
+
+// entityServiceRoute retorna o caminho público do entity set do serviço (nome de registro,
+// WithRoutePrefix e WithEntitySetName). Serviços não registrados usam o nome dos metadados
+func (s *Server) entityServiceRoute(service EntityService) entityRoute {
+	s.mu.RLock()
+	var name string
+	for registered, candidate := range s.entities {
+		if candidate == service {
+			name = registered
+			break
+		}
+	}
+	s.mu.RUnlock()
+
+	if name == "" {
+		name = service.GetMetadata().Name
+	}
+	return s.entityRoute(name)
+}
+
+// entityLinks monta as URLs canônicas das entidades de uma resposta, resolvendo o entity
+// set de cada serviço uma única vez por requisição
+type entityLinks struct {
+	server *Server
+	base   string
+	routes map[EntityService]entityRoute
+}
+
+// newEntityLinks cria o gerador de URLs da requisição
+func (s *Server) newEntityLinks(c fiber.Ctx) *entityLinks {
+	return &entityLinks{server: s, base: s.requestBaseURL(c), routes: make(map[EntityService]entityRoute)}
+}
+
+// route retorna o caminho público do entity set do serviço
+func (l *entityLinks) route(service EntityService) entityRoute {
+	route, ok := l.routes[service]
+	if !ok {
+		route = l.server.entityServiceRoute(service)
+		l.routes[service] = route
+	}
+	return route
+}
+
+// contextURL retorna o @odata.context do entity set com a projeção do $select/$expand (ex:
+// https://host/odata/$metadata#Products(Name,Category(Name))), terminado em /$entity para
+// entidades únicas
+func (l *entityLinks) contextURL(service EntityService, projection string, entity bool) string {
+	route := l.route(service)
+	context := l.base + route.prefix + "/$metadata#" + route.setName + projection
+	if entity {
+		context += "/$entity"
+	}
+	return context
+}
+
+// contextProjection monta a lista de seleção do @odata.context a partir do $select e do
+// $expand da requisição. Vazia quando não há projeção
+func contextProjection(c fiber.Ctx) string {
+	selectQuery, err := ParseSelectString(c.Context(), c.Query("$select"))
+	if err != nil {
+		return ""
+	}
+	expandQuery, err := ParseExpandString(c.Context(), c.Query("$expand"))
+	if err != nil {
+		return ""
+	}
+	return projectionList(selectQuery, expandQuery)
+}
+
+// projectionList retorna "(Prop1,Prop2,Nav(Sub))" com as propriedades selecionadas e as
+// navegações expandidas; navegações sem $select/$expand aninhados aparecem como "Nav()"
+func projectionList(selectQuery *GoDataSelectQuery, expandQuery *GoDataExpandQuery) string {
+	items := GetSelectedProperties(selectQuery)
+	if expandQuery != nil {
+		for _, item := range expandQuery.ExpandItems {
+			if len(item.Path) == 0 {
+				continue
+			}
+			nested := projectionList(item.Select, item.Expand)
+			if nested == "" {
+				nested = "()"
+			}
+			items = append(items, item.Path[0].Value+nested)
+		}
+	}
+	if len(items) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(items, ",") + ")"
+}
+
+// entityID retorna a URL canônica da entidade (ex: https://host/odata/Products(1)), ou false
+// quando os valores das chaves não estão presentes
+func (l *entityLinks) entityID(service EntityService, get func(name string) (interface{}, bool)) (string, bool) {
+	predicate, ok := entityKeyPredicate(service.GetMetadata(), get)
+	if !ok {
+		return "", false
+	}
+	return l.base + l.route(service).path() + predicate, true
+}
@@ -0,0 +1,119 @@
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_RequestBaseURL(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	app := fiber.New()
+	app.Get("/base", func(c fiber.Ctx) error {
+		return c.SendString(server.requestBaseURL(c))
+	})
+
+	base := func(t *testing.T, headers map[string]string) string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/base", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	assert.Equal(t, "http://example.com", base(t, nil))
+	assert.Equal(t, "https://api.exemplo.com", base(t, map[string]string{
+		"X-Forwarded-Proto": "HTTPS, http",
+		"X-Forwarded-Host":  "api.exemplo.com, proxy.interno",
+	}))
+	assert.Equal(t, "http://example.com", base(t, map[string]string{
+		"X-Forwarded-Proto": "javascript",
+		"X-Forwarded-Host":  "evil.com/path",
+	}), "valores inválidos são ignorados")
+
+	server.SetExternalBaseURL("https://public.exemplo.com/api/")
+	assert.Equal(t, "https://public.exemplo.com/api", base(t, map[string]string{"X-Forwarded-Host": "outro.com"}))
+}
+
+func TestServer_CanonicalURLs(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.config.ExternalBaseURL = "https://api.exemplo.com"
+	server.setEntityRoute("Products", entityRoute{prefix: "/api/v2", setName: "Produtos"})
+	server.setupEntityRoutes("Products")
+
+	request := func(t *testing.T, method, path, body string) (*http.Response, map[string]interface{}) {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json;odata.metadata=full")
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return resp, result
+	}
+
+	t.Run("Contexto e id usam o prefixo e o entity set", func(t *testing.T) {
+		_, body := request(t, http.MethodGet, "/api/v2/Produtos?$top=1", "")
+		assert.Equal(t, "https://api.exemplo.com/api/v2/$metadata#Produtos", body["@odata.context"])
+		entity := body["value"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "#Default.Produtos", entity["@odata.type"])
+		assert.Equal(t, "https://api.exemplo.com/api/v2/Produtos(1)", entity["@odata.id"])
+	})
+
+	t.Run("Contexto com projeção", func(t *testing.T) {
+		_, body := request(t, http.MethodGet, "/api/v2/Produtos?$select=name,price&$top=1", "")
+		assert.Equal(t, "https://api.exemplo.com/api/v2/$metadata#Produtos(name,price)", body["@odata.context"])
+
+		_, body = request(t, http.MethodGet, "/api/v2/Produtos(2)?$select=name", "")
+		assert.Equal(t, "https://api.exemplo.com/api/v2/$metadata#Produtos(name)/$entity", body["@odata.context"])
+	})
+
+	t.Run("Location do POST", func(t *testing.T) {
+		resp, body := request(t, http.MethodPost, "/api/v2/Produtos", `{"id": 10, "name": "Cabo", "price": 5}`)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "https://api.exemplo.com/api/v2/Produtos(10)", resp.Header.Get("Location"))
+		assert.Equal(t, "https://api.exemplo.com/api/v2/Produtos(10)", resp.Header.Get("OData-EntityId"))
+		assert.Equal(t, "https://api.exemplo.com/api/v2/Produtos(10)", body["@odata.id"])
+	})
+
+	t.Run("Location com chave gerada pelo banco", func(t *testing.T) {
+		resp, body := request(t, http.MethodPost, "/api/v2/Produtos", `{"name": "Hub", "price": 80}`)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		require.NotNil(t, body["id"])
+		assert.Equal(t, body["@odata.id"], resp.Header.Get("Location"))
+		assert.Equal(t, body["@odata.id"], resp.Header.Get("OData-EntityId"))
+	})
+}
+
+func TestProjectionList(t *testing.T) {
+	projection := func(t *testing.T, sel, expand string) string {
+		t.Helper()
+		selectQuery, err := ParseSelectString(context.Background(), sel)
+		require.NoError(t, err)
+		expandQuery, err := ParseExpandString(context.Background(), expand)
+		require.NoError(t, err)
+		return projectionList(selectQuery, expandQuery)
+	}
+
+	assert.Equal(t, "", projection(t, "", ""))
+	assert.Equal(t, "(Name,Price)", projection(t, "Name,Price", ""))
+	assert.Equal(t, "(Category())", projection(t, "", "Category"))
+	assert.Equal(t, "(Name,Category(Name),Items(Product()))", projection(t, "Name", "Category($select=Name),Items($expand=Product)"))
+}
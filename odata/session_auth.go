@@ -0,0 +1,258 @@
+package odata
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// AUTENTICAÇÃO POR SESSÃO (COOKIE) COM PROTEÇÃO CSRF
+// =======================================================================================
+
+// SessionAuthConfig configurações para autenticação por cookie de sessão, voltada a interfaces
+// administrativas no navegador. A sessão é um cookie assinado (HMAC-SHA256) e HttpOnly com o
+// usuário e o token CSRF; requisições que alteram dados precisam reenviar o token no header
+type SessionAuthConfig struct {
+	SecretKey    string        // Chave HMAC de assinatura do cookie (obrigatória)
+	CookieName   string        // Nome do cookie de sessão (padrão: "odata_session")
+	CookiePath   string        // Path dos cookies (padrão: "/")
+	CookieDomain string        // Domínio dos cookies (opcional)
+	Secure       bool          // Envia os cookies apenas via HTTPS
+	SameSite     string        // Strict, Lax ou None (padrão: Strict)
+	MaxAge       time.Duration // Duração da sessão (padrão: 8h)
+
+	// CSRF: o token é vinculado à sessão e fica no cookie CSRFCookieName, legível pelo
+	// JavaScript, para ser reenviado em CSRFHeaderName nos métodos POST, PUT, PATCH e DELETE
+	CSRFCookieName string // Padrão: "odata_csrf"
+	CSRFHeaderName string // Padrão: "X-CSRF-Token"
+
+	// Fallback atende requisições sem cookie de sessão ou com header Authorization (ex:
+	// NewRouterJWTAuth para clientes de API). Sem fallback, essas requisições recebem 401
+	Fallback fiber.Handler
+}
+
+// DefaultSessionAuthConfig retorna a configuração padrão de sessão
+func DefaultSessionAuthConfig() *SessionAuthConfig {
+	return &SessionAuthConfig{
+		CookieName:     "odata_session",
+		CookiePath:     "/",
+		Secure:         true,
+		SameSite:       fiber.CookieSameSiteStrictMode,
+		MaxAge:         8 * time.Hour,
+		CSRFCookieName: "odata_csrf",
+		CSRFHeaderName: "X-CSRF-Token",
+	}
+}
+
+// sessionPayload é o conteúdo assinado do cookie de sessão
+type sessionPayload struct {
+	User      *UserIdentity `json:"user"`
+	CSRFToken string        `json:"csrf"`
+	ExpiresAt int64         `json:"exp"`
+}
+
+// sessionCSRFKey é a chave do token CSRF da sessão no contexto
+const sessionCSRFKey = "session_csrf"
+
+// withDefaults retorna uma cópia da configuração com os valores padrão preenchidos
+func (cfg SessionAuthConfig) withDefaults() *SessionAuthConfig {
+	defaults := DefaultSessionAuthConfig()
+	if cfg.CookieName == "" {
+		cfg.CookieName = defaults.CookieName
+	}
+	if cfg.CookiePath == "" {
+		cfg.CookiePath = defaults.CookiePath
+	}
+	if cfg.SameSite == "" {
+		cfg.SameSite = defaults.SameSite
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = defaults.MaxAge
+	}
+	if cfg.CSRFCookieName == "" {
+		cfg.CSRFCookieName = defaults.CSRFCookieName
+	}
+	if cfg.CSRFHeaderName == "" {
+		cfg.CSRFHeaderName = defaults.CSRFHeaderName
+	}
+	return &cfg
+}
+
+// NewRouterSessionAuth retorna middleware de autenticação por cookie de sessão. O usuário da
+// sessão fica disponível em GetCurrentUser e os métodos que alteram dados exigem o token CSRF.
+// Requisições com header Authorization (ex: Bearer) seguem para o Fallback, sem CSRF
+func (s *Server) NewRouterSessionAuth(config *SessionAuthConfig) fiber.Handler {
+	if config == nil || config.SecretKey == "" {
+		panic("Session SecretKey é obrigatório para autenticação por sessão")
+	}
+	cfg := config.withDefaults()
+
+	unauthorized := func(c fiber.Ctx) error {
+		if cfg.Fallback != nil {
+			return cfg.Fallback(c)
+		}
+		return s.writeODataError(c, fiber.StatusUnauthorized, NewODataError("Unauthorized", "Sessão não encontrada ou expirada"), nil)
+	}
+
+	return func(c fiber.Ctx) error {
+		// Clientes de API autenticam pelo header; cookies não são enviados automaticamente nesse caso
+		if c.Get("Authorization") != "" {
+			return unauthorized(c)
+		}
+
+		value := c.Cookies(cfg.CookieName)
+		if value == "" {
+			return unauthorized(c)
+		}
+		payload, err := cfg.decodeSession(value, time.Now())
+		if err != nil {
+			if s.config.EnableLogging {
+				s.logger.Printf("❌ Session: Cookie inválido para %s %s - Erro: %v", c.Method(), c.Path(), err)
+			}
+			return unauthorized(c)
+		}
+
+		if isMutatingMethod(c.Method()) {
+			token := c.Get(cfg.CSRFHeaderName)
+			if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(payload.CSRFToken)) != 1 {
+				if s.config.EnableLogging {
+					s.logger.Printf("❌ Session: Token CSRF inválido para %s %s", c.Method(), c.Path())
+				}
+				return s.writeODataError(c, fiber.StatusForbidden, NewODataError("Forbidden", "Invalid or missing CSRF token"), nil)
+			}
+		}
+
+		c.Locals(UserContextKey, payload.User)
+		c.Locals(sessionCSRFKey, payload.CSRFToken)
+		return c.Next()
+	}
+}
+
+// CreateSession inicia a sessão do usuário (ex: na rota de login), gravando o cookie de sessão
+// e o cookie CSRF. Retorna o token CSRF, que também pode ser enviado no corpo da resposta
+func (cfg *SessionAuthConfig) CreateSession(c fiber.Ctx, user *UserIdentity) (string, error) {
+	if cfg.SecretKey == "" {
+		return "", fmt.Errorf("session secret key is required")
+	}
+	if user == nil {
+		return "", fmt.Errorf("session user is required")
+	}
+	cfg = cfg.withDefaults()
+
+	csrf, err := newCSRFToken()
+	if err != nil {
+		return "", err
+	}
+	expiresAt := time.Now().Add(cfg.MaxAge)
+	value, err := cfg.encodeSession(sessionPayload{User: user, CSRFToken: csrf, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return "", err
+	}
+
+	c.Cookie(cfg.cookie(cfg.CookieName, value, expiresAt, true))
+	c.Cookie(cfg.cookie(cfg.CSRFCookieName, csrf, expiresAt, false))
+	return csrf, nil
+}
+
+// DestroySession encerra a sessão (ex: na rota de logout), expirando os cookies
+func (cfg *SessionAuthConfig) DestroySession(c fiber.Ctx) {
+	cfg = cfg.withDefaults()
+	expired := time.Unix(0, 0)
+	c.Cookie(cfg.cookie(cfg.CookieName, "", expired, true))
+	c.Cookie(cfg.cookie(cfg.CSRFCookieName, "", expired, false))
+}
+
+// GetCSRFToken retorna o token CSRF da sessão atual (vazio sem sessão)
+func GetCSRFToken(c fiber.Ctx) string {
+	if token, ok := c.Locals(sessionCSRFKey).(string); ok {
+		return token
+	}
+	return ""
+}
+
+// cookie monta um cookie de sessão com os atributos da configuração
+func (cfg *SessionAuthConfig) cookie(name, value string, expiresAt time.Time, httpOnly bool) *fiber.Cookie {
+	maxAge := int(time.Until(expiresAt).Seconds())
+	if maxAge <= 0 {
+		maxAge = -1
+	}
+	return &fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     cfg.CookiePath,
+		Domain:   cfg.CookieDomain,
+		Expires:  expiresAt,
+		MaxAge:   maxAge,
+		Secure:   cfg.Secure,
+		HTTPOnly: httpOnly,
+		SameSite: cfg.SameSite,
+	}
+}
+
+// encodeSession serializa e assina o payload no formato base64url(json).base64url(hmac)
+func (cfg *SessionAuthConfig) encodeSession(payload sessionPayload) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode session: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + cfg.sign(encoded), nil
+}
+
+// decodeSession valida a assinatura e a expiração do cookie de sessão
+func (cfg *SessionAuthConfig) decodeSession(value string, now time.Time) (*sessionPayload, error) {
+	encoded, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(cfg.sign(encoded))) {
+		return nil, fmt.Errorf("invalid session signature")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session encoding: %w", err)
+	}
+
+	var payload sessionPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("invalid session payload: %w", err)
+	}
+	if payload.User == nil || payload.CSRFToken == "" {
+		return nil, fmt.Errorf("incomplete session payload")
+	}
+	if now.Unix() >= payload.ExpiresAt {
+		return nil, fmt.Errorf("session expired")
+	}
+	return &payload, nil
+}
+
+// sign calcula o HMAC-SHA256 do valor com a chave da sessão
+func (cfg *SessionAuthConfig) sign(value string) string {
+	mac := hmac.New(sha256.New, []byte(cfg.SecretKey))
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// newCSRFToken gera um token CSRF aleatório
+func newCSRFToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate csrf token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// isMutatingMethod verifica se o método HTTP altera dados e exige o token CSRF
+func isMutatingMethod(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package odata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionAuth(t *testing.T) {
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New(fiber.Config{ErrorHandler: server.handleFiberError})
+
+	jwtConfig := &JWTConfig{SecretKey: "api-secret", ExpiresIn: time.Hour, ContextKey: "user"}
+	sessions := DefaultSessionAuthConfig()
+	sessions.SecretKey = "session-secret"
+	sessions.Fallback = server.NewRouterJWTAuth(jwtConfig)
+
+	server.router.Post("/login", func(c fiber.Ctx) error {
+		csrf, err := sessions.CreateSession(c, &UserIdentity{Username: "ana", Roles: []string{"admin"}})
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"csrf": csrf})
+	})
+	server.router.Post("/logout", func(c fiber.Ctx) error {
+		sessions.DestroySession(c)
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	server.router.Use("/odata", server.NewRouterSessionAuth(sessions))
+	server.setupEntityRoutes("Products")
+
+	resp, err := server.router.Test(httptest.NewRequest(http.MethodPost, "/login", nil))
+	require.NoError(t, err)
+	cookies := resp.Cookies()
+	require.Len(t, cookies, 2)
+	session, csrf := cookies[0], cookies[1]
+	assert.Equal(t, "odata_session", session.Name)
+	assert.True(t, session.HttpOnly)
+	assert.True(t, session.Secure)
+	assert.Equal(t, http.SameSiteStrictMode, session.SameSite)
+	assert.Equal(t, "odata_csrf", csrf.Name)
+	assert.False(t, csrf.HttpOnly, "o token CSRF é lido pelo JavaScript")
+
+	request := func(method, target, body string, cookies []*http.Cookie, headers map[string]string) int {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	t.Run("Leitura com sessão", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/odata/Products", "", []*http.Cookie{session}, nil))
+		assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "/odata/Products", "", nil, nil))
+	})
+
+	t.Run("Alteração exige token CSRF", func(t *testing.T) {
+		body := `{"id":4,"name":"Cabo","price":5}`
+		assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/odata/Products", body, []*http.Cookie{session, csrf}, nil))
+		assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/odata/Products", body, []*http.Cookie{session}, map[string]string{"X-CSRF-Token": "forged"}))
+		assert.Equal(t, http.StatusCreated, request(http.MethodPost, "/odata/Products", body, []*http.Cookie{session}, map[string]string{"X-CSRF-Token": csrf.Value}))
+	})
+
+	t.Run("Cookie adulterado", func(t *testing.T) {
+		tampered := &http.Cookie{Name: session.Name, Value: session.Value[:len(session.Value)-2] + "xx"}
+		assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "/odata/Products", "", []*http.Cookie{tampered}, nil))
+	})
+
+	t.Run("Clientes de API usam JWT sem CSRF", func(t *testing.T) {
+		token, err := GenerateJWT(jwt.MapClaims{"username": "api"}, jwtConfig)
+		require.NoError(t, err)
+		bearer := map[string]string{"Authorization": "Bearer " + token}
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/odata/Products", "", nil, bearer))
+		assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/odata/Products(4)", "", nil, bearer))
+		assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "/odata/Products", "", nil, map[string]string{"Authorization": "Bearer invalid"}))
+	})
+
+	t.Run("Logout expira os cookies", func(t *testing.T) {
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodPost, "/logout", nil))
+		require.NoError(t, err)
+		for _, cookie := range resp.Cookies() {
+			assert.Empty(t, cookie.Value)
+			assert.Less(t, cookie.MaxAge, 0)
+		}
+	})
+
+	t.Run("Sessão expirada", func(t *testing.T) {
+		cfg := sessions.withDefaults()
+		value, err := cfg.encodeSession(sessionPayload{User: &UserIdentity{Username: "ana"}, CSRFToken: "x", ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+		require.NoError(t, err)
+		_, err = cfg.decodeSession(value, time.Now())
+		assert.ErrorContains(t, err, "expired")
+	})
+}
@@ -0,0 +1,245 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// LOG DE QUERIES LENTAS (THRESHOLD E CAPTURA DE EXPLAIN)
+// =======================================================================================
+
+// SlowQueryConfig configura o registro de queries que excedem o threshold
+type SlowQueryConfig struct {
+	// Habilita/desabilita o log de queries lentas
+	Enabled bool
+
+	// Duração a partir da qual a query é considerada lenta (padrão: 1s)
+	Threshold time.Duration
+
+	// Captura o plano de execução (EXPLAIN) em PostgreSQL e MySQL
+	CaptureExplain bool
+
+	// Timeout do EXPLAIN, executado em background após a query (padrão: 5s)
+	ExplainTimeout time.Duration
+
+	// Armazena as entradas para análise offline (opcional; ex: NewMemorySlowQueryStore)
+	Store SlowQueryStore
+}
+
+// DefaultSlowQueryConfig retorna configuração padrão do log de queries lentas (desabilitado)
+func DefaultSlowQueryConfig() *SlowQueryConfig {
+	return &SlowQueryConfig{
+		Enabled:        false,
+		Threshold:      1 * time.Second,
+		CaptureExplain: false,
+		ExplainTimeout: 5 * time.Second,
+	}
+}
+
+// SlowQueryEntry representa uma query lenta registrada
+type SlowQueryEntry struct {
+	Timestamp  time.Time     `json:"timestamp"`
+	EntityName string        `json:"entity_name"`
+	Operation  string        `json:"operation"`
+	URL        string        `json:"url,omitempty"` // URL OData que originou a query
+	SQL        string        `json:"sql"`
+	Args       []any         `json:"args,omitempty"`
+	Duration   time.Duration `json:"-"`
+	DurationMs int64         `json:"duration_ms"`
+	RowCount   int64         `json:"row_count"`
+	Error      string        `json:"error,omitempty"`
+	Plan       string        `json:"plan,omitempty"` // Saída do EXPLAIN, quando capturada
+}
+
+// SlowQueryStore armazena queries lentas para análise posterior
+type SlowQueryStore interface {
+	Save(entry SlowQueryEntry) error
+}
+
+// MemorySlowQueryStore mantém as últimas queries lentas em memória
+type MemorySlowQueryStore struct {
+	mu      sync.Mutex
+	entries []SlowQueryEntry
+	size    int
+}
+
+// NewMemorySlowQueryStore cria um store em memória com capacidade para size entradas
+func NewMemorySlowQueryStore(size int) *MemorySlowQueryStore {
+	if size <= 0 {
+		size = 100
+	}
+	return &MemorySlowQueryStore{size: size}
+}
+
+// Save registra a entrada, descartando a mais antiga quando a capacidade é atingida
+func (m *MemorySlowQueryStore) Save(entry SlowQueryEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = append(m.entries, entry)
+	if len(m.entries) > m.size {
+		m.entries = m.entries[len(m.entries)-m.size:]
+	}
+	return nil
+}
+
+// Entries retorna uma cópia das entradas registradas (da mais antiga para a mais recente)
+func (m *MemorySlowQueryStore) Entries() []SlowQueryEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := make([]SlowQueryEntry, len(m.entries))
+	copy(entries, m.entries)
+	return entries
+}
+
+// recordIfSlow registra a query se a duração exceder o threshold configurado
+func (t *queryTrace) recordIfSlow(duration time.Duration, rowCount int64, err error) {
+	s := t.service
+	if s.server == nil || s.server.config == nil {
+		return
+	}
+	config := s.server.config.SlowQueryConfig
+	if config == nil || !config.Enabled || duration < config.Threshold {
+		return
+	}
+
+	entry := SlowQueryEntry{
+		Timestamp:  time.Now(),
+		EntityName: s.metadata.Name,
+		Operation:  t.operation,
+		URL:        requestURLFromContext(t.ctx),
+		SQL:        t.query,
+		Args:       t.args,
+		Duration:   duration,
+		DurationMs: duration.Milliseconds(),
+		RowCount:   rowCount,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if config.CaptureExplain && s.supportsExplain() {
+		// O EXPLAIN roda em background para não atrasar ainda mais a requisição
+		go func() {
+			entry.Plan = s.explainQuery(t.ctx, config, entry.SQL, entry.Args)
+			s.server.recordSlowQuery(config, entry)
+		}()
+		return
+	}
+	s.server.recordSlowQuery(config, entry)
+}
+
+// recordSlowQuery loga a query lenta e a envia ao store configurado
+func (s *Server) recordSlowQuery(config *SlowQueryConfig, entry SlowQueryEntry) {
+	if s.logger != nil {
+		s.logger.Printf("🐢 [SLOW QUERY] %s %s em %s (%d linhas) URL=%s", entry.EntityName, entry.Operation, entry.Duration, entry.RowCount, entry.URL)
+		s.logger.Printf("🐢 [SLOW QUERY] SQL: %s", entry.SQL)
+		if len(entry.Args) > 0 {
+			s.logger.Printf("🐢 [SLOW QUERY] ARGS: %v", entry.Args)
+		}
+		if entry.Error != "" {
+			s.logger.Printf("🐢 [SLOW QUERY] ERRO: %s", entry.Error)
+		}
+		if entry.Plan != "" {
+			s.logger.Printf("🐢 [SLOW QUERY] PLANO:\n%s", entry.Plan)
+		}
+	}
+
+	if config.Store != nil {
+		if err := config.Store.Save(entry); err != nil && s.logger != nil {
+			s.logger.Printf("Erro ao armazenar query lenta: %v", err)
+		}
+	}
+}
+
+// supportsExplain indica se o plano pode ser capturado no banco do provider
+func (s *BaseEntityService) supportsExplain() bool {
+	if s.provider == nil {
+		return false
+	}
+	switch GetDialect(s.provider.GetDriverName()).GetName() {
+	case "postgresql", "mysql":
+		return true
+	}
+	return false
+}
+
+// explainQuery executa EXPLAIN da query (sem executá-la) e retorna o plano em texto. O
+// contexto da requisição mantém os valores (tracing), mas não o cancelamento: o EXPLAIN
+// roda depois da resposta, com o próprio timeout
+func (s *BaseEntityService) explainQuery(ctx context.Context, config *SlowQueryConfig, query string, args []any) string {
+	conn := s.provider.GetConnection()
+	if conn == nil {
+		return ""
+	}
+
+	timeout := config.ExplainTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	rows, err := conn.QueryContext(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return fmt.Sprintf("erro ao capturar EXPLAIN: %v", err)
+	}
+	defer rows.Close()
+
+	plan, err := formatExplainRows(rows)
+	if err != nil {
+		return fmt.Sprintf("erro ao ler EXPLAIN: %v", err)
+	}
+	return plan
+}
+
+// formatExplainRows converte o resultado do EXPLAIN em texto: uma linha por registro,
+// com os nomes das colunas quando houver mais de uma (MySQL)
+func formatExplainRows(rows *sql.Rows) (string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	if len(columns) > 1 {
+		lines = append(lines, strings.Join(columns, " | "))
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return "", err
+		}
+		fields := make([]string, len(values))
+		for i, value := range values {
+			fields[i] = value.String
+		}
+		lines = append(lines, strings.Join(fields, " | "))
+	}
+	return strings.Join(lines, "\n"), rows.Err()
+}
+
+// requestURLFromContext retorna a URL OData da requisição associada ao contexto
+func requestURLFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if fc, ok := ctx.Value(FiberContextKey).(fiber.Ctx); ok && fc != nil {
+		return strings.Clone(fc.OriginalURL())
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package odata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSlowQueryTestServer(t *testing.T, config *SlowQueryConfig) *Server {
+	t.Helper()
+
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+	server.SetSlowQueryConfig(config)
+	return server
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	t.Run("Queries abaixo do threshold não são registradas", func(t *testing.T) {
+		store := NewMemorySlowQueryStore(10)
+		server := newSlowQueryTestServer(t, &SlowQueryConfig{Enabled: true, Threshold: time.Hour, Store: store})
+
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Products", nil))
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Empty(t, store.Entries())
+	})
+
+	t.Run("Registra URL, SQL, argumentos e linhas", func(t *testing.T) {
+		store := NewMemorySlowQueryStore(10)
+		server := newSlowQueryTestServer(t, &SlowQueryConfig{Enabled: true, Threshold: time.Nanosecond, Store: store})
+
+		resp, err := server.router.Test(httptest.NewRequest(http.MethodGet, "/odata/Products?$filter=price%20gt%2020", nil))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		entries := store.Entries()
+		require.Len(t, entries, 1)
+		entry := entries[0]
+		assert.Equal(t, "Products", entry.EntityName)
+		assert.Equal(t, QueryOperationSelect, entry.Operation)
+		assert.Equal(t, "/odata/Products?$filter=price%20gt%2020", entry.URL)
+		assert.Contains(t, entry.SQL, "FROM products")
+		assert.NotEmpty(t, entry.Args)
+		assert.Equal(t, int64(2), entry.RowCount)
+		assert.Empty(t, entry.Plan)
+	})
+
+	t.Run("Captura o plano de execução em background", func(t *testing.T) {
+		store := NewMemorySlowQueryStore(10)
+		server := newSlowQueryTestServer(t, &SlowQueryConfig{
+			Enabled:        true,
+			Threshold:      time.Nanosecond,
+			CaptureExplain: true,
+			Store:          store,
+		})
+
+		// O provider de teste usa o dialeto MySQL sobre SQLite, que também aceita EXPLAIN
+		_, err := server.entities["Products"].Query(context.Background(), QueryOptions{})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool { return len(store.Entries()) == 1 }, 2*time.Second, 10*time.Millisecond)
+		assert.NotEmpty(t, store.Entries()[0].Plan)
+		assert.Empty(t, store.Entries()[0].URL)
+	})
+
+	t.Run("Desabilitado por padrão", func(t *testing.T) {
+		config := DefaultSlowQueryConfig()
+		assert.False(t, config.Enabled)
+		assert.Equal(t, time.Second, config.Threshold)
+	})
+}
+
+func TestMemorySlowQueryStore(t *testing.T) {
+	store := NewMemorySlowQueryStore(2)
+	for _, sql := range []string{"q1", "q2", "q3"} {
+		require.NoError(t, store.Save(SlowQueryEntry{SQL: sql}))
+	}
+
+	entries := store.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "q2", entries[0].SQL)
+	assert.Equal(t, "q3", entries[1].SQL)
+}
+
+func TestFormatExplainRows(t *testing.T) {
+	server := newBatchGetTestServer(t)
+
+	rows, err := server.provider.GetConnection().Query("EXPLAIN QUERY PLAN SELECT * FROM products WHERE price > ?", 20)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	plan, err := formatExplainRows(rows)
+	require.NoError(t, err)
+	assert.Contains(t, plan, "detail")
+	assert.Contains(t, plan, "products")
+}
@@ -0,0 +1,226 @@
+package odata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// =======================================================================================
+// ARMAZENAMENTO DE ARQUIVOS (SISTEMA DE ARQUIVOS, S3, GCS E AZURE BLOB)
+// =======================================================================================
+
+// Storage guarda arquivos binários (exportações, relatórios gerados, mídias da aplicação).
+// As chaves usam "/" como separador (ex: "Orders/3f9c....csv")
+type Storage interface {
+	Save(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// StorageURLSigner é implementado pelos armazenamentos que geram a própria URL de download
+// assinada (S3, GCS e Azure Blob). Nos demais, o arquivo é servido pelo servidor
+type StorageURLSigner interface {
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// Drivers aceitos em STORAGE_DRIVER
+const (
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+	StorageDriverGCS   = "gcs"
+	StorageDriverAzure = "azure"
+)
+
+// storagePresignTTL é a validade das URLs assinadas usadas nas próprias requisições
+const storagePresignTTL = 15 * time.Minute
+
+// FileSystemStorage guarda os arquivos em um diretório local
+type FileSystemStorage struct {
+	Dir string
+}
+
+// NewFileSystemStorage cria o armazenamento no diretório informado
+func NewFileSystemStorage(dir string) *FileSystemStorage {
+	return &FileSystemStorage{Dir: dir}
+}
+
+// path resolve a chave dentro do diretório, rejeitando chaves que escapem dele
+func (s *FileSystemStorage) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if filepath.IsAbs(clean) || clean == "." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) || clean == ".." {
+		return "", fmt.Errorf("invalid storage key '%s'", key)
+	}
+	return filepath.Join(s.Dir, clean), nil
+}
+
+// Save implementa Storage (gravação em arquivo temporário + rename)
+func (s *FileSystemStorage) Save(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".storage-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// Open implementa Storage
+func (s *FileSystemStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Delete implementa Storage (chaves inexistentes não são erro)
+func (s *FileSystemStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// CreateStorageFromConfig cria o armazenamento configurado em STORAGE_DRIVER. Sem driver,
+// retorna nil (as exportações usam o diretório temporário do sistema)
+func (c *EnvConfig) CreateStorageFromConfig() (Storage, error) {
+	switch strings.ToLower(c.StorageDriver) {
+	case "":
+		return nil, nil
+	case StorageDriverLocal:
+		return NewFileSystemStorage(c.StorageLocalDir), nil
+	case StorageDriverS3:
+		storage := NewS3Storage(c.StorageBucket)
+		storage.AccessKeyID = c.StorageAccessKey
+		storage.SecretAccessKey = c.StorageSecretKey
+		storage.Region = c.StorageRegion
+		storage.Prefix = c.StoragePrefix
+		storage.Endpoint = c.StorageEndpoint
+		return storage, nil
+	case StorageDriverGCS:
+		storage := NewGCSStorage(c.StorageBucket)
+		storage.CredentialsFile = c.StorageCredentialsFile
+		storage.Prefix = c.StoragePrefix
+		storage.Endpoint = c.StorageEndpoint
+		return storage, nil
+	case StorageDriverAzure:
+		storage := NewAzureBlobStorage(c.StorageBucket)
+		storage.AccountName = c.StorageAccountName
+		storage.AccountKey = c.StorageAccountKey
+		storage.Prefix = c.StoragePrefix
+		storage.Endpoint = c.StorageEndpoint
+		return storage, nil
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER inválido: %s (use local, s3, gcs ou azure)", c.StorageDriver)
+	}
+}
+
+// loadStorage cria o armazenamento da configuração por ambiente, registrando no log
+// configurações inválidas
+func loadStorage(c *EnvConfig) Storage {
+	storage, err := c.CreateStorageFromConfig()
+	if err != nil {
+		log.Printf("⚠️ Armazenamento indisponível: %v", err)
+		return nil
+	}
+	return storage
+}
+
+// storageRequest executa a requisição a uma URL assinada do armazenamento, convertendo
+// respostas de erro em error
+func storageRequest(ctx context.Context, client *http.Client, service, method, target, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		return nil, fmt.Errorf("%s %s %s returned status %d: %s", service, method, key, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+// contentTypeHeader monta o cabeçalho Content-Type do upload (vazio quando não informado)
+func contentTypeHeader(contentType string) http.Header {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return header
+}
+
+// canonicalQueryV4 monta a query string canônica das assinaturas V4 (S3 e GCS): parâmetros
+// ordenados por nome, codificados conforme awsURIEncode
+func canonicalQueryV4(query map[string]string) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = awsURIEncode(name, true) + "=" + awsURIEncode(query[name], true)
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsURIEncode codifica o texto conforme a SigV4: apenas A-Z, a-z, 0-9, '-', '.', '_' e '~'
+// ficam sem codificação ('/' também, exceto quando encodeSlash)
+func awsURIEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
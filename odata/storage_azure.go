@@ -0,0 +1,125 @@
+package odata
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// azureSASVersion é a versão do serviço usada nas assinaturas SAS
+const azureSASVersion = "2022-11-02"
+
+// AzureBlobStorage guarda os arquivos em um container do Azure Blob Storage, com requisições
+// autorizadas por SAS de serviço assinadas com a chave da conta. O download usa uma URL SAS
+// somente leitura
+type AzureBlobStorage struct {
+	AccountName string       // Conta de armazenamento (padrão: AZURE_STORAGE_ACCOUNT)
+	AccountKey  string       // Chave da conta em base64 (padrão: AZURE_STORAGE_KEY)
+	Container   string       // Container dos arquivos
+	Prefix      string       // Prefixo dos nomes dos blobs (ex: "exports/")
+	Endpoint    string       // Endpoint da conta (ex: http://127.0.0.1:10000/devstoreaccount1); padrão: https://{conta}.blob.core.windows.net
+	HTTPClient  *http.Client // Cliente HTTP (padrão: sem timeout, os uploads podem ser grandes)
+}
+
+// NewAzureBlobStorage cria o armazenamento no container informado
+func NewAzureBlobStorage(container string) *AzureBlobStorage {
+	return &AzureBlobStorage{Container: container}
+}
+
+// Save implementa Storage com um Put Blob (block blob de até 5000 MiB)
+func (s *AzureBlobStorage) Save(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	header := contentTypeHeader(contentType)
+	header.Set("x-ms-blob-type", "BlockBlob")
+	resp, err := s.do(ctx, http.MethodPut, "cw", key, content, size, header)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Open implementa Storage
+func (s *AzureBlobStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, "r", key, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete implementa Storage
+func (s *AzureBlobStorage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, "d", key, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SignedURL implementa StorageURLSigner com uma SAS somente leitura
+func (s *AzureBlobStorage) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return s.presign("r", key, expires, time.Now().UTC())
+}
+
+// do executa a requisição ao blob com uma URL SAS com as permissões informadas
+func (s *AzureBlobStorage) do(ctx context.Context, method, permissions, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	signed, err := s.presign(permissions, key, storagePresignTTL, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return storageRequest(ctx, s.HTTPClient, "azure blob", method, signed, key, body, size, header)
+}
+
+// presign gera a URL do blob com uma SAS de serviço (sr=b). O início é recuado alguns
+// minutos para tolerar diferenças de relógio
+func (s *AzureBlobStorage) presign(permissions, key string, expires time.Duration, now time.Time) (string, error) {
+	account := firstNonEmpty(s.AccountName, os.Getenv("AZURE_STORAGE_ACCOUNT"))
+	encodedKey := firstNonEmpty(s.AccountKey, os.Getenv("AZURE_STORAGE_KEY"))
+	if account == "" || encodedKey == "" {
+		return "", fmt.Errorf("azure storage account name and key are required")
+	}
+	accountKey, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid azure storage account key: %w", err)
+	}
+	if s.Container == "" {
+		return "", fmt.Errorf("azure blob container is required")
+	}
+
+	blob := s.Prefix + key
+	start := now.Add(-5 * time.Minute).Format(time.RFC3339)
+	expiry := now.Add(expires).Format(time.RFC3339)
+	stringToSign := strings.Join([]string{
+		permissions,
+		start,
+		expiry,
+		"/blob/" + account + "/" + s.Container + "/" + blob,
+		"", // identificador da política
+		"", // IP
+		"", // protocolo
+		azureSASVersion,
+		"b",
+		"",                 // snapshot
+		"",                 // escopo de criptografia
+		"", "", "", "", "", // rscc, rscd, rsce, rscl, rsct
+	}, "\n")
+	signature := base64.StdEncoding.EncodeToString(hmacSHA256(accountKey, stringToSign))
+
+	query := url.Values{}
+	query.Set("sv", azureSASVersion)
+	query.Set("sr", "b")
+	query.Set("sp", permissions)
+	query.Set("st", start)
+	query.Set("se", expiry)
+	query.Set("sig", signature)
+
+	endpoint := strings.TrimRight(firstNonEmpty(s.Endpoint, "https://"+account+".blob.core.windows.net"), "/")
+	return endpoint + "/" + awsURIEncode(s.Container, true) + "/" + awsURIEncode(blob, false) + "?" + query.Encode(), nil
+}
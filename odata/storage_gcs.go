@@ -0,0 +1,158 @@
+package odata
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// GCSStorage guarda os arquivos em um bucket do Google Cloud Storage pela API XML, com
+// URLs assinadas V4 (GOOG4-RSA-SHA256) pela chave da conta de serviço. O download usa uma
+// URL assinada do próprio GCS
+type GCSStorage struct {
+	Bucket          string
+	Prefix          string       // Prefixo das chaves no bucket (ex: "exports/")
+	CredentialsFile string       // JSON da conta de serviço (padrão: GOOGLE_APPLICATION_CREDENTIALS)
+	ClientEmail     string       // E-mail da conta de serviço (alternativa ao CredentialsFile)
+	PrivateKey      string       // Chave privada PEM da conta de serviço (alternativa ao CredentialsFile)
+	Endpoint        string       // Endpoint da API XML (padrão: https://storage.googleapis.com)
+	HTTPClient      *http.Client // Cliente HTTP (padrão: sem timeout, os uploads podem ser grandes)
+}
+
+// NewGCSStorage cria o armazenamento no bucket informado
+func NewGCSStorage(bucket string) *GCSStorage {
+	return &GCSStorage{Bucket: bucket}
+}
+
+// Save implementa Storage com um PUT do objeto
+func (s *GCSStorage) Save(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, content, size, contentTypeHeader(contentType))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Open implementa Storage
+func (s *GCSStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete implementa Storage
+func (s *GCSStorage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SignedURL implementa StorageURLSigner (validade máxima de 7 dias)
+func (s *GCSStorage) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, expires, time.Now().UTC())
+}
+
+// do executa a requisição ao objeto com uma URL assinada
+func (s *GCSStorage) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	signed, err := s.presign(method, key, storagePresignTTL, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return storageRequest(ctx, s.HTTPClient, "gcs", method, signed, key, body, size, header)
+}
+
+// credentials retorna o e-mail e a chave privada da conta de serviço
+func (s *GCSStorage) credentials() (string, *rsa.PrivateKey, error) {
+	email, privateKey := s.ClientEmail, s.PrivateKey
+	if email == "" || privateKey == "" {
+		path := firstNonEmpty(s.CredentialsFile, os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"))
+		if path == "" {
+			return "", nil, fmt.Errorf("gcs service account credentials are required")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read gcs credentials: %w", err)
+		}
+		var account struct {
+			ClientEmail string `json:"client_email"`
+			PrivateKey  string `json:"private_key"`
+		}
+		if err := json.Unmarshal(data, &account); err != nil {
+			return "", nil, fmt.Errorf("invalid gcs credentials file: %w", err)
+		}
+		email, privateKey = account.ClientEmail, account.PrivateKey
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKey))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid gcs private key: %w", err)
+	}
+	return email, key, nil
+}
+
+// presign gera a URL assinada V4 (payload não assinado, apenas o cabeçalho host)
+func (s *GCSStorage) presign(method, key string, expires time.Duration, now time.Time) (string, error) {
+	email, privateKey, err := s.credentials()
+	if err != nil {
+		return "", err
+	}
+	if s.Bucket == "" {
+		return "", fmt.Errorf("gcs bucket is required")
+	}
+
+	endpoint := strings.TrimRight(firstNonEmpty(s.Endpoint, "https://storage.googleapis.com"), "/")
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid gcs endpoint: %w", err)
+	}
+	path := "/" + awsURIEncode(s.Bucket, true) + "/" + awsURIEncode(s.Prefix+key, false)
+
+	date := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/auto/storage/goog4_request"
+	canonicalQuery := canonicalQueryV4(map[string]string{
+		"X-Goog-Algorithm":     "GOOG4-RSA-SHA256",
+		"X-Goog-Credential":    email + "/" + scope,
+		"X-Goog-Date":          date,
+		"X-Goog-Expires":       strconv.Itoa(int(expires.Seconds())),
+		"X-Goog-SignedHeaders": "host",
+	})
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		base.EscapedPath() + path,
+		canonicalQuery,
+		"host:" + base.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "GOOG4-RSA-SHA256\n" + date + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	digest := sha256.Sum256([]byte(stringToSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign gcs url: %w", err)
+	}
+
+	return endpoint + path + "?" + canonicalQuery + "&X-Goog-Signature=" + hex.EncodeToString(signature), nil
+}
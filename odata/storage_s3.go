@@ -0,0 +1,128 @@
+package odata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Storage guarda os arquivos em um bucket S3 (ou compatível, como MinIO), com requisições
+// assinadas por SigV4. O download usa uma URL pré-assinada do próprio S3
+type S3Storage struct {
+	AWSCredentials
+	Bucket     string
+	Prefix     string       // Prefixo das chaves no bucket (ex: "exports/")
+	Endpoint   string       // Endpoint path-style (ex: http://minio:9000); padrão: https://{bucket}.s3.{região}.amazonaws.com
+	HTTPClient *http.Client // Cliente HTTP (padrão: sem timeout, os uploads podem ser grandes)
+}
+
+// NewS3Storage cria o armazenamento no bucket informado
+func NewS3Storage(bucket string) *S3Storage {
+	return &S3Storage{Bucket: bucket}
+}
+
+// Save implementa Storage com um PUT do objeto (até 5 GB)
+func (s *S3Storage) Save(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, content, size, contentTypeHeader(contentType))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Open implementa Storage
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete implementa Storage
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SignedURL implementa StorageURLSigner
+func (s *S3Storage) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, expires, time.Now().UTC())
+}
+
+// do executa a requisição ao objeto com uma URL pré-assinada
+func (s *S3Storage) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	signed, err := s.presign(method, key, storagePresignTTL, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return storageRequest(ctx, s.HTTPClient, "s3", method, signed, key, body, size, header)
+}
+
+// presign gera a URL pré-assinada (SigV4 na query string, payload não assinado)
+func (s *S3Storage) presign(method, key string, expires time.Duration, now time.Time) (string, error) {
+	credentials, err := s.AWSCredentials.resolve()
+	if err != nil {
+		return "", err
+	}
+	if s.Bucket == "" {
+		return "", fmt.Errorf("s3 bucket is required")
+	}
+
+	objectKey := s.Prefix + key
+	endpoint := fmt.Sprintf("https://%s.s3.%s.amazonaws.com", s.Bucket, credentials.Region)
+	path := "/" + awsURIEncode(objectKey, false)
+	if s.Endpoint != "" {
+		endpoint = strings.TrimRight(s.Endpoint, "/")
+		path = "/" + awsURIEncode(s.Bucket, true) + path
+	}
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+
+	amzDate := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/" + credentials.Region + "/s3/aws4_request"
+	query := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    credentials.AccessKeyID + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(expires.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if credentials.SessionToken != "" {
+		query["X-Amz-Security-Token"] = credentials.SessionToken
+	}
+	canonicalQuery := canonicalQueryV4(query)
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		base.EscapedPath() + path,
+		canonicalQuery,
+		"host:" + base.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), now.Format("20060102"))
+	signingKey = hmacSHA256(signingKey, credentials.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	return endpoint + path + "?" + canonicalQuery + "&X-Amz-Signature=" + signature, nil
+}
@@ -0,0 +1,209 @@
+package odata
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeObjectServer simula um serviço de objetos (PUT/GET/DELETE por caminho), recusando
+// as requisições que authorize rejeitar
+func newFakeObjectServer(t *testing.T, authorize func(r *http.Request) bool) (*httptest.Server, map[string]string) {
+	var mu sync.Mutex
+	objects := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorize(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = string(data)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			io.WriteString(w, data)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, objects
+}
+
+// assertStorageRoundTrip grava, lê e remove um objeto pelo armazenamento
+func assertStorageRoundTrip(t *testing.T, storage Storage, objects map[string]string, path string) {
+	ctx := context.Background()
+	require.NoError(t, storage.Save(ctx, "default/Orders/1.csv", strings.NewReader("id\n1\n"), 5, "text/csv"))
+	assert.Contains(t, objects, path)
+
+	reader, err := storage.Open(ctx, "default/Orders/1.csv")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	assert.Equal(t, "id\n1\n", string(data))
+
+	require.NoError(t, storage.Delete(ctx, "default/Orders/1.csv"))
+	_, err = storage.Open(ctx, "default/Orders/1.csv")
+	assert.Error(t, err)
+}
+
+func TestFileSystemStorage(t *testing.T) {
+	storage := NewFileSystemStorage(t.TempDir())
+	ctx := context.Background()
+
+	require.NoError(t, storage.Save(ctx, "media/a.txt", strings.NewReader("abc"), 3, "text/plain"))
+	reader, err := storage.Open(ctx, "media/a.txt")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	assert.Equal(t, "abc", string(data))
+
+	assert.Error(t, storage.Save(ctx, "../escape.txt", strings.NewReader("x"), 1, ""))
+	require.NoError(t, storage.Delete(ctx, "media/a.txt"))
+	assert.NoError(t, storage.Delete(ctx, "media/a.txt"), "chaves inexistentes não são erro")
+}
+
+func TestS3Storage(t *testing.T) {
+	s3, objects := newFakeObjectServer(t, func(r *http.Request) bool {
+		query := r.URL.Query()
+		return query.Get("X-Amz-Algorithm") == "AWS4-HMAC-SHA256" && query.Get("X-Amz-Signature") != "" &&
+			strings.HasPrefix(query.Get("X-Amz-Credential"), "AKID/")
+	})
+
+	storage := NewS3Storage("exports")
+	storage.AWSCredentials = AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", Region: "us-east-1"}
+	storage.Endpoint = s3.URL
+	storage.Prefix = "odata/"
+	assertStorageRoundTrip(t, storage, objects, "/exports/odata/default/Orders/1.csv")
+
+	link, err := storage.SignedURL(context.Background(), "default/Orders/1.csv", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(link, s3.URL+"/exports/odata/default/Orders/1.csv?"))
+	assert.Contains(t, link, "X-Amz-Expires=60")
+}
+
+func TestGCSStorage(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	encoded, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "exports@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: encoded})),
+	})
+	require.NoError(t, err)
+	credentialsFile := filepath.Join(t.TempDir(), "service-account.json")
+	require.NoError(t, os.WriteFile(credentialsFile, credentials, 0o600))
+
+	// O servidor falso valida a assinatura RSA sobre a requisição canônica
+	gcs, objects := newFakeObjectServer(t, func(r *http.Request) bool {
+		query := r.URL.Query()
+		signature, err := hex.DecodeString(query.Get("X-Goog-Signature"))
+		if err != nil || query.Get("X-Goog-Algorithm") != "GOOG4-RSA-SHA256" {
+			return false
+		}
+		query.Del("X-Goog-Signature")
+		canonicalQuery := strings.ReplaceAll(query.Encode(), "+", "%20")
+		canonicalRequest := r.Method + "\n" + r.URL.EscapedPath() + "\n" + canonicalQuery + "\nhost:" + r.Host + "\n\nhost\nUNSIGNED-PAYLOAD"
+		requestHash := sha256.Sum256([]byte(canonicalRequest))
+		credential := strings.SplitN(query.Get("X-Goog-Credential"), "/", 2)
+		stringToSign := "GOOG4-RSA-SHA256\n" + query.Get("X-Goog-Date") + "\n" + credential[1] + "\n" + hex.EncodeToString(requestHash[:])
+		digest := sha256.Sum256([]byte(stringToSign))
+		return rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, digest[:], signature) == nil
+	})
+
+	storage := NewGCSStorage("exports")
+	storage.CredentialsFile = credentialsFile
+	storage.Endpoint = gcs.URL
+	assertStorageRoundTrip(t, storage, objects, "/exports/default/Orders/1.csv")
+
+	link, err := storage.SignedURL(context.Background(), "default/Orders/1.csv", time.Hour)
+	require.NoError(t, err)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "3600", parsed.Query().Get("X-Goog-Expires"))
+	assert.True(t, strings.HasPrefix(parsed.Query().Get("X-Goog-Credential"), "exports@project.iam.gserviceaccount.com/"))
+}
+
+func TestAzureBlobStorage(t *testing.T) {
+	accountKey := base64.StdEncoding.EncodeToString([]byte("azure-account-key"))
+	permissions := map[string]string{http.MethodPut: "cw", http.MethodGet: "r", http.MethodDelete: "d"}
+
+	// O servidor falso valida a SAS de serviço e o tipo do blob no upload
+	azure, objects := newFakeObjectServer(t, func(r *http.Request) bool {
+		query := r.URL.Query()
+		if query.Get("sp") != permissions[r.Method] || query.Get("sr") != "b" {
+			return false
+		}
+		if r.Method == http.MethodPut && r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+			return false
+		}
+		resource := "/blob/devstoreaccount1" + strings.TrimPrefix(r.URL.Path, "/devstoreaccount1")
+		stringToSign := strings.Join([]string{query.Get("sp"), query.Get("st"), query.Get("se"), resource,
+			"", "", "", query.Get("sv"), "b", "", "", "", "", "", "", ""}, "\n")
+		expected := base64.StdEncoding.EncodeToString(hmacSHA256([]byte("azure-account-key"), stringToSign))
+		return query.Get("sig") == expected
+	})
+
+	storage := NewAzureBlobStorage("exports")
+	storage.AccountName = "devstoreaccount1"
+	storage.AccountKey = accountKey
+	storage.Endpoint = azure.URL + "/devstoreaccount1"
+	storage.Prefix = "odata/"
+	assertStorageRoundTrip(t, storage, objects, "/devstoreaccount1/exports/odata/default/Orders/1.csv")
+
+	link, err := storage.SignedURL(context.Background(), "default/Orders/1.csv", time.Minute)
+	require.NoError(t, err)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "r", parsed.Query().Get("sp"))
+}
+
+func TestEnvConfig_CreateStorageFromConfig(t *testing.T) {
+	storage, err := (&EnvConfig{}).CreateStorageFromConfig()
+	require.NoError(t, err)
+	assert.Nil(t, storage)
+
+	storage, err = (&EnvConfig{StorageDriver: "S3", StorageBucket: "files", StoragePrefix: "app/", StorageRegion: "sa-east-1"}).CreateStorageFromConfig()
+	require.NoError(t, err)
+	require.IsType(t, &S3Storage{}, storage)
+	assert.Equal(t, "files", storage.(*S3Storage).Bucket)
+	assert.Equal(t, "sa-east-1", storage.(*S3Storage).Region)
+
+	storage, err = (&EnvConfig{StorageDriver: "azure", StorageBucket: "files", StorageAccountName: "acct"}).CreateStorageFromConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "acct", storage.(*AzureBlobStorage).AccountName)
+
+	_, err = (&EnvConfig{StorageDriver: "ftp"}).CreateStorageFromConfig()
+	assert.Error(t, err)
+}
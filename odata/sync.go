@@ -0,0 +1,532 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// =======================================================================================
+// SINCRONIZAÇÃO EM LOTE ($sync): UPSERT + EXCLUSÃO DAS LINHAS AUSENTES
+// =======================================================================================
+
+// DefaultSyncMaxEntities é a quantidade máxima padrão de entidades por requisição $sync
+const DefaultSyncMaxEntities = 1000
+
+// syncSegment é o segmento da sincronização nas rotas (ex: POST /odata/Products/$sync)
+const syncSegment = "/$sync"
+
+// queryOperationUpsert identifica a gravação do $sync, que pode inserir ou atualizar a linha
+const queryOperationUpsert = "UPSERT"
+
+// Estratégias de correspondência entre as entidades do $sync e as linhas existentes
+const (
+	SyncKeyStrategyKey       = "key"       // Chave primária da entidade (padrão)
+	SyncKeyStrategyAlternate = "alternate" // Chave alternativa (SyncConfig.AlternateKey)
+)
+
+// SyncConfig configura a sincronização em lote de uma entidade
+type SyncConfig struct {
+	AlternateKey       []string // Propriedades da chave alternativa (ex: UUID gerado no cliente), com índice único no banco
+	AllowDeleteMissing bool     // Permite excluir as linhas do escopo ausentes do payload (deleteMissing)
+	MaxEntities        int      // Entidades por requisição (padrão: DefaultSyncMaxEntities)
+}
+
+// SyncResult é a resposta do $sync
+type SyncResult struct {
+	Upserted    int                      `json:"upserted"`
+	Deleted     int                      `json:"deleted"`
+	DeletedKeys []map[string]interface{} `json:"deletedKeys,omitempty"`
+}
+
+// syncRequest é o corpo do $sync
+type syncRequest struct {
+	KeyStrategy   string                   `json:"keyStrategy"`
+	DeleteMissing bool                     `json:"deleteMissing"`
+	Value         []map[string]interface{} `json:"value"`
+}
+
+// WithSync expõe POST /Entidade/$sync, que grava um lote de entidades em uma transação com
+// upsert nativo do banco (INSERT ... ON CONFLICT, ON DUPLICATE KEY UPDATE ou MERGE) e,
+// opcionalmente, exclui as linhas do escopo ausentes do lote. Pensado para clientes
+// offline-first que enviam suas alterações ao reconectar
+func WithSync(config SyncConfig) EntityOption {
+	return func(entityConfig *EntityConfig) {
+		entityConfig.Sync = &config
+	}
+}
+
+// registerSync valida e guarda a configuração do $sync da entidade
+func (s *Server) registerSync(entityName string, metadata EntityMetadata, config SyncConfig) error {
+	if config.MaxEntities < 0 {
+		return fmt.Errorf("MaxEntities do $sync não pode ser negativo")
+	}
+	if config.MaxEntities == 0 {
+		config.MaxEntities = DefaultSyncMaxEntities
+	}
+	for _, name := range config.AlternateKey {
+		if findPropertyByName(metadata, name) == nil {
+			return fmt.Errorf("propriedade '%s' da chave alternativa do $sync não encontrada", name)
+		}
+	}
+	// O histórico é indexado pela chave primária, ausente nos lotes por chave alternativa
+	if len(config.AlternateKey) > 0 && metadata.History {
+		return fmt.Errorf("a chave alternativa do $sync não suporta WithHistory")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.syncs == nil {
+		s.syncs = make(map[string]SyncConfig)
+	}
+	s.syncs[entityName] = config
+	return nil
+}
+
+// getSync retorna a configuração do $sync da entidade, se houver
+func (s *Server) getSync(entityName string) (SyncConfig, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	config, ok := s.syncs[entityName]
+	return config, ok
+}
+
+// setupSyncRoute registra a rota do $sync com os middlewares da entidade
+func (s *Server) setupSyncRoute(router fiber.Router, entityName, prefix, setName string, middlewares []any) {
+	config, ok := s.getSync(entityName)
+	if !ok {
+		return
+	}
+	chain := append(append([]any{}, middlewares...), s.handleSync(entityName, config))
+	router.Add([]string{fiber.MethodPost}, prefix+"/"+setName+syncSegment, chain[0], chain[1:]...)
+}
+
+// handleSync grava o lote do $sync. Com deleteMissing, o $filter da URL (combinado com o
+// filtro padrão da entidade) define o escopo das linhas excluídas quando ausentes do lote
+func (s *Server) handleSync(entityName string, config SyncConfig) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		service, ok := s.GetEntityService(entityName).(*BaseEntityService)
+		if !ok {
+			s.writeError(c, fiber.StatusNotImplemented, "NotImplemented", "Entity service does not support $sync")
+			return nil
+		}
+		metadata := service.GetMetadata()
+
+		if err := s.authorizeRequest(c, entityName, nil); err != nil {
+			s.writeEntityError(c, fiber.StatusForbidden, "Forbidden", err)
+			return nil
+		}
+
+		var request syncRequest
+		if err := s.decodeJSONBody(c.Body(), &request); err != nil {
+			s.writeError(c, fiber.StatusBadRequest, "InvalidRequest", "Invalid JSON")
+			return nil
+		}
+		for _, entity := range request.Value {
+			s.convertEntityNumbers(entity, metadata)
+		}
+		if len(request.Value) > config.MaxEntities {
+			s.writeError(c, fiber.StatusRequestEntityTooLarge, "TooManyEntities",
+				fmt.Sprintf("$sync accepts at most %d entities", config.MaxEntities))
+			return nil
+		}
+		match, err := syncMatchProperties(metadata, config, request.KeyStrategy)
+		if err != nil {
+			s.writeError(c, fiber.StatusBadRequest, "InvalidKeyStrategy", err.Error())
+			return nil
+		}
+
+		ctx, cancel := s.requestContext(c, entityName)
+		defer cancel()
+
+		var scope *QueryOptions
+		if request.DeleteMissing {
+			if !config.AllowDeleteMissing {
+				s.writeError(c, fiber.StatusBadRequest, "InvalidRequest",
+					fmt.Sprintf("deleteMissing is not allowed for entity '%s'", entityName))
+				return nil
+			}
+			if err := s.authorizeEntity(ctx, &AuthorizationRequest{
+				EntityName: entityName,
+				Operation:  fiber.MethodDelete,
+				User:       GetCurrentUser(c),
+			}); err != nil {
+				s.writeEntityError(c, fiber.StatusForbidden, "Forbidden", err)
+				return nil
+			}
+
+			options, err := s.parseQueryOptions(c)
+			if err == nil {
+				scope = &QueryOptions{Filter: options.Filter}
+				err = s.applyDefaultFilters(scope, metadata, "")
+			}
+			if err != nil {
+				s.writeError(c, fiber.StatusBadRequest, "InvalidQuery", err.Error())
+				return nil
+			}
+		}
+
+		result, err := service.sync(ctx, match, request.Value, scope)
+		if err != nil {
+			s.writeEntityError(c, fiber.StatusInternalServerError, "SyncError", s.queryContextError(ctx, entityName, err))
+			return nil
+		}
+		return c.JSON(result)
+	}
+}
+
+// syncMatchProperties retorna as propriedades que identificam as linhas na estratégia
+func syncMatchProperties(metadata EntityMetadata, config SyncConfig, strategy string) ([]PropertyMetadata, error) {
+	var names []string
+	switch strategy {
+	case "", SyncKeyStrategyKey:
+		for _, prop := range metadata.Properties {
+			if prop.IsKey {
+				names = append(names, prop.Name)
+			}
+		}
+	case SyncKeyStrategyAlternate:
+		if len(config.AlternateKey) == 0 {
+			return nil, fmt.Errorf("entity '%s' has no alternate key for $sync", metadata.Name)
+		}
+		names = config.AlternateKey
+	default:
+		return nil, fmt.Errorf("invalid keyStrategy '%s': use '%s' or '%s'", strategy, SyncKeyStrategyKey, SyncKeyStrategyAlternate)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("entity '%s' has no key for $sync", metadata.Name)
+	}
+
+	match := make([]PropertyMetadata, 0, len(names))
+	for _, name := range names {
+		match = append(match, *findPropertyByName(metadata, name))
+	}
+	return match, nil
+}
+
+// sync grava o lote em uma única transação: upsert de cada entidade e, com escopo, exclusão
+// (lógica, se configurada) das linhas do escopo ausentes do lote
+func (s *BaseEntityService) sync(ctx context.Context, match []PropertyMetadata, entities []map[string]interface{}, scope *QueryOptions) (*SyncResult, error) {
+	received := make(map[string]bool, len(entities))
+	for i, entity := range entities {
+		values := make([]interface{}, len(match))
+		for j, prop := range match {
+			if values[j] = entity[prop.Name]; values[j] == nil {
+				return nil, NewODataError("InvalidSyncEntity",
+					fmt.Sprintf("entity %d is missing key property '%s'", i, prop.Name)).WithStatus(http.StatusBadRequest)
+			}
+		}
+		key := syncMatchKey(values)
+		if received[key] {
+			return nil, NewODataError("InvalidSyncEntity",
+				fmt.Sprintf("entity %d is duplicated in the payload", i)).WithStatus(http.StatusBadRequest)
+		}
+		received[key] = true
+	}
+
+	// As linhas ausentes são lidas antes da transação, com os filtros de consulta da entidade
+	result := &SyncResult{}
+	if scope != nil {
+		missing, err := s.syncMissingKeys(ctx, match, *scope, received)
+		if err != nil {
+			return nil, err
+		}
+		result.DeletedKeys = missing
+	}
+
+	tx, err := beginSessionTx(ctx, s.provider, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if tx != nil {
+			tx.Rollback()
+		}
+	}()
+
+	for i, entity := range entities {
+		if err := s.upsertInTx(ctx, tx.Tx, entity, match); err != nil {
+			return nil, fmt.Errorf("failed to sync entity %d: %w", i, err)
+		}
+		result.Upserted++
+	}
+	for _, keys := range result.DeletedKeys {
+		if err := executeDeleteInTx(ctx, tx.Tx, s, keys); err != nil {
+			return nil, fmt.Errorf("failed to delete missing entity: %w", err)
+		}
+		result.Deleted++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	tx = nil
+	return result, nil
+}
+
+// syncMissingKeys retorna as chaves primárias das linhas do escopo ausentes do lote
+func (s *BaseEntityService) syncMissingKeys(ctx context.Context, match []PropertyMetadata, scope QueryOptions, received map[string]bool) ([]map[string]interface{}, error) {
+	scope.Select = &GoDataSelectQuery{}
+	for _, prop := range s.metadata.Properties {
+		if prop.IsKey || containsProperty(match, prop.Name) {
+			scope.Select.SelectItems = append(scope.Select.SelectItems, &SelectItem{Segments: []*Token{{Value: prop.Name}}})
+		}
+	}
+
+	response, err := s.Query(ctx, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync scope: %w", err)
+	}
+	rows, _ := response.Value.([]interface{})
+
+	var missing []map[string]interface{}
+	for _, row := range rows {
+		entity, ok := row.(*OrderedEntity)
+		if !ok {
+			continue
+		}
+		values := make([]interface{}, len(match))
+		for i, prop := range match {
+			values[i], _ = entity.Get(prop.Name)
+		}
+		if received[syncMatchKey(values)] {
+			continue
+		}
+		keys := make(map[string]interface{}, len(s.metadata.Keys))
+		for _, prop := range s.metadata.Properties {
+			if prop.IsKey {
+				keys[prop.Name], _ = entity.Get(prop.Name)
+			}
+		}
+		missing = append(missing, keys)
+	}
+	return missing, nil
+}
+
+// upsertInTx insere ou atualiza a entidade identificada pelas propriedades de correspondência.
+// As chaves, as propriedades de correspondência e os valores automáticos só de INSERT não são
+// alterados quando a linha já existe. Linhas excluídas logicamente voltam a ficar ativas
+func (s *BaseEntityService) upsertInTx(ctx context.Context, tx *sql.Tx, entity map[string]interface{}, match []PropertyMetadata) error {
+	metadata := s.metadata
+	data := make(map[string]interface{}, len(entity)+1)
+	for k, v := range entity {
+		data[k] = v
+	}
+	if metadata.SoftDeleteProperty != "" {
+		data[metadata.SoftDeleteProperty] = nil
+	}
+	applyAutoValues(ctx, metadata, data, queryOperationUpsert)
+	if _, err := s.server.generateKeyValues(metadata, data); err != nil {
+		return err
+	}
+	s.stripVirtualProperties(data)
+	if err := s.preparePayloadValues(metadata, data); err != nil {
+		return err
+	}
+	stored, err := s.server.encryptPropertyValues(ctx, metadata, data)
+	if err != nil {
+		return err
+	}
+
+	if keys := extractKeysFromEntity(stored, metadata); hasAllKeys(keys, metadata) {
+		if err := recordHistory(ctx, s.provider, metadata, keys, txHistoryExec(tx)); err != nil {
+			return err
+		}
+	}
+
+	// Colunas preservadas quando a linha já existe
+	fixed := make(map[string]bool)
+	for _, prop := range metadata.Properties {
+		if prop.IsKey || containsProperty(match, prop.Name) {
+			fixed[strings.ToLower(columnNameOf(prop))] = true
+		}
+	}
+	for _, autoValue := range metadata.AutoValues {
+		if autoValue.OnInsert && !autoValue.OnUpdate {
+			fixed[strings.ToLower(columnNameOf(*findPropertyByName(metadata, autoValue.Property)))] = true
+		}
+	}
+	matchColumns := make([]string, len(match))
+	for i, prop := range match {
+		matchColumns[i] = columnNameOf(prop)
+	}
+
+	query, args, native, err := buildUpsertQuery(s.provider, metadata, stored, matchColumns, fixed)
+	if err != nil {
+		return err
+	}
+	if !native {
+		query, args, err = s.buildUpsertFallback(ctx, tx, stored, match, fixed)
+		if err != nil || query == "" {
+			return err
+		}
+	}
+
+	if s.shouldLogSQL() {
+		log.Printf("🔍 [SQL] UPSERT (TX): %s", query)
+		if len(args) > 0 {
+			log.Printf("🔍 [SQL] ARGS: %v", args)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		if s.shouldLogSQL() {
+			log.Printf("❌ [SQL] ERRO: %v", err)
+		}
+		return fmt.Errorf("failed to execute upsert: %w", TranslateDatabaseError(err, metadata))
+	}
+	return nil
+}
+
+// buildUpsertFallback escolhe entre UPDATE e INSERT consultando a existência da linha, nos
+// dialetos sem upsert nativo. Retorna query vazia quando não há o que atualizar
+func (s *BaseEntityService) buildUpsertFallback(ctx context.Context, tx *sql.Tx, stored map[string]interface{}, match []PropertyMetadata, fixed map[string]bool) (string, []interface{}, error) {
+	conditions := make([]string, len(match))
+	matchValues := make(map[string]interface{}, len(match))
+	args := make([]interface{}, len(match))
+	for i, prop := range match {
+		conditions[i] = columnNameOf(prop) + " = ?"
+		args[i] = stored[prop.Name]
+		matchValues[prop.Name] = stored[prop.Name]
+	}
+
+	var count int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", tableNameOf(s.metadata), strings.Join(conditions, " AND "))
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return "", nil, fmt.Errorf("failed to check existing entity: %w", TranslateDatabaseError(err, s.metadata))
+	}
+	if count == 0 {
+		return s.provider.BuildInsertQuery(s.metadata, stored)
+	}
+
+	updates := make(map[string]interface{}, len(stored))
+	for name, value := range stored {
+		column := name
+		if prop := findPropertyByName(s.metadata, name); prop != nil {
+			column = columnNameOf(*prop)
+		}
+		if !fixed[strings.ToLower(column)] {
+			updates[name] = value
+		}
+	}
+	if len(updates) == 0 {
+		return "", nil, nil
+	}
+	return s.provider.BuildUpdateQuery(s.metadata, updates, matchValues)
+}
+
+// insertQueryPattern separa tabela, colunas e valores do INSERT gerado pelos providers
+var insertQueryPattern = regexp.MustCompile(`^INSERT INTO (\S+) \((.+?)\) VALUES \((.+)\)$`)
+
+// buildUpsertQuery gera o upsert nativo do dialeto a partir do INSERT do provider:
+// INSERT ... ON CONFLICT (PostgreSQL), INSERT ... ON DUPLICATE KEY UPDATE (MySQL) ou MERGE
+// (Oracle). As colunas em fixed não são atualizadas. native = false nos demais dialetos
+func buildUpsertQuery(provider DatabaseProvider, metadata EntityMetadata, data map[string]interface{}, matchColumns []string, fixed map[string]bool) (query string, args []interface{}, native bool, err error) {
+	dialect := GetDialect(provider.GetDriverName()).GetName()
+	if dialect != "postgresql" && dialect != "mysql" && dialect != "oracle" {
+		return "", nil, false, nil
+	}
+
+	insert, args, err := provider.BuildInsertQuery(metadata, data)
+	if err != nil {
+		return "", nil, true, fmt.Errorf("failed to build insert query: %w", err)
+	}
+	parts := insertQueryPattern.FindStringSubmatch(strings.TrimSuffix(insert, " RETURNING *"))
+	if parts == nil {
+		return "", nil, true, fmt.Errorf("unsupported insert query for upsert: %s", insert)
+	}
+	table, columns, values := parts[1], strings.Split(parts[2], ", "), strings.Split(parts[3], ", ")
+
+	var updated []string
+	for _, column := range columns {
+		if !fixed[strings.ToLower(column)] {
+			updated = append(updated, column)
+		}
+	}
+
+	switch dialect {
+	case "postgresql":
+		action := "DO NOTHING"
+		if len(updated) > 0 {
+			sets := make([]string, len(updated))
+			for i, column := range updated {
+				sets[i] = fmt.Sprintf("%s = EXCLUDED.%s", column, column)
+			}
+			action = "DO UPDATE SET " + strings.Join(sets, ", ")
+		}
+		query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) %s",
+			table, parts[2], parts[3], strings.Join(matchColumns, ", "), action)
+
+	case "mysql":
+		// Sem colunas a atualizar, a atribuição neutra evita o erro de chave duplicada
+		sets := []string{fmt.Sprintf("%s = %s", matchColumns[0], matchColumns[0])}
+		if len(updated) > 0 {
+			sets = make([]string, len(updated))
+			for i, column := range updated {
+				sets[i] = fmt.Sprintf("%s = VALUES(%s)", column, column)
+			}
+		}
+		query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s",
+			table, parts[2], parts[3], strings.Join(sets, ", "))
+
+	case "oracle":
+		if len(values) != len(columns) {
+			return "", nil, true, fmt.Errorf("unsupported insert query for upsert: %s", insert)
+		}
+		selected := make([]string, len(columns))
+		inserted := make([]string, len(columns))
+		for i, column := range columns {
+			selected[i] = fmt.Sprintf("%s AS %s", values[i], column)
+			inserted[i] = "src." + column
+		}
+		conditions := make([]string, len(matchColumns))
+		for i, column := range matchColumns {
+			conditions[i] = fmt.Sprintf("dst.%s = src.%s", column, column)
+		}
+
+		var merge strings.Builder
+		fmt.Fprintf(&merge, "MERGE INTO %s dst USING (SELECT %s FROM dual) src ON (%s)",
+			table, strings.Join(selected, ", "), strings.Join(conditions, " AND "))
+		if len(updated) > 0 {
+			sets := make([]string, len(updated))
+			for i, column := range updated {
+				sets[i] = fmt.Sprintf("dst.%s = src.%s", column, column)
+			}
+			fmt.Fprintf(&merge, " WHEN MATCHED THEN UPDATE SET %s", strings.Join(sets, ", "))
+		}
+		fmt.Fprintf(&merge, " WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", parts[2], strings.Join(inserted, ", "))
+		query = merge.String()
+	}
+
+	return query, args, true, nil
+}
+
+// syncMatchKey normaliza os valores de correspondência de uma entidade (números do JSON
+// chegam como float64, os do banco como inteiros)
+func syncMatchKey(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		if number, ok := value.(float64); ok && number == math.Trunc(number) {
+			value = int64(number)
+		}
+		parts[i] = strconv.Quote(fmt.Sprint(value))
+	}
+	return strings.Join(parts, ",")
+}
+
+// containsProperty verifica se a propriedade está na lista
+func containsProperty(properties []PropertyMetadata, name string) bool {
+	for _, prop := range properties {
+		if prop.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package odata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncTestProvider usa o upsert genérico (sem dialeto nativo) sobre o SQLite
+type syncTestProvider struct {
+	*MySQLProvider
+}
+
+func (p *syncTestProvider) GetDriverName() string {
+	return "sqlite"
+}
+
+func TestServer_Sync(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	provider := &syncTestProvider{server.provider.(*MySQLProvider)}
+	server.provider = provider
+	orders := server.entities["Orders"].GetMetadata()
+	server.entities["Orders"] = NewBaseEntityService(provider, orders, server)
+	require.NoError(t, server.registerSync("Orders", orders, SyncConfig{AllowDeleteMissing: true, MaxEntities: 3}))
+
+	app := fiber.New()
+	server.router = app
+	server.setupSyncRoute(server.router, "Orders", "/odata", "Orders", nil)
+
+	post := func(path, body string) (int, string) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+	row := func(id int) (status string, customer int64, found bool) {
+		t.Helper()
+		err := provider.GetConnection().QueryRow("SELECT status, customer_id FROM orders WHERE id = ?", id).Scan(&status, &customer)
+		return status, customer, err == nil
+	}
+
+	t.Run("Upsert pela chave", func(t *testing.T) {
+		status, body := post("/odata/Orders/$sync", `{"value": [
+			{"id": 1, "status": "synced"},
+			{"id": 5, "customer_id": 2, "status": "new"}
+		]}`)
+		require.Equal(t, http.StatusOK, status, body)
+
+		var result SyncResult
+		require.NoError(t, json.Unmarshal([]byte(body), &result))
+		assert.Equal(t, 2, result.Upserted)
+		assert.Equal(t, 0, result.Deleted)
+
+		orderStatus, customer, _ := row(1)
+		assert.Equal(t, "synced", orderStatus)
+		assert.Equal(t, int64(1), customer, "propriedades ausentes do payload são preservadas")
+		orderStatus, _, found := row(5)
+		require.True(t, found)
+		assert.Equal(t, "new", orderStatus)
+	})
+
+	t.Run("deleteMissing restrito ao $filter", func(t *testing.T) {
+		status, body := post("/odata/Orders/$sync?$filter=customer_id%20eq%201",
+			`{"deleteMissing": true, "value": [{"id": 1, "status": "kept"}]}`)
+		require.Equal(t, http.StatusOK, status, body)
+
+		var result SyncResult
+		require.NoError(t, json.Unmarshal([]byte(body), &result))
+		assert.Equal(t, 1, result.Upserted)
+		assert.Equal(t, 1, result.Deleted)
+		assert.Equal(t, []map[string]interface{}{{"id": float64(2)}}, result.DeletedKeys)
+
+		_, _, found := row(2)
+		assert.False(t, found)
+		_, _, found = row(5)
+		assert.True(t, found, "linhas fora do escopo não são excluídas")
+	})
+
+	t.Run("Payload inválido", func(t *testing.T) {
+		for body, expected := range map[string]int{
+			`{"value": [{"status": "sem chave"}]}`:                            http.StatusBadRequest,
+			`{"value": [{"id": 1}, {"id": 1}]}`:                               http.StatusBadRequest,
+			`{"keyStrategy": "alternate", "value": []}`:                       http.StatusBadRequest,
+			`{"value": [{"id": 1}, {"id": 2}, {"id": 3}, {"id": 4}]}`:         http.StatusRequestEntityTooLarge,
+			`{"keyStrategy": "natural", "value": [{"id": 1, "status": "x"}]}`: http.StatusBadRequest,
+		} {
+			status, response := post("/odata/Orders/$sync", body)
+			assert.Equal(t, expected, status, response)
+		}
+		orderStatus, _, _ := row(1)
+		assert.Equal(t, "kept", orderStatus)
+	})
+}
+
+func TestBuildUpsertQuery(t *testing.T) {
+	metadata := EntityMetadata{
+		Name:      "Orders",
+		TableName: "orders",
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+			{Name: "code", ColumnName: "code", Type: "string"},
+		},
+	}
+	data := map[string]interface{}{"id": int64(1), "code": "A1"}
+	fixed := map[string]bool{"id": true}
+
+	query, _, native, err := buildUpsertQuery(&PostgreSQLProvider{BaseProvider: BaseProvider{driverName: "pgx"}}, metadata, data, []string{"id"}, fixed)
+	require.NoError(t, err)
+	assert.True(t, native)
+	assert.Contains(t, query, " ON CONFLICT (id) DO UPDATE SET code = EXCLUDED.code")
+	assert.NotContains(t, query, "RETURNING")
+
+	query, _, _, err = buildUpsertQuery(NewMySQLProvider(nil), metadata, data, []string{"id"}, fixed)
+	require.NoError(t, err)
+	assert.Contains(t, query, " ON DUPLICATE KEY UPDATE code = VALUES(code)")
+
+	oracle := &OracleProvider{BaseProvider: &BaseProvider{driverName: "oracle"}}
+	query, args, _, err := buildUpsertQuery(oracle, metadata, map[string]interface{}{"code": "A1"}, []string{"code"}, map[string]bool{"code": true})
+	require.NoError(t, err)
+	assert.Equal(t, "MERGE INTO orders dst USING (SELECT :param1 AS code FROM dual) src ON (dst.code = src.code) WHEN NOT MATCHED THEN INSERT (code) VALUES (src.code)", query)
+	assert.Len(t, args, 1)
+
+	_, _, native, err = buildUpsertQuery(&syncTestProvider{NewMySQLProvider(nil)}, metadata, data, []string{"id"}, fixed)
+	require.NoError(t, err)
+	assert.False(t, native)
+}
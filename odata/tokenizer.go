@@ -53,6 +53,12 @@ const (
 	FilterTokenDuration
 	FilterTokenGeographyPoint
 	FilterTokenGeometryPoint
+	// FilterTokenNavigationExists é gerado (não tokenizado) para condições sobre caminhos de
+	// navegação; SemanticReference contém o *navigationFilter
+	FilterTokenNavigationExists
+	// FilterTokenRootReference é uma referência $root/EntitySet(chave)/Propriedade; após a
+	// resolução pelo serviço, SemanticReference contém a *rootReference
+	FilterTokenRootReference
 )
 
 // GetGlobalFilterTokenizer retorna o tokenizer global para filtros
@@ -77,7 +83,7 @@ func createFilterTokenizer() *Tokenizer {
 	t.Add(`^(?i)\b(add|sub|mul|div|divby|mod)\b`, int(FilterTokenArithmetic))
 
 	// Funções (lista completa de funções OData)
-	t.Add(`^(?i)\b(contains|startswith|endswith|length|indexof|substring|tolower|toupper|trim|concat|year|month|day|hour|minute|second|now|date|time|round|floor|ceiling|cast|isof)\b`, int(FilterTokenFunction))
+	t.Add(`^(?i)\b(contains|startswith|endswith|length|indexof|substring|tolower|toupper|trim|concat|year|month|day|hour|minute|second|now|date|time|totaloffsetminutes|round|floor|ceiling|cast|isof)\b`, int(FilterTokenFunction))
 
 	// Parênteses
 	t.Add(`^\(`, int(FilterTokenOpenParen))
@@ -98,16 +104,16 @@ func createFilterTokenizer() *Tokenizer {
 	t.Add(`^geometry'[^']*'`, int(FilterTokenGeometryPoint))
 
 	// DateTime: 2023-12-25T10:30:00Z ou 2023-12-25T10:30:00.000Z
-	t.Add(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d{3})?Z?`, int(FilterTokenDateTime))
+	t.Add(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}(:\d{2}(\.\d{1,9})?)?(Z|[+-]\d{2}:\d{2})?`, int(FilterTokenDateTime))
 
 	// Date: 2023-12-25
 	t.Add(`^\d{4}-\d{2}-\d{2}`, int(FilterTokenDate))
 
 	// Time: 14:30:00
-	t.Add(`^\d{2}:\d{2}:\d{2}`, int(FilterTokenTime))
+	t.Add(`^\d{2}:\d{2}(:\d{2}(\.\d{1,9})?)?`, int(FilterTokenTime))
 
-	// Duration: P1DT12H30M5S
-	t.Add(`^P(\d+D)?(T(\d+H)?(\d+M)?(\d+S)?)?`, int(FilterTokenDuration))
+	// Duration: duration'P1DT12H30M5S' (o prefixo evita conflito com propriedades iniciadas por P)
+	t.Add(`^(?i)duration'-?P(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?'`, int(FilterTokenDuration))
 
 	// Boolean
 	t.Add(`^(?i)\b(true|false)\b`, int(FilterTokenBoolean))
@@ -121,8 +127,14 @@ func createFilterTokenizer() *Tokenizer {
 	// Números (int, float, decimal)
 	t.Add(`^-?\d+(\.\d+)?([eE][+-]?\d+)?[dDfFmM]?`, int(FilterTokenNumber))
 
-	// Propriedades/Identificadores (deve vir por último)
-	t.Add(`^[a-zA-Z_][a-zA-Z0-9_]*`, int(FilterTokenProperty))
+	// $root/EntitySet(chave)/Propriedade: valor de outra entidade, resolvido em subquery
+	t.Add(`^\$root/[a-zA-Z_][a-zA-Z0-9_]*\(('([^']|'')*'|[^()'])*\)(/[a-zA-Z_][a-zA-Z0-9_]*)+`, int(FilterTokenRootReference))
+
+	// $it/Propriedade: a instância atual (o prefixo é removido por resolveItReferences)
+	t.Add(`^\$it\b(/[a-zA-Z_][a-zA-Z0-9_]*)*`, int(FilterTokenProperty))
+
+	// Propriedades/Identificadores e caminhos de navegação (deve vir por último)
+	t.Add(`^[a-zA-Z_][a-zA-Z0-9_]*(/[a-zA-Z_][a-zA-Z0-9_]*)*`, int(FilterTokenProperty))
 
 	// Whitespace (skip)
 	t.Add(`^\s+`, -1) // -1 indica que deve ser ignorado
@@ -0,0 +1,182 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// =======================================================================================
+// TRANSAÇÃO PROPAGADA PELO CONTEXTO (WithTransaction)
+// =======================================================================================
+
+// contextTx é a transação aberta por WithTransaction e compartilhada pelas operações que
+// recebem o contexto: serviços de entidade, ObjectManager e $batch/$sync/$import
+type contextTx struct {
+	tx       *sql.Tx
+	db       *sql.DB
+	provider DatabaseProvider
+
+	mu         sync.Mutex
+	savepoints int // savepoints criados (nomes únicos na transação)
+}
+
+// contextTxKey é a chave da contextTx no contexto
+type contextTxKey struct{}
+
+// WithTransaction executa fn em uma transação do banco do contexto (o provider do tenant,
+// no modo multi-tenant, ou o provider padrão). As operações que recebem txCtx - Create,
+// Update, Delete e Query dos serviços de entidade, ObjectManager criado com txCtx e
+// transações internas da biblioteca - usam essa transação. Commit se fn retornar nil,
+// rollback em caso de erro ou panic. Chamadas aninhadas usam um savepoint
+func (s *Server) WithTransaction(ctx context.Context, fn func(txCtx context.Context) error) (err error) {
+	var tx *scopedTx
+	if current := contextTransaction(ctx); current != nil {
+		tx, err = current.savepoint(ctx, current.provider)
+	} else {
+		tx, err = s.beginContextTx(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	txCtx := tx.ctx
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(txCtx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// beginContextTx inicia a transação de WithTransaction e a associa ao contexto
+func (s *Server) beginContextTx(ctx context.Context) (*scopedTx, error) {
+	provider := s.contextProvider(ctx)
+	if provider == nil {
+		return nil, fmt.Errorf("no database provider configured")
+	}
+	db := provider.GetConnection()
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	tx, err := beginSessionTx(ctx, provider, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	current := &contextTx{tx: tx.Tx, db: db, provider: provider}
+	tx.ctx = context.WithValue(ctx, contextTxKey{}, current)
+	return tx, nil
+}
+
+// contextProvider retorna o provider do contexto: o do tenant no modo multi-tenant ou o padrão
+func (s *Server) contextProvider(ctx context.Context) DatabaseProvider {
+	if s.multiTenantPool != nil {
+		tenantID := contextTenant(ctx)
+		if tenantID == "" {
+			tenantID = "default"
+		}
+		if provider := s.multiTenantPool.GetProvider(tenantID); provider != nil {
+			return provider
+		}
+	}
+	return s.provider
+}
+
+// TransactionFromContext retorna a transação aberta por WithTransaction (nil se não houver),
+// para SQL próprio na mesma transação
+func TransactionFromContext(ctx context.Context) *sql.Tx {
+	if current := contextTransaction(ctx); current != nil {
+		return current.tx
+	}
+	return nil
+}
+
+// contextTransaction retorna a contextTx associada ao contexto (nil se não houver)
+func contextTransaction(ctx context.Context) *contextTx {
+	current, _ := ctx.Value(contextTxKey{}).(*contextTx)
+	return current
+}
+
+// querier retorna a transação para as queries do banco informado. Bancos de outros
+// providers não participam da transação
+func (t *contextTx) querier(db *sql.DB) (sqlQuerier, error) {
+	if db != t.db {
+		return nil, fmt.Errorf("the entity database is not part of the context transaction: operations in a transaction must use the same database provider")
+	}
+	return txQuerier{t.tx}, nil
+}
+
+// savepoint abre um escopo aninhado na transação (transações internas da biblioteca ou
+// WithTransaction aninhado)
+func (t *contextTx) savepoint(ctx context.Context, provider DatabaseProvider) (*scopedTx, error) {
+	if db := provider.GetConnection(); db != t.db {
+		return nil, fmt.Errorf("the entity database is not part of the context transaction: operations in a transaction must use the same database provider")
+	}
+
+	t.mu.Lock()
+	t.savepoints++
+	name := fmt.Sprintf("godata_tx_%d", t.savepoints)
+	t.mu.Unlock()
+
+	sp, err := newSavepoint(ctx, t.provider, t.tx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &scopedTx{Tx: t.tx, ctx: ctx, savepoint: sp}, nil
+}
+
+// txQuerier executa as queries na transação do contexto
+type txQuerier struct {
+	*sql.Tx
+}
+
+// BeginTx implementa sqlQuerier: dentro da transação do contexto, os escopos aninhados
+// usam savepoints (beginSessionTx)
+func (q txQuerier) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return nil, fmt.Errorf("nested transaction in the context transaction: use a savepoint")
+}
+
+// scopedTx é uma transação iniciada pela biblioteca. Dentro de WithTransaction é um
+// savepoint da transação do contexto: Commit libera o savepoint e Rollback desfaz apenas
+// as alterações feitas após ele
+type scopedTx struct {
+	*sql.Tx
+	ctx       context.Context
+	savepoint *changesetSavepoint
+	done      bool
+}
+
+// Commit confirma a transação ou libera o savepoint
+func (t *scopedTx) Commit() error {
+	if t.savepoint == nil {
+		return t.Tx.Commit()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	return t.savepoint.releaseSavepoint(t.ctx)
+}
+
+// Rollback desfaz a transação ou as alterações feitas após o savepoint
+func (t *scopedTx) Rollback() error {
+	if t.savepoint == nil {
+		return t.Tx.Rollback()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	return t.savepoint.rollback(t.ctx)
+}
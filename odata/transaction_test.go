@@ -0,0 +1,116 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_WithTransaction(t *testing.T) {
+	// A conexão única do pool garante que todas as operações usam a transação: uma query
+	// fora dela ficaria bloqueada
+	server := newPatchDeltaTestServer(t)
+	db := server.provider.GetConnection()
+	customers := server.GetEntityService("Customers")
+	orders := server.GetEntityService("Orders")
+
+	count := func(t *testing.T, query string) int {
+		t.Helper()
+		var n int
+		require.NoError(t, db.QueryRow(query).Scan(&n))
+		return n
+	}
+
+	t.Run("rollback on error", func(t *testing.T) {
+		err := server.WithTransaction(context.Background(), func(txCtx context.Context) error {
+			_, err := customers.Create(txCtx, map[string]any{"id": 10, "name": "Carla"})
+			require.NoError(t, err)
+			_, err = orders.Update(txCtx, map[string]any{"id": 1}, map[string]any{"status": "closed"})
+			require.NoError(t, err)
+			return errors.New("payment declined")
+		})
+		assert.EqualError(t, err, "payment declined")
+
+		assert.Equal(t, 0, count(t, "SELECT COUNT(*) FROM customers WHERE id = 10"))
+		assert.Equal(t, 0, count(t, "SELECT COUNT(*) FROM orders WHERE status = 'closed'"))
+	})
+
+	t.Run("commit shares the transaction", func(t *testing.T) {
+		err := server.WithTransaction(context.Background(), func(txCtx context.Context) error {
+			require.NotNil(t, TransactionFromContext(txCtx))
+			if _, err := customers.Create(txCtx, map[string]any{"id": 11, "name": "Davi"}); err != nil {
+				return err
+			}
+
+			// As leituras enxergam as gravações ainda não confirmadas
+			created, err := customers.Get(txCtx, map[string]any{"id": 11})
+			require.NoError(t, err)
+			require.NotNil(t, created)
+
+			rows, err := NewObjectManager(server.provider, txCtx).ExecuteQuery("SELECT name FROM customers WHERE id = 11")
+			require.NoError(t, err)
+			defer rows.Close()
+			require.True(t, rows.Next())
+
+			return orders.Delete(txCtx, map[string]any{"id": 2})
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, count(t, "SELECT COUNT(*) FROM customers WHERE id = 11"))
+		assert.Equal(t, 0, count(t, "SELECT COUNT(*) FROM orders WHERE id = 2"))
+		assert.Nil(t, TransactionFromContext(context.Background()))
+	})
+
+	t.Run("nested scopes use savepoints", func(t *testing.T) {
+		err := server.WithTransaction(context.Background(), func(txCtx context.Context) error {
+			if _, err := customers.Create(txCtx, map[string]any{"id": 12, "name": "Eva"}); err != nil {
+				return err
+			}
+			inner := server.WithTransaction(txCtx, func(innerCtx context.Context) error {
+				_, err := customers.Create(innerCtx, map[string]any{"id": 13, "name": "Fábio"})
+				require.NoError(t, err)
+				return errors.New("optional step failed")
+			})
+			assert.Error(t, inner)
+
+			// Transações internas da biblioteca (ObjectManager, PATCH profundo, $batch) também
+			// viram savepoints
+			manager := NewObjectManager(server.provider, txCtx)
+			return manager.UnitOfWork(func() error {
+				_, err := manager.ExecuteQuery("SELECT 1")
+				return err
+			})
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, count(t, "SELECT COUNT(*) FROM customers WHERE id = 12"))
+		assert.Equal(t, 0, count(t, "SELECT COUNT(*) FROM customers WHERE id = 13"))
+	})
+
+	t.Run("panic rolls back", func(t *testing.T) {
+		assert.Panics(t, func() {
+			server.WithTransaction(context.Background(), func(txCtx context.Context) error {
+				customers.Create(txCtx, map[string]any{"id": 14, "name": "Gil"})
+				panic("boom")
+			})
+		})
+		assert.Equal(t, 0, count(t, "SELECT COUNT(*) FROM customers WHERE id = 14"))
+	})
+
+	t.Run("entities of another provider", func(t *testing.T) {
+		other, err := sql.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		t.Cleanup(func() { other.Close() })
+		service := NewBaseEntityService(NewMySQLProvider(other), customers.GetMetadata(), server)
+
+		err = server.WithTransaction(context.Background(), func(txCtx context.Context) error {
+			_, err := service.Create(txCtx, map[string]any{"id": 15, "name": "Hugo"})
+			return err
+		})
+		assert.ErrorContains(t, err, "not part of the context transaction")
+	})
+}
@@ -16,14 +16,19 @@ type ODataResponse struct {
 	NextLink string      `json:"@odata.nextLink,omitempty"`
 	Value    interface{} `json:"value"`
 	Error    *ODataError `json:"error,omitempty"`
+
+	// Anotações de instância do envelope (ex: @myapp.permissions), antes do value
+	Annotations []OrderedProperty `json:"-"`
 }
 
 // ODataError representa um erro OData
 type ODataError struct {
-	Code    string             `json:"code"`
-	Message string             `json:"message"`
-	Target  string             `json:"target,omitempty"`
-	Details []ODataErrorDetail `json:"details,omitempty"`
+	Code       string             `json:"code"`
+	Message    string             `json:"message"`
+	Target     string             `json:"target,omitempty"`
+	Details    []ODataErrorDetail `json:"details,omitempty"`
+	InnerError *ODataInnerError   `json:"innererror,omitempty"`
+	Status     int                `json:"-"` // Status HTTP quando retornado por um handler (padrão: 500)
 }
 
 // ODataErrorDetail representa detalhes adicionais de um erro
@@ -56,6 +61,15 @@ type QueryOptions struct {
 	Count   *GoDataCountQuery
 	Compute *ComputeOption
 	Search  *SearchOption
+
+	// Resolve entidades relacionadas para JOINs de navegação (preenchido pelo serviço)
+	navigationResolver navigationResolver
+
+	// Filtro da exclusão lógica já combinado ao $filter (evita repeti-lo no $count)
+	softDeleteApplied bool
+
+	// Referências $root do $orderby já resolvidas pelo serviço (valor da referência -> subquery)
+	rootReferences map[string]*rootReference
 }
 
 // EntityMetadata representa os metadados de uma entidade
@@ -65,11 +79,30 @@ type EntityMetadata struct {
 	Schema     string // Schema da tabela
 	Properties []PropertyMetadata
 	Keys       []string
+	Search     *SearchConfig // Configuração de $search (propriedades sem a tag searchable)
+
+	// Comparações de strings no $filter sem diferenciar maiúsculas/minúsculas
+	// (nil = configuração do servidor)
+	CaseInsensitive *bool
+
+	DefaultFilter string        // Filtro aplicado a todas as consultas da coleção
+	QueryPresets  []QueryPreset // Consultas nomeadas da coleção
+
+	SoftDeleteProperty string // Propriedade de data da exclusão lógica (WithSoftDelete)
+	History            bool   // Versões anteriores mantidas na tabela de histórico (WithHistory)
+
+	ExposedProperties []string // Únicas propriedades expostas nas consultas (vazio = todas)
+	RequireSelect     bool     // Consultas exigem $select (WithRequireSelect)
+
+	AutoValues []AutoValue // Propriedades preenchidas pelo servidor nas gravações (WithAutoValue)
+
+	historyIndex []string // Colunas do índice de versões (apenas na tabela de histórico)
 }
 
 // PropertyMetadata representa os metadados de uma propriedade
 type PropertyMetadata struct {
 	Name         string
+	FieldName    string // Campo Go de origem (structs mapeadas), independente da política de nomes
 	Type         string
 	ColumnName   string
 	IsKey        bool
@@ -78,7 +111,9 @@ type PropertyMetadata struct {
 	Precision    int
 	Scale        int
 	IsNavigation bool
+	IsSearchable bool // Participa do $search (tag odata:"searchable")
 	HasDefault   bool
+	DefaultValue string // Expressão SQL do valor padrão (prop:"default:<valor>")
 	IDGenerator  string
 	SequenceName string
 	IsCollection bool
@@ -90,6 +125,13 @@ type PropertyMetadata struct {
 	Schema          string                   // Schema da tabela
 	Association     *AssociationMetadata     // Para associações simples
 	ManyAssociation *ManyAssociationMetadata // Para associações múltiplas
+
+	// Criptografia em repouso (prop:"[Encrypted]; encryption:deterministic")
+	IsEncrypted             bool
+	DeterministicEncryption bool // Mesmo texto gera o mesmo valor cifrado, permitindo eq/ne/in no $filter
+
+	// Valores permitidos (odata:"enum:ativo|inativo"), validados nos payloads de escrita
+	EnumValues []string
 }
 
 // RelationshipMetadata representa os metadados de um relacionamento
@@ -130,6 +172,9 @@ const (
 	IDGeneratorUuid36    IDGeneratorType = "uuid36"
 	IDGeneratorUuid32    IDGeneratorType = "uuid32"
 	IDGeneratorSmartGuid IDGeneratorType = "smartGuid"
+	IDGeneratorSnowflake IDGeneratorType = "snowflake" // int64 ordenável por tempo, com nó (SnowflakeNodeID)
+	IDGeneratorUlid      IDGeneratorType = "ulid"      // 26 caracteres, ordenável por milissegundo
+	IDGeneratorKsuid     IDGeneratorType = "ksuid"     // 27 caracteres, ordenável por segundo
 )
 
 // DatabaseProvider interface para os providers de banco
@@ -149,6 +194,15 @@ type DatabaseProvider interface {
 	FormatDateTime(t time.Time) string
 }
 
+// ContextQueryBuilder é implementado pelos providers que montam as queries com o contexto da
+// requisição: o cancelamento e o deadline interrompem a montagem, e os valores do contexto
+// (tracing, tenant) chegam às expressões. Os serviços de entidade o preferem aos métodos
+// BuildSelectQuery e BuildOrderByClause de DatabaseProvider
+type ContextQueryBuilder interface {
+	BuildSelectQueryContext(ctx context.Context, entity EntityMetadata, options QueryOptions) (string, []interface{}, error)
+	BuildOrderByClauseContext(ctx context.Context, orderBy string, metadata EntityMetadata) (string, error)
+}
+
 // EntityService interface para serviços de entidade
 type EntityService interface {
 	GetMetadata() EntityMetadata
@@ -233,6 +287,10 @@ const (
 type OrderByExpression struct {
 	Property  string
 	Direction OrderByDirection
+
+	// Árvore da expressão quando o item não é uma propriedade simples
+	// (ex: "Price mul Quantity", "tolower(Name)"); nil para propriedades
+	Expression *ParseNode
 }
 
 // MetadataResponse representa a resposta de metadados em JSON
@@ -241,7 +299,12 @@ type MetadataResponse struct {
 	Version    string               `json:"@odata.version"`
 	Entities   []EntityTypeMetadata `json:"entities"`
 	EntitySets []EntitySetMetadata  `json:"entitySets"`
+	Functions  []FunctionMetadata   `json:"functions,omitempty"`
+	Actions    []ActionMetadata     `json:"actions,omitempty"`
 	Schemas    []SchemaMetadata     `json:"schemas"`
+
+	FunctionImports []OperationImportMetadata `json:"functionImports,omitempty"`
+	ActionImports   []OperationImportMetadata `json:"actionImports,omitempty"`
 }
 
 // EntityTypeMetadata representa os metadados de um tipo de entidade
@@ -263,6 +326,7 @@ type PropertyTypeMetadata struct {
 	Scale      int    `json:"scale,omitempty"`
 	IsKey      bool   `json:"isKey"`
 	HasDefault bool   `json:"hasDefault"`
+	Computed   bool   `json:"computed,omitempty"`
 }
 
 // NavigationPropertyMetadata representa os metadados de uma propriedade de navegação
@@ -281,6 +345,34 @@ type EntitySetMetadata struct {
 	URL        string `json:"url"`
 }
 
+// FunctionMetadata representa os metadados de uma função OData
+type FunctionMetadata struct {
+	Name             string          `json:"name"`
+	Namespace        string          `json:"namespace"`
+	IsBound          bool            `json:"isBound"`
+	IsComposable     bool            `json:"isComposable,omitempty"`
+	BindingParameter string          `json:"bindingParameter,omitempty"`
+	Parameters       []ParameterInfo `json:"parameters,omitempty"`
+	ReturnType       string          `json:"returnType"`
+}
+
+// ActionMetadata representa os metadados de uma ação OData
+type ActionMetadata struct {
+	Name       string          `json:"name"`
+	Namespace  string          `json:"namespace"`
+	IsBound    bool            `json:"isBound"`
+	Parameters []ParameterInfo `json:"parameters,omitempty"`
+	ReturnType string          `json:"returnType,omitempty"`
+}
+
+// OperationImportMetadata representa um FunctionImport ou ActionImport do container
+type OperationImportMetadata struct {
+	Name     string `json:"name"`
+	Function string `json:"function,omitempty"` // Nome qualificado da função (FunctionImport)
+	Action   string `json:"action,omitempty"`   // Nome qualificado da ação (ActionImport)
+	URL      string `json:"url"`
+}
+
 // SchemaMetadata representa os metadados de um schema
 type SchemaMetadata struct {
 	Namespace       string                  `json:"namespace"`
@@ -298,6 +390,7 @@ type EntityContainerMetadata struct {
 
 // OrderedEntity representa uma entidade com propriedades ordenadas
 type OrderedEntity struct {
+	Annotations     []OrderedProperty `json:"-"` // Anotações de instância (@odata.type, @odata.id, ...)
 	Properties      []OrderedProperty `json:"-"`
 	NavigationLinks []NavigationLink  `json:"-"`
 	data            map[string]interface{}
@@ -326,6 +419,17 @@ func NewOrderedEntity() *OrderedEntity {
 	}
 }
 
+// newOrderedEntityWithCapacity cria uma entidade ordenada com espaço pré-alocado para as
+// propriedades e links de navegação (scan de resultados)
+func newOrderedEntityWithCapacity(properties, links int) *OrderedEntity {
+	return &OrderedEntity{
+		Properties:      make([]OrderedProperty, 0, properties),
+		NavigationLinks: make([]NavigationLink, 0, links),
+		data:            make(map[string]interface{}, properties),
+		navigationData:  make(map[string]string, links),
+	}
+}
+
 // Set adiciona uma propriedade mantendo a ordem
 func (e *OrderedEntity) Set(name string, value interface{}) {
 	// Verifica se a propriedade já existe
@@ -345,6 +449,23 @@ func (e *OrderedEntity) Set(name string, value interface{}) {
 	e.data[name] = value
 }
 
+// SetAnnotation adiciona uma anotação de instância (nome com "@"), serializada antes das
+// propriedades
+func (e *OrderedEntity) SetAnnotation(name string, value interface{}) {
+	e.Annotations = setOrderedProperty(e.Annotations, name, value)
+}
+
+// setOrderedProperty substitui o valor da propriedade ou a adiciona no fim da lista
+func setOrderedProperty(properties []OrderedProperty, name string, value interface{}) []OrderedProperty {
+	for i, prop := range properties {
+		if prop.Name == name {
+			properties[i].Value = value
+			return properties
+		}
+	}
+	return append(properties, OrderedProperty{Name: name, Value: value})
+}
+
 // SetNavigationProperty adiciona uma propriedade de navegação como link
 func (e *OrderedEntity) SetNavigationProperty(name string, navigationURL string) {
 	// Verifica se o navigation link já existe
@@ -380,16 +501,18 @@ func (e *OrderedEntity) MarshalJSON() ([]byte, error) {
 	// Constrói o JSON mantendo a ordem das propriedades
 	var pairs []string
 
-	// Adiciona propriedades normais
-	for _, prop := range e.Properties {
-		// Serializa o valor
-		valueJSON, err := json.Marshal(prop.Value)
-		if err != nil {
-			return nil, err
-		}
+	// Adiciona anotações de instância e propriedades normais
+	for _, properties := range [][]OrderedProperty{e.Annotations, e.Properties} {
+		for _, prop := range properties {
+			// Serializa o valor
+			valueJSON, err := json.Marshal(prop.Value)
+			if err != nil {
+				return nil, err
+			}
 
-		// Adiciona o par chave-valor
-		pairs = append(pairs, fmt.Sprintf(`"%s":%s`, prop.Name, string(valueJSON)))
+			// Adiciona o par chave-valor
+			pairs = append(pairs, fmt.Sprintf(`"%s":%s`, prop.Name, string(valueJSON)))
+		}
 	}
 
 	// Adiciona navigation links no formato OData
@@ -415,6 +538,7 @@ func (e *OrderedEntity) UnmarshalJSON(data []byte) error {
 
 	// Reinicializa os dados
 	e.data = make(map[string]interface{})
+	e.Annotations = nil
 	e.Properties = make([]OrderedProperty, 0)
 	e.NavigationLinks = make([]NavigationLink, 0)
 	e.navigationData = make(map[string]string)
@@ -425,6 +549,8 @@ func (e *OrderedEntity) UnmarshalJSON(data []byte) error {
 			// É um navigation link
 			propName := strings.TrimSuffix(key, "@odata.navigationLink")
 			e.SetNavigationProperty(propName, value.(string))
+		} else if strings.HasPrefix(key, "@") {
+			e.SetAnnotation(key, value)
 		} else {
 			// É uma propriedade normal
 			e.Set(key, value)
@@ -437,6 +563,7 @@ func (e *OrderedEntity) UnmarshalJSON(data []byte) error {
 // OrderedEntityResponse representa uma resposta de entidade única mantendo a ordem dos campos
 type OrderedEntityResponse struct {
 	Context         string                   `json:"@odata.context"`
+	Annotations     []OrderedProperty        `json:"-"` // Anotações de instância, após o contexto
 	Fields          []ResponseField          `json:"-"`
 	NavigationLinks []ResponseNavigationLink `json:"-"`
 	entityMetadata  EntityMetadata           `json:"-"`
@@ -472,6 +599,11 @@ func (r *OrderedEntityResponse) AddField(name string, value interface{}) {
 	})
 }
 
+// SetAnnotation adiciona uma anotação de instância (nome com "@") à resposta
+func (r *OrderedEntityResponse) SetAnnotation(name string, value interface{}) {
+	r.Annotations = setOrderedProperty(r.Annotations, name, value)
+}
+
 // AddNavigationLink adiciona um navigation link à resposta
 func (r *OrderedEntityResponse) AddNavigationLink(name, url string) {
 	r.NavigationLinks = append(r.NavigationLinks, ResponseNavigationLink{
@@ -484,12 +616,22 @@ func (r *OrderedEntityResponse) AddNavigationLink(name, url string) {
 func (r *OrderedEntityResponse) MarshalJSON() ([]byte, error) {
 	var pairs []string
 
-	// Adiciona o contexto primeiro
-	contextJSON, err := json.Marshal(r.Context)
-	if err != nil {
-		return nil, err
+	// Adiciona o contexto primeiro (omitido com odata.metadata=none)
+	if r.Context != "" {
+		contextJSON, err := json.Marshal(r.Context)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, fmt.Sprintf(`"@odata.context":%s`, string(contextJSON)))
+	}
+
+	for _, annotation := range r.Annotations {
+		valueJSON, err := json.Marshal(annotation.Value)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, fmt.Sprintf(`"%s":%s`, annotation.Name, string(valueJSON)))
 	}
-	pairs = append(pairs, fmt.Sprintf(`"@odata.context":%s`, string(contextJSON)))
 
 	// Adiciona campos na ordem dos metadados da entidade
 	fieldsMap := make(map[string]interface{})
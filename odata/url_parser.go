@@ -33,6 +33,8 @@ var supportedODataKeywords = map[string]bool{
 	"$search":      true,
 	"$compute":     true,
 	"$format":      true,
+	"$asof":        true,
+	"$skiptoken":   true,
 }
 
 // Configuração de compliance OData otimizada
@@ -147,8 +149,8 @@ func (up *URLParser) fastValidate(values url.Values) error {
 	}
 
 	for key, vals := range values {
-		// Verifica se é keyword suportada (O(1) lookup)
-		if _, ok := supportedODataKeywords[key]; !ok {
+		// Verifica se é keyword suportada (O(1) lookup); aliases (@p) são livres
+		if _, ok := supportedODataKeywords[key]; !ok && !isParameterAlias(key) {
 			return &QueryParseError{
 				Message: fmt.Sprintf("Query parameter '%s' is not supported", key),
 				Query:   key,
@@ -0,0 +1,144 @@
+package odata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// =======================================================================================
+// PROPRIEDADES VIRTUAIS (CALCULADAS EM GO)
+// =======================================================================================
+
+// VirtualPropertyFunc calcula o valor de uma propriedade virtual a partir da entidade lida
+// do banco
+type VirtualPropertyFunc func(entity *OrderedEntity) any
+
+// VirtualPropertyOption configura uma propriedade virtual
+type VirtualPropertyOption func(*virtualProperty)
+
+// virtualProperty é uma propriedade calculada após o scan, sem coluna no banco
+type virtualProperty struct {
+	name     string
+	goType   string
+	nullable bool
+	compute  VirtualPropertyFunc
+}
+
+// WithVirtualPropertyType define o tipo Go (ex: "float64", "int64", "bool") usado para o
+// tipo EDM da propriedade nos metadados. Padrão: string
+func WithVirtualPropertyType(goType string) VirtualPropertyOption {
+	return func(prop *virtualProperty) {
+		prop.goType = goType
+	}
+}
+
+// WithVirtualPropertyNullable marca a propriedade virtual como nullable nos metadados
+func WithVirtualPropertyNullable() VirtualPropertyOption {
+	return func(prop *virtualProperty) {
+		prop.nullable = true
+	}
+}
+
+// AddVirtualProperty registra uma propriedade calculada em Go para a entidade. O valor é
+// calculado após o scan de cada entidade, aparece nos metadados como computada, respeita o
+// $select e é ignorado nas gravações. Não pode ser usada em $filter/$orderby
+func (s *Server) AddVirtualProperty(entityName, propertyName string, compute VirtualPropertyFunc, opts ...VirtualPropertyOption) error {
+	if compute == nil {
+		return fmt.Errorf("virtual property '%s' requires a compute function", propertyName)
+	}
+
+	service := s.GetEntityService(entityName)
+	if service == nil {
+		return fmt.Errorf("entity '%s' not found", entityName)
+	}
+	for _, prop := range service.GetMetadata().Properties {
+		if strings.EqualFold(prop.Name, propertyName) {
+			return fmt.Errorf("entity '%s' already has a property '%s'", entityName, propertyName)
+		}
+	}
+
+	prop := virtualProperty{name: propertyName, goType: "string", compute: compute}
+	for _, opt := range opts {
+		opt(&prop)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.virtualProperties == nil {
+		s.virtualProperties = make(map[string][]virtualProperty)
+	}
+	for _, existing := range s.virtualProperties[entityName] {
+		if strings.EqualFold(existing.name, propertyName) {
+			return fmt.Errorf("virtual property '%s' already registered for entity '%s'", propertyName, entityName)
+		}
+	}
+	s.virtualProperties[entityName] = append(s.virtualProperties[entityName], prop)
+	return nil
+}
+
+// getVirtualProperties retorna as propriedades virtuais registradas para a entidade
+func (s *Server) getVirtualProperties(entityName string) []virtualProperty {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.virtualProperties[entityName]
+}
+
+// virtualProperties retorna as propriedades virtuais da entidade do serviço
+func (s *BaseEntityService) virtualProperties() []virtualProperty {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.getVirtualProperties(s.metadata.Name)
+}
+
+// selectsVirtualProperty verifica se o $select inclui alguma propriedade virtual
+func selectsVirtualProperty(sel *GoDataSelectQuery, virtuals []virtualProperty) bool {
+	for _, field := range GetSelectedProperties(sel) {
+		for _, prop := range virtuals {
+			if strings.EqualFold(prop.name, field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyVirtualProperties calcula as propriedades virtuais das entidades. Com $select, só as
+// propriedades virtuais selecionadas são calculadas
+func applyVirtualProperties(results []any, virtuals []virtualProperty, sel *GoDataSelectQuery) {
+	if len(virtuals) == 0 {
+		return
+	}
+
+	selected := virtuals
+	if sel != nil && len(sel.SelectItems) > 0 {
+		selected = nil
+		for _, prop := range virtuals {
+			if selectsVirtualProperty(sel, []virtualProperty{prop}) {
+				selected = append(selected, prop)
+			}
+		}
+	}
+
+	for _, result := range results {
+		entity, ok := result.(*OrderedEntity)
+		if !ok {
+			continue
+		}
+		for _, prop := range selected {
+			entity.Set(prop.name, prop.compute(entity))
+		}
+	}
+}
+
+// stripVirtualProperties remove as propriedades virtuais do payload antes da gravação
+func (s *BaseEntityService) stripVirtualProperties(data map[string]any) {
+	for _, prop := range s.virtualProperties() {
+		for key := range data {
+			if strings.EqualFold(key, prop.name) {
+				delete(data, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package odata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newVirtualPropertyTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := newBatchGetTestServer(t)
+	server.router = fiber.New()
+	server.setupEntityRoutes("Products")
+
+	err := server.AddVirtualProperty("Products", "PriceWithTax", func(entity *OrderedEntity) any {
+		price, _ := entity.Get("price")
+		value, _ := price.(float64)
+		return value * 1.1
+	}, WithVirtualPropertyType("float64"))
+	require.NoError(t, err)
+	return server
+}
+
+func TestServer_AddVirtualProperty_Validation(t *testing.T) {
+	server := newVirtualPropertyTestServer(t)
+
+	assert.Error(t, server.AddVirtualProperty("Unknown", "X", func(*OrderedEntity) any { return nil }))
+	assert.Error(t, server.AddVirtualProperty("Products", "price", func(*OrderedEntity) any { return nil }), "conflito com propriedade existente")
+	assert.Error(t, server.AddVirtualProperty("Products", "pricewithtax", func(*OrderedEntity) any { return nil }), "já registrada")
+	assert.Error(t, server.AddVirtualProperty("Products", "Y", nil))
+}
+
+func TestServer_VirtualProperties(t *testing.T) {
+	server := newVirtualPropertyTestServer(t)
+
+	t.Run("Calculada após o scan", func(t *testing.T) {
+		status, body := getComputeTestProducts(t, server, "$orderby=id")
+		require.Equal(t, http.StatusOK, status)
+
+		values := body["value"].([]any)
+		require.Len(t, values, 3)
+		assert.InDelta(t, 11.0, values[0].(map[string]any)["PriceWithTax"], 0.0001)
+		assert.InDelta(t, 990.0, values[2].(map[string]any)["PriceWithTax"], 0.0001)
+	})
+
+	t.Run("Respeita o $select", func(t *testing.T) {
+		status, body := getComputeTestProducts(t, server, "$orderby=id&$select=name,PriceWithTax")
+		require.Equal(t, http.StatusOK, status)
+
+		first := body["value"].([]any)[0].(map[string]any)
+		assert.Equal(t, "Mouse", first["name"])
+		assert.InDelta(t, 11.0, first["PriceWithTax"], 0.0001, "dependências buscadas mesmo fora do $select")
+		assert.NotContains(t, first, "price")
+
+		status, body = getComputeTestProducts(t, server, "$orderby=id&$select=name")
+		require.Equal(t, http.StatusOK, status)
+		assert.NotContains(t, body["value"].([]any)[0].(map[string]any), "PriceWithTax")
+	})
+
+	t.Run("Aparece nos metadados como computada", func(t *testing.T) {
+		var found *PropertyTypeMetadata
+		for _, entity := range server.buildMetadataJSON().Entities {
+			for i, prop := range entity.Properties {
+				if entity.Name == "Products" && prop.Name == "PriceWithTax" {
+					found = &entity.Properties[i]
+				}
+			}
+		}
+		require.NotNil(t, found)
+		assert.True(t, found.Computed)
+		assert.Equal(t, "Edm.Double", found.Type)
+	})
+
+	t.Run("Ignorada nas gravações", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/odata/Products", strings.NewReader(`{"id": 4, "name": "Cabo", "price": 5, "PriceWithTax": 999}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := server.router.Test(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		status, body := getComputeTestProducts(t, server, "$filter=id%20eq%204")
+		require.Equal(t, http.StatusOK, status)
+		assert.InDelta(t, 5.5, body["value"].([]any)[0].(map[string]any)["PriceWithTax"], 0.0001)
+	})
+}
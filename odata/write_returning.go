@@ -0,0 +1,163 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// =======================================================================================
+// RETORNO DA LINHA GRAVADA (INSERT/UPDATE EM UM ÚNICO ROUND TRIP)
+// =======================================================================================
+
+// returningProvider é implementado pelos providers cujas queries de INSERT/UPDATE terminam em
+// RETURNING e devolvem a linha gravada como result set (PostgreSQL)
+type returningProvider interface {
+	writesReturnRows() bool
+}
+
+// returningIntoProvider é implementado pelos providers cujo dialeto devolve as colunas gravadas
+// em variáveis de saída (Oracle: RETURNING ... INTO) em vez de um result set
+type returningIntoProvider interface {
+	buildReturningInto(entity EntityMetadata, query string, args []interface{}) (*returningInto, error)
+}
+
+// returningInto é a query de gravação com as variáveis de saída das colunas retornadas
+type returningInto struct {
+	query   string
+	args    []interface{}
+	columns []string
+	outputs []interface{} // Destinos das variáveis de saída, na ordem de columns
+}
+
+// values retorna os valores das variáveis de saída, com nil para NULL
+func (r *returningInto) values() []any {
+	values := make([]any, len(r.outputs))
+	for i, output := range r.outputs {
+		switch v := output.(type) {
+		case *sql.NullString:
+			if v.Valid {
+				values[i] = v.String
+			}
+		case *sql.NullInt64:
+			if v.Valid {
+				values[i] = v.Int64
+			}
+		case *sql.NullFloat64:
+			if v.Valid {
+				values[i] = v.Float64
+			}
+		case *sql.NullBool:
+			if v.Valid {
+				values[i] = v.Bool
+			}
+		case *sql.NullTime:
+			if v.Valid {
+				values[i] = v.Time
+			}
+		case *[]byte:
+			if *v != nil {
+				values[i] = *v
+			}
+		}
+	}
+	return values
+}
+
+// executeWriteReturning executa o INSERT/UPDATE obtendo a linha gravada no mesmo round trip:
+// os providers com RETURNING (PostgreSQL) devolvem um result set e os providers com RETURNING
+// ... INTO (Oracle) preenchem variáveis de saída. O caminho é decidido pelo provider, e não pelo
+// texto da query (tabelas e colunas podem conter "returning"). handled = false quando o dialeto
+// não retorna a linha (MySQL, SQLite), e o chamador a relê após a gravação
+func (s *BaseEntityService) executeWriteReturning(ctx context.Context, operation, query string, args []any) (results []any, handled bool, err error) {
+	if provider, ok := s.provider.(returningProvider); ok && provider.writesReturnRows() {
+		results, err = s.queryReturning(ctx, operation, query, args)
+	} else if provider, ok := s.provider.(returningIntoProvider); ok {
+		results, err = s.execReturningInto(ctx, provider, operation, query, args)
+	} else {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+
+	if err := s.server.decryptPropertyValues(ctx, s.metadata, results); err != nil {
+		return nil, true, err
+	}
+	return results, true, nil
+}
+
+// queryReturning executa a query com RETURNING e lê as linhas retornadas
+// (usamos Query em vez de QueryRow para poder obter os nomes das colunas dinamicamente)
+func (s *BaseEntityService) queryReturning(ctx context.Context, operation, query string, args []any) ([]any, error) {
+	conn := s.provider.GetConnection()
+	if conn == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	// Dispara QueryBuilt (handlers podem alterar a query)
+	trace, err := s.beginQuery(ctx, operation, query, args)
+	if err != nil {
+		return nil, err
+	}
+	query, args = trace.query, trace.args
+
+	// Log da query SQL se DB_LOG_SQL estiver habilitado
+	if s.shouldLogSQL() {
+		log.Printf("🔍 [SQL] EXEC (RETURNING): %s", query)
+		if len(args) > 0 {
+			log.Printf("🔍 [SQL] ARGS: %v", args)
+		}
+	}
+
+	querier, err := sessionQuerier(ctx, s.provider, conn)
+	if err != nil {
+		trace.finish(0, err)
+		return nil, err
+	}
+	rows, err := querier.QueryContext(ctx, query, args...)
+	if err != nil {
+		if s.shouldLogSQL() {
+			log.Printf("❌ [SQL] ERRO na query: %v", err)
+		}
+		trace.finish(0, err)
+		return nil, fmt.Errorf("failed to execute %s with returning: %w", strings.ToLower(operation), TranslateDatabaseError(err, s.metadata))
+	}
+	defer rows.Close()
+
+	// scanRows já sabe lidar com mapeamento dinâmico de colunas
+	results, err := s.scanRows(rows, []ExpandOption{})
+	trace.finish(int64(len(results)), err)
+	if err != nil {
+		if s.shouldLogSQL() {
+			log.Printf("❌ [SQL] ERRO no scan: %v", err)
+		}
+		return nil, fmt.Errorf("failed to scan returned values: %w", err)
+	}
+	return results, nil
+}
+
+// execReturningInto executa a query com RETURNING ... INTO e monta a entidade a partir das
+// variáveis de saída (nenhuma linha quando a gravação não afetou registros)
+func (s *BaseEntityService) execReturningInto(ctx context.Context, provider returningIntoProvider, operation, query string, args []any) ([]any, error) {
+	returning, err := provider.buildReturningInto(s.metadata, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.executeExec(ctx, returning.query, returning.args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s with returning: %w", strings.ToLower(operation), TranslateDatabaseError(err, s.metadata))
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return []any{}, nil
+	}
+
+	return []any{s.scanEntity(s.getScanPlan(returning.columns), returning.values(), nil)}, nil
+}
@@ -0,0 +1,147 @@
+package odata
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// returningTestProvider simula o PostgreSQL: o SQLite também suporta RETURNING *
+type returningTestProvider struct {
+	*MySQLProvider
+}
+
+func (p *returningTestProvider) writesReturnRows() bool {
+	return true
+}
+
+func (p *returningTestProvider) BuildInsertQuery(entity EntityMetadata, data map[string]interface{}) (string, []interface{}, error) {
+	query, args, err := p.MySQLProvider.BuildInsertQuery(entity, data)
+	return query + " RETURNING *", args, err
+}
+
+func (p *returningTestProvider) BuildUpdateQuery(entity EntityMetadata, data map[string]interface{}, keyValues map[string]interface{}) (string, []interface{}, error) {
+	query, args, err := p.MySQLProvider.BuildUpdateQuery(entity, data, keyValues)
+	return query + " RETURNING *", args, err
+}
+
+// returningIntoTestProvider simula o Oracle, preenchendo as variáveis de saída com valores fixos
+type returningIntoTestProvider struct {
+	*MySQLProvider
+}
+
+func (p *returningIntoTestProvider) buildReturningInto(entity EntityMetadata, query string, args []interface{}) (*returningInto, error) {
+	return &returningInto{
+		query:   query,
+		args:    args,
+		columns: []string{"id", "customer_id", "status"},
+		outputs: []interface{}{&sql.NullInt64{Int64: 1, Valid: true}, &sql.NullInt64{}, &sql.NullString{String: "returned", Valid: true}},
+	}, nil
+}
+
+func TestEntityService_WriteReturning(t *testing.T) {
+	server := newPatchDeltaTestServer(t)
+	mysql := server.provider.(*MySQLProvider)
+	orders := server.entities["Orders"].GetMetadata()
+
+	var executed []string
+	server.OnQueryExecutedGlobal(func(args EventArgs) error {
+		executed = append(executed, args.(*QueryExecutedArgs).SQL)
+		return nil
+	})
+
+	t.Run("RETURNING em um único round trip", func(t *testing.T) {
+		service := NewBaseEntityService(&returningTestProvider{mysql}, orders, server)
+		executed = nil
+
+		updated, err := service.Update(context.Background(), map[string]any{"id": int64(1)}, map[string]any{"status": "closed"})
+		require.NoError(t, err)
+		entity := updated.(*OrderedEntity)
+		assert.Equal(t, "closed", entity.data["status"])
+		assert.Equal(t, int64(1), entity.data["customer_id"])
+		require.Len(t, executed, 1)
+		assert.True(t, strings.HasPrefix(executed[0], "UPDATE"))
+
+		created, err := service.Create(context.Background(), map[string]any{"id": int64(3), "status": "new"})
+		require.NoError(t, err)
+		assert.Equal(t, "new", created.(*OrderedEntity).data["status"])
+
+		_, err = service.Update(context.Background(), map[string]any{"id": int64(99)}, map[string]any{"status": "closed"})
+		assert.EqualError(t, err, "no rows updated")
+	})
+
+	t.Run("RETURNING ... INTO", func(t *testing.T) {
+		service := NewBaseEntityService(&returningIntoTestProvider{mysql}, orders, server)
+		executed = nil
+
+		updated, err := service.Update(context.Background(), map[string]any{"id": int64(1)}, map[string]any{"status": "closed"})
+		require.NoError(t, err)
+		entity := updated.(*OrderedEntity)
+		assert.Equal(t, "returned", entity.data["status"])
+		assert.Nil(t, entity.data["customer_id"])
+		assert.Len(t, executed, 1)
+	})
+
+	t.Run("MySQL relê a linha gravada", func(t *testing.T) {
+		service := NewBaseEntityService(mysql, orders, server)
+		executed = nil
+
+		updated, err := service.Update(context.Background(), map[string]any{"id": int64(2)}, map[string]any{"status": "closed"})
+		require.NoError(t, err)
+		assert.Equal(t, "closed", updated.(*OrderedEntity).data["status"])
+		require.Len(t, executed, 2)
+		assert.True(t, strings.HasPrefix(executed[1], "SELECT"))
+	})
+
+	t.Run("Nomes com returning não mudam o caminho", func(t *testing.T) {
+		_, err := mysql.GetConnection().Exec("CREATE TABLE returning_orders (id INTEGER PRIMARY KEY, returning_reason TEXT)")
+		require.NoError(t, err)
+		service := NewBaseEntityService(mysql, EntityMetadata{
+			Name:      "ReturningOrders",
+			TableName: "returning_orders",
+			Keys:      []string{"id"},
+			Properties: []PropertyMetadata{
+				{Name: "id", ColumnName: "id", Type: "int64", IsKey: true},
+				{Name: "returning_reason", ColumnName: "returning_reason", Type: "string"},
+			},
+		}, server)
+
+		created, err := service.Create(context.Background(), map[string]any{"id": int64(1), "returning_reason": "defeito"})
+		require.NoError(t, err)
+		assert.Equal(t, "defeito", created.(*OrderedEntity).data["returning_reason"])
+
+		updated, err := service.Update(context.Background(), map[string]any{"id": int64(1)}, map[string]any{"returning_reason": "troca"})
+		require.NoError(t, err)
+		assert.Equal(t, "troca", updated.(*OrderedEntity).data["returning_reason"])
+	})
+}
+
+func TestOracleProvider_BuildReturningInto(t *testing.T) {
+	oracle := &OracleProvider{BaseProvider: &BaseProvider{driverName: "oracle"}}
+	metadata := EntityMetadata{
+		Name:      "Orders",
+		TableName: "orders",
+		Properties: []PropertyMetadata{
+			{Name: "id", ColumnName: "ID", Type: "int64", IsKey: true},
+			{Name: "status", ColumnName: "STATUS", Type: "string"},
+			{Name: "Customer", IsNavigation: true},
+		},
+	}
+
+	query, args, err := oracle.BuildUpdateQuery(metadata, map[string]interface{}{"status": "closed"}, map[string]interface{}{"id": int64(1)})
+	require.NoError(t, err)
+
+	returning, err := oracle.buildReturningInto(metadata, query, args)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE orders SET STATUS = :param1 WHERE ID = :param2 RETURNING ID, STATUS INTO :ret1, :ret2", returning.query)
+	assert.Equal(t, []string{"ID", "STATUS"}, returning.columns)
+	require.Len(t, returning.args, 4)
+	assert.Equal(t, sql.Named("param1", "closed"), returning.args[0])
+
+	returning.outputs[0].(*sql.NullInt64).Int64, returning.outputs[0].(*sql.NullInt64).Valid = 1, true
+	assert.Equal(t, []any{int64(1), nil}, returning.values())
+}